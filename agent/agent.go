@@ -59,8 +59,20 @@ const (
 	StorageAddr            = "STORAGE_ADDR"
 	AgentServiceName       = "AGENT_SERVICE_NAME"
 	MongoOplogSize         = "MONGO_OPLOG_SIZE"
+	MongoCacheSize         = "MONGO_CACHE_SIZE"
+	MongoJournalInterval   = "MONGO_JOURNAL_INTERVAL"
 	NumaCtlPreference      = "NUMA_CTL_PREFERENCE"
 	AllowsSecureConnection = "SECURE_STATESERVER_CONNECTION"
+	AgentLoginRate         = "AGENT_LOGIN_RATE"
+	AgentLoginBurst        = "AGENT_LOGIN_BURST"
+	AuditLog               = "AUDIT_LOG"
+	AuditLogFile           = "AUDIT_LOG_FILE"
+	APIConcurrencyLimits   = "API_CONCURRENCY_LIMITS"
+	APIAdmissionTimeout    = "API_ADMISSION_TIMEOUT"
+	MongoSOCKSProxy        = "MONGO_SOCKS_PROXY"
+	APIDeniedEntities      = "API_DENIED_ENTITIES"
+	APIMethodPolicy        = "API_METHOD_POLICY"
+	CharmDownloadRate      = "CHARM_DOWNLOAD_RATE"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -414,10 +426,10 @@ func ReadConfig(configFilePath string) (ConfigSetterWriter, error) {
 		if err != nil {
 			return nil, err
 		}
-		config, err = format.unmarshal(configData)
+		config, err = format.unmarshal(configData, dir)
 	} else {
 		// Does not exist, just parse the data.
-		format, config, err = parseConfigData(configData)
+		format, config, err = parseConfigData(configData, dir)
 	}
 	if err != nil {
 		return nil, err
@@ -655,8 +667,21 @@ func (c *configInternal) fileContents() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// cloudInitFileContents returns the contents of the config file as
+// written for an agent whose machine has not yet been provisioned.
+func (c *configInternal) cloudInitFileContents() ([]byte, error) {
+	data, err := cloudInitFormat.marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s%s\n", formatPrefix, cloudInitFormat.version())
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
 func (c *configInternal) WriteCommands(renderer shell.Renderer) ([]string, error) {
-	data, err := c.fileContents()
+	data, err := c.cloudInitFileContents()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -708,8 +733,9 @@ func (c *configInternal) MongoInfo() (info *mongo.MongoInfo, ok bool) {
 	}
 	return &mongo.MongoInfo{
 		Info: mongo.Info{
-			Addrs:  []string{addr},
-			CACert: c.caCert,
+			Addrs:      []string{addr},
+			CACert:     c.caCert,
+			SOCKSProxy: c.values[MongoSOCKSProxy],
 		},
 		Password: c.stateDetails.password,
 		Tag:      c.tag,
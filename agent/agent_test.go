@@ -596,6 +596,18 @@ func (*suite) TestMongoInfoHonorsPreferIPv6(c *gc.C) {
 	c.Check(mongoInfo.Info.Addrs, jc.DeepEquals, []string{"127.0.0.1:69"})
 }
 
+func (*suite) TestMongoInfoSOCKSProxy(c *gc.C) {
+	attrParams := attributeParams
+	attrParams.Values = map[string]string{
+		agent.MongoSOCKSProxy: "bastion.example.com:1080",
+	}
+	conf, err := agent.NewStateMachineConfig(attrParams, stateServingInfo())
+	c.Assert(err, jc.ErrorIsNil)
+	mongoInfo, ok := conf.MongoInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(mongoInfo.Info.SOCKSProxy, gc.Equals, "bastion.example.com:1080")
+}
+
 func (*suite) TestAPIInfoDoesntAddLocalhostWhenNoServingInfoPreferIPv6Off(c *gc.C) {
 	attrParams := attributeParams
 	attrParams.PreferIPv6 = false
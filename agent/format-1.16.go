@@ -69,7 +69,7 @@ func (formatter_1_16) version() string {
 	return "1.16"
 }
 
-func (formatter_1_16) unmarshal(data []byte) (*configInternal, error) {
+func (formatter_1_16) unmarshal(data []byte, dir string) (*configInternal, error) {
 	var format format_1_16Serialization
 	if err := goyaml.Unmarshal(data, &format); err != nil {
 		return nil, err
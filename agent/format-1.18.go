@@ -66,13 +66,18 @@ func (formatter_1_18) version() string {
 	return "1.18"
 }
 
-func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
-	// NOTE: this needs to handle the absence of StatePort and get it from the
-	// address
+func (formatter_1_18) unmarshal(data []byte, dir string) (*configInternal, error) {
 	var format format_1_18Serialization
 	if err := goyaml.Unmarshal(data, &format); err != nil {
 		return nil, err
 	}
+	return format.configInternal()
+}
+
+// configInternal returns the agent config held in the serialization.
+func (format *format_1_18Serialization) configInternal() (*configInternal, error) {
+	// NOTE: this needs to handle the absence of StatePort and get it from the
+	// address
 	if format.UpgradedToVersion == nil || *format.UpgradedToVersion == version.Zero {
 		// Assume we upgrade from 1.16.
 		upgradedToVersion := version.MustParse("1.16.0")
@@ -153,7 +158,16 @@ func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
 	return config, nil
 }
 
+// marshal is retained for the 1.18 format, despite it no longer being
+// the current format, because it is used to write configs for machines
+// being provisioned; see cloudInitFormat.
 func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
+	return goyaml.Marshal(newFormat_1_18Serialization(config))
+}
+
+// newFormat_1_18Serialization returns the 1.18 serialization of the
+// given agent config.
+func newFormat_1_18Serialization(config *configInternal) *format_1_18Serialization {
 	var envTag string
 	if config.environment.Id() != "" {
 		envTag = config.environment.String()
@@ -188,5 +202,5 @@ func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
 		format.APIAddresses = config.apiDetails.addresses
 		format.APIPassword = config.apiDetails.password
 	}
-	return goyaml.Marshal(format)
+	return format
 }
@@ -0,0 +1,227 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+var format_1_25 = formatter_1_25{}
+
+// formatter_1_25 is the formatter for the 1.25 format.
+type formatter_1_25 struct {
+}
+
+// Ensure that the formatter_1_25 struct implements the formatter interface.
+var _ formatter = formatter_1_25{}
+
+// format_1_25Serialization holds information for a given agent. It is
+// the 1.18 format, except that the sensitive fields are not stored in
+// the clear; they are sealed together into Secrets using a key that is
+// kept alongside the agent config file, and never leaves the machine.
+type format_1_25Serialization struct {
+	format_1_18Serialization `yaml:",inline"`
+	Secrets                  string `yaml:",omitempty"`
+}
+
+// format_1_25Secrets holds the sensitive fields of the 1.18 format.
+type format_1_25Secrets struct {
+	StatePassword  string `yaml:",omitempty"`
+	APIPassword    string `yaml:",omitempty"`
+	OldPassword    string `yaml:",omitempty"`
+	StateServerKey string `yaml:",omitempty"`
+	CAPrivateKey   string `yaml:",omitempty"`
+	SharedSecret   string `yaml:",omitempty"`
+	SystemIdentity string `yaml:",omitempty"`
+}
+
+// secretKeyFilename is the name of the file, in the same directory as
+// the agent config file, that holds the key used to seal secrets.
+const secretKeyFilename = "agent.key"
+
+// secretKeySize is the size in bytes of the AES-256 key used to
+// seal secrets.
+const secretKeySize = 32
+
+func init() {
+	registerFormat(format_1_25)
+}
+
+func (formatter_1_25) version() string {
+	return "1.25"
+}
+
+func (formatter_1_25) unmarshal(data []byte, dir string) (*configInternal, error) {
+	var format format_1_25Serialization
+	if err := goyaml.Unmarshal(data, &format); err != nil {
+		return nil, err
+	}
+	if format.Secrets != "" {
+		key, err := readSecretKey(dir)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read agent config secrets key")
+		}
+		var secrets format_1_25Secrets
+		if err := openSecrets(key, format.Secrets, &secrets); err != nil {
+			return nil, errors.Annotate(err, "cannot open agent config secrets")
+		}
+		format.StatePassword = secrets.StatePassword
+		format.APIPassword = secrets.APIPassword
+		format.OldPassword = secrets.OldPassword
+		format.StateServerKey = secrets.StateServerKey
+		format.CAPrivateKey = secrets.CAPrivateKey
+		format.SharedSecret = secrets.SharedSecret
+		format.SystemIdentity = secrets.SystemIdentity
+	}
+	return format.configInternal()
+}
+
+func (formatter_1_25) marshal(config *configInternal) ([]byte, error) {
+	format := format_1_25Serialization{
+		format_1_18Serialization: *newFormat_1_18Serialization(config),
+	}
+	secrets := format_1_25Secrets{
+		StatePassword:  format.StatePassword,
+		APIPassword:    format.APIPassword,
+		OldPassword:    format.OldPassword,
+		StateServerKey: format.StateServerKey,
+		CAPrivateKey:   format.CAPrivateKey,
+		SharedSecret:   format.SharedSecret,
+		SystemIdentity: format.SystemIdentity,
+	}
+	format.StatePassword = ""
+	format.APIPassword = ""
+	format.OldPassword = ""
+	format.StateServerKey = ""
+	format.CAPrivateKey = ""
+	format.SharedSecret = ""
+	format.SystemIdentity = ""
+	if secrets != (format_1_25Secrets{}) {
+		key, err := ensureSecretKey(filepath.Dir(config.configFilePath))
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot create agent config secrets key")
+		}
+		format.Secrets, err = sealSecrets(key, &secrets)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot seal agent config secrets")
+		}
+	}
+	return goyaml.Marshal(&format)
+}
+
+// readSecretKey reads the secrets key from the given directory.
+func readSecretKey(dir string) ([]byte, error) {
+	key, err := ioutil.ReadFile(filepath.Join(dir, secretKeyFilename))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(key) != secretKeySize {
+		return nil, errors.Errorf("invalid key length %d", len(key))
+	}
+	return key, nil
+}
+
+// ensureSecretKey returns the secrets key in the given directory,
+// generating a new key if none exists.
+func ensureSecretKey(dir string) ([]byte, error) {
+	key, err := readSecretKey(dir)
+	if err == nil {
+		return key, nil
+	} else if !os.IsNotExist(errors.Cause(err)) {
+		return nil, errors.Trace(err)
+	}
+	key = make([]byte, secretKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Create the file exclusively, so that if another process
+	// created a key concurrently we use that instead.
+	f, err := os.OpenFile(filepath.Join(dir, secretKeyFilename), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return readSecretKey(dir)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return key, nil
+}
+
+// sealSecrets encrypts and authenticates the YAML serialization of
+// secrets with the given key.
+func sealSecrets(key []byte, secrets *format_1_25Secrets) (string, error) {
+	plaintext, err := goyaml.Marshal(secrets)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return sealData(key, plaintext)
+}
+
+// openSecrets reverses sealSecrets.
+func openSecrets(key []byte, sealed string, secrets *format_1_25Secrets) error {
+	plaintext, err := openData(key, sealed)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return goyaml.Unmarshal(plaintext, secrets)
+}
+
+// sealData encrypts and authenticates data with AES-GCM, returning
+// the base64-encoded nonce and ciphertext.
+func sealData(key, plaintext []byte) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Trace(err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openData reverses sealData.
+func openData(key []byte, sealed string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("sealed data too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
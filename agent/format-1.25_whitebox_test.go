@@ -0,0 +1,201 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The format tests are white box tests, meaning that the tests are in the
+// same package as the code, as all the format details are internal to the
+// package.
+
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type format_1_25Suite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&format_1_25Suite{})
+
+func (s *format_1_25Suite) newStateServerConfig(c *gc.C) *configInternal {
+	servingInfo := params.StateServingInfo{
+		Cert:           "some special cert",
+		PrivateKey:     "a special key",
+		CAPrivateKey:   "ca special key",
+		SharedSecret:   "a shared secret",
+		SystemIdentity: "a system identity",
+		StatePort:      12345,
+		APIPort:        23456,
+	}
+	params := agentParams
+	params.DataDir = c.MkDir()
+	configInterface, err := NewStateMachineConfig(params, servingInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	return configInterface.(*configInternal)
+}
+
+func (s *format_1_25Suite) TestSecretsNotWrittenInClear(c *gc.C) {
+	config := s.newStateServerConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasPrefix(string(data), "# format 1.25\n"), jc.IsTrue)
+	for _, secret := range []string{
+		"sekrit",
+		"a special key",
+		"ca special key",
+		"a shared secret",
+		"a system identity",
+	} {
+		c.Check(strings.Contains(string(data), secret), jc.IsFalse, gc.Commentf("%q", secret))
+	}
+	// Non-sensitive fields are still readable.
+	c.Check(strings.Contains(string(data), "some special cert"), jc.IsTrue)
+
+	keyPath := filepath.Join(filepath.Dir(config.configFilePath), secretKeyFilename)
+	info, err := os.Stat(keyPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size(), gc.Equals, int64(secretKeySize))
+	if runtime.GOOS != "windows" {
+		c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+	}
+}
+
+func (s *format_1_25Suite) TestReadWrite(c *gc.C) {
+	config := s.newStateServerConfig(c)
+	assertWriteAndRead(c, config)
+}
+
+func (s *format_1_25Suite) TestKeyReused(c *gc.C) {
+	config := s.newStateServerConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	keyPath := filepath.Join(filepath.Dir(config.configFilePath), secretKeyFilename)
+	key0, err := ioutil.ReadFile(keyPath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	config.SetPassword("new password")
+	err = config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	key1, err := ioutil.ReadFile(keyPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key1, jc.DeepEquals, key0)
+
+	readConfig, err := ReadConfig(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readConfig.APIInfo().Password, gc.Equals, "new password")
+}
+
+func (s *format_1_25Suite) TestMissingKey(c *gc.C) {
+	config := s.newStateServerConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	keyPath := filepath.Join(filepath.Dir(config.configFilePath), secretKeyFilename)
+	err = os.Remove(keyPath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = ReadConfig(config.configFilePath)
+	c.Assert(err, gc.ErrorMatches, "cannot read agent config secrets key: .*")
+}
+
+func (s *format_1_25Suite) TestMigrateFrom1_18(c *gc.C) {
+	dataDir := c.MkDir()
+	configPath := filepath.Join(dataDir, agentConfigFilename)
+	err := utils.AtomicWriteFile(configPath, []byte(agentConfig1_18Contents), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	config, err := ReadConfig(configPath)
+	c.Assert(err, jc.ErrorIsNil)
+	servingInfo, ok := config.StateServingInfo()
+	c.Assert(ok, jc.IsTrue)
+
+	// The config is migrated to the current format, with
+	// secrets sealed.
+	data, err := ioutil.ReadFile(configPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasPrefix(string(data), "# format 1.25\n"), jc.IsTrue)
+	c.Assert(strings.Contains(string(data), "NB5imrDaWCCRW/4akSSvUxhX"), jc.IsFalse)
+
+	migrated, err := ReadConfig(configPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrated.APIInfo().Password, gc.Equals, "NB5imrDaWCCRW/4akSSvUxhX")
+	migratedInfo, ok := migrated.StateServingInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(migratedInfo, jc.DeepEquals, servingInfo)
+}
+
+func (s *format_1_25Suite) TestWriteCommandsUseCloudInitFormat(c *gc.C) {
+	config := newTestConfig(c)
+	data, err := config.cloudInitFileContents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasPrefix(string(data), "# format 1.18\n"), jc.IsTrue)
+	// No key is needed to write configs for new machines.
+	keyPath := filepath.Join(filepath.Dir(config.configFilePath), secretKeyFilename)
+	_, err = os.Stat(keyPath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *format_1_25Suite) TestExportImportSecretKey(c *gc.C) {
+	config := s.newStateServerConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	dir := filepath.Dir(config.configFilePath)
+
+	sealed, err := ExportSecretKey(dir, "ca special key")
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := readSecretKey(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Contains(sealed, string(key)), jc.IsFalse)
+
+	// Restore the config elsewhere, as restoring a backup does.
+	newDir := c.MkDir()
+	data, err := ioutil.ReadFile(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	newPath := filepath.Join(newDir, agentConfigFilename)
+	err = ioutil.WriteFile(newPath, data, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ImportSecretKey(newDir, "wrong passphrase", sealed)
+	c.Assert(err, gc.ErrorMatches, "cannot open agent config secrets key: .*")
+	err = ImportSecretKey(newDir, "ca special key", sealed)
+	c.Assert(err, jc.ErrorIsNil)
+	restored, err := ReadConfig(newPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restored.OldPassword(), gc.Equals, config.OldPassword())
+}
+
+func (s *format_1_25Suite) TestExportSecretKeyNotFound(c *gc.C) {
+	_, err := ExportSecretKey(c.MkDir(), "passphrase")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *format_1_25Suite) TestDowngradeConfig(c *gc.C) {
+	config := s.newStateServerConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = DowngradeConfig(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasPrefix(string(data), "# format 1.18\n"), jc.IsTrue)
+
+	// Older agents read the secrets without the key.
+	_, readConfig, err := parseConfigData(data, c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readConfig.OldPassword(), gc.Equals, config.OldPassword())
+}
@@ -39,9 +39,11 @@ var formats = make(map[string]formatter)
 
 // The formatter defines the two methods needed by the formatters for
 // translating to and from the internal, format agnostic, structure.
+// The dir passed to unmarshal is the directory holding the agent config
+// file, in which formats that seal secrets keep their key.
 type formatter interface {
 	version() string
-	unmarshal(data []byte) (*configInternal, error)
+	unmarshal(data []byte, dir string) (*configInternal, error)
 }
 
 func registerFormat(format formatter) {
@@ -55,7 +57,13 @@ func registerFormat(format formatter) {
 // - Remove the marshal() method from the old format;
 
 // currentFormat holds the current agent config version's formatter.
-var currentFormat = format_1_18
+var currentFormat = format_1_25
+
+// cloudInitFormat holds the formatter used to write agent configs for
+// machines yet to be provisioned. The current format seals secrets with
+// a key that only exists on the agent's machine, so configs are written
+// in the preceding format and migrated when first read by the agent.
+var cloudInitFormat = format_1_18
 
 // agentConfigFilename is the default file name of used for the agent
 // config.
@@ -82,7 +90,7 @@ func getFormatter(version string) (formatter, error) {
 	return format, nil
 }
 
-func parseConfigData(data []byte) (formatter, *configInternal, error) {
+func parseConfigData(data []byte, dir string) (formatter, *configInternal, error) {
 	i := bytes.IndexByte(data, '\n')
 	if i == -1 {
 		return nil, nil, fmt.Errorf("invalid agent config format: %s", string(data))
@@ -96,7 +104,7 @@ func parseConfigData(data []byte) (formatter, *configInternal, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	config, err := format.unmarshal(configData)
+	config, err := format.unmarshal(configData, dir)
 	if err != nil {
 		return nil, nil, err
 	}
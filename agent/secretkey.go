@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// SecretKeyFilename is the name of the file, in the same directory as
+// an agent config file, that holds the key sealing the config's
+// secrets. It must never be copied off the machine as it is; use
+// ExportSecretKey instead.
+const SecretKeyFilename = secretKeyFilename
+
+// ExportSecretKey returns the key sealing the secrets of the agent
+// config in the given directory, itself sealed with a key derived from
+// the given passphrase, so that it can be stored apart from the
+// machine, e.g. in backups. If there is no key, an error satisfying
+// errors.IsNotFound is returned.
+func ExportSecretKey(dir, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", errors.New("empty passphrase")
+	}
+	key, err := readSecretKey(dir)
+	if os.IsNotExist(errors.Cause(err)) {
+		return "", errors.NotFoundf("agent config secrets key in %q", dir)
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	passKey := sha256.Sum256([]byte(passphrase))
+	return sealData(passKey[:], key)
+}
+
+// ImportSecretKey unseals a key returned by ExportSecretKey using the
+// same passphrase, and writes it to the given directory, so that the
+// agent config there can be read.
+func ImportSecretKey(dir, passphrase, sealed string) error {
+	passKey := sha256.Sum256([]byte(passphrase))
+	key, err := openData(passKey[:], sealed)
+	if err != nil {
+		return errors.Annotate(err, "cannot open agent config secrets key")
+	}
+	if len(key) != secretKeySize {
+		return errors.Errorf("invalid key length %d", len(key))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	return utils.AtomicWriteFile(filepath.Join(dir, secretKeyFilename), key, 0600)
+}
+
+// DowngradeConfig rewrites the agent config file at the given path in
+// the format preceding the current one, which does not seal secrets and
+// so can be read by agents older than 1.25. It must be called before
+// an agent is reverted to such tools; newer tools migrate the config
+// back when they next read it.
+func DowngradeConfig(configFilePath string) error {
+	config, err := ReadConfig(configFilePath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := config.(*configInternal).cloudInitFileContents()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return utils.AtomicWriteFile(configFilePath, data, 0600)
+}
@@ -0,0 +1,242 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/symlink"
+
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+// rollbackSuffix is appended to an agent's name to give the name of
+// the file, in the tools directory, that holds its rollback record.
+const rollbackSuffix = ".rollback"
+
+// Rollback records what is needed to return an agent to the tools it
+// was running before its most recent upgrade.
+type Rollback struct {
+	// Previous holds the version of the tools the agent ran before
+	// it was upgraded. It is empty once the agent has been reverted.
+	Previous version.Binary
+
+	// Current holds the version of the tools the agent was upgraded to.
+	Current version.Binary
+
+	// Changed holds the time of the upgrade.
+	Changed time.Time
+
+	// Failures holds the number of times the agent has failed since
+	// the upgrade, while still within the grace period. The agent
+	// fails if it exits with an error, or if it stops without
+	// recording its exit, for example by panicking.
+	Failures int
+
+	// Running reports whether the agent has started since the
+	// upgrade without yet recording its exit.
+	Running bool
+
+	// RevertedFrom holds the version the agent was reverted from,
+	// if it has been reverted.
+	RevertedFrom version.Binary
+
+	// Report holds a message describing why the agent was reverted,
+	// until the agent has recorded it in state.
+	Report string
+}
+
+func rollbackFile(dataDir, agentName string) string {
+	return path.Join(dataDir, "tools", agentName+rollbackSuffix)
+}
+
+// ReadRollback returns the rollback record for the given agent.
+// It returns an error satisfying errors.IsNotFound if there is none.
+func ReadRollback(dataDir, agentName string) (*Rollback, error) {
+	data, err := ioutil.ReadFile(rollbackFile(dataDir, agentName))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("rollback record for agent %q", agentName)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read rollback record")
+	}
+	var rollback Rollback
+	if err := json.Unmarshal(data, &rollback); err != nil {
+		return nil, errors.Annotatef(err, "invalid rollback record for agent %q", agentName)
+	}
+	return &rollback, nil
+}
+
+func writeRollback(dataDir, agentName string, rollback *Rollback) error {
+	data, err := json.Marshal(rollback)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Write to a temporary file and rename it so that a crash
+	// never leaves a partially written record behind.
+	name := rollbackFile(dataDir, agentName)
+	if err := ioutil.WriteFile(name+".tmp", data, 0644); err != nil {
+		return errors.Annotate(err, "cannot write rollback record")
+	}
+	return errors.Annotate(os.Rename(name+".tmp", name), "cannot write rollback record")
+}
+
+// currentAgentTools returns the version of the tools the given agent's
+// tools directory currently refers to.
+func currentAgentTools(dataDir, agentName string) (version.Binary, bool) {
+	target, err := symlink.Read(ToolsDir(dataDir, agentName))
+	if err != nil {
+		return version.Binary{}, false
+	}
+	vers, err := version.ParseBinary(path.Base(target))
+	if err != nil {
+		return version.Binary{}, false
+	}
+	return vers, true
+}
+
+// recordUpgrade records that the given agent is being changed from
+// the tools it currently uses to the given version, so that it can
+// later be reverted.
+func recordUpgrade(dataDir, agentName string, vers version.Binary) error {
+	previous, ok := currentAgentTools(dataDir, agentName)
+	if !ok || previous == vers {
+		return nil
+	}
+	return writeRollback(dataDir, agentName, &Rollback{
+		Previous: previous,
+		Current:  vers,
+		Changed:  time.Now().UTC(),
+	})
+}
+
+// RecordAgentStart records that the given agent has started, and returns
+// the number of times it has failed since it was upgraded. A start that
+// follows a run whose exit was not recorded counts as a failure. Starts
+// are only recorded within the given grace period after the upgrade,
+// and not at all once the agent has been reverted; zero is returned in
+// those cases, and when there is no upgrade to revert.
+func RecordAgentStart(dataDir, agentName string, now time.Time, grace time.Duration) (int, error) {
+	rollback, err := ReadRollback(dataDir, agentName)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if rollback.Previous == (version.Binary{}) || now.Sub(rollback.Changed) > grace {
+		return 0, nil
+	}
+	if rollback.Running {
+		rollback.Failures++
+	}
+	rollback.Running = true
+	if err := writeRollback(dataDir, agentName, rollback); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return rollback.Failures, nil
+}
+
+// RecordAgentExit records that the given agent, whose start was recorded
+// by RecordAgentStart, is exiting, and whether it failed. Exits that
+// follow no recorded start are ignored.
+func RecordAgentExit(dataDir, agentName string, failed bool) error {
+	rollback, err := ReadRollback(dataDir, agentName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if !rollback.Running {
+		return nil
+	}
+	rollback.Running = false
+	if failed {
+		rollback.Failures++
+	}
+	return writeRollback(dataDir, agentName, rollback)
+}
+
+// RevertAgentTools changes the tools directory of the given agent back
+// to the tools it ran before its most recent upgrade. An agent can be
+// reverted only once per upgrade; if there is nothing to revert to, an
+// error satisfying errors.IsNotFound is returned.
+func RevertAgentTools(dataDir, agentName string) (*coretools.Tools, error) {
+	rollback, err := ReadRollback(dataDir, agentName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if rollback.Previous == (version.Binary{}) {
+		return nil, errors.NotFoundf("previous tools for agent %q", agentName)
+	}
+	tools, err := ReadTools(dataDir, rollback.Previous)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read previous tools")
+	}
+	toolPath := ToolsDir(dataDir, tools.Version.String())
+	if err := symlink.Replace(ToolsDir(dataDir, agentName), toolPath); err != nil {
+		return nil, errors.Annotate(err, "cannot replace tools directory")
+	}
+	rollback.RevertedFrom = rollback.Current
+	rollback.Current = rollback.Previous
+	rollback.Previous = version.Binary{}
+	if err := writeRollback(dataDir, agentName, rollback); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tools, nil
+}
+
+// PreviousTools returns the version of the tools the given agent ran
+// before its most recent upgrade, if it can still be reverted to them.
+func PreviousTools(dataDir, agentName string) (version.Binary, bool) {
+	rollback, err := ReadRollback(dataDir, agentName)
+	if err != nil || rollback.Previous == (version.Binary{}) {
+		return version.Binary{}, false
+	}
+	return rollback.Previous, true
+}
+
+// RevertedFrom returns the version of the tools the given agent was
+// reverted from, if it has been reverted since its last upgrade.
+func RevertedFrom(dataDir, agentName string) (version.Binary, bool) {
+	rollback, err := ReadRollback(dataDir, agentName)
+	if err != nil || rollback.RevertedFrom == (version.Binary{}) {
+		return version.Binary{}, false
+	}
+	return rollback.RevertedFrom, true
+}
+
+// SetRevertReport stores a message describing why the given agent was
+// reverted, to be recorded in state once the agent can connect to it.
+func SetRevertReport(dataDir, agentName, report string) error {
+	rollback, err := ReadRollback(dataDir, agentName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rollback.Report = report
+	return writeRollback(dataDir, agentName, rollback)
+}
+
+// ReportRevert passes any message stored by SetRevertReport for the
+// given agent to record, and forgets the message once record succeeds.
+func ReportRevert(dataDir, agentName string, record func(report string) error) error {
+	rollback, err := ReadRollback(dataDir, agentName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if rollback.Report == "" {
+		return nil
+	}
+	if err := record(rollback.Report); err != nil {
+		return errors.Trace(err)
+	}
+	rollback.Report = ""
+	return writeRollback(dataDir, agentName, rollback)
+}
@@ -0,0 +1,198 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/symlink"
+	gc "gopkg.in/check.v1"
+
+	agenttools "github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/testing"
+	coretest "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+type RollbackSuite struct {
+	testing.BaseSuite
+	dataDir string
+}
+
+var _ = gc.Suite(&RollbackSuite{})
+
+func (s *RollbackSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+}
+
+func (s *RollbackSuite) unpackTools(c *gc.C, vers string) *coretest.Tools {
+	data, checksum := testing.TarGz(testing.NewTarFile("jujud", agenttools.DirPerm, "jujud "+vers))
+	tools := &coretest.Tools{
+		URL:     "http://foo/" + vers,
+		Version: version.MustParseBinary(vers),
+		Size:    int64(len(data)),
+		SHA256:  checksum,
+	}
+	err := agenttools.UnpackTools(s.dataDir, tools, bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	return tools
+}
+
+func (s *RollbackSuite) assertAgentTools(c *gc.C, vers version.Binary) {
+	link, err := symlink.Read(agenttools.ToolsDir(s.dataDir, "testagent"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(link, jc.SamePath, agenttools.ToolsDir(s.dataDir, vers.String()))
+}
+
+// upgrade installs oldTools and then upgrades the agent to newTools.
+func (s *RollbackSuite) upgrade(c *gc.C, oldTools, newTools *coretest.Tools) {
+	_, err := agenttools.ChangeAgentTools(s.dataDir, "testagent", oldTools.Version)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = agenttools.ChangeAgentTools(s.dataDir, "testagent", newTools.Version)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RollbackSuite) TestNoRollbackBeforeUpgrade(c *gc.C) {
+	tools := s.unpackTools(c, "1.2.3-quantal-amd64")
+	_, err := agenttools.ChangeAgentTools(s.dataDir, "testagent", tools.Version)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = agenttools.ReadRollback(s.dataDir, "testagent")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = agenttools.RevertAgentTools(s.dataDir, "testagent")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	failures, err := agenttools.RecordAgentStart(s.dataDir, "testagent", time.Now(), time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, gc.Equals, 0)
+}
+
+func (s *RollbackSuite) TestRecordAgentStart(c *gc.C) {
+	oldTools := s.unpackTools(c, "1.2.3-quantal-amd64")
+	newTools := s.unpackTools(c, "1.2.4-quantal-amd64")
+	s.upgrade(c, oldTools, newTools)
+
+	rollback, err := agenttools.ReadRollback(s.dataDir, "testagent")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.Previous, gc.Equals, oldTools.Version)
+	c.Assert(rollback.Current, gc.Equals, newTools.Version)
+
+	// Each start that follows a run whose exit was not recorded
+	// counts as a failure.
+	now := rollback.Changed.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		failures, err := agenttools.RecordAgentStart(s.dataDir, "testagent", now, time.Hour)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(failures, gc.Equals, i)
+	}
+
+	// Starts after the grace period are not counted.
+	failures, err := agenttools.RecordAgentStart(s.dataDir, "testagent", now.Add(time.Hour), time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, gc.Equals, 0)
+}
+
+func (s *RollbackSuite) TestRecordAgentExit(c *gc.C) {
+	oldTools := s.unpackTools(c, "1.2.3-quantal-amd64")
+	newTools := s.unpackTools(c, "1.2.4-quantal-amd64")
+	s.upgrade(c, oldTools, newTools)
+
+	// Exits without a recorded start are ignored.
+	err := agenttools.RecordAgentExit(s.dataDir, "testagent", true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := time.Now()
+	for _, failed := range []bool{false, true, false} {
+		_, err := agenttools.RecordAgentStart(s.dataDir, "testagent", now, time.Hour)
+		c.Assert(err, jc.ErrorIsNil)
+		err = agenttools.RecordAgentExit(s.dataDir, "testagent", failed)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	failures, err := agenttools.RecordAgentStart(s.dataDir, "testagent", now, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, gc.Equals, 1)
+}
+
+func (s *RollbackSuite) TestRevertAgentTools(c *gc.C) {
+	oldTools := s.unpackTools(c, "1.2.3-quantal-amd64")
+	newTools := s.unpackTools(c, "1.2.4-quantal-amd64")
+	s.upgrade(c, oldTools, newTools)
+	s.assertAgentTools(c, newTools.Version)
+
+	_, ok := agenttools.RevertedFrom(s.dataDir, "testagent")
+	c.Assert(ok, jc.IsFalse)
+	previous, ok := agenttools.PreviousTools(s.dataDir, "testagent")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(previous, gc.Equals, oldTools.Version)
+
+	gotTools, err := agenttools.RevertAgentTools(s.dataDir, "testagent")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*gotTools, gc.Equals, *oldTools)
+	s.assertAgentTools(c, oldTools.Version)
+
+	revertedFrom, ok := agenttools.RevertedFrom(s.dataDir, "testagent")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(revertedFrom, gc.Equals, newTools.Version)
+
+	// Reverting is one-shot.
+	_, ok = agenttools.PreviousTools(s.dataDir, "testagent")
+	c.Assert(ok, jc.IsFalse)
+	_, err = agenttools.RevertAgentTools(s.dataDir, "testagent")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertAgentTools(c, oldTools.Version)
+
+	// Starts are no longer counted once the agent has been reverted.
+	failures, err := agenttools.RecordAgentStart(s.dataDir, "testagent", time.Now(), time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, gc.Equals, 0)
+}
+
+func (s *RollbackSuite) TestUpgradeAfterRevert(c *gc.C) {
+	oldTools := s.unpackTools(c, "1.2.3-quantal-amd64")
+	bad := s.unpackTools(c, "1.2.4-quantal-amd64")
+	good := s.unpackTools(c, "1.2.5-quantal-amd64")
+	s.upgrade(c, oldTools, bad)
+	_, err := agenttools.RevertAgentTools(s.dataDir, "testagent")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = agenttools.ChangeAgentTools(s.dataDir, "testagent", good.Version)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, ok := agenttools.RevertedFrom(s.dataDir, "testagent")
+	c.Assert(ok, jc.IsFalse)
+	rollback, err := agenttools.ReadRollback(s.dataDir, "testagent")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.Previous, gc.Equals, oldTools.Version)
+	c.Assert(rollback.Current, gc.Equals, good.Version)
+}
+
+func (s *RollbackSuite) TestReportRevert(c *gc.C) {
+	oldTools := s.unpackTools(c, "1.2.3-quantal-amd64")
+	newTools := s.unpackTools(c, "1.2.4-quantal-amd64")
+	s.upgrade(c, oldTools, newTools)
+	_, err := agenttools.RevertAgentTools(s.dataDir, "testagent")
+	c.Assert(err, jc.ErrorIsNil)
+	err = agenttools.SetRevertReport(s.dataDir, "testagent", "reverted")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A report that cannot be recorded is kept for later.
+	err = agenttools.ReportRevert(s.dataDir, "testagent", func(string) error {
+		return errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	var reports []string
+	record := func(report string) error {
+		reports = append(reports, report)
+		return nil
+	}
+	err = agenttools.ReportRevert(s.dataDir, "testagent", record)
+	c.Assert(err, jc.ErrorIsNil)
+	err = agenttools.ReportRevert(s.dataDir, "testagent", record)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reports, jc.DeepEquals, []string{"reverted"})
+}
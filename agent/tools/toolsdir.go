@@ -174,7 +174,9 @@ func ReadTools(dataDir string, vers version.Binary) (*coretools.Tools, error) {
 
 // ChangeAgentTools atomically replaces the agent-specific symlink
 // under dataDir so it points to the previously unpacked
-// version vers. It returns the new tools read.
+// version vers. It returns the new tools read. The tools previously
+// used by the agent are recorded so that RevertAgentTools can return
+// to them.
 func ChangeAgentTools(dataDir string, agentName string, vers version.Binary) (*coretools.Tools, error) {
 	tools, err := ReadTools(dataDir, vers)
 	if err != nil {
@@ -186,6 +188,11 @@ func ChangeAgentTools(dataDir string, agentName string, vers version.Binary) (*c
 	toolPath := ToolsDir(dataDir, tools.Version.String())
 	toolsDir := ToolsDir(dataDir, agentName)
 
+	// Remember the tools we're changing from so the
+	// agent can be reverted if the new ones misbehave.
+	if err := recordUpgrade(dataDir, agentName, tools.Version); err != nil {
+		return nil, err
+	}
 	err = symlink.Replace(toolsDir, toolPath)
 	if err != nil {
 		return nil, fmt.Errorf("cannot replace tools directory: %s", err)
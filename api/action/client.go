@@ -4,10 +4,14 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 )
 
@@ -83,6 +87,72 @@ func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
 	return results, err
 }
 
+// watchActionResults returns a StringsWatcher that notifies the ids
+// of Actions that finish on the same ActionReceiver as the given
+// Action.
+func (c *Client) watchActionResults(tag names.ActionTag) (watcher.StringsWatcher, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("waiting for action results")
+	}
+	var results params.StringsWatchResults
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	if err := c.facade.FacadeCall("WatchActionResults", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("%d results, expected 1", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return watcher.NewStringsWatcher(c.facade.RawAPICaller(), result), nil
+}
+
+// WaitForResult blocks until the given Action has finished, or until
+// a value is received on stop, and returns the Action's most recent
+// result. If the API server cannot notify action results, an error
+// satisfying errors.IsNotImplemented is returned, and the caller
+// should poll with Actions instead.
+func (c *Client) WaitForResult(tag names.ActionTag, stop <-chan time.Time) (params.ActionResult, error) {
+	w, err := c.watchActionResults(tag)
+	if err != nil {
+		return params.ActionResult{}, err
+	}
+	defer w.Stop()
+	for {
+		select {
+		case ids, ok := <-w.Changes():
+			if !ok {
+				return params.ActionResult{}, errors.Trace(w.Err())
+			}
+			for _, id := range ids {
+				if id == tag.Id() {
+					return c.actionResult(tag)
+				}
+			}
+		case <-stop:
+			return c.actionResult(tag)
+		}
+	}
+}
+
+// actionResult returns the result of a single Action.
+func (c *Client) actionResult(tag names.ActionTag) (params.ActionResult, error) {
+	results, err := c.Actions(params.Entities{Entities: []params.Entity{{Tag: tag.String()}}})
+	if err != nil {
+		return params.ActionResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ActionResult{}, errors.Errorf("%d results, expected 1", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ActionResult{}, result.Error
+	}
+	return result, nil
+}
+
 // servicesCharmActions is a batched query for the charm.Actions for a slice
 // of services by Entity.
 func (c *Client) servicesCharmActions(arg params.Entities) (params.ServicesCharmActionsResults, error) {
@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package addresser provides access to the Addresser API facade,
+// used by the addresser worker to release dead IP addresses.
+package addresser
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+)
+
+const addresserFacade = "Addresser"
+
+// API provides access to the Addresser API facade.
+type API struct {
+	*common.EnvironWatcher
+
+	facade base.FacadeCaller
+}
+
+// NewAPI creates a new client-side Addresser API facade.
+func NewAPI(caller base.APICaller) *API {
+	facadeCaller := base.NewFacadeCaller(caller, addresserFacade)
+	return &API{
+		EnvironWatcher: common.NewEnvironWatcher(facadeCaller),
+		facade:         facadeCaller,
+	}
+}
+
+// IPAddress returns the IP address with the given value. It returns
+// an error satisfying errors.IsNotFound if there is no such address.
+func (api *API) IPAddress(value string) (*IPAddress, error) {
+	var results params.IPAddressResults
+	args := params.IPAddressValues{Values: []string{value}}
+	if err := api.facade.FacadeCall("IPAddresses", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return nil, errors.NotFoundf("IP address %q", value)
+		}
+		return nil, result.Error
+	}
+	return &IPAddress{api: api, info: *result.Result}, nil
+}
+
+// InstanceId returns the provider specific instance id of the
+// machine with the given id.
+func (api *API) InstanceId(machineId string) (instance.Id, error) {
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}},
+	}
+	if err := api.facade.FacadeCall("InstanceId", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return "", errors.NotFoundf("machine %s", machineId)
+		}
+		return "", result.Error
+	}
+	return instance.Id(result.Result), nil
+}
+
+// WatchIPAddresses returns a StringsWatcher reporting the values of
+// IP addresses as they change.
+func (api *API) WatchIPAddresses() (watcher.StringsWatcher, error) {
+	var result params.StringsWatchResult
+	if err := api.facade.FacadeCall("WatchIPAddresses", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return watcher.NewStringsWatcher(api.facade.RawAPICaller(), result), nil
+}
+
+// removeIPAddress removes the dead IP address with the given value.
+func (api *API) removeIPAddress(value string) error {
+	var results params.ErrorResults
+	args := params.IPAddressValues{Values: []string{value}}
+	if err := api.facade.FacadeCall("RemoveIPAddresses", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
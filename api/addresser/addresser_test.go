@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package addresser_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/addresser"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type addresserSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&addresserSuite{})
+
+func (s *addresserSuite) TestIPAddress(c *gc.C) {
+	var calls []string
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Addresser")
+			c.Check(id, gc.Equals, "")
+			c.Check(a, jc.DeepEquals, params.IPAddressValues{
+				Values: []string{"0.1.2.3"},
+			})
+			calls = append(calls, request)
+			switch response := response.(type) {
+			case *params.IPAddressResults:
+				response.Results = []params.IPAddressResult{{
+					Result: &params.IPAddressInfo{
+						Value:     "0.1.2.3",
+						Life:      params.Dead,
+						MachineId: "0",
+						SubnetId:  "foobar",
+						Address:   params.FromNetworkAddress(network.NewAddress("0.1.2.3")),
+					},
+				}}
+			case *params.ErrorResults:
+				response.Results = []params.ErrorResult{{}}
+			default:
+				c.Fatalf("unexpected response type %T", response)
+			}
+			return nil
+		})
+	api := addresser.NewAPI(apiCaller)
+	addr, err := api.IPAddress("0.1.2.3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value(), gc.Equals, "0.1.2.3")
+	c.Check(addr.Life(), gc.Equals, params.Dead)
+	c.Check(addr.MachineId(), gc.Equals, "0")
+	c.Check(addr.SubnetId(), gc.Equals, "foobar")
+	c.Check(addr.Address(), gc.Equals, network.NewAddress("0.1.2.3"))
+
+	err = addr.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, jc.DeepEquals, []string{"IPAddresses", "RemoveIPAddresses"})
+}
+
+func (s *addresserSuite) TestIPAddressNotFound(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			results, ok := response.(*params.IPAddressResults)
+			c.Assert(ok, jc.IsTrue)
+			results.Results = []params.IPAddressResult{{
+				Error: &params.Error{
+					Code:    params.CodeNotFound,
+					Message: `IP address "0.1.2.3" not found`,
+				},
+			}}
+			return nil
+		})
+	api := addresser.NewAPI(apiCaller)
+	_, err := api.IPAddress("0.1.2.3")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *addresserSuite) TestInstanceId(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Addresser")
+			c.Check(request, gc.Equals, "InstanceId")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-0"}},
+			})
+			results, ok := response.(*params.StringResults)
+			c.Assert(ok, jc.IsTrue)
+			results.Results = []params.StringResult{{Result: "i-0"}}
+			return nil
+		})
+	api := addresser.NewAPI(apiCaller)
+	instId, err := api.InstanceId("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("i-0"))
+}
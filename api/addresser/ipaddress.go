@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package addresser
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+// IPAddress represents an IP address as seen by the addresser worker.
+type IPAddress struct {
+	api  *API
+	info params.IPAddressInfo
+}
+
+// Value returns the value of the address.
+func (a *IPAddress) Value() string {
+	return a.info.Value
+}
+
+// Life returns the life of the address when it was fetched.
+func (a *IPAddress) Life() params.Life {
+	return a.info.Life
+}
+
+// MachineId returns the id of the machine the address is allocated
+// to, if any.
+func (a *IPAddress) MachineId() string {
+	return a.info.MachineId
+}
+
+// SubnetId returns the provider id of the subnet the address
+// belongs to.
+func (a *IPAddress) SubnetId() string {
+	return a.info.SubnetId
+}
+
+// Address returns the network address.
+func (a *IPAddress) Address() network.Address {
+	return a.info.Address.NetworkAddress()
+}
+
+// Remove removes the address, which must be dead.
+func (a *IPAddress) Remove() error {
+	return a.api.removeIPAddress(a.info.Value)
+}
@@ -128,8 +128,19 @@ type DialOpts struct {
 	// RetryDelay is the amount of time to wait between
 	// unsucssful connection attempts.
 	RetryDelay time.Duration
+
+	// LoginRetryDelay is the initial amount of time to wait before
+	// retrying a login that the server refused because it is
+	// throttling logins. The delay doubles after each refusal, up to
+	// maxLoginRetryDelay, for as long as Timeout allows. If zero,
+	// throttled logins are not retried.
+	LoginRetryDelay time.Duration
 }
 
+// maxLoginRetryDelay is the maximum amount of time to wait between
+// retries of a throttled login.
+const maxLoginRetryDelay = 30 * time.Second
+
 // DefaultDialOpts returns a DialOpts representing the default
 // parameters for contacting a state server.
 func DefaultDialOpts() DialOpts {
@@ -137,6 +148,7 @@ func DefaultDialOpts() DialOpts {
 		DialAddressInterval: 50 * time.Millisecond,
 		Timeout:             10 * time.Minute,
 		RetryDelay:          2 * time.Second,
+		LoginRetryDelay:     1 * time.Second,
 	}
 }
 
@@ -173,7 +185,7 @@ func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce s
 		certPool: conn.Config().TlsConfig.RootCAs,
 	}
 	if info.Tag != nil || info.Password != "" {
-		if err := loginFunc(st, info.Tag.String(), info.Password, info.Nonce); err != nil {
+		if err := loginWithBackoff(st, info, opts, loginFunc); err != nil {
 			conn.Close()
 			return nil, err
 		}
@@ -184,6 +196,25 @@ func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce s
 	return st, nil
 }
 
+// loginWithBackoff logs in using loginFunc, retrying with exponential
+// backoff while the server reports that logins are being throttled.
+func loginWithBackoff(st *State, info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce string) error) error {
+	delay := opts.LoginRetryDelay
+	deadline := time.Now().Add(opts.Timeout)
+	for {
+		err := loginFunc(st, info.Tag.String(), info.Password, info.Nonce)
+		if !params.IsCodeTryAgain(err) || delay <= 0 || time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		logger.Infof("login throttled by API server; retrying in %v", delay)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxLoginRetryDelay {
+			delay = maxLoginRetryDelay
+		}
+	}
+}
+
 // OpenWithVersion uses an explicit version of the Admin facade to call Login
 // on. This allows the caller to pretend to be an older client, and is used
 // only in testing.
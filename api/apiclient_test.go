@@ -9,6 +9,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"golang.org/x/net/websocket"
 
@@ -238,3 +239,52 @@ func assertConnAddrForEnv(c *gc.C, conn *websocket.Conn, addr, envUUID, tail str
 func assertConnAddrForRoot(c *gc.C, conn *websocket.Conn, addr string) {
 	c.Assert(conn.RemoteAddr(), gc.Matches, "^wss://"+addr+"/$")
 }
+
+func (s *apiclientSuite) TestLoginWithBackoffRetriesThrottledLogins(c *gc.C) {
+	info := &api.Info{Tag: names.NewMachineTag("0"), Password: "secret"}
+	opts := api.DialOpts{
+		Timeout:         time.Minute,
+		LoginRetryDelay: time.Millisecond,
+	}
+	attempts := 0
+	loginFunc := func(st *api.State, tag, pwd, nonce string) error {
+		attempts++
+		c.Check(tag, gc.Equals, "machine-0")
+		c.Check(pwd, gc.Equals, "secret")
+		if attempts < 3 {
+			return &params.Error{Code: params.CodeTryAgain, Message: "try again"}
+		}
+		return nil
+	}
+	err := api.LoginWithBackoff(nil, info, opts, loginFunc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attempts, gc.Equals, 3)
+}
+
+func (s *apiclientSuite) TestLoginWithBackoffNoRetryDelay(c *gc.C) {
+	info := &api.Info{Tag: names.NewMachineTag("0"), Password: "secret"}
+	attempts := 0
+	loginFunc := func(st *api.State, tag, pwd, nonce string) error {
+		attempts++
+		return &params.Error{Code: params.CodeTryAgain, Message: "try again"}
+	}
+	err := api.LoginWithBackoff(nil, info, api.DialOpts{}, loginFunc)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+	c.Assert(attempts, gc.Equals, 1)
+}
+
+func (s *apiclientSuite) TestLoginWithBackoffOtherErrorsNotRetried(c *gc.C) {
+	info := &api.Info{Tag: names.NewMachineTag("0"), Password: "secret"}
+	opts := api.DialOpts{
+		Timeout:         time.Minute,
+		LoginRetryDelay: time.Millisecond,
+	}
+	attempts := 0
+	loginFunc := func(st *api.State, tag, pwd, nonce string) error {
+		attempts++
+		return errors.New("bad password")
+	}
+	err := api.LoginWithBackoff(nil, info, opts, loginFunc)
+	c.Assert(err, gc.ErrorMatches, "bad password")
+	c.Assert(attempts, gc.Equals, 1)
+}
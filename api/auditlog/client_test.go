@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/auditlog"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditLogSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+func (s *auditLogSuite) TestEntries(c *gc.C) {
+	called := false
+	expected := []params.AuditLogEntry{{
+		Caller: "user-admin@local",
+		Facade: "Client",
+		Method: "DestroyMachines",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "AuditLog")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Entries")
+			c.Check(a, jc.DeepEquals, params.AuditLogFilter{Facade: "Client", Limit: 10})
+
+			results, ok := response.(*params.AuditLogResults)
+			c.Assert(ok, jc.IsTrue)
+			results.Entries = expected
+			return nil
+		})
+	client := auditlog.NewClient(apiCaller)
+	entries, err := client.Entries(params.AuditLogFilter{Facade: "Client", Limit: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(entries, jc.DeepEquals, expected)
+}
+
+func (s *auditLogSuite) TestEntriesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := auditlog.NewClient(apiCaller)
+	_, err := client.Entries(params.AuditLogFilter{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,43 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cleaner provides access to the API facade used by the
+// cleaner worker.
+package cleaner
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const cleanerFacade = "Cleaner"
+
+// API provides access to the Cleaner API facade.
+type API struct {
+	facade base.FacadeCaller
+}
+
+// NewAPI creates a new client-side Cleaner facade.
+func NewAPI(caller base.APICaller) *API {
+	return &API{base.NewFacadeCaller(caller, cleanerFacade)}
+}
+
+// Cleanup runs all the cleanups scheduled in state.
+func (api *API) Cleanup() error {
+	return api.facade.FacadeCall("Cleanup", nil, nil)
+}
+
+// WatchCleanups returns a watcher that notifies when cleanups are
+// scheduled in state.
+func (api *API) WatchCleanups() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	if err := api.facade.FacadeCall("WatchCleanups", nil, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(api.facade.RawAPICaller(), result)
+	return w, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleaner_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/cleaner"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CleanerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&CleanerSuite{})
+
+func (s *CleanerSuite) TestCleanup(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Cleaner")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Cleanup")
+			c.Check(a, gc.IsNil)
+			return nil
+		})
+	err := cleaner.NewAPI(apiCaller).Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *CleanerSuite) TestCleanupError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	err := cleaner.NewAPI(apiCaller).Cleanup()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *CleanerSuite) TestWatchCleanupsError(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Cleaner")
+			c.Check(request, gc.Equals, "WatchCleanups")
+			result, ok := response.(*params.NotifyWatchResult)
+			c.Assert(ok, jc.IsTrue)
+			result.Error = &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}
+			return nil
+		})
+	w, err := cleaner.NewAPI(apiCaller).WatchCleanups()
+	c.Assert(w, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(called, jc.IsTrue)
+}
@@ -105,6 +105,11 @@ type UnitStatusHistory struct {
 	Statuses []AgentStatus
 }
 
+// MachineStatusHistory holds a slice of statuses.
+type MachineStatusHistory struct {
+	Statuses []AgentStatus
+}
+
 // UnitStatus holds status info about a unit.
 type UnitStatus struct {
 	// UnitAgent holds the status for a unit's agent.
@@ -195,6 +200,25 @@ func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, siz
 	return &results, nil
 }
 
+// MachineStatusHistory retrieves the last <size> statuses of the
+// agent for the machine with the given id.
+func (c *Client) MachineStatusHistory(machineId string, size int) (*MachineStatusHistory, error) {
+	var results MachineStatusHistory
+	args := params.StatusHistory{
+		Kind: params.KindAgent,
+		Size: size,
+		Name: machineId,
+	}
+	err := c.facade.FacadeCall("MachineStatusHistory", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return &MachineStatusHistory{}, errors.NotImplementedf("MachineStatusHistory")
+		}
+		return &MachineStatusHistory{}, errors.Trace(err)
+	}
+	return &results, nil
+}
+
 // LegacyMachineStatus holds just the instance-id of a machine.
 type LegacyMachineStatus struct {
 	InstanceId string // Not type instance.Id just to match original api.
@@ -257,6 +281,22 @@ func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.Error
 	return results.Results, err
 }
 
+// SetMachineLabels replaces the user-defined labels of the given
+// machine.
+func (c *Client) SetMachineLabels(machine names.MachineTag, labels []string) error {
+	p := params.SetMachineLabels{
+		Machines: []params.MachineLabels{{
+			MachineTag: machine.String(),
+			Labels:     labels,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetMachineLabels", p, &results); err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
 // PublicAddress returns the public address of the specified
 // machine or unit. For a machine, target is an id not a tag.
 func (c *Client) PublicAddress(target string) (string, error) {
@@ -275,6 +315,15 @@ func (c *Client) PrivateAddress(target string) (string, error) {
 	return results.PrivateAddress, err
 }
 
+// SSHHostKeys returns the public SSH host keys of the specified
+// machine, or of the machine hosting the specified unit.
+func (c *Client) SSHHostKeys(target string) ([]string, error) {
+	var results params.SSHHostKeysResults
+	p := params.SSHHostKeys{Target: target}
+	err := c.facade.FacadeCall("SSHHostKeys", p, &results)
+	return results.PublicKeys, err
+}
+
 // ServiceSetYAML sets configuration options on a service
 // given options in YAML format.
 func (c *Client) ServiceSetYAML(service string, yaml string) error {
@@ -340,6 +389,19 @@ func (c *Client) AddMachines(machineParams []params.AddMachineParams) ([]params.
 	return results.Machines, err
 }
 
+// AddIdenticalMachines adds n machines configured according to
+// the supplied parameters. The machines are added atomically: if
+// any of them cannot be added, none are.
+func (c *Client) AddIdenticalMachines(machineParams params.AddMachineParams, n int) ([]params.AddMachinesResult, error) {
+	args := params.AddIdenticalMachines{
+		MachineParams: machineParams,
+		NumMachines:   n,
+	}
+	results := new(params.AddMachinesResults)
+	err := c.facade.FacadeCall("AddIdenticalMachines", args, results)
+	return results.Machines, err
+}
+
 // ProvisioningScript returns a shell script that, when run,
 // provisions a machine agent on the machine executing the script.
 func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (script string, err error) {
@@ -618,6 +680,38 @@ func (c *Client) UnshareEnvironment(users ...names.UserTag) error {
 	return result.Combine()
 }
 
+// GrantEnvironAccess grants the given users read or write access to
+// the environment.
+func (c *Client) GrantEnvironAccess(access params.EnvironAccess, users ...names.UserTag) error {
+	return c.modifyEnvironAccess(params.GrantEnvironAccess, access, users)
+}
+
+// RevokeEnvironAccess revokes read or write access to the environment
+// from the given users. Users whose write access is revoked may still
+// read the environment.
+func (c *Client) RevokeEnvironAccess(access params.EnvironAccess, users ...names.UserTag) error {
+	return c.modifyEnvironAccess(params.RevokeEnvironAccess, access, users)
+}
+
+func (c *Client) modifyEnvironAccess(action params.EnvironAccessAction, access params.EnvironAccess, users []names.UserTag) error {
+	args := params.ModifyEnvironAccess{
+		Changes: make([]params.ModifyEnvironUserAccess, len(users)),
+	}
+	for i, user := range users {
+		args.Changes[i] = params.ModifyEnvironUserAccess{
+			UserTag: user.String(),
+			Action:  action,
+			Access:  access,
+		}
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ModifyEnvironAccess", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.Combine()
+}
+
 // WatchAll holds the id of the newly-created AllWatcher.
 type WatchAll struct {
 	AllWatcherId string
@@ -674,6 +768,13 @@ func (c *Client) EnvironmentSet(config map[string]interface{}) error {
 	return c.facade.FacadeCall("EnvironmentSet", args, nil)
 }
 
+// EnvironmentUpdateCredentials replaces the provider credentials in the
+// environment configuration, after checking them with the provider.
+func (c *Client) EnvironmentUpdateCredentials(credentials map[string]interface{}) error {
+	args := params.EnvironmentSet{Config: credentials}
+	return c.facade.FacadeCall("EnvironmentUpdateCredentials", args, nil)
+}
+
 // EnvironmentUnset sets the given key-value pairs in the environment.
 func (c *Client) EnvironmentUnset(keys ...string) error {
 	args := params.EnvironmentUnset{Keys: keys}
@@ -698,11 +799,27 @@ func (c *Client) FindTools(
 	majorVersion, minorVersion int,
 	series, arch string,
 ) (result params.FindToolsResult, err error) {
+	return c.FindToolsInStream(majorVersion, minorVersion, series, arch, "")
+}
+
+// FindToolsInStream returns a List containing all tools matching the
+// specified parameters, searching the given simplestreams stream
+// rather than the environment's agent-stream if stream is non-empty.
+// Searching another stream requires version 1 of the Client facade;
+// older servers would silently search the environment's stream.
+func (c *Client) FindToolsInStream(
+	majorVersion, minorVersion int,
+	series, arch, stream string,
+) (result params.FindToolsResult, err error) {
+	if stream != "" && c.BestAPIVersion() < 1 {
+		return result, errors.NotSupportedf("searching the %q agent stream on this API server", stream)
+	}
 	args := params.FindToolsParams{
 		MajorVersion: majorVersion,
 		MinorVersion: minorVersion,
 		Arch:         arch,
 		Series:       series,
+		AgentStream:  stream,
 	}
 	err = c.facade.FacadeCall("FindTools", args, &result)
 	return result, err
@@ -0,0 +1,33 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package diagnose provides access to the diagnose API facade.
+package diagnose
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the diagnose API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the diagnose API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Diagnose")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ServerChecks asks the API server to check its host for problems.
+func (c *Client) ServerChecks() (params.ServerChecks, error) {
+	var result params.ServerChecks
+	if err := c.facade.FacadeCall("ServerChecks", nil, &result); err != nil {
+		return params.ServerChecks{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diagnose_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/diagnose"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type diagnoseSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&diagnoseSuite{})
+
+func (s *diagnoseSuite) TestServerChecks(c *gc.C) {
+	expected := params.ServerChecks{
+		Time:     time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC),
+		Problems: []string{"no DNS nameservers configured in /etc/resolv.conf"},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Diagnose")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ServerChecks")
+			c.Check(a, gc.IsNil)
+			result, ok := response.(*params.ServerChecks)
+			c.Assert(ok, jc.IsTrue)
+			*result = expected
+			return nil
+		})
+	client := diagnose.NewClient(apiCaller)
+	checks, err := client.ServerChecks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, jc.DeepEquals, expected)
+}
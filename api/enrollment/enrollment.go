@@ -0,0 +1,50 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package enrollment
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const enrollmentFacade = "Enrollment"
+
+// State provides access to the Enrollment API facade, used by a
+// machine agent to replace the one-time password it was provisioned
+// with.
+type State struct {
+	facade base.FacadeCaller
+}
+
+// NewState creates a new client-side Enrollment facade.
+func NewState(caller base.APICaller) *State {
+	return &State{base.NewFacadeCaller(caller, enrollmentFacade)}
+}
+
+// Enroll replaces the one-time password of the machine with the given
+// tag with the given password. It must be called by the machine agent
+// itself, while logged in with the one-time password.
+func (st *State) Enroll(tag names.MachineTag, password string) error {
+	var results params.ErrorResults
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{
+			Tag:      tag.String(),
+			Password: password,
+		}},
+	}
+	err := st.facade.FacadeCall("Enroll", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return err
+	}
+	return nil
+}
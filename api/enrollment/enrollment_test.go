@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package enrollment_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type enrollmentSuite struct {
+	testing.JujuConnSuite
+
+	machine *state.Machine
+	st      *api.State
+}
+
+var _ = gc.Suite(&enrollmentSuite{})
+
+func (s *enrollmentSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	password, err := utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetInitialPassword(password)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetProvisioned("foo", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st = s.OpenAPIAsMachine(c, s.machine.Tag(), password, "fake_nonce")
+}
+
+func (s *enrollmentSuite) TestEnroll(c *gc.C) {
+	password, err := utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.st.Enrollment().Enroll(s.machine.MachineTag(), password)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.HasInitialPassword(), jc.IsFalse)
+	c.Assert(s.machine.PasswordValid(password), jc.IsTrue)
+}
+
+func (s *enrollmentSuite) TestEnrollOtherMachine(c *gc.C) {
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	password, err := utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.st.Enrollment().Enroll(other.MachineTag(), password)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
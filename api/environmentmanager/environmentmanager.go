@@ -84,3 +84,20 @@ func (c *Client) ListEnvironments(user string) ([]params.Environment, error) {
 	}
 	return result.Environments, nil
 }
+
+// DestroyEnvironment destroys the hosted environment with the given
+// UUID. Only the owner of the environment, or the state server owner,
+// may destroy it.
+func (c *Client) DestroyEnvironment(uuid string) error {
+	if !names.IsValidEnvironment(uuid) {
+		return errors.Errorf("invalid environment UUID %q", uuid)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewEnvironTag(uuid).String()}},
+	}
+	var result params.ErrorResults
+	if err := c.facade.FacadeCall("DestroyEnvironments", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
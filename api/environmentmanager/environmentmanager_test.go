@@ -4,6 +4,7 @@
 package environmentmanager_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -115,3 +116,24 @@ func (s *environmentmanagerSuite) TestListEnvironments(c *gc.C) {
 	envNames := []string{envs[0].Name, envs[1].Name}
 	c.Assert(envNames, jc.SameContents, []string{"first", "second"})
 }
+
+func (s *environmentmanagerSuite) TestDestroyEnvironmentBadUUID(c *gc.C) {
+	envManager := s.OpenAPI(c)
+	err := envManager.DestroyEnvironment("not-a-uuid")
+	c.Assert(err, gc.ErrorMatches, `invalid environment UUID "not-a-uuid"`)
+}
+
+func (s *environmentmanagerSuite) TestDestroyEnvironment(c *gc.C) {
+	s.SetFeatureFlags(feature.JES)
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{
+		Owner: names.NewUserTag("user@remote")})
+	defer st.Close()
+	envTag := st.EnvironTag()
+
+	envManager := s.OpenAPI(c)
+	err := envManager.DestroyEnvironment(envTag.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.GetEnvironment(envTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
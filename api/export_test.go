@@ -16,6 +16,7 @@ var (
 	BestVersion           = bestVersion
 	FacadeVersions        = &facadeVersions
 	NewHTTPClient         = &newHTTPClient
+	LoginWithBackoff      = loginWithBackoff
 )
 
 // SetServerAddress allows changing the URL to the internal API server
@@ -11,26 +11,34 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":                       0,
+	"Action":                       1,
+	"Addresser":                    1,
 	"Agent":                        1,
 	"AllWatcher":                   0,
 	"Annotations":                  1,
+	"AuditLog":                     1,
 	"Backups":                      0,
 	"Block":                        1,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
-	"Client":                       0,
+	"Cleaner":                      1,
+	"Client":                       1,
+	"Consistency":                  1,
 	"Deployer":                     0,
+	"Diagnose":                     1,
 	"DiskManager":                  1,
+	"Enrollment":                   1,
 	"Environment":                  0,
 	"EnvironmentManager":           1,
 	"FilesystemAttachmentsWatcher": 1,
 	"Firewaller":                   1,
 	"HighAvailability":             1,
+	"HostKeyReporter":              1,
 	"ImageManager":                 1,
 	"KeyManager":                   0,
 	"KeyUpdater":                   0,
 	"LeadershipService":            1,
+	"Listing":                      1,
 	"Logger":                       0,
 	"MachineManager":               1,
 	"Machiner":                     0,
@@ -38,18 +46,22 @@ var facadeVersions = map[string]int{
 	"Networker":                    0,
 	"NotifyWatcher":                0,
 	"Pinger":                       0,
-	"Provisioner":                  0,
+	"Provisioner":                  1,
 	"Reboot":                       1,
 	"RelationUnitsWatcher":         0,
 	"Rsyslog":                      0,
-	"Service":                      1,
+	"ServerStats":                  1,
+	"Service":                      2,
 	"Storage":                      1,
 	"StorageProvisioner":           1,
 	"StringsWatcher":               0,
 	"Upgrader":                     0,
+	"UnitDetails":                  1,
+	"UnitEvents":                   1,
 	"Uniter":                       2,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
+	"WorkerHealth":                 1,
 }
 
 // bestVersion tries to find the newest version in the version list that we can
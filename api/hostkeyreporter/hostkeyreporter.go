@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hostkeyreporter provides access to the HostKeyReporter API
+// facade, used by machine agents to report their SSH host keys.
+package hostkeyreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// State provides access to the HostKeyReporter API facade.
+type State struct {
+	facade base.FacadeCaller
+}
+
+// NewState returns a new State using the given API caller.
+func NewState(caller base.APICaller) *State {
+	return &State{base.NewFacadeCaller(caller, "HostKeyReporter")}
+}
+
+// ReportKeys records the public SSH host keys of the machine with the
+// given tag, replacing any recorded previously.
+func (st *State) ReportKeys(tag names.MachineTag, publicKeys []string) error {
+	args := params.SSHHostKeySet{
+		EntityKeys: []params.EntitySSHHostKeys{{
+			Tag:        tag.String(),
+			PublicKeys: publicKeys,
+		}},
+	}
+	var results params.ErrorResults
+	if err := st.facade.FacadeCall("ReportKeys", args, &results); err != nil {
+		return err
+	}
+	return results.OneError()
+}
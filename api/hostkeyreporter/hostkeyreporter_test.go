@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/hostkeyreporter"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
+)
+
+type hostKeyReporterSuite struct {
+	jujutesting.JujuConnSuite
+
+	rawMachine *state.Machine
+	reporter   *hostkeyreporter.State
+}
+
+var _ = gc.Suite(&hostKeyReporterSuite{})
+
+func (s *hostKeyReporterSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	stateAPI, rawMachine := s.OpenAPIAsNewMachine(c)
+	s.rawMachine = rawMachine
+	s.reporter = stateAPI.HostKeyReporter()
+}
+
+func (s *hostKeyReporterSuite) TestReportKeys(c *gc.C) {
+	keys := []string{sshtesting.ValidKeyOne.Key}
+	err := s.reporter.ReportKeys(s.rawMachine.Tag().(names.MachineTag), keys)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rawMachine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.rawMachine.SSHHostKeys(), jc.DeepEquals, keys)
+}
+
+func (s *hostKeyReporterSuite) TestReportKeysForbiddenMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.reporter.ReportKeys(m.Tag().(names.MachineTag), []string{sshtesting.ValidKeyOne.Key})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
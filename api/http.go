@@ -5,6 +5,7 @@ package api
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"net/http"
 	"net/url"
@@ -14,6 +15,7 @@ import (
 
 	apihttp "github.com/juju/juju/api/http"
 	apiserverhttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/cert"
 )
 
 var newHTTPClient = func(s *State) apihttp.HTTPClient {
@@ -22,9 +24,27 @@ var newHTTPClient = func(s *State) apihttp.HTTPClient {
 
 // NewHTTPClient returns an HTTP client initialized based on State.
 func (s *State) NewHTTPClient() *http.Client {
+	return newValidatingHTTPClient(s.certPool)
+}
+
+// NewHTTPClientForCACert returns an HTTP client that only accepts
+// API servers whose certificates are signed by the given CA
+// certificate. Agents use it to download tools and charms from
+// the API server.
+func NewHTTPClientForCACert(caCert string) (*http.Client, error) {
+	xcert, err := cert.ParseCert(caCert)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot parse CA certificate")
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(xcert)
+	return newValidatingHTTPClient(pool), nil
+}
+
+func newValidatingHTTPClient(rootCAs *x509.CertPool) *http.Client {
 	httpclient := utils.GetValidatingHTTPClient()
 	tlsconfig := tls.Config{
-		RootCAs: s.certPool,
+		RootCAs: rootCAs,
 		// We want to be specific here (rather than just using "anything".
 		// See commit 7fc118f015d8480dfad7831788e4b8c0432205e8 (PR 899).
 		ServerName: "juju-apiserver",
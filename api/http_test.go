@@ -5,6 +5,7 @@ package api_test
 
 import (
 	"net/http"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -12,7 +13,9 @@ import (
 	"github.com/juju/juju/api"
 	apihttp "github.com/juju/juju/api/http"
 	apihttptesting "github.com/juju/juju/api/http/testing"
+	"github.com/juju/juju/cert"
 	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type httpSuite struct {
@@ -75,6 +78,33 @@ func (s *httpSuite) TestNewHTTPClientValidatesCert(c *gc.C) {
 	c.Check(resp.StatusCode, gc.Equals, http.StatusNotFound)
 }
 
+func (s *httpSuite) TestNewHTTPClientForCACertValidatesCert(c *gc.C) {
+	req, err := s.APIState.NewHTTPRequest("GET", "somefacade")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient, err := api.NewHTTPClientForCACert(coretesting.CACert)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := httpClient.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(resp.StatusCode, gc.Equals, http.StatusNotFound)
+}
+
+func (s *httpSuite) TestNewHTTPClientForCACertRejectsOtherCA(c *gc.C) {
+	req, err := s.APIState.NewHTTPRequest("GET", "somefacade")
+	c.Assert(err, jc.ErrorIsNil)
+	otherCACert, _, err := cert.NewCA("other", time.Now().AddDate(0, 0, 1))
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient, err := api.NewHTTPClientForCACert(otherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = httpClient.Do(req)
+	c.Assert(err, gc.ErrorMatches, ".*certificate signed by unknown authority")
+}
+
+func (s *httpSuite) TestNewHTTPClientForCACertInvalid(c *gc.C) {
+	_, err := api.NewHTTPClientForCACert("bad")
+	c.Assert(err, gc.ErrorMatches, "cannot parse CA certificate: .*")
+}
+
 func (s *httpSuite) TestSendHTTPRequestSuccess(c *gc.C) {
 	req, resp, err := s.APIState.SendHTTPRequest("somefacade", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,44 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package listing provides access to the listing API facade.
+package listing
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the listing API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the listing API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Listing")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ListMachines returns a summary of every machine in the
+// environment, ordered by machine id.
+func (c *Client) ListMachines() ([]params.ListedMachine, error) {
+	var result params.ListMachinesResults
+	if err := c.facade.FacadeCall("ListMachines", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Machines, nil
+}
+
+// ListUnits returns a summary of every unit in the environment,
+// ordered by unit name.
+func (c *Client) ListUnits() ([]params.ListedUnit, error) {
+	var result params.ListUnitsResults
+	if err := c.facade.FacadeCall("ListUnits", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Units, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package listing_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/listing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type listingSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&listingSuite{})
+
+func (s *listingSuite) TestListMachines(c *gc.C) {
+	expected := []params.ListedMachine{{
+		Id:     "0",
+		Series: "trusty",
+		Life:   "alive",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Listing")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListMachines")
+			c.Check(a, gc.IsNil)
+			result, ok := response.(*params.ListMachinesResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Machines = expected
+			return nil
+		})
+	client := listing.NewClient(apiCaller)
+	machines, err := client.ListMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, jc.DeepEquals, expected)
+}
+
+func (s *listingSuite) TestListUnits(c *gc.C) {
+	expected := []params.ListedUnit{{
+		Name:    "mysql/0",
+		Service: "mysql",
+		Machine: "0",
+		Life:    "alive",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Listing")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListUnits")
+			c.Check(a, gc.IsNil)
+			result, ok := response.(*params.ListUnitsResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Units = expected
+			return nil
+		})
+	client := listing.NewClient(apiCaller)
+	units, err := client.ListUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, expected)
+}
+
+func (s *listingSuite) TestListUnitsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := listing.NewClient(apiCaller)
+	_, err := client.ListUnits()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
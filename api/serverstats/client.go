@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package serverstats provides access to the server stats API facade.
+package serverstats
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the server stats API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the server stats API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ServerStats")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Stats returns live statistics about the load on the API server
+// the client is connected to.
+func (c *Client) Stats() (params.ServerStats, error) {
+	var result params.ServerStats
+	if err := c.facade.FacadeCall("Stats", nil, &result); err != nil {
+		return params.ServerStats{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package serverstats_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/serverstats"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type serverStatsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&serverStatsSuite{})
+
+func (s *serverStatsSuite) TestStats(c *gc.C) {
+	expected := params.ServerStats{
+		Connections: map[string]int{"user": 1, "unit": 3},
+		Resources:   map[string]int{"*common.multiNotifyWatcher": 4},
+		Mongo:       params.MongoStats{SentOps: 12},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ServerStats")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Stats")
+			c.Check(a, gc.IsNil)
+			result, ok := response.(*params.ServerStats)
+			c.Assert(ok, jc.IsTrue)
+			*result = expected
+			return nil
+		})
+	client := serverstats.NewClient(apiCaller)
+	stats, err := client.Stats()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats, jc.DeepEquals, expected)
+}
+
+func (s *serverStatsSuite) TestStatsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := serverstats.NewClient(apiCaller)
+	_, err := client.Stats()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -9,9 +9,12 @@ package service
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/storage"
@@ -48,7 +51,8 @@ func (c *Client) SetMetricCredentials(service string, credentials []byte) error
 // the charm store, and deploys it. It allows the specification of
 // requested networks that must be present on the machines where the
 // service is deployed. Another way to specify networks to include/exclude
-// is using constraints.
+// is using constraints. If assignmentPolicy is not empty, it overrides
+// the environment's policy for choosing machines for the service's units.
 func (c *Client) ServiceDeploy(
 	charmURL string,
 	serviceName string,
@@ -58,17 +62,22 @@ func (c *Client) ServiceDeploy(
 	toMachineSpec string,
 	networks []string,
 	storage map[string]storage.Constraints,
+	assignmentPolicy string,
 ) error {
+	if assignmentPolicy != "" && c.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("deploying with an assignment policy")
+	}
 	args := params.ServicesDeploy{
 		Services: []params.ServiceDeploy{{
-			ServiceName:   serviceName,
-			CharmUrl:      charmURL,
-			NumUnits:      numUnits,
-			ConfigYAML:    configYAML,
-			Constraints:   cons,
-			ToMachineSpec: toMachineSpec,
-			Networks:      networks,
-			Storage:       storage,
+			ServiceName:      serviceName,
+			CharmUrl:         charmURL,
+			NumUnits:         numUnits,
+			ConfigYAML:       configYAML,
+			Constraints:      cons,
+			ToMachineSpec:    toMachineSpec,
+			Networks:         networks,
+			Storage:          storage,
+			AssignmentPolicy: assignmentPolicy,
 		}},
 	}
 	var results params.ErrorResults
@@ -78,3 +87,171 @@ func (c *Client) ServiceDeploy(
 	}
 	return results.OneError()
 }
+
+// InferRelationEndpoints returns the endpoints that AddRelation would
+// relate, given the same endpoint names, without adding the relation.
+// If interfaceName is not empty, only relations using that interface
+// are considered. The result maps service names to their endpoints.
+func (c *Client) InferRelationEndpoints(interfaceName string, endpoints ...string) (map[string]charm.Relation, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("InferRelationEndpoints")
+	}
+	args := params.RelationEndpointsQueries{
+		Queries: []params.RelationEndpointsQuery{{
+			Endpoints: endpoints,
+			Interface: interfaceName,
+		}},
+	}
+	var results params.RelationEndpointsResults
+	if err := c.facade.FacadeCall("InferRelationEndpoints", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Endpoints, nil
+}
+
+// CharmOrigin returns the charm store that the given service's
+// charm was deployed from, or nil if it is not known.
+func (c *Client) CharmOrigin(serviceName string) (*params.CharmOrigin, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("CharmOrigin")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(serviceName).String()}},
+	}
+	var results params.CharmOriginResults
+	if err := c.facade.FacadeCall("CharmOrigins", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Origin, nil
+}
+
+// SetCharmOrigin records the charm store that the given service's
+// charm was deployed from. A nil origin clears it.
+func (c *Client) SetCharmOrigin(serviceName string, origin *params.CharmOrigin) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("SetCharmOrigin")
+	}
+	args := params.ServiceCharmOrigins{
+		Origins: []params.ServiceCharmOrigin{{
+			ServiceName: serviceName,
+			Origin:      origin,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetCharmOrigins", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// SetUnitCount adds or removes units of the given service so that it
+// has numUnits alive units, and returns the names of the units added
+// and removed. When removing units, the most recently added units are
+// chosen first, skipping those on any of excludeMachines.
+func (c *Client) SetUnitCount(serviceName string, numUnits int, excludeMachines ...string) (added, removed []string, err error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, nil, errors.NotImplementedf("SetServiceUnitCounts")
+	}
+	args := params.ServiceUnitCounts{
+		Services: []params.ServiceUnitCount{{
+			ServiceName:     serviceName,
+			NumUnits:        numUnits,
+			ExcludeMachines: excludeMachines,
+		}},
+	}
+	var results params.ServiceUnitCountResults
+	if err := c.facade.FacadeCall("SetServiceUnitCounts", args, &results); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Added, result.Removed, result.Error
+	}
+	return result.Added, result.Removed, nil
+}
+
+// SetServicesConfig applies the given configuration changes to several
+// services at once. Either all of the changes are made or none are.
+func (c *Client) SetServicesConfig(changes ...params.ServiceConfigChanges) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("SetServicesConfig")
+	}
+	args := params.ServicesConfigChanges{Changes: changes}
+	return c.facade.FacadeCall("SetServicesConfig", args, nil)
+}
+
+// ServiceRemovalProgress reports the units, relations and machines
+// that remain to be dealt with before the given service is removed.
+func (c *Client) ServiceRemovalProgress(serviceName string) (*params.ServiceRemovalProgress, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("ServiceRemovalProgress")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(serviceName).String()}},
+	}
+	var results params.ServiceRemovalProgressResults
+	if err := c.facade.FacadeCall("ServiceRemovalProgress", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Progress, nil
+}
+
+// WatchServiceRemoval returns a watcher that notifies when the
+// removal of the given service may have progressed.
+func (c *Client) WatchServiceRemoval(serviceName string) (watcher.NotifyWatcher, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("WatchServiceRemoval")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(serviceName).String()}},
+	}
+	var results params.NotifyWatchResults
+	if err := c.facade.FacadeCall("WatchServiceRemoval", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return watcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
+
+// ForceDestroyService destroys the given service and removes its
+// remaining units and relations without waiting for their agents.
+func (c *Client) ForceDestroyService(serviceName string) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("ForceDestroyService")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(serviceName).String()}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ForceDestroyServices", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -6,12 +6,14 @@ package service_test
 import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
 
 	"github.com/juju/juju/api/service"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	jujutesting "github.com/juju/juju/juju/testing"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/storage"
 )
 
@@ -89,13 +91,161 @@ func (s *serviceSuite) TestSetServiceDeploy(c *gc.C) {
 		c.Assert(args.Services[0].ToMachineSpec, gc.Equals, "machineSpec")
 		c.Assert(args.Services[0].Networks, gc.DeepEquals, []string{"neta"})
 		c.Assert(args.Services[0].Storage, gc.DeepEquals, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}})
+		c.Assert(args.Services[0].AssignmentPolicy, gc.Equals, "least-loaded")
 
 		result := response.(*params.ErrorResults)
 		result.Results = make([]params.ErrorResult, 1)
 		return nil
 	})
 	err := s.client.ServiceDeploy("charmURL", "serviceA", 2, "configYAML", constraints.MustParse("mem=4G"),
-		"machineSpec", []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}})
+		"machineSpec", []string{"neta"}, map[string]storage.Constraints{"data": storage.Constraints{Pool: "pool"}}, "least-loaded")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *serviceSuite) TestInferRelationEndpoints(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "InferRelationEndpoints")
+		c.Assert(a, jc.DeepEquals, params.RelationEndpointsQueries{
+			Queries: []params.RelationEndpointsQuery{{
+				Endpoints: []string{"wordpress", "mysql"},
+				Interface: "mysql",
+			}},
+		})
+		result := response.(*params.RelationEndpointsResults)
+		result.Results = []params.RelationEndpointsResult{{
+			Endpoints: map[string]charm.Relation{
+				"wordpress": {Name: "db"},
+				"mysql":     {Name: "server"},
+			},
+		}}
+		return nil
+	})
+	endpoints, err := s.client.InferRelationEndpoints("mysql", "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(endpoints, jc.DeepEquals, map[string]charm.Relation{
+		"wordpress": {Name: "db"},
+		"mysql":     {Name: "server"},
+	})
+}
+
+func (s *serviceSuite) TestInferRelationEndpointsError(c *gc.C) {
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		result := response.(*params.RelationEndpointsResults)
+		result.Results = []params.RelationEndpointsResult{{
+			Error: &params.Error{Message: "no relations found"},
+		}}
+		return nil
+	})
+	_, err := s.client.InferRelationEndpoints("", "wordpress", "mysql")
+	c.Assert(err, gc.ErrorMatches, "no relations found")
+}
+
+func (s *serviceSuite) TestInferRelationEndpointsNoMocks(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	endpoints, err := s.client.InferRelationEndpoints("", "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endpoints, gc.HasLen, 2)
+	c.Assert(endpoints["wordpress"].Name, gc.Equals, "db")
+	c.Assert(endpoints["mysql"].Name, gc.Equals, "server")
+}
+
+func (s *serviceSuite) TestCharmOriginNoMocks(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	origin, err := s.client.CharmOrigin("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(origin, gc.IsNil)
+
+	expected := &params.CharmOrigin{StoreURL: "https://api.jujucharms.com/charmstore"}
+	err = s.client.SetCharmOrigin("wordpress", expected)
+	c.Assert(err, jc.ErrorIsNil)
+	origin, err = s.client.CharmOrigin("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(origin, jc.DeepEquals, expected)
+
+	err = s.client.SetCharmOrigin("wordpress", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	origin, err = s.client.CharmOrigin("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(origin, gc.IsNil)
+}
+
+func (s *serviceSuite) TestSetUnitCountNoMocks(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	added, removed, err := s.client.SetUnitCount("wordpress", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added, jc.DeepEquals, []string{"wordpress/0", "wordpress/1"})
+	c.Assert(removed, gc.HasLen, 0)
+
+	added, removed, err = s.client.SetUnitCount("wordpress", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added, gc.HasLen, 0)
+	c.Assert(removed, jc.DeepEquals, []string{"wordpress/1"})
+
+	_, _, err = s.client.SetUnitCount("unknown", 1)
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *serviceSuite) TestSetServicesConfigNoMocks(c *gc.C) {
+	dummy := s.AddTestingCharm(c, "dummy")
+	svc1 := s.AddTestingService(c, "dummy1", dummy)
+	svc2 := s.AddTestingService(c, "dummy2", dummy)
+	err := s.client.SetServicesConfig(params.ServiceConfigChanges{
+		ServiceName: "dummy1",
+		Options:     map[string]string{"outlook": "positive"},
+	}, params.ServiceConfigChanges{
+		ServiceName: "dummy2",
+		Options:     map[string]string{"title": "sir"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "positive"})
+	settings, err = svc2.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "sir"})
+
+	err = s.client.SetServicesConfig(params.ServiceConfigChanges{
+		ServiceName: "dummy1",
+		Unset:       []string{"outlook"},
+	}, params.ServiceConfigChanges{
+		ServiceName: "unknown",
+		Options:     map[string]string{"title": "sir"},
+	})
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+	settings, err = svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "positive"})
+}
+
+func (s *serviceSuite) TestServiceRemovalNoMocks(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	w, err := s.client.WatchServiceRemoval("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	// Initial event.
+	wc.AssertOneChange()
+
+	err = s.APIState.Client().ServiceDestroy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	progress, err := s.client.ServiceRemovalProgress("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(progress, jc.DeepEquals, &params.ServiceRemovalProgress{
+		Units: []string{"wordpress/0"},
+	})
+
+	err = s.client.ForceDestroyService("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	progress, err = s.client.ServiceRemovalProgress("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(progress, jc.DeepEquals, &params.ServiceRemovalProgress{Removed: true})
+}
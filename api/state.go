@@ -10,13 +10,16 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 
+	"github.com/juju/juju/api/addresser"
 	"github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/charmrevisionupdater"
 	"github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/api/diskmanager"
+	"github.com/juju/juju/api/enrollment"
 	"github.com/juju/juju/api/environment"
 	"github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/api/hostkeyreporter"
 	"github.com/juju/juju/api/keyupdater"
 	apilogger "github.com/juju/juju/api/logger"
 	"github.com/juju/juju/api/machiner"
@@ -285,12 +288,23 @@ func (st *State) Firewaller() *firewaller.State {
 	return firewaller.NewState(st)
 }
 
+// Addresser returns access to the Addresser API, used by the
+// addresser worker.
+func (st *State) Addresser() *addresser.API {
+	return addresser.NewAPI(st)
+}
+
 // Agent returns a version of the state that provides
 // functionality required by the agent code.
 func (st *State) Agent() *agent.State {
 	return agent.NewState(st)
 }
 
+// Enrollment returns access to the Enrollment API
+func (st *State) Enrollment() *enrollment.State {
+	return enrollment.NewState(st)
+}
+
 // Upgrader returns access to the Upgrader API
 func (st *State) Upgrader() *upgrader.State {
 	return upgrader.NewState(st)
@@ -326,6 +340,11 @@ func (st *State) KeyUpdater() *keyupdater.State {
 	return keyupdater.NewState(st)
 }
 
+// HostKeyReporter returns access to the HostKeyReporter API
+func (st *State) HostKeyReporter() *hostkeyreporter.State {
+	return hostkeyreporter.NewState(st)
+}
+
 // CharmRevisionUpdater returns access to the CharmRevisionUpdater API
 func (st *State) CharmRevisionUpdater() *charmrevisionupdater.State {
 	return charmrevisionupdater.NewState(st)
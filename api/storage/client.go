@@ -101,3 +101,15 @@ func (c *Client) ListVolumes(machines []string) ([]params.VolumeItem, error) {
 	}
 	return found.Results, nil
 }
+
+// AddToUnit adds specified storage to desired units.
+// The returned results hold an error for each storage directive
+// that could not be added, in the order they were supplied.
+func (c *Client) AddToUnit(storages []params.StorageAddParams) ([]params.ErrorResult, error) {
+	out := params.ErrorResults{}
+	in := params.StoragesAddParams{Storages: storages}
+	if err := c.facade.FacadeCall("AddToUnit", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitdetails provides access to the unit details API facade.
+package unitdetails
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the unit details API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the unit details API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "UnitDetails")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Details returns the details of the given unit, including at most
+// historySize entries of each of its status histories.
+func (c *Client) Details(unit names.UnitTag, historySize int) (*params.UnitDetails, error) {
+	args := params.UnitDetailsQuery{
+		Entities:    []params.Entity{{Tag: unit.String()}},
+		HistorySize: historySize,
+	}
+	var results params.UnitDetailsResults
+	if err := c.facade.FacadeCall("Details", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Details, nil
+}
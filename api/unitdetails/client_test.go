@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitdetails_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/unitdetails"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type unitDetailsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&unitDetailsSuite{})
+
+func (s *unitDetailsSuite) TestDetails(c *gc.C) {
+	expected := &params.UnitDetails{
+		Service: "mysql",
+		Machine: "0",
+		Leader:  true,
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "UnitDetails")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Details")
+			c.Check(a, jc.DeepEquals, params.UnitDetailsQuery{
+				Entities:    []params.Entity{{Tag: "unit-mysql-0"}},
+				HistorySize: 3,
+			})
+			results, ok := response.(*params.UnitDetailsResults)
+			c.Assert(ok, jc.IsTrue)
+			results.Results = []params.UnitDetailsResult{{Details: expected}}
+			return nil
+		})
+	client := unitdetails.NewClient(apiCaller)
+	details, err := client.Details(names.NewUnitTag("mysql/0"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details, jc.DeepEquals, expected)
+}
+
+func (s *unitDetailsSuite) TestDetailsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			results := response.(*params.UnitDetailsResults)
+			results.Results = []params.UnitDetailsResult{{
+				Error: &params.Error{Message: `unit "mysql/0" not found`, Code: params.CodeNotFound},
+			}}
+			return nil
+		})
+	client := unitdetails.NewClient(apiCaller)
+	_, err := client.Details(names.NewUnitTag("mysql/0"), 3)
+	c.Assert(err, gc.ErrorMatches, `unit "mysql/0" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
@@ -128,6 +128,49 @@ func (ru *RelationUnit) Settings() (*Settings, error) {
 	return newSettings(ru.st, ru.relation.tag.String(), ru.unit.tag.String(), result.Settings), nil
 }
 
+// ServiceSettings returns a Settings which allows access to the
+// service-level settings of the unit's service within the relation.
+// Only the leader of the service may write them.
+func (ru *RelationUnit) ServiceSettings() (*Settings, error) {
+	settings, err := ru.ReadServiceSettings(ru.unit.ServiceName())
+	if err != nil {
+		return nil, err
+	}
+	return newServiceSettings(ru.st, ru.relation.tag.String(), ru.unit.tag.String(), settings), nil
+}
+
+// ReadServiceSettings returns a map holding the service-level settings
+// of the named service within this relation. The service may be either
+// the unit's own service or a remote one.
+func (ru *RelationUnit) ReadServiceSettings(serviceName string) (params.Settings, error) {
+	if ru.st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("ReadServiceSettings() (need V2+)")
+	}
+	if !names.IsValidService(serviceName) {
+		return nil, errors.Errorf("%q is not a valid service", serviceName)
+	}
+	var results params.SettingsResults
+	args := params.RelationUnitServices{
+		RelationUnitServices: []params.RelationUnitService{{
+			Relation:  ru.relation.tag.String(),
+			LocalUnit: ru.unit.tag.String(),
+			Service:   names.NewServiceTag(serviceName).String(),
+		}},
+	}
+	err := ru.st.facade.FacadeCall("ReadServiceSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Settings, nil
+}
+
 // ReadSettings returns a map holding the settings of the unit with the
 // supplied name within this relation. An error will be returned if the
 // relation no longer exists, or if the unit's service is not part of the
@@ -162,6 +205,46 @@ func (ru *RelationUnit) ReadSettings(uname string) (params.Settings, error) {
 	return result.Settings, nil
 }
 
+// ReadMultipleSettings returns the settings of each of the given units
+// in the relation, read in a single API call. The result at each index
+// holds either the settings of the unit at the same index in unames, or
+// the error that ReadSettings would return for that unit; a unit whose
+// settings cannot be read does not prevent the others being read.
+func (ru *RelationUnit) ReadMultipleSettings(unames []string) ([]params.SettingsResult, error) {
+	results := make([]params.SettingsResult, len(unames))
+	var args params.RelationUnitPairs
+	var indexes []int
+	for i, uname := range unames {
+		if !names.IsValidUnit(uname) {
+			results[i].Error = &params.Error{
+				Message: fmt.Sprintf("%q is not a valid unit", uname),
+			}
+			continue
+		}
+		args.RelationUnitPairs = append(args.RelationUnitPairs, params.RelationUnitPair{
+			Relation:   ru.relation.tag.String(),
+			LocalUnit:  ru.unit.tag.String(),
+			RemoteUnit: names.NewUnitTag(uname).String(),
+		})
+		indexes = append(indexes, i)
+	}
+	if len(indexes) == 0 {
+		return results, nil
+	}
+	var apiResults params.SettingsResults
+	err := ru.st.facade.FacadeCall("ReadRemoteSettings", args, &apiResults)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiResults.Results) != len(indexes) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(indexes), len(apiResults.Results))
+	}
+	for j, result := range apiResults.Results {
+		results[indexes[j]] = result
+	}
+	return results, nil
+}
+
 // Watch returns a watcher that notifies of changes to counterpart
 // units in the relation.
 func (ru *RelationUnit) Watch() (watcher.RelationUnitsWatcher, error) {
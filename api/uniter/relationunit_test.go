@@ -256,6 +256,72 @@ func (s *relationUnitSuite) TestReadSettingsInvalidUnitTag(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "\"mysql\" is not a valid unit")
 }
 
+func (s *relationUnitSuite) TestReadMultipleSettings(c *gc.C) {
+	myRelUnit, err := s.stateRelation.Unit(s.mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = myRelUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+	otherUnit, err := s.mysqlService.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	otherRelUnit, err := s.stateRelation.Unit(otherUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = otherRelUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, apiRelUnit := s.getRelationUnits(c)
+	results, err := apiRelUnit.ReadMultipleSettings([]string{"mysql/0", "mysql/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.SettingsResult{
+		{Settings: params.Settings{"some": "settings"}},
+		{Settings: params.Settings{}},
+	})
+
+	results, err = apiRelUnit.ReadMultipleSettings(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 0)
+
+	// A unit whose settings cannot be read does not stop the others
+	// being read.
+	results, err = apiRelUnit.ReadMultipleSettings([]string{"mysql", "mysql/0", "mysql/5"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, gc.ErrorMatches, "\"mysql\" is not a valid unit")
+	c.Assert(results[1], jc.DeepEquals, params.SettingsResult{
+		Settings: params.Settings{"some": "settings"},
+	})
+	c.Assert(results[2].Error, gc.NotNil)
+}
+
+func (s *relationUnitSuite) TestReadServiceSettings(c *gc.C) {
+	_, apiRelUnit := s.getRelationUnits(c)
+	gotSettings, err := apiRelUnit.ReadServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotSettings, gc.HasLen, 0)
+
+	settings, err := s.stateRelation.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("endpoint", "10.0.0.2:3306")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	gotSettings, err = apiRelUnit.ReadServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotSettings, gc.DeepEquals, params.Settings{
+		"endpoint": "10.0.0.2:3306",
+	})
+	ownSettings, err := apiRelUnit.ServiceSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ownSettings.Map(), gc.HasLen, 0)
+}
+
+func (s *relationUnitSuite) TestReadServiceSettingsInvalidService(c *gc.C) {
+	_, apiRelUnit := s.getRelationUnits(c)
+	_, err := apiRelUnit.ReadServiceSettings("mysql/0")
+	c.Assert(err, gc.ErrorMatches, `"mysql/0" is not a valid service`)
+	_, err = apiRelUnit.ReadServiceSettings("riak")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *relationUnitSuite) TestWatchRelationUnits(c *gc.C) {
 	// Enter scope with mysqlUnit.
 	myRelUnit, err := s.stateRelation.Unit(s.mysqlUnit)
@@ -10,12 +10,13 @@ import (
 // This module implements a subset of the interface provided by
 // state.Settings, as needed by the uniter API.
 
-// Settings manages changes to unit settings in a relation.
+// Settings manages changes to unit or service settings in a relation.
 type Settings struct {
-	st          *State
-	relationTag string
-	unitTag     string
-	settings    params.Settings
+	st           *State
+	relationTag  string
+	unitTag      string
+	settings     params.Settings
+	updateMethod string
 }
 
 func newSettings(st *State, relationTag, unitTag string, settings params.Settings) *Settings {
@@ -23,13 +24,23 @@ func newSettings(st *State, relationTag, unitTag string, settings params.Setting
 		settings = make(params.Settings)
 	}
 	return &Settings{
-		st:          st,
-		relationTag: relationTag,
-		unitTag:     unitTag,
-		settings:    settings,
+		st:           st,
+		relationTag:  relationTag,
+		unitTag:      unitTag,
+		settings:     settings,
+		updateMethod: "UpdateSettings",
 	}
 }
 
+// newServiceSettings returns a Settings for the service-level settings
+// of the given unit's service. Writes are made on behalf of the unit,
+// which must be the service leader.
+func newServiceSettings(st *State, relationTag, unitTag string, settings params.Settings) *Settings {
+	s := newSettings(st, relationTag, unitTag, settings)
+	s.updateMethod = "UpdateServiceSettings"
+	return s
+}
+
 // Map returns all keys and values of the node.
 //
 // TODO(dimitern): This differes from state.Settings.Map() - it does
@@ -88,7 +99,7 @@ func (s *Settings) Write() error {
 			Settings: settingsCopy,
 		}},
 	}
-	err := s.st.facade.FacadeCall("UpdateSettings", args, &result)
+	err := s.st.facade.FacadeCall(s.updateMethod, args, &result)
 	if err != nil {
 		return err
 	}
@@ -114,6 +114,24 @@ func (u *Unit) SetAgentStatus(status params.Status, info string, data map[string
 	return result.OneError()
 }
 
+// RecordEvents adds the given events to the unit's event log.
+func (u *Unit) RecordEvents(events ...params.UnitEvent) error {
+	if u.st.facade.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("RecordEvents")
+	}
+	var result params.ErrorResults
+	args := params.UnitEventsParams{
+		Units: []params.UnitEventsParam{
+			{Tag: u.tag.String(), Events: events},
+		},
+	}
+	err := u.st.facade.FacadeCall("RecordUnitEvents", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
 // AddMetrics adds the metrics for the unit.
 func (u *Unit) AddMetrics(metrics []params.Metric) error {
 	var result params.ErrorResults
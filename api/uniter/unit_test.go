@@ -129,6 +129,46 @@ func (s *unitSuite) TestSetUnitStatusOldServer(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "SetUnitStatus not implemented")
 }
 
+func (s *unitSuite) TestRecordEvents(c *gc.C) {
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := s.apiUnit.RecordEvents(params.UnitEvent{
+		Kind: params.UnitEventHookStarted,
+		Hook: "install",
+		Time: t0,
+	}, params.UnitEvent{
+		Kind:     params.UnitEventHookCompleted,
+		Hook:     "install",
+		Time:     t0.Add(time.Second),
+		Duration: time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.wordpressUnit.Events(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []state.UnitEvent{{
+		Kind:     state.UnitEventHookCompleted,
+		Hook:     "install",
+		Time:     t0.Add(time.Second),
+		Duration: time.Second,
+	}, {
+		Kind: state.UnitEventHookStarted,
+		Hook: "install",
+		Time: t0,
+	}})
+}
+
+func (s *unitSuite) TestRecordEventsOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	err := s.apiUnit.RecordEvents(params.UnitEvent{
+		Kind: params.UnitEventHookStarted,
+		Hook: "install",
+		Time: time.Now(),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "RecordEvents not implemented")
+}
+
 func (s *unitSuite) TestSetAgentStatusOldServer(c *gc.C) {
 	s.patchNewState(c, uniter.NewStateV1)
 
@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitevents provides access to the unit events API facade.
+package unitevents
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the unit events API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the unit events API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "UnitEvents")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Events returns at most size of the most recent events in the
+// event log of the given unit, newest first.
+func (c *Client) Events(unit names.UnitTag, size int) ([]params.UnitEvent, error) {
+	args := params.UnitEventsQuery{
+		Entities: []params.Entity{{Tag: unit.String()}},
+		Size:     size,
+	}
+	var results params.UnitEventsResults
+	if err := c.facade.FacadeCall("Events", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Events, nil
+}
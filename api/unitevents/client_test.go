@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitevents_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/unitevents"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type unitEventsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&unitEventsSuite{})
+
+func (s *unitEventsSuite) TestEvents(c *gc.C) {
+	expected := []params.UnitEvent{{
+		Kind: params.UnitEventHookStarted,
+		Hook: "install",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "UnitEvents")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Events")
+			c.Check(a, jc.DeepEquals, params.UnitEventsQuery{
+				Entities: []params.Entity{{Tag: "unit-mysql-0"}},
+				Size:     5,
+			})
+			results, ok := response.(*params.UnitEventsResults)
+			c.Assert(ok, jc.IsTrue)
+			results.Results = []params.UnitEventsResult{{Events: expected}}
+			return nil
+		})
+	client := unitevents.NewClient(apiCaller)
+	events, err := client.Events(names.NewUnitTag("mysql/0"), 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, expected)
+}
+
+func (s *unitEventsSuite) TestEventsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			results := response.(*params.UnitEventsResults)
+			results.Results = []params.UnitEventsResult{{
+				Error: &params.Error{Message: `unit "mysql/0" not found`, Code: params.CodeNotFound},
+			}}
+			return nil
+		})
+	client := unitevents.NewClient(apiCaller)
+	_, err := client.Events(names.NewUnitTag("mysql/0"), 5)
+	c.Assert(err, gc.ErrorMatches, `unit "mysql/0" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
@@ -89,6 +89,12 @@ func (c *Client) EnableUser(username string) error {
 	return c.userCall(username, "EnableUser")
 }
 
+// RemoveUser removes a user. Once removed, the user can no longer log in
+// and the name may be reused for a new user.
+func (c *Client) RemoveUser(username string) error {
+	return c.userCall(username, "RemoveUser")
+}
+
 // IncludeDisabled is a type alias to avoid bare true/false values
 // in calls to the client method.
 type IncludeDisabled bool
@@ -0,0 +1,50 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package workerhealth provides access to the worker health API facade.
+package workerhealth
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the worker health API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the worker health API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "WorkerHealth")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SetWorkerHealth reports the health of the workers of the given agent.
+func (c *Client) SetWorkerHealth(agent names.Tag, workers []params.WorkerHealth) error {
+	args := params.SetWorkerHealth{
+		Agents: []params.AgentWorkerHealth{{
+			Tag:     agent.String(),
+			Workers: workers,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetWorkerHealth", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// AllWorkerHealth returns the most recent worker health report of
+// each agent in the environment.
+func (c *Client) AllWorkerHealth() ([]params.AgentWorkerHealth, error) {
+	var results params.AgentWorkerHealthResults
+	if err := c.facade.FacadeCall("AllWorkerHealth", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package workerhealth_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/workerhealth"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type workerHealthSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&workerHealthSuite{})
+
+func (s *workerHealthSuite) TestSetWorkerHealth(c *gc.C) {
+	workers := []params.WorkerHealth{{Name: "machiner", Running: true}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "WorkerHealth")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "SetWorkerHealth")
+			c.Check(a, jc.DeepEquals, params.SetWorkerHealth{
+				Agents: []params.AgentWorkerHealth{{
+					Tag:     "machine-0",
+					Workers: workers,
+				}},
+			})
+			results, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			results.Results = []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}}
+			return nil
+		})
+	client := workerhealth.NewClient(apiCaller)
+	err := client.SetWorkerHealth(names.NewMachineTag("0"), workers)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *workerHealthSuite) TestAllWorkerHealth(c *gc.C) {
+	expected := []params.AgentWorkerHealth{{
+		Tag:     "machine-0",
+		Workers: []params.WorkerHealth{{Name: "addresser", Failed: true}},
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "WorkerHealth")
+			c.Check(request, gc.Equals, "AllWorkerHealth")
+			c.Check(a, gc.IsNil)
+			results, ok := response.(*params.AgentWorkerHealthResults)
+			c.Assert(ok, jc.IsTrue)
+			results.Results = expected
+			return nil
+		})
+	client := workerhealth.NewClient(apiCaller)
+	all, err := client.AllWorkerHealth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, expected)
+}
@@ -574,6 +574,46 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 	c.Assert(myActions[1].Status, gc.Equals, params.ActionCancelled)
 }
 
+func (s *actionSuite) TestWatchActionResults(c *gc.C) {
+	api, err := action.NewActionAPIV1(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	enqueued, err := api.Enqueue(params.Actions{
+		Actions: []params.Action{{
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+		}, {
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enqueued.Results, gc.HasLen, 2)
+	finished := enqueued.Results[0].Action.Tag
+	pending := enqueued.Results[1].Action.Tag
+
+	// Finish the first Action; the initial event for either Action
+	// on the Unit reports it.
+	_, err = api.Cancel(params.Entities{Entities: []params.Entity{{Tag: finished}}})
+	c.Assert(err, jc.ErrorIsNil)
+	finishedTag, err := names.ParseActionTag(finished)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.WatchActionResults(params.Entities{Entities: []params.Entity{
+		{Tag: pending},
+		{Tag: names.NewActionTag("f47ac10b-58cc-4372-a567-0e02b2c3d479").String()},
+		{Tag: s.wordpressUnit.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].StringsWatcherId, gc.Equals, "1")
+	c.Assert(results.Results[0].Changes, jc.SameContents, []string{finishedTag.Id()})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "id not found")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "id not found")
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+}
+
 func (s *actionSuite) TestServicesCharmActions(c *gc.C) {
 	actionSchemas := map[string]map[string]interface{}{
 		"snapshot": {
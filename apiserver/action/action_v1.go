@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Action", 1, NewActionAPIV1)
+}
+
+// ActionAPIV1 implements version 1 of the Action API facade. It adds
+// WatchActionResults to version 0.
+type ActionAPIV1 struct {
+	*ActionAPI
+}
+
+// NewActionAPIV1 returns an initialized ActionAPIV1.
+func NewActionAPIV1(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ActionAPIV1, error) {
+	api, err := NewActionAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ActionAPIV1{api}, nil
+}
+
+// WatchActionResults starts a StringsWatcher for each of the given
+// ActionTags. The watcher notifies the ids of Actions that have
+// finished on the same ActionReceiver, so that clients can wait for
+// an Action's results without polling.
+func (a *ActionAPIV1) WatchActionResults(arg params.Entities) (params.StringsWatchResults, error) {
+	response := params.StringsWatchResults{Results: make([]params.StringsWatchResult, len(arg.Entities))}
+	for i, entity := range arg.Entities {
+		currentResult := &response.Results[i]
+		actionTag, err := names.ParseActionTag(entity.Tag)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		action, err := a.state.ActionByTag(actionTag)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		receiverTag, err := names.ActionReceiverTag(action.Receiver())
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		receiver, err := tagToActionReceiver(a.state, receiverTag.String())
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		watch := a.state.WatchActionResultsFilteredBy(receiver)
+		// Consume the initial event and forward it to the result.
+		if changes, ok := <-watch.Changes(); ok {
+			currentResult.StringsWatcherId = a.resources.Register(watch)
+			currentResult.Changes = changes
+		} else {
+			currentResult.Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package addresser implements the API facade used by the addresser
+// worker to release and remove dead IP addresses.
+package addresser
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Addresser", 1, NewAddresserAPI)
+}
+
+// AddresserAPI provides access to the Addresser API facade.
+type AddresserAPI struct {
+	*common.EnvironWatcher
+	*common.InstanceIdGetter
+
+	st         *state.State
+	resources  *common.Resources
+	authorizer common.Authorizer
+}
+
+// NewAddresserAPI creates a new server-side Addresser API facade.
+func NewAddresserAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*AddresserAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		return nil, common.ErrPerm
+	}
+	return &AddresserAPI{
+		EnvironWatcher:   common.NewEnvironWatcher(st, resources, authorizer),
+		InstanceIdGetter: common.NewInstanceIdGetter(st, common.AuthAlways()),
+		st:               st,
+		resources:        resources,
+		authorizer:       authorizer,
+	}, nil
+}
+
+// IPAddresses returns the details of each IP address with
+// the given values.
+func (api *AddresserAPI) IPAddresses(args params.IPAddressValues) (params.IPAddressResults, error) {
+	results := params.IPAddressResults{
+		Results: make([]params.IPAddressResult, len(args.Values)),
+	}
+	for i, value := range args.Values {
+		addr, err := api.st.IPAddress(value)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = &params.IPAddressInfo{
+			Value:     addr.Value(),
+			Life:      params.Life(addr.Life().String()),
+			MachineId: addr.MachineId(),
+			SubnetId:  addr.SubnetId(),
+			Address:   params.FromNetworkAddress(addr.Address()),
+		}
+	}
+	return results, nil
+}
+
+// RemoveIPAddresses removes each dead IP address with the given
+// values.
+func (api *AddresserAPI) RemoveIPAddresses(args params.IPAddressValues) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Values)),
+	}
+	for i, value := range args.Values {
+		err := api.removeIPAddress(value)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *AddresserAPI) removeIPAddress(value string) error {
+	addr, err := api.st.IPAddress(value)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(addr.Remove())
+}
+
+// WatchIPAddresses starts a watcher reporting the values of
+// IP addresses as they change.
+func (api *AddresserAPI) WatchIPAddresses() (params.StringsWatchResult, error) {
+	watch := api.st.WatchIPAddresses()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: api.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return params.StringsWatchResult{}, watcher.EnsureErr(watch)
+}
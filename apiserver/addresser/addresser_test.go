@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package addresser_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/addresser"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type addresserSuite struct {
+	jujutesting.JujuConnSuite
+
+	resources *common.Resources
+	api       *addresser.AddresserAPI
+}
+
+var _ = gc.Suite(&addresserSuite{})
+
+func (s *addresserSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+
+	auth := apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	}
+	api, err := addresser.NewAddresserAPI(s.State, s.resources, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *addresserSuite) addIPAddress(c *gc.C, value, machineId string) *state.IPAddress {
+	addr, err := s.State.AddIPAddress(network.NewAddress(value), "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = addr.AllocateTo(machineId, "wobble")
+	c.Assert(err, jc.ErrorIsNil)
+	return addr
+}
+
+func (s *addresserSuite) TestNewAddresserAPIRequiresEnvironManager(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("1"),
+	}
+	_, err := addresser.NewAddresserAPI(s.State, s.resources, auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *addresserSuite) TestIPAddresses(c *gc.C) {
+	addr := s.addIPAddress(c, "0.1.2.3", "0")
+	err := addr.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.IPAddresses(params.IPAddressValues{
+		Values: []string{"0.1.2.3", "0.1.2.4"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.IPAddressResults{
+		Results: []params.IPAddressResult{{
+			Result: &params.IPAddressInfo{
+				Value:     "0.1.2.3",
+				Life:      params.Dead,
+				MachineId: "0",
+				SubnetId:  "foobar",
+				Address:   params.FromNetworkAddress(network.NewAddress("0.1.2.3")),
+			},
+		}, {
+			Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `IP address "0.1.2.4" not found`,
+			},
+		}},
+	})
+}
+
+func (s *addresserSuite) TestRemoveIPAddresses(c *gc.C) {
+	dead := s.addIPAddress(c, "0.1.2.3", "0")
+	err := dead.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.addIPAddress(c, "0.1.2.4", "0")
+
+	results, err := s.api.RemoveIPAddresses(params.IPAddressValues{
+		Values: []string{"0.1.2.3", "0.1.2.4", "0.1.2.5"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `cannot remove IP address .*: IP address is not dead`)
+	c.Check(results.Results[2].Error, gc.IsNil)
+
+	_, err = s.State.IPAddress("0.1.2.3")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *addresserSuite) TestWatchIPAddresses(c *gc.C) {
+	s.addIPAddress(c, "0.1.2.3", "0")
+
+	result, err := s.api.WatchIPAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.StringsWatcherId, gc.Equals, "1")
+	c.Assert(result.Changes, jc.SameContents, []string{"0.1.2.3"})
+
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+	wc := statetesting.NewStringsWatcherC(c, s.State, resource.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
@@ -70,21 +70,46 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 
 	var agentPingerNeeded = true
 	var isUser bool
+	// Users of the environment's external identity service log in
+	// with macaroons rather than a tag and password.
+	macaroonLogin := loginVersion > 1 && req.AuthTag == "" && a.srv.macaroonAuth != nil
 	kind, err := names.TagKind(req.AuthTag)
-	if err != nil || kind != names.UserTagKind {
+	if macaroonLogin {
+		isUser = true
+	} else if err != nil || kind != names.UserTagKind {
 		// Users are not rate limited, all other entities are
 		if !a.srv.limiter.Acquire() {
 			logger.Debugf("rate limiting for agent %s", req.AuthTag)
 			return fail, common.ErrTryAgain
 		}
 		defer a.srv.limiter.Release()
+		// Only take a login token once the login can proceed, so
+		// that logins refused by the limiter do not drain the bucket.
+		if a.srv.loginBucket.TakeAvailable(1) == 0 {
+			logger.Debugf("throttling login for agent %s", req.AuthTag)
+			return fail, common.ErrTryAgain
+		}
 	} else {
 		isUser = true
 	}
 
 	serverOnlyLogin := loginVersion > 1 && a.root.envUUID == ""
 
-	entity, lastConnection, err := doCheckCreds(a.root.state, req, !serverOnlyLogin)
+	var entity state.Entity
+	var lastConnection *time.Time
+	if macaroonLogin {
+		entity, lastConnection, err = a.checkMacaroons(req, !serverOnlyLogin)
+		if err, ok := err.(*authentication.DischargeRequiredError); ok {
+			// The user is not logged in; they must discharge the
+			// macaroon with the identity service and try again.
+			return params.LoginResultV1{
+				DischargeRequired:       err.Macaroon,
+				DischargeRequiredReason: err.Cause.Error(),
+			}, nil
+		}
+	} else {
+		entity, lastConnection, err = doCheckCreds(a.root.state, req, !serverOnlyLogin)
+	}
 	if err != nil {
 		if a.maintenanceInProgress() {
 			// An upgrade, restore or similar operation is in
@@ -117,6 +142,7 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		agentPingerNeeded = false
 	}
 	a.root.entity = entity
+	a.srv.stats.SetKind(a.root.resources, entity.Tag().Kind())
 
 	if a.reqNotifier != nil {
 		a.reqNotifier.login(entity.Tag().String())
@@ -132,12 +158,23 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		}
 	}
 
+	// Users that have only been granted read access to the
+	// environment may not make calls that change it.
+	var readOnly bool
+	if isUser && !serverOnlyLogin {
+		readOnly, err = a.isReadOnlyUser(entity)
+		if err != nil {
+			return fail, err
+		}
+	}
+
 	var maybeUserInfo *params.AuthUserInfo
 	// Send back user info if user
 	if isUser {
 		maybeUserInfo = &params.AuthUserInfo{
 			Identity:       entity.Tag().String(),
 			LastConnection: lastConnection,
+			ReadOnly:       readOnly,
 		}
 	}
 
@@ -179,6 +216,37 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		loginResult.Facades = facades
 	}
 
+	// Machine agents that log in with the one-time password they were
+	// provisioned with may do nothing but exchange it for a permanent
+	// one.
+	if machine, ok := entity.(*state.Machine); ok && machine.HasInitialPassword() {
+		authedApi = newEnrollingRoot(authedApi)
+		var facades []params.FacadeVersions
+		for _, facade := range loginResult.Facades {
+			if enrollingRootNames.Contains(facade.Name) {
+				facades = append(facades, facade)
+			}
+		}
+		loginResult.Facades = facades
+	}
+
+	// Expensive calls are queued so that a burst of them cannot
+	// overwhelm the state server.
+	authedApi = newAdmittingRoot(authedApi, a.srv.admission)
+
+	if readOnly {
+		authedApi = newReadOnlyRoot(authedApi)
+	}
+
+	if len(a.srv.authorizers) > 0 {
+		authedApi = newAuthorizingRoot(authedApi, entity.Tag(), a.root.envUUID, a.srv.authorizers)
+	}
+
+	// Mutating calls made by users are recorded in the audit log.
+	if a.srv.auditLog != nil && isUser {
+		authedApi = newAuditingRoot(authedApi, a.root.state, entity.Tag(), a.srv.auditLog)
+	}
+
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return loginResult, nil
@@ -208,6 +276,55 @@ func (a *admin) checkCredsOfStateServerMachine(req params.LoginRequest) (state.E
 	return nil, common.ErrBadCreds
 }
 
+// checkMacaroons authenticates a user of the external identity service
+// by the macaroons in the login request. The identity service only
+// vouches for who the user is: if lookForEnvUser is true, the user must
+// have been given access to the environment; otherwise the login is to
+// the server alone, and the user must have been given access to the
+// state server environment.
+func (a *admin) checkMacaroons(req params.LoginRequest, lookForEnvUser bool) (state.Entity, *time.Time, error) {
+	tag, err := a.srv.macaroonAuth.Authenticate(req.Macaroons)
+	if err != nil {
+		return nil, nil, err
+	}
+	st := a.root.state
+	if !lookForEnvUser {
+		st = a.srv.state
+	}
+	envUser, err := st.EnvironmentUser(tag)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, common.ErrBadCreds)
+	}
+	lastConnection := envUser.LastConnection()
+	envUser.UpdateLastConnection()
+	return externalUser{tag}, lastConnection, nil
+}
+
+// isReadOnlyUser returns whether the given user has been granted
+// only read access to the environment.
+func (a *admin) isReadOnlyUser(entity state.Entity) (bool, error) {
+	tag, ok := entity.Tag().(names.UserTag)
+	if !ok {
+		return false, nil
+	}
+	envUser, err := a.root.state.EnvironmentUser(tag)
+	if err != nil {
+		return false, errors.Wrap(err, common.ErrBadCreds)
+	}
+	return envUser.ReadOnly(), nil
+}
+
+// externalUser is the entity for a user authenticated by the external
+// identity service. Such users have no user document in state.
+type externalUser struct {
+	tag names.UserTag
+}
+
+// Tag implements state.Entity.
+func (u externalUser) Tag() names.Tag {
+	return u.tag
+}
+
 func (a *admin) maintenanceInProgress() bool {
 	if a.srv.validator == nil {
 		return false
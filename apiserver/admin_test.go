@@ -413,6 +413,88 @@ func (s *loginSuite) TestLoginRateLimited(c *gc.C) {
 	}
 }
 
+func (s *loginSuite) TestDenyListMatchesLocalUser(c *gc.C) {
+	listener, err := net.Listen("tcp", ":0")
+	c.Assert(err, jc.ErrorIsNil)
+	srv, err := apiserver.NewServer(
+		s.State,
+		listener,
+		apiserver.ServerConfig{
+			Cert: []byte(coretesting.ServerCert),
+			Key:  []byte(coretesting.ServerKey),
+			Tag:  names.NewMachineTag("0"),
+			Authorizers: []apiserver.CallAuthorizer{
+				apiserver.DenyList{names.NewUserTag("admin")},
+			},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Stop()
+	s.setAdminApi(srv)
+
+	// The admin user logs in as admin@local, which the deny list
+	// entry without a domain must still match.
+	info := &api.Info{
+		Tag:        s.AdminUserTag(c),
+		Password:   "dummy-secret",
+		EnvironTag: s.State.EnvironTag(),
+		Addrs:      []string{srv.Addr()},
+		CACert:     coretesting.CACert,
+	}
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	_, err = st.Client().Status(nil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) TestAgentLoginsThrottled(c *gc.C) {
+	machine, password := s.Factory.MakeMachineReturningPassword(
+		c, &factory.MachineParams{Nonce: "fake_nonce"})
+	listener, err := net.Listen("tcp", ":0")
+	c.Assert(err, jc.ErrorIsNil)
+	srv, err := apiserver.NewServer(
+		s.State,
+		listener,
+		apiserver.ServerConfig{
+			Cert:            []byte(coretesting.ServerCert),
+			Key:             []byte(coretesting.ServerKey),
+			Tag:             names.NewMachineTag("0"),
+			AgentLoginRate:  0.001,
+			AgentLoginBurst: 1,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Stop()
+	s.setAdminApi(srv)
+	c.Assert(apiserver.LoginBucketAvailable(srv), gc.Equals, int64(1))
+
+	info := &api.Info{
+		Tag:        machine.Tag(),
+		Password:   password,
+		Nonce:      "fake_nonce",
+		EnvironTag: s.State.EnvironTag(),
+		Addrs:      []string{srv.Addr()},
+		CACert:     coretesting.CACert,
+	}
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+
+	// The bucket is now empty, so the next agent login is refused
+	// with a try again error.
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+
+	// Users are never throttled.
+	info.Tag = s.AdminUserTag(c)
+	info.Password = "dummy-secret"
+	info.Nonce = ""
+	st, err = api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+}
+
 func (s *loginSuite) TestUsersLoginWhileRateLimited(c *gc.C) {
 	info, cleanup := s.setupMachineAndServer(c)
 	defer cleanup()
@@ -956,3 +1038,34 @@ func (s *loginSuite) TestLoginUpdatesLastLoginAndConnection(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.NotNil)
 	c.Assert(envUser.LastConnection().After(startTime), jc.IsTrue)
 }
+
+func (s *loginSuite) TestReadOnlyUserCannotChangeEnvironment(c *gc.C) {
+	_, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	password := "shhh..."
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: password,
+	})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = envUser.SetReadOnly(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	info.Tag = user.Tag()
+	info.Password = password
+	apiState, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer apiState.Close()
+
+	client := apiState.Client()
+	_, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = client.EnvironmentSet(map[string]interface{}{"some-key": "value"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+}
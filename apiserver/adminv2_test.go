@@ -8,8 +8,12 @@ import (
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+	"gopkg.in/macaroon-bakery.v0/bakery/checkers"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -93,3 +97,101 @@ func (s *loginV2Suite) TestClientLoginToRootOldClient(c *gc.C) {
 	_, err = client.GetEnvironmentConstraints()
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+// setUpIdentityService configures the environment to accept logins
+// from a new identity service, which it returns.
+func (s *loginV2Suite) setUpIdentityService(c *gc.C) *bakery.Service {
+	const identityURL = "https://identity.example.com"
+	identity, err := bakery.NewService(bakery.NewServiceParams{
+		Location: identityURL,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	publicKey, err := identity.PublicKey().MarshalText()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"identity-url":        identityURL,
+		"identity-public-key": string(publicKey),
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return identity
+}
+
+// macaroonLogin logs in to the given API connection as bob, with
+// macaroons discharged by the given identity service.
+func macaroonLogin(c *gc.C, st *api.State, identity *bakery.Service) (params.LoginResultV1, error) {
+	// Without macaroons, the server asks for a discharge.
+	var result params.LoginResultV1
+	err := st.APICall("Admin", 2, "", "Login", params.LoginRequest{}, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.DischargeRequired, gc.NotNil)
+	c.Assert(result.UserInfo, gc.IsNil)
+
+	ms, err := bakery.DischargeAll(result.DischargeRequired, func(_ string, cav macaroon.Caveat) (*macaroon.Macaroon, error) {
+		checker := bakery.ThirdPartyCheckerFunc(func(_, _ string) ([]checkers.Caveat, error) {
+			return []checkers.Caveat{checkers.DeclaredCaveat("username", "bob")}, nil
+		})
+		return identity.Discharge(checker, cav.Id)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var loginResult params.LoginResultV1
+	err = st.APICall("Admin", 2, "", "Login", params.LoginRequest{
+		Macaroons: []macaroon.Slice{ms},
+	}, &loginResult)
+	return loginResult, err
+}
+
+func (s *loginV2Suite) TestMacaroonLogin(c *gc.C) {
+	identity := s.setUpIdentityService(c)
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: "bob@external"})
+
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	info.Tag = nil
+	info.Password = ""
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	loginResult, err := macaroonLogin(c, st, identity)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loginResult.DischargeRequired, gc.IsNil)
+	c.Assert(loginResult.UserInfo, gc.NotNil)
+	c.Assert(loginResult.UserInfo.Identity, gc.Equals, "user-bob@external")
+}
+
+func (s *loginV2Suite) TestMacaroonLoginToServerRequiresAccess(c *gc.C) {
+	identity := s.setUpIdentityService(c)
+
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	info.Tag = nil
+	info.Password = ""
+	info.EnvironTag = names.EnvironTag{}
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// Being known to the identity service is not enough to log in
+	// to the server.
+	_, err = macaroonLogin(c, st, identity)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginV2Suite) TestMacaroonLoginToServer(c *gc.C) {
+	identity := s.setUpIdentityService(c)
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: "bob@external"})
+
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	info.Tag = nil
+	info.Password = ""
+	info.EnvironTag = names.EnvironTag{}
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	loginResult, err := macaroonLogin(c, st, identity)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loginResult.UserInfo.Identity, gc.Equals, "user-bob@external")
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// defaultConcurrencyLimits holds the number of concurrent requests
+// allowed for expensive operations, unless configured otherwise.
+// Facade methods are named "<facade>.<method>"; a bare facade name
+// limits all calls to that facade. The HTTP endpoints are named after
+// their path; only charm uploads are limited, not downloads.
+var defaultConcurrencyLimits = map[string]int{
+	"Client.FullStatus": 10,
+	"Backups.Create":    1,
+	"backups":           2,
+	"charms":            5,
+}
+
+// defaultAdmissionTimeout is how long a request waits for one of its
+// operation's slots to become free before being refused as busy.
+const defaultAdmissionTimeout = 30 * time.Second
+
+// admission limits the number of requests that may run concurrently
+// for each of a set of named operations. Requests over the limit are
+// queued until a slot is released or the admission timeout expires.
+type admission struct {
+	timeout time.Duration
+	slots   map[string]chan struct{}
+}
+
+// newAdmission returns an admission that applies the given limits,
+// falling back to defaultConcurrencyLimits for operations without one.
+// A limit of zero or less removes the default limit for an operation.
+func newAdmission(limits map[string]int, timeout time.Duration) *admission {
+	if timeout <= 0 {
+		timeout = defaultAdmissionTimeout
+	}
+	a := &admission{
+		timeout: timeout,
+		slots:   make(map[string]chan struct{}),
+	}
+	for name, limit := range defaultConcurrencyLimits {
+		a.slots[name] = make(chan struct{}, limit)
+	}
+	for name, limit := range limits {
+		if limit <= 0 {
+			delete(a.slots, name)
+			continue
+		}
+		a.slots[name] = make(chan struct{}, limit)
+	}
+	return a
+}
+
+// limited reports whether any of the named operations is limited.
+func (a *admission) limited(names ...string) bool {
+	for _, name := range names {
+		if _, ok := a.slots[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// admit waits for a slot for each of the named operations that is
+// limited, and returns a function that releases them. If the slots
+// cannot all be taken within the admission timeout, none are held and
+// common.ErrTooBusy is returned.
+func (a *admission) admit(names ...string) (release func(), err error) {
+	var held []chan struct{}
+	release = func() {
+		for _, slots := range held {
+			<-slots
+		}
+	}
+	timeout := time.After(a.timeout)
+	for _, name := range names {
+		slots, ok := a.slots[name]
+		if !ok {
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+			held = append(held, slots)
+		case <-timeout:
+			release()
+			logger.Warningf("refusing %s: too many concurrent requests", name)
+			return nil, common.ErrTooBusy
+		}
+	}
+	return release, nil
+}
+
+// admittingRoot limits the number of concurrent calls to the facade
+// methods dispatched by the wrapped method finder.
+type admittingRoot struct {
+	rpc.MethodFinder
+	admission *admission
+}
+
+// newAdmittingRoot returns a new admittingRoot that applies the
+// given admission control.
+func newAdmittingRoot(finder rpc.MethodFinder, admission *admission) *admittingRoot {
+	return &admittingRoot{
+		MethodFinder: finder,
+		admission:    admission,
+	}
+}
+
+// FindMethod returns a method caller that waits for admission before
+// making the call, if calls to the method are limited.
+func (r *admittingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return caller, err
+	}
+	names := []string{rootName, rootName + "." + methodName}
+	if !r.admission.limited(names...) {
+		return caller, nil
+	}
+	return &admittingMethodCaller{
+		MethodCaller: caller,
+		admission:    r.admission,
+		names:        names,
+	}, nil
+}
+
+// admittingMethodCaller wraps a MethodCaller, waiting for admission
+// before each call made through it.
+type admittingMethodCaller struct {
+	rpcreflect.MethodCaller
+	admission *admission
+	names     []string
+}
+
+// Call is part of the rpcreflect.MethodCaller interface.
+func (c *admittingMethodCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	release, err := c.admission.admit(c.names...)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	defer release()
+	return c.MethodCaller.Call(objId, arg)
+}
+
+// admittingHandler is an HTTP handler that waits for admission before
+// serving each request with the wrapped handler. If methods is not
+// empty, requests using other methods are served without admission.
+type admittingHandler struct {
+	handler interface {
+		http.Handler
+		errorSender
+	}
+	admission *admission
+	name      string
+	methods   set.Strings
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *admittingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.methods.IsEmpty() && !h.methods.Contains(r.Method) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	release, err := h.admission.admit(h.name)
+	if err != nil {
+		h.handler.sendError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer release()
+	h.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type admissionInternalSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&admissionInternalSuite{})
+
+// countingHandler counts the requests it serves.
+type countingHandler struct {
+	served int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.served++
+}
+
+func (h *countingHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	w.WriteHeader(statusCode)
+}
+
+func (s *admissionInternalSuite) TestAdmittingHandlerLimitsOnlyGivenMethods(c *gc.C) {
+	handler := &countingHandler{}
+	admission := newAdmission(map[string]int{"charms": 1}, testing.ShortWait)
+	h := &admittingHandler{
+		handler:   handler,
+		admission: admission,
+		name:      "charms",
+		methods:   set.NewStrings("POST"),
+	}
+	// Take the only slot, as a concurrent upload would.
+	release, err := admission.admit("charms")
+	c.Assert(err, gc.IsNil)
+	defer release()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, &http.Request{Method: "GET"})
+	c.Assert(w.Code, gc.Equals, http.StatusOK)
+	c.Assert(handler.served, gc.Equals, 1)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, &http.Request{Method: "POST"})
+	c.Assert(w.Code, gc.Equals, http.StatusServiceUnavailable)
+	c.Assert(handler.served, gc.Equals, 1)
+}
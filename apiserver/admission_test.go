@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"reflect"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc/rpcreflect"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type admissionSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&admissionSuite{})
+
+// blockingFinder returns callers that block until a value
+// is sent on its unblock channel.
+type blockingFinder struct {
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (f *blockingFinder) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	return blockingCaller{f}, nil
+}
+
+type blockingCaller struct {
+	finder *blockingFinder
+}
+
+func (c blockingCaller) ParamsType() reflect.Type {
+	return nil
+}
+
+func (c blockingCaller) ResultType() reflect.Type {
+	return reflect.TypeOf(params.ErrorResult{})
+}
+
+func (c blockingCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	c.finder.started <- struct{}{}
+	<-c.finder.unblock
+	return reflect.ValueOf(params.ErrorResult{}), nil
+}
+
+func (s *admissionSuite) newFinder() *blockingFinder {
+	return &blockingFinder{
+		started: make(chan struct{}, 10),
+		unblock: make(chan struct{}),
+	}
+}
+
+func (s *admissionSuite) startCall(c *gc.C, caller rpcreflect.MethodCaller, finder *blockingFinder) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := caller.Call("", reflect.Value{})
+		done <- err
+	}()
+	select {
+	case <-finder.started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("call not started")
+	}
+	return done
+}
+
+func (s *admissionSuite) TestRefusesCallsOverLimit(c *gc.C) {
+	finder := s.newFinder()
+	root := apiserver.TestingAdmittingRoot(finder, map[string]int{"Slow.Call": 1}, coretesting.ShortWait)
+	caller, err := root.FindMethod("Slow", 0, "Call")
+	c.Assert(err, jc.ErrorIsNil)
+
+	done := s.startCall(c, caller, finder)
+	_, err = caller.Call("", reflect.Value{})
+	c.Assert(err, gc.Equals, common.ErrTooBusy)
+	c.Assert(params.IsCodeTryAgain(common.ServerError(err)), jc.IsTrue)
+
+	finder.unblock <- struct{}{}
+	c.Assert(<-done, jc.ErrorIsNil)
+
+	// Once the first call has finished, another is admitted.
+	done = s.startCall(c, caller, finder)
+	finder.unblock <- struct{}{}
+	c.Assert(<-done, jc.ErrorIsNil)
+}
+
+func (s *admissionSuite) TestQueuesCallsOverLimit(c *gc.C) {
+	finder := s.newFinder()
+	root := apiserver.TestingAdmittingRoot(finder, map[string]int{"Slow": 1}, coretesting.LongWait)
+	caller, err := root.FindMethod("Slow", 0, "Call")
+	c.Assert(err, jc.ErrorIsNil)
+
+	first := s.startCall(c, caller, finder)
+	second := make(chan error, 1)
+	go func() {
+		_, err := caller.Call("", reflect.Value{})
+		second <- err
+	}()
+	select {
+	case <-finder.started:
+		c.Fatalf("second call started while the first was running")
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	finder.unblock <- struct{}{}
+	c.Assert(<-first, jc.ErrorIsNil)
+	select {
+	case <-finder.started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("second call not started")
+	}
+	finder.unblock <- struct{}{}
+	c.Assert(<-second, jc.ErrorIsNil)
+}
+
+func (s *admissionSuite) TestUnlimitedCalls(c *gc.C) {
+	finder := s.newFinder()
+	root := apiserver.TestingAdmittingRoot(finder, map[string]int{"Client.FullStatus": 0}, coretesting.ShortWait)
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.FitsTypeOf, blockingCaller{})
+}
@@ -6,6 +6,7 @@ package apiserver
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"io"
 	"net"
 	"net/http"
 	"strings"
@@ -17,11 +18,14 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/ratelimit"
 	"github.com/juju/utils"
 	"github.com/juju/utils/featureflag"
+	"github.com/juju/utils/set"
 	"golang.org/x/net/websocket"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/feature"
@@ -36,6 +40,21 @@ var logger = loggo.GetLogger("juju.apiserver")
 // accept
 const loginRateLimit = 10
 
+const (
+	// defaultAgentLoginRate is the default sustained rate, in logins
+	// per second, at which agent logins are accepted.
+	defaultAgentLoginRate = 20
+
+	// defaultAgentLoginBurst is the default number of agent logins
+	// that will be accepted in a burst before agent logins are
+	// throttled to the sustained rate.
+	defaultAgentLoginBurst = 100
+
+	// defaultCharmDownloadRate is the default combined rate, in bytes
+	// per second, at which charm archives are sent to agents.
+	defaultCharmDownloadRate = 10 * 1024 * 1024
+)
+
 // Server holds the server side of the API.
 type Server struct {
 	tomb              tomb.Tomb
@@ -46,8 +65,18 @@ type Server struct {
 	dataDir           string
 	logDir            string
 	limiter           utils.Limiter
+	loginBucket       *ratelimit.Bucket
+	charmBucket       *ratelimit.Bucket
 	validator         LoginValidator
+	authorizers       []CallAuthorizer
+	auditLog          *auditLog
+	admission         *admission
 	adminApiFactories map[int]adminApiFactory
+	stats             *common.ServerStats
+
+	// macaroonAuth authenticates users of the environment's external
+	// identity service. It is nil if none is configured.
+	macaroonAuth *authentication.ExternalMacaroonAuthenticator
 
 	mu          sync.Mutex // protects the fields that follow
 	environUUID string
@@ -67,6 +96,38 @@ type ServerConfig struct {
 	LogDir      string
 	Validator   LoginValidator
 	CertChanged chan params.StateServingInfo
+
+	// Authorizers holds any external authorization plug-ins to be
+	// consulted before each API call made by an authenticated entity.
+	Authorizers []CallAuthorizer
+
+	// AgentLoginRate and AgentLoginBurst configure the token bucket
+	// used to throttle agent logins, so that a mass restart of agents
+	// does not overwhelm mongo. AgentLoginRate is the sustained rate
+	// in logins per second; AgentLoginBurst is the bucket capacity.
+	// If zero, defaults are used.
+	AgentLoginRate  float64
+	AgentLoginBurst int64
+
+	// AuditLog enables the recording of every mutating API call made
+	// by a user in the environment's audit log. If AuditLogFile is
+	// also set, entries are appended to it as JSON, one per line.
+	AuditLog     bool
+	AuditLogFile io.Writer
+
+	// ConcurrencyLimits overrides the number of requests allowed to
+	// run at once for expensive operations, such as Client.FullStatus
+	// or charm uploads; a limit of zero or less removes the limit.
+	// Requests over a limit wait for up to AdmissionTimeout before
+	// being refused as busy. If zero, defaults are used.
+	ConcurrencyLimits map[string]int
+	AdmissionTimeout  time.Duration
+
+	// CharmDownloadRate limits the combined rate, in bytes per second,
+	// at which charm archives are sent to agents, so that many units
+	// deploying at once cannot saturate the server's uplink. If zero,
+	// a default is used; if negative, the rate is not limited.
+	CharmDownloadRate int64
 }
 
 // changeCertListener wraps a TLS net.Listener.
@@ -183,19 +244,31 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 		return nil, err
 	}
 	srv := &Server{
-		state:     s,
-		addr:      net.JoinHostPort("localhost", listeningPort),
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:       s,
+		addr:        net.JoinHostPort("localhost", listeningPort),
+		tag:         cfg.Tag,
+		dataDir:     cfg.DataDir,
+		logDir:      cfg.LogDir,
+		limiter:     utils.NewLimiter(loginRateLimit),
+		loginBucket: newLoginBucket(cfg.AgentLoginRate, cfg.AgentLoginBurst),
+		charmBucket: newCharmBucket(cfg.CharmDownloadRate),
+		validator:   cfg.Validator,
+		authorizers: cfg.Authorizers,
+		admission:   newAdmission(cfg.ConcurrencyLimits, cfg.AdmissionTimeout),
+		stats:       common.NewServerStats(),
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
 			2: newAdminApiV2,
 		},
 	}
+	if cfg.AuditLog {
+		srv.auditLog = newAuditLog(cfg.AuditLogFile)
+	}
+	srv.macaroonAuth, err = newExternalMacaroonAuthenticator(s)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot set up external identity authentication")
+	}
 	// TODO(rog) check that *srvRoot is a valid type for using
 	// as an RPC server.
 	tlsConfig := tls.Config{
@@ -207,6 +280,31 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 	return srv, nil
 }
 
+// newLoginBucket returns the token bucket used to throttle agent
+// logins, filling at the given rate up to the given capacity.
+func newLoginBucket(rate float64, capacity int64) *ratelimit.Bucket {
+	if rate <= 0 {
+		rate = defaultAgentLoginRate
+	}
+	if capacity <= 0 {
+		capacity = defaultAgentLoginBurst
+	}
+	return ratelimit.NewBucketWithRate(rate, capacity)
+}
+
+// newCharmBucket returns the token bucket shared by all charm archive
+// downloads, filling at the given rate in bytes per second, or nil if
+// the rate is negative.
+func newCharmBucket(rate int64) *ratelimit.Bucket {
+	if rate < 0 {
+		return nil
+	}
+	if rate == 0 {
+		rate = defaultCharmDownloadRate
+	}
+	return ratelimit.NewBucketWithRate(float64(rate), rate)
+}
+
 // Dead returns a channel that signals when the server has exited.
 func (srv *Server) Dead() <-chan struct{} {
 	return srv.tomb.Dead()
@@ -344,9 +442,12 @@ func (srv *Server) run(lis net.Listener) {
 		)
 	}
 	handleAll(mux, "/environment/:envuuid/charms",
-		&charmsHandler{
+		srv.admit("charms", &charmsHandler{
 			httpHandler: httpHandler{ssState: srv.state},
-			dataDir:     srv.dataDir},
+			dataDir:     srv.dataDir,
+			bucket:      srv.charmBucket},
+			"POST",
+		),
 	)
 	// TODO: We can switch from handleAll to mux.Post/Get/etc for entries
 	// where we only want to support specific request methods. However, our
@@ -363,11 +464,11 @@ func (srv *Server) run(lis net.Listener) {
 		}},
 	)
 	handleAll(mux, "/environment/:envuuid/backups",
-		&backupHandler{httpHandler{
+		srv.admit("backups", &backupHandler{httpHandler{
 			ssState:            srv.state,
 			strictValidation:   true,
 			stateServerEnvOnly: true,
-		}},
+		}}),
 	)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	handleAll(mux, "/environment/:envuuid/images/:kind/:series/:arch/:filename",
@@ -380,9 +481,12 @@ func (srv *Server) run(lis net.Listener) {
 			logDir:      srv.logDir},
 	)
 	handleAll(mux, "/charms",
-		&charmsHandler{
+		srv.admit("charms", &charmsHandler{
 			httpHandler: httpHandler{ssState: srv.state},
-			dataDir:     srv.dataDir},
+			dataDir:     srv.dataDir,
+			bucket:      srv.charmBucket},
+			"POST",
+		),
 	)
 	handleAll(mux, "/tools",
 		&toolsUploadHandler{toolsHandler{
@@ -399,6 +503,21 @@ func (srv *Server) run(lis net.Listener) {
 	http.Serve(lis, mux)
 }
 
+// admit returns an HTTP handler that serves requests with the given
+// handler once they are admitted for the named operation. If any
+// methods are given, only requests using them must be admitted.
+func (srv *Server) admit(name string, handler interface {
+	http.Handler
+	errorSender
+}, methods ...string) http.Handler {
+	return &admittingHandler{
+		handler:   handler,
+		admission: srv.admission,
+		name:      name,
+		methods:   set.NewStrings(methods...),
+	}
+}
+
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	reqNotifier := newRequestNotifier()
 	reqNotifier.join(req)
@@ -450,6 +569,8 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
 	} else {
+		srv.stats.Add(h.resources)
+		defer srv.stats.Remove(h.resources)
 		adminApis := make(map[int]interface{})
 		for apiVersion, factory := range srv.adminApiFactories {
 			adminApis[apiVersion] = factory(srv, h, reqNotifier)
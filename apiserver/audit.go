@@ -0,0 +1,210 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// unauditedFacades holds the facades whose calls are never recorded
+// in the audit log.
+var unauditedFacades = map[string]bool{
+	"AuditLog": true,
+	"Pinger":   true,
+}
+
+// isAuditedCall reports whether a call to the given facade method
+// may change the environment, and should therefore be audited.
+func isAuditedCall(facade, method string) bool {
+	return !unauditedFacades[facade] && !isReadOnlyCall(facade, method)
+}
+
+// auditLog records audit entries in state and, optionally, appends
+// them to a file as JSON, one entry per line.
+type auditLog struct {
+	mu   sync.Mutex
+	file io.Writer
+}
+
+// newAuditLog returns an auditLog that additionally writes to file,
+// if it is not nil.
+func newAuditLog(file io.Writer) *auditLog {
+	return &auditLog{file: file}
+}
+
+// record adds the given entry to the audit log for the environment
+// managed by st.
+func (l *auditLog) record(st *state.State, entry state.AuditEntry) error {
+	if err := st.AddAuditEntry(entry); err != nil {
+		return errors.Trace(err)
+	}
+	if l.file == nil {
+		return nil
+	}
+	data, err := json.Marshal(struct {
+		state.AuditEntry
+		EnvUUID string
+	}{entry, st.EnvironUUID()})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(append(data, '\n'))
+	return errors.Annotate(err, "cannot write audit log file")
+}
+
+// auditingRoot records the mutating calls dispatched by the wrapped
+// method finder in the audit log.
+type auditingRoot struct {
+	rpc.MethodFinder
+	st  *state.State
+	tag names.Tag
+	log *auditLog
+}
+
+// newAuditingRoot returns a new auditingRoot for calls made by the
+// entity with the given tag.
+func newAuditingRoot(finder rpc.MethodFinder, st *state.State, tag names.Tag, log *auditLog) *auditingRoot {
+	return &auditingRoot{
+		MethodFinder: finder,
+		st:           st,
+		tag:          tag,
+		log:          log,
+	}
+}
+
+// FindMethod returns a method caller that records the call in the
+// audit log once it has completed, if the call is to be audited.
+func (r *auditingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil || !isAuditedCall(rootName, methodName) {
+		return caller, err
+	}
+	return &auditingMethodCaller{
+		MethodCaller: caller,
+		root:         r,
+		facade:       rootName,
+		version:      version,
+		method:       methodName,
+	}, nil
+}
+
+// auditingMethodCaller wraps a MethodCaller, recording each call
+// made through it in the audit log.
+type auditingMethodCaller struct {
+	rpcreflect.MethodCaller
+	root    *auditingRoot
+	facade  string
+	version int
+	method  string
+}
+
+// Call is part of the rpcreflect.MethodCaller interface.
+func (c *auditingMethodCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	result, callErr := c.MethodCaller.Call(objId, arg)
+	entry := state.AuditEntry{
+		Time:    time.Now(),
+		Caller:  c.root.tag.String(),
+		Facade:  c.facade,
+		Version: c.version,
+		Method:  c.method,
+	}
+	secrets, err := secretAttrs(c.root.st)
+	if err != nil {
+		logger.Warningf("cannot get secret environment settings: %v", err)
+	}
+	entry.Args = auditValue(arg, secrets)
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	} else {
+		entry.Result = auditValue(result, secrets)
+	}
+	if err := c.root.log.record(c.root.st, entry); err != nil {
+		// Failing to audit a call that has already been made
+		// must not hide its result from the caller.
+		logger.Errorf("cannot audit call %s(%d).%s by %s: %v", c.facade, c.version, c.method, c.root.tag, err)
+	}
+	return result, callErr
+}
+
+// secretAttrs returns the names of the environment settings whose
+// values are secret: those named by the environment's provider, and
+// the CA private key.
+func secretAttrs(st *state.State) (map[string]bool, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	provider, err := environs.Provider(cfg.Type())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attrs, err := provider.SecretAttrs(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	secrets := map[string]bool{"ca-private-key": true}
+	for name := range attrs {
+		secrets[name] = true
+	}
+	return secrets, nil
+}
+
+// auditValue returns the JSON serialisation of v as recorded in the
+// audit log, with the values of the given secret settings, and of any
+// fields that look like secrets, redacted. It returns the empty string
+// if v cannot be serialised.
+func auditValue(v reflect.Value, secrets map[string]bool) string {
+	if !v.IsValid() || !v.CanInterface() {
+		return ""
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return ""
+	}
+	data, err = json.Marshal(redactSecrets(generic, secrets))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// redactSecrets replaces the values of any map keys in v that are
+// named in secrets, or that mention passwords or secrets.
+func redactSecrets(v interface{}, secrets map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			lower := strings.ToLower(key)
+			if secrets[key] || strings.Contains(lower, "password") || strings.Contains(lower, "secret") {
+				v[key] = "<redacted>"
+			} else {
+				v[key] = redactSecrets(value, secrets)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactSecrets(value, secrets)
+		}
+	}
+	return v
+}
@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+type auditSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) TestIsAuditedCall(c *gc.C) {
+	for i, test := range []struct {
+		facade  string
+		method  string
+		audited bool
+	}{
+		{"Client", "DestroyMachines", true},
+		{"Client", "AddMachinesV2", true},
+		{"Client", "FullStatus", false},
+		{"Client", "EnvironmentGet", false},
+		{"Client", "WatchAll", false},
+		{"Service", "InferRelationEndpoints", false},
+		{"AllWatcher", "Stop", false},
+		{"Pinger", "Ping", false},
+		{"AuditLog", "Entries", false},
+	} {
+		c.Logf("test %d: %s.%s", i, test.facade, test.method)
+		c.Check(apiserver.IsAuditedCall(test.facade, test.method), gc.Equals, test.audited)
+	}
+}
+
+func (s *auditSuite) TestRecordsMutatingCalls(c *gc.C) {
+	var file bytes.Buffer
+	bob := names.NewUserTag("bob")
+	finder := &fakeFinder{result: params.ErrorResult{}}
+	root := apiserver.TestingAuditingRoot(finder, s.State, bob, &file)
+
+	caller, err := root.FindMethod("UserManager", 0, "SetPassword")
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.EntityPasswords{Changes: []params.EntityPassword{{
+		Tag:      "user-bob",
+		Password: "sekrit",
+	}}}
+	_, err = caller.Call("", reflect.ValueOf(args))
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	entry := entries[0]
+	c.Check(entry.Caller, gc.Equals, bob.String())
+	c.Check(entry.Facade, gc.Equals, "UserManager")
+	c.Check(entry.Method, gc.Equals, "SetPassword")
+	c.Check(entry.Args, gc.Equals, `{"Changes":[{"Password":"<redacted>","Tag":"user-bob"}]}`)
+	c.Check(entry.Result, gc.Equals, `{"Error":null}`)
+	c.Check(entry.Error, gc.Equals, "")
+
+	var logged struct {
+		state.AuditEntry
+		EnvUUID string
+	}
+	err = json.Unmarshal(file.Bytes(), &logged)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(logged.EnvUUID, gc.Equals, s.State.EnvironUUID())
+	c.Check(logged.Method, gc.Equals, "SetPassword")
+	c.Check(logged.Args, gc.Equals, entry.Args)
+}
+
+func (s *auditSuite) TestRedactsSecretSettings(c *gc.C) {
+	finder := &fakeFinder{result: params.ErrorResult{}}
+	root := apiserver.TestingAuditingRoot(finder, s.State, names.NewUserTag("bob"), nil)
+
+	caller, err := root.FindMethod("Client", 0, "EnvironmentSet")
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.EnvironmentSet{Config: map[string]interface{}{
+		"ca-private-key": "private",
+		"secret":         "pork",
+		"default-series": "trusty",
+	}}
+	_, err = caller.Call("", reflect.ValueOf(args))
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Check(entries[0].Args, gc.Equals,
+		`{"Config":{"ca-private-key":"<redacted>","default-series":"trusty","secret":"<redacted>"}}`)
+}
+
+func (s *auditSuite) TestRecordsErrors(c *gc.C) {
+	finder := &fakeFinder{err: errors.New("boom")}
+	root := apiserver.TestingAuditingRoot(finder, s.State, names.NewUserTag("bob"), nil)
+
+	caller, err := root.FindMethod("Client", 0, "DestroyMachines")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.ValueOf(params.DestroyMachines{}))
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Check(entries[0].Error, gc.Equals, "boom")
+	c.Check(entries[0].Result, gc.Equals, "")
+}
+
+func (s *auditSuite) TestIgnoresReadOnlyCalls(c *gc.C) {
+	finder := &fakeFinder{result: params.ErrorResult{}}
+	root := apiserver.TestingAuditingRoot(finder, s.State, names.NewUserTag("bob"), nil)
+
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.ValueOf(params.StatusParams{}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+// fakeFinder is a rpc.MethodFinder whose methods all return
+// the same result or error.
+type fakeFinder struct {
+	result interface{}
+	err    error
+}
+
+func (f *fakeFinder) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	return fakeCaller{f}, nil
+}
+
+type fakeCaller struct {
+	finder *fakeFinder
+}
+
+func (c fakeCaller) ParamsType() reflect.Type {
+	return nil
+}
+
+func (c fakeCaller) ResultType() reflect.Type {
+	return reflect.TypeOf(c.finder.result)
+}
+
+func (c fakeCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	if c.finder.err != nil {
+		return reflect.Value{}, c.finder.err
+	}
+	return reflect.ValueOf(c.finder.result), nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog implements the API facade used to query the record
+// of mutating API calls kept by the API server.
+package auditlog
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("AuditLog", 1, NewAPI)
+}
+
+// AuditLog defines the methods on the audit log API end point.
+type AuditLog interface {
+	// Entries returns the audit log entries matching the filter,
+	// most recent first.
+	Entries(params.AuditLogFilter) (params.AuditLogResults, error)
+}
+
+// API implements AuditLog and is the concrete implementation
+// of the api end point.
+type API struct {
+	st *state.State
+}
+
+var _ AuditLog = (*API)(nil)
+
+// NewAPI returns a new audit log API facade. Only the owner of the
+// environment may read its audit log.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return nil, err
+	}
+	userTag, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok || userTag != env.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st}, nil
+}
+
+// Entries implements AuditLog.Entries().
+func (a *API) Entries(args params.AuditLogFilter) (params.AuditLogResults, error) {
+	filter := state.AuditFilter{
+		Caller: args.Caller,
+		Facade: args.Facade,
+		Limit:  args.Limit,
+	}
+	if args.Since != nil {
+		filter.Since = *args.Since
+	}
+	entries, err := a.st.AuditEntries(filter)
+	if err != nil {
+		return params.AuditLogResults{}, common.ServerError(err)
+	}
+	results := params.AuditLogResults{
+		Entries: make([]params.AuditLogEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		results.Entries[i] = params.AuditLogEntry{
+			Time:    entry.Time,
+			Caller:  entry.Caller,
+			Facade:  entry.Facade,
+			Version: entry.Version,
+			Method:  entry.Method,
+			Args:    entry.Args,
+			Result:  entry.Result,
+			Error:   entry.Error,
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/auditlog"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type auditLogSuite struct {
+	jujutesting.JujuConnSuite
+	api *auditlog.API
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *auditLogSuite) TestNewAPIRefusesAgents(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *auditLogSuite) TestNewAPIRefusesOtherUsers(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	_, err := auditlog.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *auditLogSuite) TestEntries(c *gc.C) {
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i, method := range []string{"AddMachinesV2", "DestroyMachines"} {
+		err := s.State.AddAuditEntry(state.AuditEntry{
+			Time:   t0.Add(time.Duration(i) * time.Second),
+			Caller: "user-admin@local",
+			Facade: "Client",
+			Method: method,
+			Args:   "{}",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	results, err := s.api.Entries(params.AuditLogFilter{Facade: "Client"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.AuditLogResults{
+		Entries: []params.AuditLogEntry{{
+			Time:   t0.Add(time.Second),
+			Caller: "user-admin@local",
+			Facade: "Client",
+			Method: "DestroyMachines",
+			Args:   "{}",
+		}, {
+			Time:   t0,
+			Caller: "user-admin@local",
+			Facade: "Client",
+			Method: "AddMachinesV2",
+			Args:   "{}",
+		}},
+	})
+
+	results, err = s.api.Entries(params.AuditLogFilter{Since: &t0})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Entries, gc.HasLen, 1)
+	c.Assert(results.Entries[0].Method, gc.Equals, "DestroyMachines")
+
+	results, err = s.api.Entries(params.AuditLogFilter{Caller: "user-bob@local"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Entries, gc.HasLen, 0)
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+	"gopkg.in/macaroon-bakery.v0/bakery/checkers"
+	"gopkg.in/macaroon.v1"
+)
+
+const (
+	// usernameKey is the key of the declared caveat holding the name
+	// of the user authenticated by the identity service.
+	usernameKey = "username"
+
+	// externalUserDomain is the domain given to users authenticated
+	// by the identity service.
+	externalUserDomain = "external"
+
+	// macaroonLifetime is how long a macaroon minted for login
+	// remains valid.
+	macaroonLifetime = 24 * time.Hour
+)
+
+// DischargeRequiredError is returned by ExternalMacaroonAuthenticator
+// when the user must obtain a discharge for Macaroon from the identity
+// service before logging in.
+type DischargeRequiredError struct {
+	Cause    error
+	Macaroon *macaroon.Macaroon
+}
+
+// Error implements error.
+func (e *DischargeRequiredError) Error() string {
+	return "discharge required: " + e.Cause.Error()
+}
+
+// ExternalMacaroonAuthenticator authenticates users with macaroons
+// discharged by an external identity service.
+type ExternalMacaroonAuthenticator struct {
+	// Service is used to mint and check the login macaroons.
+	Service *bakery.Service
+
+	// IdentityLocation holds the URL of the identity service, to
+	// which the is-authenticated-user third party caveat is
+	// addressed.
+	IdentityLocation string
+}
+
+// Authenticate checks the given macaroons and returns the tag of the
+// user they were issued to. If there are no valid macaroons, it returns
+// a *DischargeRequiredError holding a new macaroon for the user to
+// discharge.
+func (a *ExternalMacaroonAuthenticator) Authenticate(macaroons []macaroon.Slice) (names.UserTag, error) {
+	declared, err := a.Service.CheckAny(macaroons, nil, checkers.New(checkers.TimeBefore))
+	if err != nil {
+		return names.UserTag{}, a.newDischargeRequiredError(err)
+	}
+	username := declared[usernameKey]
+	if !names.IsValidUserName(username) {
+		return names.UserTag{}, errors.Errorf("identity service declared invalid user name %q", username)
+	}
+	return names.NewUserTag(username + "@" + externalUserDomain), nil
+}
+
+func (a *ExternalMacaroonAuthenticator) newDischargeRequiredError(cause error) error {
+	m, err := a.Service.NewMacaroon("", nil, []checkers.Caveat{
+		checkers.NeedDeclaredCaveat(checkers.Caveat{
+			Location:  a.IdentityLocation,
+			Condition: "is-authenticated-user",
+		}, usernameKey),
+		checkers.TimeBeforeCaveat(time.Now().Add(macaroonLifetime)),
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot create login macaroon")
+	}
+	return &DischargeRequiredError{
+		Cause:    cause,
+		Macaroon: m,
+	}
+}
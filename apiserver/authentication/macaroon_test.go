@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+	"gopkg.in/macaroon-bakery.v0/bakery/checkers"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const identityLocation = "https://identity.example.com"
+
+type macaroonAuthenticatorSuite struct {
+	coretesting.BaseSuite
+	identity      *bakery.Service
+	authenticator *authentication.ExternalMacaroonAuthenticator
+}
+
+var _ = gc.Suite(&macaroonAuthenticatorSuite{})
+
+func (s *macaroonAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	identity, err := bakery.NewService(bakery.NewServiceParams{
+		Location: identityLocation,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.identity = identity
+
+	svc, err := bakery.NewService(bakery.NewServiceParams{
+		Location: "juju environment",
+		Locator: bakery.PublicKeyLocatorMap{
+			identityLocation: identity.PublicKey(),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.authenticator = &authentication.ExternalMacaroonAuthenticator{
+		Service:          svc,
+		IdentityLocation: identityLocation,
+	}
+}
+
+// discharge discharges the given macaroon as the identity service
+// would for the given user.
+func (s *macaroonAuthenticatorSuite) discharge(c *gc.C, m *macaroon.Macaroon, username string) macaroon.Slice {
+	ms, err := bakery.DischargeAll(m, func(_ string, cav macaroon.Caveat) (*macaroon.Macaroon, error) {
+		c.Check(cav.Location, gc.Equals, identityLocation)
+		checker := bakery.ThirdPartyCheckerFunc(func(_, cond string) ([]checkers.Caveat, error) {
+			c.Check(cond, gc.Equals, "need-declared username is-authenticated-user")
+			return []checkers.Caveat{checkers.DeclaredCaveat("username", username)}, nil
+		})
+		return s.identity.Discharge(checker, cav.Id)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return ms
+}
+
+func (s *macaroonAuthenticatorSuite) dischargeRequired(c *gc.C, macaroons []macaroon.Slice) *macaroon.Macaroon {
+	_, err := s.authenticator.Authenticate(macaroons)
+	c.Assert(err, gc.FitsTypeOf, (*authentication.DischargeRequiredError)(nil))
+	m := err.(*authentication.DischargeRequiredError).Macaroon
+	c.Assert(m, gc.NotNil)
+	return m
+}
+
+func (s *macaroonAuthenticatorSuite) TestNoMacaroonsRequiresDischarge(c *gc.C) {
+	s.dischargeRequired(c, nil)
+}
+
+func (s *macaroonAuthenticatorSuite) TestDischargedMacaroonAuthenticates(c *gc.C) {
+	m := s.dischargeRequired(c, nil)
+	ms := s.discharge(c, m, "bob")
+
+	tag, err := s.authenticator.Authenticate([]macaroon.Slice{ms})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag.Username(), gc.Equals, "bob@external")
+}
+
+func (s *macaroonAuthenticatorSuite) TestUndischargedMacaroonRequiresDischarge(c *gc.C) {
+	m := s.dischargeRequired(c, nil)
+	s.dischargeRequired(c, []macaroon.Slice{{m}})
+}
+
+func (s *macaroonAuthenticatorSuite) TestInvalidUserName(c *gc.C) {
+	m := s.dischargeRequired(c, nil)
+	ms := s.discharge(c, m, "not a user")
+
+	_, err := s.authenticator.Authenticate([]macaroon.Slice{ms})
+	c.Assert(err, gc.ErrorMatches, `identity service declared invalid user name "not a user"`)
+}
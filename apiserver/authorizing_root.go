@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// CallInfo describes an API call made by an authenticated entity, as
+// presented to a CallAuthorizer.
+type CallInfo struct {
+	// Tag identifies the authenticated entity making the call.
+	Tag names.Tag
+
+	// EnvUUID is the UUID of the environment the connection was made
+	// to, or empty if the connection was made to the server root.
+	EnvUUID string
+
+	// Facade, Version and Method identify the facade method being
+	// called.
+	Facade  string
+	Version int
+	Method  string
+}
+
+// CallAuthorizer is implemented by external authorization plug-ins.
+// Authorizers are consulted after a connection has been authenticated
+// and before any facade method is dispatched; they allow sites to
+// enforce their own policies without modifying the facades themselves.
+type CallAuthorizer interface {
+	// AuthorizeCall returns an error if the call described
+	// must not be made. A nil error allows the call to proceed,
+	// subject to the checks made by the facade itself.
+	AuthorizeCall(call CallInfo) error
+}
+
+// CallAuthorizerFunc is a function type that implements CallAuthorizer.
+type CallAuthorizerFunc func(call CallInfo) error
+
+// AuthorizeCall is part of the CallAuthorizer interface.
+func (f CallAuthorizerFunc) AuthorizeCall(call CallInfo) error {
+	return f(call)
+}
+
+// MethodPolicy is a CallAuthorizer that restricts individual facade
+// methods to an explicit set of entities. Keys are of the form
+// "Facade.Method", or "Facade.*" to restrict every method on a facade;
+// methods that match no key are not restricted by the policy. For
+// example, the following allows only the admin user to destroy
+// machines:
+//
+//	MethodPolicy{
+//		"Client.DestroyMachines": {names.NewUserTag("admin")},
+//	}
+type MethodPolicy map[string][]names.Tag
+
+// AuthorizeCall is part of the CallAuthorizer interface.
+func (p MethodPolicy) AuthorizeCall(call CallInfo) error {
+	allowed, ok := p[call.Facade+"."+call.Method]
+	if !ok {
+		allowed, ok = p[call.Facade+".*"]
+	}
+	if !ok {
+		return nil
+	}
+	for _, tag := range allowed {
+		if sameEntity(tag, call.Tag) {
+			return nil
+		}
+	}
+	return common.ErrPerm
+}
+
+// DenyList is a CallAuthorizer that refuses all API calls made by
+// the listed entities.
+type DenyList []names.Tag
+
+// AuthorizeCall is part of the CallAuthorizer interface.
+func (d DenyList) AuthorizeCall(call CallInfo) error {
+	for _, tag := range d {
+		if sameEntity(tag, call.Tag) {
+			return common.ErrPerm
+		}
+	}
+	return nil
+}
+
+// sameEntity returns whether the tags identify the same entity. User
+// tags are compared by their canonical user names, so that a local
+// user configured as "user-admin" matches the logged in admin@local.
+func sameEntity(a, b names.Tag) bool {
+	if userA, ok := a.(names.UserTag); ok {
+		userB, ok := b.(names.UserTag)
+		return ok && userA.Username() == userB.Username()
+	}
+	return a == b
+}
+
+// authorizingRoot consults a set of CallAuthorizers before allowing
+// calls to be dispatched by the wrapped method finder.
+type authorizingRoot struct {
+	rpc.MethodFinder
+	tag         names.Tag
+	envUUID     string
+	authorizers []CallAuthorizer
+}
+
+// newAuthorizingRoot returns a new authorizingRoot for calls made by
+// the entity with the given tag.
+func newAuthorizingRoot(
+	finder rpc.MethodFinder,
+	tag names.Tag,
+	envUUID string,
+	authorizers []CallAuthorizer,
+) *authorizingRoot {
+	return &authorizingRoot{
+		MethodFinder: finder,
+		tag:          tag,
+		envUUID:      envUUID,
+		authorizers:  authorizers,
+	}
+}
+
+// FindMethod returns the error from the first authorizer that refuses
+// the call, or the wrapped finder's method caller if all of them allow it.
+func (r *authorizingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	// Look the method up first, so that calls to nonexistent methods
+	// report the usual not implemented errors.
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	call := CallInfo{
+		Tag:     r.tag,
+		EnvUUID: r.envUUID,
+		Facade:  rootName,
+		Version: version,
+		Method:  methodName,
+	}
+	for _, authorizer := range r.authorizers {
+		if err := authorizer.AuthorizeCall(call); err != nil {
+			logger.Debugf("call %s(%d).%s by %s refused: %v", rootName, version, methodName, r.tag, err)
+			return nil, errors.Trace(err)
+		}
+	}
+	return caller, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/testing"
+)
+
+type authorizingRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&authorizingRootSuite{})
+
+var (
+	adminTag = names.NewUserTag("admin")
+	bobTag   = names.NewUserTag("bob")
+)
+
+func (s *authorizingRootSuite) TestMethodPolicy(c *gc.C) {
+	policy := apiserver.MethodPolicy{
+		"Client.DestroyMachines": {adminTag},
+	}
+	root := apiserver.TestingAuthorizingApiHandler(nil, bobTag, policy)
+	caller, err := root.FindMethod("Client", 0, "DestroyMachines")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(caller, gc.IsNil)
+
+	// Methods not covered by the policy are unrestricted.
+	caller, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+
+	root = apiserver.TestingAuthorizingApiHandler(nil, adminTag, policy)
+	caller, err = root.FindMethod("Client", 0, "DestroyMachines")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (s *authorizingRootSuite) TestMethodPolicyWholeFacade(c *gc.C) {
+	policy := apiserver.MethodPolicy{
+		"Client.*": {adminTag},
+	}
+	root := apiserver.TestingAuthorizingApiHandler(nil, bobTag, policy)
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *authorizingRootSuite) TestDenyList(c *gc.C) {
+	root := apiserver.TestingAuthorizingApiHandler(nil, bobTag, apiserver.DenyList{bobTag})
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+
+	root = apiserver.TestingAuthorizingApiHandler(nil, adminTag, apiserver.DenyList{bobTag})
+	_, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *authorizingRootSuite) TestUserTagsMatchWithDomain(c *gc.C) {
+	localBob := names.NewLocalUserTag("bob")
+	root := apiserver.TestingAuthorizingApiHandler(nil, localBob, apiserver.DenyList{bobTag})
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+
+	policy := apiserver.MethodPolicy{
+		"Client.DestroyMachines": {bobTag},
+	}
+	root = apiserver.TestingAuthorizingApiHandler(nil, localBob, policy)
+	_, err = root.FindMethod("Client", 0, "DestroyMachines")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Users in other domains are different users.
+	root = apiserver.TestingAuthorizingApiHandler(nil, names.NewUserTag("bob@external"), apiserver.DenyList{bobTag})
+	_, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *authorizingRootSuite) TestAuthorizersConsultedInOrder(c *gc.C) {
+	var calls []apiserver.CallInfo
+	record := apiserver.CallAuthorizerFunc(func(call apiserver.CallInfo) error {
+		calls = append(calls, call)
+		return nil
+	})
+	refuse := apiserver.CallAuthorizerFunc(func(call apiserver.CallInfo) error {
+		return errors.New("change freeze in effect")
+	})
+	root := apiserver.TestingAuthorizingApiHandler(nil, bobTag, record, refuse, record)
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.ErrorMatches, "change freeze in effect")
+	c.Assert(calls, jc.DeepEquals, []apiserver.CallInfo{{
+		Tag:     bobTag,
+		Facade:  "Client",
+		Version: 0,
+		Method:  "FullStatus",
+	}})
+}
+
+func (s *authorizingRootSuite) TestNonExistentMethodNotAuthorized(c *gc.C) {
+	refuse := apiserver.CallAuthorizerFunc(func(call apiserver.CallInfo) error {
+		c.Fatalf("authorizer should not be called")
+		return nil
+	})
+	root := apiserver.TestingAuthorizingApiHandler(nil, bobTag, refuse)
+	_, err := root.FindMethod("Client", 0, "NoSuchMethod")
+	c.Assert(err, gc.ErrorMatches, `no such request - method Client\(0\).NoSuchMethod is not implemented`)
+}
@@ -25,6 +25,7 @@ var logger = loggo.GetLogger("juju.apiserver.backups")
 type API struct {
 	st    *state.State
 	paths *backups.Paths
+	check *common.BlockChecker
 
 	// machineID is the ID of the machine where the API server is running.
 	machineID string
@@ -58,6 +59,7 @@ func NewAPI(st *state.State, resources *common.Resources, authorizer common.Auth
 	b := API{
 		st:        st,
 		paths:     &paths,
+		check:     common.NewBlockChecker(st),
 		machineID: machineID,
 	}
 	return &b, nil
@@ -40,7 +40,17 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 	}
 	meta.Notes = args.Notes
 
-	err = backupsMethods.Create(meta, a.paths, dbInfo)
+	// The keys sealing the agent config secrets are stored in the
+	// archive sealed with the CA private key, which restore obtains
+	// from the client's environment configuration.
+	servingInfo, err := a.st.StateServingInfo()
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	paths := *a.paths
+	paths.KeyPassphrase = servingInfo.CAPrivateKey
+
+	err = backupsMethods.Create(meta, &paths, dbInfo)
 	if err != nil {
 		return p, errors.Trace(err)
 	}
@@ -16,13 +16,16 @@ func (s *backupsSuite) TestCreateOkay(c *gc.C) {
 	s.PatchValue(backups.WaitUntilReady,
 		func(*mgo.Session, int) error { return nil },
 	)
-	s.setBackups(c, s.meta, "")
+	fake := s.setBackups(c, s.meta, "")
 	var args params.BackupsCreateArgs
 	result, err := s.api.Create(args)
 	c.Assert(err, jc.ErrorIsNil)
 	expected := backups.ResultFromMetadata(s.meta)
 
 	c.Check(result, gc.DeepEquals, expected)
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fake.PathsArg.KeyPassphrase, gc.Equals, info.CAPrivateKey)
 }
 
 func (s *backupsSuite) TestCreateNotes(c *gc.C) {
@@ -9,7 +9,11 @@ import (
 	"github.com/juju/juju/apiserver/params"
 )
 
+// Remove deletes the backup with the given ID from storage.
 func (a *API) Remove(args params.BackupsRemoveArgs) error {
+	if err := a.check.RemoveAllowed(); err != nil {
+		return errors.Trace(err)
+	}
 	backups, closer := newBackups(a.st)
 	defer closer.Close()
 
@@ -8,6 +8,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
 )
 
 func (s *backupsSuite) TestRemoveOkay(c *gc.C) {
@@ -28,3 +29,16 @@ func (s *backupsSuite) TestRemoveError(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func (s *backupsSuite) TestRemoveBlocked(c *gc.C) {
+	fake := s.setBackups(c, nil, "")
+	err := s.State.SwitchBlockOn(state.RemoveBlock, "TestRemoveBlocked")
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.BackupsRemoveArgs{
+		ID: "some-id",
+	}
+	err = s.api.Remove(args)
+
+	c.Check(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	c.Check(fake.Calls, gc.HasLen, 0)
+}
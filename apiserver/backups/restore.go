@@ -55,6 +55,13 @@ func (a *API) Restore(p params.RestoreArgs) error {
 		return errors.Annotate(err, "cannot obtain instance id for machine to be restored")
 	}
 
+	// The environment's CA private key unseals the agent config
+	// secrets keys in the backup; see Create.
+	servingInfo, err := a.st.StateServingInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	logger.Infof("beginning server side restore of backup %q", p.BackupId)
 	// Restore
 	restoreArgs := backups.RestoreArgs{
@@ -63,6 +70,7 @@ func (a *API) Restore(p params.RestoreArgs) error {
 		NewInstId:      instanceId,
 		NewInstTag:     machine.Tag(),
 		NewInstSeries:  machine.Series(),
+		KeyPassphrase:  servingInfo.CAPrivateKey,
 	}
 	if err := backup.Restore(p.BackupId, restoreArgs); err != nil {
 		return errors.Annotate(err, "restore failed")
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/ratelimit"
 	ziputil "github.com/juju/utils/zip"
 	"gopkg.in/juju/charm.v5"
 
@@ -36,6 +37,10 @@ import (
 type charmsHandler struct {
 	httpHandler
 	dataDir string
+
+	// bucket, if not nil, limits the rate at which charm
+	// archives are sent.
+	bucket *ratelimit.Bucket
 }
 
 // bundleContentSenderFunc functions are responsible for sending a
@@ -180,9 +185,23 @@ func (h *charmsHandler) archiveEntrySender(filePath string) bundleContentSenderF
 // archiveSender is a bundleContentSenderFunc which is responsible for sending
 // the contents of the given charm bundle.
 func (h *charmsHandler) archiveSender(w http.ResponseWriter, r *http.Request, bundle *charm.CharmArchive) {
+	if h.bucket != nil {
+		w = &rateLimitedResponseWriter{w, ratelimit.Writer(w, h.bucket)}
+	}
 	http.ServeFile(w, r, bundle.Path)
 }
 
+// rateLimitedResponseWriter is an http.ResponseWriter that writes
+// the response body through a rate-limited writer.
+type rateLimitedResponseWriter struct {
+	http.ResponseWriter
+	body io.Writer
+}
+
+func (w *rateLimitedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
 // sendError sends a JSON-encoded error response.
 func (h *charmsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
 	if err := h.sendJSON(w, statusCode, &params.CharmsResponse{Error: message}); err != nil {
@@ -409,7 +428,8 @@ func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, st
 }
 
 // downloadCharm downloads the given charm name from the provider storage and
-// saves the corresponding zip archive to the given charmArchivePath.
+// saves the corresponding zip archive to the given charmArchivePath. The
+// archive is verified against the charm's recorded SHA256 hash.
 func (h *charmsHandler) downloadCharm(st *state.State, curl *charm.URL, charmArchivePath string) error {
 	storage := storage.NewStorage(st.EnvironUUID(), st.MongoSession())
 	ch, err := st.Charm(curl)
@@ -439,10 +459,20 @@ func (h *charmsHandler) downloadCharm(st *state.State, curl *charm.URL, charmArc
 	}
 	defer reader.Close()
 
-	if _, err = io.Copy(tempCharmArchive, reader); err != nil {
+	// Verify the archive against the SHA256 hash recorded when the
+	// charm was added, so that a corrupted blob is never cached.
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tempCharmArchive, hash), reader); err != nil {
 		defer cleanupFile(tempCharmArchive)
 		return errors.Annotate(err, "error processing charm archive download")
 	}
+	if sha := hex.EncodeToString(hash.Sum(nil)); sha != ch.BundleSha256() {
+		defer cleanupFile(tempCharmArchive)
+		return errors.Errorf(
+			"charm archive SHA256 mismatch: expected %s, got %s",
+			ch.BundleSha256(), sha,
+		)
+	}
 	tempCharmArchive.Close()
 	if err = os.Rename(tempCharmArchive.Name(), charmArchivePath); err != nil {
 		defer cleanupFile(tempCharmArchive)
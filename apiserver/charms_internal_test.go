@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"net/http/httptest"
+
+	"github.com/juju/ratelimit"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type charmsInternalSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&charmsInternalSuite{})
+
+func (s *charmsInternalSuite) TestNewCharmBucket(c *gc.C) {
+	c.Assert(newCharmBucket(-1), gc.IsNil)
+	c.Assert(newCharmBucket(0).Available(), gc.Equals, int64(defaultCharmDownloadRate))
+	c.Assert(newCharmBucket(1000).Available(), gc.Equals, int64(1000))
+}
+
+func (s *charmsInternalSuite) TestRateLimitedResponseWriter(c *gc.C) {
+	bucket := ratelimit.NewBucketWithRate(1, 10)
+	recorder := httptest.NewRecorder()
+	w := &rateLimitedResponseWriter{recorder, ratelimit.Writer(recorder, bucket)}
+	w.Header().Set("Content-Type", "application/zip")
+	_, err := w.Write([]byte("0123456789"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(recorder.Body.String(), gc.Equals, "0123456789")
+	c.Assert(recorder.Header().Get("Content-Type"), gc.Equals, "application/zip")
+	c.Assert(bucket.Available(), gc.Equals, int64(0))
+}
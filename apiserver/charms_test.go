@@ -363,6 +363,35 @@ func (s *charmsSuite) TestGetReturnsFileContents(c *gc.C) {
 	}
 }
 
+func (s *charmsSuite) TestGetRejectsCorruptedArchive(c *gc.C) {
+	// Add the dummy charm.
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	_, err := s.uploadRequest(
+		c, s.charmsURI(c, "?series=quantal"), true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Replace the stored archive with different content.
+	sch, err := s.State.Charm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.ErrorIsNil)
+	storage := storage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
+	err = storage.Remove(sch.StoragePath())
+	c.Assert(err, jc.ErrorIsNil)
+	corrupted := []byte("not the charm you are looking for")
+	err = storage.Put(sch.StoragePath(), bytes.NewReader(corrupted), int64(len(corrupted)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	uri := s.charmsURI(c, "?url=local:quantal/dummy-1&file=revision")
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest,
+		"unable to retrieve and save the charm: charm archive SHA256 mismatch: expected .*, got .*")
+
+	// Nothing is left in the cache.
+	cached := filepath.Join(s.DataDir(), "charm-get-cache", charm.Quote("local:quantal/dummy-1")+".zip")
+	_, err = os.Stat(cached)
+	c.Assert(os.IsNotExist(err), jc.IsTrue)
+}
+
 func (s *charmsSuite) TestGetStarReturnsArchiveBytes(c *gc.C) {
 	// Add the dummy charm.
 	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
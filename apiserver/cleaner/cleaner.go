@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cleaner implements the API facade used by the cleaner worker
+// to run the cleanups scheduled in state.
+package cleaner
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Cleaner", 1, NewCleanerAPI)
+}
+
+// stateInterface holds the state methods used by the Cleaner facade.
+type stateInterface interface {
+	Cleanup() error
+	WatchCleanups() state.NotifyWatcher
+}
+
+var getState = func(st *state.State) stateInterface {
+	return st
+}
+
+// CleanerAPI implements the API used by the cleaner worker.
+type CleanerAPI struct {
+	st        stateInterface
+	resources *common.Resources
+}
+
+// NewCleanerAPI creates a new instance of the Cleaner API.
+func NewCleanerAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*CleanerAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		return nil, common.ErrPerm
+	}
+	return &CleanerAPI{
+		st:        getState(st),
+		resources: resources,
+	}, nil
+}
+
+// Cleanup runs all the cleanups scheduled in state.
+func (api *CleanerAPI) Cleanup() error {
+	return api.st.Cleanup()
+}
+
+// WatchCleanups watches for cleanups being scheduled in state.
+func (api *CleanerAPI) WatchCleanups() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	watch := api.st.WatchCleanups()
+	// Consume the initial event. Technically, API calls to Watch
+	// 'transmit' the initial event in the Watch response. But
+	// NotifyWatchers have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		result.Error = common.ServerError(watcher.EnsureErr(watch))
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleaner_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/cleaner"
+	"github.com/juju/juju/apiserver/common"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CleanerSuite struct {
+	coretesting.BaseSuite
+
+	st         *mockState
+	api        *cleaner.CleanerAPI
+	authoriser apiservertesting.FakeAuthorizer
+	resources  *common.Resources
+}
+
+var _ = gc.Suite(&CleanerSuite{})
+
+func (s *CleanerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	}
+	s.st = newMockState()
+	cleaner.PatchState(s, s.st)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+
+	var err error
+	s.api, err = cleaner.NewCleanerAPI(nil, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CleanerSuite) TestNewCleanerAPIRequiresEnvironManager(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.EnvironManager = false
+	api, err := cleaner.NewCleanerAPI(nil, nil, anAuthoriser)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *CleanerSuite) TestWatchCleanups(c *gc.C) {
+	result, err := s.api.WatchCleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+}
+
+func (s *CleanerSuite) TestWatchCleanupsFailure(c *gc.C) {
+	s.st.watcher = &mockNotifyWatcher{
+		changes: make(chan struct{}),
+		err:     errors.New("boom"),
+	}
+	close(s.st.watcher.changes)
+
+	result, err := s.api.WatchCleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+}
+
+func (s *CleanerSuite) TestCleanup(c *gc.C) {
+	err := s.api.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.cleanups, gc.Equals, 1)
+}
+
+func (s *CleanerSuite) TestCleanupFailure(c *gc.C) {
+	s.st.cleanupErr = errors.New("boom")
+	err := s.api.Cleanup()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockState struct {
+	watcher    *mockNotifyWatcher
+	cleanups   int
+	cleanupErr error
+}
+
+func newMockState() *mockState {
+	w := &mockNotifyWatcher{changes: make(chan struct{}, 1)}
+	w.changes <- struct{}{}
+	return &mockState{watcher: w}
+}
+
+func (st *mockState) Cleanup() error {
+	st.cleanups++
+	return st.cleanupErr
+}
+
+func (st *mockState) WatchCleanups() state.NotifyWatcher {
+	return st.watcher
+}
+
+type mockNotifyWatcher struct {
+	state.NotifyWatcher
+	changes chan struct{}
+	err     error
+}
+
+func (m *mockNotifyWatcher) Stop() error {
+	return nil
+}
+
+func (m *mockNotifyWatcher) Err() error {
+	return m.err
+}
+
+func (m *mockNotifyWatcher) Changes() <-chan struct{} {
+	return m.changes
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/highavailability"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/service"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
@@ -28,6 +29,9 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 0, NewClient)
+	// Version 1 differs only in that FindTools honours
+	// FindToolsParams.AgentStream, which version 0 servers ignore.
+	common.RegisterStandardFacade("Client", 1, NewClient)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -221,6 +225,33 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 	return results, fmt.Errorf("unknown unit or machine %q", p.Target)
 }
 
+// SSHHostKeys returns the public SSH host keys reported by the machine
+// agent of the specified machine, or of the machine hosting the
+// specified unit.
+func (c *Client) SSHHostKeys(p params.SSHHostKeys) (results params.SSHHostKeysResults, err error) {
+	var machineId string
+	switch {
+	case names.IsValidMachine(p.Target):
+		machineId = p.Target
+	case names.IsValidUnit(p.Target):
+		unit, err := c.api.state.Unit(p.Target)
+		if err != nil {
+			return results, err
+		}
+		machineId, err = unit.AssignedMachineId()
+		if err != nil {
+			return results, err
+		}
+	default:
+		return results, fmt.Errorf("unknown unit or machine %q", p.Target)
+	}
+	machine, err := c.api.state.Machine(machineId)
+	if err != nil {
+		return results, err
+	}
+	return params.SSHHostKeysResults{PublicKeys: machine.SSHHostKeys()}, nil
+}
+
 // ServiceExpose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
@@ -410,7 +441,8 @@ func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.
 	if args.NumUnits < 1 {
 		return nil, fmt.Errorf("must add at least one unit")
 	}
-	if args.NumUnits > 1 && args.ToMachineSpec != "" {
+	_, isLabel := instance.ParseLabelDirective(args.ToMachineSpec)
+	if args.NumUnits > 1 && args.ToMachineSpec != "" && !isLabel {
 		return nil, fmt.Errorf("cannot use NumUnits with ToMachineSpec")
 	}
 
@@ -582,17 +614,78 @@ func (c *Client) AddMachinesV2(args params.AddMachines) (params.AddMachinesResul
 	return results, nil
 }
 
+// AddIdenticalMachines adds args.NumMachines new machines, all
+// configured according to args.MachineParams, in a single
+// transaction. Either all of the machines are added or none are.
+func (c *Client) AddIdenticalMachines(args params.AddIdenticalMachines) (params.AddMachinesResults, error) {
+	var results params.AddMachinesResults
+	if err := c.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	if args.NumMachines < 1 || args.NumMachines > params.MaxIdenticalMachines {
+		return results, errors.NotValidf("number of machines %d", args.NumMachines)
+	}
+	p, err := c.resolveMachineParams(args.MachineParams)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	if p.ContainerType != "" {
+		return results, errors.NotSupportedf("adding containers in bulk")
+	}
+	if p.InstanceId != "" {
+		return results, errors.NotValidf("instance id for identical machines")
+	}
+	template, err := c.machineTemplate(p)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	templates := make([]state.MachineTemplate, args.NumMachines)
+	for i := range templates {
+		templates[i] = template
+	}
+	machines, err := c.api.state.AddMachines(templates...)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	results.Machines = make([]params.AddMachinesResult, len(machines))
+	for i, m := range machines {
+		results.Machines[i].Machine = m.Id()
+	}
+	return results, nil
+}
+
 // InjectMachines injects a machine into state with provisioned status.
 func (c *Client) InjectMachines(args params.AddMachines) (params.AddMachinesResults, error) {
 	return c.AddMachines(args)
 }
 
 func (c *Client) addOneMachine(p params.AddMachineParams) (*state.Machine, error) {
+	p, err := c.resolveMachineParams(p)
+	if err != nil {
+		return nil, err
+	}
+	template, err := c.machineTemplate(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.ContainerType == "" {
+		return c.api.state.AddOneMachine(template)
+	}
+	if p.ParentId != "" {
+		return c.api.state.AddMachineInsideMachine(template, p.ParentId, p.ContainerType)
+	}
+	return c.api.state.AddMachineInsideNewMachine(template, template, p.ContainerType)
+}
+
+// resolveMachineParams validates the given machine parameters and
+// returns them with container placement directives, labels and the
+// default series resolved.
+func (c *Client) resolveMachineParams(p params.AddMachineParams) (params.AddMachineParams, error) {
 	if p.ParentId != "" && p.ContainerType == "" {
-		return nil, fmt.Errorf("parent machine specified without container type")
+		return p, fmt.Errorf("parent machine specified without container type")
 	}
 	if p.ContainerType != "" && p.Placement != nil {
-		return nil, fmt.Errorf("container type and placement are mutually exclusive")
+		return p, fmt.Errorf("container type and placement are mutually exclusive")
 	}
 	if p.Placement != nil {
 		// Extract container type and parent from container placement directives.
@@ -603,6 +696,14 @@ func (c *Client) addOneMachine(p params.AddMachineParams) (*state.Machine, error
 			p.Placement = nil
 		}
 	}
+	if label, ok := instance.ParseLabelDirective(p.ParentId); ok {
+		// Containers may be placed inside any machine with the label.
+		parentId, err := c.machineIdWithLabel(label)
+		if err != nil {
+			return p, err
+		}
+		p.ParentId = parentId
+	}
 
 	if p.ContainerType != "" || p.Placement != nil {
 		// Guard against dubious client by making sure that
@@ -617,28 +718,33 @@ func (c *Client) addOneMachine(p params.AddMachineParams) (*state.Machine, error
 	if p.Series == "" {
 		conf, err := c.api.state.EnvironConfig()
 		if err != nil {
-			return nil, err
+			return p, err
 		}
 		p.Series = config.PreferredSeries(conf)
 	}
+	return p, nil
+}
 
+// machineTemplate returns the state.MachineTemplate corresponding
+// to the given resolved machine parameters.
+func (c *Client) machineTemplate(p params.AddMachineParams) (state.MachineTemplate, error) {
 	var placementDirective string
 	if p.Placement != nil {
 		env, err := c.api.state.Environment()
 		if err != nil {
-			return nil, err
+			return state.MachineTemplate{}, err
 		}
 		// For 1.21 we should support both UUID and name, and with 1.22
 		// just support UUID
 		if p.Placement.Scope != env.Name() && p.Placement.Scope != env.UUID() {
-			return nil, fmt.Errorf("invalid environment name %q", p.Placement.Scope)
+			return state.MachineTemplate{}, fmt.Errorf("invalid environment name %q", p.Placement.Scope)
 		}
 		placementDirective = p.Placement.Directive
 	}
 
 	jobs, err := common.StateJobs(p.Jobs)
 	if err != nil {
-		return nil, err
+		return state.MachineTemplate{}, err
 	}
 	template := state.MachineTemplate{
 		Series:      p.Series,
@@ -649,14 +755,22 @@ func (c *Client) addOneMachine(p params.AddMachineParams) (*state.Machine, error
 		HardwareCharacteristics: p.HardwareCharacteristics,
 		Addresses:               params.NetworkAddresses(p.Addrs),
 		Placement:               placementDirective,
+		Labels:                  p.Labels,
 	}
-	if p.ContainerType == "" {
-		return c.api.state.AddOneMachine(template)
+	return template, nil
+}
+
+// machineIdWithLabel returns the id of the first alive machine
+// with the given user-defined label.
+func (c *Client) machineIdWithLabel(label string) (string, error) {
+	machines, err := c.api.state.MachinesWithLabel(label)
+	if err != nil {
+		return "", err
 	}
-	if p.ParentId != "" {
-		return c.api.state.AddMachineInsideMachine(template, p.ParentId, p.ContainerType)
+	if len(machines) == 0 {
+		return "", errors.NotFoundf("machine with label %q", label)
 	}
-	return c.api.state.AddMachineInsideNewMachine(template, template, p.ContainerType)
+	return machines[0].Id(), nil
 }
 
 // ProvisioningScript returns a shell script that, when run,
@@ -761,6 +875,9 @@ func (c *Client) EnvironmentInfo() (api.EnvironmentInfo, error) {
 
 // ShareEnvironment manages allowing and denying the given user(s) access to the environment.
 func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params.ErrorResults, err error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
 	var createdBy names.UserTag
 	var ok bool
 	if createdBy, ok = c.api.auth.GetAuthTag().(names.UserTag); !ok {
@@ -801,6 +918,74 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// ModifyEnvironAccess grants or revokes read or write access to the
+// environment for the given users. Granting read access to a user
+// shares the environment with them, and revoking it unshares the
+// environment; users that have read access alone may only make
+// calls that do not change the environment.
+func (c *Client) ModifyEnvironAccess(args params.ModifyEnvironAccess) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	createdBy, ok := c.api.auth.GetAuthTag().(names.UserTag)
+	if !ok {
+		return result, errors.Errorf("api connection is not through a user")
+	}
+	env, err := c.api.state.Environment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		err := c.modifyEnvironAccess(env.Owner(), createdBy, arg)
+		if err != nil {
+			err = errors.Annotate(err, "could not modify environment access")
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (c *Client) modifyEnvironAccess(owner, createdBy names.UserTag, arg params.ModifyEnvironUserAccess) error {
+	user, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	envUser, err := c.api.state.EnvironmentUser(user)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if arg.Action == params.RevokeEnvironAccess && user.Username() == owner.Username() {
+		return errors.Errorf("cannot revoke access of the environment owner")
+	}
+	switch {
+	case arg.Action == params.GrantEnvironAccess && arg.Access == params.EnvironReadAccess:
+		if envUser != nil {
+			// Users with write access can already read.
+			return nil
+		}
+		_, err = c.api.state.AddReadOnlyEnvironmentUser(user, createdBy, "")
+	case arg.Action == params.GrantEnvironAccess && arg.Access == params.EnvironWriteAccess:
+		if envUser != nil {
+			err = envUser.SetReadOnly(false)
+		} else {
+			_, err = c.api.state.AddEnvironmentUser(user, createdBy, "")
+		}
+	case arg.Action == params.RevokeEnvironAccess && arg.Access == params.EnvironWriteAccess:
+		if envUser == nil {
+			return errors.NotFoundf("environment user %q", user.Username())
+		}
+		err = envUser.SetReadOnly(true)
+	case arg.Action == params.RevokeEnvironAccess && arg.Access == params.EnvironReadAccess:
+		err = c.api.state.RemoveEnvironmentUser(user)
+	default:
+		return errors.Errorf("unknown action %q of %q access", arg.Action, arg.Access)
+	}
+	return errors.Trace(err)
+}
+
 // EnvUserInfo returns information on all users in the environment.
 func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 	var results params.EnvUserInfoResults
@@ -927,6 +1112,51 @@ func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
 	return c.api.state.UpdateEnvironConfig(attrs, nil, checkAgentVersion)
 }
 
+// EnvironmentUpdateCredentials replaces the provider credentials in the
+// environment configuration. Only attributes that the provider treats
+// as secret may be changed, and the new credentials are checked with
+// the provider before they are saved. Workers holding an Environ pick
+// up the change through the environment config watcher, so credentials
+// can be rotated without restarting any agents.
+func (c *Client) EnvironmentUpdateCredentials(args params.EnvironmentSet) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	if len(args.Config) == 0 {
+		return errors.New("no credentials specified")
+	}
+	verifyCredentials := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
+		newConfig, err := oldConfig.Apply(updateAttrs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		provider, err := environs.Provider(newConfig.Type())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		secrets, err := provider.SecretAttrs(newConfig)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for key := range updateAttrs {
+			if _, ok := secrets[key]; !ok {
+				return errors.Errorf("%q is not a credential attribute", key)
+			}
+		}
+		env, err := environs.New(newConfig)
+		if err != nil {
+			return errors.Annotate(err, "invalid credentials")
+		}
+		if verifier, ok := environs.SupportsCredentialsVerification(env); ok {
+			if err := verifier.VerifyCredentials(); err != nil {
+				return errors.Annotate(err, "cannot verify credentials")
+			}
+		}
+		return nil
+	}
+	return c.api.state.UpdateEnvironConfig(args.Config, nil, verifyCredentials)
+}
+
 // EnvironmentUnset implements the server-side part of the
 // set-environment CLI command.
 func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
@@ -1009,6 +1239,34 @@ func (c *Client) RetryProvisioning(p params.Entities) (params.ErrorResults, erro
 	})
 }
 
+// SetMachineLabels replaces the user-defined labels of the given
+// machines.
+func (c *Client) SetMachineLabels(args params.SetMachineLabels) (params.ErrorResults, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Machines)),
+	}
+	for i, arg := range args.Machines {
+		err := c.setMachineLabels(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (c *Client) setMachineLabels(arg params.MachineLabels) error {
+	tag, err := names.ParseMachineTag(arg.MachineTag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	machine, err := c.api.state.Machine(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return machine.SetLabels(arg.Labels)
+}
+
 // APIHostPorts returns the API host/port addresses stored in state.
 func (c *Client) APIHostPorts() (result params.APIHostPortsResult, err error) {
 	var servers [][]network.HostPort
@@ -276,6 +276,77 @@ func (s *serverSuite) TestShareEnvironmentAddLocalUser(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.IsNil)
 }
 
+func (s *serverSuite) modifyEnvironAccess(c *gc.C, user names.UserTag, action params.EnvironAccessAction, access params.EnvironAccess) error {
+	args := params.ModifyEnvironAccess{
+		Changes: []params.ModifyEnvironUserAccess{{
+			UserTag: user.String(),
+			Action:  action,
+			Access:  access,
+		}}}
+	result, err := s.client.ModifyEnvironAccess(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	return result.OneError()
+}
+
+func (s *serverSuite) TestGrantEnvironReadAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "auditor", NoEnvUser: true})
+	err := s.modifyEnvironAccess(c, user.UserTag(), params.GrantEnvironAccess, params.EnvironReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+	c.Assert(envUser.CreatedBy(), gc.Equals, dummy.AdminUserTag().Username())
+}
+
+func (s *serverSuite) TestGrantEnvironReadAccessKeepsWriteAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "writer"})
+	err := s.modifyEnvironAccess(c, user.UserTag(), params.GrantEnvironAccess, params.EnvironReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsFalse)
+}
+
+func (s *serverSuite) TestGrantAndRevokeEnvironWriteAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "auditor", NoEnvUser: true})
+	err := s.modifyEnvironAccess(c, user.UserTag(), params.GrantEnvironAccess, params.EnvironReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.modifyEnvironAccess(c, user.UserTag(), params.GrantEnvironAccess, params.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsFalse)
+
+	err = s.modifyEnvironAccess(c, user.UserTag(), params.RevokeEnvironAccess, params.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+}
+
+func (s *serverSuite) TestRevokeEnvironReadAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "auditor"})
+	err := s.modifyEnvironAccess(c, user.UserTag(), params.RevokeEnvironAccess, params.EnvironReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serverSuite) TestRevokeEnvironAccessOfOwner(c *gc.C) {
+	err := s.modifyEnvironAccess(c, dummy.AdminUserTag(), params.RevokeEnvironAccess, params.EnvironWriteAccess)
+	c.Assert(err, gc.ErrorMatches, "could not modify environment access: cannot revoke access of the environment owner")
+}
+
+func (s *serverSuite) TestRevokeEnvironWriteAccessMissingUser(c *gc.C) {
+	err := s.modifyEnvironAccess(c, names.NewUserTag("nobody@remote"), params.RevokeEnvironAccess, params.EnvironWriteAccess)
+	c.Assert(err, gc.ErrorMatches, `could not modify environment access: environment user "nobody@remote" not found`)
+}
+
 func (s *serverSuite) TestShareEnvironmentAddRemoteUser(c *gc.C) {
 	user := names.NewUserTag("foobar@ubuntuone")
 	args := params.ModifyEnvironUsers{
@@ -886,6 +957,87 @@ func (s *clientSuite) TestClientAddServiceUnitsToNewContainer(c *gc.C) {
 	c.Assert(mid, gc.Equals, machine.Id()+"/lxc/0")
 }
 
+func (s *clientSuite) TestClientAddServiceUnitsToMachineLabel(c *gc.C) {
+	svc := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetLabels([]string{"gpu"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.APIState.Client().AddServiceUnits("dummy", 2, "label=gpu")
+	c.Assert(err, jc.ErrorIsNil)
+
+	units, err := svc.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 2)
+	for _, unit := range units {
+		mid, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(mid, gc.Equals, machine.Id())
+	}
+}
+
+func (s *clientSuite) TestClientSetMachineLabels(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().SetMachineLabels(machine.MachineTag(), []string{"ssd", "gpu"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Labels(), jc.DeepEquals, []string{"gpu", "ssd"})
+
+	err = s.APIState.Client().SetMachineLabels(machine.MachineTag(), []string{"Not Valid"})
+	c.Assert(err, gc.ErrorMatches, `cannot set labels of machine 0: machine label "Not Valid" not valid`)
+}
+
+func (s *clientSuite) TestBlockChangesShareEnvironment(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	s.BlockAllChanges(c, "TestBlockChangesShareEnvironment")
+	err := s.APIState.Client().ShareEnvironment(user.UserTag())
+	s.AssertBlocked(c, err, "TestBlockChangesShareEnvironment")
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientSuite) TestBlockChangesGrantEnvironAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	s.BlockAllChanges(c, "TestBlockChangesGrantEnvironAccess")
+	err := s.APIState.Client().GrantEnvironAccess(params.EnvironReadAccess, user.UserTag())
+	s.AssertBlocked(c, err, "TestBlockChangesGrantEnvironAccess")
+}
+
+func (s *clientSuite) TestClientSetMachineLabelsBlocked(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockAllChanges(c, "TestClientSetMachineLabelsBlocked")
+	err = s.APIState.Client().SetMachineLabels(machine.MachineTag(), []string{"gpu"})
+	s.AssertBlocked(c, err, "TestClientSetMachineLabelsBlocked")
+}
+
+func (s *clientSuite) TestClientAddMachineInsideMachineWithLabel(c *gc.C) {
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = host.SetLabels([]string{"gpu"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.APIState.Client().AddMachines([]params.AddMachineParams{{
+		Jobs:      []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Placement: instance.MustParsePlacement("lxc:label=gpu"),
+		Labels:    []string{"web"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[0].Machine, gc.Equals, host.Id()+"/lxc/0")
+
+	m, err := s.State.Machine(results[0].Machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Labels(), jc.DeepEquals, []string{"web"})
+}
+
 func (s *clientSuite) assertAddServiceUnits(c *gc.C) {
 	units, err := s.APIState.Client().AddServiceUnits("dummy", 3, "")
 	c.Assert(err, jc.ErrorIsNil)
@@ -2604,6 +2756,31 @@ func (s *clientSuite) TestClientPublicAddressUnit(c *gc.C) {
 	c.Assert(addr, gc.Equals, "public")
 }
 
+func (s *clientSuite) TestClientSSHHostKeys(c *gc.C) {
+	s.setUpScenario(c)
+
+	keys, err := s.APIState.Client().SSHHostKeys("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 0)
+
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	expected := []string{"ssh-rsa AAAAB3Nza root@host"}
+	err = m1.SetSSHHostKeys(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	keys, err = s.APIState.Client().SSHHostKeys("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, expected)
+	keys, err = s.APIState.Client().SSHHostKeys("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, expected)
+
+	_, err = s.APIState.Client().SSHHostKeys("wordpress")
+	c.Assert(err, gc.ErrorMatches, `unknown unit or machine "wordpress"`)
+	_, err = s.APIState.Client().SSHHostKeys("42")
+	c.Assert(err, gc.ErrorMatches, `machine 42 not found`)
+}
+
 func (s *clientSuite) TestClientPrivateAddressErrors(c *gc.C) {
 	s.setUpScenario(c)
 	_, err := s.APIState.Client().PrivateAddress("wordpress")
@@ -2686,6 +2863,36 @@ func (s *serverSuite) TestClientEnvironmentSet(c *gc.C) {
 	s.assertEnvValue(c, "other-key", "other value")
 }
 
+func (s *serverSuite) TestClientEnvironmentUpdateCredentials(c *gc.C) {
+	err := s.client.EnvironmentUpdateCredentials(params.EnvironmentSet{
+		Config: map[string]interface{}{"secret": "bacon"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvValue(c, "secret", "bacon")
+}
+
+func (s *serverSuite) TestClientEnvironmentUpdateCredentialsNotCredential(c *gc.C) {
+	err := s.client.EnvironmentUpdateCredentials(params.EnvironmentSet{
+		Config: map[string]interface{}{"secret": "bacon", "some-key": "value"},
+	})
+	c.Assert(err, gc.ErrorMatches, `"some-key" is not a credential attribute`)
+	s.assertEnvValue(c, "secret", "pork")
+}
+
+func (s *serverSuite) TestClientEnvironmentUpdateCredentialsVerifyFails(c *gc.C) {
+	s.AssertConfigParameterUpdated(c, "broken", "VerifyCredentials")
+	err := s.client.EnvironmentUpdateCredentials(params.EnvironmentSet{
+		Config: map[string]interface{}{"secret": "bacon"},
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot verify credentials: dummy.VerifyCredentials is broken")
+	s.assertEnvValue(c, "secret", "pork")
+}
+
+func (s *serverSuite) TestClientEnvironmentUpdateCredentialsNoCredentials(c *gc.C) {
+	err := s.client.EnvironmentUpdateCredentials(params.EnvironmentSet{})
+	c.Assert(err, gc.ErrorMatches, "no credentials specified")
+}
+
 func (s *serverSuite) TestClientEnvironmentSetImmutable(c *gc.C) {
 	// The various immutable config values are tested in
 	// environs/config/config_test.go, so just choosing one here.
@@ -2894,6 +3101,62 @@ func (s *clientSuite) TestClientAddMachineInsideMachine(c *gc.C) {
 	c.Assert(machines[0].Machine, gc.Equals, "0/lxc/0")
 }
 
+func (s *clientSuite) TestClientAddIdenticalMachines(c *gc.C) {
+	apiParams := params.AddMachineParams{
+		Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Constraints: constraints.MustParse("mem=4G"),
+	}
+	machines, err := s.APIState.Client().AddIdenticalMachines(apiParams, 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 3)
+	for i, machineResult := range machines {
+		c.Assert(machineResult.Error, gc.IsNil)
+		c.Assert(machineResult.Machine, gc.Equals, strconv.Itoa(i))
+		s.checkMachine(c, machineResult.Machine, coretesting.FakeDefaultSeries, apiParams.Constraints.String())
+	}
+}
+
+func (s *clientSuite) TestClientAddIdenticalMachinesAllOrNothing(c *gc.C) {
+	apiParams := params.AddMachineParams{
+		Jobs:      []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Placement: instance.MustParsePlacement("dummyenv:invalid"),
+	}
+	_, err := s.APIState.Client().AddIdenticalMachines(apiParams, 3)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: invalid placement is invalid")
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestClientAddIdenticalMachinesInvalid(c *gc.C) {
+	apiParams := params.AddMachineParams{
+		Jobs: []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}
+	_, err := s.APIState.Client().AddIdenticalMachines(apiParams, 0)
+	c.Assert(err, gc.ErrorMatches, "number of machines 0 not valid")
+
+	_, err = s.APIState.Client().AddIdenticalMachines(apiParams, params.MaxIdenticalMachines+1)
+	c.Assert(err, gc.ErrorMatches, "number of machines 101 not valid")
+
+	apiParams.Placement = instance.MustParsePlacement("lxc")
+	_, err = s.APIState.Client().AddIdenticalMachines(apiParams, 2)
+	c.Assert(err, gc.ErrorMatches, "adding containers in bulk not supported")
+
+	apiParams.Placement = nil
+	apiParams.InstanceId = "i-am-unique"
+	_, err = s.APIState.Client().AddIdenticalMachines(apiParams, 2)
+	c.Assert(err, gc.ErrorMatches, "instance id for identical machines not valid")
+}
+
+func (s *clientSuite) TestBlockChangesClientAddIdenticalMachines(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockChangesClientAddIdenticalMachines")
+	apiParams := params.AddMachineParams{
+		Jobs: []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}
+	_, err := s.APIState.Client().AddIdenticalMachines(apiParams, 3)
+	s.AssertBlocked(c, err, "TestBlockChangesClientAddIdenticalMachines")
+}
+
 // updateConfig sets config variable with given key to a given value
 // Asserts that no errors were encountered.
 func (s *baseSuite) updateConfig(c *gc.C, key string, block bool) {
@@ -106,6 +106,42 @@ func (c *Client) UnitStatusHistory(args params.StatusHistory) (api.UnitStatusHis
 	return statuses, nil
 }
 
+// MachineStatusHistory returns a slice of past statuses for a given
+// machine. Only the machine agent's status is recorded, so the
+// workload kind is not supported.
+func (c *Client) MachineStatusHistory(args params.StatusHistory) (api.MachineStatusHistory, error) {
+	switch args.Kind {
+	case "", params.KindCombined, params.KindAgent:
+	default:
+		return api.MachineStatusHistory{}, errors.NotValidf("machine status history kind %q", args.Kind)
+	}
+	size := args.Size - 1
+	if size < 1 {
+		return api.MachineStatusHistory{}, errors.Errorf("invalid history size: %d", args.Size)
+	}
+	machine, err := c.api.state.Machine(args.Name)
+	if err != nil {
+		return api.MachineStatusHistory{}, errors.Trace(err)
+	}
+	machineStatuses, err := machine.StatusHistory(size)
+	if err != nil {
+		return api.MachineStatusHistory{}, errors.Trace(err)
+	}
+	current, err := machine.Status()
+	if err != nil {
+		return api.MachineStatusHistory{}, errors.Trace(err)
+	}
+	// The history is returned newest first; report the
+	// statuses oldest first, ending with the current one.
+	for i, j := 0, len(machineStatuses)-1; i < j; i, j = i+1, j-1 {
+		machineStatuses[i], machineStatuses[j] = machineStatuses[j], machineStatuses[i]
+	}
+	machineStatuses = append(machineStatuses, current)
+	return api.MachineStatusHistory{
+		Statuses: agentStatusFromStatusInfo(machineStatuses, params.KindAgent),
+	}, nil
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	cfg, err := c.api.state.EnvironConfig()
@@ -172,6 +208,14 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 			}
 		}
 
+		// Filter relations: keep only those involving at
+		// least one of the services that remain.
+		for svcName := range context.relations {
+			if _, ok := context.services[svcName]; !ok {
+				delete(context.relations, svcName)
+			}
+		}
+
 		// Filter machines
 		for status, machineList := range context.machines {
 			filteredList := make([]*state.Machine, 0, len(machineList))
@@ -4,10 +4,13 @@
 package client_test
 
 import (
+	"sort"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -45,6 +48,62 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestMachineStatusHistory(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusError, "it broke", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	history, err := client.MachineStatusHistory(machine.Id(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 3)
+	var statuses []params.Status
+	for _, status := range history.Statuses {
+		c.Check(status.Kind, gc.Equals, params.KindAgent)
+		statuses = append(statuses, status.Status)
+	}
+	c.Assert(statuses, jc.DeepEquals, []params.Status{
+		params.StatusPending, params.StatusStarted, params.StatusError,
+	})
+	c.Assert(history.Statuses[2].Info, gc.Equals, "it broke")
+}
+
+func (s *statusSuite) TestFullStatusFiltersRelations(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.AddTestingService(c, "riak", s.AddTestingCharm(c, "riak"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	for i, test := range []struct {
+		patterns  []string
+		relations []string
+	}{{
+		relations: []string{"riak:ring", "wordpress:db mysql:server"},
+	}, {
+		patterns:  []string{"wordpress"},
+		relations: []string{"wordpress:db mysql:server"},
+	}, {
+		patterns:  []string{"riak"},
+		relations: []string{"riak:ring"},
+	}} {
+		c.Logf("test %d: %v", i, test.patterns)
+		status, err := client.Status(test.patterns)
+		c.Assert(err, jc.ErrorIsNil)
+		var relations []string
+		for _, relation := range status.Relations {
+			relations = append(relations, relation.Key)
+		}
+		sort.Strings(relations)
+		c.Check(relations, jc.DeepEquals, test.relations)
+	}
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
@@ -74,6 +74,7 @@ var (
 	ErrStoppedWatcher     = stderrors.New("watcher has been stopped")
 	ErrBadRequest         = stderrors.New("invalid request")
 	ErrTryAgain           = stderrors.New("try again")
+	ErrTooBusy            = stderrors.New("server is too busy, try again later")
 	ErrActionNotAvailable = stderrors.New("action no longer available")
 
 	ErrOperationBlocked = func(msg string) *params.Error {
@@ -101,6 +102,7 @@ var singletonErrorCodes = map[error]string{
 	ErrUnknownWatcher:            params.CodeNotFound,
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
+	ErrTooBusy:                   params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
 }
 
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"fmt"
+	"sync"
+)
+
+// UnauthenticatedKind is the connection kind reported by ServerStats
+// for connections that have not yet logged in.
+const UnauthenticatedKind = "unauthenticated"
+
+// ServerStats tracks the open connections to an API server, so that
+// facades can report on them. The server registers it with every
+// connection's Resources under the name "serverStats".
+type ServerStats struct {
+	mu    sync.Mutex
+	conns map[*Resources]string
+}
+
+// NewServerStats returns a ServerStats with no connections.
+func NewServerStats() *ServerStats {
+	return &ServerStats{
+		conns: make(map[*Resources]string),
+	}
+}
+
+// Stop implements Resource. It does nothing, as the stats outlive any
+// single connection.
+func (s *ServerStats) Stop() error {
+	return nil
+}
+
+// Add records a new connection, identified by its resources.
+func (s *ServerStats) Add(rs *Resources) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conns[rs] = UnauthenticatedKind
+}
+
+// SetKind records the kind of entity (e.g. "user", "machine", "unit")
+// that has logged in on the given connection.
+func (s *ServerStats) SetKind(rs *Resources, kind string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.conns[rs]; ok {
+		s.conns[rs] = kind
+	}
+}
+
+// Remove forgets a closed connection.
+func (s *ServerStats) Remove(rs *Resources) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, rs)
+}
+
+// Connections returns the number of open connections by entity kind.
+func (s *ServerStats) Connections() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[string]int)
+	for _, kind := range s.conns {
+		counts[kind]++
+	}
+	return counts
+}
+
+// Resources returns the number of resources held across all open
+// connections, by resource type. Informational string resources and
+// the stats themselves are not counted.
+func (s *ServerStats) Resources() map[string]int {
+	s.mu.Lock()
+	all := make([]*Resources, 0, len(s.conns))
+	for rs := range s.conns {
+		all = append(all, rs)
+	}
+	s.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, rs := range all {
+		rs.mu.Lock()
+		for _, r := range rs.resources {
+			switch r.(type) {
+			case StringResource, *ServerStats:
+				continue
+			}
+			counts[fmt.Sprintf("%T", r)]++
+		}
+		rs.mu.Unlock()
+	}
+	return counts
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/testing"
+)
+
+type serverStatsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&serverStatsSuite{})
+
+func (s *serverStatsSuite) TestConnections(c *gc.C) {
+	stats := common.NewServerStats()
+	rs1 := common.NewResources()
+	rs2 := common.NewResources()
+	rs3 := common.NewResources()
+	stats.Add(rs1)
+	stats.Add(rs2)
+	stats.Add(rs3)
+	stats.SetKind(rs1, "user")
+	stats.SetKind(rs2, "unit")
+	c.Assert(stats.Connections(), jc.DeepEquals, map[string]int{
+		"user":                     1,
+		"unit":                     1,
+		common.UnauthenticatedKind: 1,
+	})
+
+	stats.Remove(rs3)
+	stats.SetKind(rs3, "machine")
+	c.Assert(stats.Connections(), jc.DeepEquals, map[string]int{
+		"user": 1,
+		"unit": 1,
+	})
+}
+
+func (s *serverStatsSuite) TestResources(c *gc.C) {
+	stats := common.NewServerStats()
+	rs1 := common.NewResources()
+	rs2 := common.NewResources()
+	stats.Add(rs1)
+	stats.Add(rs2)
+	err := rs1.RegisterNamed("serverStats", stats)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rs1.RegisterNamed("dataDir", common.StringResource("/var/lib/juju"))
+	c.Assert(err, jc.ErrorIsNil)
+	rs1.Register(&fakeResource{})
+	rs2.Register(&fakeResource{})
+	c.Assert(stats.Resources(), jc.DeepEquals, map[string]int{
+		"*common_test.fakeResource": 2,
+	})
+}
@@ -258,6 +258,14 @@ func (f *ToolsFinder) findMatchingTools(args params.FindToolsParams) (coretools.
 	if err != nil {
 		return nil, err
 	}
+	if args.AgentStream != "" {
+		cfg, err = cfg.Apply(map[string]interface{}{
+			config.AgentStreamKey: args.AgentStream,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 	env, err := environs.New(cfg)
 	if err != nil {
 		return nil, err
@@ -184,6 +184,18 @@ func (s *toolsSuite) TestFindTools(c *gc.C) {
 	})
 }
 
+func (s *toolsSuite) TestFindToolsAgentStream(c *gc.C) {
+	var stream string
+	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, filter coretools.Filter) (coretools.List, error) {
+		stream = e.Config().AgentStream()
+		return nil, errors.NotFoundf("tools")
+	})
+	toolsFinder := common.NewToolsFinder(s.State, s.State, sprintfURLGetter("%s"))
+	_, err := toolsFinder.FindTools(params.FindToolsParams{AgentStream: "proposed"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stream, gc.Equals, "proposed")
+}
+
 func (s *toolsSuite) TestFindToolsNotFound(c *gc.C) {
 	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, filter coretools.Filter) (list coretools.List, err error) {
 		return nil, errors.NotFoundf("tools")
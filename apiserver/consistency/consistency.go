@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package consistency implements the API facade used to find and
+// repair dangling references in state, such as units assigned to
+// machines that no longer exist, for recovering environments left
+// inconsistent by past bugs.
+package consistency
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Consistency", 1, NewAPI)
+}
+
+// Consistency defines the methods on the consistency API end point.
+type Consistency interface {
+	// Check returns the inconsistencies found in the environment.
+	Check() (params.Inconsistencies, error)
+
+	// Repair repairs each of the given inconsistencies.
+	Repair(params.Inconsistencies) (params.ErrorResults, error)
+}
+
+// API implements Consistency and is the concrete implementation
+// of the api end point.
+type API struct {
+	st *state.State
+}
+
+var _ Consistency = (*API)(nil)
+
+// NewAPI returns a new consistency API facade. Only the owner of the
+// environment may use it.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// TODO(thumper): PERMISSIONS Change this permission check when we have
+	// real permissions. For now, only the owner of the environment may
+	// check and repair it.
+	user, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if user != env.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st}, nil
+}
+
+// Check implements Consistency.Check().
+func (api *API) Check() (params.Inconsistencies, error) {
+	found, err := api.st.CheckConsistency()
+	if err != nil {
+		return params.Inconsistencies{}, errors.Trace(err)
+	}
+	result := params.Inconsistencies{
+		Inconsistencies: make([]params.Inconsistency, len(found)),
+	}
+	for i, inc := range found {
+		result.Inconsistencies[i] = params.Inconsistency{
+			Kind:    string(inc.Kind),
+			Entity:  inc.Entity,
+			Missing: inc.Missing,
+			Message: inc.String(),
+		}
+	}
+	return result, nil
+}
+
+// Repair implements Consistency.Repair().
+func (api *API) Repair(args params.Inconsistencies) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Inconsistencies)),
+	}
+	for i, inc := range args.Inconsistencies {
+		err := api.st.RepairInconsistency(state.Inconsistency{
+			Kind:    state.InconsistencyKind(inc.Kind),
+			Entity:  inc.Entity,
+			Missing: inc.Missing,
+		})
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package consistency_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/consistency"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type consistencySuite struct {
+	jujutesting.JujuConnSuite
+
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&consistencySuite{})
+
+func (s *consistencySuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+}
+
+func (s *consistencySuite) newAPI(c *gc.C) *consistency.API {
+	api, err := consistency.NewAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *consistencySuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	api, err := consistency.NewAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *consistencySuite) TestNewAPIRefusesNonOwner(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	s.authorizer.Tag = user.Tag()
+	api, err := consistency.NewAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *consistencySuite) addDanglingAddress(c *gc.C) *state.IPAddress {
+	addr := network.NewScopedAddress("0.1.2.3", network.ScopeCloudLocal)
+	ipAddr, err := s.State.AddIPAddress(addr, "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.AllocateTo("42", "wibble")
+	c.Assert(err, jc.ErrorIsNil)
+	return ipAddr
+}
+
+func (s *consistencySuite) TestCheck(c *gc.C) {
+	api := s.newAPI(c)
+	result, err := api.Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Inconsistencies, gc.HasLen, 0)
+
+	s.addDanglingAddress(c)
+	result, err = api.Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Inconsistencies, jc.DeepEquals, []params.Inconsistency{{
+		Kind:    "address-machine-missing",
+		Entity:  "0.1.2.3",
+		Missing: "42",
+		Message: `IP address "0.1.2.3" is allocated to missing machine "42"`,
+	}})
+}
+
+func (s *consistencySuite) TestRepair(c *gc.C) {
+	ipAddr := s.addDanglingAddress(c)
+	api := s.newAPI(c)
+	results, err := api.Repair(params.Inconsistencies{
+		Inconsistencies: []params.Inconsistency{{
+			Kind:    "address-machine-missing",
+			Entity:  "0.1.2.3",
+			Missing: "42",
+		}, {
+			Kind: "bogus",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot repair inconsistency: .*: inconsistency kind "bogus" not valid`)
+
+	err = ipAddr.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddr.Life(), gc.Equals, state.Dead)
+}
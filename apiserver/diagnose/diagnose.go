@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package diagnose implements the API facade through which clients
+// ask an API server to check its own host for problems, such as
+// clock skew and broken DNS, that cause obscure failures elsewhere.
+package diagnose
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Diagnose", 1, NewAPI)
+}
+
+// The following are defined as variables so they can be replaced
+// for testing.
+var (
+	resolvConfPath = "/etc/resolv.conf"
+	hostname       = os.Hostname
+	lookupHost     = net.LookupHost
+	now            = time.Now
+)
+
+// Diagnose defines the methods on the diagnose API end point.
+type Diagnose interface {
+	// ServerChecks checks the API server's host for problems.
+	ServerChecks() (params.ServerChecks, error)
+}
+
+// API implements Diagnose and is the concrete implementation
+// of the api end point.
+type API struct{}
+
+var _ Diagnose = (*API)(nil)
+
+// NewAPI returns a new diagnose API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{}, nil
+}
+
+// ServerChecks implements Diagnose.ServerChecks(). It makes the same
+// DNS checks as are made of the bootstrap instance, and reports the
+// server's clock so that the client can check for clock skew.
+func (a *API) ServerChecks() (params.ServerChecks, error) {
+	var problems []string
+	if !hasNameserver() {
+		problems = append(problems, fmt.Sprintf("no DNS nameservers configured in %s", resolvConfPath))
+	}
+	if name, err := hostname(); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot get hostname: %v", err))
+	} else if _, err := lookupHost(name); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot resolve own hostname %q: %v", name, err))
+	}
+	return params.ServerChecks{
+		Time:     now(),
+		Problems: problems,
+	}, nil
+}
+
+// hasNameserver reports whether resolv.conf names any nameservers.
+func hasNameserver() bool {
+	f, err := os.Open(resolvConfPath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "nameserver") {
+			return true
+		}
+	}
+	return false
+}
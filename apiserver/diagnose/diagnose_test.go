@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diagnose_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/diagnose"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type diagnoseSuite struct {
+	coretesting.BaseSuite
+	api        *diagnose.API
+	resolvConf string
+	now        time.Time
+}
+
+var _ = gc.Suite(&diagnoseSuite{})
+
+func (s *diagnoseSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	var err error
+	s.api, err = diagnose.NewAPI(nil, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.resolvConf = filepath.Join(c.MkDir(), "resolv.conf")
+	err = ioutil.WriteFile(s.resolvConf, []byte("search example.com\nnameserver 10.0.0.2\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(diagnose.ResolvConfPath, s.resolvConf)
+	s.PatchValue(diagnose.Hostname, func() (string, error) {
+		return "juju-machine-0", nil
+	})
+	s.PatchValue(diagnose.LookupHost, func(host string) ([]string, error) {
+		return []string{"10.0.0.1"}, nil
+	})
+	s.now = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(diagnose.Now, func() time.Time {
+		return s.now
+	})
+}
+
+func (s *diagnoseSuite) TestNewAPIRefusesAgents(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := diagnose.NewAPI(nil, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *diagnoseSuite) TestServerChecks(c *gc.C) {
+	checks, err := s.api.ServerChecks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, jc.DeepEquals, params.ServerChecks{Time: s.now})
+}
+
+func (s *diagnoseSuite) TestServerChecksProblems(c *gc.C) {
+	err := ioutil.WriteFile(s.resolvConf, []byte("search example.com\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(diagnose.LookupHost, func(host string) ([]string, error) {
+		return nil, errors.New("no such host")
+	})
+	checks, err := s.api.ServerChecks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks.Problems, jc.DeepEquals, []string{
+		"no DNS nameservers configured in " + s.resolvConf,
+		`cannot resolve own hostname "juju-machine-0": no such host`,
+	})
+}
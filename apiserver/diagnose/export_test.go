@@ -0,0 +1,11 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diagnose
+
+var (
+	ResolvConfPath = &resolvConfPath
+	Hostname       = &hostname
+	LookupHost     = &lookupHost
+	Now            = &now
+)
@@ -0,0 +1,44 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// enrollingRoot restricts API calls made by a machine agent that has
+// logged in with the one-time password it was provisioned with to
+// those needed to exchange it for a permanent password.
+type enrollingRoot struct {
+	rpc.MethodFinder
+}
+
+// newEnrollingRoot returns a new enrollingRoot.
+func newEnrollingRoot(finder rpc.MethodFinder) *enrollingRoot {
+	return &enrollingRoot{finder}
+}
+
+// enrollingRootNames are the facades that can be accessed by a machine
+// agent logged in with its initial password.
+var enrollingRootNames = set.NewStrings(
+	"Enrollment",
+	"Pinger",
+)
+
+// FindMethod returns a not supported error if the rootName is not one
+// of the facades available to an enrolling machine agent.
+func (r *enrollingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !enrollingRootNames.Contains(rootName) {
+		return nil, errors.NotSupportedf("logged in with initial password, %q", rootName)
+	}
+	return caller, nil
+}
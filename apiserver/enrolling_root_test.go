@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type enrollingRootSuite struct {
+	testing.BaseSuite
+
+	root rpc.MethodFinder
+}
+
+var _ = gc.Suite(&enrollingRootSuite{})
+
+func (r *enrollingRootSuite) SetUpTest(c *gc.C) {
+	r.BaseSuite.SetUpTest(c)
+	r.root = apiserver.TestingEnrollingApiHandler(nil)
+}
+
+func (r *enrollingRootSuite) TestFindAllowedMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Enrollment", 1, "Enroll")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+
+	caller, err = r.root.FindMethod("Pinger", 0, "Ping")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (r *enrollingRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Agent", 1, "GetEntities")
+
+	c.Assert(err, gc.ErrorMatches, `logged in with initial password, "Agent" not supported`)
+	c.Assert(errors.IsNotSupported(err), jc.IsTrue)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *enrollingRootSuite) TestNonExistentFacade(c *gc.C) {
+	caller, err := r.root.FindMethod("NonExistent", 0, "Method")
+
+	c.Assert(err, gc.ErrorMatches, `unknown object type "NonExistent"`)
+	c.Assert(caller, gc.IsNil)
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package enrollment implements the API facade used by a machine agent
+// to exchange the one-time password it was provisioned with for a
+// permanent password.
+package enrollment
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Enrollment", 1, NewEnrollmentAPI)
+}
+
+// EnrollmentAPI implements the API used by machine agents on their
+// first login.
+type EnrollmentAPI struct {
+	passwordChanger *common.PasswordChanger
+}
+
+// NewEnrollmentAPI returns a new EnrollmentAPI. Only machine agents may
+// use it.
+func NewEnrollmentAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*EnrollmentAPI, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	getCanEnroll := func() (common.AuthFunc, error) {
+		authEntityTag := authorizer.GetAuthTag()
+		return func(tag names.Tag) bool {
+			// A machine agent may only enroll itself.
+			return tag == authEntityTag
+		}, nil
+	}
+	return &EnrollmentAPI{
+		passwordChanger: common.NewPasswordChanger(st, getCanEnroll),
+	}, nil
+}
+
+// Enroll replaces the one-time password each given machine logged in
+// with by the given permanent password. The one-time password is
+// invalidated, and later logins are not restricted to this facade.
+func (api *EnrollmentAPI) Enroll(args params.EntityPasswords) (params.ErrorResults, error) {
+	return api.passwordChanger.SetPasswords(args)
+}
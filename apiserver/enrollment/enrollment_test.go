@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package enrollment_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/enrollment"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type enrollmentSuite struct {
+	jujutesting.JujuConnSuite
+
+	machine    *state.Machine
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&enrollmentSuite{})
+
+func (s *enrollmentSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetInitialPassword("initial-1234567890123456")
+	c.Assert(err, jc.ErrorIsNil)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.machine.Tag(),
+	}
+}
+
+func (s *enrollmentSuite) TestNewEnrollmentAPIRefusesNonMachineAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewUnitTag("mysql/0")
+	api, err := enrollment.NewEnrollmentAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *enrollmentSuite) TestEnroll(c *gc.C) {
+	api, err := enrollment.NewEnrollmentAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.Enroll(params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: s.machine.Tag().String(), Password: "permanent-12345678901234"},
+			{Tag: other.Tag().String(), Password: "permanent-12345678901234"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.HasInitialPassword(), jc.IsFalse)
+	c.Assert(s.machine.PasswordValid("initial-1234567890123456"), jc.IsFalse)
+	c.Assert(s.machine.PasswordValid("permanent-12345678901234"), jc.IsTrue)
+
+	err = other.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(other.PasswordValid("permanent-12345678901234"), jc.IsFalse)
+}
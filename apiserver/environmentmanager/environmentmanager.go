@@ -32,6 +32,7 @@ type EnvironmentManager interface {
 	ConfigSkeleton(args params.EnvironmentSkeletonConfigArgs) (params.EnvironConfigResult, error)
 	CreateEnvironment(args params.EnvironmentCreateArgs) (params.Environment, error)
 	ListEnvironments(user params.Entity) (params.EnvironmentList, error)
+	DestroyEnvironments(args params.Entities) (params.ErrorResults, error)
 }
 
 // EnvironmentManagerAPI implements the environment manager interface and is
@@ -356,3 +357,52 @@ func (em *EnvironmentManagerAPI) ListEnvironments(user params.Entity) (params.En
 
 	return result, nil
 }
+
+// DestroyEnvironments destroys the specified hosted environments. As
+// with creating environments, users may destroy their own environments
+// and the state server owner may destroy anyone's. The state server
+// environment itself cannot be destroyed through this API.
+func (em *EnvironmentManagerAPI) DestroyEnvironments(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+
+	stateServerEnv, err := em.state.StateServerEnvironment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	for i, entity := range args.Entities {
+		err := em.destroyEnvironment(entity.Tag, stateServerEnv)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (em *EnvironmentManagerAPI) destroyEnvironment(tag string, stateServerEnv *state.Environment) error {
+	envTag, err := names.ParseEnvironTag(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if envTag == stateServerEnv.EnvironTag() {
+		return errors.New("cannot destroy the state server environment")
+	}
+	env, err := em.state.GetEnvironment(envTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := em.authCheck(env.Owner(), stateServerEnv.Owner()); err != nil {
+		return errors.Trace(err)
+	}
+
+	st, err := em.state.ForEnviron(envTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+
+	if err := common.NewBlockChecker(st).DestroyAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(common.DestroyEnvironment(st))
+}
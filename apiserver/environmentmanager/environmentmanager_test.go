@@ -4,6 +4,7 @@
 package environmentmanager_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -25,6 +26,7 @@ import (
 	_ "github.com/juju/juju/provider/openstack"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
 )
 
@@ -332,6 +334,69 @@ func (s *envManagerSuite) TestListEnvironmentsDenied(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *envManagerSuite) destroyEnvironment(c *gc.C, envTag names.EnvironTag) error {
+	results, err := s.envmanager.DestroyEnvironments(params.Entities{
+		Entities: []params.Entity{{Tag: envTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	return results.OneError()
+}
+
+func (s *envManagerSuite) TestDestroyEnvironmentOwner(c *gc.C) {
+	owner := names.NewUserTag("external@remote")
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner})
+	defer st.Close()
+	envTag := st.EnvironTag()
+
+	s.setAPIUser(c, owner)
+	err := s.destroyEnvironment(c, envTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.GetEnvironment(envTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *envManagerSuite) TestAdminCanDestroyEnvironmentForSomeoneElse(c *gc.C) {
+	owner := names.NewUserTag("external@remote")
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner})
+	defer st.Close()
+
+	s.setAPIUser(c, s.AdminUserTag(c))
+	err := s.destroyEnvironment(c, st.EnvironTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *envManagerSuite) TestDestroyEnvironmentDenied(c *gc.C) {
+	owner := names.NewUserTag("external@remote")
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner})
+	defer st.Close()
+	envTag := st.EnvironTag()
+
+	s.setAPIUser(c, names.NewUserTag("other@remote"))
+	err := s.destroyEnvironment(c, envTag)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	env, err := s.State.GetEnvironment(envTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Alive)
+}
+
+func (s *envManagerSuite) TestDestroyStateServerEnvironmentRefused(c *gc.C) {
+	s.setAPIUser(c, s.AdminUserTag(c))
+	err := s.destroyEnvironment(c, s.State.EnvironTag())
+	c.Assert(err, gc.ErrorMatches, "cannot destroy the state server environment")
+}
+
+func (s *envManagerSuite) TestDestroyEnvironmentBadTag(c *gc.C) {
+	s.setAPIUser(c, s.AdminUserTag(c))
+	results, err := s.envmanager.DestroyEnvironments(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, `"machine-0" is not a valid environment tag`)
+}
+
 type fakeProvider struct {
 	environs.EnvironProvider
 }
@@ -18,6 +18,8 @@ type stateInterface interface {
 	StateServerEnvironment() (*state.Environment, error)
 	NewEnvironment(*config.Config, names.UserTag) (*state.Environment, *state.State, error)
 	EnvironmentsForUser(names.UserTag) ([]*state.Environment, error)
+	GetEnvironment(names.EnvironTag) (*state.Environment, error)
+	ForEnviron(names.EnvironTag) (*state.State, error)
 }
 
 type stateShim struct {
@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"fmt"
+	"io"
 	"reflect"
 	"time"
 
@@ -28,6 +29,8 @@ var (
 	NewBackups            = &newBackups
 	ParseLogLine          = parseLogLine
 	AgentMatchesFilter    = agentMatchesFilter
+	IsAuditedCall         = isAuditedCall
+	IsReadOnlyCall        = isReadOnlyCall
 )
 
 func ApiHandlerWithEntity(entity state.Entity) *apiHandler {
@@ -36,6 +39,12 @@ func ApiHandlerWithEntity(entity state.Entity) *apiHandler {
 
 const LoginRateLimit = loginRateLimit
 
+// LoginBucketAvailable returns the number of agent logins the server
+// will currently accept before throttling.
+func LoginBucketAvailable(srv *Server) int64 {
+	return srv.loginBucket.Available()
+}
+
 // DelayLogins changes how the Login code works so that logins won't proceed
 // until they get a message on the returned channel.
 // After calling this function, the caller is responsible for sending messages
@@ -78,6 +87,7 @@ func TestingApiHandler(c *gc.C, srvSt, st *state.State) (*apiHandler, *common.Re
 	srv := &Server{
 		state: srvSt,
 		tag:   names.NewMachineTag("0"),
+		stats: common.NewServerStats(),
 	}
 	h, err := newApiHandler(srv, st, nil, nil, st.EnvironUUID())
 	c.Assert(err, jc.ErrorIsNil)
@@ -98,6 +108,42 @@ func TestingRestrictedApiHandler(st *state.State) rpc.MethodFinder {
 	return newRestrictedRoot(r)
 }
 
+// TestingEnrollingApiHandler returns a srvRoot as if accessed by a
+// machine agent logged in with its initial password.
+func TestingEnrollingApiHandler(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newEnrollingRoot(r)
+}
+
+// TestingReadOnlyApiHandler returns a srvRoot that only dispatches
+// calls that do not change the environment.
+func TestingReadOnlyApiHandler(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newReadOnlyRoot(r)
+}
+
+// TestingAuthorizingApiHandler returns a srvRoot that consults the given
+// authorizers before dispatching calls made by the entity with the
+// given tag.
+func TestingAuthorizingApiHandler(st *state.State, tag names.Tag, authorizers ...CallAuthorizer) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newAuthorizingRoot(r, tag, "", authorizers)
+}
+
+// TestingAuditingRoot returns a MethodFinder that records the calls
+// dispatched by finder on behalf of the entity with the given tag in
+// the audit log of st, and in file if it is not nil.
+func TestingAuditingRoot(finder rpc.MethodFinder, st *state.State, tag names.Tag, file io.Writer) rpc.MethodFinder {
+	return newAuditingRoot(finder, st, tag, newAuditLog(file))
+}
+
+// TestingAdmittingRoot returns a MethodFinder that limits the calls
+// dispatched by finder according to the given concurrency limits,
+// waiting for up to timeout for a call to be admitted.
+func TestingAdmittingRoot(finder rpc.MethodFinder, limits map[string]int, timeout time.Duration) rpc.MethodFinder {
+	return newAdmittingRoot(finder, newAdmission(limits, timeout))
+}
+
 type preFacadeAdminApi struct{}
 
 func newPreFacadeAdminApi(srv *Server, root *apiHandler, reqNotifier *requestNotifier) interface{} {
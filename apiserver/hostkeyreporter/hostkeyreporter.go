@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hostkeyreporter implements the API facade used by machine
+// agents to report the public SSH host keys of their machines, so
+// that clients can verify them when connecting with "juju ssh".
+package hostkeyreporter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/ssh"
+)
+
+func init() {
+	common.RegisterStandardFacade("HostKeyReporter", 1, NewHostKeyReporterAPI)
+}
+
+// HostKeyReporterAPI implements the HostKeyReporter API facade.
+type HostKeyReporterAPI struct {
+	state        *state.State
+	getCanModify common.GetAuthFunc
+}
+
+// NewHostKeyReporterAPI creates a new server-side HostKeyReporter
+// API facade.
+func NewHostKeyReporterAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*HostKeyReporterAPI, error) {
+	// Only machine agents may report host keys, and only
+	// for their own machines.
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	getCanModify := func() (common.AuthFunc, error) {
+		return authorizer.AuthOwner, nil
+	}
+	return &HostKeyReporterAPI{
+		state:        st,
+		getCanModify: getCanModify,
+	}, nil
+}
+
+// ReportKeys records the public SSH host keys of the given machines,
+// replacing any recorded previously.
+func (api *HostKeyReporterAPI) ReportKeys(args params.SSHHostKeySet) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.EntityKeys)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.EntityKeys {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canModify(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = api.reportKeys(tag, arg.PublicKeys)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *HostKeyReporterAPI) reportKeys(tag names.MachineTag, keys []string) error {
+	for _, key := range keys {
+		if _, err := ssh.ParseAuthorisedKey(key); err != nil {
+			return errors.NewNotValid(err, "invalid SSH host key")
+		}
+	}
+	machine, err := api.state.Machine(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return machine.SetSSHHostKeys(keys)
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/hostkeyreporter"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
+)
+
+type hostKeyReporterSuite struct {
+	jujutesting.JujuConnSuite
+
+	rawMachine       *state.Machine
+	unrelatedMachine *state.Machine
+	reporter         *hostkeyreporter.HostKeyReporterAPI
+	authoriser       apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&hostKeyReporterSuite{})
+
+func (s *hostKeyReporterSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	var err error
+	s.rawMachine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unrelatedMachine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Tag: s.rawMachine.Tag(),
+	}
+	s.reporter, err = hostkeyreporter.NewHostKeyReporterAPI(s.State, common.NewResources(), s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *hostKeyReporterSuite) TestNewHostKeyReporterAPIRefusesNonMachineAgent(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.Tag = names.NewUnitTag("ubuntu/1")
+	endPoint, err := hostkeyreporter.NewHostKeyReporterAPI(s.State, common.NewResources(), anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *hostKeyReporterSuite) TestReportKeys(c *gc.C) {
+	keys := []string{sshtesting.ValidKeyOne.Key, sshtesting.ValidKeyTwo.Key}
+	results, err := s.reporter.ReportKeys(params.SSHHostKeySet{
+		EntityKeys: []params.EntitySSHHostKeys{
+			{Tag: s.rawMachine.Tag().String(), PublicKeys: keys},
+			{Tag: s.unrelatedMachine.Tag().String(), PublicKeys: keys},
+			{Tag: "unit-ubuntu-1", PublicKeys: keys},
+			{Tag: s.rawMachine.Tag().String(), PublicKeys: []string{"ssh-rsa bad key"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(results.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, "invalid SSH host key: .*")
+
+	// The invalid report left the earlier keys in place.
+	err = s.rawMachine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.rawMachine.SSHHostKeys(), jc.DeepEquals, keys)
+	err = s.unrelatedMachine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unrelatedMachine.SSHHostKeys(), gc.HasLen, 0)
+}
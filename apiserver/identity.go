@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/state"
+)
+
+// newExternalMacaroonAuthenticator returns an authenticator for users
+// of the external identity service named in the environment config,
+// or nil if no identity service is configured. Changes to the identity
+// settings take effect when the API server is restarted.
+func newExternalMacaroonAuthenticator(st *state.State) (*authentication.ExternalMacaroonAuthenticator, error) {
+	envCfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	idURL := envCfg.IdentityURL()
+	if idURL == "" {
+		return nil, nil
+	}
+	svc, err := bakery.NewService(bakery.NewServiceParams{
+		Location: "juju environment " + st.EnvironUUID(),
+		Store:    bakeryStorage{st},
+		Locator: bakery.PublicKeyLocatorMap{
+			idURL: envCfg.IdentityPublicKey(),
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("accepting logins from identity service at %s", idURL)
+	return &authentication.ExternalMacaroonAuthenticator{
+		Service:          svc,
+		IdentityLocation: idURL,
+	}, nil
+}
+
+// bakeryStorage stores the root keys of the login macaroons in state,
+// so that a macaroon minted by one API server can be checked by any
+// other, and remains valid when the server restarts.
+type bakeryStorage struct {
+	st *state.State
+}
+
+// Put implements bakery.Storage.
+func (s bakeryStorage) Put(location, item string) error {
+	return s.st.PutMacaroonKey(location, item)
+}
+
+// Get implements bakery.Storage.
+func (s bakeryStorage) Get(location string) (string, error) {
+	item, err := s.st.MacaroonKey(location)
+	if errors.IsNotFound(err) {
+		return "", bakery.ErrNotFound
+	}
+	return item, err
+}
+
+// Del implements bakery.Storage.
+func (s bakeryStorage) Del(location string) error {
+	return s.st.RemoveMacaroonKey(location)
+}
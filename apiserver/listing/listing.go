@@ -0,0 +1,175 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package listing implements the API facade used to list the
+// machines and units in an environment. The results have a fixed
+// layout independent of the status document, for the benefit of
+// scripts and other external tools.
+package listing
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Listing", 1, NewAPI)
+}
+
+// Listing defines the methods on the listing API end point.
+type Listing interface {
+	// ListMachines returns a summary of every machine in the
+	// environment, ordered by machine id.
+	ListMachines() (params.ListMachinesResults, error)
+
+	// ListUnits returns a summary of every unit in the
+	// environment, ordered by unit name.
+	ListUnits() (params.ListUnitsResults, error)
+}
+
+// API implements Listing and is the concrete implementation
+// of the api end point.
+type API struct {
+	st *state.State
+}
+
+var _ Listing = (*API)(nil)
+
+// NewAPI returns a new listing API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st}, nil
+}
+
+// ListMachines implements Listing.ListMachines().
+func (a *API) ListMachines() (params.ListMachinesResults, error) {
+	machines, err := a.st.AllMachines()
+	if err != nil {
+		return params.ListMachinesResults{}, errors.Trace(err)
+	}
+	result := params.ListMachinesResults{
+		Machines: make([]params.ListedMachine, len(machines)),
+	}
+	for i, m := range machines {
+		listed, err := listMachine(m)
+		if err != nil {
+			return params.ListMachinesResults{}, errors.Annotatef(err, "machine %s", m.Id())
+		}
+		result.Machines[i] = listed
+	}
+	return result, nil
+}
+
+func listMachine(m *state.Machine) (params.ListedMachine, error) {
+	listed := params.ListedMachine{
+		Id:      m.Id(),
+		Series:  m.Series(),
+		Life:    params.Life(m.Life().String()),
+		DNSName: network.SelectPublicAddress(m.Addresses()),
+	}
+	instId, err := m.InstanceId()
+	if err != nil && !errors.IsNotProvisioned(err) {
+		return params.ListedMachine{}, errors.Trace(err)
+	}
+	listed.InstanceId = instId
+	status, err := m.Status()
+	if err != nil {
+		return params.ListedMachine{}, errors.Trace(err)
+	}
+	listed.Status = params.Status(status.Status)
+	listed.StatusInfo = status.Message
+	for _, job := range m.Jobs() {
+		listed.Jobs = append(listed.Jobs, job.ToParams())
+	}
+	if listed.Jobs == nil {
+		listed.Jobs = []multiwatcher.MachineJob{}
+	}
+	return listed, nil
+}
+
+// ListUnits implements Listing.ListUnits().
+func (a *API) ListUnits() (params.ListUnitsResults, error) {
+	services, err := a.st.AllServices()
+	if err != nil {
+		return params.ListUnitsResults{}, errors.Trace(err)
+	}
+	result := params.ListUnitsResults{
+		Units: []params.ListedUnit{},
+	}
+	for _, service := range services {
+		units, err := service.AllUnits()
+		if err != nil {
+			return params.ListUnitsResults{}, errors.Annotatef(err, "service %s", service.Name())
+		}
+		for _, u := range units {
+			listed, err := listUnit(u)
+			if err != nil {
+				return params.ListUnitsResults{}, errors.Annotatef(err, "unit %s", u.Name())
+			}
+			result.Units = append(result.Units, listed)
+		}
+	}
+	sort.Sort(byUnitName(result.Units))
+	return result, nil
+}
+
+func listUnit(u *state.Unit) (params.ListedUnit, error) {
+	listed := params.ListedUnit{
+		Name:    u.Name(),
+		Service: u.ServiceName(),
+		Life:    params.Life(u.Life().String()),
+	}
+	machineId, err := u.AssignedMachineId()
+	if err != nil && !errors.IsNotAssigned(err) {
+		return params.ListedUnit{}, errors.Trace(err)
+	}
+	listed.Machine = machineId
+	listed.PublicAddress, _ = u.PublicAddress()
+	agentStatus, err := u.AgentStatus()
+	if err != nil {
+		return params.ListedUnit{}, errors.Trace(err)
+	}
+	listed.AgentStatus = params.Status(agentStatus.Status)
+	workloadStatus, err := u.Status()
+	if err != nil {
+		return params.ListedUnit{}, errors.Trace(err)
+	}
+	listed.WorkloadStatus = params.Status(workloadStatus.Status)
+	listed.StatusInfo = workloadStatus.Message
+	return listed, nil
+}
+
+// byUnitName sorts units by service name and then by unit number.
+type byUnitName []params.ListedUnit
+
+func (u byUnitName) Len() int      { return len(u) }
+func (u byUnitName) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byUnitName) Less(i, j int) bool {
+	service0, number0 := splitUnitName(u[i].Name)
+	service1, number1 := splitUnitName(u[j].Name)
+	if service0 != service1 {
+		return service0 < service1
+	}
+	return number0 < number1
+}
+
+func splitUnitName(name string) (string, int) {
+	i := strings.LastIndex(name, "/")
+	number, _ := strconv.Atoi(name[i+1:])
+	return name[:i], number
+}
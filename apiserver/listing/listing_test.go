@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package listing_test
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/listing"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/testing/factory"
+)
+
+type listingSuite struct {
+	jujutesting.JujuConnSuite
+	api *listing.API
+}
+
+var _ = gc.Suite(&listingSuite{})
+
+func (s *listingSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = listing.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *listingSuite) TestNewAPIRefusesAgents(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := listing.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *listingSuite) TestListMachinesEmpty(c *gc.C) {
+	results, err := s.api.ListMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, gc.HasLen, 0)
+}
+
+func (s *listingSuite) TestListMachines(c *gc.C) {
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		InstanceId: "i-0",
+		Jobs:       []state.MachineJob{state.JobHostUnits},
+		Addresses:  []network.Address{network.NewScopedAddress("0.1.2.3", network.ScopePublic)},
+	})
+	pending, err := s.State.AddMachine("trusty", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = pending.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, jc.DeepEquals, []params.ListedMachine{{
+		Id:         "0",
+		InstanceId: "i-0",
+		Series:     "quantal",
+		Life:       "alive",
+		Status:     params.StatusPending,
+		DNSName:    "0.1.2.3",
+		Jobs:       []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}, {
+		Id:     "1",
+		Series: "trusty",
+		Life:   "dying",
+		Status: params.StatusPending,
+		Jobs:   []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}})
+}
+
+func (s *listingSuite) TestListUnitsEmpty(c *gc.C) {
+	results, err := s.api.ListUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Units, gc.HasLen, 0)
+}
+
+func (s *listingSuite) TestListUnits(c *gc.C) {
+	service := s.Factory.MakeService(c, nil)
+	machine := s.Factory.MakeMachine(c, nil)
+	for i := 0; i < 11; i++ {
+		s.Factory.MakeUnit(c, &factory.UnitParams{
+			Service: service,
+			Machine: machine,
+		})
+	}
+	unassigned, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Units, gc.HasLen, 12)
+
+	// Units are ordered by number, not lexically.
+	var unitNames []string
+	var expectNames []string
+	for i, unit := range results.Units {
+		unitNames = append(unitNames, unit.Name)
+		expectNames = append(expectNames, fmt.Sprintf("mysql/%d", i))
+	}
+	c.Assert(unitNames, jc.DeepEquals, expectNames)
+
+	c.Check(results.Units[0].Service, gc.Equals, "mysql")
+	c.Check(results.Units[0].Machine, gc.Equals, machine.Id())
+	c.Check(results.Units[0].Life, gc.Equals, params.Life("alive"))
+	c.Check(results.Units[11].Name, gc.Equals, unassigned.Name())
+	c.Check(results.Units[11].Machine, gc.Equals, "")
+}
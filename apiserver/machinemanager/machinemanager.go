@@ -148,6 +148,7 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		HardwareCharacteristics: p.HardwareCharacteristics,
 		Addresses:               params.NetworkAddresses(p.Addrs),
 		Placement:               placementDirective,
+		Labels:                  p.Labels,
 	}
 	if p.ContainerType == "" {
 		return mm.st.AddOneMachine(template)
@@ -23,6 +23,9 @@ var (
 // DefaultSender is the default used for sending
 // metrics to the collector service.
 type DefaultSender struct {
+	// URL holds the address of the collector service. If it is
+	// empty, the built-in collector address is used.
+	URL string
 }
 
 // Send sends the given metrics to the collector service.
@@ -34,7 +37,11 @@ func (s *DefaultSender) Send(metrics []*wireformat.MetricBatch) (*wireformat.Res
 	r := bytes.NewBuffer(b)
 	t := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: metricsCertsPool}}
 	client := &http.Client{Transport: t}
-	resp, err := client.Post(metricsHost, "application/json", r)
+	host := s.URL
+	if host == "" {
+		host = metricsHost
+	}
+	resp, err := client.Post(host, "application/json", r)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -10,3 +10,13 @@ import (
 func PatchSender(s metricsender.MetricSender) {
 	sender = s
 }
+
+func PatchCollectorSender(s metricsender.MetricSender) func() {
+	restore := newCollectorSender
+	newCollectorSender = func(string) metricsender.MetricSender {
+		return s
+	}
+	return func() {
+		newCollectorSender = restore
+	}
+}
@@ -21,6 +21,12 @@ var (
 	maxBatchesPerSend = 1000
 
 	sender metricsender.MetricSender = &metricsender.NopSender{}
+
+	// newCollectorSender returns the sender used to send metrics
+	// to the collector configured for the environment.
+	newCollectorSender = func(url string) metricsender.MetricSender {
+		return &metricsender.DefaultSender{URL: url}
+	}
 )
 
 func init() {
@@ -115,6 +121,10 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 	if err != nil {
 		return result, err
 	}
+	metricSender, err := api.metricSender()
+	if err != nil {
+		return result, err
+	}
 	for i, arg := range args.Entities {
 		tag, err := names.ParseEnvironTag(arg.Tag)
 		if err != nil {
@@ -125,7 +135,7 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 			result.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
-		err = metricsender.SendMetrics(api.state, sender, maxBatchesPerSend)
+		err = metricsender.SendMetrics(api.state, metricSender, maxBatchesPerSend)
 		if err != nil {
 			err = errors.Annotate(err, "failed to send metrics")
 			logger.Warningf("%v", err)
@@ -135,3 +145,16 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 	}
 	return result, nil
 }
+
+// metricSender returns the sender used to send metrics from the
+// environment: the configured collector, if there is one.
+func (api *MetricsManagerAPI) metricSender() (metricsender.MetricSender, error) {
+	cfg, err := api.state.EnvironConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get environment config")
+	}
+	if url, ok := cfg.MetricsCollectorURL(); ok {
+		return newCollectorSender(url), nil
+	}
+	return sender, nil
+}
@@ -136,6 +136,29 @@ func (s *metricsManagerSuite) TestSendMetrics(c *gc.C) {
 	c.Assert(m.Sent(), jc.IsTrue)
 }
 
+func (s *metricsManagerSuite) TestSendMetricsToConfiguredCollector(c *gc.C) {
+	var defaultSender, collectorSender testing.MockSender
+	metricsmanager.PatchSender(&defaultSender)
+	restore := metricsmanager.PatchCollectorSender(&collectorSender)
+	defer restore()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"metrics-collector-url": "https://metrics.example.com/metrics",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now()
+	metric := state.Metric{"pings", "5", now}
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false, Time: &now, Metrics: []state.Metric{metric}})
+	args := params.Entities{Entities: []params.Entity{
+		{s.State.EnvironTag().String()},
+	}}
+	result, err := s.metricsmanager.SendMetrics(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0], gc.DeepEquals, params.ErrorResult{Error: nil})
+	c.Assert(collectorSender.Data, gc.HasLen, 1)
+	c.Assert(defaultSender.Data, gc.HasLen, 0)
+}
+
 func (s *metricsManagerSuite) TestSendOldMetricsInvalidArg(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{"invalid"},
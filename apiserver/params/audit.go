@@ -0,0 +1,42 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AuditLogFilter holds the parameters for querying the audit log.
+// Zero-valued fields do not restrict the entries returned.
+type AuditLogFilter struct {
+	// Caller restricts entries to calls made by the entity
+	// with the given tag.
+	Caller string `json:"caller,omitempty"`
+
+	// Facade restricts entries to calls made on the given facade.
+	Facade string `json:"facade,omitempty"`
+
+	// Since restricts entries to calls made after the given time.
+	Since *time.Time `json:"since,omitempty"`
+
+	// Limit restricts the number of entries returned.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditLogEntry describes a single mutating API call recorded
+// in the audit log.
+type AuditLogEntry struct {
+	Time    time.Time `json:"time"`
+	Caller  string    `json:"caller"`
+	Facade  string    `json:"facade"`
+	Version int       `json:"version"`
+	Method  string    `json:"method"`
+	Args    string    `json:"args,omitempty"`
+	Result  string    `json:"result,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// AuditLogResults holds the entries of the audit log matching
+// a query, most recent first.
+type AuditLogResults struct {
+	Entries []AuditLogEntry `json:"entries"`
+}
@@ -47,6 +47,40 @@ type ModifyEnvironUser struct {
 	Action  EnvironAction `json:"action"`
 }
 
+// EnvironAccess is the level of access a user has to an environment.
+type EnvironAccess string
+
+// Levels of access a user may have to an environment. Users with
+// read access may only make calls that do not change the environment.
+const (
+	EnvironReadAccess  EnvironAccess = "read"
+	EnvironWriteAccess EnvironAccess = "write"
+)
+
+// EnvironAccessAction is an action that changes a user's access to
+// an environment.
+type EnvironAccessAction string
+
+// Actions that change a user's access to an environment.
+const (
+	GrantEnvironAccess  EnvironAccessAction = "grant"
+	RevokeEnvironAccess EnvironAccessAction = "revoke"
+)
+
+// ModifyEnvironAccess holds the parameters for making Client
+// ModifyEnvironAccess calls.
+type ModifyEnvironAccess struct {
+	Changes []ModifyEnvironUserAccess
+}
+
+// ModifyEnvironUserAccess stores the parameters used to change one
+// user's access to an environment.
+type ModifyEnvironUserAccess struct {
+	UserTag string              `json:"user-tag"`
+	Action  EnvironAccessAction `json:"action"`
+	Access  EnvironAccess       `json:"access"`
+}
+
 // SetEnvironAgentVersion contains the arguments for
 // SetEnvironAgentVersion client API call.
 type SetEnvironAgentVersion struct {
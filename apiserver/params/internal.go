@@ -227,6 +227,20 @@ type RelationUnitPairs struct {
 	RelationUnitPairs []RelationUnitPair
 }
 
+// RelationUnitService holds a relation tag, a local unit tag and
+// the tag of a service taking part in the relation.
+type RelationUnitService struct {
+	Relation  string
+	LocalUnit string
+	Service   string
+}
+
+// RelationUnitServices holds the parameters for API calls expecting
+// multiple sets of a relation tag, a local unit tag and a service tag.
+type RelationUnitServices struct {
+	RelationUnitServices []RelationUnitService
+}
+
 // RelationUnitSettings holds a relation tag, a unit tag and local
 // unit settings.
 type RelationUnitSettings struct {
@@ -360,6 +374,149 @@ type StatusHistory struct {
 	Name string
 }
 
+// UnitEventKind identifies the kind of an event in a unit's event log.
+type UnitEventKind string
+
+const (
+	UnitEventHookStarted   UnitEventKind = "hook-started"
+	UnitEventHookCompleted UnitEventKind = "hook-completed"
+	UnitEventHookFailed    UnitEventKind = "hook-failed"
+)
+
+// UnitEvent describes something that happened to a unit's workload,
+// such as the execution of a hook.
+type UnitEvent struct {
+	Kind     UnitEventKind
+	Hook     string
+	Time     time.Time
+	Duration time.Duration
+	Message  string
+}
+
+// UnitEventsParam holds the events to record for a single unit.
+type UnitEventsParam struct {
+	Tag    string
+	Events []UnitEvent
+}
+
+// UnitEventsParams holds the events to record for several units.
+type UnitEventsParams struct {
+	Units []UnitEventsParam
+}
+
+// UnitEventsQuery holds the parameters to query the event logs of
+// several units. At most Size events are returned for each unit.
+type UnitEventsQuery struct {
+	Entities []Entity
+	Size     int
+}
+
+// UnitEventsResult holds the most recent events in a unit's event
+// log, newest first, or an error.
+type UnitEventsResult struct {
+	Events []UnitEvent
+	Error  *Error
+}
+
+// UnitEventsResults holds the results of a UnitEventsQuery.
+type UnitEventsResults struct {
+	Results []UnitEventsResult
+}
+
+// UnitStatusEntry holds a current or past status of a unit's
+// workload or agent.
+type UnitStatusEntry struct {
+	Status Status
+	Info   string
+	Since  *time.Time
+}
+
+// UnitRelationDetails describes a relation in which a unit is in
+// scope. SettingsDigest is a digest of the unit's settings in the
+// relation, so that changes can be spotted without showing them.
+type UnitRelationDetails struct {
+	Key            string
+	Endpoint       string
+	Interface      string
+	SettingsDigest string
+}
+
+// UnitDetails holds everything known about a single unit.
+type UnitDetails struct {
+	Service               string
+	Machine               string
+	PublicAddress         string
+	PrivateAddress        string
+	Leader                bool
+	OpenedPorts           []PortRange
+	Relations             []UnitRelationDetails
+	WorkloadStatusHistory []UnitStatusEntry
+	AgentStatusHistory    []UnitStatusEntry
+}
+
+// UnitDetailsQuery holds the parameters to query the details of
+// several units. At most HistorySize entries of each status history
+// are returned for each unit.
+type UnitDetailsQuery struct {
+	Entities    []Entity
+	HistorySize int
+}
+
+// UnitDetailsResult holds the details of a unit, or an error.
+type UnitDetailsResult struct {
+	Details *UnitDetails
+	Error   *Error
+}
+
+// UnitDetailsResults holds the results of a UnitDetailsQuery.
+type UnitDetailsResults struct {
+	Results []UnitDetailsResult
+}
+
+// WorkerHealth describes the health of one of an agent's workers.
+type WorkerHealth struct {
+	Name          string
+	Running       bool
+	Restarts      int
+	LastError     string `json:",omitempty"`
+	LastErrorTime time.Time
+	Failed        bool
+}
+
+// AgentWorkerHealth holds the health of the workers of the agent
+// with the given tag. Updated holds when that health last changed,
+// and Reported when the agent last reported it.
+type AgentWorkerHealth struct {
+	Tag      string
+	Updated  time.Time
+	Reported time.Time
+	Workers  []WorkerHealth
+}
+
+// SetWorkerHealth holds the arguments for making a SetWorkerHealth
+// API call. The Updated and Reported fields of each report are
+// ignored.
+type SetWorkerHealth struct {
+	Agents []AgentWorkerHealth
+}
+
+// AgentWorkerHealthResults holds the worker health reports
+// of a number of agents.
+type AgentWorkerHealthResults struct {
+	Results []AgentWorkerHealth
+}
+
+// ServerChecks holds the results of the checks an API server makes
+// of its own host, for diagnosing problems.
+type ServerChecks struct {
+	// Time holds the API server's clock when the checks were made,
+	// so that clients can detect clock skew.
+	Time time.Time
+
+	// Problems describes each problem found with the host.
+	Problems []string
+}
+
 // StatusResult holds an entity status, extra information, or an
 // error.
 type StatusResult struct {
@@ -553,6 +710,18 @@ type ProvisioningInfo struct {
 	Networks    []string
 	Jobs        []multiwatcher.MachineJob
 	Volumes     []VolumeParams
+
+	// Tags holds the tags that should be applied to the instance
+	// by the provider. It is only populated by version 1 and later
+	// of the Provisioner facade.
+	Tags map[string]string
+
+	// InstanceName holds the display name that should be given to
+	// the instance by providers that support naming instances (only
+	// EC2 currently), as built from the environment's
+	// instance-name-template. It is
+	// only populated by version 1 and later of the Provisioner facade.
+	InstanceName string `json:",omitempty"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -412,3 +412,30 @@ type APIHostPortsResult struct {
 func (r APIHostPortsResult) NetworkHostsPorts() [][]network.HostPort {
 	return NetworkHostsPorts(r.Servers)
 }
+
+// IPAddressValues holds the values of a number of IP addresses.
+type IPAddressValues struct {
+	Values []string `json:"Values"`
+}
+
+// IPAddressInfo holds the details of an IP address used by the
+// addresser worker.
+type IPAddressInfo struct {
+	Value     string  `json:"Value"`
+	Life      Life    `json:"Life"`
+	MachineId string  `json:"MachineId"`
+	SubnetId  string  `json:"SubnetId"`
+	Address   Address `json:"Address"`
+}
+
+// IPAddressResult holds the details of an IP address or an error.
+type IPAddressResult struct {
+	Error  *Error         `json:"Error"`
+	Result *IPAddressInfo `json:"Result"`
+}
+
+// IPAddressResults holds the results of an Addresser.IPAddresses
+// API call.
+type IPAddressResults struct {
+	Results []IPAddressResult `json:"Results"`
+}
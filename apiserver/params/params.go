@@ -109,6 +109,142 @@ type AddRelationResults struct {
 	Endpoints map[string]charm.Relation
 }
 
+// RelationEndpointsQuery holds the endpoints of a relation that might be
+// added by an AddRelation call. If Interface is set, only relations using
+// that interface are considered.
+type RelationEndpointsQuery struct {
+	Endpoints []string
+	Interface string
+}
+
+// RelationEndpointsQueries holds the parameters for making the
+// InferRelationEndpoints call.
+type RelationEndpointsQueries struct {
+	Queries []RelationEndpointsQuery
+}
+
+// RelationEndpointsResult holds the endpoints that would be related for
+// a single RelationEndpointsQuery, or the reason why none could be chosen.
+// The Endpoints field maps service names to the involved endpoints.
+type RelationEndpointsResult struct {
+	Endpoints map[string]charm.Relation
+	Error     *Error
+}
+
+// RelationEndpointsResults holds the results of an InferRelationEndpoints
+// call.
+type RelationEndpointsResults struct {
+	Results []RelationEndpointsResult
+}
+
+// CharmOrigin records the charm store that a service's charm was
+// deployed from.
+type CharmOrigin struct {
+	StoreURL string
+}
+
+// ServiceCharmOrigin holds the charm origin of a service. A nil
+// Origin means the origin is not known.
+type ServiceCharmOrigin struct {
+	ServiceName string
+	Origin      *CharmOrigin
+}
+
+// ServiceCharmOrigins holds the parameters for making the
+// SetCharmOrigins call.
+type ServiceCharmOrigins struct {
+	Origins []ServiceCharmOrigin
+}
+
+// CharmOriginResult holds the charm origin of a service, or an error.
+type CharmOriginResult struct {
+	Origin *CharmOrigin
+	Error  *Error
+}
+
+// CharmOriginResults holds the results of a CharmOrigins call.
+type CharmOriginResults struct {
+	Results []CharmOriginResult
+}
+
+// ServiceUnitCount holds the number of units a service should have.
+// When units must be removed to reach NumUnits, the most recently
+// added units are removed first, skipping any assigned to one of
+// ExcludeMachines.
+type ServiceUnitCount struct {
+	ServiceName     string
+	NumUnits        int
+	ExcludeMachines []string `json:",omitempty"`
+}
+
+// ServiceUnitCounts holds the parameters for making the
+// SetServiceUnitCounts call.
+type ServiceUnitCounts struct {
+	Services []ServiceUnitCount
+}
+
+// ServiceUnitCountResult holds the names of the units added to and
+// removed from a service by SetServiceUnitCounts, or an error.
+type ServiceUnitCountResult struct {
+	Added   []string
+	Removed []string
+	Error   *Error
+}
+
+// ServiceUnitCountResults holds the results of a
+// SetServiceUnitCounts call.
+type ServiceUnitCountResults struct {
+	Results []ServiceUnitCountResult
+}
+
+// ServiceConfigChanges holds configuration changes for a service.
+// Options holds the values to set, as for ServiceSet, and Unset holds
+// the names of options to reset to their defaults.
+type ServiceConfigChanges struct {
+	ServiceName string
+	Options     map[string]string `json:",omitempty"`
+	Unset       []string          `json:",omitempty"`
+}
+
+// ServicesConfigChanges holds the parameters for making the
+// SetServicesConfig call. The changes are applied all together or
+// not at all.
+type ServicesConfigChanges struct {
+	Changes []ServiceConfigChanges
+}
+
+// ServiceRemovalProgress describes what remains to be removed before a
+// service that is being destroyed is gone.
+type ServiceRemovalProgress struct {
+	// Removed is true once the service itself has been removed.
+	Removed bool
+
+	// Units holds the names of the service's remaining units.
+	Units []string
+
+	// Relations holds the keys of the service's remaining relations,
+	// which wait on relation-broken hooks.
+	Relations []string
+
+	// Machines holds the ids of the machines still hosting units of
+	// the service. They are not removed with the service, so are
+	// only pending stop by the user.
+	Machines []string
+}
+
+// ServiceRemovalProgressResult holds the removal progress of a
+// service, or an error.
+type ServiceRemovalProgressResult struct {
+	Progress *ServiceRemovalProgress
+	Error    *Error
+}
+
+// ServiceRemovalProgressResults holds the results of a
+// ServiceRemovalProgress call.
+type ServiceRemovalProgressResults struct {
+	Results []ServiceRemovalProgressResult
+}
+
 // DestroyRelation holds the parameters for making the DestroyRelation call.
 // The endpoints specified are unordered.
 type DestroyRelation struct {
@@ -133,6 +269,9 @@ type AddMachineParams struct {
 	// the machine when it is provisioned.
 	Disks []storage.Constraints `json:"Disks"`
 
+	// Labels holds the user-defined labels of the new machine.
+	Labels []string `json:"Labels,omitempty"`
+
 	// If Placement is non-nil, it contains a placement directive
 	// that will be used to decide how to instantiate the machine.
 	Placement *instance.Placement `json:"Placement"`
@@ -168,6 +307,19 @@ type AddMachines struct {
 	MachineParams []AddMachineParams `json:"MachineParams"`
 }
 
+// AddIdenticalMachines holds the parameters for making the
+// AddIdenticalMachines call, which adds NumMachines machines
+// configured according to MachineParams in a single transaction.
+type AddIdenticalMachines struct {
+	MachineParams AddMachineParams `json:"MachineParams"`
+	NumMachines   int              `json:"NumMachines"`
+}
+
+// MaxIdenticalMachines holds the largest number of machines that a
+// single AddIdenticalMachines call may add, which keeps the size of
+// its transaction bounded.
+const MaxIdenticalMachines = 100
+
 // AddMachinesResults holds the results of an AddMachines call.
 type AddMachinesResults struct {
 	Machines []AddMachinesResult `json:"Machines"`
@@ -186,6 +338,18 @@ type DestroyMachines struct {
 	Force        bool
 }
 
+// MachineLabels holds the user-defined labels of a machine.
+type MachineLabels struct {
+	MachineTag string
+	Labels     []string
+}
+
+// SetMachineLabels holds the parameters for making the
+// SetMachineLabels call.
+type SetMachineLabels struct {
+	Machines []MachineLabels
+}
+
 // ServicesDeploy holds the parameters for deploying one or more services.
 type ServicesDeploy struct {
 	Services []ServiceDeploy
@@ -202,6 +366,10 @@ type ServiceDeploy struct {
 	ToMachineSpec string
 	Networks      []string
 	Storage       map[string]storage.Constraints
+
+	// AssignmentPolicy, if set, overrides the environment's
+	// unit-assignment-policy for units of the service.
+	AssignmentPolicy string `json:",omitempty"`
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -310,6 +478,28 @@ type PrivateAddressResults struct {
 	PrivateAddress string
 }
 
+// SSHHostKeys holds parameters for the SSHHostKeys call.
+type SSHHostKeys struct {
+	Target string
+}
+
+// SSHHostKeysResults holds results of the SSHHostKeys call.
+type SSHHostKeysResults struct {
+	PublicKeys []string
+}
+
+// EntitySSHHostKeys holds the public SSH host keys of an entity.
+type EntitySSHHostKeys struct {
+	Tag        string
+	PublicKeys []string
+}
+
+// SSHHostKeySet holds the parameters for reporting the public SSH
+// host keys of one or more entities.
+type SSHHostKeySet struct {
+	EntityKeys []EntitySSHHostKeys
+}
+
 // Resolved holds parameters for the Resolved call.
 type Resolved struct {
 	UnitName string
@@ -359,6 +549,11 @@ type LoginRequest struct {
 	AuthTag     string `json:"auth-tag"`
 	Credentials string `json:"credentials"`
 	Nonce       string `json:"nonce"`
+
+	// Macaroons holds macaroons discharged by the environment's
+	// external identity service. They are used to authenticate the
+	// user when AuthTag is empty.
+	Macaroons []macaroon.Slice `json:"macaroons,omitempty"`
 }
 
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
@@ -608,6 +803,10 @@ type AuthUserInfo struct {
 	// Credentials contains an optional opaque credential value to be held by
 	// the client, if any.
 	Credentials *string `json:"credentials,omitempty"`
+
+	// ReadOnly is true if the user may only make calls that do not
+	// change the environment.
+	ReadOnly bool `json:"read-only,omitempty"`
 }
 
 // LoginRequestV1 holds the result of an Admin v1 Login call.
@@ -636,6 +835,16 @@ type LoginResultV1 struct {
 	// ServerVersion is the string representation of the server version
 	// if the server supports it.
 	ServerVersion string `json:"server-version,omitempty"`
+
+	// DischargeRequired holds a macaroon that must be discharged by
+	// the external identity service before the user can log in. When
+	// it is set, the login has not succeeded and the client should
+	// retry the login with the discharged macaroon.
+	DischargeRequired *macaroon.Macaroon `json:"discharge-required,omitempty"`
+
+	// DischargeRequiredReason holds the reason that the macaroons
+	// presented at login, if any, were not accepted.
+	DischargeRequiredReason string `json:"discharge-required-reason,omitempty"`
 }
 
 // StateServersSpec contains arguments for
@@ -700,6 +909,11 @@ type FindToolsParams struct {
 
 	// Series will be used to match tools by series if non-empty.
 	Series string
+
+	// AgentStream will be used to choose the simplestreams stream
+	// searched for tools if non-empty, in place of the environment's
+	// agent-stream setting.
+	AgentStream string
 }
 
 // FindToolsResult holds a list of tools from FindTools and any error.
@@ -856,3 +1070,92 @@ const (
 	// been asked to offer.
 	StatusActive Status = "active"
 )
+
+// ServerStats holds live statistics about the load on an API server.
+type ServerStats struct {
+	// Connections holds the number of open API connections by the
+	// kind of entity logged in on them (e.g. "user", "machine",
+	// "unit"), or "unauthenticated" for connections not yet logged in.
+	Connections map[string]int
+
+	// Resources holds the number of watchers and other resources
+	// held on behalf of all open API connections, by resource type.
+	Resources map[string]int
+
+	// Mongo holds the server's mongo connection and operation counts.
+	Mongo MongoStats
+}
+
+// MongoStats holds the counters kept by the mongo driver for all the
+// mongo sessions of the state server agent, since it started serving
+// the API.
+type MongoStats struct {
+	Clusters     int
+	MasterConns  int
+	SlaveConns   int
+	SentOps      int
+	ReceivedOps  int
+	ReceivedDocs int
+	SocketsAlive int
+	SocketsInUse int
+	SocketRefs   int
+}
+
+// ListedMachine holds the summary of a machine returned by the
+// Listing facade. Unlike the status document, its layout is kept
+// stable so that it can be relied upon by scripts.
+type ListedMachine struct {
+	Id         string                    `json:"id"`
+	InstanceId instance.Id               `json:"instance-id"`
+	Series     string                    `json:"series"`
+	Life       Life                      `json:"life"`
+	Status     Status                    `json:"status"`
+	StatusInfo string                    `json:"status-info"`
+	DNSName    string                    `json:"dns-name"`
+	Jobs       []multiwatcher.MachineJob `json:"jobs"`
+}
+
+// ListMachinesResults holds the machines in an environment.
+type ListMachinesResults struct {
+	Machines []ListedMachine `json:"machines"`
+}
+
+// ListedUnit holds the summary of a unit returned by the Listing
+// facade. Unlike the status document, its layout is kept stable so
+// that it can be relied upon by scripts.
+type ListedUnit struct {
+	Name           string `json:"name"`
+	Service        string `json:"service"`
+	Machine        string `json:"machine"`
+	Life           Life   `json:"life"`
+	AgentStatus    Status `json:"agent-status"`
+	WorkloadStatus Status `json:"workload-status"`
+	StatusInfo     string `json:"status-info"`
+	PublicAddress  string `json:"public-address"`
+}
+
+// ListUnitsResults holds the units in an environment.
+type ListUnitsResults struct {
+	Units []ListedUnit `json:"units"`
+}
+
+// Inconsistency describes a document in state that refers to another
+// document that no longer exists.
+type Inconsistency struct {
+	// Kind identifies the kind of inconsistency.
+	Kind string `json:"kind"`
+
+	// Entity identifies the document holding the dangling reference.
+	Entity string `json:"entity"`
+
+	// Missing identifies the document referred to that no longer exists.
+	Missing string `json:"missing"`
+
+	// Message describes the inconsistency.
+	Message string `json:"message,omitempty"`
+}
+
+// Inconsistencies holds a list of inconsistencies found in state.
+type Inconsistencies struct {
+	Inconsistencies []Inconsistency `json:"inconsistencies"`
+}
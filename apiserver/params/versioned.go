@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Params structs are shared between all versions of a facade, so that
+// bumping a facade version does not require duplicating every struct
+// it uses. Instead, fields that are not part of every version carry a
+// "version" struct tag giving the range of facade versions that send
+// them:
+//
+//	type MachineInfo struct {
+//		Id string
+//		// Address is replaced by Addresses in version 2.
+//		Address   string    `json:",omitempty" version:"-1"`
+//		Addresses []Address `json:",omitempty" version:"2-"`
+//	}
+//
+// The tag takes the form "N-" (version N onwards), "-M" (up to and
+// including version M) or "N-M". Fields without a tag are sent by all
+// versions.
+//
+// The following rules keep old and new clients working:
+//
+//   - Fields added to an existing struct must be tagged "omitempty",
+//     and clients must treat the zero value as meaning that the server
+//     predates the field.
+//   - Deprecated fields are kept, and still populated by the server,
+//     until no supported client version reads them; their version tag
+//     records the last facade version that sends them.
+//   - A facade populates every field and calls ForVersion on its
+//     result before returning it, so each version sends only the
+//     fields it declares.
+
+// ForVersion zeroes every field of the struct pointed to by v, and of
+// any structs it contains, whose version tag excludes the given facade
+// version. Combined with "omitempty", this removes the field from the
+// wire format of that version.
+func ForVersion(v interface{}, version int) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.Errorf("expected non-nil pointer, got %T", v)
+	}
+	return forVersion(rv.Elem(), version)
+}
+
+func forVersion(v reflect.Value, version int) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return forVersion(v.Elem(), version)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := forVersion(v.Index(i), version); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map values are not addressable, so structs held
+		// directly in a map are left alone; pointers to structs
+		// are handled as usual.
+		for _, key := range v.MapKeys() {
+			if err := forVersion(v.MapIndex(key), version); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				// Unexported fields are not sent.
+				continue
+			}
+			fv := v.Field(i)
+			if tag := field.Tag.Get("version"); tag != "" {
+				ok, err := versionInRange(tag, version)
+				if err != nil {
+					return errors.Annotatef(err, "field %s.%s", t.Name(), field.Name)
+				}
+				if !ok {
+					if fv.CanSet() {
+						fv.Set(reflect.Zero(field.Type))
+					}
+					continue
+				}
+			}
+			if err := forVersion(fv, version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// versionInRange reports whether version lies within the range
+// described by the given version tag.
+func versionInRange(tag string, version int) (bool, error) {
+	parts := strings.Split(tag, "-")
+	if len(parts) != 2 || parts[0] == "" && parts[1] == "" {
+		return false, errors.NotValidf("version tag %q", tag)
+	}
+	if parts[0] != "" {
+		since, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return false, errors.NotValidf("version tag %q", tag)
+		}
+		if version < since {
+			return false, nil
+		}
+	}
+	if parts[1] != "" {
+		until, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return false, errors.NotValidf("version tag %q", tag)
+		}
+		if version > until {
+			return false, nil
+		}
+	}
+	return true, nil
+}
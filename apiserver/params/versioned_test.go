@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params_test
+
+import (
+	"encoding/json"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type VersionedSuite struct{}
+
+var _ = gc.Suite(&VersionedSuite{})
+
+type versionedEntity struct {
+	Id        string
+	Address   string   `json:",omitempty" version:"-1"`
+	Addresses []string `json:",omitempty" version:"2-"`
+	Spaces    []string `json:",omitempty" version:"2-3"`
+	Child     *versionedEntity
+}
+
+type versionedResults struct {
+	Results []versionedEntity
+	ByName  map[string]*versionedEntity
+}
+
+func newVersionedEntity(id string) versionedEntity {
+	return versionedEntity{
+		Id:        id,
+		Address:   "10.0.0.1",
+		Addresses: []string{"10.0.0.1", "10.0.0.2"},
+		Spaces:    []string{"default"},
+	}
+}
+
+func (s *VersionedSuite) TestForVersion(c *gc.C) {
+	for i, test := range []struct {
+		version int
+		json    string
+	}{{
+		version: 1,
+		json:    `{"Id":"0","Address":"10.0.0.1","Child":null}`,
+	}, {
+		version: 2,
+		json:    `{"Id":"0","Addresses":["10.0.0.1","10.0.0.2"],"Spaces":["default"],"Child":null}`,
+	}, {
+		version: 4,
+		json:    `{"Id":"0","Addresses":["10.0.0.1","10.0.0.2"],"Child":null}`,
+	}} {
+		c.Logf("test %d: version %d", i, test.version)
+		entity := newVersionedEntity("0")
+		err := params.ForVersion(&entity, test.version)
+		c.Assert(err, jc.ErrorIsNil)
+		data, err := json.Marshal(entity)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(string(data), gc.Equals, test.json)
+	}
+}
+
+func (s *VersionedSuite) TestForVersionNested(c *gc.C) {
+	child := newVersionedEntity("1")
+	parent := newVersionedEntity("0")
+	parent.Child = &child
+	byName := newVersionedEntity("2")
+	results := versionedResults{
+		Results: []versionedEntity{parent},
+		ByName:  map[string]*versionedEntity{"2": &byName},
+	}
+	err := params.ForVersion(&results, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, entity := range []*versionedEntity{&results.Results[0], results.Results[0].Child, results.ByName["2"]} {
+		c.Check(entity.Address, gc.Equals, "10.0.0.1")
+		c.Check(entity.Addresses, gc.IsNil)
+		c.Check(entity.Spaces, gc.IsNil)
+	}
+}
+
+func (s *VersionedSuite) TestForVersionNotPointer(c *gc.C) {
+	err := params.ForVersion(newVersionedEntity("0"), 1)
+	c.Assert(err, gc.ErrorMatches, `expected non-nil pointer, got params_test.versionedEntity`)
+}
+
+type invalidVersionTag struct {
+	Field string `version:"a-"`
+}
+
+func (s *VersionedSuite) TestForVersionInvalidTag(c *gc.C) {
+	err := params.ForVersion(&invalidVersionTag{}, 1)
+	c.Assert(err, gc.ErrorMatches, `field invalidVersionTag.Field: version tag "a-" not valid`)
+}
@@ -125,6 +125,52 @@ func (p *ProvisionerAPI) getMachine(canAccess common.AuthFunc, tag names.Machine
 	return entity.(*state.Machine), nil
 }
 
+// SetPasswords sets the given password for each supplied machine. The
+// passwords are delivered to the machines when they are provisioned, so
+// they are recorded as one-time initial passwords, which the machine
+// agents must exchange for permanent passwords when they first log in.
+func (p *ProvisionerAPI) SetPasswords(args params.EntityPasswords) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if len(args.Changes) == 0 {
+		return result, nil
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err == nil {
+			err = p.setInitialPassword(machine, arg.Password)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (p *ProvisionerAPI) setInitialPassword(m *state.Machine, password string) error {
+	if m.IsManager() {
+		// As in common.PasswordChanger, the mongo password is set
+		// first so that a failure leaves the agent able to
+		// authenticate to the API and try again.
+		if err := m.SetMongoPassword(password); err != nil {
+			return err
+		}
+	}
+	if m.Tag() == p.authorizer.GetAuthTag() {
+		// A machine agent's own password is never provisioned.
+		return m.SetPassword(password)
+	}
+	return m.SetInitialPassword(password)
+}
+
 func (p *ProvisionerAPI) watchOneMachineContainers(arg params.WatchContainer) (params.StringsWatchResult, error) {
 	nothing := params.StringsWatchResult{}
 	canAccess, err := p.getAuthFunc()
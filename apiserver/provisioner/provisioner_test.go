@@ -157,6 +157,7 @@ func (s *withoutStateServerSuite) TestSetPasswords(c *gc.C) {
 		c.Assert(err, jc.ErrorIsNil)
 		changed := machine.PasswordValid(fmt.Sprintf("xxx%d-1234567890123457890", i))
 		c.Assert(changed, jc.IsTrue)
+		c.Assert(machine.HasInitialPassword(), jc.IsTrue)
 	}
 }
 
@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Provisioner", 1, NewProvisionerAPIV1)
+}
+
+// ProvisionerAPIV1 implements version 1 of the Provisioner API facade.
+// It differs from version 0 only in that ProvisioningInfo also reports
+// the tags and display name the provider should apply to each new
+// instance.
+type ProvisionerAPIV1 struct {
+	*ProvisionerAPI
+}
+
+// NewProvisionerAPIV1 creates a new server-side version 1
+// Provisioner API facade.
+func NewProvisionerAPIV1(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ProvisionerAPIV1, error) {
+	api, err := NewProvisionerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ProvisionerAPIV1{api}, nil
+}
+
+// ProvisioningInfo returns the provisioning information for each
+// given machine entity, including the instance tags and name.
+func (p *ProvisionerAPIV1) ProvisioningInfo(args params.Entities) (params.ProvisioningInfoResults, error) {
+	result, err := p.ProvisionerAPI.ProvisioningInfo(args)
+	if err != nil {
+		return result, err
+	}
+	cfg, err := p.st.EnvironConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, r := range result.Results {
+		if r.Result == nil {
+			continue
+		}
+		r.Result.Tags = p.instanceTags(r.Result.Jobs)
+		name, err := p.instanceName(cfg, args.Entities[i].Tag)
+		if err != nil {
+			result.Results[i] = params.ProvisioningInfoResult{Error: common.ServerError(err)}
+			continue
+		}
+		r.Result.InstanceName = name
+	}
+	return result, nil
+}
+
+// instanceTags returns the tags for an instance hosting
+// a machine with the given jobs.
+func (p *ProvisionerAPIV1) instanceTags(jobs []multiwatcher.MachineJob) map[string]string {
+	instTags := map[string]string{
+		tags.JujuEnv: p.st.EnvironUUID(),
+	}
+	for _, job := range jobs {
+		if job == multiwatcher.JobManageEnviron {
+			instTags[tags.JujuStateServer] = "true"
+			break
+		}
+	}
+	return instTags
+}
+
+// instanceName returns the display name for the instance hosting
+// the machine with the given tag, which names the services of the
+// units already assigned to the machine.
+func (p *ProvisionerAPIV1) instanceName(cfg *config.Config, machineTag string) (string, error) {
+	tag, err := names.ParseMachineTag(machineTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	machine, err := p.st.Machine(tag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	units, err := machine.Units()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	services := set.NewStrings()
+	for _, unit := range units {
+		services.Add(unit.ServiceName())
+	}
+	return cfg.InstanceName(machine.Id(), services.SortedValues()), nil
+}
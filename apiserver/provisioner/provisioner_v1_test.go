@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/provisioner"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+)
+
+type provisionerV1Suite struct {
+	provisionerSuite
+
+	provisionerV1 *provisioner.ProvisionerAPIV1
+}
+
+var _ = gc.Suite(&provisionerV1Suite{})
+
+func (s *provisionerV1Suite) SetUpTest(c *gc.C) {
+	s.setUpTest(c, true)
+	api, err := provisioner.NewProvisionerAPIV1(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.provisionerV1 = api
+}
+
+func (s *provisionerV1Suite) TestProvisioningInfoTags(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: "machine-42"},
+	}}
+	result, err := s.provisionerV1.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+
+	uuid := s.State.EnvironUUID()
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Check(result.Results[0].Result.Tags, jc.DeepEquals, map[string]string{
+		"juju-env-uuid": uuid,
+		"juju-is-state": "true",
+	})
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Check(result.Results[1].Result.Tags, jc.DeepEquals, map[string]string{
+		"juju-env-uuid": uuid,
+	})
+	c.Check(result.Results[2].Error, jc.DeepEquals, apiservertesting.NotFoundError("machine 42"))
+}
+
+func (s *provisionerV1Suite) TestProvisioningInfoV0HasNoTags(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[1].Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Check(result.Results[0].Result.Tags, gc.IsNil)
+}
+
+func (s *provisionerV1Suite) TestProvisioningInfoInstanceName(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"instance-name-template": "{env}-{machine}-{service}",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machines[1])
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: s.machines[2].Tag().String()},
+	}}
+	result, err := s.provisionerV1.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	envName := cfg.Name()
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Check(result.Results[0].Result.InstanceName, gc.Equals, envName+"-"+s.machines[1].Id()+"-wordpress")
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Check(result.Results[1].Result.InstanceName, gc.Equals, envName+"-"+s.machines[2].Id())
+}
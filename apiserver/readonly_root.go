@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// readOnlyMethodPrefixes holds the prefixes of facade methods that do
+// not change anything.
+var readOnlyMethodPrefixes = []string{
+	"Describe",
+	"Find",
+	"FullStatus",
+	"Get",
+	"Infer",
+	"Info",
+	"List",
+	"Next",
+	"Read",
+	"Show",
+	"Status",
+	"Watch",
+}
+
+// readOnlyMethods holds the facade methods, of the form
+// "Facade.Method", that do not change anything but whose names
+// do not start with any of the readOnlyMethodPrefixes.
+var readOnlyMethods = map[string]bool{
+	"AuditLog.Entries":               true,
+	"Client.APIHostPorts":            true,
+	"Client.AgentVersion":            true,
+	"Client.CharmInfo":               true,
+	"Client.EnvUserInfo":             true,
+	"Client.EnvironmentGet":          true,
+	"Client.EnvironmentInfo":         true,
+	"Client.MachineStatusHistory":    true,
+	"Client.PrivateAddress":          true,
+	"Client.PublicAddress":           true,
+	"Client.ResolveCharms":           true,
+	"Client.SSHHostKeys":             true,
+	"Client.ServiceCharmRelations":   true,
+	"Client.ServiceGet":              true,
+	"Client.ServiceGetCharmURL":      true,
+	"Client.UnitStatusHistory":       true,
+	"Diagnose.ServerChecks":          true,
+	"Pinger.Ping":                    true,
+	"ServerStats.Stats":              true,
+	"Service.CharmOrigins":           true,
+	"Service.ServiceRemovalProgress": true,
+	"UnitDetails.Details":            true,
+	"UnitEvents.Events":              true,
+	"WorkerHealth.AllWorkerHealth":   true,
+}
+
+// isReadOnlyCall reports whether a call to the given facade method
+// only reads from the environment.
+func isReadOnlyCall(facade, method string) bool {
+	if readOnlyMethods[facade+"."+method] || strings.HasSuffix(facade, "Watcher") {
+		return true
+	}
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readOnlyRoot restricts API calls to those that only read from the
+// environment, for users that have been granted read access alone.
+type readOnlyRoot struct {
+	rpc.MethodFinder
+}
+
+// newReadOnlyRoot returns a new readOnlyRoot.
+func newReadOnlyRoot(finder rpc.MethodFinder) *readOnlyRoot {
+	return &readOnlyRoot{finder}
+}
+
+// FindMethod returns a permission denied error if the method may
+// change the environment.
+func (r *readOnlyRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	// The lookup of the name is done first to return a not found error if the
+	// user is looking for a method that we just don't have.
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !isReadOnlyCall(rootName, methodName) {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	return caller, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type readOnlyRootSuite struct {
+	testing.BaseSuite
+
+	root rpc.MethodFinder
+}
+
+var _ = gc.Suite(&readOnlyRootSuite{})
+
+func (r *readOnlyRootSuite) SetUpTest(c *gc.C) {
+	r.BaseSuite.SetUpTest(c)
+	r.root = apiserver.TestingReadOnlyApiHandler(nil)
+}
+
+func (r *readOnlyRootSuite) TestIsReadOnlyCall(c *gc.C) {
+	for i, test := range []struct {
+		facade   string
+		method   string
+		readOnly bool
+	}{
+		{"Client", "FullStatus", true},
+		{"Client", "EnvironmentGet", true},
+		{"Client", "ServiceGet", true},
+		{"Client", "WatchAll", true},
+		{"AllWatcher", "Next", true},
+		{"AllWatcher", "Stop", true},
+		{"Pinger", "Ping", true},
+		{"AuditLog", "Entries", true},
+		{"Service", "ServiceRemovalProgress", true},
+		{"Diagnose", "ServerChecks", true},
+		{"WorkerHealth", "AllWorkerHealth", true},
+		{"UnitDetails", "Details", true},
+		{"UnitEvents", "Events", true},
+		{"ServerStats", "Stats", true},
+		{"Service", "CharmOrigins", true},
+		{"Client", "ServiceDeploy", false},
+		{"Client", "EnvironmentSet", false},
+		{"Client", "DestroyEnvironment", false},
+		{"Client", "ShareEnvironment", false},
+		{"UserManager", "SetPassword", false},
+	} {
+		c.Logf("test %d: %s.%s", i, test.facade, test.method)
+		c.Check(apiserver.IsReadOnlyCall(test.facade, test.method), gc.Equals, test.readOnly)
+	}
+}
+
+func (r *readOnlyRootSuite) TestFindAllowedMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Client", 0, "FullStatus")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+
+	caller, err = r.root.FindMethod("Client", 0, "EnvironmentGet")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (r *readOnlyRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Client", 0, "ServiceDeploy")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *readOnlyRootSuite) TestNonExistentFacade(c *gc.C) {
+	caller, err := r.root.FindMethod("NonExistent", 0, "Method")
+	c.Assert(err, gc.ErrorMatches, `unknown object type "NonExistent"`)
+	c.Assert(caller, gc.IsNil)
+}
@@ -74,6 +74,9 @@ func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier
 	if err := r.resources.RegisterNamed("logDir", common.StringResource(srv.logDir)); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := r.resources.RegisterNamed("serverStats", srv.stats); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return r, nil
 }
 
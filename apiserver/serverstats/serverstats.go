@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package serverstats implements the API facade that reports live
+// statistics about the load on the API server, such as its open
+// connections, watchers and mongo traffic, to aid capacity planning
+// for state servers.
+package serverstats
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ServerStats", 1, NewAPI)
+}
+
+// ServerStats defines the methods on the server stats API end point.
+type ServerStats interface {
+	// Stats returns the current statistics of the API server.
+	Stats() (params.ServerStats, error)
+}
+
+// API implements ServerStats and is the concrete implementation
+// of the api end point.
+type API struct {
+	stats *common.ServerStats
+}
+
+var _ ServerStats = (*API)(nil)
+
+// getMongoStats is a variable so it can be patched in tests.
+var getMongoStats = mgo.GetStats
+
+// NewAPI returns a new server stats API facade. Only the owner of the
+// state server environment may use it.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// TODO(thumper): PERMISSIONS Change this permission check when we have
+	// real permissions. For now, only the owner of the initial environment
+	// may see the state server's statistics.
+	user, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	env, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if user != env.Owner() {
+		return nil, common.ErrPerm
+	}
+	stats, ok := resources.Get("serverStats").(*common.ServerStats)
+	if !ok {
+		return nil, errors.New("server statistics not available")
+	}
+	return &API{stats: stats}, nil
+}
+
+// Stats implements ServerStats.
+func (api *API) Stats() (params.ServerStats, error) {
+	m := getMongoStats()
+	return params.ServerStats{
+		Connections: api.stats.Connections(),
+		Resources:   api.stats.Resources(),
+		Mongo: params.MongoStats{
+			Clusters:     m.Clusters,
+			MasterConns:  m.MasterConns,
+			SlaveConns:   m.SlaveConns,
+			SentOps:      m.SentOps,
+			ReceivedOps:  m.ReceivedOps,
+			ReceivedDocs: m.ReceivedDocs,
+			SocketsAlive: m.SocketsAlive,
+			SocketsInUse: m.SocketsInUse,
+			SocketRefs:   m.SocketRefs,
+		},
+	}, nil
+}
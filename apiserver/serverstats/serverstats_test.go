@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package serverstats_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/serverstats"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type serverStatsSuite struct {
+	jujutesting.JujuConnSuite
+
+	resources  *common.Resources
+	stats      *common.ServerStats
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&serverStatsSuite{})
+
+func (s *serverStatsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.stats = common.NewServerStats()
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	err := s.resources.RegisterNamed("serverStats", s.stats)
+	c.Assert(err, jc.ErrorIsNil)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+}
+
+func (s *serverStatsSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	api, err := serverstats.NewAPI(s.State, s.resources, s.authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *serverStatsSuite) TestNewAPIRefusesNonOwner(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	s.authorizer.Tag = user.Tag()
+	api, err := serverstats.NewAPI(s.State, s.resources, s.authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *serverStatsSuite) TestStats(c *gc.C) {
+	s.PatchValue(serverstats.GetMongoStats, func() mgo.Stats {
+		return mgo.Stats{MasterConns: 2, SentOps: 10, ReceivedOps: 9}
+	})
+	conn := common.NewResources()
+	s.stats.Add(s.resources)
+	s.stats.Add(conn)
+	s.stats.SetKind(s.resources, names.UserTagKind)
+	conn.Register(&fakeWatcher{})
+
+	api, err := serverstats.NewAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	stats, err := api.Stats()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats, jc.DeepEquals, params.ServerStats{
+		Connections: map[string]int{
+			names.UserTagKind:          1,
+			common.UnauthenticatedKind: 1,
+		},
+		Resources: map[string]int{
+			"*serverstats_test.fakeWatcher": 1,
+		},
+		Mongo: params.MongoStats{
+			MasterConns: 2,
+			SentOps:     10,
+			ReceivedOps: 9,
+		},
+	})
+}
+
+type fakeWatcher struct{}
+
+func (*fakeWatcher) Stop() error {
+	return nil
+}
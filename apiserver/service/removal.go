@@ -0,0 +1,249 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// ServiceRemovalProgress reports, for each of the given services,
+// the units, relations and machines that remain to be dealt with
+// before the service is removed. A service that has already been
+// removed is reported as such rather than as an error.
+func (api *APIV2) ServiceRemovalProgress(args params.Entities) (params.ServiceRemovalProgressResults, error) {
+	result := params.ServiceRemovalProgressResults{
+		Results: make([]params.ServiceRemovalProgressResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		progress, err := api.serviceRemovalProgress(entity.Tag)
+		result.Results[i].Progress = progress
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *APIV2) serviceRemovalProgress(tag string) (*params.ServiceRemovalProgress, error) {
+	serviceTag, err := names.ParseServiceTag(tag)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	service, err := api.state.Service(serviceTag.Id())
+	if errors.IsNotFound(err) {
+		return &params.ServiceRemovalProgress{Removed: true}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	progress := &params.ServiceRemovalProgress{}
+	units, err := service.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machines := set.NewStrings()
+	for _, unit := range units {
+		progress.Units = append(progress.Units, unit.Name())
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machines.Add(machineId)
+	}
+	sort.Strings(progress.Units)
+	progress.Machines = machines.SortedValues()
+
+	relations, err := service.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		progress.Relations = append(progress.Relations, rel.String())
+	}
+	sort.Strings(progress.Relations)
+	return progress, nil
+}
+
+// WatchServiceRemoval returns a NotifyWatcher for each of the given
+// services, which notifies when the service, its units or its
+// relations change, so that ServiceRemovalProgress may be called
+// again.
+func (api *APIV2) WatchServiceRemoval(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		id, err := api.watchServiceRemoval(entity.Tag)
+		result.Results[i].NotifyWatcherId = id
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *APIV2) watchServiceRemoval(tag string) (string, error) {
+	serviceTag, err := names.ParseServiceTag(tag)
+	if err != nil {
+		return "", common.ErrPerm
+	}
+	service, err := api.state.Service(serviceTag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	watch := service.WatchRemoval()
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		return api.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
+// ForceDestroyServices destroys each of the given services, and then
+// removes its remaining units without waiting for their agents, and
+// takes the units of related services out of its relations without
+// running their relation-broken hooks. It is intended for use once
+// an orderly removal has failed to complete in time.
+func (api *APIV2) ForceDestroyServices(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := api.check.RemoveAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		err := api.forceDestroyService(entity.Tag)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *APIV2) forceDestroyService(tag string) error {
+	serviceTag, err := names.ParseServiceTag(tag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	service, err := api.state.Service(serviceTag.Id())
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if err := service.Destroy(); err != nil {
+		return errors.Trace(err)
+	}
+	// The relations must be read before the last unit is removed,
+	// as that may remove the service.
+	relations, err := service.Relations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unit := range units {
+		if err := api.reapUnit(unit); err != nil {
+			return errors.Annotatef(err, "cannot remove unit %q", unit.Name())
+		}
+	}
+	for _, rel := range relations {
+		if err := api.leaveRelation(rel, serviceTag.Id()); err != nil {
+			return errors.Annotatef(err, "cannot remove relation %q", rel)
+		}
+	}
+	return nil
+}
+
+// reapUnit removes the given unit, and its subordinates, without
+// waiting for their agents.
+func (api *APIV2) reapUnit(unit *state.Unit) error {
+	for _, name := range unit.SubordinateNames() {
+		sub, err := api.state.Unit(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if err := api.reapUnit(sub); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := unit.Refresh(); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.reapStorageAttachments(unit); err != nil {
+		return errors.Trace(err)
+	}
+	if err := unit.EnsureDead(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := unit.Remove(); err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// reapStorageAttachments removes the storage attachments of the given
+// unit without waiting for its agent to run the storage-detaching
+// hooks, since a unit cannot become Dead while it has any.
+func (api *APIV2) reapStorageAttachments(unit *state.Unit) error {
+	unitTag := unit.UnitTag()
+	attachments, err := api.state.UnitStorageAttachments(unitTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, attachment := range attachments {
+		storageTag := attachment.StorageInstance()
+		if err := api.state.DestroyStorageAttachment(storageTag, unitTag); err != nil {
+			return errors.Trace(err)
+		}
+		if err := api.state.RemoveStorageAttachment(storageTag, unitTag); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// leaveRelation takes the units of the services other than the given
+// one out of the scope of the given relation, so that the relation
+// is removed.
+func (api *APIV2) leaveRelation(rel *state.Relation, serviceName string) error {
+	if err := rel.Refresh(); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, ep := range rel.Endpoints() {
+		if ep.ServiceName == serviceName {
+			continue
+		}
+		other, err := api.state.Service(ep.ServiceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		units, err := other.AllUnits()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, unit := range units {
+			ru, err := rel.Unit(unit)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err := ru.LeaveScope(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
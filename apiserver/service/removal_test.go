@@ -0,0 +1,173 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/service"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type serviceRemovalSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+
+	resources  *common.Resources
+	serviceApi *service.APIV2
+
+	mysql    *state.Service
+	unit     *state.Unit
+	machine  *state.Machine
+	relation *state.Relation
+}
+
+var _ = gc.Suite(&serviceRemovalSuite{})
+
+func (s *serviceRemovalSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.serviceApi, err = service.NewAPIV2(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Relate a unit of mysql to a unit of wordpress, with both
+	// units in scope.
+	s.mysql = s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.machine = s.Factory.MakeMachine(c, nil)
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.mysql, Machine: s.machine})
+	wpUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress})
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.relation, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range []*state.Unit{s.unit, wpUnit} {
+		ru, err := s.relation.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *serviceRemovalSuite) progress(c *gc.C) params.ServiceRemovalProgress {
+	results, err := s.serviceApi.ServiceRemovalProgress(params.Entities{
+		Entities: []params.Entity{{Tag: "service-mysql"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	return *results.Results[0].Progress
+}
+
+func (s *serviceRemovalSuite) TestServiceRemovalProgress(c *gc.C) {
+	err := s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.progress(c), jc.DeepEquals, params.ServiceRemovalProgress{
+		Units:     []string{"mysql/0"},
+		Relations: []string{s.relation.String()},
+		Machines:  []string{s.machine.Id()},
+	})
+}
+
+func (s *serviceRemovalSuite) TestServiceRemovalProgressInvalidTag(c *gc.C) {
+	results, err := s.serviceApi.ServiceRemovalProgress(params.Entities{
+		Entities: []params.Entity{{Tag: "unit-mysql-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *serviceRemovalSuite) TestWatchServiceRemoval(c *gc.C) {
+	results, err := s.serviceApi.WatchServiceRemoval(params.Entities{
+		Entities: []params.Entity{{Tag: "service-mysql"}, {Tag: "service-foo"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].NotifyWatcherId, gc.Equals, "1")
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *serviceRemovalSuite) TestForceDestroyServices(c *gc.C) {
+	results, err := s.serviceApi.ForceDestroyServices(params.Entities{
+		Entities: []params.Entity{{Tag: "service-mysql"}, {Tag: "service-foo"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {}},
+	})
+
+	c.Assert(s.progress(c), jc.DeepEquals, params.ServiceRemovalProgress{Removed: true})
+	_, err = s.State.Unit("mysql/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.relation.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The machine is left alone.
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Life(), gc.Equals, state.Alive)
+}
+
+func (s *serviceRemovalSuite) TestForceDestroyServiceWithStorage(c *gc.C) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	sCons := map[string]state.StorageConstraints{
+		"data": {Pool: "", Size: 1024, Count: 1},
+	}
+	storageBlock := s.AddTestingServiceWithStorage(c, "storage-block", ch, sCons)
+	unit, err := storageBlock.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := s.State.UnitStorageAttachments(unit.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+
+	results, err := s.serviceApi.ForceDestroyServices(params.Entities{
+		Entities: []params.Entity{{Tag: "service-storage-block"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	_, err = s.State.Unit(unit.Name())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	attachments, err = s.State.UnitStorageAttachments(unit.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+}
+
+func (s *serviceRemovalSuite) TestBlockForceDestroyServices(c *gc.C) {
+	s.BlockRemoveObject(c, "TestBlockForceDestroyServices")
+	_, err := s.serviceApi.ForceDestroyServices(params.Entities{
+		Entities: []params.Entity{{Tag: "service-mysql"}},
+	})
+	s.AssertBlocked(c, err, "TestBlockForceDestroyServices")
+}
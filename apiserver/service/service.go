@@ -39,6 +39,7 @@ type Service interface {
 type API struct {
 	check      *common.BlockChecker
 	state      *state.State
+	resources  *common.Resources
 	authorizer common.Authorizer
 }
 
@@ -54,6 +55,7 @@ func NewAPI(
 
 	return &API{
 		state:      st,
+		resources:  resources,
 		authorizer: authorizer,
 		check:      common.NewBlockChecker(st),
 	}, nil
@@ -153,14 +155,15 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 		jjj.DeployServiceParams{
 			ServiceName: args.ServiceName,
 			// TODO(dfc) ServiceOwner should be a tag
-			ServiceOwner:   owner,
-			Charm:          ch,
-			NumUnits:       args.NumUnits,
-			ConfigSettings: settings,
-			Constraints:    args.Constraints,
-			ToMachineSpec:  args.ToMachineSpec,
-			Networks:       requestedNetworks,
-			Storage:        args.Storage,
+			ServiceOwner:     owner,
+			Charm:            ch,
+			NumUnits:         args.NumUnits,
+			ConfigSettings:   settings,
+			Constraints:      args.Constraints,
+			ToMachineSpec:    args.ToMachineSpec,
+			Networks:         requestedNetworks,
+			Storage:          args.Storage,
+			AssignmentPolicy: state.AssignmentPolicy(args.AssignmentPolicy),
 		})
 	return err
 }
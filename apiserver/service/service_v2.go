@@ -0,0 +1,262 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jjj "github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Service", 2, NewAPIV2)
+}
+
+// APIV2 implements version 2 of the Service API facade. It adds
+// InferRelationEndpoints, CharmOrigins, SetCharmOrigins,
+// SetServiceUnitCounts, SetServicesConfig, ServiceRemovalProgress,
+// WatchServiceRemoval and ForceDestroyServices to version 1.
+type APIV2 struct {
+	*API
+}
+
+// NewAPIV2 returns a new version 2 service API facade.
+func NewAPIV2(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*APIV2, error) {
+	api, err := NewAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &APIV2{api}, nil
+}
+
+// InferRelationEndpoints reports, for each query, the endpoints that
+// AddRelation would relate, without adding the relation. If no single
+// relation can be chosen, the result's error explains why.
+func (api *APIV2) InferRelationEndpoints(args params.RelationEndpointsQueries) (params.RelationEndpointsResults, error) {
+	result := params.RelationEndpointsResults{
+		Results: make([]params.RelationEndpointsResult, len(args.Queries)),
+	}
+	for i, query := range args.Queries {
+		eps, err := api.state.InferEndpointsVia(query.Interface, query.Endpoints...)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		endpoints := make(map[string]charm.Relation)
+		for _, ep := range eps {
+			endpoints[ep.ServiceName] = ep.Relation
+		}
+		result.Results[i].Endpoints = endpoints
+	}
+	return result, nil
+}
+
+// CharmOrigins returns the charm store that each of the given
+// services' charms was deployed from.
+func (api *APIV2) CharmOrigins(args params.Entities) (params.CharmOriginResults, error) {
+	result := params.CharmOriginResults{
+		Results: make([]params.CharmOriginResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := api.state.Service(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if origin, ok := service.CharmOrigin(); ok {
+			result.Results[i].Origin = &params.CharmOrigin{StoreURL: origin.StoreURL}
+		}
+	}
+	return result, nil
+}
+
+// SetCharmOrigins records the charm store that each of the given
+// services' charms was deployed from, so that upgrades use the same
+// charm store.
+func (api *APIV2) SetCharmOrigins(args params.ServiceCharmOrigins) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Origins)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Origins {
+		service, err := api.state.Service(arg.ServiceName)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		var origin *state.CharmOrigin
+		if arg.Origin != nil {
+			origin = &state.CharmOrigin{StoreURL: arg.Origin.StoreURL}
+		}
+		err = service.SetCharmOrigin(origin)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetServiceUnitCounts adds or removes units of each of the given
+// services so that it has the requested number of alive units. A
+// service that already has that many units is left alone, so the
+// call may safely be repeated.
+func (api *APIV2) SetServiceUnitCounts(args params.ServiceUnitCounts) (params.ServiceUnitCountResults, error) {
+	result := params.ServiceUnitCountResults{
+		Results: make([]params.ServiceUnitCountResult, len(args.Services)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Services {
+		added, removed, err := api.setServiceUnitCount(arg)
+		result.Results[i].Added = added
+		result.Results[i].Removed = removed
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *APIV2) setServiceUnitCount(arg params.ServiceUnitCount) (added, removed []string, err error) {
+	if arg.NumUnits < 0 {
+		return nil, nil, errors.NotValidf("unit count %d", arg.NumUnits)
+	}
+	service, err := api.state.Service(arg.ServiceName)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if !service.IsPrincipal() {
+		return nil, nil, errors.Errorf("cannot scale subordinate service %q", arg.ServiceName)
+	}
+	allUnits, err := service.AllUnits()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var units []*state.Unit
+	for _, unit := range allUnits {
+		if unit.Life() == state.Alive {
+			units = append(units, unit)
+		}
+	}
+	switch {
+	case len(units) < arg.NumUnits:
+		newUnits, err := jjj.AddUnits(api.state, service, arg.NumUnits-len(units), "")
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		for _, unit := range newUnits {
+			added = append(added, unit.Name())
+		}
+	case len(units) > arg.NumUnits:
+		if err := api.check.RemoveAllowed(); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		victims, err := unitsToRemove(units, len(units)-arg.NumUnits, arg.ExcludeMachines)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		for _, unit := range victims {
+			if err := unit.Destroy(); err != nil {
+				return nil, removed, errors.Annotatef(err, "cannot destroy unit %q", unit.Name())
+			}
+			removed = append(removed, unit.Name())
+		}
+	}
+	return added, removed, nil
+}
+
+// SetServicesConfig applies configuration changes to several services
+// at once. Either all of the changes are made or, if any of them is
+// invalid or cannot be made, none of them are.
+func (api *APIV2) SetServicesConfig(args params.ServicesConfigChanges) error {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	changes := make(map[string]charm.Settings)
+	for _, arg := range args.Changes {
+		if _, ok := changes[arg.ServiceName]; ok {
+			return errors.Errorf("duplicate changes for service %q", arg.ServiceName)
+		}
+		service, err := api.state.Service(arg.ServiceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ch, _, err := service.Charm()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		settings, err := ch.Config().ParseSettingsStrings(arg.Options)
+		if err != nil {
+			return errors.Annotatef(err, "service %q", arg.ServiceName)
+		}
+		for _, name := range arg.Unset {
+			if _, ok := settings[name]; ok {
+				return errors.Errorf("service %q: option %q both set and unset", arg.ServiceName, name)
+			}
+			settings[name] = nil
+		}
+		changes[arg.ServiceName] = settings
+	}
+	return api.state.UpdateServicesConfigSettings(changes)
+}
+
+// unitsToRemove chooses n of the given units to remove, preferring
+// the most recently added ones and never choosing a unit assigned to
+// one of the excluded machines.
+func unitsToRemove(units []*state.Unit, n int, excludeMachines []string) ([]*state.Unit, error) {
+	excluded := set.NewStrings(excludeMachines...)
+	var candidates []*state.Unit
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if err != nil && !errors.IsNotAssigned(err) {
+			return nil, errors.Trace(err)
+		}
+		if err == nil && excluded.Contains(machineId) {
+			continue
+		}
+		candidates = append(candidates, unit)
+	}
+	if len(candidates) < n {
+		return nil, errors.Errorf(
+			"cannot remove %d units: only %d are not on excluded machines",
+			n, len(candidates),
+		)
+	}
+	sort.Sort(byUnitNumberDescending(candidates))
+	return candidates[:n], nil
+}
+
+// byUnitNumberDescending sorts units of a service from the most
+// recently added to the least recently added.
+type byUnitNumberDescending []*state.Unit
+
+func (u byUnitNumberDescending) Len() int      { return len(u) }
+func (u byUnitNumberDescending) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byUnitNumberDescending) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) > unitNumber(u[j].Name())
+}
+
+// unitNumber returns the number of the unit with the given name.
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
@@ -0,0 +1,303 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/service"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type serviceV2Suite struct {
+	jujutesting.JujuConnSuite
+
+	serviceApi *service.APIV2
+}
+
+var _ = gc.Suite(&serviceV2Suite{})
+
+func (s *serviceV2Suite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.serviceApi, err = service.NewAPIV2(s.State, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *serviceV2Suite) TestInferRelationEndpoints(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql-alternative"))
+
+	results, err := s.serviceApi.InferRelationEndpoints(params.RelationEndpointsQueries{
+		Queries: []params.RelationEndpointsQuery{
+			{Endpoints: []string{"wordpress", "logging"}, Interface: "juju-info"},
+			{Endpoints: []string{"wordpress", "mysql"}},
+			{Endpoints: []string{"wordpress", "mysql"}, Interface: "http"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Endpoints, jc.DeepEquals, map[string]charm.Relation{
+		"logging": {
+			Interface: "juju-info",
+			Name:      "info",
+			Role:      charm.RoleRequirer,
+			Scope:     charm.ScopeContainer,
+			Limit:     1,
+		},
+		"wordpress": {
+			Interface: "juju-info",
+			Name:      "juju-info",
+			Role:      charm.RoleProvider,
+			Scope:     charm.ScopeGlobal,
+		},
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches,
+		`ambiguous relation: "wordpress mysql" could refer to "wordpress:db mysql:dev"; "wordpress:db mysql:prod"`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `no relations found using interface "http"`)
+
+	// Nothing was related.
+	relations, err := s.State.AllRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations, gc.HasLen, 0)
+}
+
+func (s *serviceV2Suite) TestCharmOrigins(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	origin := &params.CharmOrigin{StoreURL: "https://api.jujucharms.com/charmstore"}
+	results, err := s.serviceApi.SetCharmOrigins(params.ServiceCharmOrigins{
+		Origins: []params.ServiceCharmOrigin{
+			{ServiceName: "wordpress", Origin: origin},
+			{ServiceName: "mysql", Origin: &params.CharmOrigin{}},
+			{ServiceName: "unknown", Origin: origin},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot set charm origin for service "mysql": .* not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "unknown" not found`)
+
+	origins, err := s.serviceApi.CharmOrigins(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "service-wordpress"},
+			{Tag: "service-mysql"},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(origins.Results, jc.DeepEquals, []params.CharmOriginResult{
+		{Origin: origin},
+		{},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	})
+}
+
+func (s *serviceV2Suite) TestBlockChangesSetCharmOrigins(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := s.State.SwitchBlockOn(state.ChangeBlock, "TestBlockChangesSetCharmOrigins")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.serviceApi.SetCharmOrigins(params.ServiceCharmOrigins{
+		Origins: []params.ServiceCharmOrigin{{
+			ServiceName: "wordpress",
+			Origin:      &params.CharmOrigin{StoreURL: "https://api.jujucharms.com/charmstore"},
+		}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+}
+
+func (s *serviceV2Suite) TestSetServiceUnitCounts(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+
+	results, err := s.serviceApi.SetServiceUnitCounts(params.ServiceUnitCounts{
+		Services: []params.ServiceUnitCount{
+			{ServiceName: "wordpress", NumUnits: 3},
+			{ServiceName: "logging", NumUnits: 1},
+			{ServiceName: "unknown", NumUnits: 1},
+			{ServiceName: "wordpress", NumUnits: -1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0], jc.DeepEquals, params.ServiceUnitCountResult{
+		Added: []string{"wordpress/0", "wordpress/1", "wordpress/2"},
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot scale subordinate service "logging"`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "unknown" not found`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `unit count -1 not valid`)
+
+	// Asking for the current count changes nothing.
+	s.assertSetServiceUnitCount(c, params.ServiceUnitCount{
+		ServiceName: "wordpress",
+		NumUnits:    3,
+	}, params.ServiceUnitCountResult{})
+}
+
+func (s *serviceV2Suite) TestSetServiceUnitCountsRemovesYoungestUnits(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.assertSetServiceUnitCount(c, params.ServiceUnitCount{
+		ServiceName: "wordpress",
+		NumUnits:    4,
+	}, params.ServiceUnitCountResult{
+		Added: []string{"wordpress/0", "wordpress/1", "wordpress/2", "wordpress/3"},
+	})
+	machineId := s.assignedMachineId(c, "wordpress/2")
+
+	s.assertSetServiceUnitCount(c, params.ServiceUnitCount{
+		ServiceName:     "wordpress",
+		NumUnits:        2,
+		ExcludeMachines: []string{machineId},
+	}, params.ServiceUnitCountResult{
+		Removed: []string{"wordpress/3", "wordpress/1"},
+	})
+
+	// Units that are no longer alive are not counted.
+	s.assertSetServiceUnitCount(c, params.ServiceUnitCount{
+		ServiceName: "wordpress",
+		NumUnits:    2,
+	}, params.ServiceUnitCountResult{})
+
+	results, err := s.serviceApi.SetServiceUnitCounts(params.ServiceUnitCounts{
+		Services: []params.ServiceUnitCount{{
+			ServiceName:     "wordpress",
+			NumUnits:        1,
+			ExcludeMachines: []string{machineId, s.assignedMachineId(c, "wordpress/0")},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "cannot remove 1 units: only 0 are not on excluded machines")
+}
+
+func (s *serviceV2Suite) assignedMachineId(c *gc.C, unitName string) string {
+	unit, err := s.State.Unit(unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	return machineId
+}
+
+func (s *serviceV2Suite) assertSetServiceUnitCount(c *gc.C, arg params.ServiceUnitCount, expect params.ServiceUnitCountResult) {
+	results, err := s.serviceApi.SetServiceUnitCounts(params.ServiceUnitCounts{
+		Services: []params.ServiceUnitCount{arg},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ServiceUnitCountResult{expect})
+}
+
+func (s *serviceV2Suite) TestBlockSetServiceUnitCounts(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.assertSetServiceUnitCount(c, params.ServiceUnitCount{
+		ServiceName: "wordpress",
+		NumUnits:    2,
+	}, params.ServiceUnitCountResult{
+		Added: []string{"wordpress/0", "wordpress/1"},
+	})
+	err := s.State.SwitchBlockOn(state.RemoveBlock, "TestBlockSetServiceUnitCounts")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Scaling up is still allowed, but scaling down is not.
+	results, err := s.serviceApi.SetServiceUnitCounts(params.ServiceUnitCounts{
+		Services: []params.ServiceUnitCount{
+			{ServiceName: "wordpress", NumUnits: 3},
+			{ServiceName: "wordpress", NumUnits: 1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(params.IsCodeOperationBlocked(results.Results[1].Error), jc.IsTrue)
+
+	err = s.State.SwitchBlockOn(state.ChangeBlock, "TestBlockSetServiceUnitCounts")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.serviceApi.SetServiceUnitCounts(params.ServiceUnitCounts{
+		Services: []params.ServiceUnitCount{{ServiceName: "wordpress", NumUnits: 3}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+}
+
+func (s *serviceV2Suite) TestSetServicesConfig(c *gc.C) {
+	dummy := s.AddTestingCharm(c, "dummy")
+	svc1 := s.AddTestingService(c, "dummy1", dummy)
+	svc2 := s.AddTestingService(c, "dummy2", dummy)
+	err := svc2.UpdateConfigSettings(charm.Settings{"title": "sir"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.serviceApi.SetServicesConfig(params.ServicesConfigChanges{
+		Changes: []params.ServiceConfigChanges{{
+			ServiceName: "dummy1",
+			Options:     map[string]string{"outlook": "positive", "skill-level": "9"},
+		}, {
+			ServiceName: "dummy2",
+			Unset:       []string{"title"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "positive", "skill-level": int64(9)})
+	settings, err = svc2.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *serviceV2Suite) TestSetServicesConfigAllOrNothing(c *gc.C) {
+	dummy := s.AddTestingCharm(c, "dummy")
+	svc1 := s.AddTestingService(c, "dummy1", dummy)
+	s.AddTestingService(c, "dummy2", dummy)
+
+	err := s.serviceApi.SetServicesConfig(params.ServicesConfigChanges{
+		Changes: []params.ServiceConfigChanges{{
+			ServiceName: "dummy1",
+			Options:     map[string]string{"outlook": "positive"},
+		}, {
+			ServiceName: "dummy2",
+			Options:     map[string]string{"skill-level": "profound"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, `service "dummy2": option "skill-level" expected int, got "profound"`)
+
+	err = s.serviceApi.SetServicesConfig(params.ServicesConfigChanges{
+		Changes: []params.ServiceConfigChanges{{
+			ServiceName: "dummy1",
+			Options:     map[string]string{"outlook": "positive"},
+		}, {
+			ServiceName: "dummy1",
+			Options:     map[string]string{"title": "sir"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, `duplicate changes for service "dummy1"`)
+
+	settings, err := svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *serviceV2Suite) TestBlockSetServicesConfig(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err := s.State.SwitchBlockOn(state.ChangeBlock, "TestBlockSetServicesConfig")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.serviceApi.SetServicesConfig(params.ServicesConfigChanges{
+		Changes: []params.ServiceConfigChanges{{
+			ServiceName: "dummy",
+			Options:     map[string]string{"outlook": "positive"},
+		}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+}
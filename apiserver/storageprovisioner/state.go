@@ -40,6 +40,11 @@ type provisionerState interface {
 	SetFilesystemAttachmentInfo(names.MachineTag, names.FilesystemTag, state.FilesystemAttachmentInfo) error
 	SetVolumeInfo(names.VolumeTag, state.VolumeInfo) error
 	SetVolumeAttachmentInfo(names.MachineTag, names.VolumeTag, state.VolumeAttachmentInfo) error
+
+	RemoveFilesystem(names.FilesystemTag) error
+	RemoveFilesystemAttachment(names.MachineTag, names.FilesystemTag) error
+	RemoveVolume(names.VolumeTag) error
+	RemoveVolumeAttachment(names.MachineTag, names.VolumeTag) error
 }
 
 type stateShim struct {
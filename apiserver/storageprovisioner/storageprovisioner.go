@@ -980,3 +980,76 @@ func (s *StorageProvisionerAPI) AttachmentLife(args params.MachineStorageIds) (p
 	}
 	return results, nil
 }
+
+// Remove removes volumes and filesystems from state, once they are Dead.
+func (s *StorageProvisionerAPI) Remove(args params.Entities) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) error {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil {
+			return err
+		}
+		if !canAccess(tag) {
+			return common.ErrPerm
+		}
+		switch tag := tag.(type) {
+		case names.FilesystemTag:
+			return s.st.RemoveFilesystem(tag)
+		case names.VolumeTag:
+			return s.st.RemoveVolume(tag)
+		default:
+			return common.ErrPerm
+		}
+	}
+	for i, arg := range args.Entities {
+		if err := one(arg); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return results, nil
+}
+
+// RemoveAttachment removes the specified machine storage attachments
+// from state, once they are Dying.
+func (s *StorageProvisionerAPI) RemoveAttachment(args params.MachineStorageIds) (params.ErrorResults, error) {
+	canAccess, err := s.getAttachmentAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(arg params.MachineStorageId) error {
+		machineTag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil {
+			return err
+		}
+		attachmentTag, err := names.ParseTag(arg.AttachmentTag)
+		if err != nil {
+			return err
+		}
+		if !canAccess(machineTag, attachmentTag) {
+			return common.ErrPerm
+		}
+		switch attachmentTag := attachmentTag.(type) {
+		case names.VolumeTag:
+			return s.st.RemoveVolumeAttachment(machineTag, attachmentTag)
+		case names.FilesystemTag:
+			return s.st.RemoveFilesystemAttachment(machineTag, attachmentTag)
+		default:
+			return common.ErrPerm
+		}
+	}
+	for i, arg := range args.Ids {
+		if err := one(arg); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return results, nil
+}
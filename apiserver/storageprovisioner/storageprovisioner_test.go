@@ -875,6 +875,63 @@ func (s *provisionerSuite) TestEnsureDead(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) TestRemoveVolumesEnvironManager(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.DestroyVolume(names.NewVolumeTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveVolumeAttachment(names.NewMachineTag("0"), names.NewVolumeTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"volume-1"}, {"volume-0-0"}, {"volume-42"}, {"machine-0"},
+	}}
+	result, err := s.api.Remove(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{Message: "removing volume 0/0: volume is not dead"}},
+			{Error: nil},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+	_, err = s.State.Volume(names.NewVolumeTag("1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *provisionerSuite) TestRemoveVolumeAttachments(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.DetachVolume(names.NewMachineTag("0"), names.NewVolumeTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.RemoveAttachment(params.MachineStorageIds{
+		Ids: []params.MachineStorageId{{
+			MachineTag:    "machine-0",
+			AttachmentTag: "volume-1",
+		}, {
+			MachineTag:    "machine-0",
+			AttachmentTag: "volume-0-0",
+		}, {
+			MachineTag:    "machine-2",
+			AttachmentTag: "volume-4",
+		}, {
+			MachineTag:    "machine-0",
+			AttachmentTag: "machine-1",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{Message: "removing attachment of volume 0/0 from machine 0: volume attachment is not dying"}},
+			{Error: nil},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+	_, err = s.State.VolumeAttachment(names.NewMachineTag("0"), names.NewVolumeTag("1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *provisionerSuite) TestWatchForEnvironConfigChanges(c *gc.C) {
 	result, err := s.api.WatchForEnvironConfigChanges()
 	c.Assert(err, jc.ErrorIsNil)
@@ -57,7 +57,12 @@ func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		tarball, err := h.processGet(r, stateWrapper.state)
 		if err != nil {
 			logger.Errorf("GET(%s) failed: %v", r.URL, err)
-			h.sendExistingError(w, http.StatusBadRequest, err)
+			statusCode := http.StatusBadRequest
+			if errors.Cause(err) == errToolsHashMismatch {
+				// The stored tools are corrupt; the request is fine.
+				statusCode = http.StatusInternalServerError
+			}
+			h.sendExistingError(w, statusCode, err)
 			return
 		}
 		h.sendTools(w, http.StatusOK, tarball)
@@ -123,6 +128,10 @@ func (h *toolsHandler) sendExistingError(w http.ResponseWriter, statusCode int,
 	}
 }
 
+// errToolsHashMismatch is returned by processGet when stored tools do
+// not match the hash recorded when they were added.
+var errToolsHashMismatch = errors.New("hash mismatch")
+
 // processGet handles a tools GET request.
 func (h *toolsDownloadHandler) processGet(r *http.Request, st *state.State) ([]byte, error) {
 	version, err := version.ParseBinary(r.URL.Query().Get(":version"))
@@ -134,25 +143,37 @@ func (h *toolsDownloadHandler) processGet(r *http.Request, st *state.State) ([]b
 		return nil, errors.Annotate(err, "error getting tools storage")
 	}
 	defer storage.Close()
-	_, reader, err := storage.Tools(version)
+	metadata, reader, err := storage.Tools(version)
 	if errors.IsNotFound(err) {
 		// Tools could not be found in toolstorage,
 		// so look for them in simplestreams, fetch
-		// them and cache in toolstorage.
+		// them and cache in toolstorage. Fetched
+		// tools are verified before being cached.
 		logger.Infof("%v tools not found locally, fetching", version)
 		reader, err = h.fetchAndCacheTools(version, storage, st)
 		if err != nil {
-			err = errors.Annotate(err, "error fetching tools")
+			return nil, errors.Annotate(err, "error fetching tools")
 		}
+		defer reader.Close()
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to read tools tarball")
+		}
+		return data, nil
 	}
 	if err != nil {
 		return nil, err
 	}
 	defer reader.Close()
-	data, err := ioutil.ReadAll(reader)
+	data, sha256, err := readAndHash(reader)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to read tools tarball")
 	}
+	// Refuse to serve stored tools that do not match the
+	// hash recorded when they were added.
+	if sha256 != metadata.SHA256 {
+		return nil, errors.Annotatef(errToolsHashMismatch, "%v tools", version)
+	}
 	return data, nil
 }
 
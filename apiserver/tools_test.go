@@ -278,6 +278,17 @@ func (s *toolsSuite) TestDownloadTopLevelPath(c *gc.C) {
 	s.testDownload(c, tools, "")
 }
 
+func (s *toolsSuite) TestDownloadVerifiesStoredHash(c *gc.C) {
+	tools := s.storeFakeTools(c, s.State, "abd", toolstorage.Metadata{
+		Version: version.Current,
+		Size:    3,
+		SHA256:  "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	})
+	resp, err := s.downloadRequest(c, tools.Version, "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusInternalServerError, ".* tools: hash mismatch")
+}
+
 func (s *toolsSuite) TestDownloadFetchesAndCaches(c *gc.C) {
 	// The tools are not in toolstorage, so the download request causes
 	// the API server to search for the tools in simplestreams, fetch
@@ -0,0 +1,207 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitdetails implements the API facade used to gather
+// everything known about individual units, for debugging.
+package unitdetails
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("UnitDetails", 1, NewAPI)
+}
+
+// defaultHistorySize is the number of entries of each status history
+// returned for each unit when the query does not specify a size.
+const defaultHistorySize = 5
+
+// isServiceLeader reports whether the given unit is currently the
+// leader of the given service.
+var isServiceLeader = func(serviceId, unitId string) bool {
+	return leadership.NewLeadershipManager(lease.Manager()).Leader(serviceId, unitId)
+}
+
+// UnitDetails defines the methods on the unit details API end point.
+type UnitDetails interface {
+	// Details returns the details of each given unit.
+	Details(params.UnitDetailsQuery) (params.UnitDetailsResults, error)
+}
+
+// API implements UnitDetails and is the concrete implementation
+// of the api end point.
+type API struct {
+	st *state.State
+}
+
+var _ UnitDetails = (*API)(nil)
+
+// NewAPI returns a new unit details API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st}, nil
+}
+
+// Details implements UnitDetails.Details().
+func (a *API) Details(args params.UnitDetailsQuery) (params.UnitDetailsResults, error) {
+	if args.HistorySize < 0 {
+		return params.UnitDetailsResults{}, errors.NotValidf("history size %d", args.HistorySize)
+	}
+	size := args.HistorySize
+	if size == 0 {
+		size = defaultHistorySize
+	}
+	results := params.UnitDetailsResults{
+		Results: make([]params.UnitDetailsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		details, err := a.unitDetails(entity.Tag, size)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Details = details
+	}
+	return results, nil
+}
+
+func (a *API) unitDetails(tag string, size int) (*params.UnitDetails, error) {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unit, err := a.st.Unit(unitTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	details := &params.UnitDetails{
+		Service: unit.ServiceName(),
+		Leader:  isServiceLeader(unit.ServiceName(), unit.Name()),
+	}
+	details.Machine, err = unit.AssignedMachineId()
+	if err != nil && !errors.IsNotAssigned(err) {
+		return nil, errors.Trace(err)
+	}
+	details.PublicAddress, _ = unit.PublicAddress()
+	details.PrivateAddress, _ = unit.PrivateAddress()
+
+	ports, err := unit.OpenedPorts()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, port := range ports {
+		details.OpenedPorts = append(details.OpenedPorts, params.FromNetworkPortRange(port))
+	}
+
+	if details.Relations, err = unitRelations(unit); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	details.WorkloadStatusHistory, err = statusEntries(unit, size)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	agent, ok := unit.Agent().(*state.UnitAgent)
+	if !ok {
+		return nil, errors.Errorf("cannot obtain agent for %q", unit.Name())
+	}
+	details.AgentStatusHistory, err = statusEntries(agent, size)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return details, nil
+}
+
+// unitRelations returns the details of each relation in which the
+// given unit is in scope.
+func unitRelations(unit *state.Unit) ([]params.UnitRelationDetails, error) {
+	relations, err := unit.RelationsInScope()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []params.UnitRelationDetails
+	for _, rel := range relations {
+		ru, err := rel.Unit(unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		settings, err := ru.ReadSettings(unit.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		digest, err := settingsDigest(settings)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot digest settings for relation %q", rel)
+		}
+		ep := ru.Endpoint()
+		result = append(result, params.UnitRelationDetails{
+			Key:            rel.String(),
+			Endpoint:       ep.Name,
+			Interface:      ep.Interface,
+			SettingsDigest: digest,
+		})
+	}
+	return result, nil
+}
+
+// settingsDigest returns the hex-encoded SHA256 digest of the
+// JSON encoding of the given settings, whose keys are sorted.
+func settingsDigest(settings map[string]interface{}) (string, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// statusHistoryGetter is implemented by units and unit agents.
+type statusHistoryGetter interface {
+	Status() (state.StatusInfo, error)
+	StatusHistory(size int) ([]state.StatusInfo, error)
+}
+
+// statusEntries returns the current status of the given entity
+// followed by its past statuses, newest first, up to size entries
+// in all.
+func statusEntries(entity statusHistoryGetter, size int) ([]params.UnitStatusEntry, error) {
+	current, err := entity.Status()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	statuses := []state.StatusInfo{current}
+	if size > 1 {
+		history, err := entity.StatusHistory(size - 1)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		statuses = append(statuses, history...)
+	}
+	result := make([]params.UnitStatusEntry, len(statuses))
+	for i, status := range statuses {
+		result[i] = params.UnitStatusEntry{
+			Status: params.Status(status.Status),
+			Info:   status.Message,
+			Since:  status.Since,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitdetails_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/unitdetails"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type unitDetailsSuite struct {
+	jujutesting.JujuConnSuite
+	api *unitdetails.API
+}
+
+var _ = gc.Suite(&unitDetailsSuite{})
+
+func (s *unitDetailsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = unitdetails.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(unitdetails.IsServiceLeader, func(serviceId, unitId string) bool {
+		return unitId == "wordpress/0"
+	})
+}
+
+func (s *unitDetailsSuite) TestNewAPIRefusesAgents(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := unitdetails.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *unitDetailsSuite) TestDetails(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine := s.Factory.MakeMachine(c, nil)
+	err = machine.SetProviderAddresses(
+		network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPorts("tcp", 80, 81)
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(map[string]interface{}{"host": "10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusActive, "serving", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.Details(params.UnitDetailsQuery{
+		Entities: []params.Entity{
+			{Tag: unit.Tag().String()},
+			{Tag: "unit-foo-0"},
+			{Tag: "service-foo"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	details := results.Results[0].Details
+	c.Assert(details.Service, gc.Equals, "wordpress")
+	c.Assert(details.Machine, gc.Equals, machine.Id())
+	c.Assert(details.PublicAddress, gc.Equals, "8.8.8.8")
+	c.Assert(details.PrivateAddress, gc.Equals, "10.0.0.1")
+	c.Assert(details.Leader, jc.IsTrue)
+	c.Assert(details.OpenedPorts, jc.DeepEquals, []params.PortRange{
+		{FromPort: 80, ToPort: 81, Protocol: "tcp"},
+	})
+	c.Assert(details.Relations, gc.HasLen, 1)
+	c.Assert(details.Relations[0].Key, gc.Equals, rel.String())
+	c.Assert(details.Relations[0].Endpoint, gc.Equals, "db")
+	c.Assert(details.Relations[0].Interface, gc.Equals, "mysql")
+	c.Assert(details.Relations[0].SettingsDigest, gc.Matches, "[0-9a-f]{64}")
+	c.Assert(details.WorkloadStatusHistory[0].Status, gc.Equals, params.StatusActive)
+	c.Assert(details.WorkloadStatusHistory[0].Info, gc.Equals, "serving")
+	c.Assert(details.WorkloadStatusHistory[1].Status, gc.Equals, params.StatusUnknown)
+	c.Assert(details.AgentStatusHistory, gc.HasLen, 1)
+	c.Assert(details.AgentStatusHistory[0].Status, gc.Equals, params.StatusAllocating)
+
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"service-foo" is not a valid unit tag`)
+}
+
+func (s *unitDetailsSuite) TestDetailsRelationSettingsDigest(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(map[string]interface{}{"host": "10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	digest := func() string {
+		results, err := s.api.Details(params.UnitDetailsQuery{
+			Entities: []params.Entity{{Tag: unit.Tag().String()}},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(results.Results[0].Error, gc.IsNil)
+		c.Assert(results.Results[0].Details.Relations, gc.HasLen, 1)
+		return results.Results[0].Details.Relations[0].SettingsDigest
+	}
+	before := digest()
+	c.Assert(digest(), gc.Equals, before)
+
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("host", "10.0.0.2")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(digest(), gc.Not(gc.Equals), before)
+}
+
+func (s *unitDetailsSuite) TestDetailsInvalidHistorySize(c *gc.C) {
+	_, err := s.api.Details(params.UnitDetailsQuery{HistorySize: -1})
+	c.Assert(err, gc.ErrorMatches, "history size -1 not valid")
+}
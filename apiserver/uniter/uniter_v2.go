@@ -76,3 +76,123 @@ func NewUniterAPIV2(st *state.State, resources *common.Resources, authorizer com
 		StorageAPI:  *storageAPI,
 	}, nil
 }
+
+// ReadServiceSettings returns the service-level settings of each given
+// service within the given relation, as seen by the given local unit.
+func (u *UniterAPIV2) ReadServiceSettings(args params.RelationUnitServices) (params.SettingsResults, error) {
+	result := params.SettingsResults{
+		Results: make([]params.SettingsResult, len(args.RelationUnitServices)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SettingsResults{}, err
+	}
+	for i, arg := range args.RelationUnitServices {
+		unit, err := names.ParseUnitTag(arg.LocalUnit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := names.ParseServiceTag(arg.Service)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unit)
+		if err == nil {
+			rel := relUnit.Relation()
+			if _, err = rel.Endpoint(service.Id()); err != nil {
+				err = common.ErrPerm
+			} else {
+				var settings map[string]interface{}
+				settings, err = rel.ReadServiceSettings(service.Id())
+				if err == nil {
+					result.Results[i].Settings, err = convertRelationSettings(settings)
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UpdateServiceSettings persists all changes made to the service-level
+// settings of the given unit's service within each given relation. Only
+// the leader of the service may do so; leadership is checked in the
+// same transaction that writes the settings. Keys with empty values
+// are considered a signal to delete these values.
+func (u *UniterAPIV2) UpdateServiceSettings(args params.RelationUnitsSettings) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.RelationUnits)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.RelationUnits {
+		unit, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unit)
+		if err == nil {
+			rel := relUnit.Relation()
+			var settings *state.Settings
+			settings, err = rel.ServiceSettings(relUnit.Endpoint().ServiceName)
+			if err == nil {
+				for k, v := range arg.Settings {
+					if v == "" {
+						settings.Delete(k)
+					} else {
+						settings.Set(k, v)
+					}
+				}
+				err = rel.WriteServiceSettings(unit.Id(), settings)
+				if err == state.ErrNotServiceLeader {
+					err = common.ErrPerm
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RecordUnitEvents adds the given events to the event log of each
+// given unit.
+func (u *UniterAPIV2) RecordUnitEvents(args params.UnitEventsParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Units {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			for _, event := range arg.Events {
+				if err != nil {
+					break
+				}
+				err = unit.RecordEvent(state.UnitEvent{
+					Kind:     state.UnitEventKind(event.Kind),
+					Hook:     event.Hook,
+					Time:     event.Time,
+					Duration: event.Duration,
+					Message:  event.Message,
+				})
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
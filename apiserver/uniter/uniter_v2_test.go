@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/lease"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
@@ -271,3 +272,126 @@ func (s *unitMetricBatchesSuite) TestAddMetricsBatchDiffTag(c *gc.C) {
 		c.Assert(err, jc.Satisfies, errors.IsNotFound)
 	}
 }
+
+func (s *uniterV2Suite) TestUpdateServiceSettings(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnitsSettings{RelationUnits: []params.RelationUnitSettings{
+		{Relation: "relation-42", Unit: "unit-foo-0", Settings: params.Settings{"a": "b"}},
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0", Settings: params.Settings{"a": "b"}},
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Settings: params.Settings{"endpoint": "10.0.0.1"}},
+	}}
+	result, err := s.uniter.UpdateServiceSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	settings, err := rel.ReadServiceSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+
+	err = s.State.LeasePersistor.WriteToken("wordpress-leadership", lease.Token{
+		Namespace:  "wordpress-leadership",
+		Id:         "wordpress/0",
+		Expiration: time.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.UpdateServiceSettings(params.RelationUnitsSettings{
+		RelationUnits: args.RelationUnits[2:],
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{nil}},
+	})
+	settings, err = rel.ReadServiceSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]interface{}{"endpoint": "10.0.0.1"})
+}
+
+func (s *uniterV2Suite) TestReadServiceSettings(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := rel.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("endpoint", "10.0.0.2:3306")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnitServices{RelationUnitServices: []params.RelationUnitService{
+		{Relation: "relation-42", LocalUnit: "unit-wordpress-0", Service: "service-mysql"},
+		{Relation: rel.Tag().String(), LocalUnit: "unit-mysql-0", Service: "service-mysql"},
+		{Relation: rel.Tag().String(), LocalUnit: "unit-wordpress-0", Service: "service-riak"},
+		{Relation: rel.Tag().String(), LocalUnit: "unit-wordpress-0", Service: "unit-mysql-0"},
+		{Relation: rel.Tag().String(), LocalUnit: "unit-wordpress-0", Service: "service-mysql"},
+		{Relation: rel.Tag().String(), LocalUnit: "unit-wordpress-0", Service: "service-wordpress"},
+	}}
+	result, err := s.uniter.ReadServiceSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.SettingsResults{
+		Results: []params.SettingsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Settings: params.Settings{"endpoint": "10.0.0.2:3306"}},
+			{Settings: params.Settings{}},
+		},
+	})
+}
+
+func (s *uniterV2Suite) TestRecordUnitEvents(c *gc.C) {
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []params.UnitEvent{{
+		Kind: params.UnitEventHookStarted,
+		Hook: "install",
+		Time: t0,
+	}, {
+		Kind:     params.UnitEventHookFailed,
+		Hook:     "install",
+		Time:     t0.Add(2 * time.Second),
+		Duration: 2 * time.Second,
+		Message:  "exit status 1",
+	}}
+	args := params.UnitEventsParams{Units: []params.UnitEventsParam{
+		{Tag: "unit-mysql-0", Events: events},
+		{Tag: "service-wordpress", Events: events},
+		{Tag: "unit-wordpress-0", Events: events},
+	}}
+	result, err := s.uniter.RecordUnitEvents(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+		},
+	})
+
+	recorded, err := s.wordpressUnit.Events(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, jc.DeepEquals, []state.UnitEvent{{
+		Kind:     state.UnitEventHookFailed,
+		Hook:     "install",
+		Time:     t0.Add(2 * time.Second),
+		Duration: 2 * time.Second,
+		Message:  "exit status 1",
+	}, {
+		Kind: state.UnitEventHookStarted,
+		Hook: "install",
+		Time: t0,
+	}})
+	recorded, err = s.mysqlUnit.Events(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, gc.HasLen, 0)
+}
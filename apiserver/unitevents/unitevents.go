@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitevents implements the API facade used to query the
+// event logs of units, which record the execution of their hooks.
+package unitevents
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("UnitEvents", 1, NewAPI)
+}
+
+// defaultSize is the number of events returned for each unit when
+// the query does not specify a size.
+const defaultSize = 20
+
+// UnitEvents defines the methods on the unit events API end point.
+type UnitEvents interface {
+	// Events returns the most recent events in the event log of
+	// each given unit.
+	Events(params.UnitEventsQuery) (params.UnitEventsResults, error)
+}
+
+// API implements UnitEvents and is the concrete implementation
+// of the api end point.
+type API struct {
+	st *state.State
+}
+
+var _ UnitEvents = (*API)(nil)
+
+// NewAPI returns a new unit events API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st}, nil
+}
+
+// Events implements UnitEvents.Events().
+func (a *API) Events(args params.UnitEventsQuery) (params.UnitEventsResults, error) {
+	if args.Size < 0 {
+		return params.UnitEventsResults{}, errors.NotValidf("size %d", args.Size)
+	}
+	size := args.Size
+	if size == 0 {
+		size = defaultSize
+	}
+	results := params.UnitEventsResults{
+		Results: make([]params.UnitEventsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		events, err := a.unitEvents(entity.Tag, size)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Events = events
+	}
+	return results, nil
+}
+
+func (a *API) unitEvents(tag string, size int) ([]params.UnitEvent, error) {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unit, err := a.st.Unit(unitTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	events, err := unit.Events(size)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]params.UnitEvent, len(events))
+	for i, event := range events {
+		result[i] = params.UnitEvent{
+			Kind:     params.UnitEventKind(event.Kind),
+			Hook:     event.Hook,
+			Time:     event.Time,
+			Duration: event.Duration,
+			Message:  event.Message,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitevents_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/unitevents"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type unitEventsSuite struct {
+	jujutesting.JujuConnSuite
+	api *unitevents.API
+}
+
+var _ = gc.Suite(&unitEventsSuite{})
+
+func (s *unitEventsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = unitevents.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *unitEventsSuite) TestNewAPIRefusesAgents(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := unitevents.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *unitEventsSuite) TestEvents(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		err := unit.RecordEvent(state.UnitEvent{
+			Kind:     state.UnitEventHookFailed,
+			Hook:     "config-changed",
+			Time:     t0.Add(time.Duration(i) * time.Minute),
+			Duration: time.Second,
+			Message:  "exit status 1",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	results, err := s.api.Events(params.UnitEventsQuery{
+		Entities: []params.Entity{
+			{Tag: unit.Tag().String()},
+			{Tag: "unit-foo-0"},
+			{Tag: "service-foo"},
+		},
+		Size: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Events, jc.DeepEquals, []params.UnitEvent{{
+		Kind:     params.UnitEventHookFailed,
+		Hook:     "config-changed",
+		Time:     t0.Add(2 * time.Minute),
+		Duration: time.Second,
+		Message:  "exit status 1",
+	}, {
+		Kind:     params.UnitEventHookFailed,
+		Hook:     "config-changed",
+		Time:     t0.Add(time.Minute),
+		Duration: time.Second,
+		Message:  "exit status 1",
+	}})
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"service-foo" is not a valid unit tag`)
+}
+
+func (s *unitEventsSuite) TestEventsInvalidSize(c *gc.C) {
+	_, err := s.api.Events(params.UnitEventsQuery{Size: -1})
+	c.Assert(err, gc.ErrorMatches, "size -1 not valid")
+}
@@ -29,6 +29,7 @@ var allowedMethodsDuringUpgrades = set.NewStrings(
 	"EnvironmentGet", // for "juju ssh"
 	"PrivateAddress", // for "juju ssh"
 	"PublicAddress",  // for "juju ssh"
+	"SSHHostKeys",    // for "juju ssh"
 	"WatchDebugLog",  // for "juju debug-log"
 )
 
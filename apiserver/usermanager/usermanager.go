@@ -24,6 +24,7 @@ type UserManager interface {
 	AddUser(args params.AddUsers) (params.AddUserResults, error)
 	DisableUser(args params.Entities) (params.ErrorResults, error)
 	EnableUser(args params.Entities) (params.ErrorResults, error)
+	RemoveUser(args params.Entities) (params.ErrorResults, error)
 	SetPassword(args params.EntityPasswords) (params.ErrorResults, error)
 	UserInfo(args params.UserInfoRequest) (params.UserInfoResults, error)
 }
@@ -132,6 +133,15 @@ func (api *UserManagerAPI) DisableUser(users params.Entities) (params.ErrorResul
 	return api.enableUserImpl(users, "disable", (*state.User).Disable)
 }
 
+// RemoveUser removes one or more users. Removed users can no longer log
+// in, and their names may be reused by new users.
+func (api *UserManagerAPI) RemoveUser(users params.Entities) (params.ErrorResults, error) {
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	return api.enableUserImpl(users, "remove", (*state.User).Remove)
+}
+
 func (api *UserManagerAPI) enableUserImpl(args params.Entities, action string, method func(*state.User) error) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -249,6 +249,70 @@ func (s *userManagerSuite) TestBlockEnableUser(c *gc.C) {
 	c.Assert(barb.IsDisabled(), jc.IsTrue)
 }
 
+func (s *userManagerSuite) TestRemoveUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+
+	args := params.Entities{
+		Entities: []params.Entity{
+			{alex.Tag().String()},
+			{s.AdminUserTag(c).String()},
+			{names.NewLocalUserTag("ellie").String()},
+			{"not-a-tag"},
+		}}
+	result, err := s.usermanager.RemoveUser(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{
+				Message: "failed to remove user: cannot remove state server environment owner",
+			}},
+			{Error: &params.Error{
+				Message: "permission denied",
+				Code:    params.CodeUnauthorized,
+			}},
+			{Error: &params.Error{
+				Message: `"not-a-tag" is not a valid tag`,
+			}},
+		}})
+	_, err = s.State.User(alex.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestBlockRemoveUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+
+	args := params.Entities{
+		Entities: []params.Entity{{alex.Tag().String()}},
+	}
+
+	s.BlockRemoveObject(c, "TestBlockRemoveUser")
+	_, err := s.usermanager.RemoveUser(args)
+	// Check that the call is blocked
+	s.AssertBlocked(c, err, "TestBlockRemoveUser")
+
+	_, err = s.State.User(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userManagerSuite) TestRemoveUserAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb"})
+
+	args := params.Entities{
+		[]params.Entity{{barb.Tag().String()}},
+	}
+	_, err = usermanager.RemoveUser(args)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	_, err = s.State.User(barb.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *userManagerSuite) TestDisableUserAsNormalUser(c *gc.C) {
 	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
 	usermanager, err := usermanager.NewUserManagerAPI(
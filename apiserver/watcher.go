@@ -130,7 +130,10 @@ type srvStringsWatcher struct {
 }
 
 func newStringsWatcher(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
-	if !isAgent(auth) {
+	// Clients may watch for action results, so unlike most
+	// watchers this one is not restricted to agents. Resources are
+	// per-connection, so clients can only reach their own watchers.
+	if !isAgent(auth) && !auth.AuthClient() {
 		return nil, common.ErrPerm
 	}
 	watcher, ok := resources.Get(id).(state.StringsWatcher)
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package workerhealth implements the API facade through which agents
+// report the health of their workers, and clients read those reports.
+package workerhealth
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("WorkerHealth", 1, NewAPI)
+}
+
+// WorkerHealth defines the methods on the worker health API end point.
+type WorkerHealth interface {
+	// SetWorkerHealth records the health of the workers of each
+	// given agent. Agents may only report on themselves.
+	SetWorkerHealth(params.SetWorkerHealth) (params.ErrorResults, error)
+
+	// AllWorkerHealth returns the most recent report of each agent
+	// in the environment. It may only be called by clients.
+	AllWorkerHealth() (params.AgentWorkerHealthResults, error)
+}
+
+// API implements WorkerHealth and is the concrete implementation
+// of the api end point.
+type API struct {
+	st         *state.State
+	authorizer common.Authorizer
+}
+
+var _ WorkerHealth = (*API)(nil)
+
+// NewAPI returns a new worker health API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() && !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st, authorizer: authorizer}, nil
+}
+
+// SetWorkerHealth implements WorkerHealth.SetWorkerHealth().
+func (a *API) SetWorkerHealth(args params.SetWorkerHealth) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Agents)),
+	}
+	for i, agent := range args.Agents {
+		err := a.setWorkerHealth(agent)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (a *API) setWorkerHealth(agent params.AgentWorkerHealth) error {
+	tag, err := names.ParseTag(agent.Tag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	if !a.authorizer.AuthOwner(tag) {
+		return common.ErrPerm
+	}
+	workers := make([]state.WorkerHealth, len(agent.Workers))
+	for i, w := range agent.Workers {
+		workers[i] = state.WorkerHealth{
+			Name:          w.Name,
+			Running:       w.Running,
+			Restarts:      w.Restarts,
+			LastError:     w.LastError,
+			LastErrorTime: w.LastErrorTime,
+			Failed:        w.Failed,
+		}
+	}
+	return errors.Trace(a.st.SetWorkerHealth(tag, workers))
+}
+
+// AllWorkerHealth implements WorkerHealth.AllWorkerHealth().
+func (a *API) AllWorkerHealth() (params.AgentWorkerHealthResults, error) {
+	if !a.authorizer.AuthClient() {
+		return params.AgentWorkerHealthResults{}, common.ErrPerm
+	}
+	all, err := a.st.AllWorkerHealth()
+	if err != nil {
+		return params.AgentWorkerHealthResults{}, errors.Trace(err)
+	}
+	results := params.AgentWorkerHealthResults{
+		Results: make([]params.AgentWorkerHealth, len(all)),
+	}
+	for i, health := range all {
+		result := params.AgentWorkerHealth{
+			Tag:      health.Agent.String(),
+			Updated:  health.Updated,
+			Reported: health.Reported,
+			Workers:  make([]params.WorkerHealth, len(health.Workers)),
+		}
+		for j, w := range health.Workers {
+			result.Workers[j] = params.WorkerHealth{
+				Name:          w.Name,
+				Running:       w.Running,
+				Restarts:      w.Restarts,
+				LastError:     w.LastError,
+				LastErrorTime: w.LastErrorTime,
+				Failed:        w.Failed,
+			}
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
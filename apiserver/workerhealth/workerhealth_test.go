@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package workerhealth_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/workerhealth"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type workerHealthSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&workerHealthSuite{})
+
+func (s *workerHealthSuite) newAPI(c *gc.C, tag names.Tag) *workerhealth.API {
+	auth := apiservertesting.FakeAuthorizer{Tag: tag}
+	api, err := workerhealth.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *workerHealthSuite) TestNewAPIRefusesUnknownEntities(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewServiceTag("mysql"),
+	}
+	_, err := workerhealth.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *workerHealthSuite) TestSetWorkerHealth(c *gc.C) {
+	failedAt := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	api := s.newAPI(c, names.NewMachineTag("0"))
+	results, err := api.SetWorkerHealth(params.SetWorkerHealth{
+		Agents: []params.AgentWorkerHealth{{
+			Tag: "machine-0",
+			Workers: []params.WorkerHealth{{
+				Name:          "addresser",
+				Restarts:      3,
+				LastError:     "authentication failed",
+				LastErrorTime: failedAt,
+				Failed:        true,
+			}},
+		}, {
+			Tag: "machine-1",
+		}, {
+			Tag: "invalid",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	health, err := s.State.WorkerHealth(names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Workers, gc.HasLen, 1)
+	c.Assert(health.Workers[0].LastErrorTime.Equal(failedAt), jc.IsTrue)
+	health.Workers[0].LastErrorTime = failedAt
+	c.Assert(health.Workers[0], jc.DeepEquals, state.WorkerHealth{
+		Name:          "addresser",
+		Restarts:      3,
+		LastError:     "authentication failed",
+		LastErrorTime: failedAt,
+		Failed:        true,
+	})
+}
+
+func (s *workerHealthSuite) TestAllWorkerHealth(c *gc.C) {
+	err := s.State.SetWorkerHealth(names.NewMachineTag("0"), []state.WorkerHealth{{
+		Name:    "machiner",
+		Running: true,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.newAPI(c, s.AdminUserTag(c))
+	results, err := api.AllWorkerHealth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Tag, gc.Equals, "machine-0")
+	c.Assert(results.Results[0].Updated.IsZero(), jc.IsFalse)
+	c.Assert(results.Results[0].Reported.IsZero(), jc.IsFalse)
+	c.Assert(results.Results[0].Workers, jc.DeepEquals, []params.WorkerHealth{{
+		Name:    "machiner",
+		Running: true,
+	}})
+}
+
+func (s *workerHealthSuite) TestAllWorkerHealthRefusesAgents(c *gc.C) {
+	api := s.newAPI(c, names.NewMachineTag("0"))
+	_, err := api.AllWorkerHealth()
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
@@ -170,6 +170,11 @@ type InstanceConfig struct {
 	// instances. If enabled, the OS will perform any upgrades
 	// available as part of its provisioning.
 	EnableOSUpgrade bool
+
+	// CloudInitUserData holds additional cloud-init directives,
+	// supplied by the operator, to apply to the instance. It is
+	// always nil for Windows instances.
+	CloudInitUserData *config.CloudInitUserData
 }
 
 func (cfg *InstanceConfig) agentInfo() service.AgentInfo {
@@ -517,6 +522,15 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 	); err != nil {
 		return errors.Trace(err)
 	}
+	if userData := cfg.CloudInitUserData(); userData != nil {
+		// The directives are for cloud-init on Ubuntu and CentOS;
+		// Windows machines are configured by a PowerShell script.
+		if os, err := version.GetOSFromSeries(icfg.Series); err == nil && os == version.Windows {
+			logger.Warningf("ignoring %s for windows machine %s", config.CloudInitUserDataKey, icfg.MachineId)
+		} else {
+			icfg.CloudInitUserData = userData
+		}
+	}
 
 	if isStateInstanceConfig(icfg) {
 		// Add NUMACTL preference. Needed to work for both bootstrap and high availability
@@ -524,6 +538,18 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 		logger.Debugf("Setting numa ctl preference to %v", cfg.NumaCtlPreference())
 		// Unfortunately, AgentEnvironment can only take strings as values
 		icfg.AgentEnvironment[agent.NumaCtlPreference] = fmt.Sprintf("%v", cfg.NumaCtlPreference())
+
+		// Pass on any mongo tuning; unset values are computed by
+		// the state server itself.
+		for key, value := range map[string]int{
+			agent.MongoOplogSize:       cfg.MongoOplogSize(),
+			agent.MongoCacheSize:       cfg.MongoCacheSize(),
+			agent.MongoJournalInterval: cfg.MongoJournalInterval(),
+		} {
+			if value > 0 {
+				icfg.AgentEnvironment[key] = strconv.Itoa(value)
+			}
+		}
 	}
 	// The following settings are only appropriate at bootstrap time. At the
 	// moment, the only state server is the bootstrap node, but this
@@ -40,22 +40,25 @@ func configureCloudinit(icfg *instancecfg.InstanceConfig, cloudcfg cloudinit.Clo
 
 // ComposeUserData fills out the provided cloudinit configuration structure
 // so it is suitable for initialising a machine with the given configuration,
-// and then renders it and returns it as a binary (gzipped) blob of user data.
+// and then renders it with the renderer registered for the machine's
+// operating system and returns it as a binary (gzipped) blob of user data.
 //
 // If the provided cloudcfg is nil, a new one will be created internally.
 func ComposeUserData(icfg *instancecfg.InstanceConfig, cloudcfg cloudinit.CloudConfig) ([]byte, error) {
+	renderer, err := RendererForSeries(icfg.Series)
+	if err != nil {
+		return nil, err
+	}
 	if cloudcfg == nil {
-		var err error
 		cloudcfg, err = cloudinit.New(icfg.Series)
 		if err != nil {
 			return nil, err
 		}
 	}
-	_, err := configureCloudinit(icfg, cloudcfg)
-	if err != nil {
+	if _, err := configureCloudinit(icfg, cloudcfg); err != nil {
 		return nil, err
 	}
-	data, err := cloudcfg.RenderYAML()
+	data, err := renderer.Render(cloudcfg)
 	logger.Tracef("Generated cloud init:\n%s", string(data))
 	if err != nil {
 		return nil, err
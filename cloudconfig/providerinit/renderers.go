@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package providerinit
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/version"
+)
+
+// Renderer renders a fully configured cloudinit.CloudConfig as the
+// user data consumed on first boot by machines running a particular
+// operating system.
+type Renderer interface {
+	Render(cloudcfg cloudinit.CloudConfig) ([]byte, error)
+}
+
+// RendererFunc is a function that implements Renderer.
+type RendererFunc func(cloudcfg cloudinit.CloudConfig) ([]byte, error)
+
+// Render is part of the Renderer interface.
+func (f RendererFunc) Render(cloudcfg cloudinit.CloudConfig) ([]byte, error) {
+	return f(cloudcfg)
+}
+
+var (
+	renderersMu sync.Mutex
+	renderers   = map[version.OSType]Renderer{
+		version.Ubuntu:  RendererFunc(renderCloudConfig),
+		version.CentOS:  RendererFunc(renderCloudConfig),
+		version.Windows: RendererFunc(renderPowerShell),
+	}
+)
+
+// RegisterRenderer sets the renderer used to produce user data for
+// machines running the given operating system, replacing any existing
+// one. Registering a nil renderer removes support for the operating
+// system.
+func RegisterRenderer(os version.OSType, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if r == nil {
+		delete(renderers, os)
+		return
+	}
+	renderers[os] = r
+}
+
+// RendererForSeries returns the renderer that produces user data for
+// machines running the given series.
+func RendererForSeries(series string) (Renderer, error) {
+	os, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	r, ok := renderers[os]
+	if !ok {
+		return nil, errors.NotSupportedf("rendering user data for %s", os)
+	}
+	return r, nil
+}
+
+// renderCloudConfig renders the configuration as a cloud-config
+// document, as understood by cloud-init.
+func renderCloudConfig(cloudcfg cloudinit.CloudConfig) ([]byte, error) {
+	return cloudcfg.RenderYAML()
+}
+
+// renderPowerShell renders the configuration's commands as a
+// PowerShell script, as understood by cloudbase-init. Windows
+// configurations render such a script from RenderYAML.
+func renderPowerShell(cloudcfg cloudinit.CloudConfig) ([]byte, error) {
+	return cloudcfg.RenderYAML()
+}
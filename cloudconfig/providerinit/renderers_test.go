@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package providerinit_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type RendererSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&RendererSuite{})
+
+func (s *RendererSuite) render(c *gc.C, series string) string {
+	renderer, err := providerinit.RendererForSeries(series)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudcfg, err := cloudinit.New(series)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudcfg.AddRunCmd("echo hello")
+	data, err := renderer.Render(cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *RendererSuite) TestCloudConfig(c *gc.C) {
+	data := s.render(c, "trusty")
+	c.Assert(strings.HasPrefix(data, "#cloud-config\n"), jc.IsTrue)
+	c.Assert(data, jc.Contains, "- echo hello")
+}
+
+func (s *RendererSuite) TestPowerShell(c *gc.C) {
+	data := s.render(c, "win2012r2")
+	c.Assert(data, gc.Equals, "#ps1_sysnative\r\n\r\necho hello")
+}
+
+func (s *RendererSuite) TestUnknownSeries(c *gc.C) {
+	_, err := providerinit.RendererForSeries("nosuchseries")
+	c.Assert(err, gc.ErrorMatches, `invalid series "nosuchseries"`)
+}
+
+func (s *RendererSuite) TestRegisterRenderer(c *gc.C) {
+	original, err := providerinit.RendererForSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) {
+		providerinit.RegisterRenderer(version.Ubuntu, original)
+	})
+
+	providerinit.RegisterRenderer(version.Ubuntu, providerinit.RendererFunc(
+		func(cloudinit.CloudConfig) ([]byte, error) {
+			return []byte("custom"), nil
+		},
+	))
+	c.Assert(s.render(c, "trusty"), gc.Equals, "custom")
+
+	providerinit.RegisterRenderer(version.Ubuntu, nil)
+	_, err = providerinit.RendererForSeries("trusty")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "rendering user data for Ubuntu not supported")
+}
@@ -33,6 +33,10 @@ type ConfigureParams struct {
 
 	// Series is the series of the machine on which the script will be carried out
 	Series string
+
+	// ScriptSent, if not nil, is called once the whole script has
+	// been sent to the host, while it may still be running.
+	ScriptSent func()
 }
 
 // Configure connects to the specified host over SSH,
@@ -60,6 +64,25 @@ func RunConfigureScript(script string, params ConfigureParams) error {
 	}
 	cmd := ssh.Command(params.Host, []string{"sudo", "/bin/bash"}, nil)
 	cmd.Stdin = strings.NewReader(script)
+	if params.ScriptSent != nil {
+		cmd.Stdin = &sentReader{Reader: cmd.Stdin, sent: params.ScriptSent}
+	}
 	cmd.Stderr = params.ProgressWriter
 	return cmd.Run()
 }
+
+// sentReader calls sent once the wrapped reader is exhausted.
+type sentReader struct {
+	io.Reader
+	sent func()
+}
+
+// Read is part of the io.Reader interface.
+func (r *sentReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF && r.sent != nil {
+		r.sent()
+		r.sent = nil
+	}
+	return n, err
+}
@@ -328,8 +328,10 @@ mkdir -p /var/log/juju
 chown syslog:adm /var/log/juju
 bin='/var/lib/juju/tools/1\.2\.3-quantal-amd64'
 mkdir -p \$bin
+install -D -m 644 /dev/null '/var/lib/juju/ca-cert\.pem'
+printf '%s\\n' 'CA CERT\\n.*' > '/var/lib/juju/ca-cert\.pem'
 echo 'Fetching tools.*
-curl .* --noproxy "\*" --insecure -o \$bin/tools\.tar\.gz 'https://state-addr\.testing\.invalid:54321/tools/1\.2\.3-quantal-amd64'
+curl .* --noproxy "\*" --cacert '/var/lib/juju/ca-cert\.pem' -o \$bin/tools\.tar\.gz 'https://state-addr\.testing\.invalid:54321/tools/1\.2\.3-quantal-amd64'
 sha256sum \$bin/tools\.tar\.gz > \$bin/juju1\.2\.3-quantal-amd64\.sha256
 grep '1234' \$bin/juju1\.2\.3-quantal-amd64.sha256 \|\| \(echo "Tools checksum mismatch"; exit 1\)
 tar zxf \$bin/tools.tar.gz -C \$bin
@@ -420,7 +422,7 @@ start jujud-machine-2-lxc-1
 		},
 		inexactMatch: true,
 		expectScripts: `
-curl .* --noproxy "\*" --insecure -o \$bin/tools\.tar\.gz 'https://state-addr\.testing\.invalid:54321/tools/1\.2\.3-quantal-amd64'
+curl .* --noproxy "\*" --cacert '/var/lib/juju/ca-cert\.pem' -o \$bin/tools\.tar\.gz 'https://state-addr\.testing\.invalid:54321/tools/1\.2\.3-quantal-amd64'
 `,
 	}, {
 		// empty contraints.
@@ -1116,6 +1118,48 @@ export NO_PROXY=localhost,10.0.3.1' > /home/ubuntu/.juju-proxy && chown ubuntu:u
 	c.Assert(found, jc.IsTrue)
 }
 
+func (s *cloudinitSuite) TestCloudInitUserData(c *gc.C) {
+	environConfig := minimalConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"cloudinit-userdata": `
+packages: [htop]
+runcmd: [touch /tmp/first, touch /tmp/second]
+write_files:
+- path: /etc/motd
+  content: hello
+`,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	packages := cloudcfg.Packages()
+	c.Assert(packages[len(packages)-1], gc.Equals, "htop")
+	cmds := strings.Join(cloudcfg.RunCmds(), "\n")
+	c.Assert(cmds, jc.Contains, "printf '%s\\n' 'hello' > '/etc/motd'\ntouch /tmp/first\ntouch /tmp/second\n")
+}
+
+func (s *cloudinitSuite) TestCloudInitUserDataIgnoredOnWindows(c *gc.C) {
+	environConfig := minimalConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"cloudinit-userdata": "packages: [htop]",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stateInfo := jujutesting.FakeStateInfo("42")
+	apiInfo := jujutesting.FakeAPIInfo("42")
+	instanceConfig, err := instancecfg.NewInstanceConfig("42", "fake-nonce", imagemetadata.ReleasedStream, "win8", true, nil, stateInfo, apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Tools = newSimpleTools("1.2.3-win8-amd64")
+	err = instancecfg.FinishInstanceConfig(instanceConfig, environConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceConfig.CloudInitUserData, gc.IsNil)
+}
+
 func (s *cloudinitSuite) TestAptMirror(c *gc.C) {
 	environConfig := minimalConfig(c)
 	environConfig, err := environConfig.Apply(map[string]interface{}{
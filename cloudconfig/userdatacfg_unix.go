@@ -219,11 +219,12 @@ func (w *unixConfigure) ConfigureJuju() error {
 			// Don't go through the proxy when downloading tools from the state servers
 			curlCommand += ` --noproxy "*"`
 
-			// Our API server certificates are unusable by curl (invalid subject name),
-			// so we must disable certificate validation. It doesn't actually
-			// matter, because there is no sensitive information being transmitted
-			// and we verify the tools' hash after.
-			curlCommand += " --insecure"
+			// Verify the API server certificates against the environment's
+			// CA certificate. The certificates name the state server
+			// machines' addresses, so curl can check them.
+			caCertPath := path.Join(w.icfg.DataDir, "ca-cert.pem")
+			w.conf.AddRunTextFile(caCertPath, w.icfg.APIInfo.CACert, 0644)
+			curlCommand += " --cacert " + shquote(caCertPath)
 		}
 		curlCommand += " -o $bin/tools.tar.gz"
 		w.conf.AddRunCmd(cloudinit.LogProgressCmd("Fetching tools: %s <%s>", curlCommand, urls))
@@ -313,7 +314,27 @@ func (w *unixConfigure) ConfigureJuju() error {
 		)
 	}
 
-	return w.addMachineAgentToBoot()
+	if err := w.addMachineAgentToBoot(); err != nil {
+		return errors.Trace(err)
+	}
+	w.addCloudInitUserData()
+	return nil
+}
+
+// addCloudInitUserData appends the operator-supplied cloud-init
+// directives, if any, after juju's own configuration.
+func (w *unixConfigure) addCloudInitUserData() {
+	userData := w.icfg.CloudInitUserData
+	if userData == nil {
+		return
+	}
+	for _, pkg := range userData.Packages {
+		w.conf.AddPackage(pkg)
+	}
+	for _, file := range userData.WriteFiles {
+		w.conf.AddRunTextFile(file.Path, file.Content, file.Permissions)
+	}
+	w.conf.AddScripts(userData.RunCmd...)
 }
 
 // toolsDownloadCommand takes a curl command minus the source URL,
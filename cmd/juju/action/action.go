@@ -5,9 +5,11 @@ package action
 
 import (
 	"io"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5"
 
 	"github.com/juju/juju/api/action"
@@ -78,6 +80,11 @@ type APIClient interface {
 	// FindActionTagsByPrefix takes a list of string prefixes and finds
 	// corresponding ActionTags that match that prefix.
 	FindActionTagsByPrefix(params.FindTags) (params.FindTagsResults, error)
+
+	// WaitForResult blocks until the given Action has finished, or
+	// until a value is received on stop, and returns its most recent
+	// result.
+	WaitForResult(names.ActionTag, <-chan time.Time) (params.ActionResult, error)
 }
 
 // ActionCommandBase is the base type for action sub-commands.
@@ -32,6 +32,7 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+When waiting, the results are returned as soon as the action finishes.
 `
 
 // Set up the output.
@@ -99,7 +100,14 @@ func (c *FetchCommand) Run(ctx *cmd.Context) error {
 		wait = time.NewTimer(waitDur)
 	}
 
-	result, err := timerLoop(api, c.requestedId, wait, tick)
+	var result params.ActionResult
+	if waitDur.Nanoseconds() >= 0 {
+		result, err = waitForResult(api, c.requestedId, wait)
+	}
+	if waitDur.Nanoseconds() < 0 || errors.IsNotImplemented(err) {
+		// Older API servers cannot notify us of results, so poll.
+		result, err = timerLoop(api, c.requestedId, wait, tick)
+	}
 	if err != nil {
 		return err
 	}
@@ -107,6 +115,17 @@ func (c *FetchCommand) Run(ctx *cmd.Context) error {
 	return c.out.Write(ctx, formatActionResult(result))
 }
 
+// waitForResult blocks until the action with the given ID prefix has
+// finished, or until "wait" times out, using the API's notification of
+// action results rather than polling.
+func waitForResult(api APIClient, requestedId string, wait *time.Timer) (params.ActionResult, error) {
+	actionTag, err := getActionTagByPrefix(api, requestedId)
+	if err != nil {
+		return params.ActionResult{}, err
+	}
+	return api.WaitForResult(actionTag, wait.C)
+}
+
 // timerLoop loops indefinitely to query the given API, until "wait" times
 // out, using the "tick" timer to delay the API queries.  It writes the
 // result to the given output.
@@ -287,6 +287,29 @@ timing:
 	}
 }
 
+func (s *FetchSuite) TestRunWaitsForResultNotification(c *gc.C) {
+	// The API delay is longer than the wait, so polling would only
+	// ever see a pending result.
+	client := makeFakeClient(
+		time.Minute,
+		time.Minute,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		[]params.ActionResult{{
+			Status:    "completed",
+			Enqueued:  time.Date(2015, time.February, 14, 8, 13, 0, 0, time.UTC),
+			Completed: time.Date(2015, time.February, 14, 8, 15, 30, 0, time.UTC),
+		}},
+		"",
+	)
+	client.notifyResults = true
+	testRunHelper(c, s, client, "", `
+status: completed
+timing:
+  completed: 2015-02-14 08:15:30 +0000 UTC
+  enqueued: 2015-02-14 08:13:00 +0000 UTC
+`[1:], "0", validActionId)
+}
+
 func testRunHelper(c *gc.C, s *FetchSuite, client *fakeAPIClient, expectedErr, expectedOutput, wait, query string) {
 	unpatch := s.BaseActionSuite.patchAPIClient(client)
 	defer unpatch()
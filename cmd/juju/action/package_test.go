@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	jujuerrors "github.com/juju/errors"
+	"github.com/juju/names"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -137,6 +139,7 @@ type fakeAPIClient struct {
 	actionTagMatches   params.FindTagsResults
 	charmActions       *charm.Actions
 	apiErr             error
+	notifyResults      bool
 }
 
 var _ action.APIClient = (*fakeAPIClient)(nil)
@@ -214,3 +217,15 @@ func (c *fakeAPIClient) Actions(args params.Entities) (params.ActionResults, err
 func (c *fakeAPIClient) FindActionTagsByPrefix(arg params.FindTags) (params.FindTagsResults, error) {
 	return c.actionTagMatches, c.apiErr
 }
+
+func (c *fakeAPIClient) WaitForResult(tag names.ActionTag, stop <-chan time.Time) (params.ActionResult, error) {
+	// Unless the test wants result notifications, behave like an
+	// API server that cannot send them.
+	if !c.notifyResults {
+		return params.ActionResult{}, jujuerrors.NotImplementedf("waiting for action results")
+	}
+	if len(c.actionResults) != 1 {
+		return params.ActionResult{}, errors.New("expected one action result")
+	}
+	return c.actionResults[0], c.apiErr
+}
@@ -5,17 +5,39 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
 
+const addRelationDoc = `
+Adds a relation between two services. If a service exposes more than one
+endpoint that could take part in the relation, the endpoint to use may be
+given as <service>:<relation name>.
+
+The --via option restricts the relations considered to those using the
+given interface, which helps to choose between endpoints without naming
+them. The --dry-run option reports which endpoints would be related, or
+why they could not be chosen, without adding the relation.
+
+Examples:
+    juju add-relation wordpress mysql
+    juju add-relation --via juju-info wordpress logging
+    juju add-relation --dry-run wordpress mysql
+`
+
 // AddRelationCommand adds a relation between two service endpoints.
 type AddRelationCommand struct {
 	envcmd.EnvCommandBase
 	Endpoints []string
+	Interface string
+	DryRun    bool
 }
 
 func (c *AddRelationCommand) Info() *cmd.Info {
@@ -23,9 +45,15 @@ func (c *AddRelationCommand) Info() *cmd.Info {
 		Name:    "add-relation",
 		Args:    "<service1>[:<relation name1>] <service2>[:<relation name2>]",
 		Purpose: "add a relation between two services",
+		Doc:     addRelationDoc,
 	}
 }
 
+func (c *AddRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Interface, "via", "", "only consider relations using this interface")
+	f.BoolVar(&c.DryRun, "dry-run", false, "report the endpoints that would be related without adding the relation")
+}
+
 func (c *AddRelationCommand) Init(args []string) error {
 	if len(args) != 2 {
 		return fmt.Errorf("a relation must involve two services")
@@ -34,12 +62,51 @@ func (c *AddRelationCommand) Init(args []string) error {
 	return nil
 }
 
-func (c *AddRelationCommand) Run(_ *cmd.Context) error {
+func (c *AddRelationCommand) Run(ctx *cmd.Context) error {
+	endpoints := c.Endpoints
+	if c.Interface != "" || c.DryRun {
+		inferred, err := c.inferEndpoints()
+		if err != nil {
+			return err
+		}
+		if c.DryRun {
+			fmt.Fprintf(ctx.Stdout, "would relate %s\n", strings.Join(inferred, " "))
+			return nil
+		}
+		endpoints = inferred
+	}
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
-	_, err = client.AddRelation(c.Endpoints...)
+	_, err = client.AddRelation(endpoints...)
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
+
+// inferEndpoints returns the fully qualified endpoints, in the order
+// given on the command line, that the environment would relate.
+func (c *AddRelationCommand) inferEndpoints() ([]string, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client := apiservice.NewClient(root)
+	defer client.Close()
+	relations, err := client.InferRelationEndpoints(c.Interface, c.Endpoints...)
+	if errors.IsNotImplemented(err) {
+		return nil, errors.New("cannot use --via or --dry-run: not supported by the API server")
+	} else if err != nil {
+		return nil, err
+	}
+	var inferred []string
+	for _, endpoint := range c.Endpoints {
+		serviceName := strings.SplitN(endpoint, ":", 2)[0]
+		relation, ok := relations[serviceName]
+		if !ok {
+			return nil, errors.Errorf("no endpoint reported for service %q", serviceName)
+		}
+		inferred = append(inferred, serviceName+":"+relation.Name)
+	}
+	return inferred, nil
+}
@@ -188,3 +188,49 @@ func (s *AddRelationSuite) TestBlockAddRelation(c *gc.C) {
 		}
 	}
 }
+
+func (s *AddRelationSuite) deployRelationCharms(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err := runDeploy(c, "local:wordpress", "wp")
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql-alternative")
+	err = runDeploy(c, "local:mysql-alternative", "ms")
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
+	err = runDeploy(c, "local:logging", "lg")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AddRelationSuite) TestAddRelationDryRun(c *gc.C) {
+	s.deployRelationCharms(c)
+
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AddRelationCommand{}), "--dry-run", "lg", "wp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "would relate lg:logging-directory wp:logging-dir\n")
+
+	ctx, err = testing.RunCommand(c, envcmd.Wrap(&AddRelationCommand{}), "--dry-run", "--via", "juju-info", "wp", "lg")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "would relate wp:juju-info lg:info\n")
+
+	err = runAddRelation(c, "--dry-run", "wp", "ms")
+	c.Assert(err, gc.ErrorMatches, `ambiguous relation: "wp ms" could refer to "wp:db ms:dev"; "wp:db ms:prod"`)
+
+	// Nothing was related.
+	relations, err := s.State.AllRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations, gc.HasLen, 0)
+}
+
+func (s *AddRelationSuite) TestAddRelationVia(c *gc.C) {
+	s.deployRelationCharms(c)
+
+	err := runAddRelation(c, "--via", "juju-info", "wp", "lg")
+	c.Assert(err, jc.ErrorIsNil)
+	err = runAddRelation(c, "--via", "mysql", "wp", "lg")
+	c.Assert(err, gc.ErrorMatches, `no relations found using interface "mysql"`)
+
+	relations, err := s.State.AllRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations, gc.HasLen, 1)
+	c.Assert(relations[0].String(), gc.Equals, "lg:info wp:juju-info")
+}
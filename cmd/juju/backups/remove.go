@@ -8,6 +8,8 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
 )
 
 const removeDoc = `
@@ -54,7 +56,7 @@ func (c *RemoveCommand) Run(ctx *cmd.Context) error {
 
 	err = client.Remove(c.ID)
 	if err != nil {
-		return errors.Trace(err)
+		return block.ProcessBlockedError(err, block.BlockRemove)
 	}
 
 	fmt.Fprintln(ctx.Stdout, "successfully removed:", c.ID)
@@ -6,11 +6,13 @@ package backups_test
 import (
 	"strings"
 
+	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/backups"
 	"github.com/juju/juju/testing"
 )
@@ -59,3 +61,17 @@ func (s *removeSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *removeSuite) TestBlocked(c *gc.C) {
+	s.patchAPIClient(&fakeAPIClient{err: &params.Error{
+		Code:    params.CodeOperationBlocked,
+		Message: "TestBlocked",
+	}})
+	s.subcommand.ID = "spam"
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+
+	c.Check(err, gc.Equals, cmd.ErrSilent)
+	c.Check(c.GetTestLog(), jc.Contains, "TestBlocked")
+	c.Check(c.GetTestLog(), jc.Contains, "juju unblock remove-object")
+}
@@ -106,11 +106,13 @@ To disable the block, run unblock command - see "juju help unblock".
 To by-pass the block, where available, run desired remove command with --force option.
 
 "juju block remove-object" blocks these commands:
+    backups remove
     destroy-environment
     remove-machine
     remove-relation
     remove-service
     remove-unit
+    user remove
    
 Examples:
    To prevent the machines, services, units and relations from being removed:
@@ -153,10 +155,14 @@ To by-pass the block, where available, run desired remove command with --force o
     authorised-keys add
     authorised-keys delete
     authorised-keys import
+    backups remove
     deploy
     destroy-environment
     ensure-availability
+    environment share
+    environment unshare
     expose
+    machine set-labels
     remove-machine
     remove-relation
     remove-service
@@ -177,6 +183,9 @@ To by-pass the block, where available, run desired remove command with --force o
     user change-password
     user disable
     user enable
+    user grant
+    user remove
+    user revoke
    
 Examples:
    To prevent changes to the environment:
@@ -95,8 +95,8 @@ func ProcessBlockedError(err error, block Block) error {
 }
 
 var removeMsg = `
-All operations that remove (or delete or terminate) machines, services, units or
-relations have been blocked for the current environment.
+All operations that remove (or delete or terminate) machines, services, units,
+relations or backups have been blocked for the current environment.
 To unblock removal, run
 
     juju unblock remove-object
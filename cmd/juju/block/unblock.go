@@ -37,6 +37,7 @@ destroy-environment includes command:
     destroy-environment
 
 remove-object includes termination commands:
+    backups remove
     destroy-environment
     remove-machine
     remove-relation
@@ -50,6 +51,7 @@ all-changes includes all alteration commands
     authorised-keys add
     authorised-keys delete
     authorised-keys import
+    backups remove
     deploy
     destroy-environment
     ensure-availability
@@ -68,6 +68,17 @@ Juju tools to cloud storage if no outgoing Internet access is available. In this
 use the --metadata-source paramater to tell bootstrap a local directory from which to
 upload tools and/or image metadata.
 
+In isolated data centers, use --offline together with --metadata-source to
+bootstrap without access to public networks. Tools and image metadata are then
+only read from the metadata source and from the agent-metadata-url and
+image-metadata-url settings, and, unless apt-mirror is set, package updates and
+upgrades are disabled in the environment. --offline only affects the bootstrap
+itself; to keep the environment offline afterwards, set agent-metadata-url,
+image-metadata-url and apt-mirror to locations inside the data center.
+
+With --verbose, the time taken to reach each stage of the bootstrap (instance
+started, tools selected, state initialized) is reported as it completes.
+
 See Also:
    juju help switch
    juju help constraints
@@ -86,6 +97,7 @@ type BootstrapCommand struct {
 	MetadataSource        string
 	Placement             string
 	KeepBrokenEnvironment bool
+	Offline               bool
 }
 
 func (c *BootstrapCommand) Info() *cmd.Info {
@@ -104,6 +116,7 @@ func (c *BootstrapCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.MetadataSource, "metadata-source", "", "local path to use as tools and/or metadata source")
 	f.StringVar(&c.Placement, "to", "", "a placement directive indicating an instance to bootstrap")
 	f.BoolVar(&c.KeepBrokenEnvironment, "keep-broken", false, "do not destroy the environment if bootstrap fails")
+	f.BoolVar(&c.Offline, "offline", false, "bootstrap without accessing public networks; requires --metadata-source")
 }
 
 func (c *BootstrapCommand) Init(args []string) (err error) {
@@ -116,6 +129,9 @@ func (c *BootstrapCommand) Init(args []string) (err error) {
 	if len(c.Series) > 0 && len(c.seriesOld) > 0 {
 		return fmt.Errorf("--upload-series and --series can't be used together")
 	}
+	if c.Offline && c.MetadataSource == "" {
+		return fmt.Errorf("--offline requires --metadata-source")
+	}
 
 	// Parse the placement directive. Bootstrap currently only
 	// supports provider-specific placement directives.
@@ -269,6 +285,10 @@ func (c *BootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 		Placement:   c.Placement,
 		UploadTools: c.UploadTools,
 		MetadataDir: metadataDir,
+		Offline:     c.Offline,
+		Progress: func(event bootstrap.ProgressEvent) {
+			ctx.Verbosef("%v", event)
+		},
 	})
 	if err != nil {
 		return errors.Annotate(err, "failed to bootstrap environment")
@@ -216,6 +216,10 @@ var bootstrapTests = []bootstrapTest{{
 	info: "--upload-series with --series",
 	args: []string{"--upload-tools", "--upload-series", "foo", "--series", "bar"},
 	err:  `--upload-series and --series can't be used together`,
+}, {
+	info: "lonely --offline",
+	args: []string{"--offline"},
+	err:  `--offline requires --metadata-source`,
 }, {
 	info:    "bad environment",
 	version: "1.2.3-%LTS%-amd64",
@@ -560,6 +564,25 @@ func (s *BootstrapSuite) TestBootstrapCalledWithMetadataDir(c *gc.C) {
 		"--metadata-source", sourceDir, "--constraints", "mem=4G",
 	)
 	c.Assert(_bootstrap.args.MetadataDir, gc.Equals, sourceDir)
+	c.Assert(_bootstrap.args.Offline, jc.IsFalse)
+	c.Assert(_bootstrap.args.Progress, gc.NotNil)
+}
+
+func (s *BootstrapSuite) TestBootstrapCalledOffline(c *gc.C) {
+	sourceDir, _ := createImageMetadata(c)
+	resetJujuHome(c, "devenv")
+
+	_bootstrap := &fakeBootstrapFuncs{}
+	s.PatchValue(&getBootstrapFuncs, func() BootstrapInterface {
+		return _bootstrap
+	})
+
+	coretesting.RunCommand(
+		c, envcmd.Wrap(&BootstrapCommand{}),
+		"--metadata-source", sourceDir, "--offline",
+	)
+	c.Assert(_bootstrap.args.MetadataDir, gc.Equals, sourceDir)
+	c.Assert(_bootstrap.args.Offline, jc.IsTrue)
 }
 
 func (s *BootstrapSuite) TestAutoSyncLocalSource(c *gc.C) {
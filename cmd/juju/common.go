@@ -21,8 +21,10 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/api"
+	apiservice "github.com/juju/juju/api/service"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
@@ -117,7 +119,10 @@ func environFromNameProductionFunc(
 // If it is a local charm URL, the local charm repository at
 // the given repoPath will be used. The given configuration
 // will be used to add any necessary attributes to the repo
-// and to resolve the default series if possible.
+// and to resolve the default series if possible. Failing that,
+// the charm store chooses the series of a charm store charm from
+// the charm's metadata; the chosen series is part of the returned
+// URL, which deploy reports.
 //
 // resolveCharmURL also returns the charm repository holding
 // the charm.
@@ -257,6 +262,36 @@ func (c *csClient) authorize(curl *charm.URL) (*macaroon.Macaroon, error) {
 	return m, nil
 }
 
+// withOrigin returns a copy of c that resolves charms from the charm
+// store held in the given origin. An empty store URL leaves the charm
+// store unchanged.
+func (c *csClient) withOrigin(origin params.CharmOrigin) *csClient {
+	client := *c
+	if origin.StoreURL != "" {
+		client.params.URL = origin.StoreURL
+	}
+	return &client
+}
+
+// origin returns the charm origin of charms resolved through c.
+func (c *csClient) origin() *params.CharmOrigin {
+	storeURL := c.params.URL
+	if storeURL == "" {
+		storeURL = csclient.ServerURL
+	}
+	return &params.CharmOrigin{StoreURL: storeURL}
+}
+
+// setCharmOrigin records the charm origin of the given service. If
+// the API server cannot record charm origins, no error is returned.
+func setCharmOrigin(client *apiservice.Client, serviceName string, origin *params.CharmOrigin) error {
+	err := client.SetCharmOrigin(serviceName, origin)
+	if params.IsCodeNotImplemented(err) || errors.IsNotImplemented(err) {
+		return nil
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
 // formatStatusTime returns a string with the local time
 // formatted in an arbitrary format used for status or
 // and localized tz or in utc timezone and format RFC3339
@@ -31,6 +31,14 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+The output of charm hooks is logged by module, so that it can be picked out
+with --include-module. Each hook's stdout and stderr are logged to the
+modules unit.<unit>.<hook>.stdout and unit.<unit>.<hook>.stderr, and, at
+DEBUG level, the start and end of each run of the hook are logged to
+unit.<unit>.<hook>. For example:
+
+    juju debug-log --include-module unit.mysql/0.config-changed.stderr
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/charmrepo"
 	"launchpad.net/gnuflag"
 
 	apiservice "github.com/juju/juju/api/service"
@@ -41,6 +42,14 @@ type DeployCommand struct {
 	// Storage is a map of storage constraints, keyed on the storage name
 	// defined in charm storage metadata.
 	Storage map[string]storage.Constraints
+
+	// AssignmentPolicy, if set, overrides the environment's
+	// unit-assignment-policy for units of the service.
+	AssignmentPolicy string
+
+	// Dev, if set, keeps the command running after a local charm is
+	// deployed, upgrading the service whenever the charm changes.
+	Dev bool
 }
 
 const deployDoc = `
@@ -111,6 +120,24 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+By default, new units of the service are assigned to machines according
+to the environment's unit-assignment-policy setting. The --assignment-policy
+argument overrides it for the service being deployed; it may be one of
+"clean" (prefer existing machines that have never hosted units),
+"clean-empty" (prefer existing machines that have never hosted units and
+host no containers), "new" (always create new machines) or "least-loaded"
+(prefer the existing machines hosting the fewest units).
+
+The charm store a charm is deployed from is recorded with the service, so
+that upgrade-charm resolves new revisions from the same charm store.
+
+When developing a local charm, the --dev argument keeps deploy running
+after the service is deployed, watching the charm for changes. Each time
+the charm changes, it is added to the environment with a new revision and
+the service is upgraded to it, as with upgrade-charm. Interrupt the
+command to stop watching. For example:
+   juju deploy --dev --repository ~/charms local:trusty/mysql
+
 See Also:
    juju help constraints
    juju help set-constraints
@@ -136,6 +163,8 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.StringVar(&c.AssignmentPolicy, "assignment-policy", "", "policy for assigning the service's units to machines")
+	f.BoolVar(&c.Dev, "dev", false, "upgrade the service whenever the local charm changes")
 }
 
 func (c *DeployCommand) Init(args []string) error {
@@ -192,6 +221,10 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.Dev && curl.Schema != "local" {
+		return errors.New("--dev can only be used with local charms")
+	}
+	localURL := curl
 
 	curl, err = addCharmViaAPI(client, ctx, curl, repo, csClient)
 	if err != nil {
@@ -246,9 +279,13 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	// If storage is specified, we attempt to use a new API on the service facade.
-	if len(c.Storage) > 0 {
+	// If storage or an assignment policy is specified, we attempt to use
+	// a new API on the service facade.
+	if len(c.Storage) > 0 || c.AssignmentPolicy != "" {
 		notSupported := errors.New("cannot deploy charms with storage: not supported by the API server")
+		if len(c.Storage) == 0 {
+			notSupported = errors.New("cannot use --assignment-policy: not supported by the API server")
+		}
 		serviceClient, err := c.newServiceAPIClient()
 		if err != nil {
 			return notSupported
@@ -263,11 +300,15 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			c.ToMachineSpec,
 			requestedNetworks,
 			c.Storage,
+			c.AssignmentPolicy,
 		)
-		if params.IsCodeNotImplemented(err) {
+		if params.IsCodeNotImplemented(err) || errors.IsNotImplemented(err) {
 			return notSupported
 		}
-		return block.ProcessBlockedError(err, block.BlockChange)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		return c.afterDeploy(ctx, client, repo, localURL, curl, serviceName, csClient)
 	}
 
 	err = client.ServiceDeployWithNetworks(
@@ -291,7 +332,49 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			c.Constraints,
 			c.ToMachineSpec)
 	}
-	return block.ProcessBlockedError(err, block.BlockChange)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return c.afterDeploy(ctx, client, repo, localURL, curl, serviceName, csClient)
+}
+
+// afterDeploy records the origin of a deployed charm store charm, or
+// with --dev watches a deployed local charm for changes. The local
+// charm is identified by localURL, the URL it was resolved to in the
+// local repository.
+func (c *DeployCommand) afterDeploy(
+	ctx *cmd.Context,
+	client devCharmClient,
+	repo charmrepo.Interface,
+	localURL, curl *charm.URL,
+	serviceName string,
+	csClient *csClient,
+) error {
+	if err := c.recordCharmOrigin(curl, serviceName, csClient); err != nil {
+		return err
+	}
+	if !c.Dev {
+		return nil
+	}
+	stop := make(chan os.Signal, 1)
+	ctx.InterruptNotify(stop)
+	defer ctx.StopInterruptNotify(stop)
+	return watchLocalCharm(ctx, client, repo, localURL, serviceName, stop)
+}
+
+// recordCharmOrigin records the charm store that the service's charm
+// was deployed from, so that upgrade-charm resolves new revisions from
+// the same charm store. Only charm store charms have an origin.
+func (c *DeployCommand) recordCharmOrigin(curl *charm.URL, serviceName string, csClient *csClient) error {
+	if curl.Schema != "cs" {
+		return nil
+	}
+	serviceClient, err := c.newServiceAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer serviceClient.Close()
+	return setCharmOrigin(serviceClient, serviceName, csClient.origin())
 }
 
 // parseNetworks returns a list of network names by parsing the
@@ -244,6 +244,23 @@ func (s *DeploySuite) TestStorage(c *gc.C) {
 	})
 }
 
+func (s *DeploySuite) TestAssignmentPolicy(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "--assignment-policy", "least-loaded")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	service, _ := s.AssertService(c, "dummy", curl, 1, 0)
+	policy, ok := service.AssignmentPolicy()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(policy, gc.Equals, state.AssignLeastLoaded)
+}
+
+func (s *DeploySuite) TestInvalidAssignmentPolicy(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "--assignment-policy", "local")
+	c.Assert(err, gc.ErrorMatches, `unit assignment policy "local" not valid`)
+}
+
 func (s *DeploySuite) TestSubordinateConstraints(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
 	err := runDeploy(c, "local:logging", "--constraints", "mem=1G")
@@ -441,6 +458,27 @@ func (s *DeployCharmStoreSuite) TestDeployAuthorization(c *gc.C) {
 	}
 }
 
+func (s *DeployCharmStoreSuite) TestDeploySeriesChosenByCharmStore(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"default-series": ""}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.uploadCharm(c, "cs:~bob/precise/wordpress-10", "wordpress")
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "cs:~bob/wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	output := strings.Trim(coretesting.Stderr(ctx), "\n")
+	c.Assert(output, gc.Equals, `Added charm "cs:~bob/precise/wordpress-10" to the environment.`)
+}
+
+func (s *DeployCharmStoreSuite) TestDeployRecordsCharmOrigin(c *gc.C) {
+	s.uploadCharm(c, "cs:trusty/wordpress-3", "wordpress")
+	err := runDeploy(c, "cs:trusty/wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	origin, ok := svc.CharmOrigin()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(origin, jc.DeepEquals, state.CharmOrigin{StoreURL: s.srv.URL()})
+}
+
 const (
 	// clientUserCookie is the name of the cookie which is
 	// used to signal to the charmStoreSuite macaroon discharger
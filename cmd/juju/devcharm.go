@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/charmrepo"
+)
+
+// devPollInterval holds how often deploy --dev checks the local
+// charm for changes.
+var devPollInterval = 2 * time.Second
+
+// devCharmClient holds the API client methods used to upgrade a
+// service to a changed local charm.
+type devCharmClient interface {
+	AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, error)
+	ServiceSetCharm(serviceName string, charmURL string, force bool) error
+}
+
+// localCharmPath returns the path of the directory or archive that
+// the given local repository holds the charm with the given URL in.
+func localCharmPath(repo charmrepo.Interface, curl *charm.URL) (string, error) {
+	ch, err := repo.Get(curl.WithRevision(-1))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	switch ch := ch.(type) {
+	case *charm.CharmDir:
+		return ch.Path, nil
+	case *charm.CharmArchive:
+		return ch.Path, nil
+	}
+	return "", errors.Errorf("cannot watch charm %q: unexpected charm type %T", curl, ch)
+}
+
+// charmSnapshot returns a digest of the names, sizes and modification
+// times of the files at path, so that changes to a charm directory or
+// archive can be detected cheaply. Version control directories are
+// ignored.
+func charmSnapshot(path string) (string, error) {
+	hash := sha256.New()
+	err := filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && name != path && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		fmt.Fprintf(hash, "%s %d %d %v\n", name, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		return nil
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// watchLocalCharm watches the local charm with the given URL and,
+// each time it changes, adds it to the environment with a new
+// revision and upgrades the named service to it. It returns when
+// a value is received on stop. Failures to upgrade are reported
+// but do not stop the watch, so that a charm can be fixed while
+// it is being developed.
+func watchLocalCharm(
+	ctx *cmd.Context,
+	client devCharmClient,
+	repo charmrepo.Interface,
+	curl *charm.URL,
+	serviceName string,
+	stop <-chan os.Signal,
+) error {
+	path, err := localCharmPath(repo, curl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	last, err := charmSnapshot(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Watching %s for changes; interrupt to stop.", path)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(devPollInterval):
+		}
+		snapshot, err := charmSnapshot(path)
+		if err != nil {
+			ctx.Infof("cannot read charm: %v", err)
+			continue
+		}
+		if snapshot == last {
+			continue
+		}
+		last = snapshot
+		if err := upgradeToLocalCharm(ctx, client, repo, curl, serviceName); err != nil {
+			ctx.Infof("cannot upgrade %q: %v", serviceName, err)
+		}
+	}
+}
+
+// upgradeToLocalCharm adds the local charm with the given URL to the
+// environment and upgrades the named service to it.
+func upgradeToLocalCharm(
+	ctx *cmd.Context,
+	client devCharmClient,
+	repo charmrepo.Interface,
+	curl *charm.URL,
+	serviceName string,
+) error {
+	curl = curl.WithRevision(-1)
+	ch, err := repo.Get(curl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	addedURL, err := client.AddLocalCharm(curl, ch)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := client.ServiceSetCharm(serviceName, addedURL.String(), false); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Upgraded %q to charm %q.", serviceName, addedURL)
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/charmrepo"
+
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type devCharmSuite struct {
+	coretesting.FakeJujuHomeSuite
+	repo     charmrepo.Interface
+	charmDir string
+}
+
+var _ = gc.Suite(&devCharmSuite{})
+
+func (s *devCharmSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	repoPath := c.MkDir()
+	seriesPath := filepath.Join(repoPath, "quantal")
+	err := os.Mkdir(seriesPath, 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	s.charmDir = testcharms.Repo.ClonedDirPath(seriesPath, "dummy")
+	s.repo = &charmrepo.LocalRepository{Path: repoPath}
+	s.PatchValue(&devPollInterval, 10*time.Millisecond)
+}
+
+func (s *devCharmSuite) TestCharmSnapshot(c *gc.C) {
+	before, err := charmSnapshot(s.charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	again, err := charmSnapshot(s.charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, before)
+
+	// Changes in version control directories are ignored.
+	err = os.Mkdir(filepath.Join(s.charmDir, ".bzr"), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(s.charmDir, ".bzr", "branch"), []byte("x"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	again, err = charmSnapshot(s.charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, before)
+
+	err = ioutil.WriteFile(filepath.Join(s.charmDir, "hooks", "new-hook"), []byte("#!/bin/sh\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	after, err := charmSnapshot(s.charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(after, gc.Not(gc.Equals), before)
+}
+
+func (s *devCharmSuite) TestWatchLocalCharmUpgradesOnChange(c *gc.C) {
+	client := &fakeDevCharmClient{upgraded: make(chan string, 10)}
+	curl := charm.MustParseURL("local:quantal/dummy-1")
+	ctx := coretesting.Context(c)
+	stop := make(chan os.Signal)
+	done := make(chan error, 1)
+	go func() {
+		done <- watchLocalCharm(ctx, client, s.repo, curl, "dummy", stop)
+	}()
+
+	// Nothing happens until the charm changes.
+	select {
+	case url := <-client.upgraded:
+		c.Fatalf("unexpected upgrade to %q", url)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	err := ioutil.WriteFile(filepath.Join(s.charmDir, "hooks", "new-hook"), []byte("#!/bin/sh\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case url := <-client.upgraded:
+		c.Assert(url, gc.Equals, "local:quantal/dummy-42")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("service not upgraded")
+	}
+	c.Assert(client.added, jc.DeepEquals, []string{"local:quantal/dummy"})
+
+	stop <- os.Interrupt
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("watch did not stop")
+	}
+	c.Assert(coretesting.Stderr(ctx), gc.Matches, `(?s).*Upgraded "dummy" to charm "local:quantal/dummy-42"\..*`)
+}
+
+type fakeDevCharmClient struct {
+	added    []string
+	upgraded chan string
+}
+
+func (f *fakeDevCharmClient) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, error) {
+	f.added = append(f.added, curl.String())
+	return curl.WithRevision(42), nil
+}
+
+func (f *fakeDevCharmClient) ServiceSetCharm(serviceName string, charmURL string, force bool) error {
+	f.upgraded <- charmURL
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/diagnose"
+	"github.com/juju/juju/api/workerhealth"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const diagnoseDoc = `
+Show problems with the environment that are otherwise hard to spot.
+
+The API server checks its own host for broken DNS, and its clock is
+compared with this machine's; clocks that disagree by more than a minute
+cause certificate and authorization failures.
+
+The agent workers that are not healthy, as last reported by their agents,
+are also shown. A worker is unhealthy if it is not running, if it has
+failed by exiting too often in quick succession, or if it last exited
+with an error; a worker that keeps restarting with the same error is
+likely to be crash-looping, for example on a provider authentication
+error. Agents keep restarting failed workers, less often than others.
+
+Agents report the health of their workers every minute. An agent that
+has not reported for more than five minutes is shown as stale, whatever
+the health of its workers; it may be down, or unable to reach the API
+server.
+
+Each worker is named after the runner that runs it within its agent,
+so "env-<uuid>/addresser" on machine-0 is the addresser worker that the
+state server runs for that environment.
+
+Examples:
+    juju diagnose
+    juju diagnose --all --format json
+`
+
+// maxClockSkew is the largest difference between the clocks of the
+// client and the API server that is not reported as a problem.
+const maxClockSkew = time.Minute
+
+// maxReportAge is the longest time since an agent last reported the
+// health of its workers before its report is considered stale.
+const maxReportAge = 5 * time.Minute
+
+// DiagnoseCommand shows problems with the environment.
+type DiagnoseCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+	all bool
+	api DiagnoseAPI
+}
+
+// DiagnoseAPI defines the API methods that the diagnose command uses.
+type DiagnoseAPI interface {
+	Close() error
+	ServerChecks() (params.ServerChecks, error)
+	AllWorkerHealth() ([]params.AgentWorkerHealth, error)
+}
+
+// diagnoseAPI combines the clients of the facades used by the
+// diagnose command.
+type diagnoseAPI struct {
+	*workerhealth.Client
+	diagnose *diagnose.Client
+}
+
+func (api diagnoseAPI) ServerChecks() (params.ServerChecks, error) {
+	return api.diagnose.ServerChecks()
+}
+
+// WorkerHealthInfo defines the serialization behaviour of the health
+// of an agent's worker.
+type WorkerHealthInfo struct {
+	Name          string `yaml:"name" json:"name"`
+	Running       bool   `yaml:"running" json:"running"`
+	Restarts      int    `yaml:"restarts" json:"restarts"`
+	LastError     string `yaml:"last-error,omitempty" json:"last-error,omitempty"`
+	LastErrorTime string `yaml:"last-error-time,omitempty" json:"last-error-time,omitempty"`
+	Failed        bool   `yaml:"failed,omitempty" json:"failed,omitempty"`
+}
+
+// AgentHealthInfo defines the serialization behaviour of the health
+// of an agent's workers.
+type AgentHealthInfo struct {
+	Updated  string             `yaml:"updated" json:"updated"`
+	Reported string             `yaml:"reported,omitempty" json:"reported,omitempty"`
+	Stale    bool               `yaml:"stale,omitempty" json:"stale,omitempty"`
+	Workers  []WorkerHealthInfo `yaml:"workers,omitempty" json:"workers,omitempty"`
+}
+
+// ServerChecksInfo defines the serialization behaviour of the results
+// of the API server's checks of its host.
+type ServerChecksInfo struct {
+	Time     string   `yaml:"time" json:"time"`
+	Problems []string `yaml:"problems,omitempty" json:"problems,omitempty"`
+}
+
+// DiagnoseInfo defines the serialization behaviour of the problems
+// found by the diagnose command.
+type DiagnoseInfo struct {
+	APIServer *ServerChecksInfo          `yaml:"api-server,omitempty" json:"api-server,omitempty"`
+	Agents    map[string]AgentHealthInfo `yaml:"agents,omitempty" json:"agents,omitempty"`
+}
+
+func (c *DiagnoseCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diagnose",
+		Args:    "[--all]",
+		Purpose: "show problems with the environment",
+		Doc:     diagnoseDoc,
+	}
+}
+
+func (c *DiagnoseCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.BoolVar(&c.all, "all", false, "show the results of all checks and healthy workers too")
+}
+
+func (c *DiagnoseCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *DiagnoseCommand) getAPI() (DiagnoseAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return diagnoseAPI{
+		Client:   workerhealth.NewClient(root),
+		diagnose: diagnose.NewClient(root),
+	}, nil
+}
+
+func (c *DiagnoseCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	var result DiagnoseInfo
+	sent := time.Now()
+	// Report ages are measured against the API server's clock when
+	// it is known, so that clock skew does not make reports stale.
+	now := sent
+	checks, err := api.ServerChecks()
+	if params.IsCodeNotImplemented(err) {
+		logger.Debugf("server checks not supported by the API server")
+	} else if err != nil {
+		return errors.Trace(err)
+	} else {
+		now = checks.Time
+		problems := checks.Problems
+		if skew := clockSkewProblem(checks.Time, sent, time.Now()); skew != "" {
+			problems = append(problems, skew)
+		}
+		if c.all || len(problems) > 0 {
+			result.APIServer = &ServerChecksInfo{
+				Time:     checks.Time.UTC().Format(time.RFC3339),
+				Problems: problems,
+			}
+		}
+	}
+
+	reports, err := api.AllWorkerHealth()
+	if params.IsCodeNotImplemented(err) {
+		logger.Debugf("worker health not supported by the API server")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, report := range reports {
+		var workers []WorkerHealthInfo
+		for _, w := range report.Workers {
+			if !c.all && workerHealthy(w) {
+				continue
+			}
+			info := WorkerHealthInfo{
+				Name:      w.Name,
+				Running:   w.Running,
+				Restarts:  w.Restarts,
+				LastError: w.LastError,
+				Failed:    w.Failed,
+			}
+			if !w.LastErrorTime.IsZero() {
+				info.LastErrorTime = w.LastErrorTime.UTC().Format(time.RFC3339)
+			}
+			workers = append(workers, info)
+		}
+		// Servers that predate report times leave Reported zero.
+		stale := !report.Reported.IsZero() && now.Sub(report.Reported) > maxReportAge
+		if len(workers) == 0 && !stale {
+			continue
+		}
+		if result.Agents == nil {
+			result.Agents = make(map[string]AgentHealthInfo)
+		}
+		info := AgentHealthInfo{
+			Updated: report.Updated.UTC().Format(time.RFC3339),
+			Stale:   stale,
+			Workers: workers,
+		}
+		if !report.Reported.IsZero() {
+			info.Reported = report.Reported.UTC().Format(time.RFC3339)
+		}
+		result.Agents[report.Tag] = info
+	}
+	if result.APIServer == nil && result.Agents == nil {
+		ctx.Infof("no problems found")
+	}
+	return c.out.Write(ctx, result)
+}
+
+// clockSkewProblem describes the difference between the API server's
+// clock and this machine's, if it is larger than maxClockSkew. The
+// server's time was taken between the sent and received times, and
+// is compared with whichever of those is closer.
+func clockSkewProblem(serverTime, sent, received time.Time) string {
+	switch {
+	case serverTime.Before(sent.Add(-maxClockSkew)):
+		skew := sent.Sub(serverTime)
+		return fmt.Sprintf("API server clock is %v behind this machine's clock", skew-skew%time.Second)
+	case serverTime.After(received.Add(maxClockSkew)):
+		skew := serverTime.Sub(received)
+		return fmt.Sprintf("API server clock is %v ahead of this machine's clock", skew-skew%time.Second)
+	}
+	return ""
+}
+
+// workerHealthy reports whether the given worker is running and has
+// not exited with an error.
+func workerHealthy(w params.WorkerHealth) bool {
+	return w.Running && !w.Failed && w.LastError == ""
+}
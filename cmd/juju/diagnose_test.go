@@ -0,0 +1,182 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type DiagnoseSuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeDiagnoseAPI
+}
+
+var _ = gc.Suite(&DiagnoseSuite{})
+
+type fakeDiagnoseAPI struct {
+	checks    params.ServerChecks
+	checksErr error
+	reports   []params.AgentWorkerHealth
+	err       error
+}
+
+func (f *fakeDiagnoseAPI) Close() error {
+	return nil
+}
+
+func (f *fakeDiagnoseAPI) ServerChecks() (params.ServerChecks, error) {
+	if f.checks.Time.IsZero() {
+		f.checks.Time = time.Now()
+	}
+	return f.checks, f.checksErr
+}
+
+func (f *fakeDiagnoseAPI) AllWorkerHealth() ([]params.AgentWorkerHealth, error) {
+	return f.reports, f.err
+}
+
+func (s *DiagnoseSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.fake = &fakeDiagnoseAPI{
+		reports: []params.AgentWorkerHealth{{
+			Tag:     "machine-0",
+			Updated: t0,
+			Workers: []params.WorkerHealth{{
+				Name:    "state/machiner",
+				Running: true,
+			}, {
+				Name:          "env-deadbeef/addresser",
+				Restarts:      12,
+				LastError:     "authentication failed",
+				LastErrorTime: t0.Add(-time.Minute),
+			}},
+		}, {
+			Tag:     "unit-mysql-0",
+			Updated: t0,
+			Workers: []params.WorkerHealth{{
+				Name:    "api/uniter",
+				Running: true,
+			}},
+		}},
+	}
+}
+
+func (s *DiagnoseSuite) runDiagnose(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &DiagnoseCommand{api: s.fake}
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *DiagnoseSuite) TestInit(c *gc.C) {
+	_, err := s.runDiagnose(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *DiagnoseSuite) TestDiagnose(c *gc.C) {
+	ctx, err := s.runDiagnose(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+agents:
+  machine-0:
+    updated: 2015-06-01T12:00:00Z
+    workers:
+    - name: env-deadbeef/addresser
+      running: false
+      restarts: 12
+      last-error: authentication failed
+      last-error-time: 2015-06-01T11:59:00Z
+`[1:])
+}
+
+func (s *DiagnoseSuite) TestDiagnoseStaleReport(c *gc.C) {
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.fake.checks.Time = t0.Add(10 * time.Minute)
+	s.fake.reports[0].Reported = t0.Add(9 * time.Minute)
+	s.fake.reports[1].Reported = t0.Add(time.Minute)
+	ctx, err := s.runDiagnose(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Matches, `
+api-server:
+(.|\n)*
+agents:
+  machine-0:
+    updated: 2015-06-01T12:00:00Z
+    reported: 2015-06-01T12:09:00Z
+    workers:
+    - name: env-deadbeef/addresser
+      running: false
+      restarts: 12
+      last-error: authentication failed
+      last-error-time: 2015-06-01T11:59:00Z
+  unit-mysql-0:
+    updated: 2015-06-01T12:00:00Z
+    reported: 2015-06-01T12:01:00Z
+    stale: true
+`[1:])
+}
+
+func (s *DiagnoseSuite) TestDiagnoseServerProblems(c *gc.C) {
+	s.fake.reports = nil
+	serverTime := time.Now().Add(2 * time.Hour)
+	s.fake.checks = params.ServerChecks{
+		Time:     serverTime,
+		Problems: []string{"no DNS nameservers configured in /etc/resolv.conf"},
+	}
+	ctx, err := s.runDiagnose(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Matches, `
+api-server:
+  time: `+serverTime.UTC().Format(time.RFC3339)+`
+  problems:
+  - no DNS nameservers configured in /etc/resolv.conf
+  - API server clock is 1h59m5\ds ahead of this machine's clock
+`[1:])
+}
+
+func (s *DiagnoseSuite) TestDiagnoseAll(c *gc.C) {
+	s.fake.reports = s.fake.reports[1:]
+	s.fake.checks.Time = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx, err := s.runDiagnose(c, "--all", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Matches,
+		`{"api-server":{"time":"2015-06-01T12:00:00Z","problems":\["API server clock is .* behind this machine's clock"\]},`+
+			`"agents":{"unit-mysql-0":{"updated":"2015-06-01T12:00:00Z","workers":\[{"name":"api/uniter","running":true,"restarts":0}\]}}}`+"\n")
+}
+
+func (s *DiagnoseSuite) TestDiagnoseAllHealthy(c *gc.C) {
+	s.fake.reports = s.fake.reports[1:]
+	ctx, err := s.runDiagnose(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "no problems found\n")
+}
+
+func (s *DiagnoseSuite) TestDiagnoseNotImplemented(c *gc.C) {
+	notImplemented := &params.Error{Code: params.CodeNotImplemented, Message: "not implemented"}
+	s.fake.checksErr = notImplemented
+	s.fake.err = notImplemented
+	ctx, err := s.runDiagnose(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "no problems found\n")
+}
+
+func (s *DiagnoseSuite) TestDiagnoseServerChecksError(c *gc.C) {
+	s.fake.checksErr = errors.New("boom")
+	_, err := s.runDiagnose(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *DiagnoseSuite) TestDiagnoseError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.runDiagnose(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
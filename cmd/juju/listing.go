@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/listing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listMachinesDoc = `
+List the machines in the environment.
+
+Unlike "juju status", the output has a fixed layout that does not
+change between releases, making it suitable for use in scripts.
+Machines are listed in order of id.
+
+Examples:
+    juju list-machines
+    juju list-machines --format json
+`
+
+const listUnitsDoc = `
+List the units in the environment.
+
+Unlike "juju status", the output has a fixed layout that does not
+change between releases, making it suitable for use in scripts.
+Units are listed in order of service name and unit number.
+
+Examples:
+    juju list-units
+    juju list-units --format json
+`
+
+// ListingAPI defines the API methods that the list-machines and
+// list-units commands use.
+type ListingAPI interface {
+	Close() error
+	ListMachines() ([]params.ListedMachine, error)
+	ListUnits() ([]params.ListedUnit, error)
+}
+
+// MachineListing defines the serialization behaviour of a machine
+// listed by list-machines.
+type MachineListing struct {
+	Id         string   `yaml:"id" json:"id"`
+	InstanceId string   `yaml:"instance-id" json:"instance-id"`
+	Series     string   `yaml:"series" json:"series"`
+	Life       string   `yaml:"life" json:"life"`
+	Status     string   `yaml:"status" json:"status"`
+	StatusInfo string   `yaml:"status-info" json:"status-info"`
+	DNSName    string   `yaml:"dns-name" json:"dns-name"`
+	Jobs       []string `yaml:"jobs" json:"jobs"`
+}
+
+// UnitListing defines the serialization behaviour of a unit listed
+// by list-units.
+type UnitListing struct {
+	Name           string `yaml:"name" json:"name"`
+	Service        string `yaml:"service" json:"service"`
+	Machine        string `yaml:"machine" json:"machine"`
+	Life           string `yaml:"life" json:"life"`
+	AgentStatus    string `yaml:"agent-status" json:"agent-status"`
+	WorkloadStatus string `yaml:"workload-status" json:"workload-status"`
+	StatusInfo     string `yaml:"status-info" json:"status-info"`
+	PublicAddress  string `yaml:"public-address" json:"public-address"`
+}
+
+// listingCommandBase holds what is common to the list-machines and
+// list-units commands.
+type listingCommandBase struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+	api ListingAPI
+}
+
+func (c *listingCommandBase) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *listingCommandBase) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *listingCommandBase) getAPI() (ListingAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return listing.NewClient(root), nil
+}
+
+// ListMachinesCommand lists the machines in an environment.
+type ListMachinesCommand struct {
+	listingCommandBase
+}
+
+func (c *ListMachinesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-machines",
+		Purpose: "list the machines in the environment",
+		Doc:     listMachinesDoc,
+	}
+}
+
+func (c *ListMachinesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	machines, err := api.ListMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	listings := make([]MachineListing, len(machines))
+	for i, m := range machines {
+		jobs := make([]string, len(m.Jobs))
+		for j, job := range m.Jobs {
+			jobs[j] = string(job)
+		}
+		listings[i] = MachineListing{
+			Id:         m.Id,
+			InstanceId: string(m.InstanceId),
+			Series:     m.Series,
+			Life:       string(m.Life),
+			Status:     string(m.Status),
+			StatusInfo: m.StatusInfo,
+			DNSName:    m.DNSName,
+			Jobs:       jobs,
+		}
+	}
+	return c.out.Write(ctx, map[string][]MachineListing{"machines": listings})
+}
+
+// ListUnitsCommand lists the units in an environment.
+type ListUnitsCommand struct {
+	listingCommandBase
+}
+
+func (c *ListUnitsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-units",
+		Purpose: "list the units in the environment",
+		Doc:     listUnitsDoc,
+	}
+}
+
+func (c *ListUnitsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	units, err := api.ListUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	listings := make([]UnitListing, len(units))
+	for i, u := range units {
+		listings[i] = UnitListing{
+			Name:           u.Name,
+			Service:        u.Service,
+			Machine:        u.Machine,
+			Life:           string(u.Life),
+			AgentStatus:    string(u.AgentStatus),
+			WorkloadStatus: string(u.WorkloadStatus),
+			StatusInfo:     u.StatusInfo,
+			PublicAddress:  u.PublicAddress,
+		}
+	}
+	return c.out.Write(ctx, map[string][]UnitListing{"units": listings})
+}
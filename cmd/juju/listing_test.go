@@ -0,0 +1,148 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/multiwatcher"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ListingSuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeListingAPI
+}
+
+var _ = gc.Suite(&ListingSuite{})
+
+type fakeListingAPI struct {
+	machines []params.ListedMachine
+	units    []params.ListedUnit
+	err      error
+}
+
+func (f *fakeListingAPI) Close() error {
+	return nil
+}
+
+func (f *fakeListingAPI) ListMachines() ([]params.ListedMachine, error) {
+	return f.machines, f.err
+}
+
+func (f *fakeListingAPI) ListUnits() ([]params.ListedUnit, error) {
+	return f.units, f.err
+}
+
+func (s *ListingSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeListingAPI{
+		machines: []params.ListedMachine{{
+			Id:         "0",
+			InstanceId: "i-0",
+			Series:     "trusty",
+			Life:       "alive",
+			Status:     params.StatusStarted,
+			DNSName:    "0.1.2.3",
+			Jobs:       []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		}},
+		units: []params.ListedUnit{{
+			Name:           "mysql/0",
+			Service:        "mysql",
+			Machine:        "0",
+			Life:           "alive",
+			AgentStatus:    params.StatusIdle,
+			WorkloadStatus: params.StatusActive,
+			StatusInfo:     "ready",
+			PublicAddress:  "0.1.2.3",
+		}},
+	}
+}
+
+func (s *ListingSuite) runListMachines(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &ListMachinesCommand{}
+	command.api = s.fake
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *ListingSuite) runListUnits(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &ListUnitsCommand{}
+	command.api = s.fake
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *ListingSuite) TestInit(c *gc.C) {
+	_, err := s.runListMachines(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	_, err = s.runListUnits(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ListingSuite) TestListMachines(c *gc.C) {
+	ctx, err := s.runListMachines(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+machines:
+- id: "0"
+  instance-id: i-0
+  series: trusty
+  life: alive
+  status: started
+  status-info: ""
+  dns-name: 0.1.2.3
+  jobs:
+  - JobHostUnits
+`[1:])
+}
+
+func (s *ListingSuite) TestListMachinesJSON(c *gc.C) {
+	ctx, err := s.runListMachines(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals,
+		`{"machines":[{"id":"0","instance-id":"i-0","series":"trusty","life":"alive","status":"started","status-info":"","dns-name":"0.1.2.3","jobs":["JobHostUnits"]}]}`+"\n")
+}
+
+func (s *ListingSuite) TestListMachinesEmpty(c *gc.C) {
+	s.fake.machines = nil
+	ctx, err := s.runListMachines(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `{"machines":[]}`+"\n")
+}
+
+func (s *ListingSuite) TestListUnits(c *gc.C) {
+	ctx, err := s.runListUnits(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+units:
+- name: mysql/0
+  service: mysql
+  machine: "0"
+  life: alive
+  agent-status: idle
+  workload-status: active
+  status-info: ready
+  public-address: 0.1.2.3
+`[1:])
+}
+
+func (s *ListingSuite) TestListUnitsJSON(c *gc.C) {
+	ctx, err := s.runListUnits(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals,
+		`{"units":[{"name":"mysql/0","service":"mysql","machine":"0","life":"alive","agent-status":"idle","workload-status":"active","status-info":"ready","public-address":"0.1.2.3"}]}`+"\n")
+}
+
+func (s *ListingSuite) TestListError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.runListMachines(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	_, err = s.runListUnits(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -4,8 +4,11 @@
 package machine
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -42,7 +45,9 @@ Without any parameters, add machine will allocate a new provider-specific
 machine (multiple, if "-n" is provided). When adding a new machine, you
 may specify constraints for the machine to be provisioned; the provider
 will interpret these constraints in order to decide what kind of machine
-to allocate.
+to allocate. Where the API server supports it, the machines requested
+with "-n" are added to the environment together: either all of them are
+added, or none are.
 
 If a container type is specified (e.g. "lxc"), then add machine will
 allocate a container of that type on a new provider-specific machine. It is
@@ -54,7 +59,10 @@ container types are: $CONTAINER_TYPES$.
 Manual provisioning is the process of installing Juju on an existing machine
 and bringing it under Juju's management; currently this requires that the
 machine be running Ubuntu, that it be accessible via SSH, and be running on
-the same network as the API server.
+the same network as the API server. Several ssh: targets may be given, in
+which case the machines are provisioned concurrently; the output for each
+is prefixed with its host, and a machine that fails to provision is
+removed from the environment again.
 
 It is possible to override or augment constraints by passing provider-specific
 "placement directives" with "--to"; these give the provider additional
@@ -68,8 +76,11 @@ Examples:
    juju machine add lxc                  (starts a new machine with an lxc container)
    juju machine add lxc -n 2             (starts 2 new machines with an lxc container)
    juju machine add lxc:4                (starts a new lxc container on machine 4)
+   juju machine add lxc:label=gpu        (starts a new lxc container on a machine labelled "gpu")
    juju machine add --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju machine add ssh:user@10.10.0.3   (manually provisions a machine with ssh)
+   juju machine add ssh:10.10.0.3 ssh:10.10.0.4
+                                         (manually provisions two machines at once)
    juju machine add zone=us-east-1a
 
 See Also:
@@ -101,6 +112,9 @@ type AddCommand struct {
 	Constraints constraints.Value
 	// Placement is passed verbatim to the API, to be parsed and evaluated server-side.
 	Placement *instance.Placement
+	// SSHHosts holds the [user@]host targets to provision manually
+	// when more than one ssh: target is given.
+	SSHHosts []string
 	// NumMachines is the number of machines to add.
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
@@ -110,7 +124,7 @@ type AddCommand struct {
 func (c *AddCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "add",
-		Args:    "[<container>:machine | <container> | ssh:[user@]host | placement]",
+		Args:    "[<container>:machine | <container> | ssh:[user@]host ... | placement]",
 		Purpose: "start a new, empty machine and optionally a container, or add a container to a machine",
 		Doc:     addMachineDoc,
 	}
@@ -127,6 +141,18 @@ func (c *AddCommand) Init(args []string) error {
 	if c.Constraints.Container != nil {
 		return fmt.Errorf("container constraint %q not allowed when adding a machine", *c.Constraints.Container)
 	}
+	if len(args) > 1 && strings.HasPrefix(args[0], sshHostPrefix) {
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, sshHostPrefix) {
+				return fmt.Errorf("cannot mix ssh: targets with other placement %q", arg)
+			}
+			c.SSHHosts = append(c.SSHHosts, arg[len(sshHostPrefix):])
+		}
+		if c.NumMachines > 1 {
+			return fmt.Errorf("cannot use -n when specifying a placement directive")
+		}
+		return nil
+	}
 	placement, err := cmd.ZeroOrOneArgs(args)
 	if err != nil {
 		return err
@@ -147,6 +173,7 @@ func (c *AddCommand) Init(args []string) error {
 
 type AddMachineAPI interface {
 	AddMachines([]params.AddMachineParams) ([]params.AddMachinesResult, error)
+	AddIdenticalMachines(params.AddMachineParams, int) ([]params.AddMachinesResult, error)
 	AddMachines1dot18([]params.AddMachineParams) ([]params.AddMachinesResult, error)
 	Close() error
 	ForceDestroyMachines(machines ...string) error
@@ -185,6 +212,16 @@ func (c *AddCommand) getMachineManagerAPI() (MachineManagerAPI, error) {
 	return c.NewMachineManagerClient()
 }
 
+// placesContainer reports whether the command's placement
+// directive asks for a container rather than a machine.
+func (c *AddCommand) placesContainer() bool {
+	if c.Placement == nil {
+		return false
+	}
+	_, err := instance.ParseContainerType(c.Placement.Scope)
+	return err == nil
+}
+
 func (c *AddCommand) Run(ctx *cmd.Context) error {
 	client, err := c.getClientAPI()
 	if err != nil {
@@ -212,6 +249,10 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if len(c.SSHHosts) > 0 {
+		return c.provisionManualMachines(ctx, client, config)
+	}
+
 	if c.Placement != nil && c.Placement.Scope == "ssh" {
 		logger.Infof("manual provisioning")
 		args := manual.ProvisionMachineArgs{
@@ -279,7 +320,25 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 	if len(c.Disks) > 0 {
 		results, err = machineManager.AddMachines(machines)
 	} else {
-		results, err = client.AddMachines(machines)
+		if c.NumMachines > 1 && !c.placesContainer() {
+			// Add the machines in as few transactions as the API
+			// server allows, where it supports it.
+			for len(results) < c.NumMachines {
+				n := c.NumMachines - len(results)
+				if n > params.MaxIdenticalMachines {
+					n = params.MaxIdenticalMachines
+				}
+				var added []params.AddMachinesResult
+				added, err = client.AddIdenticalMachines(machineParams, n)
+				if err != nil || len(added) == 0 {
+					break
+				}
+				results = append(results, added...)
+			}
+		}
+		if results == nil && (err == nil || params.IsCodeNotImplemented(err)) {
+			results, err = client.AddMachines(machines)
+		}
 		if params.IsCodeNotImplemented(err) {
 			if c.Placement != nil {
 				containerType, parseErr := instance.ParseContainerType(c.Placement.Scope)
@@ -296,7 +355,12 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 				"AddMachinesWithPlacement not supported by the API server, " +
 					"falling back to 1.18 compatibility mode",
 			)
-			results, err = client.AddMachines1dot18([]params.AddMachineParams{machineParams})
+			// Older servers still accept several machines in a
+			// single call, so -n is honoured here too.
+			for i := range machines {
+				machines[i] = machineParams
+			}
+			results, err = client.AddMachines1dot18(machines)
 		}
 	}
 	if params.IsCodeOperationBlocked(err) {
@@ -334,3 +398,100 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 	}
 	return nil
 }
+
+// provisionManualMachines provisions each of the command's SSH hosts
+// concurrently, reporting the outcome for each host as it completes.
+// Any host that fails to provision is removed from the environment by
+// the manual provisioner.
+func (c *AddCommand) provisionManualMachines(ctx *cmd.Context, client AddMachineAPI, config *config.Config) error {
+	logger.Infof("manual provisioning of %d hosts", len(c.SSHHosts))
+	var (
+		outputMu      sync.Mutex
+		interactiveMu sync.Mutex
+		wg            sync.WaitGroup
+	)
+	errs := make([]error, len(c.SSHHosts))
+	for i, host := range c.SSHHosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			stderr := &prefixWriter{mu: &outputMu, w: ctx.Stderr, prefix: host + ": "}
+			defer stderr.Flush()
+			args := manual.ProvisionMachineArgs{
+				Host:            host,
+				Client:          client,
+				Stdin:           ctx.Stdin,
+				Stdout:          ctx.Stdout,
+				Stderr:          stderr,
+				InteractiveLock: &interactiveMu,
+				UpdateBehavior: &params.UpdateBehavior{
+					config.EnableOSRefreshUpdate(),
+					config.EnableOSUpgrade(),
+				},
+			}
+			machineId, err := manualProvisioner(args)
+			if err != nil {
+				errs[i] = err
+				fmt.Fprintf(stderr, "failed to provision machine: %v\n", err)
+				return
+			}
+			fmt.Fprintf(stderr, "created machine %v\n", machineId)
+		}(i, host)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to provision %d of %d machines", failed, len(c.SSHHosts))
+	}
+	return nil
+}
+
+// prefixWriter writes each complete line written to it to an
+// underlying writer shared with other prefixWriters, preceded by a
+// prefix, so that the output for several concurrently provisioned
+// hosts can be told apart.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+// Write is part of the io.Writer interface.
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf = append(p.buf, data...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(data), nil
+}
+
+// Flush writes any incomplete line remaining in the buffer.
+func (p *prefixWriter) Flush() error {
+	if len(p.buf) == 0 {
+		return nil
+	}
+	line := append(p.buf, '\n')
+	p.buf = nil
+	return p.writeLine(line)
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := fmt.Fprintf(p.w, "%s%s", p.prefix, line)
+	return err
+}
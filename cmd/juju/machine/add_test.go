@@ -4,8 +4,10 @@
 package machine_test
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -43,6 +45,7 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 		series      string
 		constraints string
 		placement   string
+		sshHosts    []string
 		count       int
 		errorString string
 	}{
@@ -78,6 +81,16 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"ssh:user@10.10.0.3"},
 			count:     1,
 			placement: "ssh:user@10.10.0.3",
+		}, {
+			args:     []string{"ssh:user@10.10.0.3", "ssh:10.10.0.4"},
+			count:    1,
+			sshHosts: []string{"user@10.10.0.3", "10.10.0.4"},
+		}, {
+			args:        []string{"ssh:10.10.0.3", "lxc:4"},
+			errorString: `cannot mix ssh: targets with other placement "lxc:4"`,
+		}, {
+			args:        []string{"ssh:10.10.0.3", "ssh:10.10.0.4", "-n", "2"},
+			errorString: "cannot use -n when specifying a placement directive",
 		}, {
 			args:      []string{"zone=us-east-1a"},
 			count:     1,
@@ -108,6 +121,7 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 				c.Check("", gc.Equals, test.placement)
 			}
 			c.Check(addCmd.NumMachines, gc.Equals, test.count)
+			c.Check(addCmd.SSHHosts, jc.DeepEquals, test.sshHosts)
 		} else {
 			c.Check(err, gc.ErrorMatches, test.errorString)
 		}
@@ -150,6 +164,37 @@ func (s *AddMachineSuite) TestSSHPlacementError(c *gc.C) {
 	c.Assert(testing.Stderr(context), gc.Equals, "")
 }
 
+func (s *AddMachineSuite) TestSSHPlacementMultipleHosts(c *gc.C) {
+	var mu sync.Mutex
+	var hosts []string
+	s.PatchValue(machine.ManualProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		c.Check(args.InteractiveLock, gc.NotNil)
+		fmt.Fprintf(args.Stderr, "installing agent\n")
+		mu.Lock()
+		hosts = append(hosts, args.Host)
+		mu.Unlock()
+		switch args.Host {
+		case "10.1.2.3":
+			return "42", nil
+		case "user@10.1.2.4":
+			return "43", nil
+		}
+		return "", errors.New("failed to initialize warp core")
+	})
+	context, err := s.run(c, "ssh:10.1.2.3", "ssh:user@10.1.2.4", "ssh:10.1.2.5")
+	c.Assert(err, gc.ErrorMatches, "failed to provision 1 of 3 machines")
+	c.Assert(hosts, jc.SameContents, []string{"10.1.2.3", "user@10.1.2.4", "10.1.2.5"})
+	lines := strings.Split(strings.TrimSuffix(testing.Stderr(context), "\n"), "\n")
+	c.Assert(lines, jc.SameContents, []string{
+		"10.1.2.3: installing agent",
+		"10.1.2.3: created machine 42",
+		"user@10.1.2.4: installing agent",
+		"user@10.1.2.4: created machine 43",
+		"10.1.2.5: installing agent",
+		"10.1.2.5: failed to provision machine: failed to initialize warp core",
+	})
+}
+
 func (s *AddMachineSuite) TestParamsPassedOn(c *gc.C) {
 	_, err := s.run(c, "--constraints", "mem=8G", "--series=special", "zone=nz")
 	c.Assert(err, jc.ErrorIsNil)
@@ -169,6 +214,49 @@ func (s *AddMachineSuite) TestParamsPassedOnNTimes(c *gc.C) {
 	c.Assert(param.Constraints.String(), gc.Equals, "mem=8192M")
 	c.Assert(s.fakeAddMachine.args[0], jc.DeepEquals, s.fakeAddMachine.args[1])
 	c.Assert(s.fakeAddMachine.args[0], jc.DeepEquals, s.fakeAddMachine.args[2])
+	c.Assert(s.fakeAddMachine.identicalArgs, gc.HasLen, 3)
+}
+
+func (s *AddMachineSuite) TestManyIdenticalMachinesAddedInBatches(c *gc.C) {
+	n := params.MaxIdenticalMachines + 50
+	_, err := s.run(c, "-n", strconv.Itoa(n))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddMachine.identicalCalls, jc.DeepEquals, []int{params.MaxIdenticalMachines, 50})
+	c.Assert(s.fakeAddMachine.identicalArgs, gc.HasLen, n)
+}
+
+func (s *AddMachineSuite) TestParamsPassedOnNTimesNoIdenticalMachines(c *gc.C) {
+	s.fakeAddMachine.noIdentical = true
+	_, err := s.run(c, "-n", "3", "--series=special")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddMachine.identicalArgs, gc.HasLen, 0)
+	c.Assert(s.fakeAddMachine.args, gc.HasLen, 3)
+	for _, param := range s.fakeAddMachine.args {
+		c.Check(param.Series, gc.Equals, "special")
+	}
+}
+
+func (s *AddMachineSuite) TestContainersNotAddedIdentically(c *gc.C) {
+	_, err := s.run(c, "-n", "2", "lxc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddMachine.identicalArgs, gc.HasLen, 0)
+	c.Assert(s.fakeAddMachine.args, gc.HasLen, 2)
+}
+
+func (s *AddMachineSuite) TestParamsPassedOnNTimesOldServer(c *gc.C) {
+	s.fakeAddMachine.placementError = true
+	context, err := s.run(c, "-n", "3", "lxc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddMachine.args1dot18, gc.HasLen, 3)
+	for _, param := range s.fakeAddMachine.args1dot18 {
+		c.Check(param.Placement, gc.IsNil)
+		c.Check(string(param.ContainerType), gc.Equals, "lxc")
+		c.Check(param.ParentId, gc.Equals, "")
+	}
+	c.Assert(testing.Stderr(context), gc.Equals, `created machine 0
+created machine 1
+created machine 2
+`)
 }
 
 func (s *AddMachineSuite) TestAddThreeMachinesWithTwoFailures(c *gc.C) {
@@ -221,11 +309,16 @@ func (s *AddMachineSuite) TestAddMachineWithDisksUnsupported(c *gc.C) {
 }
 
 type fakeAddMachineAPI struct {
-	successOrder []bool
-	currentOp    int
-	args         []params.AddMachineParams
-	addError     error
-	agentVersion interface{}
+	successOrder   []bool
+	currentOp      int
+	args           []params.AddMachineParams
+	args1dot18     []params.AddMachineParams
+	identicalArgs  []params.AddMachineParams
+	identicalCalls []int
+	addError       error
+	placementError bool
+	noIdentical    bool
+	agentVersion   interface{}
 }
 
 func (f *fakeAddMachineAPI) Close() error {
@@ -237,6 +330,26 @@ func (f *fakeAddMachineAPI) EnvironmentUUID() string {
 }
 
 func (f *fakeAddMachineAPI) AddMachines(args []params.AddMachineParams) ([]params.AddMachinesResult, error) {
+	if f.placementError {
+		return nil, &params.Error{Message: "AddMachinesV2 not implemented", Code: params.CodeNotImplemented}
+	}
+	return f.addMachines(args)
+}
+
+func (f *fakeAddMachineAPI) AddIdenticalMachines(args params.AddMachineParams, n int) ([]params.AddMachinesResult, error) {
+	if f.placementError || f.noIdentical {
+		return nil, &params.Error{Message: "AddIdenticalMachines not implemented", Code: params.CodeNotImplemented}
+	}
+	f.identicalCalls = append(f.identicalCalls, n)
+	machines := make([]params.AddMachineParams, n)
+	for i := range machines {
+		machines[i] = args
+	}
+	f.identicalArgs = append(f.identicalArgs, machines...)
+	return f.addMachines(machines)
+}
+
+func (f *fakeAddMachineAPI) addMachines(args []params.AddMachineParams) ([]params.AddMachinesResult, error) {
 	if f.addError != nil {
 		return nil, f.addError
 	}
@@ -260,7 +373,8 @@ func (f *fakeAddMachineAPI) AddMachines(args []params.AddMachineParams) ([]param
 }
 
 func (f *fakeAddMachineAPI) AddMachines1dot18(args []params.AddMachineParams) ([]params.AddMachinesResult, error) {
-	return f.AddMachines(args)
+	f.args1dot18 = append(f.args1dot18, args...)
+	return f.addMachines(args)
 }
 
 func (f *fakeAddMachineAPI) ForceDestroyMachines(machines ...string) error {
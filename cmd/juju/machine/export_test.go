@@ -7,6 +7,7 @@ import "github.com/juju/juju/storage"
 
 var (
 	ManualProvisioner = &manualProvisioner
+	CheckInstance     = &checkInstance
 )
 
 // NewAddCommand returns an AddCommand with the api provided as specified.
@@ -24,6 +25,20 @@ func NewRemoveCommand(api RemoveMachineAPI) *RemoveCommand {
 	}
 }
 
+// NewSetLabelsCommand returns a SetLabelsCommand with the api provided as specified.
+func NewSetLabelsCommand(api SetMachineLabelsAPI) *SetLabelsCommand {
+	return &SetLabelsCommand{
+		api: api,
+	}
+}
+
+// NewImportCommand returns an ImportCommand with the api provided as specified.
+func NewImportCommand(api AddMachineAPI) *ImportCommand {
+	return &ImportCommand{
+		api: api,
+	}
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
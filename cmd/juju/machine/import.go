@@ -0,0 +1,134 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/instance"
+)
+
+const importMachineDoc = `
+Import an existing, already running provider instance into the environment.
+
+The instance is identified by its provider-specific instance id, which is
+checked with the provider, and must be reachable via SSH at the given address. The machine agent is installed over
+SSH, and the machine is recorded in state with the instance's real hardware
+characteristics. Once imported, the machine is managed by Juju exactly like
+any other machine: it may host units, and removing it will stop the
+underlying instance.
+
+This is useful for bringing hand-built instances under Juju's control
+without having to rebuild them.
+
+Examples:
+   juju machine import i-0123abcd ubuntu@10.0.0.7
+   juju machine import i-0123abcd 10.0.0.7
+`
+
+// ImportCommand adopts an existing provider instance as a machine in
+// the environment.
+type ImportCommand struct {
+	envcmd.EnvCommandBase
+	api        AddMachineAPI
+	InstanceId instance.Id
+	Host       string
+}
+
+func (c *ImportCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "import",
+		Args:    "<instance-id> [user@]host",
+		Purpose: "import an existing provider instance as a machine",
+		Doc:     importMachineDoc,
+	}
+}
+
+func (c *ImportCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return fmt.Errorf("no instance id specified")
+	case 1:
+		return fmt.Errorf("no host specified")
+	}
+	if args[0] == "" {
+		return fmt.Errorf("invalid instance id %q", args[0])
+	}
+	c.InstanceId = instance.Id(args[0])
+	c.Host = args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+func (c *ImportCommand) getClientAPI() (AddMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// checkInstance returns an error if the environment's provider does
+// not know of an instance with the given id.
+var checkInstance = func(cfg *config.Config, id instance.Id) error {
+	env, err := environs.New(cfg)
+	if err != nil {
+		return errors.Annotate(err, "cannot open environment")
+	}
+	_, err = env.Instances([]instance.Id{id})
+	if err == environs.ErrNoInstances {
+		return errors.NotFoundf("instance %v", id)
+	}
+	return errors.Trace(err)
+}
+
+func (c *ImportCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getClientAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	var config *config.Config
+	if defaultStore, err := configstore.Default(); err != nil {
+		return err
+	} else if config, err = c.Config(defaultStore); err != nil {
+		return err
+	}
+
+	if err := checkInstance(config, c.InstanceId); err != nil {
+		return errors.Annotatef(err, "cannot import instance %v", c.InstanceId)
+	}
+
+	logger.Infof("importing instance %v", c.InstanceId)
+	args := manual.ProvisionMachineArgs{
+		Host:       c.Host,
+		InstanceId: c.InstanceId,
+		Client:     client,
+		Stdin:      ctx.Stdin,
+		Stdout:     ctx.Stdout,
+		Stderr:     ctx.Stderr,
+		UpdateBehavior: &params.UpdateBehavior{
+			config.EnableOSRefreshUpdate(),
+			config.EnableOSUpgrade(),
+		},
+	}
+	machineId, err := manualProvisioner(args)
+	if params.IsCodeOperationBlocked(err) {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot import instance %v", c.InstanceId)
+	}
+	ctx.Infof("imported instance %v as machine %v", c.InstanceId, machineId)
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+type ImportMachineSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeAddMachineAPI
+}
+
+var _ = gc.Suite(&ImportMachineSuite{})
+
+func (s *ImportMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAddMachineAPI{}
+	s.PatchValue(machine.CheckInstance, func(*config.Config, instance.Id) error {
+		return nil
+	})
+}
+
+func (s *ImportMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	importCmd := machine.NewImportCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(importCmd), args...)
+}
+
+func (s *ImportMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		instanceId  instance.Id
+		host        string
+		errorString string
+	}{
+		{
+			errorString: "no instance id specified",
+		}, {
+			args:        []string{"i-123"},
+			errorString: "no host specified",
+		}, {
+			args:       []string{"i-123", "ubuntu@10.0.0.7"},
+			instanceId: "i-123",
+			host:       "ubuntu@10.0.0.7",
+		}, {
+			args:        []string{"i-123", "10.0.0.7", "extra"},
+			errorString: `unrecognized args: \["extra"\]`,
+		},
+	} {
+		c.Logf("test %d", i)
+		importCmd := &machine.ImportCommand{}
+		err := testing.InitCommand(importCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(importCmd.InstanceId, gc.Equals, test.instanceId)
+			c.Check(importCmd.Host, gc.Equals, test.host)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *ImportMachineSuite) TestImport(c *gc.C) {
+	var called manual.ProvisionMachineArgs
+	s.PatchValue(machine.ManualProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		called = args
+		return "42", nil
+	})
+	context, err := s.run(c, "i-123", "ubuntu@10.0.0.7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(context), gc.Equals, "imported instance i-123 as machine 42\n")
+	c.Assert(called.InstanceId, gc.Equals, instance.Id("i-123"))
+	c.Assert(called.Host, gc.Equals, "ubuntu@10.0.0.7")
+}
+
+func (s *ImportMachineSuite) TestImportUnknownInstance(c *gc.C) {
+	s.PatchValue(machine.CheckInstance, func(_ *config.Config, id instance.Id) error {
+		c.Check(id, gc.Equals, instance.Id("i-123"))
+		return errors.NotFoundf("instance %v", id)
+	})
+	s.PatchValue(machine.ManualProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		c.Fatalf("instance provisioned")
+		return "", nil
+	})
+	_, err := s.run(c, "i-123", "10.0.0.7")
+	c.Assert(err, gc.ErrorMatches, "cannot import instance i-123: instance i-123 not found")
+}
+
+func (s *ImportMachineSuite) TestImportError(c *gc.C) {
+	s.PatchValue(machine.ManualProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		return "", errors.New("no route to host")
+	})
+	_, err := s.run(c, "i-123", "10.0.0.7")
+	c.Assert(err, gc.ErrorMatches, "cannot import instance i-123: no route to host")
+}
@@ -13,7 +13,8 @@ import (
 var logger = loggo.GetLogger("juju.cmd.juju.machine")
 
 const machineCommandDoc = `
-"juju machine" provides commands to add and remove machines in the Juju environment.
+"juju machine" provides commands to add, import, label and remove machines in
+the Juju environment.
 `
 
 const machineCommandPurpose = "manage machines"
@@ -29,5 +30,7 @@ func NewSuperCommand() cmd.Command {
 	})
 	machineCmd.Register(envcmd.Wrap(&AddCommand{}))
 	machineCmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	machineCmd.Register(envcmd.Wrap(&ImportCommand{}))
+	machineCmd.Register(envcmd.Wrap(&SetLabelsCommand{}))
 	return machineCmd
 }
@@ -22,7 +22,9 @@ var _ = gc.Suite(&MachineCommandSuite{})
 var expectedCommmandNames = []string{
 	"add",
 	"help",
+	"import",
 	"remove",
+	"set-labels",
 }
 
 func (s *MachineCommandSuite) TestHelp(c *gc.C) {
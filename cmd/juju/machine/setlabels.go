@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// SetLabelsCommand replaces the user-defined labels of a machine.
+type SetLabelsCommand struct {
+	envcmd.EnvCommandBase
+	api       SetMachineLabelsAPI
+	MachineId string
+	Labels    []string
+}
+
+const setLabelsDoc = `
+Machine labels are free-form names, made of lowercase letters, digits and
+dashes, that may be used to choose machines for units with a "label="
+placement directive. The given labels replace any existing labels of the
+machine; specifying none removes them all.
+
+Examples:
+	# Label machine 3 as having a GPU and fast disks
+	$ juju machine set-labels 3 gpu ssd
+
+	# Deploy two units to machines labelled "gpu"
+	$ juju service add-unit cuda-worker -n 2 --to label=gpu
+
+	# Add an lxc container to a machine labelled "gpu"
+	$ juju machine add lxc:label=gpu
+
+	# Remove all labels from machine 3
+	$ juju machine set-labels 3
+`
+
+func (c *SetLabelsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-labels",
+		Args:    "<machine> [<label> ...]",
+		Purpose: "set the labels of a machine",
+		Doc:     setLabelsDoc,
+	}
+}
+
+func (c *SetLabelsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no machine specified")
+	}
+	if !names.IsValidMachine(args[0]) {
+		return fmt.Errorf("invalid machine id %q", args[0])
+	}
+	c.MachineId, c.Labels = args[0], args[1:]
+	return nil
+}
+
+type SetMachineLabelsAPI interface {
+	SetMachineLabels(machine names.MachineTag, labels []string) error
+	Close() error
+}
+
+func (c *SetLabelsCommand) getSetMachineLabelsAPI() (SetMachineLabelsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *SetLabelsCommand) Run(_ *cmd.Context) error {
+	client, err := c.getSetMachineLabelsAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.SetMachineLabels(names.NewMachineTag(c.MachineId), c.Labels)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type SetLabelsSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeSetMachineLabelsAPI
+}
+
+var _ = gc.Suite(&SetLabelsSuite{})
+
+func (s *SetLabelsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeSetMachineLabelsAPI{}
+}
+
+func (s *SetLabelsSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	setLabels := machine.NewSetLabelsCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(setLabels), args...)
+}
+
+func (s *SetLabelsSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machine     string
+		labels      []string
+		errorString string
+	}{
+		{
+			errorString: "no machine specified",
+		}, {
+			args:    []string{"1"},
+			machine: "1",
+			labels:  []string{},
+		}, {
+			args:    []string{"1/lxc/2", "gpu", "ssd"},
+			machine: "1/lxc/2",
+			labels:  []string{"gpu", "ssd"},
+		}, {
+			args:        []string{"gpu"},
+			errorString: `invalid machine id "gpu"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		setLabelsCmd := &machine.SetLabelsCommand{}
+		err := testing.InitCommand(setLabelsCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(setLabelsCmd.MachineId, gc.Equals, test.machine)
+			c.Check(setLabelsCmd.Labels, jc.DeepEquals, test.labels)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *SetLabelsSuite) TestSetLabels(c *gc.C) {
+	_, err := s.run(c, "3", "gpu", "ssd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.machine, gc.Equals, names.NewMachineTag("3"))
+	c.Assert(s.fake.labels, jc.DeepEquals, []string{"gpu", "ssd"})
+}
+
+func (s *SetLabelsSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockedError")
+	_, err := s.run(c, "3", "gpu")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestBlockedError.*")
+}
+
+type fakeSetMachineLabelsAPI struct {
+	machine names.MachineTag
+	labels  []string
+	err     error
+}
+
+func (f *fakeSetMachineLabelsAPI) Close() error {
+	return nil
+}
+
+func (f *fakeSetMachineLabelsAPI) SetMachineLabels(machine names.MachineTag, labels []string) error {
+	f.machine = machine
+	f.labels = labels
+	return f.err
+}
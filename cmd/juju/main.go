@@ -131,6 +131,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
 	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
+	r.Register(wrapEnvCommand(&ShowUnitCommand{}))
+	r.Register(wrapEnvCommand(&ListMachinesCommand{}))
+	r.Register(wrapEnvCommand(&ListUnitsCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
@@ -139,6 +142,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&ResolvedCommand{}))
 	r.Register(wrapEnvCommand(&DebugLogCommand{}))
 	r.Register(wrapEnvCommand(&DebugHooksCommand{}))
+	r.Register(wrapEnvCommand(&DiagnoseCommand{}))
 
 	// Configuration commands.
 	r.Register(&InitCommand{})
@@ -206,6 +210,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage storage
 	r.Register(storage.NewSuperCommand())
+	r.RegisterSuperAlias("add-storage", "storage", "add", nil)
 }
 
 // envCmdWrapper is a struct that wraps an environment command and lets us handle
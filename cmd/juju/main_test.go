@@ -191,6 +191,7 @@ var commandNames = []string{
 	"action",
 	"add-machine",
 	"add-relation",
+	"add-storage", // alias for storage add
 	"add-unit",
 	"api-endpoints",
 	"api-info",
@@ -208,6 +209,7 @@ var commandNames = []string{
 	"destroy-relation",
 	"destroy-service",
 	"destroy-unit",
+	"diagnose",
 	"ensure-availability",
 	"env", // alias for switch
 	"environment",
@@ -220,6 +222,8 @@ var commandNames = []string{
 	"help",
 	"help-tool",
 	"init",
+	"list-machines",
+	"list-units",
 	"machine",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
@@ -235,6 +239,7 @@ var commandNames = []string{
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"show-unit",
 	"ssh",
 	"stat", // alias for status
 	"status",
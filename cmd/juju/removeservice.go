@@ -5,10 +5,17 @@ package main
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -17,19 +24,46 @@ import (
 type RemoveServiceCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	Wait        bool
+	ForceAfter  time.Duration
 }
 
+const removeServiceDoc = `
+Removing a service will remove all its units and relations.
+
+By default the command returns as soon as the service has been marked
+for removal. With --wait, it reports the units, relations and machines
+that remain to be dealt with until the service has been removed.
+
+With --force-after, it also waits, but once the given duration has
+passed, any units that remain are removed without waiting for their
+agents, and any relations that remain are removed without running
+their relation-broken hooks. Machines hosting the service's units are
+reported as pending stop, but are never removed.
+
+Example:
+    juju remove-service --force-after 10m wordpress
+`
+
 func (c *RemoveServiceCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "remove-service",
 		Args:    "<service>",
 		Purpose: "remove a service from the environment",
-		Doc:     "Removing a service will remove all its units and relations.",
+		Doc:     removeServiceDoc,
 		Aliases: []string{"destroy-service"},
 	}
 }
 
+func (c *RemoveServiceCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Wait, "wait", false, "wait until the service has been removed, reporting progress")
+	f.DurationVar(&c.ForceAfter, "force-after", 0, "wait, and force the removal of what remains after this long")
+}
+
 func (c *RemoveServiceCommand) Init(args []string) error {
+	if c.ForceAfter < 0 {
+		return fmt.Errorf("invalid --force-after duration %v", c.ForceAfter)
+	}
 	if len(args) == 0 {
 		return fmt.Errorf("no service specified")
 	}
@@ -40,11 +74,99 @@ func (c *RemoveServiceCommand) Init(args []string) error {
 	return cmd.CheckEmpty(args)
 }
 
-func (c *RemoveServiceCommand) Run(_ *cmd.Context) error {
+func (c *RemoveServiceCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.ServiceDestroy(c.ServiceName), block.BlockRemove)
+	if err := client.ServiceDestroy(c.ServiceName); err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	if !c.Wait && c.ForceAfter == 0 {
+		return nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	serviceClient := apiservice.NewClient(root)
+	defer serviceClient.Close()
+	return c.waitForRemoval(ctx, serviceClient)
+}
+
+// serviceRemovalAPI holds the calls used to follow the removal of
+// a service.
+type serviceRemovalAPI interface {
+	WatchServiceRemoval(serviceName string) (watcher.NotifyWatcher, error)
+	ServiceRemovalProgress(serviceName string) (*params.ServiceRemovalProgress, error)
+	ForceDestroyService(serviceName string) error
+}
+
+// waitForRemoval reports the progress of the removal of the service
+// until it is complete, forcing the removal once c.ForceAfter has
+// passed, if set.
+func (c *RemoveServiceCommand) waitForRemoval(ctx *cmd.Context, client serviceRemovalAPI) error {
+	w, err := client.WatchServiceRemoval(c.ServiceName)
+	if errors.IsNotImplemented(err) {
+		return errors.New("cannot use --wait or --force-after: not supported by the API server")
+	} else if params.IsCodeNotFound(err) {
+		// A service with no units or relations is removed as
+		// soon as it is destroyed.
+		ctx.Infof("service %q removed", c.ServiceName)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.Stop()
+
+	var deadline <-chan time.Time
+	if c.ForceAfter > 0 {
+		deadline = time.After(c.ForceAfter)
+	}
+	for {
+		select {
+		case _, ok := <-w.Changes():
+			if !ok {
+				return errors.Annotate(w.Err(), "service removal watcher stopped")
+			}
+			progress, err := client.ServiceRemovalProgress(c.ServiceName)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if progress.Removed {
+				ctx.Infof("service %q removed", c.ServiceName)
+				return nil
+			}
+			ctx.Infof("waiting for service %q: %s", c.ServiceName, formatRemovalProgress(progress))
+		case <-deadline:
+			ctx.Infof("forcing removal of service %q", c.ServiceName)
+			err := client.ForceDestroyService(c.ServiceName)
+			return block.ProcessBlockedError(err, block.BlockRemove)
+		}
+	}
+}
+
+// formatRemovalProgress describes what remains of a service that is
+// being removed.
+func formatRemovalProgress(progress *params.ServiceRemovalProgress) string {
+	var parts []string
+	if len(progress.Units) > 0 {
+		parts = append(parts, "units "+strings.Join(progress.Units, ", "))
+	}
+	if len(progress.Relations) > 0 {
+		parts = append(parts, fmt.Sprintf("relations %q", progress.Relations))
+	}
+	var remaining string
+	if len(parts) == 0 {
+		remaining = "nothing remaining"
+	} else {
+		remaining = strings.Join(parts, "; ") + " remaining"
+	}
+	// Machines are not removed along with the service, so they are
+	// not waited on, but are worth knowing about.
+	if len(progress.Machines) > 0 {
+		remaining += "; machines " + strings.Join(progress.Machines, ", ") + " pending stop"
+	}
+	return remaining
 }
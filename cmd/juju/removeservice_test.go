@@ -4,9 +4,13 @@
 package main
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
@@ -74,4 +78,83 @@ func (s *RemoveServiceSuite) TestInvalidArgs(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["pong"\]`)
 	err = runRemoveService(c, "invalid:name")
 	c.Assert(err, gc.ErrorMatches, `invalid service name "invalid:name"`)
+	err = runRemoveService(c, "--force-after", "-1s", "riak")
+	c.Assert(err, gc.ErrorMatches, `invalid --force-after duration -1s`)
+}
+
+func (s *RemoveServiceSuite) TestForceAfter(c *gc.C) {
+	s.setupTestService(c)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&RemoveServiceCommand{}), "--force-after", "1ms", "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), jc.Contains, `forcing removal of service "riak"`)
+	_, err = s.State.Service("riak")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoveServiceSuite) TestWait(c *gc.C) {
+	s.setupTestService(c)
+	units, err := s.State.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+	unit := units[0]
+
+	done := make(chan error)
+	go func() {
+		err := runRemoveService(c, "--wait", "riak")
+		done <- err
+	}()
+	// Stand in for the unit agent once the service is dying.
+	riak, err := s.State.Service("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		err := riak.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if riak.Life() == state.Dying {
+			break
+		}
+	}
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for remove-service")
+	}
+	_, err = s.State.Service("riak")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoveServiceSuite) TestWaitServiceAlreadyRemoved(c *gc.C) {
+	// A service without units is removed as soon as it is destroyed.
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&RemoveServiceCommand{}), "--wait", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), jc.Contains, `service "mysql" removed`)
+}
+
+func (s *RemoveServiceSuite) TestFormatRemovalProgress(c *gc.C) {
+	for i, test := range []struct {
+		progress params.ServiceRemovalProgress
+		expected string
+	}{{
+		expected: "nothing remaining",
+	}, {
+		progress: params.ServiceRemovalProgress{
+			Units:     []string{"riak/0", "riak/1"},
+			Relations: []string{"riak:ring"},
+			Machines:  []string{"0", "1"},
+		},
+		expected: `units riak/0, riak/1; relations ["riak:ring"] remaining; machines 0, 1 pending stop`,
+	}, {
+		progress: params.ServiceRemovalProgress{
+			Machines: []string{"0"},
+		},
+		expected: "nothing remaining; machines 0 pending stop",
+	}} {
+		c.Logf("test %d", i)
+		c.Check(formatRemovalProgress(&test.progress), gc.Equals, test.expected)
+	}
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider"
 )
 
@@ -34,7 +35,11 @@ func (c *UnitCommandBase) Init(args []string) error {
 	if c.NumUnits < 1 {
 		return errors.New("--num-units must be a positive integer")
 	}
-	if c.ToMachineSpec != "" {
+	if label, ok := instance.ParseLabelDirective(c.ToMachineSpec); ok {
+		if label == "" {
+			return fmt.Errorf("invalid --to parameter %q", c.ToMachineSpec)
+		}
+	} else if c.ToMachineSpec != "" {
 		if c.NumUnits > 1 {
 			return errors.New("cannot use --num-units > 1 with --to")
 		}
@@ -92,6 +97,8 @@ Examples:
  juju service add-unit mysql --to 23       (Add a mysql unit to machine 23)
  juju service add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
  juju service add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju service add-unit mysql -n 2 --to label=db
+                                           (Add 2 units to machines labelled "db")
 `
 
 func (c *AddUnitCommand) Info() *cmd.Info {
@@ -85,6 +85,9 @@ var initAddUnitErrorTests = []struct {
 	}, {
 		args: []string{"some-service-name", "-n", "2", "--to", "123"},
 		err:  `cannot use --num-units > 1 with --to`,
+	}, {
+		args: []string{"some-service-name", "--to", "label="},
+		err:  `invalid --to parameter "label="`,
 	},
 }
 
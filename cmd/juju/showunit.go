@@ -0,0 +1,234 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/unitdetails"
+	"github.com/juju/juju/api/unitevents"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const showUnitDoc = `
+Show everything known about a unit, for debugging: its assigned machine
+and addresses, open ports, whether it is the leader of its service, the
+relations it is in scope for, and the recent history of its workload
+and agent status.
+
+The relation settings are not shown; instead, a digest of the unit's
+settings in each relation makes it easy to spot when they change.
+
+The recent events in the life of the unit's workload are also shown.
+Each hook run by the unit agent is recorded as it starts, and again
+when it completes or fails, along with how long it ran for. The most
+recent events and statuses are shown first.
+
+Examples:
+    juju show-unit mysql/0
+    juju show-unit -n 50 --history 10 --format json mysql/0
+`
+
+// ShowUnitCommand shows the event log of a unit.
+type ShowUnitCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	size        int
+	historySize int
+	unitName    string
+	api         ShowUnitAPI
+}
+
+// ShowUnitAPI defines the API methods that the show-unit command uses.
+type ShowUnitAPI interface {
+	Close() error
+	Events(unit names.UnitTag, size int) ([]params.UnitEvent, error)
+	Details(unit names.UnitTag, historySize int) (*params.UnitDetails, error)
+}
+
+// showUnitAPI combines the clients of the facades used by the
+// show-unit command.
+type showUnitAPI struct {
+	*unitevents.Client
+	details *unitdetails.Client
+}
+
+func (api showUnitAPI) Details(unit names.UnitTag, historySize int) (*params.UnitDetails, error) {
+	return api.details.Details(unit, historySize)
+}
+
+// UnitEventInfo defines the serialization behaviour of a unit event.
+type UnitEventInfo struct {
+	Kind     string `yaml:"kind" json:"kind"`
+	Hook     string `yaml:"hook" json:"hook"`
+	Time     string `yaml:"time" json:"time"`
+	Duration string `yaml:"duration,omitempty" json:"duration,omitempty"`
+	Message  string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// UnitStatusInfo defines the serialization behaviour of a current or
+// past status of a unit's workload or agent.
+type UnitStatusInfo struct {
+	Status  string `yaml:"status" json:"status"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+	Since   string `yaml:"since,omitempty" json:"since,omitempty"`
+}
+
+// UnitRelationInfo defines the serialization behaviour of a relation
+// in which a unit is in scope.
+type UnitRelationInfo struct {
+	Relation       string `yaml:"relation" json:"relation"`
+	Endpoint       string `yaml:"endpoint" json:"endpoint"`
+	Interface      string `yaml:"interface" json:"interface"`
+	SettingsDigest string `yaml:"settings-digest" json:"settings-digest"`
+}
+
+// UnitDetails defines the serialization behaviour of the details
+// shown for a unit.
+type UnitDetails struct {
+	Service               string             `yaml:"service,omitempty" json:"service,omitempty"`
+	Machine               string             `yaml:"machine,omitempty" json:"machine,omitempty"`
+	PublicAddress         string             `yaml:"public-address,omitempty" json:"public-address,omitempty"`
+	PrivateAddress        string             `yaml:"private-address,omitempty" json:"private-address,omitempty"`
+	Leader                bool               `yaml:"leader,omitempty" json:"leader,omitempty"`
+	OpenPorts             []string           `yaml:"open-ports,omitempty" json:"open-ports,omitempty"`
+	Relations             []UnitRelationInfo `yaml:"relations,omitempty" json:"relations,omitempty"`
+	WorkloadStatusHistory []UnitStatusInfo   `yaml:"workload-status-history,omitempty" json:"workload-status-history,omitempty"`
+	AgentStatusHistory    []UnitStatusInfo   `yaml:"agent-status-history,omitempty" json:"agent-status-history,omitempty"`
+	Events                []UnitEventInfo    `yaml:"events" json:"events"`
+}
+
+func (c *ShowUnitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-unit",
+		Args:    "[-n N] <unit>",
+		Purpose: "show everything known about a unit",
+		Doc:     showUnitDoc,
+	}
+}
+
+func (c *ShowUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.IntVar(&c.size, "n", 20, "number of events to show")
+	f.IntVar(&c.historySize, "history", 5, "number of entries of each status history to show")
+}
+
+func (c *ShowUnitCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no unit specified")
+	case 1:
+		c.unitName = args[0]
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+	if !names.IsValidUnit(c.unitName) {
+		return errors.Errorf("invalid unit name %q", c.unitName)
+	}
+	if c.size <= 0 {
+		return errors.Errorf("invalid number of events: %d", c.size)
+	}
+	if c.historySize <= 0 {
+		return errors.Errorf("invalid status history size: %d", c.historySize)
+	}
+	return nil
+}
+
+func (c *ShowUnitCommand) getAPI() (ShowUnitAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return showUnitAPI{
+		Client:  unitevents.NewClient(root),
+		details: unitdetails.NewClient(root),
+	}, nil
+}
+
+func (c *ShowUnitCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	unitTag := names.NewUnitTag(c.unitName)
+	var details UnitDetails
+	unitDetails, err := api.Details(unitTag, c.historySize)
+	if params.IsCodeNotImplemented(err) {
+		// Older servers only keep the event log.
+		logger.Debugf("unit details not supported by the API server")
+	} else if err != nil {
+		return errors.Trace(err)
+	} else {
+		details = formatUnitDetails(unitDetails)
+	}
+
+	events, err := api.Events(unitTag, c.size)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	details.Events = make([]UnitEventInfo, len(events))
+	for i, event := range events {
+		info := UnitEventInfo{
+			Kind:    string(event.Kind),
+			Hook:    event.Hook,
+			Time:    event.Time.UTC().Format(time.RFC3339),
+			Message: event.Message,
+		}
+		if event.Kind != params.UnitEventHookStarted {
+			info.Duration = event.Duration.String()
+		}
+		details.Events[i] = info
+	}
+	return c.out.Write(ctx, map[string]UnitDetails{c.unitName: details})
+}
+
+func formatUnitDetails(in *params.UnitDetails) UnitDetails {
+	out := UnitDetails{
+		Service:               in.Service,
+		Machine:               in.Machine,
+		PublicAddress:         in.PublicAddress,
+		PrivateAddress:        in.PrivateAddress,
+		Leader:                in.Leader,
+		WorkloadStatusHistory: formatUnitStatuses(in.WorkloadStatusHistory),
+		AgentStatusHistory:    formatUnitStatuses(in.AgentStatusHistory),
+	}
+	for _, port := range in.OpenedPorts {
+		out.OpenPorts = append(out.OpenPorts, port.NetworkPortRange().String())
+	}
+	for _, rel := range in.Relations {
+		out.Relations = append(out.Relations, UnitRelationInfo{
+			Relation:       rel.Key,
+			Endpoint:       rel.Endpoint,
+			Interface:      rel.Interface,
+			SettingsDigest: rel.SettingsDigest,
+		})
+	}
+	return out
+}
+
+func formatUnitStatuses(in []params.UnitStatusEntry) []UnitStatusInfo {
+	var out []UnitStatusInfo
+	for _, status := range in {
+		info := UnitStatusInfo{
+			Status:  string(status.Status),
+			Message: status.Info,
+		}
+		if status.Since != nil {
+			info.Since = status.Since.UTC().Format(time.RFC3339)
+		}
+		out = append(out, info)
+	}
+	return out
+}
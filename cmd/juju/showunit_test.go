@@ -0,0 +1,189 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ShowUnitSuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeShowUnitAPI
+}
+
+var _ = gc.Suite(&ShowUnitSuite{})
+
+type fakeShowUnitAPI struct {
+	unit        names.UnitTag
+	size        int
+	historySize int
+	events      []params.UnitEvent
+	details     *params.UnitDetails
+	err         error
+	detailsErr  error
+}
+
+func (f *fakeShowUnitAPI) Close() error {
+	return nil
+}
+
+func (f *fakeShowUnitAPI) Events(unit names.UnitTag, size int) ([]params.UnitEvent, error) {
+	f.unit = unit
+	f.size = size
+	return f.events, f.err
+}
+
+func (f *fakeShowUnitAPI) Details(unit names.UnitTag, historySize int) (*params.UnitDetails, error) {
+	f.historySize = historySize
+	return f.details, f.detailsErr
+}
+
+func (s *ShowUnitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.fake = &fakeShowUnitAPI{
+		events: []params.UnitEvent{{
+			Kind:     params.UnitEventHookFailed,
+			Hook:     "config-changed",
+			Time:     t0.Add(1500 * time.Millisecond),
+			Duration: 1500 * time.Millisecond,
+			Message:  "exit status 1",
+		}, {
+			Kind: params.UnitEventHookStarted,
+			Hook: "config-changed",
+			Time: t0,
+		}},
+		details: &params.UnitDetails{
+			Service:        "mysql",
+			Machine:        "1",
+			PublicAddress:  "8.8.8.8",
+			PrivateAddress: "10.0.0.1",
+			Leader:         true,
+			OpenedPorts: []params.PortRange{
+				{FromPort: 3306, ToPort: 3306, Protocol: "tcp"},
+			},
+			Relations: []params.UnitRelationDetails{{
+				Key:            "wordpress:db mysql:server",
+				Endpoint:       "server",
+				Interface:      "mysql",
+				SettingsDigest: "f00d",
+			}},
+			WorkloadStatusHistory: []params.UnitStatusEntry{{
+				Status: params.StatusActive,
+				Info:   "ready",
+				Since:  &t0,
+			}, {
+				Status: params.StatusMaintenance,
+			}},
+			AgentStatusHistory: []params.UnitStatusEntry{{
+				Status: params.StatusIdle,
+				Since:  &t0,
+			}},
+		},
+	}
+}
+
+func (s *ShowUnitSuite) runShowUnit(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &ShowUnitCommand{api: s.fake}
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *ShowUnitSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{}, "no unit specified"},
+		{[]string{"mysql"}, `invalid unit name "mysql"`},
+		{[]string{"mysql/0", "extra"}, `unrecognized args: \["extra"\]`},
+		{[]string{"-n", "0", "mysql/0"}, "invalid number of events: 0"},
+		{[]string{"--history", "0", "mysql/0"}, "invalid status history size: 0"},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.runShowUnit(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ShowUnitSuite) TestShowUnit(c *gc.C) {
+	ctx, err := s.runShowUnit(c, "-n", "5", "--history", "3", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.unit, gc.Equals, names.NewUnitTag("mysql/0"))
+	c.Assert(s.fake.size, gc.Equals, 5)
+	c.Assert(s.fake.historySize, gc.Equals, 3)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+mysql/0:
+  service: mysql
+  machine: "1"
+  public-address: 8.8.8.8
+  private-address: 10.0.0.1
+  leader: true
+  open-ports:
+  - 3306/tcp
+  relations:
+  - relation: wordpress:db mysql:server
+    endpoint: server
+    interface: mysql
+    settings-digest: f00d
+  workload-status-history:
+  - status: active
+    message: ready
+    since: 2015-06-01T12:00:00Z
+  - status: maintenance
+  agent-status-history:
+  - status: idle
+    since: 2015-06-01T12:00:00Z
+  events:
+  - kind: hook-failed
+    hook: config-changed
+    time: 2015-06-01T12:00:01Z
+    duration: 1.5s
+    message: exit status 1
+  - kind: hook-started
+    hook: config-changed
+    time: 2015-06-01T12:00:00Z
+`[1:])
+}
+
+func (s *ShowUnitSuite) TestShowUnitJSON(c *gc.C) {
+	s.fake.events = s.fake.events[1:]
+	s.fake.details = &params.UnitDetails{Service: "mysql"}
+	ctx, err := s.runShowUnit(c, "--format", "json", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.size, gc.Equals, 20)
+	c.Assert(s.fake.historySize, gc.Equals, 5)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals,
+		`{"mysql/0":{"service":"mysql","events":[{"kind":"hook-started","hook":"config-changed","time":"2015-06-01T12:00:00Z"}]}}`+"\n")
+}
+
+func (s *ShowUnitSuite) TestShowUnitDetailsNotImplemented(c *gc.C) {
+	s.fake.events = s.fake.events[1:]
+	s.fake.detailsErr = &params.Error{Code: params.CodeNotImplemented, Message: "not implemented"}
+	ctx, err := s.runShowUnit(c, "--format", "json", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals,
+		`{"mysql/0":{"events":[{"kind":"hook-started","hook":"config-changed","time":"2015-06-01T12:00:00Z"}]}}`+"\n")
+}
+
+func (s *ShowUnitSuite) TestShowUnitError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.runShowUnit(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ShowUnitSuite) TestShowUnitDetailsError(c *gc.C) {
+	s.fake.detailsErr = errors.New("boom")
+	_, err := s.runShowUnit(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -4,14 +4,18 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils"
 	"launchpad.net/gnuflag"
@@ -35,6 +39,13 @@ type SSHCommon struct {
 	Args      []string
 	apiClient sshAPIClient
 	apiAddr   string
+
+	// knownHosts maps the hosts resolved from machine and unit
+	// targets to the SSH host keys recorded for them.
+	knownHosts map[string][]string
+	// unknownHostKeys is set if the host keys of any host resolved
+	// from a machine or unit target are not known.
+	unknownHostKeys bool
 }
 
 func (c *SSHCommon) SetFlags(f *gnuflag.FlagSet) {
@@ -112,8 +123,10 @@ var getJujuExecutable = func() (string, error) {
 func (c *SSHCommon) getSSHOptions(enablePty bool) (*ssh.Options, error) {
 	var options ssh.Options
 
-	// TODO(waigani) do not save fingerprint only until this bug is addressed:
-	// lp:892552. Also see lp:1334481.
+	// Host fingerprints are never saved. Hosts resolved from machine
+	// and unit targets are instead verified against the host keys
+	// recorded by their machine agents; see setKnownHostsFile.
+	// Also see lp:892552 and lp:1334481.
 	options.SetKnownHostsFile("/dev/null")
 	if enablePty {
 		options.EnablePTY()
@@ -151,6 +164,11 @@ func (c *SSHCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	cleanup, err := c.setKnownHostsFile(options)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	cmd := ssh.Command(user+"@"+host, c.Args, options)
 	cmd.Stdin = ctx.Stdin
 	cmd.Stdout = ctx.Stdout
@@ -203,6 +221,7 @@ type sshAPIClient interface {
 	EnvironmentGet() (map[string]interface{}, error)
 	PublicAddress(target string) (string, error)
 	PrivateAddress(target string) (string, error)
+	SSHHostKeys(target string) ([]string, error)
 	ServiceCharmRelations(service string) ([]string, error)
 	Close() error
 }
@@ -255,12 +274,77 @@ func (c *SSHCommon) userHostFromTarget(target string) (user, host string, err er
 			addr, err = c.apiClient.PublicAddress(target)
 		}
 		if err == nil {
+			c.recordHostKeys(target, addr)
 			return user, addr, nil
 		}
 	}
 	return "", "", err
 }
 
+// recordHostKeys looks up the SSH host keys recorded for the given
+// machine or unit target, so that the host it resolved to can be
+// verified when connecting.
+func (c *SSHCommon) recordHostKeys(target, host string) {
+	keys, err := c.apiClient.SSHHostKeys(target)
+	if err != nil {
+		logger.Warningf("cannot get SSH host keys for %q, not verifying host key: %v", target, err)
+		c.unknownHostKeys = true
+		return
+	}
+	if len(keys) == 0 {
+		logger.Warningf("no SSH host keys recorded for %q, not verifying host key", target)
+		c.unknownHostKeys = true
+		return
+	}
+	if c.knownHosts == nil {
+		c.knownHosts = make(map[string][]string)
+	}
+	c.knownHosts[host] = keys
+}
+
+// setKnownHostsFile writes the recorded host keys to a temporary known
+// hosts file, and configures the given options to refuse hosts whose
+// keys do not match it. If the keys of any resolved host are unknown,
+// the options are left unchanged. The returned function removes the
+// file, and must be called once ssh has finished.
+func (c *SSHCommon) setKnownHostsFile(options *ssh.Options) (func(), error) {
+	if c.unknownHostKeys || len(c.knownHosts) == 0 {
+		return func() {}, nil
+	}
+	f, err := ioutil.TempFile("", "juju-known-hosts")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create known hosts file")
+	}
+	defer f.Close()
+	cleanup := func() {
+		os.Remove(f.Name())
+	}
+	if _, err := f.Write(c.knownHostsData()); err != nil {
+		cleanup()
+		return nil, errors.Annotate(err, "cannot write known hosts file")
+	}
+	options.SetKnownHostsFile(f.Name())
+	options.EnableStrictHostKeyChecking()
+	return cleanup, nil
+}
+
+// knownHostsData returns the recorded host keys in known hosts
+// file format.
+func (c *SSHCommon) knownHostsData() []byte {
+	hosts := make([]string, 0, len(c.knownHosts))
+	for host := range c.knownHosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	var buf bytes.Buffer
+	for _, host := range hosts {
+		for _, key := range c.knownHosts[host] {
+			fmt.Fprintf(&buf, "%s %s\n", host, key)
+		}
+	}
+	return buf.Bytes()
+}
+
 // AllowInterspersedFlags for ssh/scp is set to false so that
 // flags after the unit name are passed through to ssh, for eg.
 // `juju ssh -v service-name/0 uname -a`.
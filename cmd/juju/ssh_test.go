@@ -143,6 +143,46 @@ func (s *SSHSuite) TestSSHCommandEnvironProxySSH(c *gc.C) {
 	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, sshArgsNoProxy+"ubuntu@dummyenv-0.dns")
 }
 
+func (s *SSHSuite) TestSSHCommandVerifiesHostKeys(c *gc.C) {
+	m := s.makeMachines(1, c, true)
+	err := m[0].SetSSHHostKeys([]string{"ssh-rsa AAAAB3Nza root@host"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(&SSHCommand{}), ctx, []string{"--proxy=false", "0"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(strings.TrimRight(coretesting.Stdout(ctx), "\r\n"), gc.Matches,
+		`-o StrictHostKeyChecking yes -o PasswordAuthentication no -o ServerAliveInterval 30 `+
+			`-t -t -o UserKnownHostsFile \S*juju-known-hosts\S* ubuntu@dummyenv-0.dns`)
+}
+
+func (s *SSHSuite) TestKnownHostsData(c *gc.C) {
+	var sshCmd SSHCommon
+	sshCmd.knownHosts = map[string][]string{
+		"10.0.0.2": {"ssh-rsa AAAAB3Nza root@two"},
+		"10.0.0.1": {"ssh-rsa AAAAB3Nzb root@one", "ssh-ed25519 AAAAC3Nz root@one"},
+	}
+	c.Assert(string(sshCmd.knownHostsData()), gc.Equals, ""+
+		"10.0.0.1 ssh-rsa AAAAB3Nzb root@one\n"+
+		"10.0.0.1 ssh-ed25519 AAAAC3Nz root@one\n"+
+		"10.0.0.2 ssh-rsa AAAAB3Nza root@two\n",
+	)
+}
+
+func (s *SSHSuite) TestSetKnownHostsFileUnknownHostKeys(c *gc.C) {
+	// Host key checking is not enforced if any host's keys are unknown.
+	var sshCmd SSHCommon
+	sshCmd.knownHosts = map[string][]string{
+		"10.0.0.1": {"ssh-rsa AAAAB3Nzb root@one"},
+	}
+	sshCmd.unknownHostKeys = true
+	var options ssh.Options
+	cleanup, err := sshCmd.setKnownHostsFile(&options)
+	c.Assert(err, jc.ErrorIsNil)
+	defer cleanup()
+	c.Assert(options, jc.DeepEquals, ssh.Options{})
+}
+
 func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	// Check the API client interface used by "juju ssh" against what
 	// the API server will allow during upgrades. Ensure that the API
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
@@ -24,25 +25,27 @@ type StatusHistoryCommand struct {
 	outputContent string
 	backlogSize   int
 	isoTime       bool
-	unitName      string
+	entityName    string
 }
 
 var statusHistoryDoc = `
 This command will report the history of status changes for
-a given unit.
+a given unit or machine.
 The statuses for the unit workload and/or agent are available.
 -type supports:
     agent: will show statuses for the unit's agent
     workload: will show statuses for the unit's workload
     combined: will show agent and workload statuses combined
  and sorted by time of occurence.
+Machines only have agent statuses, so -type workload may not be
+used with a machine.
 `
 
 func (c *StatusHistoryCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "status-history",
-		Args:    "[-n N] <unit>",
-		Purpose: "output past statuses for a unit",
+		Args:    "[-n N] <unit>|<machine>",
+		Purpose: "output past statuses for a unit or machine",
 		Doc:     statusHistoryDoc,
 	}
 }
@@ -60,7 +63,7 @@ func (c *StatusHistoryCommand) Init(args []string) error {
 	case len(args) == 0:
 		return errors.Errorf("unit name is missing.")
 	default:
-		c.unitName = args[0]
+		c.entityName = args[0]
 	}
 	// If use of ISO time not specified on command line,
 	// check env var.
@@ -75,7 +78,12 @@ func (c *StatusHistoryCommand) Init(args []string) error {
 	}
 	kind := params.HistoryKind(c.outputContent)
 	switch kind {
-	case params.KindCombined, params.KindAgent, params.KindWorkload:
+	case params.KindWorkload:
+		if names.IsValidMachine(c.entityName) {
+			return errors.Errorf("machines have no workload status")
+		}
+		return nil
+	case params.KindCombined, params.KindAgent:
 		return nil
 
 	}
@@ -88,21 +96,29 @@ func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
 		return fmt.Errorf(connectionError, c.ConnectionName(), err)
 	}
 	defer apiclient.Close()
-	var statuses *api.UnitStatusHistory
-	kind := params.HistoryKind(c.outputContent)
-	statuses, err = apiclient.UnitStatusHistory(kind, c.unitName, c.backlogSize)
+	var statuses []api.AgentStatus
+	if names.IsValidMachine(c.entityName) {
+		var history *api.MachineStatusHistory
+		history, err = apiclient.MachineStatusHistory(c.entityName, c.backlogSize)
+		statuses = history.Statuses
+	} else {
+		var history *api.UnitStatusHistory
+		kind := params.HistoryKind(c.outputContent)
+		history, err = apiclient.UnitStatusHistory(kind, c.entityName, c.backlogSize)
+		statuses = history.Statuses
+	}
 	if err != nil {
-		if len(statuses.Statuses) == 0 {
+		if len(statuses) == 0 {
 			return errors.Trace(err)
 		}
 		// Display any error, but continue to print status if some was returned
 		fmt.Fprintf(ctx.Stderr, "%v\n", err)
-	} else if len(statuses.Statuses) == 0 {
+	} else if len(statuses) == 0 {
 		return errors.Errorf("no status history available")
 	}
 	table := [][]string{{"TIME", "TYPE", "STATUS", "MESSAGE"}}
 	lengths := []int{1, 1, 1, 1}
-	for _, v := range statuses.Statuses {
+	for _, v := range statuses {
 		fields := []string{formatStatusTime(v.Since, c.isoTime), string(v.Kind), string(v.Status), v.Info}
 		for k, v := range fields {
 			if len(v) > lengths[k] {
@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+const addCommandDoc = `
+Add storage instances to a unit after it has been deployed.
+
+Each storage directive names a store declared in the unit's charm
+metadata, optionally followed by constraints in the same format as
+passed to "juju deploy --storage":
+
+    <store>=<constraints>
+
+where the constraints are a comma separated sequence of POOL, COUNT
+and SIZE. Omitted constraints take the environment defaults, and a
+directive with no constraints at all adds a single storage instance.
+
+Examples:
+    # Add 3 ebs storage instances of 10GiB for "data" to unit u/0.
+    juju storage add u/0 data=ebs,10G,3
+
+    # Add one storage instance for "data" from the default pool.
+    juju storage add u/0 data
+`
+
+// AddCommand adds storage instances to a unit.
+type AddCommand struct {
+	StorageCommandBase
+	unitTag string
+
+	// storageCons holds the storage constraints for each store,
+	// keyed on the store name in the charm's storage metadata.
+	storageCons map[string]storage.Constraints
+}
+
+// Init implements Command.Init.
+func (c *AddCommand) Init(args []string) (err error) {
+	if len(args) < 2 {
+		return errors.New("storage add requires a unit and a storage directive")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return errors.NotValidf("unit name %q", args[0])
+	}
+	c.unitTag = names.NewUnitTag(args[0]).String()
+	c.storageCons, err = parseStorageDirectives(args[1:])
+	return err
+}
+
+// parseStorageDirectives parses storage directives of the form
+// <store>=<constraints>, or just <store> for a single instance with
+// default constraints, returning the constraints keyed on store name.
+func parseStorageDirectives(args []string) (map[string]storage.Constraints, error) {
+	result := make(map[string]storage.Constraints)
+	for _, arg := range args {
+		fields := strings.SplitN(arg, "=", 2)
+		name := fields[0]
+		if name == "" {
+			return nil, errors.Errorf("expected <store>=<constraints>, got %q", arg)
+		}
+		if _, ok := result[name]; ok {
+			return nil, errors.Errorf("storage %q specified more than once", name)
+		}
+		cons := storage.Constraints{Count: 1}
+		if len(fields) == 2 {
+			var err error
+			cons, err = storage.ParseConstraints(fields[1])
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot parse constraints for storage %q", name)
+			}
+		}
+		result[name] = cons
+	}
+	return result, nil
+}
+
+// Info implements Command.Info.
+func (c *AddCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<unit> <store>[=<constraints>] ...",
+		Purpose: "add storage instances to a unit",
+		Doc:     addCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+}
+
+// Run implements Command.Run.
+func (c *AddCommand) Run(ctx *cmd.Context) (err error) {
+	api, err := getStorageAddAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	storages := c.storageAddParams()
+	results, err := api.AddToUnit(storages)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(storages) {
+		return errors.Errorf("expected %d results, got %d", len(storages), len(results))
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			failed = true
+			fmt.Fprintf(ctx.Stderr, "failed to add storage %q: %v\n", storages[i].StorageName, result.Error)
+			continue
+		}
+		fmt.Fprintf(ctx.Stdout, "added storage %q\n", storages[i].StorageName)
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+func (c *AddCommand) storageAddParams() []params.StorageAddParams {
+	storageNames := make([]string, 0, len(c.storageCons))
+	for name := range c.storageCons {
+		storageNames = append(storageNames, name)
+	}
+	sort.Strings(storageNames)
+	all := make([]params.StorageAddParams, len(storageNames))
+	for i, name := range storageNames {
+		all[i] = params.StorageAddParams{
+			UnitTag:     c.unitTag,
+			StorageName: name,
+			Constraints: paramsStorageConstraints(c.storageCons[name]),
+		}
+	}
+	return all
+}
+
+// paramsStorageConstraints converts storage constraints to their
+// params form, leaving unspecified values for the server to default.
+func paramsStorageConstraints(cons storage.Constraints) params.StorageConstraints {
+	result := params.StorageConstraints{Pool: cons.Pool}
+	if cons.Size != 0 {
+		size := cons.Size
+		result.Size = &size
+	}
+	if cons.Count != 0 {
+		count := cons.Count
+		result.Count = &count
+	}
+	return result
+}
+
+var (
+	getStorageAddAPI = (*AddCommand).getStorageAddAPI
+)
+
+// StorageAddAPI defines the API methods that the storage add command uses.
+type StorageAddAPI interface {
+	Close() error
+	AddToUnit(storages []params.StorageAddParams) ([]params.ErrorResult, error)
+}
+
+func (c *AddCommand) getStorageAddAPI() (StorageAddAPI, error) {
+	return c.NewStorageAPI()
+}
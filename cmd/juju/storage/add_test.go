@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type addSuite struct {
+	SubStorageSuite
+	mockAPI *mockAddAPI
+}
+
+var _ = gc.Suite(&addSuite{})
+
+func (s *addSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockAddAPI{}
+	s.PatchValue(storage.GetStorageAddAPI, func(c *storage.AddCommand) (storage.StorageAddAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runAdd(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.AddCommand{}), args...)
+}
+
+func (s *addSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "storage add requires a unit and a storage directive"},
+		{[]string{"u/0"}, "storage add requires a unit and a storage directive"},
+		{[]string{"mysql", "data"}, `unit name "mysql" not valid`},
+		{[]string{"u/0", "=1G"}, `expected <store>=<constraints>, got "=1G"`},
+		{[]string{"u/0", "data=1G", "data=2G"}, `storage "data" specified more than once`},
+		{[]string{"u/0", "data=-1"}, `cannot parse constraints for storage "data": cannot parse count: count must be greater than zero, got "-1"`},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := runAdd(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	c.Assert(s.mockAPI.args, gc.HasLen, 0)
+}
+
+func (s *addSuite) TestAdd(c *gc.C) {
+	ctx, err := runAdd(c, "u/0", "logs", "data=ebs,1G,3")
+	c.Assert(err, jc.ErrorIsNil)
+
+	size, count, one := uint64(1024), uint64(3), uint64(1)
+	c.Assert(s.mockAPI.args, jc.DeepEquals, []params.StorageAddParams{{
+		UnitTag:     "unit-u-0",
+		StorageName: "data",
+		Constraints: params.StorageConstraints{Pool: "ebs", Size: &size, Count: &count},
+	}, {
+		UnitTag:     "unit-u-0",
+		StorageName: "logs",
+		Constraints: params.StorageConstraints{Count: &one},
+	}})
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+added storage "data"
+added storage "logs"
+`[1:])
+	c.Assert(testing.Stderr(ctx), gc.Equals, "")
+}
+
+func (s *addSuite) TestAddFailure(c *gc.C) {
+	s.mockAPI.errors = map[string]*params.Error{
+		"logs": {Message: `charm storage "logs" not found`},
+	}
+	ctx, err := runAdd(c, "u/0", "logs", "data")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `added storage "data"`+"\n")
+	c.Assert(testing.Stderr(ctx), gc.Equals, `failed to add storage "logs": charm storage "logs" not found`+"\n")
+}
+
+func (s *addSuite) TestAddAPIError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := runAdd(c, "u/0", "data")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockAddAPI struct {
+	args   []params.StorageAddParams
+	errors map[string]*params.Error
+	err    error
+}
+
+func (s *mockAddAPI) Close() error {
+	return nil
+}
+
+func (s *mockAddAPI) AddToUnit(storages []params.StorageAddParams) ([]params.ErrorResult, error) {
+	s.args = storages
+	if s.err != nil {
+		return nil, s.err
+	}
+	results := make([]params.ErrorResult, len(storages))
+	for i, one := range storages {
+		results[i].Error = s.errors[one.StorageName]
+	}
+	return results, nil
+}
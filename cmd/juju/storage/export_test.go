@@ -9,6 +9,7 @@ var (
 	GetPoolListAPI    = &getPoolListAPI
 	GetPoolCreateAPI  = &getPoolCreateAPI
 	GetVolumeListAPI  = &getVolumeListAPI
+	GetStorageAddAPI  = &getStorageAddAPI
 
 	ConvertToVolumeInfo = convertToVolumeInfo
 )
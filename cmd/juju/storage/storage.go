@@ -39,6 +39,7 @@ func NewSuperCommand() cmd.Command {
 				UsagePrefix: "juju",
 				Purpose:     storageCmdPurpose,
 			})}
+	storagecmd.Register(envcmd.Wrap(&AddCommand{}))
 	storagecmd.Register(envcmd.Wrap(&ShowCommand{}))
 	storagecmd.Register(envcmd.Wrap(&ListCommand{}))
 	storagecmd.Register(NewPoolSuperCommand())
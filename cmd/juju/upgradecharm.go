@@ -13,6 +13,8 @@ import (
 	"gopkg.in/juju/charm.v5"
 	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/service"
@@ -33,6 +35,9 @@ When no flags are set, the service's charm will be upgraded to the latest
 revision available in the repository from which it was originally deployed. An
 explicit revision can be chosen with the --revision flag.
 
+Charm store charms are upgraded from the charm store the service was
+deployed from, rather than the one currently configured.
+
 If the charm came from a local repository, its path will be assumed to be
 $JUJU_REPOSITORY unless overridden by --repository.
 
@@ -100,6 +105,14 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	return nil
 }
 
+func (c *UpgradeCharmCommand) newServiceAPIClient() (*apiservice.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apiservice.NewClient(root), nil
+}
+
 // Run connects to the specified environment and starts the charm
 // upgrade process.
 func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
@@ -134,6 +147,28 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	defer csClient.jar.Save()
+
+	// Charm store charms are resolved from the charm store the
+	// service's charm came from.
+	var serviceClient *apiservice.Client
+	var oldOrigin *params.CharmOrigin
+	if oldURL.Schema == "cs" || newRef.Schema == "cs" {
+		serviceClient, err = c.newServiceAPIClient()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer serviceClient.Close()
+		oldOrigin, err = serviceClient.CharmOrigin(c.ServiceName)
+		if params.IsCodeNotImplemented(err) || errors.IsNotImplemented(err) {
+			err = nil
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if oldOrigin != nil && oldURL.Schema == "cs" {
+			csClient = csClient.withOrigin(*oldOrigin)
+		}
+	}
 	newURL, repo, err := resolveCharmURL(newRef.String(), csClient.params, ctx.AbsPath(c.RepoPath), conf)
 	if err != nil {
 		return errors.Trace(err)
@@ -158,5 +193,25 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
-	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
+	err = client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+
+	var newOrigin *params.CharmOrigin
+	if addedURL.Schema == "cs" {
+		newOrigin = csClient.origin()
+	}
+	if serviceClient == nil || charmOriginsEqual(oldOrigin, newOrigin) {
+		return nil
+	}
+	return setCharmOrigin(serviceClient, c.ServiceName, newOrigin)
+}
+
+// charmOriginsEqual reports whether the two charm origins are the same.
+func charmOriginsEqual(a, b *params.CharmOrigin) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
@@ -333,3 +333,20 @@ func (s *UpgradeCharmCharmStoreSuite) TestUpgradeCharmAuthorization(c *gc.C) {
 		c.Assert(err, jc.ErrorIsNil)
 	}
 }
+
+func (s *UpgradeCharmCharmStoreSuite) TestUpgradeCharmKeepsCharmOrigin(c *gc.C) {
+	s.uploadCharm(c, "cs:trusty/wordpress-0", "wordpress")
+	err := runDeploy(c, "cs:trusty/wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.uploadCharm(c, "cs:trusty/wordpress-1", "wordpress")
+	err = runUpgradeCharm(c, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	origin, ok := svc.CharmOrigin()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(origin, jc.DeepEquals, state.CharmOrigin{StoreURL: s.srv.URL()})
+	curl, _ := svc.CharmURL()
+	c.Assert(curl.String(), gc.Equals, "cs:trusty/wordpress-1")
+}
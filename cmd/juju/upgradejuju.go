@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/sync"
+	envtools "github.com/juju/juju/environs/tools"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 )
@@ -35,6 +36,7 @@ type UpgradeJujuCommand struct {
 	ResetPrevious bool
 	AssumeYes     bool
 	Series        []string
+	AgentStream   string
 }
 
 var upgradeJujuDoc = `
@@ -69,6 +71,15 @@ Both of these depend on tools availability, which some situations (no
 outgoing internet access) and provider types (such as maas) require that
 you manage yourself; see the documentation for "sync-tools".
 
+Tools are looked for in the stream given by the environment's agent-stream
+setting. The --agent-stream flag searches the "released", "proposed" or
+"devel" stream instead; when the upgrade is started, the environment's
+agent-stream is set to match, so that the agents find the same tools.
+
+An upgrade to a new major version requires juju command-line tools of that
+major version, so major versions cannot be skipped. The progress of each
+agent can be followed in the agent-version fields of "juju status".
+
 The upgrade-juju command will abort if an upgrade is already in
 progress. It will also abort if a previous upgrade was partially
 completed - this can happen if one of the state servers in a high
@@ -92,6 +103,7 @@ func (c *UpgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.AssumeYes, "y", false, "answer 'yes' to confirmation prompts")
 	f.BoolVar(&c.AssumeYes, "yes", false, "")
 	f.Var(newSeriesValue(nil, &c.Series), "series", "upload tools for supplied comma-separated series list (OBSOLETE)")
+	f.StringVar(&c.AgentStream, "agent-stream", "", "search the given stream (released, proposed or devel) for tools")
 }
 
 func (c *UpgradeJujuCommand) Init(args []string) error {
@@ -117,6 +129,11 @@ func (c *UpgradeJujuCommand) Init(args []string) error {
 	if len(c.Series) > 0 && !c.UploadTools {
 		return fmt.Errorf("--series requires --upload-tools")
 	}
+	switch c.AgentStream {
+	case "", envtools.ReleasedStream, envtools.ProposedStream, envtools.DevelStream:
+	default:
+		return fmt.Errorf("invalid --agent-stream %q: expected released, proposed or devel", c.AgentStream)
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -132,7 +149,8 @@ func formatTools(tools coretools.List) string {
 
 type upgradeJujuAPI interface {
 	EnvironmentGet() (map[string]interface{}, error)
-	FindTools(majorVersion, minorVersion int, series, arch string) (result params.FindToolsResult, err error)
+	EnvironmentSet(config map[string]interface{}) error
+	FindToolsInStream(majorVersion, minorVersion int, series, arch, stream string) (result params.FindToolsResult, err error)
 	UploadTools(r io.Reader, vers version.Binary, additionalSeries ...string) (*coretools.Tools, error)
 	AbortCurrentUpgrade() error
 	SetEnvironAgentVersion(version version.Number) error
@@ -186,7 +204,7 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	ctx.Infof("available tools:\n%s", formatTools(context.tools))
 	ctx.Infof("best version:\n    %s", context.chosen)
 	if c.DryRun {
-		ctx.Infof("upgrade to this version by running\n    juju upgrade-juju --version=\"%s\"\n", context.chosen)
+		ctx.Infof("upgrade to this version by running\n    juju upgrade-juju %s\n", c.upgradeArgs(context.chosen))
 	} else {
 		if c.ResetPrevious {
 			if ok, err := c.confirmResetPreviousUpgrade(ctx); !ok || err != nil {
@@ -200,7 +218,28 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 				return block.ProcessBlockedError(err, block.BlockChange)
 			}
 		}
+		// The stream must be set before the version, so that the
+		// agents look for the new tools in the right stream as soon
+		// as they see the new version. It is set back if the upgrade
+		// is refused, leaving the environment as it was.
+		oldStream := cfg.AgentStream()
+		setStream := c.AgentStream != "" && c.AgentStream != oldStream
+		if setStream {
+			attrs := map[string]interface{}{config.AgentStreamKey: c.AgentStream}
+			if err := client.EnvironmentSet(attrs); err != nil {
+				return errors.Annotatef(
+					block.ProcessBlockedError(err, block.BlockChange),
+					"cannot set agent-stream to %q", c.AgentStream,
+				)
+			}
+		}
 		if err := client.SetEnvironAgentVersion(context.chosen); err != nil {
+			if setStream {
+				attrs := map[string]interface{}{config.AgentStreamKey: oldStream}
+				if err := client.EnvironmentSet(attrs); err != nil {
+					logger.Errorf("cannot set agent-stream back to %q: %v", oldStream, err)
+				}
+			}
 			if params.IsCodeUpgradeInProgress(err) {
 				return errors.Errorf("%s\n\n"+
 					"Please wait for the upgrade to complete or if there was a problem with\n"+
@@ -216,6 +255,16 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// upgradeArgs returns the arguments that upgrade to the given version
+// using the stream chosen for this command.
+func (c *UpgradeJujuCommand) upgradeArgs(vers version.Number) string {
+	args := fmt.Sprintf("--version=%q", vers.String())
+	if c.AgentStream != "" {
+		args += fmt.Sprintf(" --agent-stream=%q", c.AgentStream)
+	}
+	return args
+}
+
 const resetPreviousUpgradeMessage = `
 WARNING! using --reset-previous-upgrade when an upgrade is in progress
 will cause the upgrade to fail. Only use this option to clear an
@@ -252,7 +301,7 @@ func (c *UpgradeJujuCommand) initVersions(client upgradeJujuAPI, cfg *config.Con
 		return nil, errUpToDate
 	}
 	clientVersion := version.Current.Number
-	findResult, err := client.FindTools(clientVersion.Major, -1, "", "")
+	findResult, err := client.FindToolsInStream(clientVersion.Major, -1, "", "", c.AgentStream)
 	if err != nil {
 		return nil, err
 	}
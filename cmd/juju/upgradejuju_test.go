@@ -7,10 +7,12 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -103,6 +105,11 @@ var upgradeJujuTests = []struct {
 	currentVersion: "4.2.0-quantal-amd64",
 	args:           []string{"--series", "precise&quantal"},
 	expectInitErr:  `invalid value "precise&quantal" for flag --series: .*`,
+}, {
+	about:          "invalid --agent-stream",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--agent-stream", "testing"},
+	expectInitErr:  `invalid --agent-stream "testing": expected released, proposed or devel`,
 }, {
 	about:          "--series without --upload-tools",
 	currentVersion: "4.2.0-quantal-amd64",
@@ -635,6 +642,63 @@ func (s *UpgradeJujuSuite) TestResetPreviousUpgrade(c *gc.C) {
 	}
 }
 
+func (s *UpgradeJujuSuite) TestUpgradeAgentStream(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--agent-stream", "proposed"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(fakeAPI.findToolsStream, gc.Equals, "proposed")
+	c.Assert(fakeAPI.environmentSetCalledWith, jc.DeepEquals, []map[string]interface{}{{
+		"agent-stream": "proposed",
+	}})
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	// The stream is set before the version, so that agents find the
+	// new tools as soon as they see the new version.
+	c.Assert(fakeAPI.calls, jc.DeepEquals, []string{"EnvironmentSet", "SetEnvironAgentVersion"})
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeAgentStreamUpgradeRefused(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.setVersionErr = errors.New("no way")
+	fakeAPI.patch(s)
+
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--agent-stream", "proposed"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, gc.ErrorMatches, "no way")
+
+	// The stream is set back when the upgrade does not start.
+	c.Assert(fakeAPI.environmentSetCalledWith, jc.DeepEquals, []map[string]interface{}{{
+		"agent-stream": "proposed",
+	}, {
+		"agent-stream": s.Environ.Config().AgentStream(),
+	}})
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeDryRunAgentStream(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--agent-stream", "devel", "--dry-run"})
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := coretesting.Context(c)
+	err = cmd.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(fakeAPI.findToolsStream, gc.Equals, "devel")
+	c.Assert(fakeAPI.environmentSetCalledWith, gc.IsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+	c.Assert(coretesting.Stderr(ctx), jc.Contains,
+		fmt.Sprintf(`juju upgrade-juju --version="%s" --agent-stream="devel"`, fakeAPI.nextVersion.Number))
+}
+
 func NewFakeUpgradeJujuAPI(c *gc.C, st *state.State) *fakeUpgradeJujuAPI {
 	nextVersion := version.Current
 	nextVersion.Minor++
@@ -652,12 +716,18 @@ type fakeUpgradeJujuAPI struct {
 	setVersionErr             error
 	abortCurrentUpgradeCalled bool
 	setVersionCalledWith      version.Number
+	findToolsStream           string
+	environmentSetCalledWith  []map[string]interface{}
+	calls                     []string
 }
 
 func (a *fakeUpgradeJujuAPI) reset() {
 	a.setVersionErr = nil
 	a.abortCurrentUpgradeCalled = false
 	a.setVersionCalledWith = version.Number{}
+	a.findToolsStream = ""
+	a.environmentSetCalledWith = nil
+	a.calls = nil
 }
 
 func (a *fakeUpgradeJujuAPI) patch(s *UpgradeJujuSuite) {
@@ -674,9 +744,16 @@ func (a *fakeUpgradeJujuAPI) EnvironmentGet() (map[string]interface{}, error) {
 	return config.AllAttrs(), nil
 }
 
-func (a *fakeUpgradeJujuAPI) FindTools(majorVersion, minorVersion int, series, arch string) (
+func (a *fakeUpgradeJujuAPI) EnvironmentSet(attrs map[string]interface{}) error {
+	a.environmentSetCalledWith = append(a.environmentSetCalledWith, attrs)
+	a.calls = append(a.calls, "EnvironmentSet")
+	return nil
+}
+
+func (a *fakeUpgradeJujuAPI) FindToolsInStream(majorVersion, minorVersion int, series, arch, stream string) (
 	result params.FindToolsResult, err error,
 ) {
+	a.findToolsStream = stream
 	tools := toolstesting.MakeTools(a.c, a.c.MkDir(), "released", []string{a.nextVersion.String()})
 	return params.FindToolsResult{
 		List:  tools,
@@ -697,6 +774,7 @@ func (a *fakeUpgradeJujuAPI) AbortCurrentUpgrade() error {
 
 func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersion(v version.Number) error {
 	a.setVersionCalledWith = v
+	a.calls = append(a.calls, "SetEnvironAgentVersion")
 	return a.setVersionErr
 }
 
@@ -0,0 +1,145 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const grantAccessDoc = `
+Grant a user read or write access to the current environment. Users with
+read access alone may look at the environment, for example with "juju status"
+or "juju get", but may not change it. Granting read access to a user that
+already has write access has no effect.
+
+Examples:
+  juju user grant auditor read
+  juju user grant bob write
+
+See Also:
+  juju user revoke
+  juju environment share
+`
+
+const revokeAccessDoc = `
+Revoke a user's read or write access to the current environment. A user whose
+write access is revoked may still read the environment; revoking read access
+removes the user's access to the environment altogether.
+
+Examples:
+  juju user revoke bob write
+  juju user revoke auditor read
+
+See Also:
+  juju user grant
+  juju environment unshare
+`
+
+// AccessCommandBase holds the common code for the grant and revoke
+// commands.
+type AccessCommandBase struct {
+	UserCommandBase
+	User   string
+	Access params.EnvironAccess
+}
+
+// GrantCommand grants users access to the environment.
+type GrantCommand struct {
+	AccessCommandBase
+}
+
+// RevokeCommand revokes users' access to the environment.
+type RevokeCommand struct {
+	AccessCommandBase
+}
+
+// Info implements Command.Info.
+func (c *GrantCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "grant",
+		Args:    "<username> read|write",
+		Purpose: "grant a user access to the environment",
+		Doc:     grantAccessDoc,
+	}
+}
+
+// Info implements Command.Info.
+func (c *RevokeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke",
+		Args:    "<username> read|write",
+		Purpose: "revoke a user's access to the environment",
+		Doc:     revokeAccessDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *AccessCommandBase) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	if !names.IsValidUser(args[0]) {
+		return errors.Errorf("invalid username: %q", args[0])
+	}
+	c.User = args[0]
+	if len(args) == 1 {
+		return errors.New("no access level supplied")
+	}
+	switch access := params.EnvironAccess(args[1]); access {
+	case params.EnvironReadAccess, params.EnvironWriteAccess:
+		c.Access = access
+	default:
+		return errors.Errorf(`invalid access level %q, expected "read" or "write"`, args[1])
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+// EnvironAccessAPI defines the API methods that the grant and revoke
+// commands use.
+type EnvironAccessAPI interface {
+	GrantEnvironAccess(access params.EnvironAccess, users ...names.UserTag) error
+	RevokeEnvironAccess(access params.EnvironAccess, users ...names.UserTag) error
+	Close() error
+}
+
+func (c *AccessCommandBase) getEnvironAccessAPI() (EnvironAccessAPI, error) {
+	return c.NewAPIClient()
+}
+
+var getEnvironAccessAPI = (*AccessCommandBase).getEnvironAccessAPI
+
+// Run implements Command.Run.
+func (c *GrantCommand) Run(ctx *cmd.Context) error {
+	client, err := getEnvironAccessAPI(&c.AccessCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.GrantEnvironAccess(c.Access, names.NewUserTag(c.User))
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Granted %s access to user %q", c.Access, c.User)
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *RevokeCommand) Run(ctx *cmd.Context) error {
+	client, err := getEnvironAccessAPI(&c.AccessCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.RevokeEnvironAccess(c.Access, names.NewUserTag(c.User))
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Revoked %s access from user %q", c.Access, c.User)
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type AccessSuite struct {
+	BaseSuite
+	mock mockEnvironAccessAPI
+}
+
+var _ = gc.Suite(&AccessSuite{})
+
+func (s *AccessSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mock = mockEnvironAccessAPI{}
+	s.PatchValue(user.GetEnvironAccessAPI, func(*user.AccessCommandBase) (user.EnvironAccessAPI, error) {
+		return &s.mock, nil
+	})
+}
+
+func (s *AccessSuite) testInit(c *gc.C, command cmd.Command, base *user.AccessCommandBase) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+		user     string
+		access   params.EnvironAccess
+	}{
+		{
+			errMatch: "no username supplied",
+		}, {
+			args:     []string{"not/valid", "read"},
+			errMatch: `invalid username: "not/valid"`,
+		}, {
+			args:     []string{"bob"},
+			errMatch: "no access level supplied",
+		}, {
+			args:     []string{"bob", "admin"},
+			errMatch: `invalid access level "admin", expected "read" or "write"`,
+		}, {
+			args:     []string{"bob", "read", "extra"},
+			errMatch: `unrecognized args: \["extra"\]`,
+		}, {
+			args:   []string{"bob", "read"},
+			user:   "bob",
+			access: params.EnvironReadAccess,
+		}, {
+			args:   []string{"bob@remote", "write"},
+			user:   "bob@remote",
+			access: params.EnvironWriteAccess,
+		},
+	} {
+		c.Logf("test %d, args %v", i, test.args)
+		err := testing.InitCommand(command, test.args)
+		if test.errMatch == "" {
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(base.User, gc.Equals, test.user)
+			c.Assert(base.Access, gc.Equals, test.access)
+		} else {
+			c.Assert(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *AccessSuite) TestInit(c *gc.C) {
+	grant := &user.GrantCommand{}
+	s.testInit(c, grant, &grant.AccessCommandBase)
+	revoke := &user.RevokeCommand{}
+	s.testInit(c, revoke, &revoke.AccessCommandBase)
+}
+
+func (s *AccessSuite) TestGrant(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&user.GrantCommand{}), "auditor", "read")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.granted, gc.Equals, params.EnvironReadAccess)
+	c.Assert(s.mock.users, jc.DeepEquals, []names.UserTag{names.NewUserTag("auditor")})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Granted read access to user \"auditor\"\n")
+}
+
+func (s *AccessSuite) TestRevoke(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&user.RevokeCommand{}), "bob", "write")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.revoked, gc.Equals, params.EnvironWriteAccess)
+	c.Assert(s.mock.users, jc.DeepEquals, []names.UserTag{names.NewUserTag("bob")})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Revoked write access from user \"bob\"\n")
+}
+
+type mockEnvironAccessAPI struct {
+	granted params.EnvironAccess
+	revoked params.EnvironAccess
+	users   []names.UserTag
+}
+
+var _ user.EnvironAccessAPI = (*mockEnvironAccessAPI)(nil)
+
+func (m *mockEnvironAccessAPI) Close() error {
+	return nil
+}
+
+func (m *mockEnvironAccessAPI) GrantEnvironAccess(access params.EnvironAccess, users ...names.UserTag) error {
+	m.granted = access
+	m.users = users
+	return nil
+}
+
+func (m *mockEnvironAccessAPI) RevokeEnvironAccess(access params.EnvironAccess, users ...names.UserTag) error {
+	m.revoked = access
+	m.users = users
+	return nil
+}
@@ -18,6 +18,10 @@ var (
 	GetConnectionCredentials = &getConnectionCredentials
 	// disable and enable
 	GetDisableUserAPI = &getDisableUserAPI
+	// remove
+	GetRemoveUserAPI = &getRemoveUserAPI
+	// grant and revoke
+	GetEnvironAccessAPI = &getEnvironAccessAPI
 )
 
 // DisenableCommand is used for testing both Disable and Enable user commands.
@@ -34,6 +38,10 @@ func (c *EnableCommand) Username() string {
 	return c.user
 }
 
+func (c *RemoveCommand) Username() string {
+	return c.user
+}
+
 var (
 	_ DisenableCommand = (*DisableCommand)(nil)
 	_ DisenableCommand = (*EnableCommand)(nil)
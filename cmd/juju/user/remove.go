@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const removeUserDoc = `
+Removing a user deletes that user's account, so the user can no longer log
+in. Unlike disabling a user, removal cannot be undone, although a new user
+may later be added with the same name. The state server environment owner
+cannot be removed, and nor can any user that owns an environment until
+that environment is destroyed.
+
+Examples:
+  juju user remove foobar
+
+See Also:
+  juju user disable
+`
+
+// RemoveCommand removes users.
+type RemoveCommand struct {
+	UserCommandBase
+	user string
+}
+
+// Info implements Command.Info.
+func (c *RemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<username>",
+		Purpose: "remove a user so they can no longer log in",
+		Doc:     removeUserDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	c.user = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// RemoveUserAPI defines the API methods that the remove command uses.
+type RemoveUserAPI interface {
+	RemoveUser(username string) error
+	Close() error
+}
+
+func (c *RemoveCommand) getRemoveUserAPI() (RemoveUserAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+var getRemoveUserAPI = (*RemoveCommand).getRemoveUserAPI
+
+// Run implements Command.Run.
+func (c *RemoveCommand) Run(ctx *cmd.Context) error {
+	client, err := getRemoveUserAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.RemoveUser(c.user)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	ctx.Infof("User %q removed", c.user)
+	return nil
+}
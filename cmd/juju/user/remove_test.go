@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveUserSuite struct {
+	BaseSuite
+	mock mockRemoveUserAPI
+}
+
+var _ = gc.Suite(&RemoveUserSuite{})
+
+func (s *RemoveUserSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mock = mockRemoveUserAPI{}
+	s.PatchValue(user.GetRemoveUserAPI, func(*user.RemoveCommand) (user.RemoveUserAPI, error) {
+		return &s.mock, nil
+	})
+}
+
+func (s *RemoveUserSuite) removeUserCommand() cmd.Command {
+	return envcmd.Wrap(&user.RemoveCommand{})
+}
+
+func (s *RemoveUserSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+		user     string
+	}{
+		{
+			errMatch: "no username supplied",
+		}, {
+			args:     []string{"username", "password"},
+			errMatch: `unrecognized args: \["password"\]`,
+		}, {
+			args: []string{"username"},
+			user: "username",
+		},
+	} {
+		c.Logf("test %d, args %v", i, test.args)
+		command := &user.RemoveCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.errMatch == "" {
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(command.Username(), gc.Equals, test.user)
+		} else {
+			c.Assert(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *RemoveUserSuite) TestRemove(c *gc.C) {
+	username := "testing"
+	ctx, err := testing.RunCommand(c, s.removeUserCommand(), username)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.remove, gc.Equals, username)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "User \"testing\" removed\n")
+}
+
+type mockRemoveUserAPI struct {
+	remove string
+}
+
+var _ user.RemoveUserAPI = (*mockRemoveUserAPI)(nil)
+
+func (m *mockRemoveUserAPI) Close() error {
+	return nil
+}
+
+func (m *mockRemoveUserAPI) RemoveUser(username string) error {
+	m.remove = username
+	return nil
+}
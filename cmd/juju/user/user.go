@@ -40,6 +40,9 @@ func NewSuperCommand() cmd.Command {
 	usercmd.Register(envcmd.Wrap(&DisableCommand{}))
 	usercmd.Register(envcmd.Wrap(&EnableCommand{}))
 	usercmd.Register(envcmd.Wrap(&ListCommand{}))
+	usercmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	usercmd.Register(envcmd.Wrap(&GrantCommand{}))
+	usercmd.Register(envcmd.Wrap(&RevokeCommand{}))
 	return usercmd
 }
 
@@ -26,9 +26,12 @@ var expectedUserCommmandNames = []string{
 	"change-password",
 	"disable",
 	"enable",
+	"grant",
 	"help",
 	"info",
 	"list",
+	"remove",
+	"revoke",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
@@ -188,7 +188,8 @@ type configChanger func(c *agent.Config)
 // the API.
 func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.Entity, outErr error) {
 	info := agentConfig.APIInfo()
-	st, usedOldPassword, err := openAPIStateUsingInfo(info, a, agentConfig.OldPassword())
+	oldPassword := agentConfig.OldPassword()
+	st, usedOldPassword, err := openAPIStateUsingInfo(info, a, oldPassword)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -198,6 +199,13 @@ func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.
 		}
 	}()
 
+	if usedOldPassword {
+		st, usedOldPassword, err = enrollAgent(st, info, oldPassword, a)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	entity, err := st.Agent().Entity(a.Tag())
 	if err == nil && entity.Life() == params.Dead {
 		logger.Errorf("agent terminating - entity %q is dead", a.Tag())
@@ -225,7 +233,7 @@ func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.
 		if err != nil {
 			return nil, nil, err
 		}
-		err = setAgentPassword(newPassword, info.Password, a, entity)
+		err = setAgentPassword(newPassword, oldPassword, a, entity)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -242,12 +250,56 @@ func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.
 	return st, entity, err
 }
 
+// enrollAgent exchanges the one-time password a machine agent was
+// provisioned with for a new one, and reconnects to the API with it.
+// Machines logged in with a one-time password may only use the
+// Enrollment facade, so this must happen before anything else is done
+// with the connection. The connection must have been made with
+// oldPassword, which is kept so that the agent can still log in if
+// enrollment fails. If the agent is not a machine agent, or the API
+// server does not require enrollment, the connection is returned
+// unchanged, along with an indication that the old password is still
+// in use.
+func enrollAgent(st *api.State, info *api.Info, oldPassword string, a Agent) (*api.State, bool, error) {
+	tag, ok := a.Tag().(names.MachineTag)
+	if !ok || st.BestFacadeVersion("Enrollment") == 0 {
+		return st, true, nil
+	}
+	newPassword, err := utils.RandomPassword()
+	if err != nil {
+		return st, false, err
+	}
+	// As in setAgentPassword, change the configuration first so
+	// that we cannot lock ourselves out.
+	if err := a.ChangeConfig(func(c agent.ConfigSetter) error {
+		c.SetPassword(newPassword)
+		c.SetOldPassword(oldPassword)
+		return nil
+	}); err != nil {
+		return st, false, err
+	}
+	if err := st.Enrollment().Enroll(tag, newPassword); err != nil {
+		return st, false, errors.Annotate(err, "cannot enroll machine agent")
+	}
+
+	// Reconnect to the API with the new password.
+	st.Close()
+	info.Password = newPassword
+	st, err = apiOpen(info, api.DialOpts{})
+	if err != nil {
+		return nil, false, err
+	}
+	return st, false, nil
+}
+
 func setAgentPassword(newPw, oldPw string, a Agent, entity *apiagent.Entity) error {
 	// Change the configuration *before* setting the entity
 	// password, so that we avoid the possibility that
 	// we might successfully change the entity's
 	// password but fail to write the configuration,
-	// thus locking us out completely.
+	// thus locking us out completely. The old password
+	// must be the one the agent logged in with, so that
+	// it still works if setting the new one fails.
 	if err := a.ChangeConfig(func(c agent.ConfigSetter) error {
 		c.SetPassword(newPw)
 		c.SetOldPassword(oldPw)
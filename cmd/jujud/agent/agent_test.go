@@ -212,6 +212,7 @@ func (s *AgentSuite) RunTestOpenAPIState(c *gc.C, ent state.AgentEntity, agentCm
 	conf.SetPassword("")
 	err = conf.Write()
 	c.Assert(err, jc.ErrorIsNil)
+	oldPassword := conf.OldPassword()
 
 	// Check that it starts initially and changes the password
 	assertOpen := func(conf agent.Config) {
@@ -231,6 +232,8 @@ func (s *AgentSuite) RunTestOpenAPIState(c *gc.C, ent state.AgentEntity, agentCm
 	// Read the configuration and check that we can connect with it.
 	conf, err = agent.ReadConfig(agent.ConfigPath(conf.DataDir(), conf.Tag()))
 	c.Assert(err, gc.IsNil)
+	// The password the agent logged in with is kept as its fallback.
+	c.Assert(conf.OldPassword(), gc.Equals, oldPassword)
 	// Check we can open the API with the new configuration.
 	assertOpen(conf)
 }
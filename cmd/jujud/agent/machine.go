@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,8 +33,10 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	apiagent "github.com/juju/juju/api/agent"
+	apicleaner "github.com/juju/juju/api/cleaner"
 	apideployer "github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/api/metricsmanager"
+	apiworkerhealth "github.com/juju/juju/api/workerhealth"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
@@ -58,6 +61,7 @@ import (
 	"github.com/juju/juju/state/multiwatcher"
 	statestorage "github.com/juju/juju/state/storage"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/addresser"
@@ -70,8 +74,10 @@ import (
 	"github.com/juju/juju/worker/dblogpruner"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/dnsregistrar"
 	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
+	"github.com/juju/juju/worker/hostkeyreporter"
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
 	workerlogger "github.com/juju/juju/worker/logger"
@@ -86,10 +92,12 @@ import (
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/stateadapter"
 	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/terminationworker"
 	"github.com/juju/juju/worker/upgrader"
+	"github.com/juju/juju/worker/workerhealth"
 )
 
 const bootstrapMachineId = "0"
@@ -99,6 +107,14 @@ var (
 	retryDelay = 3 * time.Second
 	JujuRun    = paths.MustSucceed(paths.JujuRun(version.Current.Series))
 
+	// hostsFilePath holds the path of the hosts file in which the
+	// machine agent records the addresses of the API servers.
+	hostsFilePath = "/etc/hosts"
+
+	// workerHealthPeriod holds how often the machine agent reports
+	// the health of its workers to the state server.
+	workerHealthPeriod = workerhealth.DefaultPeriod
+
 	// The following are defined as variables to allow the tests to
 	// intercept calls to the functions.
 	useMultipleCPUs          = utils.UseMultipleCPUs
@@ -271,7 +287,8 @@ func NewMachineAgent(
 	upgradeWorkerContext *upgradeWorkerContext,
 	runner worker.Runner,
 ) *MachineAgent {
-
+	workerHealth := workerhealth.NewRegistry()
+	workerHealth.Register("agent", runner)
 	return &MachineAgent{
 		machineId:            machineId,
 		AgentConfigWriter:    agentConfWriter,
@@ -279,6 +296,7 @@ func NewMachineAgent(
 		workersStarted:       make(chan struct{}),
 		upgradeWorkerContext: upgradeWorkerContext,
 		runner:               runner,
+		workerHealth:         workerHealth,
 	}
 }
 
@@ -298,6 +316,10 @@ type MachineAgent struct {
 	restoring            bool
 	workersStarted       chan struct{}
 
+	// workerHealth holds the runners whose workers' health
+	// is reported to the state server.
+	workerHealth *workerhealth.Registry
+
 	mongoInitMutex   sync.Mutex
 	mongoInitialized bool
 }
@@ -385,6 +407,14 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 	if flags := featureflag.String(); flags != "" {
 		logger.Warningf("developer feature flags enabled: %s", flags)
 	}
+	// State servers are not reverted automatically, as their upgrade
+	// steps may have changed the database in ways the previous tools
+	// do not expect.
+	if _, isStateServer := a.CurrentConfig().StateServingInfo(); !isStateServer {
+		if err := cmdutil.RevertToolsIfCrashLooping(logger, a.CurrentConfig().DataDir(), a.Tag().String()); err != nil {
+			return err
+		}
+	}
 
 	// Before doing anything else, we need to make sure the certificate generated for
 	// use by mongo to validate state server connections is correct. This needs to be done
@@ -424,6 +454,7 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 		err = a.executeRebootOrShutdown(params.ShouldShutdown)
 	}
 	err = cmdutil.AgentDone(logger, err)
+	cmdutil.RecordAgentExit(logger, agentConfig.DataDir(), a.Tag().String(), err)
 	a.tomb.Kill(err)
 	return err
 }
@@ -628,12 +659,18 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 	if err := st.Upgrader().SetVersion(agentConfig.Tag().String(), currentTools.Version); err != nil {
 		return nil, errors.Annotate(err, "cannot set machine agent version")
 	}
+	cmdutil.ReportRevertedTools(logger, agentConfig.DataDir(), agentConfig.Tag().String(), func(info string) error {
+		return a.setMachineStatus(st, params.StatusStarted, info)
+	})
 
 	runner := newConnRunner(st)
+	a.workerHealth.Register("api", runner)
 
 	// Run the upgrader and the upgrade-steps worker without waiting for
-	// the upgrade steps to complete.
-	runner.StartWorker("upgrader", func() (worker.Worker, error) {
+	// the upgrade steps to complete. The upgrader keeps the default
+	// restart policy, so that an upgrade can always replace tools
+	// that break other workers.
+	runner.StartWorkerWithPolicy("upgrader", worker.DefaultRestartPolicy(), func() (worker.Worker, error) {
 		return upgrader.NewUpgrader(
 			st.Upgrader(),
 			agentConfig,
@@ -671,6 +708,7 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 	}
 
 	runner := newConnRunner(st)
+	a.workerHealth.Register("api-post-upgrade", runner)
 	// TODO(fwereade): this is *still* a hideous layering violation, but at least
 	// it's confined to jujud rather than extending into the worker itself.
 	// Start this worker first to try and get proxy settings in place
@@ -697,6 +735,12 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 	runner.StartWorker("apiaddressupdater", func() (worker.Worker, error) {
 		return apiaddressupdater.NewAPIAddressUpdater(st.Machiner(), a.apiAddressSetter), nil
 	})
+	if version.Current.OS != version.Windows {
+		runner.StartWorker("apihostsupdater", func() (worker.Worker, error) {
+			hostsFile := apiaddressupdater.NewHostsFile(hostsFilePath)
+			return apiaddressupdater.NewAPIAddressUpdater(st.Machiner(), hostsFile), nil
+		})
+	}
 	runner.StartWorker("logger", func() (worker.Worker, error) {
 		return workerlogger.NewLogger(st.Logger(), agentConfig), nil
 	})
@@ -757,6 +801,15 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		})
 	}
 
+	runner.StartWorker("hostkeyreporter", func() (worker.Worker, error) {
+		tag := agentConfig.Tag().(names.MachineTag)
+		return hostkeyreporter.NewWorker(st.HostKeyReporter(), tag, hostkeyreporter.DefaultSSHDir), nil
+	})
+	runner.StartWorker("workerhealth", func() (worker.Worker, error) {
+		facade := apiworkerhealth.NewClient(st)
+		return workerhealth.NewWorker(facade, agentConfig.Tag(), a.workerHealth, workerHealthPeriod), nil
+	})
+
 	// Perform the operations needed to set up hosting for containers.
 	if err := a.setupContainerSupport(runner, st, entity, agentConfig); err != nil {
 		cause := errors.Cause(err)
@@ -924,7 +977,8 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 	stor := statestorage.NewStorage(st.EnvironUUID(), st.MongoSession())
 	registerSimplestreamsDataSource(stor)
 
-	runner := newConnRunner(st)
+	runner := newStateConnRunner(st)
+	a.workerHealth.Register("state", runner)
 	singularRunner, err := newSingularStateRunner(runner, st, m)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1021,7 +1075,8 @@ func (a *MachineAgent) startEnvWorkers(
 	// Create a runner for workers specific to this
 	// environment. Either the State or API connection failing will be
 	// considered fatal, killing the runner and all its workers.
-	runner = newConnRunner(st, apiSt)
+	runner = newStateConnRunner(st, apiSt)
+	a.workerHealth.Register("env-"+envUUID, runner)
 	defer func() {
 		if err != nil && runner != nil {
 			runner.Kill()
@@ -1059,19 +1114,19 @@ func (a *MachineAgent) startEnvWorkers(
 	runner.StartWorker("instancepoller", func() (worker.Worker, error) {
 		return instancepoller.NewWorker(st), nil
 	})
-	singularRunner.StartWorker("cleaner", func() (worker.Worker, error) {
-		return cleaner.NewCleaner(st), nil
-	})
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
-		return addresser.NewWorker(st)
+		return addresser.NewWorker(stateadapter.NewStateAddresser(st))
+	})
+	singularRunner.StartWorker("dnsregistrar", func() (worker.Worker, error) {
+		return dnsregistrar.NewWorker(st)
 	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
-		return provisioner.NewEnvironProvisioner(apiSt.Provisioner(), agentConfig), nil
+		return provisioner.NewEnvironProvisioner(apiSt.Provisioner(), agentConfig, clock.WallClock), nil
 	})
 	singularRunner.StartWorker("environ-storageprovisioner", func() (worker.Worker, error) {
 		scope := agentConfig.Environment()
@@ -1081,6 +1136,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("charm-revision-updater", func() (worker.Worker, error) {
 		return charmrevisionworker.NewRevisionUpdateWorker(apiSt.CharmRevisionUpdater()), nil
 	})
+	singularRunner.StartWorker("cleaner", func() (worker.Worker, error) {
+		return cleaner.NewCleaner(apicleaner.NewAPI(apiSt)), nil
+	})
 	runner.StartWorker("metricmanagerworker", func() (worker.Worker, error) {
 		return metricworker.NewMetricsManager(getMetricAPI(apiSt))
 	})
@@ -1150,7 +1208,7 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 	if err != nil {
 		return nil, err
 	}
-	return apiserver.NewServer(st, listener, apiserver.ServerConfig{
+	serverConfig := apiserver.ServerConfig{
 		Cert:        cert,
 		Key:         key,
 		Tag:         tag,
@@ -1158,7 +1216,147 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 		LogDir:      logDir,
 		Validator:   a.limitLogins,
 		CertChanged: certChanged,
-	})
+	}
+	// Agent login throttling may be tuned in the agent configuration;
+	// the API server uses its defaults for unset or invalid values.
+	if value := agentConfig.Value(agent.AgentLoginRate); value != "" {
+		if rate, err := strconv.ParseFloat(value, 64); err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.AgentLoginRate, value, err)
+		} else {
+			serverConfig.AgentLoginRate = rate
+		}
+	}
+	if value := agentConfig.Value(agent.AgentLoginBurst); value != "" {
+		if burst, err := strconv.ParseInt(value, 10, 64); err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.AgentLoginBurst, value, err)
+		} else {
+			serverConfig.AgentLoginBurst = burst
+		}
+	}
+	// The audit log is disabled unless enabled in the agent
+	// configuration, and only written to a file when one is named.
+	if value := agentConfig.Value(agent.AuditLog); value != "" {
+		if enabled, err := strconv.ParseBool(value); err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.AuditLog, value, err)
+		} else {
+			serverConfig.AuditLog = enabled
+		}
+	}
+	if filename := agentConfig.Value(agent.AuditLogFile); serverConfig.AuditLog && filename != "" {
+		serverConfig.AuditLogFile = &lumberjack.Logger{
+			Filename:   filename,
+			MaxSize:    300, // megabytes
+			MaxBackups: 10,
+		}
+	}
+	if value := agentConfig.Value(agent.CharmDownloadRate); value != "" {
+		if rate, err := strconv.ParseInt(value, 10, 64); err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.CharmDownloadRate, value, err)
+		} else {
+			serverConfig.CharmDownloadRate = rate
+		}
+	}
+	// Concurrency limits are given as a comma-separated list of
+	// operation=limit pairs, e.g. "Client.FullStatus=5,charms=2".
+	if value := agentConfig.Value(agent.APIConcurrencyLimits); value != "" {
+		limits, err := parseConcurrencyLimits(value)
+		if err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.APIConcurrencyLimits, value, err)
+		}
+		serverConfig.ConcurrencyLimits = limits
+	}
+	if value := agentConfig.Value(agent.APIAdmissionTimeout); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.APIAdmissionTimeout, value, err)
+		}
+		serverConfig.AdmissionTimeout = timeout
+	}
+	// External authorization policies restrict API calls further than
+	// the facades do: a comma-separated list of entity tags whose calls
+	// are all refused, and a semicolon-separated list of
+	// Facade.Method=tag,... entries restricting methods to the listed
+	// entities, e.g. "Client.DestroyMachines=user-admin".
+	if value := agentConfig.Value(agent.APIDeniedEntities); value != "" {
+		denied, err := parseDenyList(value)
+		if err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.APIDeniedEntities, value, err)
+		} else {
+			serverConfig.Authorizers = append(serverConfig.Authorizers, denied)
+		}
+	}
+	if value := agentConfig.Value(agent.APIMethodPolicy); value != "" {
+		policy, err := parseMethodPolicy(value)
+		if err != nil {
+			logger.Warningf("ignoring invalid %s %q: %v", agent.APIMethodPolicy, value, err)
+		} else {
+			serverConfig.Authorizers = append(serverConfig.Authorizers, policy)
+		}
+	}
+	// The mongo driver's operation counts, reported by the ServerStats
+	// facade, cover every mongo session in the process, and keeping
+	// them takes a lock on each operation. Only state server agents,
+	// which serve that facade, collect them.
+	mgo.SetStats(true)
+	return apiserver.NewServer(st, listener, serverConfig)
+}
+
+// parseTags parses a comma-separated list of entity tags.
+func parseTags(value string) ([]names.Tag, error) {
+	var tags []names.Tag
+	for _, field := range strings.Split(value, ",") {
+		tag, err := names.ParseTag(strings.TrimSpace(field))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// parseDenyList parses a comma-separated list of entity tags.
+func parseDenyList(value string) (apiserver.DenyList, error) {
+	tags, err := parseTags(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apiserver.DenyList(tags), nil
+}
+
+// parseMethodPolicy parses a semicolon-separated list of
+// Facade.Method=tag,... entries.
+func parseMethodPolicy(value string) (apiserver.MethodPolicy, error) {
+	policy := make(apiserver.MethodPolicy)
+	for _, field := range strings.Split(value, ";") {
+		parts := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(parts) != 2 || !strings.Contains(parts[0], ".") {
+			return nil, errors.Errorf("expected Facade.Method=tag,..., got %q", field)
+		}
+		tags, err := parseTags(parts[1])
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid entities for %q", parts[0])
+		}
+		policy[parts[0]] = tags
+	}
+	return policy, nil
+}
+
+// parseConcurrencyLimits parses a comma-separated list of
+// operation=limit pairs.
+func parseConcurrencyLimits(value string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, field := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("expected operation=limit, got %q", field)
+		}
+		limit, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid limit for %q", parts[0])
+		}
+		limits[parts[0]] = limit
+	}
+	return limits, nil
 }
 
 // limitLogins is called by the API server for each login attempt.
@@ -1590,8 +1788,27 @@ func (a *MachineAgent) uninstallAgent(agentConfig agent.Config) error {
 	return fmt.Errorf("uninstall failed: %v", errors)
 }
 
-func newConnRunner(conns ...cmdutil.Pinger) worker.Runner {
-	return worker.NewRunner(cmdutil.ConnectionIsFatal(logger, conns...), cmdutil.MoreImportant)
+func newConnRunner(conns ...cmdutil.Pinger) worker.PolicyRunner {
+	return cmdutil.NewRunner(cmdutil.ConnectionIsFatal(logger, conns...))
+}
+
+// newStateConnRunner returns a runner like newConnRunner, for workers
+// using a State connection. Singular workers started on it stop when
+// the state server's master status changes, which is also considered
+// fatal so that they can be started afresh according to the new
+// status.
+func newStateConnRunner(conns ...cmdutil.Pinger) worker.PolicyRunner {
+	return cmdutil.NewRunner(stateConnIsFatal(conns...))
+}
+
+func stateConnIsFatal(conns ...cmdutil.Pinger) func(err error) bool {
+	connIsFatal := cmdutil.ConnectionIsFatal(logger, conns...)
+	return func(err error) bool {
+		if errors.Cause(err) == singular.ErrMasterChanged {
+			return true
+		}
+		return connIsFatal(err)
+	}
 }
 
 type MongoSessioner interface {
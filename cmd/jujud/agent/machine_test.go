@@ -35,6 +35,7 @@ import (
 	apimetricsmanager "github.com/juju/juju/api/metricsmanager"
 	apinetworker "github.com/juju/juju/api/networker"
 	apirsyslog "github.com/juju/juju/api/rsyslog"
+	"github.com/juju/juju/apiserver"
 	charmtesting "github.com/juju/juju/apiserver/charmrevisionupdater/testing"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
@@ -120,6 +121,8 @@ func (s *commonMachineSuite) SetUpTest(c *gc.C) {
 	os.Remove(JujuRun) // ignore error; may not exist
 	// Patch ssh user to avoid touching ~ubuntu/.ssh/authorized_keys.
 	s.AgentSuite.PatchValue(&authenticationworker.SSHUser, "")
+	// Patch the hosts file path to avoid touching /etc/hosts.
+	s.AgentSuite.PatchValue(&hostsFilePath, filepath.Join(c.MkDir(), "hosts"))
 
 	testpath := c.MkDir()
 	s.AgentSuite.PatchEnvPathPrepend(testpath)
@@ -239,11 +242,12 @@ type MachineSuite struct {
 }
 
 var perEnvSingularWorkers = []string{
-	"cleaner",
 	"minunitsworker",
 	"addresserworker",
+	"dnsregistrar",
 	"environ-provisioner",
 	"charm-revision-updater",
+	"cleaner",
 	"firewaller",
 }
 
@@ -2065,3 +2069,76 @@ func runWithTimeout(r runner) error {
 	err := r.Stop()
 	return fmt.Errorf("timed out waiting for agent to finish; stop error: %v", err)
 }
+
+type parseConcurrencyLimitsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&parseConcurrencyLimitsSuite{})
+
+func (s *parseConcurrencyLimitsSuite) TestParse(c *gc.C) {
+	limits, err := parseConcurrencyLimits("Client.FullStatus=5, charms=0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, jc.DeepEquals, map[string]int{
+		"Client.FullStatus": 5,
+		"charms":            0,
+	})
+}
+
+func (s *parseConcurrencyLimitsSuite) TestParseErrors(c *gc.C) {
+	_, err := parseConcurrencyLimits("charms")
+	c.Assert(err, gc.ErrorMatches, `expected operation=limit, got "charms"`)
+	_, err = parseConcurrencyLimits("charms=lots")
+	c.Assert(err, gc.ErrorMatches, `invalid limit for "charms": .*`)
+}
+
+type parseAuthorizersSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&parseAuthorizersSuite{})
+
+func (s *parseAuthorizersSuite) TestParseDenyList(c *gc.C) {
+	denied, err := parseDenyList("user-bob, machine-3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(denied, jc.DeepEquals, apiserver.DenyList{
+		names.NewUserTag("bob"),
+		names.NewMachineTag("3"),
+	})
+	_, err = parseDenyList("bob")
+	c.Assert(err, gc.ErrorMatches, `"bob" is not a valid tag`)
+}
+
+func (s *parseAuthorizersSuite) TestParseMethodPolicy(c *gc.C) {
+	policy, err := parseMethodPolicy("Client.DestroyMachines=user-admin,user-bob; Service.*=user-admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, apiserver.MethodPolicy{
+		"Client.DestroyMachines": {names.NewUserTag("admin"), names.NewUserTag("bob")},
+		"Service.*":              {names.NewUserTag("admin")},
+	})
+}
+
+func (s *parseAuthorizersSuite) TestParseMethodPolicyErrors(c *gc.C) {
+	_, err := parseMethodPolicy("Client=user-admin")
+	c.Assert(err, gc.ErrorMatches, `expected Facade.Method=tag,..., got "Client=user-admin"`)
+	_, err = parseMethodPolicy("Client.Status=admin")
+	c.Assert(err, gc.ErrorMatches, `invalid entities for "Client.Status": "admin" is not a valid tag`)
+}
+
+type okPinger struct{}
+
+func (okPinger) Ping() error {
+	return nil
+}
+
+func (s *MachineSuite) TestStateConnIsFatal(c *gc.C) {
+	isFatal := stateConnIsFatal(okPinger{})
+	c.Check(isFatal(singular.ErrMasterChanged), jc.IsTrue)
+	c.Check(isFatal(errors.Annotate(singular.ErrMasterChanged, "resumer")), jc.IsTrue)
+	c.Check(isFatal(worker.ErrTerminateAgent), jc.IsTrue)
+	c.Check(isFatal(errors.New("foo")), jc.IsFalse)
+
+	// Outside the state connection runners, a change of master status
+	// just restarts the worker that noticed it.
+	c.Check(cmdutil.IsFatal(singular.ErrMasterChanged), jc.IsFalse)
+}
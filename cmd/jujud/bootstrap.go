@@ -245,6 +245,11 @@ func (c *BootstrapCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 
+	// Record the subnets available to the environment.
+	if err := c.populateSubnets(st, env); err != nil {
+		return err
+	}
+
 	// bootstrap machine always gets the vote
 	return m.SetHasVote(true)
 }
@@ -303,6 +308,39 @@ func (c *BootstrapCommand) populateDefaultStoragePools(st *state.State) error {
 	return poolmanager.AddDefaultStoragePools(settings)
 }
 
+// populateSubnets adds the subnets that the environment's instances
+// can be started in to state. Environments whose provider cannot list
+// all of their subnets are left without any.
+func (c *BootstrapCommand) populateSubnets(st *state.State, env environs.Environ) error {
+	netEnv, ok := environs.SupportsNetworking(env)
+	if !ok {
+		return nil
+	}
+	subnets, err := netEnv.Subnets("", nil)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not discovering subnets: %v", err)
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot discover subnets")
+	}
+	for _, subnet := range subnets {
+		info := state.SubnetInfo{
+			ProviderId: string(subnet.ProviderId),
+			CIDR:       subnet.CIDR,
+			VLANTag:    subnet.VLANTag,
+		}
+		if subnet.AllocatableIPLow != nil && subnet.AllocatableIPHigh != nil {
+			info.AllocatableIPLow = subnet.AllocatableIPLow.String()
+			info.AllocatableIPHigh = subnet.AllocatableIPHigh.String()
+		}
+		if _, err := st.AddSubnet(info); err != nil && !errors.IsAlreadyExists(err) {
+			return errors.Trace(err)
+		}
+		logger.Debugf("added subnet %q (%s)", subnet.CIDR, subnet.ProviderId)
+	}
+	return nil
+}
+
 // populateTools stores uploaded tools in provider storage
 // and updates the tools metadata.
 func (c *BootstrapCommand) populateTools(st *state.State, env environs.Environ) error {
@@ -713,3 +713,30 @@ func (s *BootstrapSuite) TestDefaultStoragePools(c *gc.C) {
 		c.Assert(err, jc.ErrorIsNil)
 	}
 }
+
+func (s *BootstrapSuite) TestSubnetsDiscovered(c *gc.C) {
+	_, cmd, err := s.initBootstrapCommand(c, nil, "--env-config", s.b64yamlEnvcfg, "--instance-id", string(s.instanceId))
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	st, err := state.Open(&mongo.MongoInfo{
+		Info: mongo.Info{
+			Addrs:  []string{gitjujutesting.MgoServer.Addr()},
+			CACert: testing.CACert,
+		},
+		Password: testPasswordHash(),
+	}, mongo.DefaultDialOpts(), environs.NewStatePolicy())
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// The dummy provider lists two subnets for the environment.
+	for cidr, providerId := range map[string]string{
+		"0.10.0.0/24": "dummy-private",
+		"0.20.0.0/24": "dummy-public",
+	} {
+		subnet, err := st.Subnet(cidr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(subnet.ProviderId(), gc.Equals, providerId)
+	}
+}
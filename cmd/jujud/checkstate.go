@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/agent"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+)
+
+const checkStateDoc = `
+Check the state of every environment hosted by the state server for
+dangling references left behind by bugs in earlier versions of juju:
+units assigned to machines that no longer exist, IP addresses allocated
+to machines that no longer exist, and relations with services that no
+longer exist. Inconsistencies are listed under the name of the
+environment they were found in.
+
+With --repair, each inconsistency found is also repaired: units are
+unassigned from missing machines, addresses of missing machines are
+released, and relations with missing services are removed.
+
+The command must be run on a state server, and connects to mongo using
+the credentials of the machine agent given by --machine-id.
+`
+
+// CheckStateCommand checks state for dangling references, and
+// optionally repairs them.
+type CheckStateCommand struct {
+	cmd.CommandBase
+	dataDir   string
+	machineId string
+	repair    bool
+}
+
+// Info returns usage information for the command.
+func (c *CheckStateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "check-state",
+		Purpose: "check state for dangling references",
+		Doc:     checkStateDoc,
+	}
+}
+
+func (c *CheckStateCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.dataDir, "data-dir", cmdutil.DataDir, "directory for juju data")
+	f.StringVar(&c.machineId, "machine-id", "0", "id of the state server machine agent")
+	f.BoolVar(&c.repair, "repair", false, "repair the inconsistencies found")
+}
+
+func (c *CheckStateCommand) Init(args []string) error {
+	if !names.IsValidMachine(c.machineId) {
+		return errors.Errorf("--machine-id option must be set, and expects a non-negative integer")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// consistencyState holds the state methods used by the check-state
+// command.
+type consistencyState interface {
+	CheckConsistency() ([]state.Inconsistency, error)
+	RepairInconsistency(state.Inconsistency) error
+	Close() error
+}
+
+// environConsistencyState holds the consistencyState of the named
+// environment.
+type environConsistencyState struct {
+	name string
+	consistencyState
+}
+
+// openConsistencyStates opens the state of every environment hosted
+// by the state server. It is a variable so it can be patched in tests.
+var openConsistencyStates = func(info *mongo.MongoInfo) (_ []environConsistencyState, err error) {
+	st, err := state.Open(info, mongo.DefaultDialOpts(), environs.NewStatePolicy())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer st.Close()
+	envs, err := st.AllEnvironments()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list environments")
+	}
+	var result []environConsistencyState
+	defer func() {
+		if err != nil {
+			for _, envSt := range result {
+				envSt.Close()
+			}
+		}
+	}()
+	for _, env := range envs {
+		envSt, err := st.ForEnviron(env.EnvironTag())
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot open environment %q", env.Name())
+		}
+		result = append(result, environConsistencyState{env.Name(), envSt})
+	}
+	return result, nil
+}
+
+func (c *CheckStateCommand) Run(ctx *cmd.Context) error {
+	tag := names.NewMachineTag(c.machineId)
+	conf, err := agent.ReadConfig(agent.ConfigPath(c.dataDir, tag))
+	if err != nil {
+		return errors.Annotate(err, "cannot read agent configuration")
+	}
+	info, ok := conf.MongoInfo()
+	if !ok {
+		return errors.Errorf("%s is not a state server", tag)
+	}
+	states, err := openConsistencyStates(info)
+	if err != nil {
+		return errors.Annotate(err, "cannot open state")
+	}
+	defer func() {
+		for _, st := range states {
+			st.Close()
+		}
+	}()
+
+	var anyFound, failed bool
+	for _, st := range states {
+		found, err := st.CheckConsistency()
+		if err != nil {
+			return errors.Annotatef(err, "cannot check environment %q", st.name)
+		}
+		if len(found) == 0 {
+			continue
+		}
+		anyFound = true
+		fmt.Fprintf(ctx.Stdout, "environment %q:\n", st.name)
+		for _, inc := range found {
+			fmt.Fprintf(ctx.Stdout, "  %v\n", inc)
+			if !c.repair {
+				continue
+			}
+			if err := st.RepairInconsistency(inc); err != nil {
+				fmt.Fprintf(ctx.Stderr, "environment %q: %v\n", st.name, err)
+				failed = true
+				continue
+			}
+			fmt.Fprintln(ctx.Stdout, "    repaired")
+		}
+	}
+	if !anyFound {
+		fmt.Fprintln(ctx.Stdout, "no inconsistencies found")
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type CheckStateSuite struct {
+	testing.BaseSuite
+	dataDir string
+	fake    *fakeConsistencyState
+}
+
+var _ = gc.Suite(&CheckStateSuite{})
+
+type fakeConsistencyState struct {
+	found     []state.Inconsistency
+	repaired  []state.Inconsistency
+	repairErr error
+	closed    bool
+}
+
+func (f *fakeConsistencyState) CheckConsistency() ([]state.Inconsistency, error) {
+	return f.found, nil
+}
+
+func (f *fakeConsistencyState) RepairInconsistency(i state.Inconsistency) error {
+	if f.repairErr != nil {
+		return f.repairErr
+	}
+	f.repaired = append(f.repaired, i)
+	return nil
+}
+
+func (f *fakeConsistencyState) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *CheckStateSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+	s.fake = &fakeConsistencyState{
+		found: []state.Inconsistency{{
+			Kind:    state.UnitMachineMissing,
+			Entity:  "mysql/0",
+			Missing: "1",
+		}},
+	}
+	s.PatchValue(&openConsistencyStates, func(info *mongo.MongoInfo) ([]environConsistencyState, error) {
+		c.Check(info.Tag, gc.Equals, names.NewMachineTag("0"))
+		return []environConsistencyState{{"dummyenv", s.fake}}, nil
+	})
+}
+
+func (s *CheckStateSuite) writeAgentConfig(c *gc.C, stateServer bool) {
+	agentParams := agent.AgentConfigParams{
+		DataDir:           s.dataDir,
+		LogDir:            c.MkDir(),
+		Tag:               names.NewMachineTag("0"),
+		UpgradedToVersion: version.Current.Number,
+		Password:          "sekrit",
+		Nonce:             agent.BootstrapNonce,
+		Environment:       testing.EnvironmentTag,
+		StateAddresses:    []string{"127.0.0.1:37017"},
+		APIAddresses:      []string{"127.0.0.1:17070"},
+		CACert:            testing.CACert,
+	}
+	var conf agent.ConfigSetterWriter
+	var err error
+	if stateServer {
+		conf, err = agent.NewStateMachineConfig(agentParams, params.StateServingInfo{
+			Cert:         "some cert",
+			PrivateKey:   "some key",
+			CAPrivateKey: "another key",
+			APIPort:      17070,
+			StatePort:    37017,
+		})
+	} else {
+		conf, err = agent.NewAgentConfig(agentParams)
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	err = conf.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CheckStateSuite) TestArgParsing(c *gc.C) {
+	err := testing.InitCommand(&CheckStateCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	err = testing.InitCommand(&CheckStateCommand{}, []string{"--machine-id", "foo"})
+	c.Assert(err, gc.ErrorMatches, "--machine-id option must be set, and expects a non-negative integer")
+}
+
+func (s *CheckStateSuite) TestNotStateServer(c *gc.C) {
+	s.writeAgentConfig(c, false)
+	_, err := testing.RunCommand(c, &CheckStateCommand{}, "--data-dir", s.dataDir)
+	c.Assert(err, gc.ErrorMatches, "machine-0 is not a state server")
+}
+
+func (s *CheckStateSuite) TestCheck(c *gc.C) {
+	s.writeAgentConfig(c, true)
+	ctx, err := testing.RunCommand(c, &CheckStateCommand{}, "--data-dir", s.dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `environment "dummyenv":
+  unit "mysql/0" is assigned to missing machine "1"
+`)
+	c.Assert(s.fake.repaired, gc.HasLen, 0)
+	c.Assert(s.fake.closed, jc.IsTrue)
+}
+
+func (s *CheckStateSuite) TestCheckAllEnvironments(c *gc.C) {
+	s.writeAgentConfig(c, true)
+	clean := &fakeConsistencyState{}
+	other := &fakeConsistencyState{
+		found: []state.Inconsistency{{
+			Kind:    state.RelationServiceMissing,
+			Entity:  "wordpress:db mysql:server",
+			Missing: "mysql",
+		}},
+	}
+	s.PatchValue(&openConsistencyStates, func(*mongo.MongoInfo) ([]environConsistencyState, error) {
+		return []environConsistencyState{
+			{"dummyenv", s.fake},
+			{"clean", clean},
+			{"other", other},
+		}, nil
+	})
+	ctx, err := testing.RunCommand(c, &CheckStateCommand{}, "--data-dir", s.dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `environment "dummyenv":
+  unit "mysql/0" is assigned to missing machine "1"
+environment "other":
+  relation "wordpress:db mysql:server" refers to missing service "mysql"
+`)
+	for _, st := range []*fakeConsistencyState{s.fake, clean, other} {
+		c.Assert(st.closed, jc.IsTrue)
+	}
+}
+
+func (s *CheckStateSuite) TestCheckNothingFound(c *gc.C) {
+	s.writeAgentConfig(c, true)
+	s.fake.found = nil
+	ctx, err := testing.RunCommand(c, &CheckStateCommand{}, "--data-dir", s.dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "no inconsistencies found\n")
+}
+
+func (s *CheckStateSuite) TestRepair(c *gc.C) {
+	s.writeAgentConfig(c, true)
+	ctx, err := testing.RunCommand(c, &CheckStateCommand{}, "--data-dir", s.dataDir, "--repair")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `environment "dummyenv":
+  unit "mysql/0" is assigned to missing machine "1"
+    repaired
+`)
+	c.Assert(s.fake.repaired, jc.DeepEquals, s.fake.found)
+}
+
+func (s *CheckStateSuite) TestRepairError(c *gc.C) {
+	s.writeAgentConfig(c, true)
+	s.fake.repairErr = errors.New("boom")
+	ctx, err := testing.RunCommand(c, &CheckStateCommand{}, "--data-dir", s.dataDir, "--repair")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, `environment "dummyenv": boom`+"\n")
+}
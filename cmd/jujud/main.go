@@ -128,6 +128,8 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	a := NewUnitAgent()
 	a.ctx = ctx
 	jujud.Register(a)
+	jujud.Register(&RevertToolsCommand{})
+	jujud.Register(&CheckStateCommand{})
 
 	code = cmd.Main(jujud, ctx, args[1:])
 	return code, nil
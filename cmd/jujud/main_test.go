@@ -97,7 +97,7 @@ func (s *MainSuite) TestParseErrors(c *gc.C) {
 	msgf := "flag provided but not defined: --cheese"
 	checkMessage(c, msgf, "--cheese", "cavitate")
 
-	cmds := []string{"bootstrap-state", "unit", "machine"}
+	cmds := []string{"bootstrap-state", "unit", "machine", "revert-tools", "check-state"}
 	for _, cmd := range cmds {
 		checkMessage(c, msgf, cmd, "--cheese")
 	}
@@ -114,6 +114,9 @@ func (s *MainSuite) TestParseErrors(c *gc.C) {
 	checkMessage(c, msga, "machine",
 		"--machine-id", "42",
 		"toastie")
+	checkMessage(c, msga, "revert-tools",
+		"machine-42",
+		"toastie")
 }
 
 var expectedProviders = []string{
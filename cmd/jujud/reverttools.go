@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+)
+
+const revertToolsDoc = `
+Change the tools used by an agent back to those it ran before its most
+recent upgrade. An agent can be reverted only once per upgrade, and the
+agent will not upgrade to the version it was reverted from again until
+the environment's agent version changes.
+
+Agents other than state servers revert themselves if they fail too
+often shortly after an upgrade. Agents also revert when the environment's
+agent-version is set back to the version they ran before the upgrade.
+This command allows an operator to revert a single agent by hand. The
+agent must be restarted to run the reverted tools.
+
+agent-tag is the tag of the agent, e.g. machine-0 or unit-mysql-0.
+`
+
+// RevertToolsCommand reverts an agent's tools to the version it ran
+// before its most recent upgrade.
+type RevertToolsCommand struct {
+	cmd.CommandBase
+	dataDir string
+	tag     names.Tag
+}
+
+// Info returns usage information for the command.
+func (c *RevertToolsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revert-tools",
+		Args:    "<agent-tag>",
+		Purpose: "revert an agent to the tools it ran before its last upgrade",
+		Doc:     revertToolsDoc,
+	}
+}
+
+func (c *RevertToolsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.dataDir, "data-dir", cmdutil.DataDir, "directory for juju data")
+}
+
+func (c *RevertToolsCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("missing agent-tag")
+	}
+	tag, err := names.ParseTag(args[0])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch tag.(type) {
+	case names.MachineTag, names.UnitTag:
+	default:
+		return errors.Errorf("%q is not a machine or unit tag", args[0])
+	}
+	c.tag = tag
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *RevertToolsCommand) Run(ctx *cmd.Context) error {
+	tools, err := cmdutil.RevertAgentTools(c.dataDir, c.tag.String())
+	if errors.IsNotFound(err) {
+		return errors.Errorf("%s has no previous tools to revert to", c.tag)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "reverted %s to tools %s; restart the agent to run them\n", c.tag, tools.Version)
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/symlink"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	agenttools "github.com/juju/juju/agent/tools"
+	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type RevertToolsSuite struct {
+	testing.BaseSuite
+	dataDir string
+}
+
+var _ = gc.Suite(&RevertToolsSuite{})
+
+func (s *RevertToolsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+}
+
+func (s *RevertToolsSuite) TestArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "missing agent-tag",
+	}, {
+		args:     []string{"foo"},
+		errMatch: `"foo" is not a valid tag`,
+	}, {
+		args:     []string{"service-mysql"},
+		errMatch: `"service-mysql" is not a machine or unit tag`,
+	}, {
+		args:     []string{"machine-0", "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"unit-mysql-0"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&RevertToolsCommand{}, test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *RevertToolsSuite) TestNothingToRevert(c *gc.C) {
+	_, err := testing.RunCommand(c, &RevertToolsCommand{}, "--data-dir", s.dataDir, "machine-0")
+	c.Assert(err, gc.ErrorMatches, "machine-0 has no previous tools to revert to")
+}
+
+func (s *RevertToolsSuite) TestRevert(c *gc.C) {
+	oldVersion := version.MustParseBinary("1.2.3-quantal-amd64")
+	newVersion := version.MustParseBinary("1.2.4-quantal-amd64")
+	for _, vers := range []version.Binary{oldVersion, newVersion} {
+		envtesting.InstallFakeDownloadedTools(c, s.dataDir, vers)
+		_, err := agenttools.ChangeAgentTools(s.dataDir, "machine-0", vers)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	ctx, err := testing.RunCommand(c, &RevertToolsCommand{}, "--data-dir", s.dataDir, "machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "reverted machine-0 to tools 1.2.3-quantal-amd64; restart the agent to run them\n")
+	link, err := symlink.Read(agenttools.ToolsDir(s.dataDir, "machine-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(link, jc.SamePath, agenttools.ToolsDir(s.dataDir, oldVersion.String()))
+}
+
+func (s *RevertToolsSuite) TestRevertDowngradesAgentConfig(c *gc.C) {
+	conf, err := agent.NewAgentConfig(agent.AgentConfigParams{
+		DataDir:           s.dataDir,
+		Tag:               names.NewMachineTag("0"),
+		UpgradedToVersion: version.Current.Number,
+		StateAddresses:    []string{"localhost:1234"},
+		CACert:            testing.CACert,
+		Password:          "sekrit",
+		Environment:       testing.EnvironmentTag,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = conf.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	oldVersion := version.MustParseBinary("1.24.0-quantal-amd64")
+	newVersion := version.MustParseBinary("1.25.0-quantal-amd64")
+	for _, vers := range []version.Binary{oldVersion, newVersion} {
+		envtesting.InstallFakeDownloadedTools(c, s.dataDir, vers)
+		_, err := agenttools.ChangeAgentTools(s.dataDir, "machine-0", vers)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	_, err = testing.RunCommand(c, &RevertToolsCommand{}, "--data-dir", s.dataDir, "machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(agent.ConfigPath(s.dataDir, names.NewMachineTag("0")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasPrefix(string(data), "# format 1.18\n"), jc.IsTrue)
+}
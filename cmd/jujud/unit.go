@@ -17,19 +17,25 @@ import (
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/leadership"
+	apiworkerhealth "github.com/juju/juju/api/workerhealth"
+	"github.com/juju/juju/apiserver/params"
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apiaddressupdater"
+	"github.com/juju/juju/worker/healthcheck"
 	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/proxyupdater"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/uniter"
 	"github.com/juju/juju/worker/upgrader"
+	"github.com/juju/juju/worker/workerhealth"
 )
 
 var agentLogger = loggo.GetLogger("juju.jujud")
@@ -41,6 +47,7 @@ type UnitAgent struct {
 	agentcmd.AgentConf
 	UnitName     string
 	runner       worker.Runner
+	workerHealth *workerhealth.Registry
 	setupLogging func(agent.Config) error
 	logToStdErr  bool
 	ctx          *cmd.Context
@@ -79,6 +86,8 @@ func (a *UnitAgent) Init(args []string) error {
 		return err
 	}
 	a.runner = worker.NewRunner(cmdutil.IsFatal, cmdutil.MoreImportant)
+	a.workerHealth = workerhealth.NewRegistry()
+	a.workerHealth.Register("agent", a.runner)
 
 	if !a.logToStdErr {
 		if err := a.ReadConfig(a.Tag().String()); err != nil {
@@ -117,9 +126,13 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 		logger.Warningf("developer feature flags enabled: %s", flags)
 	}
 
+	if err := cmdutil.RevertToolsIfCrashLooping(logger, agentConfig.DataDir(), a.Tag().String()); err != nil {
+		return err
+	}
 	network.InitializeFromConfig(agentConfig)
 	a.runner.StartWorker("api", a.APIWorkers)
 	err := cmdutil.AgentDone(logger, a.runner.Wait())
+	cmdutil.RecordAgentExit(logger, agentConfig.DataDir(), a.Tag().String(), err)
 	a.tomb.Kill(err)
 	return err
 }
@@ -164,13 +177,19 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 	if err := st.Upgrader().SetVersion(agentConfig.Tag().String(), currentTools.Version); err != nil {
 		return nil, errors.Annotate(err, "cannot set unit agent version")
 	}
+	cmdutil.ReportRevertedTools(logger, agentConfig.DataDir(), agentConfig.Tag().String(), func(info string) error {
+		return a.setAgentStatus(st, params.StatusIdle, info)
+	})
 
-	runner := worker.NewRunner(cmdutil.ConnectionIsFatal(logger, st), cmdutil.MoreImportant)
+	runner := cmdutil.NewRunner(cmdutil.ConnectionIsFatal(logger, st))
+	a.workerHealth.Register("api", runner)
 	// start proxyupdater first to ensure proxy settings are correct
 	runner.StartWorker("proxyupdater", func() (worker.Worker, error) {
 		return proxyupdater.New(st.Environment(), false), nil
 	})
-	runner.StartWorker("upgrader", func() (worker.Worker, error) {
+	// The upgrader keeps the default restart policy, so that an
+	// upgrade can always replace tools that break other workers.
+	runner.StartWorkerWithPolicy("upgrader", worker.DefaultRestartPolicy(), func() (worker.Worker, error) {
 		return upgrader.NewUpgrader(
 			st.Upgrader(),
 			agentConfig,
@@ -186,7 +205,7 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		return uniter.NewUniter(uniterFacade, unitTag, leadership.NewClient(st), dataDir, hookLock), nil
+		return uniter.NewUniter(uniterFacade, unitTag, leadership.NewClient(st), dataDir, hookLock, agentConfig.CACert(), clock.WallClock), nil
 	})
 
 	runner.StartWorker("apiaddressupdater", func() (worker.Worker, error) {
@@ -196,12 +215,40 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 		}
 		return apiaddressupdater.NewAPIAddressUpdater(uniterFacade, a), nil
 	})
+	runner.StartWorker("healthcheck", func() (worker.Worker, error) {
+		uniterFacade, err := st.Uniter()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unit, err := uniterFacade.Unit(unitTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		charmDir := uniter.NewPaths(dataDir, unitTag).State.CharmDir
+		return healthcheck.NewWorker(unit, charmDir), nil
+	})
 	runner.StartWorker("rsyslog", func() (worker.Worker, error) {
 		return cmdutil.NewRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslog.RsyslogModeForwarding)
 	})
+	runner.StartWorker("workerhealth", func() (worker.Worker, error) {
+		facade := apiworkerhealth.NewClient(st)
+		return workerhealth.NewWorker(facade, unitTag, a.workerHealth, workerhealth.DefaultPeriod), nil
+	})
 	return cmdutil.NewCloseWorker(logger, runner, st), nil
 }
 
 func (a *UnitAgent) Tag() names.Tag {
 	return names.NewUnitTag(a.UnitName)
 }
+
+func (a *UnitAgent) setAgentStatus(st *api.State, status params.Status, info string) error {
+	uniterFacade, err := st.Uniter()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	unit, err := uniterFacade.Unit(names.NewUnitTag(a.UnitName))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(unit.SetAgentStatus(status, info, nil))
+}
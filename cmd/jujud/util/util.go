@@ -6,19 +6,24 @@ package util
 import (
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 	"github.com/juju/utils/fslock"
 
 	"github.com/juju/juju/agent"
+	agenttools "github.com/juju/juju/agent/tools"
 	apirsyslog "github.com/juju/juju/api/rsyslog"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
+	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/rsyslog"
@@ -98,7 +103,14 @@ func AgentDone(logger loggo.Logger, err error) error {
 		err = nil
 	}
 	if ug, ok := err.(*upgrader.UpgradeReadyError); ok {
-		if err := ug.ChangeAgentTools(); err != nil {
+		if ug.Revert {
+			if _, err := RevertAgentTools(ug.DataDir, ug.AgentName); err != nil {
+				err = errors.Annotate(err, "cannot revert agent tools")
+				logger.Infof(err.Error())
+				return err
+			}
+			logger.Infof("reverted from %v to %v", ug.OldTools, ug.NewTools)
+		} else if err := ug.ChangeAgentTools(); err != nil {
 			// Return and let the init system deal with the restart.
 			err = errors.Annotate(err, "cannot change agent tools")
 			logger.Infof(err.Error())
@@ -108,6 +120,103 @@ func AgentDone(logger loggo.Logger, err error) error {
 	return err
 }
 
+var (
+	// RollbackGracePeriod is how long after an upgrade an agent's
+	// failures are counted towards reverting its tools.
+	RollbackGracePeriod = 10 * time.Minute
+
+	// RollbackMaxFailures is the number of times an agent may fail
+	// within RollbackGracePeriod of an upgrade before its tools
+	// are reverted.
+	RollbackMaxFailures = 5
+)
+
+// RevertToolsIfCrashLooping records that the given agent has started
+// and, if it has failed too many times since it was upgraded, reverts
+// it to the tools it ran before the upgrade. If the tools are reverted,
+// a FatalError is returned so that the init system restarts the agent
+// using them.
+func RevertToolsIfCrashLooping(logger loggo.Logger, dataDir, agentName string) error {
+	failures, err := agenttools.RecordAgentStart(dataDir, agentName, time.Now(), RollbackGracePeriod)
+	if err != nil {
+		// Failing to track restarts must not stop the agent.
+		logger.Warningf("cannot record agent start: %v", err)
+		return nil
+	}
+	if failures <= RollbackMaxFailures {
+		return nil
+	}
+	tools, err := RevertAgentTools(dataDir, agentName)
+	if err != nil {
+		logger.Errorf("cannot revert agent tools: %v", err)
+		return nil
+	}
+	report := fmt.Sprintf("agent failed %d times within %v of upgrading; reverted to tools %v", failures, RollbackGracePeriod, tools.Version)
+	logger.Errorf("%s", report)
+	if err := agenttools.SetRevertReport(dataDir, agentName, report); err != nil {
+		logger.Warningf("cannot record revert report: %v", err)
+	}
+	return &FatalError{fmt.Sprintf("agent tools reverted to %v", tools.Version)}
+}
+
+// ReportRevertedTools records in state, using setStatus, why the given
+// agent's tools were last reverted by RevertToolsIfCrashLooping, if
+// that has not been recorded yet. The status is expected to be
+// replaced once the agent's workers run, leaving the report in the
+// agent's status history. Failing to record it must not stop the
+// agent, so errors are only logged.
+func ReportRevertedTools(logger loggo.Logger, dataDir, agentName string, setStatus func(info string) error) {
+	if err := agenttools.ReportRevert(dataDir, agentName, setStatus); err != nil {
+		logger.Warningf("cannot report reverted tools: %v", err)
+	}
+}
+
+// RecordAgentExit records that the given agent is exiting with the
+// given error, as returned by AgentDone. Exiting to upgrade, or without
+// an error, does not count as a failure towards reverting its tools.
+func RecordAgentExit(logger loggo.Logger, dataDir, agentName string, err error) {
+	failed := err != nil && !isUpgraded(errors.Cause(err))
+	if err := agenttools.RecordAgentExit(dataDir, agentName, failed); err != nil {
+		logger.Warningf("cannot record agent exit: %v", err)
+	}
+}
+
+// sealedConfigVersion is the first version whose agents read agent
+// configs with sealed secrets.
+var sealedConfigVersion = version.MustParse("1.25-alpha1")
+
+// RevertAgentTools reverts the given agent to the tools it ran before
+// its most recent upgrade. If those tools cannot read the agent's
+// config, it is rewritten in a format they can read.
+func RevertAgentTools(dataDir, agentName string) (*coretools.Tools, error) {
+	rollback, err := agenttools.ReadRollback(dataDir, agentName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if rollback.Previous != (version.Binary{}) && rollback.Previous.Number.Compare(sealedConfigVersion) < 0 {
+		// Downgrade first: if reverting then fails, the current
+		// tools migrate the config back when they next read it.
+		if err := downgradeAgentConfig(dataDir, agentName); err != nil {
+			return nil, errors.Annotate(err, "cannot downgrade agent config")
+		}
+	}
+	return agenttools.RevertAgentTools(dataDir, agentName)
+}
+
+// downgradeAgentConfig rewrites the config of the given agent, if it
+// has one, so that agents older than sealedConfigVersion can read it.
+func downgradeAgentConfig(dataDir, agentName string) error {
+	tag, err := names.ParseTag(agentName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	configPath := agent.ConfigPath(dataDir, tag)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil
+	}
+	return agent.DowngradeConfig(configPath)
+}
+
 // Pinger provides a type that knows how to ping.
 type Pinger interface {
 
@@ -115,6 +224,30 @@ type Pinger interface {
 	Ping() error
 }
 
+// AgentRestartPolicy returns the policy with which agents restart
+// their workers. A worker that keeps failing within a minute of
+// starting is restarted less and less often, down to once every five
+// minutes, and is reported as failed once it has failed that way ten
+// times in succession. Agents never stop restarting a worker, since a
+// worker that fails because of a problem elsewhere, such as an
+// unreachable provider, must come back by itself once it is fixed.
+func AgentRestartPolicy() worker.RestartPolicy {
+	return worker.RestartPolicy{
+		Delay:       worker.RestartDelay,
+		Factor:      2,
+		MaxDelay:    5 * time.Minute,
+		MinRunTime:  time.Minute,
+		MaxFailures: 10,
+	}
+}
+
+// NewRunner returns a runner for the workers an agent runs on a
+// connection, restarting them according to AgentRestartPolicy. The
+// isFatal argument is as for worker.NewRunner.
+func NewRunner(isFatal func(error) bool) worker.PolicyRunner {
+	return worker.NewRunnerWithPolicy(isFatal, MoreImportant, AgentRestartPolicy())
+}
+
 // ConnectionIsFatal returns a function suitable for passing as the
 // isFatal argument to worker.NewRunner, that diagnoses an error as
 // fatal if the connection has failed or if the error is otherwise
@@ -156,6 +289,21 @@ func NewEnsureServerParams(agentConfig agent.Config) (mongo.EnsureServerParams,
 		}
 	}
 
+	// Likewise for the WiredTiger cache size and the journal commit
+	// interval.
+	var cacheSize, journalInterval int
+	for key, value := range map[string]*int{
+		agent.MongoCacheSize:       &cacheSize,
+		agent.MongoJournalInterval: &journalInterval,
+	} {
+		if valueString := agentConfig.Value(key); valueString != "" {
+			var err error
+			if *value, err = strconv.Atoi(valueString); err != nil {
+				return mongo.EnsureServerParams{}, fmt.Errorf("invalid %s: %q", key, valueString)
+			}
+		}
+	}
+
 	// If numa ctl preference is specified in the agent configuration, use that.
 	// Otherwise leave the default false value to indicate to EnsureServer
 	// that numactl should not be used.
@@ -184,6 +332,8 @@ func NewEnsureServerParams(agentConfig agent.Config) (mongo.EnsureServerParams,
 		DataDir:              agentConfig.DataDir(),
 		Namespace:            agentConfig.Value(agent.Namespace),
 		OplogSize:            oplogSize,
+		CacheSize:            cacheSize,
+		JournalInterval:      journalInterval,
 		SetNumaControlPolicy: numaCtlPolicy,
 	}
 	return params, nil
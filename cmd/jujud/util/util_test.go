@@ -4,16 +4,21 @@
 package util
 
 import (
+	"bytes"
 	stderrors "errors"
 	"testing"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	agenttools "github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/apiserver/params"
 	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/upgrader"
 )
@@ -138,8 +143,109 @@ func (*toolSuite) TestIsFatal(c *gc.C) {
 	}
 }
 
+func (s *toolSuite) TestAgentRestartPolicy(c *gc.C) {
+	s.PatchValue(&worker.RestartDelay, time.Second)
+	policy := AgentRestartPolicy()
+	c.Assert(policy.Validate(), jc.ErrorIsNil)
+	c.Assert(policy.Delay, gc.Equals, time.Second)
+	c.Assert(policy.MaxFailures, gc.Not(gc.Equals), 0)
+}
+
+func (*toolSuite) TestNewRunnerReportsFailedWorkers(c *gc.C) {
+	runner := NewRunner(IsFatal)
+	defer worker.Stop(runner)
+	c.Assert(runner.FailedWorkers(), gc.HasLen, 0)
+}
+
 type testPinger func() error
 
 func (f testPinger) Ping() error {
 	return f()
 }
+
+// installUpgradedTools installs two versions of the tools for the
+// unit-mysql-0 agent, and upgrades it from the first to the second.
+func installUpgradedTools(c *gc.C, dataDir string) {
+	for _, vers := range []string{"1.2.3-quantal-amd64", "1.2.4-quantal-amd64"} {
+		data, checksum := coretesting.TarGz(coretesting.NewTarFile("jujud", agenttools.DirPerm, "jujud"))
+		tools := &coretools.Tools{
+			Version: version.MustParseBinary(vers),
+			Size:    int64(len(data)),
+			SHA256:  checksum,
+		}
+		err := agenttools.UnpackTools(dataDir, tools, bytes.NewReader(data))
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = agenttools.ChangeAgentTools(dataDir, "unit-mysql-0", tools.Version)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *toolSuite) TestRevertToolsIfCrashLooping(c *gc.C) {
+	s.PatchValue(&RollbackMaxFailures, 2)
+	dataDir := c.MkDir()
+	installUpgradedTools(c, dataDir)
+
+	// The agent stops twice without recording its exit, then
+	// exits with an error.
+	for i := 0; i < 3; i++ {
+		err := RevertToolsIfCrashLooping(logger, dataDir, "unit-mysql-0")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	RecordAgentExit(logger, dataDir, "unit-mysql-0", errors.New("boom"))
+	err := RevertToolsIfCrashLooping(logger, dataDir, "unit-mysql-0")
+	c.Assert(err, gc.ErrorMatches, "agent tools reverted to 1.2.3-quantal-amd64")
+	c.Assert(IsFatal(err), jc.IsTrue)
+
+	revertedFrom, ok := agenttools.RevertedFrom(dataDir, "unit-mysql-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(revertedFrom, gc.Equals, version.MustParseBinary("1.2.4-quantal-amd64"))
+
+	// Having been reverted, the agent keeps running the old tools.
+	err = RevertToolsIfCrashLooping(logger, dataDir, "unit-mysql-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The revert is reported once the agent can record it.
+	var reports []string
+	setStatus := func(info string) error {
+		reports = append(reports, info)
+		return nil
+	}
+	ReportRevertedTools(logger, dataDir, "unit-mysql-0", setStatus)
+	ReportRevertedTools(logger, dataDir, "unit-mysql-0", setStatus)
+	c.Assert(reports, jc.DeepEquals, []string{
+		"agent failed 3 times within 10m0s of upgrading; reverted to tools 1.2.3-quantal-amd64",
+	})
+}
+
+func (s *toolSuite) TestRevertToolsIgnoresCleanExits(c *gc.C) {
+	s.PatchValue(&RollbackMaxFailures, 2)
+	dataDir := c.MkDir()
+	installUpgradedTools(c, dataDir)
+
+	for _, exitErr := range []error{nil, nil, &upgrader.UpgradeReadyError{}, nil} {
+		err := RevertToolsIfCrashLooping(logger, dataDir, "unit-mysql-0")
+		c.Assert(err, jc.ErrorIsNil)
+		RecordAgentExit(logger, dataDir, "unit-mysql-0", exitErr)
+	}
+	_, ok := agenttools.RevertedFrom(dataDir, "unit-mysql-0")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *toolSuite) TestAgentDoneReverts(c *gc.C) {
+	dataDir := c.MkDir()
+	installUpgradedTools(c, dataDir)
+
+	ugErr := &upgrader.UpgradeReadyError{
+		AgentName: "unit-mysql-0",
+		OldTools:  version.MustParseBinary("1.2.4-quantal-amd64"),
+		NewTools:  version.MustParseBinary("1.2.3-quantal-amd64"),
+		DataDir:   dataDir,
+		Revert:    true,
+	}
+	err := AgentDone(logger, ugErr)
+	c.Assert(err, gc.Equals, ugErr)
+
+	revertedFrom, ok := agenttools.RevertedFrom(dataDir, "unit-mysql-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(revertedFrom, gc.Equals, version.MustParseBinary("1.2.4-quantal-amd64"))
+}
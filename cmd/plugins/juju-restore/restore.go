@@ -7,11 +7,13 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"text/template"
 	"time"
@@ -21,9 +23,9 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils"
-	goyaml "gopkg.in/yaml.v1"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
@@ -133,6 +135,12 @@ var updateBootstrapMachineTemplate = mustParseTemplate(`
 	rm -r /var/log/juju
 
 	tar -C / -xvp -f juju-backup/root.tar
+	{{if .AgentConfig.SecretKey}}
+	# The key sealing the agent config secrets is not in the files
+	# bundle; write it back beside the agent config.
+	install -m 600 /dev/null /var/lib/juju/agents/machine-0/agent.key
+	echo {{.AgentConfig.SecretKey | shquote}} | base64 -d > /var/lib/juju/agents/machine-0/agent.key
+	{{end}}
 	mkdir -p /var/lib/juju/db
 
 	# Prefer jujud-mongodb binaries if available 
@@ -223,11 +231,6 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	if err := c.Log.Start(ctx); err != nil {
 		return err
 	}
-	agentConf, err := extractConfig(c.backupFile)
-	if err != nil {
-		return errors.Annotate(err, "cannot extract configuration from backup file")
-	}
-	progress("extracted credentials from backup file")
 	store, err := configstore.Default()
 	if err != nil {
 		return err
@@ -236,6 +239,14 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	// The agent config secrets key is stored in the backup sealed
+	// with the environment's CA private key.
+	caKey, _ := cfg.CAPrivateKey()
+	agentConf, err := extractConfig(c.backupFile, caKey)
+	if err != nil {
+		return errors.Annotate(err, "cannot extract configuration from backup file")
+	}
+	progress("extracted credentials from backup file")
 	env, err := rebootstrap(cfg, ctx, c.Constraints)
 	if err != nil {
 		return errors.Annotate(err, "cannot re-bootstrap environment")
@@ -381,6 +392,10 @@ type agentConfig struct {
 	Credentials credentials
 	ApiPort     string
 	StatePort   string
+
+	// SecretKey holds the base64-encoded key sealing the secrets of
+	// the agent config, if it has one.
+	SecretKey string
 }
 
 func extractMachineID(archive *os.File) (string, error) {
@@ -407,85 +422,114 @@ func extractMachineID(archive *os.File) (string, error) {
 	return meta.Origin.Machine, nil
 }
 
-func extractConfig(backupFile string) (agentConfig, error) {
+func extractConfig(backupFile, keyPassphrase string) (agentConfig, error) {
 	f, err := os.Open(backupFile)
 	if err != nil {
 		return agentConfig{}, err
 	}
-	defer f.Close()
-
 	// Extract the machine tag.
 	machineID, err := extractMachineID(f)
-	if err != nil {
-		return agentConfig{}, err
-	}
-	_, err = f.Seek(0, os.SEEK_SET)
+	f.Close()
 	if err != nil {
 		return agentConfig{}, err
 	}
 	tag := names.NewMachineTag(machineID)
 
-	// Extract the config file.
-	gzr, err := gzip.NewReader(f)
+	// The agent config is read through the agent package, which needs
+	// it in a directory along with the key sealing its secrets.
+	dir, err := ioutil.TempDir("", "juju-restore")
 	if err != nil {
-		return agentConfig{}, errors.Annotate(err, fmt.Sprintf("cannot unzip %q", backupFile))
-	}
-	defer gzr.Close()
-	outerTar, err := findFileInTar(gzr, "juju-backup/root.tar")
-	if err != nil {
-		return agentConfig{}, err
+		return agentConfig{}, errors.Trace(err)
 	}
+	defer os.RemoveAll(dir)
+
 	// TODO(ericsnowcurrently) This should come from an authoritative source.
 	const confFilename = "var/lib/juju/agents/%s/agent.conf"
-	agentConf, err := findFileInTar(outerTar, fmt.Sprintf(confFilename, tag))
-	if err != nil {
-		return agentConfig{}, err
-	}
-
-	// Extract the config data.
-	data, err := ioutil.ReadAll(agentConf)
+	data, err := readArchiveFile(backupFile, fmt.Sprintf(confFilename, tag), true)
 	if err != nil {
 		return agentConfig{}, errors.Annotate(err, "failed to read agent config file")
 	}
-	var conf interface{}
-	if err := goyaml.Unmarshal(data, &conf); err != nil {
-		return agentConfig{}, errors.Annotate(err, "cannot unmarshal agent config file")
+	confPath := filepath.Join(dir, "agent.conf")
+	if err := ioutil.WriteFile(confPath, data, 0600); err != nil {
+		return agentConfig{}, errors.Trace(err)
 	}
-	m, ok := conf.(map[interface{}]interface{})
-	if !ok {
-		return agentConfig{}, fmt.Errorf("config file unmarshalled to %T not %T", conf, m)
+
+	// Archives made before agent config secrets were sealed hold no
+	// key, and their agent configs need none.
+	var secretKey string
+	keyFile := path.Join(backups.NewCanonicalArchivePaths().AgentKeysDir, tag.String())
+	sealedKey, err := readArchiveFile(backupFile, keyFile, false)
+	if err == nil {
+		if err := agent.ImportSecretKey(dir, keyPassphrase, string(sealedKey)); err != nil {
+			return agentConfig{}, errors.Trace(err)
+		}
+		key, err := ioutil.ReadFile(filepath.Join(dir, agent.SecretKeyFilename))
+		if err != nil {
+			return agentConfig{}, errors.Trace(err)
+		}
+		secretKey = base64.StdEncoding.EncodeToString(key)
+	} else if !errors.IsNotFound(err) {
+		return agentConfig{}, errors.Annotate(err, "failed to read agent config secrets key")
+	}
+
+	conf, err := agent.ReadConfig(confPath)
+	if err != nil {
+		return agentConfig{}, errors.Annotate(err, "cannot read agent config file")
 	}
-	password, ok := m["statepassword"].(string)
-	if !ok || password == "" {
+	mongoInfo, ok := conf.MongoInfo()
+	if !ok || mongoInfo.Password == "" {
 		return agentConfig{}, fmt.Errorf("agent password not found in configuration")
 	}
-	oldPassword, ok := m["oldpassword"].(string)
-	if !ok || oldPassword == "" {
+	if conf.OldPassword() == "" {
 		return agentConfig{}, fmt.Errorf("agent old password not found in configuration")
 	}
-	statePortNum, ok := m["stateport"].(int)
-	if !ok {
-		return agentConfig{}, fmt.Errorf("state port not found in configuration")
-	}
-
-	statePort := strconv.Itoa(statePortNum)
-	apiPortNum, ok := m["apiport"].(int)
+	servingInfo, ok := conf.StateServingInfo()
 	if !ok {
-		return agentConfig{}, fmt.Errorf("api port not found in configuration")
+		return agentConfig{}, fmt.Errorf("state serving info not found in configuration")
 	}
-	apiPort := strconv.Itoa(apiPortNum)
-
 	return agentConfig{
 		Credentials: credentials{
 			Tag:         "machine-0",
-			Password:    password,
-			OldPassword: oldPassword,
+			Password:    mongoInfo.Password,
+			OldPassword: conf.OldPassword(),
 		},
-		StatePort: statePort,
-		ApiPort:   apiPort,
+		StatePort: strconv.Itoa(servingInfo.StatePort),
+		ApiPort:   strconv.Itoa(servingInfo.APIPort),
+		SecretKey: secretKey,
 	}, nil
 }
 
+// readArchiveFile returns the contents of the named file in the given
+// backup archive or, if bundled is true, in the archive's files bundle.
+func readArchiveFile(backupFile, name string, bundled bool) ([]byte, error) {
+	f, err := os.Open(backupFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Annotate(err, fmt.Sprintf("cannot unzip %q", backupFile))
+	}
+	defer gzr.Close()
+	if !bundled {
+		r, err := findFileInTar(gzr, name)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadAll(r)
+	}
+	outerTar, err := findFileInTar(gzr, backups.NewCanonicalArchivePaths().FilesBundle)
+	if err != nil {
+		return nil, err
+	}
+	r, err := findFileInTar(outerTar, name)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
 func findFileInTar(r io.Reader, name string) (io.Reader, error) {
 	tarr := tar.NewReader(r)
 	for {
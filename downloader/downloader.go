@@ -25,11 +25,20 @@ type Status struct {
 	Err error
 }
 
+// Options holds optional settings for a download.
+type Options struct {
+	// MaxResumes is the number of times an interrupted download
+	// is resumed, using a ranged request for the remaining data,
+	// before it fails.
+	MaxResumes int
+}
+
 // Download can download a file from the network.
 type Download struct {
-	tomb                 tomb.Tomb
-	done                 chan Status
-	hostnameVerification utils.SSLHostnameVerification
+	tomb   tomb.Tomb
+	done   chan Status
+	client *http.Client
+	opts   Options
 }
 
 // New returns a new Download instance downloading from the given URL
@@ -37,9 +46,24 @@ type Download struct {
 // os.TempDir(). If disableSSLHostnameVerification is true then a non-
 // validating http client will be used.
 func New(url, dir string, hostnameVerification utils.SSLHostnameVerification) *Download {
+	return NewWithClient(url, dir, utils.GetHTTPClient(hostnameVerification))
+}
+
+// NewWithClient returns a new Download instance downloading from the
+// given URL to the given directory using the given HTTP client. If dir
+// is empty, it defaults to os.TempDir().
+func NewWithClient(url, dir string, client *http.Client) *Download {
+	return NewWithOptions(url, dir, client, Options{})
+}
+
+// NewWithOptions returns a new Download instance downloading from the
+// given URL to the given directory using the given HTTP client and
+// options. If dir is empty, it defaults to os.TempDir().
+func NewWithOptions(url, dir string, client *http.Client, opts Options) *Download {
 	d := &Download{
-		done:                 make(chan Status),
-		hostnameVerification: hostnameVerification,
+		done:   make(chan Status),
+		client: client,
+		opts:   opts,
 	}
 	go d.run(url, dir)
 	return d
@@ -63,7 +87,7 @@ func (d *Download) run(url, dir string) {
 	// TODO(dimitern) 2013-10-03 bug #1234715
 	// Add a testing HTTPS storage to verify the
 	// disableSSLHostnameVerification behavior here.
-	file, err := download(url, dir, d.hostnameVerification)
+	file, err := download(url, dir, d.client, d.opts)
 	if err != nil {
 		err = fmt.Errorf("cannot download %q: %v", url, err)
 	}
@@ -78,7 +102,7 @@ func (d *Download) run(url, dir string) {
 	}
 }
 
-func download(url, dir string, hostnameVerification utils.SSLHostnameVerification) (file *os.File, err error) {
+func download(url, dir string, client *http.Client, opts Options) (file *os.File, err error) {
 	if dir == "" {
 		dir = os.TempDir()
 	}
@@ -92,23 +116,66 @@ func download(url, dir string, hostnameVerification utils.SSLHostnameVerificatio
 		}
 	}()
 	// TODO(rog) make the download operation interruptible.
-	client := utils.GetHTTPClient(hostnameVerification)
-	resp, err := client.Get(url)
-	if err != nil {
+	var offset int64
+	for resumes := 0; ; resumes++ {
+		offset, err = fetch(url, tempFile, offset, client)
+		if err == nil {
+			break
+		}
+		if _, ok := err.(*badResponseError); ok || resumes >= opts.MaxResumes {
+			return nil, err
+		}
+		logger.Infof("download of %q interrupted after %d bytes, resuming: %v", url, offset, err)
+	}
+	if _, err := tempFile.Seek(0, 0); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad http response: %v", resp.Status)
+	return tempFile, nil
+}
+
+// badResponseError is returned by fetch when the server responds
+// with an unexpected status; such downloads are not resumed.
+type badResponseError struct {
+	status string
+}
+
+func (e *badResponseError) Error() string {
+	return fmt.Sprintf("bad http response: %v", e.status)
+}
+
+// fetch writes the content at the given URL to file, starting at
+// the given offset, and returns the offset reached. If offset is
+// non-zero, only the remaining content is requested; if the server
+// does not support ranged requests, the whole content is fetched
+// again.
+func fetch(url string, file *os.File, offset int64, client *http.Client) (int64, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return offset, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
-	_, err = io.Copy(tempFile, resp.Body)
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return offset, err
 	}
-	if _, err := tempFile.Seek(0, 0); err != nil {
-		return nil, err
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		offset = 0
+		if err := file.Truncate(0); err != nil {
+			return offset, err
+		}
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	default:
+		return offset, &badResponseError{resp.Status}
 	}
-	return tempFile, nil
+	if _, err := file.Seek(offset, 0); err != nil {
+		return offset, err
+	}
+	n, err := io.Copy(file, resp.Body)
+	return offset + n, err
 }
 
 func cleanTempFile(f *os.File) {
@@ -4,7 +4,11 @@
 package downloader_test
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	stdtesting "testing"
@@ -73,6 +77,17 @@ func (s *suite) TestDownloadWithDisablingSSLHostnameVerification(c *gc.C) {
 	s.testDownload(c, utils.NoVerifySSLHostnames)
 }
 
+func (s *suite) TestDownloadWithClient(c *gc.C) {
+	gitjujutesting.Server.Response(200, nil, []byte("archive"))
+	d := downloader.NewWithClient(s.URL("/archive.tgz"), c.MkDir(), &http.Client{})
+	status := <-d.Done()
+	c.Assert(status.Err, gc.IsNil)
+	c.Assert(status.File, gc.NotNil)
+	defer os.Remove(status.File.Name())
+	defer status.File.Close()
+	assertFileContents(c, status.File, "archive")
+}
+
 func (s *suite) TestDownloadError(c *gc.C) {
 	gitjujutesting.Server.Response(404, nil, nil)
 	d := downloader.New(s.URL("/archive.tgz"), c.MkDir(), utils.VerifySSLHostnames)
@@ -81,6 +96,56 @@ func (s *suite) TestDownloadError(c *gc.C) {
 	c.Assert(status.Err, gc.ErrorMatches, `cannot download ".*": bad http response: 404 Not Found`)
 }
 
+// interruptingHandler serves content, honouring ranged requests, but
+// cuts short the first few responses, as set by interruptions.
+type interruptingHandler struct {
+	content       string
+	interruptions int
+	ranges        []string
+}
+
+func (h *interruptingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.ranges = append(h.ranges, req.Header.Get("Range"))
+	if h.interruptions == 0 {
+		http.ServeContent(w, req, "archive.tgz", time.Time{}, bytes.NewReader([]byte(h.content)))
+		return
+	}
+	h.interruptions--
+	w.Header().Set("Content-Length", fmt.Sprint(len(h.content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(h.content[:len(h.content)/2]))
+}
+
+func (s *suite) TestDownloadResumes(c *gc.C) {
+	handler := &interruptingHandler{content: "0123456789", interruptions: 1}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	d := downloader.NewWithOptions(server.URL, c.MkDir(), &http.Client{}, downloader.Options{
+		MaxResumes: 1,
+	})
+	status := <-d.Done()
+	c.Assert(status.Err, jc.ErrorIsNil)
+	defer os.Remove(status.File.Name())
+	defer status.File.Close()
+	assertFileContents(c, status.File, "0123456789")
+	c.Assert(handler.ranges, jc.DeepEquals, []string{"", "bytes=5-"})
+}
+
+func (s *suite) TestDownloadTooManyResumes(c *gc.C) {
+	handler := &interruptingHandler{content: "0123456789", interruptions: 2}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	d := downloader.NewWithOptions(server.URL, c.MkDir(), &http.Client{}, downloader.Options{
+		MaxResumes: 0,
+	})
+	status := <-d.Done()
+	c.Assert(status.File, gc.IsNil)
+	c.Assert(status.Err, gc.ErrorMatches, `cannot download ".*": unexpected EOF`)
+	c.Assert(handler.ranges, gc.HasLen, 1)
+}
+
 func (s *suite) TestStopDownload(c *gc.C) {
 	tmp := c.MkDir()
 	d := downloader.New(s.URL("/x.tgz"), tmp, utils.VerifySSLHostnames)
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/network"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/utils/ssh"
 	"github.com/juju/juju/version"
 )
@@ -51,12 +52,32 @@ type BootstrapParams struct {
 	// MetadataDir is an optional path to a local directory containing
 	// tools and/or image metadata.
 	MetadataDir string
+
+	// Offline reports whether bootstrap should avoid all access to
+	// public networks, for clouds in isolated data centers. Tools and
+	// image metadata are then only read from MetadataDir and the
+	// locations configured in the environment, and, unless an apt
+	// mirror is configured, package updates and upgrades are disabled
+	// in the environment config. Offline is not itself recorded;
+	// after bootstrap, the environment only stays offline if its
+	// config names metadata and package locations it can reach.
+	Offline bool
+
+	// Progress, if not nil, is called as each stage of the
+	// bootstrap completes, for live progress display and timing.
+	Progress ProgressFunc
+
+	// Clock, if not nil, is used to time the stages reported to
+	// Progress. The wall clock is used otherwise.
+	Clock clock.Clock
 }
 
 // Bootstrap bootstraps the given environment. The supplied constraints are
 // used to provision the instance, and are also set within the bootstrapped
 // environment.
 func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args BootstrapParams) error {
+	progress := newProgressReporter(args.Progress, args.Clock)
+	ctx = &progressContext{ctx, progress}
 	cfg := environ.Config()
 	network.InitializeFromConfig(cfg)
 	if secret := cfg.AdminSecret(); secret == "" {
@@ -80,6 +101,9 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	// Set default tools metadata source, add image metadata source,
 	// then verify constraints. Providers may rely on image metadata
 	// for constraint validation.
+	if args.Offline && args.MetadataDir == "" {
+		return errors.New("offline bootstrap requires a metadata directory")
+	}
 	var imageMetadata []*imagemetadata.ImageMetadata
 	if args.MetadataDir != "" {
 		var err error
@@ -88,6 +112,11 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 			return err
 		}
 	}
+	if args.Offline {
+		logger.Infof("offline bootstrap: not using public image metadata")
+		environs.SetDefaultImageSourceDisabled(cfg.Name(), true)
+		defer environs.SetDefaultImageSourceDisabled(cfg.Name(), false)
+	}
 	if err := validateConstraints(environ, args.Constraints); err != nil {
 		return err
 	}
@@ -110,9 +139,15 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	// agent-version set anyway, to appease FinishInstanceConfig.
 	// In the latter case, setBootstrapTools will later set
 	// agent-version to the correct thing.
-	if cfg, err = cfg.Apply(map[string]interface{}{
+	attrs := map[string]interface{}{
 		"agent-version": version.Current.Number.String(),
-	}); err != nil {
+	}
+	if args.Offline && cfg.AptMirror() == "" {
+		// Without a mirror, the public archive is unreachable.
+		attrs["enable-os-refresh-update"] = false
+		attrs["enable-os-upgrade"] = false
+	}
+	if cfg, err = cfg.Apply(attrs); err != nil {
 		return err
 	}
 	if err = environ.SetConfig(cfg); err != nil {
@@ -128,6 +163,7 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	if err != nil {
 		return err
 	}
+	progress.done(StageInstanceStarted, "started %s/%s instance", series, arch)
 
 	matchingTools, err := availableTools.Match(coretools.Filter{
 		Arch:   arch,
@@ -154,7 +190,9 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		selectedTools.URL = fmt.Sprintf("file://%s", filename)
 		selectedTools.Size = builtTools.Size
 		selectedTools.SHA256 = builtTools.Sha256Hash
+		progress.done(StageToolsBuilt, "built tools %s", selectedTools.Version)
 	}
+	progress.done(StageToolsSelected, "selected tools %s", selectedTools.Version)
 
 	ctx.Infof("Installing Juju agent on bootstrap instance")
 	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(args.Constraints, series)
@@ -166,6 +204,7 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	if err := finalizer(ctx, instanceConfig); err != nil {
 		return err
 	}
+	progress.done(StageStateInitialized, "installed agent on bootstrap instance")
 	ctx.Infof("Bootstrap complete")
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"runtime"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -27,6 +28,7 @@ import (
 	"github.com/juju/juju/provider/dummy"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/tools"
+	clocktesting "github.com/juju/juju/utils/clock/testing"
 	"github.com/juju/juju/version"
 )
 
@@ -99,6 +101,54 @@ func (s *bootstrapSuite) TestBootstrapEmptyConstraints(c *gc.C) {
 	c.Assert(env.args, gc.DeepEquals, environs.BootstrapParams{})
 }
 
+func (s *bootstrapSuite) TestBootstrapReportsProgress(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	var events []bootstrap.ProgressEvent
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		Progress: func(event bootstrap.ProgressEvent) {
+			events = append(events, event)
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var stages []bootstrap.Stage
+	for i, event := range events {
+		stages = append(stages, event.Stage)
+		c.Check(event.Time.IsZero(), jc.IsFalse)
+		c.Check(event.Message, gc.Not(gc.Equals), "")
+		if i > 0 {
+			c.Check(event.Elapsed >= events[i-1].Elapsed, jc.IsTrue)
+		}
+	}
+	c.Assert(stages, gc.DeepEquals, []bootstrap.Stage{
+		bootstrap.StageImageSelected,
+		bootstrap.StageInstanceStarted,
+		bootstrap.StageToolsSelected,
+		bootstrap.StageToolsUploaded,
+		bootstrap.StageStateInitialized,
+	})
+	c.Assert(events[0].Message, gc.Equals, "selected image image-id")
+}
+
+func (s *bootstrapSuite) TestBootstrapProgressClock(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	now := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	var events []bootstrap.ProgressEvent
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		Progress: func(event bootstrap.ProgressEvent) {
+			events = append(events, event)
+		},
+		Clock: clocktesting.NewClock(now),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.Not(gc.HasLen), 0)
+	for _, event := range events {
+		c.Check(event.Time, gc.Equals, now)
+		c.Check(event.Elapsed, gc.Equals, time.Duration(0))
+	}
+}
+
 func (s *bootstrapSuite) TestBootstrapSpecifiedConstraints(c *gc.C) {
 	env := newEnviron("foo", useDefaultKeys, nil)
 	s.setDummyStorage(c, env)
@@ -284,6 +334,69 @@ func (s *bootstrapSuite) TestBootstrapMetadataImagesMissing(c *gc.C) {
 	c.Assert(datasources[0].Description(), gc.Equals, "default cloud images")
 }
 
+func (s *bootstrapSuite) TestBootstrapOffline(c *gc.C) {
+	environs.UnregisterImageDataSourceFunc("bootstrap metadata")
+
+	metadataDir, _ := createImageMetadata(c)
+	stor, err := filestorage.NewFileStorageWriter(metadataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	envtesting.UploadFakeTools(c, stor, "released", "released")
+
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	err = bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		MetadataDir: metadataDir,
+		Offline:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.bootstrapCount, gc.Equals, 1)
+
+	// Only the local image metadata was used during bootstrap.
+	c.Assert(env.imageSources, gc.HasLen, 1)
+	c.Assert(env.imageSources[0].Description(), gc.Equals, "bootstrap metadata")
+
+	// The public image metadata is used again afterwards.
+	datasources, err := environs.ImageMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(datasources, gc.HasLen, 2)
+	c.Assert(datasources[1].Description(), gc.Equals, "default cloud images")
+
+	// Packages are not updated from the public archive.
+	c.Assert(env.cfg.EnableOSRefreshUpdate(), jc.IsFalse)
+	c.Assert(env.cfg.EnableOSUpgrade(), jc.IsFalse)
+}
+
+func (s *bootstrapSuite) TestBootstrapOfflineAptMirror(c *gc.C) {
+	environs.UnregisterImageDataSourceFunc("bootstrap metadata")
+
+	metadataDir, _ := createImageMetadata(c)
+	stor, err := filestorage.NewFileStorageWriter(metadataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	envtesting.UploadFakeTools(c, stor, "released", "released")
+
+	env := newEnviron("foo", useDefaultKeys, map[string]interface{}{
+		"apt-mirror": "http://mirror.internal/ubuntu",
+	})
+	s.setDummyStorage(c, env)
+	err = bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		MetadataDir: metadataDir,
+		Offline:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.cfg.EnableOSRefreshUpdate(), jc.IsTrue)
+	c.Assert(env.cfg.EnableOSUpgrade(), jc.IsTrue)
+}
+
+func (s *bootstrapSuite) TestBootstrapOfflineNeedsMetadataDir(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		Offline: true,
+	})
+	c.Assert(err, gc.ErrorMatches, "offline bootstrap requires a metadata directory")
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
 type bootstrapEnviron struct {
 	cfg              *config.Config
 	environs.Environ // stub out all methods we don't care about.
@@ -294,6 +407,7 @@ type bootstrapEnviron struct {
 	supportedArchitecturesCount int
 	args                        environs.BootstrapParams
 	instanceConfig              *instancecfg.InstanceConfig
+	imageSources                []simplestreams.DataSource
 	storage                     storage.Storage
 }
 
@@ -328,9 +442,20 @@ func (s *bootstrapSuite) setDummyStorage(c *gc.C, env *bootstrapEnviron) {
 func (e *bootstrapEnviron) Bootstrap(ctx environs.BootstrapContext, args environs.BootstrapParams) (arch, series string, _ environs.BootstrapFinalizer, _ error) {
 	e.bootstrapCount++
 	e.args = args
-	finalizer := func(_ environs.BootstrapContext, icfg *instancecfg.InstanceConfig) error {
+	sources, err := environs.ImageMetadataSources(e)
+	if err != nil {
+		return "", "", nil, err
+	}
+	e.imageSources = sources
+	if progress, ok := ctx.(environs.BootstrapProgress); ok {
+		progress.ImageSelected("image-id")
+	}
+	finalizer := func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig) error {
 		e.finalizerCount++
 		e.instanceConfig = icfg
+		if progress, ok := ctx.(environs.BootstrapProgress); ok {
+			progress.ToolsUploaded()
+		}
 		return nil
 	}
 	return version.Current.Arch, version.Current.Series, finalizer, nil
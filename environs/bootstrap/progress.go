@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bootstrap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/utils/clock"
+)
+
+// Stage identifies a step of the bootstrap process.
+type Stage string
+
+const (
+	// StageImageSelected is reported once the provider has chosen
+	// the image for the bootstrap instance. It is only reported by
+	// providers that choose images themselves.
+	StageImageSelected Stage = "image-selected"
+
+	// StageInstanceStarted is reported once the provider has started
+	// the bootstrap instance.
+	StageInstanceStarted Stage = "instance-started"
+
+	// StageToolsSelected is reported once the tools to install on
+	// the bootstrap instance have been chosen.
+	StageToolsSelected Stage = "tools-selected"
+
+	// StageToolsBuilt is reported once local tools have been built
+	// for upload to the bootstrap instance. It is only reported
+	// when no prepackaged tools are used.
+	StageToolsBuilt Stage = "tools-built"
+
+	// StageToolsUploaded is reported once tools built locally have
+	// been copied to the bootstrap instance. It is only reported by
+	// providers that copy them over SSH.
+	StageToolsUploaded Stage = "tools-uploaded"
+
+	// StageStateInitialized is reported once the agent has been
+	// installed on the bootstrap instance and has initialized
+	// the environment's state.
+	StageStateInitialized Stage = "state-initialized"
+)
+
+// ProgressEvent records the completion of a bootstrap stage.
+type ProgressEvent struct {
+	// Stage identifies the stage that completed.
+	Stage Stage
+
+	// Time holds when the stage completed.
+	Time time.Time
+
+	// Elapsed holds the time taken since bootstrap started.
+	Elapsed time.Duration
+
+	// Message holds a human readable description of the outcome
+	// of the stage, e.g. the series and architecture of the
+	// instance that was started.
+	Message string
+}
+
+// String returns a description of the event suitable for display.
+func (e ProgressEvent) String() string {
+	return fmt.Sprintf("%s after %v: %s", e.Stage, e.Elapsed, e.Message)
+}
+
+// ProgressFunc is called by Bootstrap as each stage completes.
+// It is called synchronously, so it should not block.
+type ProgressFunc func(ProgressEvent)
+
+// progressReporter sends progress events to a ProgressFunc,
+// timing them from its creation with the given clock.
+type progressReporter struct {
+	report ProgressFunc
+	clock  clock.Clock
+	start  time.Time
+}
+
+func newProgressReporter(report ProgressFunc, clk clock.Clock) *progressReporter {
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	return &progressReporter{
+		report: report,
+		clock:  clk,
+		start:  clk.Now(),
+	}
+}
+
+// done reports the completion of the given stage, if there is
+// anything to report it to.
+func (p *progressReporter) done(stage Stage, format string, args ...interface{}) {
+	if p.report == nil {
+		return
+	}
+	now := p.clock.Now()
+	p.report(ProgressEvent{
+		Stage:   stage,
+		Time:    now,
+		Elapsed: now.Sub(p.start),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// progressContext is the bootstrap context passed to providers, through
+// which they report the stages only they can observe.
+type progressContext struct {
+	environs.BootstrapContext
+	progress *progressReporter
+}
+
+var _ environs.BootstrapProgress = (*progressContext)(nil)
+
+// ImageSelected is part of the environs.BootstrapProgress interface.
+func (ctx *progressContext) ImageSelected(imageId string) {
+	ctx.progress.done(StageImageSelected, "selected image %s", imageId)
+}
+
+// ToolsUploaded is part of the environs.BootstrapProgress interface.
+func (ctx *progressContext) ToolsUploaded() {
+	ctx.progress.done(StageToolsUploaded, "uploaded tools")
+}
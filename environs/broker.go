@@ -49,6 +49,19 @@ type StartInstanceParams struct {
 	// NetworkInfo is an optional list of network interface details,
 	// necessary to configure on the instance.
 	NetworkInfo []network.InterfaceInfo
+
+	// InstanceName, if non-empty, is the display name that providers
+	// which support naming instances should give the new instance.
+	// Providers that identify their instances by name ignore it.
+	InstanceName string
+
+	// InstanceTags holds the tags that providers which support
+	// tagging instances should apply to the new instance.
+	InstanceTags map[string]string
+
+	// ImageSelected, if not nil, is called with the id of the image
+	// chosen for the new instance, by providers that choose one.
+	ImageSelected func(imageId string)
 }
 
 // StartInstanceResult holds the result of an
@@ -100,3 +113,21 @@ type InstanceBroker interface {
 	// correct network configuration.
 	MaintainInstance(args StartInstanceParams) error
 }
+
+// InstanceBatcher is implemented by brokers that can start several
+// instances with a single request to the provider.
+//
+// Each instance's configuration holds the credentials of its machine
+// agent, so it must only ever be readable by that instance. Brokers
+// must not start instances together when the provider would hand all
+// of them the same user data.
+type InstanceBatcher interface {
+	InstanceBroker
+
+	// StartInstances starts an instance for each of the given params,
+	// as StartInstance would, returning the result or the error for
+	// each in the same order as the params. Brokers may start some or
+	// all of the instances separately if their params do not allow
+	// them to be started together.
+	StartInstances(args []StartInstanceParams) ([]*StartInstanceResult, []error)
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// CloudInitUserDataKey stores additional cloud-init directives,
+// in YAML format, that are applied to every provisioned machine.
+// Windows machines are not configured by cloud-init directives,
+// and ignore them.
+const CloudInitUserDataKey = "cloudinit-userdata"
+
+// CloudInitUserData holds the operator-supplied cloud-init directives
+// that are appended to the user data of every provisioned machine.
+type CloudInitUserData struct {
+	// Packages holds the names of additional packages to install.
+	Packages []string
+
+	// RunCmd holds additional commands to run once the machine
+	// has been configured by juju.
+	RunCmd []string
+
+	// WriteFiles holds additional files to write to the machine.
+	WriteFiles []CloudInitFile
+}
+
+// CloudInitFile describes a file to be written by cloud-init.
+type CloudInitFile struct {
+	// Path holds the absolute path of the file.
+	Path string
+
+	// Content holds the content of the file.
+	Content string
+
+	// Permissions holds the file mode of the file.
+	Permissions uint
+}
+
+var cloudInitFileChecker = schema.FieldMap(
+	schema.Fields{
+		"path":        schema.String(),
+		"content":     schema.String(),
+		"permissions": schema.String(),
+	},
+	schema.Defaults{
+		"content":     "",
+		"permissions": "0644",
+	},
+)
+
+var cloudInitUserDataChecker = schema.FieldMap(
+	schema.Fields{
+		"packages":    schema.List(schema.String()),
+		"runcmd":      schema.List(schema.String()),
+		"write_files": schema.List(cloudInitFileChecker),
+	},
+	schema.Defaults{
+		"packages":    schema.Omit,
+		"runcmd":      schema.Omit,
+		"write_files": schema.Omit,
+	},
+)
+
+// parseCloudInitUserData parses the YAML value of the
+// cloudinit-userdata attribute. Only the packages, runcmd and
+// write_files directives are supported.
+func parseCloudInitUserData(data string) (*CloudInitUserData, error) {
+	var raw map[string]interface{}
+	if err := goyaml.Unmarshal([]byte(data), &raw); err != nil {
+		return nil, errors.Annotate(err, "invalid YAML")
+	}
+	for key := range raw {
+		switch key {
+		case "packages", "runcmd", "write_files":
+		default:
+			return nil, errors.NotSupportedf("directive %q", key)
+		}
+	}
+	coerced, err := cloudInitUserDataChecker.Coerce(raw, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attrs := coerced.(map[string]interface{})
+	result := &CloudInitUserData{}
+	if packages, ok := attrs["packages"].([]interface{}); ok {
+		for _, p := range packages {
+			result.Packages = append(result.Packages, p.(string))
+		}
+	}
+	if runcmd, ok := attrs["runcmd"].([]interface{}); ok {
+		for _, cmd := range runcmd {
+			result.RunCmd = append(result.RunCmd, cmd.(string))
+		}
+	}
+	if files, ok := attrs["write_files"].([]interface{}); ok {
+		for i, f := range files {
+			file, err := cloudInitFile(f.(map[string]interface{}))
+			if err != nil {
+				return nil, errors.Annotatef(err, "write_files[%d]", i)
+			}
+			result.WriteFiles = append(result.WriteFiles, file)
+		}
+	}
+	return result, nil
+}
+
+func cloudInitFile(attrs map[string]interface{}) (CloudInitFile, error) {
+	perms, err := strconv.ParseUint(attrs["permissions"].(string), 8, 32)
+	if err != nil {
+		return CloudInitFile{}, fmt.Errorf("invalid permissions %q", attrs["permissions"])
+	}
+	return CloudInitFile{
+		Path:        attrs["path"].(string),
+		Content:     attrs["content"].(string),
+		Permissions: uint(perms),
+	}, nil
+}
@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,6 +20,7 @@ import (
 	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/charmrepo"
+	"gopkg.in/macaroon-bakery.v0/bakery"
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/juju/osenv"
@@ -42,6 +44,25 @@ const (
 	// instance security groups.
 	FwNone = "none"
 
+	// AssignClean requests that new units be assigned to existing
+	// machines that have never hosted a unit, with new machines
+	// launched only when there are none.
+	AssignClean = "clean"
+
+	// AssignCleanEmpty requests that new units be assigned to existing
+	// machines that have never hosted a unit and host no containers,
+	// with new machines launched only when there are none.
+	AssignCleanEmpty = "clean-empty"
+
+	// AssignNew requests that each new unit be assigned to a newly
+	// launched machine.
+	AssignNew = "new"
+
+	// AssignLeastLoaded requests that new units be assigned to the
+	// existing machines hosting the fewest units, with new machines
+	// launched only when there are none.
+	AssignLeastLoaded = "least-loaded"
+
 	// DefaultStatePort is the default port the state server is listening on.
 	DefaultStatePort int = 37017
 
@@ -88,6 +109,11 @@ const (
 	// Only prevent all-changes from running
 	// if user specifically requests it. Otherwise, let them run.
 	DefaultPreventAllChanges = false
+
+	// DefaultAgentPresenceTimeout is how long an agent may go
+	// without contacting the state server before it is reported
+	// as lost.
+	DefaultAgentPresenceTimeout = time.Minute
 )
 
 // TODO(katco-): Please grow this over time.
@@ -150,11 +176,38 @@ const (
 	// The default block storage source.
 	StorageDefaultBlockSourceKey = "storage-default-block-source"
 
+	// UnitAssignmentPolicyKey stores the key for this setting.
+	UnitAssignmentPolicyKey = "unit-assignment-policy"
+
+	// MetricsCollectorURLKey stores the key for this setting.
+	MetricsCollectorURLKey = "metrics-collector-url"
+
+	// AgentPresenceTimeoutKey stores the key for this setting.
+	AgentPresenceTimeoutKey = "agent-presence-timeout"
+
+	// InstanceStopApprovalURLKey stores the URL of the service that
+	// must approve the provisioner stopping instances.
+	InstanceStopApprovalURLKey = "instance-stop-approval-url"
+
+	// IdentityURLKey stores the URL of the external identity service
+	// trusted to authenticate users.
+	IdentityURLKey = "identity-url"
+
+	// IdentityPublicKeyKey stores the public key of the external
+	// identity service.
+	IdentityPublicKeyKey = "identity-public-key"
+
 	// For LXC containers, is the container allowed to mount block
 	// devices. A theoretical security issue, so must be explicitly
 	// allowed by the user.
 	AllowLXCLoopMounts = "allow-lxc-loop-mounts"
 
+	// DryRunKey stores whether destructive provider operations, such
+	// as stopping instances, are only logged rather than executed.
+	// It allows a copy of a production config to be used safely
+	// against a real cloud.
+	DryRunKey = "dry-run"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -589,6 +642,71 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	// Check the unit assignment policy.
+	switch policy := cfg.UnitAssignmentPolicy(); policy {
+	case AssignClean, AssignCleanEmpty, AssignNew, AssignLeastLoaded:
+	default:
+		return fmt.Errorf("invalid unit assignment policy in environment configuration: %q", policy)
+	}
+
+	// Check the metrics collector URL, if any.
+	if collector, ok := cfg.MetricsCollectorURL(); ok {
+		if u, err := url.Parse(collector); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid metrics collector URL in environment configuration: %q", collector)
+		}
+	}
+
+	// Check the instance stop approval URL, if any.
+	if approver, ok := cfg.InstanceStopApprovalURL(); ok {
+		if u, err := url.Parse(approver); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid instance stop approval URL in environment configuration: %q", approver)
+		}
+	}
+
+	// Check the agent presence timeout, if any.
+	if v := cfg.asString(AgentPresenceTimeoutKey); v != "" {
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("invalid agent presence timeout in environment configuration: %q", v)
+		}
+	}
+
+	// Check the external identity service settings, if any.
+	if idURL := cfg.IdentityURL(); idURL != "" {
+		if u, err := url.Parse(idURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid identity URL in environment configuration: %q", idURL)
+		}
+		if cfg.asString(IdentityPublicKeyKey) == "" {
+			return fmt.Errorf("%s must be set when %s is set", IdentityPublicKeyKey, IdentityURLKey)
+		}
+	}
+	if v := cfg.asString(IdentityPublicKeyKey); v != "" {
+		if cfg.IdentityURL() == "" {
+			return fmt.Errorf("%s must be set when %s is set", IdentityURLKey, IdentityPublicKeyKey)
+		}
+		var pk bakery.PublicKey
+		if err := pk.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid identity public key in environment configuration: %v", err)
+		}
+	}
+
+	// Check the additional cloud-init user data, if any.
+	if v := cfg.asString(CloudInitUserDataKey); v != "" {
+		if _, err := parseCloudInitUserData(v); err != nil {
+			return errors.Annotatef(err, "invalid %s in environment configuration", CloudInitUserDataKey)
+		}
+	}
+
+	// Check the instance name template, if any.
+	if v := cfg.asString(InstanceNameTemplateKey); v != "" {
+		if err := validateInstanceNameTemplate(v); err != nil {
+			return errors.Annotatef(err, "invalid %s in environment configuration", InstanceNameTemplateKey)
+		}
+	}
+
+	if err := validateMongoTuning(cfg); err != nil {
+		return errors.Annotate(err, "invalid mongo settings in environment configuration")
+	}
+
 	// Check the immutable config values.  These can't change
 	if old != nil {
 		for _, attr := range immutableAttributes {
@@ -1114,6 +1232,82 @@ func (c *Config) StorageDefaultBlockSource() (string, bool) {
 	return bs, bs != ""
 }
 
+// UnitAssignmentPolicy returns the policy used to choose machines for
+// new units of services that do not specify their own (AssignClean,
+// AssignCleanEmpty, AssignNew or AssignLeastLoaded).
+func (c *Config) UnitAssignmentPolicy() string {
+	if policy := c.asString(UnitAssignmentPolicyKey); policy != "" {
+		return policy
+	}
+	return AssignCleanEmpty
+}
+
+// MetricsCollectorURL returns the URL of the service to which the
+// metrics collected from charms are sent, if one is configured.
+func (c *Config) MetricsCollectorURL() (string, bool) {
+	if collector := c.asString(MetricsCollectorURLKey); collector != "" {
+		return collector, true
+	}
+	return "", false
+}
+
+// InstanceStopApprovalURL returns the URL of the service that is
+// asked to approve each request to stop instances, if one is
+// configured.
+func (c *Config) InstanceStopApprovalURL() (string, bool) {
+	if approver := c.asString(InstanceStopApprovalURLKey); approver != "" {
+		return approver, true
+	}
+	return "", false
+}
+
+// AgentPresenceTimeout returns how long an agent may go without
+// contacting the state server before it is reported as lost.
+func (c *Config) AgentPresenceTimeout() time.Duration {
+	if v := c.asString(AgentPresenceTimeoutKey); v != "" {
+		// Validate has already checked the value.
+		d, _ := time.ParseDuration(v)
+		return d
+	}
+	return DefaultAgentPresenceTimeout
+}
+
+// IdentityURL returns the URL of the external identity service trusted
+// to authenticate users, or "" if none is configured.
+func (c *Config) IdentityURL() string {
+	return c.asString(IdentityURLKey)
+}
+
+// IdentityPublicKey returns the public key of the external identity
+// service, or nil if none is configured.
+func (c *Config) IdentityPublicKey() *bakery.PublicKey {
+	v := c.asString(IdentityPublicKeyKey)
+	if v == "" {
+		return nil
+	}
+	var pk bakery.PublicKey
+	// Validate has already checked the value.
+	if err := pk.UnmarshalText([]byte(v)); err != nil {
+		return nil
+	}
+	return &pk
+}
+
+// CloudInitUserData returns the additional cloud-init directives to
+// apply to every provisioned machine, or nil if none are configured.
+func (c *Config) CloudInitUserData() *CloudInitUserData {
+	v := c.asString(CloudInitUserDataKey)
+	if v == "" {
+		return nil
+	}
+	// Validate has already checked the value.
+	userData, err := parseCloudInitUserData(v)
+	if err != nil {
+		return nil
+	}
+	return userData
+}
+
 // AllowLXCLoopMounts returns whether loop devices are allowed
 // to be mounted inside lxc containers.
 func (c *Config) AllowLXCLoopMounts() (bool, bool) {
@@ -1121,6 +1315,13 @@ func (c *Config) AllowLXCLoopMounts() (bool, bool) {
 	return v, ok
 }
 
+// DryRun returns whether destructive provider operations should
+// only be logged rather than executed.
+func (c *Config) DryRun() bool {
+	v, _ := c.defined[DryRunKey].(bool)
+	return v
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1212,6 +1413,18 @@ var fields = schema.Fields{
 	PreventAllChangesKey:         schema.Bool(),
 	StorageDefaultBlockSourceKey: schema.String(),
 	AllowLXCLoopMounts:           schema.Bool(),
+	UnitAssignmentPolicyKey:      schema.String(),
+	MetricsCollectorURLKey:       schema.String(),
+	InstanceStopApprovalURLKey:   schema.String(),
+	AgentPresenceTimeoutKey:      schema.String(),
+	IdentityURLKey:               schema.String(),
+	IdentityPublicKeyKey:         schema.String(),
+	CloudInitUserDataKey:         schema.String(),
+	InstanceNameTemplateKey:      schema.String(),
+	DryRunKey:                    schema.Bool(),
+	MongoOplogSizeKey:            schema.ForceInt(),
+	MongoCacheSizeKey:            schema.ForceInt(),
+	MongoJournalIntervalKey:      schema.ForceInt(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1255,6 +1468,18 @@ var alwaysOptional = schema.Defaults{
 	AgentStreamKey:               schema.Omit,
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	AllowLXCLoopMounts:           false,
+	UnitAssignmentPolicyKey:      schema.Omit,
+	MetricsCollectorURLKey:       schema.Omit,
+	InstanceStopApprovalURLKey:   schema.Omit,
+	AgentPresenceTimeoutKey:      schema.Omit,
+	IdentityURLKey:               schema.Omit,
+	IdentityPublicKeyKey:         schema.Omit,
+	CloudInitUserDataKey:         schema.Omit,
+	InstanceNameTemplateKey:      schema.Omit,
+	DryRunKey:                    schema.Omit,
+	MongoOplogSizeKey:            schema.Omit,
+	MongoCacheSizeKey:            schema.Omit,
+	MongoJournalIntervalKey:      schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1365,6 +1590,12 @@ var immutableAttributes = []string{
 	"lxc-clone-aufs",
 	"syslog-port",
 	"prefer-ipv6",
+	// The mongo tuning settings are given to state server machines
+	// when they are provisioned, so changes would never be applied
+	// to the existing ones.
+	MongoOplogSizeKey,
+	MongoCacheSizeKey,
+	MongoJournalIntervalKey,
 }
 
 var (
@@ -560,6 +560,181 @@ var configTests = []configTest{
 			"firewall-mode": "illegal",
 		},
 		err: "invalid firewall mode in environment configuration: .*",
+	}, {
+		about:       "Clean unit assignment policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"unit-assignment-policy": config.AssignClean,
+		},
+	}, {
+		about:       "Least loaded unit assignment policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"unit-assignment-policy": config.AssignLeastLoaded,
+		},
+	}, {
+		about:       "Illegal unit assignment policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"unit-assignment-policy": "local",
+		},
+		err: `invalid unit assignment policy in environment configuration: "local"`,
+	}, {
+		about:       "Metrics collector URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"metrics-collector-url": "https://metrics.example.com/metrics",
+		},
+	}, {
+		about:       "Invalid metrics collector URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"metrics-collector-url": "metrics.example.com",
+		},
+		err: `invalid metrics collector URL in environment configuration: "metrics.example.com"`,
+	}, {
+		about:       "Instance stop approval URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"instance-stop-approval-url": "https://change.example.com/approve",
+		},
+	}, {
+		about:       "Invalid instance stop approval URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"instance-stop-approval-url": "change.example.com",
+		},
+		err: `invalid instance stop approval URL in environment configuration: "change.example.com"`,
+	}, {
+		about:       "Agent presence timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"agent-presence-timeout": "5m",
+		},
+	}, {
+		about:       "Invalid agent presence timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"agent-presence-timeout": "-1m",
+		},
+		err: `invalid agent presence timeout in environment configuration: "-1m"`,
+	}, {
+		about:       "External identity service",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-url":        "https://identity.example.com",
+			"identity-public-key": "AQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eHyA=",
+		},
+	}, {
+		about:       "Invalid identity URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-url":        "identity.example.com",
+			"identity-public-key": "AQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eHyA=",
+		},
+		err: `invalid identity URL in environment configuration: "identity.example.com"`,
+	}, {
+		about:       "Identity URL without public key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"identity-url": "https://identity.example.com",
+		},
+		err: `identity-public-key must be set when identity-url is set`,
+	}, {
+		about:       "Identity public key without URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-public-key": "AQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eHyA=",
+		},
+		err: `identity-url must be set when identity-public-key is set`,
+	}, {
+		about:       "Invalid identity public key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-url":        "https://identity.example.com",
+			"identity-public-key": "not-a-key",
+		},
+		err: `invalid identity public key in environment configuration: .*`,
+	}, {
+		about:       "Cloud-init user data",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"cloudinit-userdata": "packages: [htop]\nruncmd: [touch /tmp/done]\n",
+		},
+	}, {
+		about:       "Invalid cloud-init user data YAML",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"cloudinit-userdata": "packages: [htop",
+		},
+		err: `invalid cloudinit-userdata in environment configuration: invalid YAML: .*`,
+	}, {
+		about:       "Unsupported cloud-init user data directive",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"cloudinit-userdata": "bootcmd: [reboot]\n",
+		},
+		err: `invalid cloudinit-userdata in environment configuration: directive "bootcmd" not supported`,
+	}, {
+		about:       "Invalid cloud-init user data file permissions",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"cloudinit-userdata": "write_files:\n- path: /etc/motd\n  permissions: rw\n",
+		},
+		err: `invalid cloudinit-userdata in environment configuration: write_files\[0\]: invalid permissions "rw"`,
+	}, {
+		about:       "Instance name template",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"instance-name-template": "{env}-{machine}-{service}",
+		},
+	}, {
+		about:       "Invalid instance name template",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"instance-name-template": "{env}-{unit}",
+		},
+		err: `invalid instance-name-template in environment configuration: placeholder "{unit}" not supported`,
 	}, {
 		about:       "ssl-hostname-verification off",
 		useDefaults: config.UseDefaults,
@@ -1195,6 +1370,51 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	} else {
 		c.Assert(cfg.ProvisionerHarvestMode(), gc.Equals, config.HarvestDestroyed)
 	}
+
+	if v, ok := test.attrs["unit-assignment-policy"]; ok {
+		c.Assert(cfg.UnitAssignmentPolicy(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.UnitAssignmentPolicy(), gc.Equals, config.AssignCleanEmpty)
+	}
+	if v, ok := test.attrs["metrics-collector-url"]; ok {
+		collector, ok := cfg.MetricsCollectorURL()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(collector, gc.Equals, v)
+	} else {
+		_, ok := cfg.MetricsCollectorURL()
+		c.Assert(ok, jc.IsFalse)
+	}
+	if v, ok := test.attrs["instance-stop-approval-url"]; ok {
+		approver, ok := cfg.InstanceStopApprovalURL()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(approver, gc.Equals, v)
+	} else {
+		_, ok := cfg.InstanceStopApprovalURL()
+		c.Assert(ok, jc.IsFalse)
+	}
+	if v, ok := test.attrs["agent-presence-timeout"]; ok {
+		timeout, err := time.ParseDuration(v.(string))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(cfg.AgentPresenceTimeout(), gc.Equals, timeout)
+	} else {
+		c.Assert(cfg.AgentPresenceTimeout(), gc.Equals, config.DefaultAgentPresenceTimeout)
+	}
+	if v, ok := test.attrs["identity-url"]; ok {
+		c.Assert(cfg.IdentityURL(), gc.Equals, v)
+		pk := cfg.IdentityPublicKey()
+		c.Assert(pk, gc.NotNil)
+		text, err := pk.MarshalText()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(string(text), gc.Equals, test.attrs["identity-public-key"])
+	} else {
+		c.Assert(cfg.IdentityURL(), gc.Equals, "")
+		c.Assert(cfg.IdentityPublicKey(), gc.IsNil)
+	}
+	if _, ok := test.attrs["cloudinit-userdata"]; ok {
+		c.Assert(cfg.CloudInitUserData(), gc.NotNil)
+	} else {
+		c.Assert(cfg.CloudInitUserData(), gc.IsNil)
+	}
 	sshOpts := cfg.BootstrapSSHOpts()
 	test.assertDuration(
 		c,
@@ -1442,6 +1662,20 @@ var validationTests = []validationTest{{
 	old:   testing.Attrs{"prefer-ipv6": false},
 	new:   testing.Attrs{"prefer-ipv6": true},
 	err:   `cannot change prefer-ipv6 from false to true`,
+}, {
+	about: "Cannot change mongo-oplog-size",
+	old:   testing.Attrs{"mongo-oplog-size": 1024},
+	new:   testing.Attrs{"mongo-oplog-size": 2048},
+	err:   `cannot change mongo-oplog-size from 1024 to 2048`,
+}, {
+	about: "Cannot set mongo-cache-size",
+	new:   testing.Attrs{"mongo-cache-size": 4},
+	err:   `cannot change mongo-cache-size from <nil> to 4`,
+}, {
+	about: "Cannot change mongo-journal-commit-interval",
+	old:   testing.Attrs{"mongo-journal-commit-interval": 100},
+	new:   testing.Attrs{"mongo-journal-commit-interval": 50},
+	err:   `cannot change mongo-journal-commit-interval from 100 to 50`,
 }, {
 	about: "Can change uuid from unset to set",
 	new:   testing.Attrs{"uuid": "dcfbdb4a-bca2-49ad-aa7c-f011424e0fe4"},
@@ -1578,6 +1812,96 @@ func newTestConfig(c *gc.C, explicit testing.Attrs) *config.Config {
 	return result
 }
 
+func (s *ConfigSuite) TestCloudInitUserData(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"cloudinit-userdata": `
+packages: [htop, tree]
+runcmd:
+- touch /tmp/done
+write_files:
+- path: /etc/motd
+  content: hello
+  permissions: "0600"
+- path: /etc/issue
+`,
+	})
+	c.Assert(cfg.CloudInitUserData(), jc.DeepEquals, &config.CloudInitUserData{
+		Packages: []string{"htop", "tree"},
+		RunCmd:   []string{"touch /tmp/done"},
+		WriteFiles: []config.CloudInitFile{{
+			Path:        "/etc/motd",
+			Content:     "hello",
+			Permissions: 0600,
+		}, {
+			Path:        "/etc/issue",
+			Permissions: 0644,
+		}},
+	})
+}
+
+func (s *ConfigSuite) TestInstanceName(c *gc.C) {
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.InstanceName("0", []string{"mysql"}), gc.Equals, "")
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"instance-name-template": "{env}-{machine}-{service}",
+	})
+	c.Assert(cfg.InstanceName("3", []string{"mysql", "nrpe"}), gc.Equals, "my-name-3-mysql-nrpe")
+	c.Assert(cfg.InstanceName("3", nil), gc.Equals, "my-name-3")
+
+	// Separators around an empty placeholder are collapsed.
+	cfg = newTestConfig(c, testing.Attrs{
+		"instance-name-template": "{env}-{service}-{machine}",
+	})
+	c.Assert(cfg.InstanceName("3", nil), gc.Equals, "my-name-3")
+	c.Assert(cfg.InstanceName("3", []string{"mysql"}), gc.Equals, "my-name-mysql-3")
+}
+
+func (s *ConfigSuite) TestDryRun(c *gc.C) {
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.DryRun(), jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{"dry-run": true})
+	c.Assert(cfg.DryRun(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestMongoTuning(c *gc.C) {
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.MongoOplogSize(), gc.Equals, 0)
+	c.Assert(cfg.MongoCacheSize(), gc.Equals, 0)
+	c.Assert(cfg.MongoJournalInterval(), gc.Equals, 0)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"mongo-oplog-size":              "2048",
+		"mongo-cache-size":              4,
+		"mongo-journal-commit-interval": 50,
+	})
+	c.Assert(cfg.MongoOplogSize(), gc.Equals, 2048)
+	c.Assert(cfg.MongoCacheSize(), gc.Equals, 4)
+	c.Assert(cfg.MongoJournalInterval(), gc.Equals, 50)
+}
+
+func (s *ConfigSuite) TestMongoTuningInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"mongo-oplog-size": -1},
+		err:   `invalid mongo settings in environment configuration: negative mongo-oplog-size -1 not valid`,
+	}, {
+		attrs: testing.Attrs{"mongo-cache-size": -2},
+		err:   `invalid mongo settings in environment configuration: negative mongo-cache-size -2 not valid`,
+	}, {
+		attrs: testing.Attrs{"mongo-journal-commit-interval": 301},
+		err:   `invalid mongo settings in environment configuration: mongo-journal-commit-interval 301 \(must be between 2 and 300\) not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{"type": "my-type", "name": "my-name"}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *ConfigSuite) TestLoggingConfig(c *gc.C) {
 	s.addJujuFiles(c)
 	config := newTestConfig(c, testing.Attrs{
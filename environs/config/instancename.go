@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// InstanceNameTemplateKey stores the template used to build the
+// display names of provider instances, for providers that support
+// naming them, e.g. "{env}-{machine}-{service}". Only EC2 does so
+// currently, with the instance's Name tag; the other providers
+// identify their instances by name, so they ignore the setting.
+const InstanceNameTemplateKey = "instance-name-template"
+
+// instanceNamePlaceholder matches the placeholders in an instance
+// name template.
+var instanceNamePlaceholder = regexp.MustCompile(`{[^{}]*}`)
+
+// instanceNameSeparators matches runs of separators in an instance
+// name.
+var instanceNameSeparators = regexp.MustCompile(`-{2,}`)
+
+// validateInstanceNameTemplate checks that the given template only
+// uses the {env}, {machine} and {service} placeholders.
+func validateInstanceNameTemplate(template string) error {
+	for _, placeholder := range instanceNamePlaceholder.FindAllString(template, -1) {
+		switch placeholder {
+		case "{env}", "{machine}", "{service}":
+		default:
+			return errors.NotSupportedf("placeholder %q", placeholder)
+		}
+	}
+	return nil
+}
+
+// InstanceName returns the display name for the instance hosting the
+// given machine, built from the instance-name-template setting, or ""
+// if no template is configured. The services hosted by the machine
+// are joined with "-"; separators left dangling or repeated by empty
+// placeholders are removed.
+func (c *Config) InstanceName(machineId string, services []string) string {
+	template := c.asString(InstanceNameTemplateKey)
+	if template == "" {
+		return ""
+	}
+	name := strings.NewReplacer(
+		"{env}", c.Name(),
+		"{machine}", machineId,
+		"{service}", strings.Join(services, "-"),
+	).Replace(template)
+	name = instanceNameSeparators.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-")
+}
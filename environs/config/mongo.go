@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"github.com/juju/errors"
+)
+
+// The mongo tuning settings are applied when state server machines are
+// provisioned, and cannot be changed after bootstrap.
+const (
+	// MongoOplogSizeKey stores the size, in megabytes, of the oplog
+	// of the mongo servers started on state server machines. If it
+	// is not set, the size is computed from the disk space available.
+	MongoOplogSizeKey = "mongo-oplog-size"
+
+	// MongoCacheSizeKey stores the size, in gigabytes, of the
+	// WiredTiger cache of the mongo servers started on state server
+	// machines. If it is not set, the size is computed from the
+	// memory of the machine. It is ignored by versions of mongo that
+	// do not support WiredTiger.
+	MongoCacheSizeKey = "mongo-cache-size"
+
+	// MongoJournalIntervalKey stores the maximum time, in
+	// milliseconds, between journal commits of the mongo servers
+	// started on state server machines. If it is not set, mongo's
+	// own default is used.
+	MongoJournalIntervalKey = "mongo-journal-commit-interval"
+)
+
+const (
+	minJournalCommitInterval = 2
+	maxJournalCommitInterval = 300
+)
+
+// validateMongoTuning checks the mongo tuning settings of the given
+// config.
+func validateMongoTuning(cfg *Config) error {
+	for _, key := range []string{MongoOplogSizeKey, MongoCacheSizeKey} {
+		if v, ok := cfg.defined[key].(int); ok && v < 0 {
+			return errors.NotValidf("negative %s %d", key, v)
+		}
+	}
+	if v := cfg.MongoJournalInterval(); v != 0 {
+		if v < minJournalCommitInterval || v > maxJournalCommitInterval {
+			return errors.NotValidf(
+				"%s %d (must be between %d and %d)",
+				MongoJournalIntervalKey, v,
+				minJournalCommitInterval, maxJournalCommitInterval,
+			)
+		}
+	}
+	return nil
+}
+
+// MongoOplogSize returns the configured size of the mongo oplog in
+// megabytes, or 0 if it should be computed.
+func (c *Config) MongoOplogSize() int {
+	v, _ := c.defined[MongoOplogSizeKey].(int)
+	return v
+}
+
+// MongoCacheSize returns the configured size of the mongo WiredTiger
+// cache in gigabytes, or 0 if it should be computed.
+func (c *Config) MongoCacheSize() int {
+	v, _ := c.defined[MongoCacheSizeKey].(int)
+	return v
+}
+
+// MongoJournalInterval returns the configured maximum time in
+// milliseconds between mongo journal commits, or 0 if mongo's default
+// should be used.
+func (c *Config) MongoJournalInterval() int {
+	v, _ := c.defined[MongoJournalIntervalKey].(int)
+	return v
+}
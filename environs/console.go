@@ -0,0 +1,25 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/instance"
+)
+
+// ConsoleOutputReader is implemented by environs whose clouds can
+// report the console output of an instance, so that the progress of
+// cloud-init on a starting instance can be followed.
+type ConsoleOutputReader interface {
+	// ConsoleOutput returns the console output of the instance with
+	// the given id, as far as the cloud has captured it. Clouds may
+	// discard the start of a long output.
+	ConsoleOutput(id instance.Id) (string, error)
+}
+
+// SupportsConsoleOutput is a convenience helper to check if an
+// environ can report the console output of its instances.
+func SupportsConsoleOutput(environ Environ) (ConsoleOutputReader, bool) {
+	r, ok := environ.(ConsoleOutputReader)
+	return r, ok
+}
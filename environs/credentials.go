@@ -0,0 +1,20 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+// CredentialsVerifier is implemented by environs that can check their
+// configured cloud credentials, using a cheap request that does not
+// modify anything in the cloud.
+type CredentialsVerifier interface {
+	// VerifyCredentials returns an error if the cloud rejects the
+	// environ's credentials.
+	VerifyCredentials() error
+}
+
+// SupportsCredentialsVerification is a convenience helper to check if
+// an environ can verify its credentials.
+func SupportsCredentialsVerification(environ Environ) (CredentialsVerifier, bool) {
+	v, ok := environ.(CredentialsVerifier)
+	return v, ok
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/network"
+)
+
+// DNSRecord describes the DNS name registered for an exposed service.
+type DNSRecord struct {
+	// Name is the fully qualified name of the service, as returned
+	// by ServiceDNSName.
+	Name string
+
+	// Addresses holds the public addresses of the service's units.
+	Addresses []network.Address
+
+	// Owner holds the UUID of the environment that registered the
+	// record. Registrars must store it with the record, for example
+	// in a TXT record of the same name, and report it from
+	// DNSRecords; records without it were not registered by juju.
+	Owner string
+}
+
+// DNSRegistrar is an optional interface implemented by environments
+// on clouds with managed DNS, such as Route53 or Designate, to publish
+// the names of exposed services.
+type DNSRegistrar interface {
+	// DNSDomain returns the domain under which service names are
+	// registered. An empty domain disables registration.
+	DNSDomain() (string, error)
+
+	// RegisterDNS creates the given record, or replaces the record
+	// with the same name if one exists.
+	RegisterDNS(record DNSRecord) error
+
+	// UnregisterDNS removes the record with the given name. It is
+	// not an error if no such record exists.
+	UnregisterDNS(name string) error
+
+	// DNSRecords returns the records currently registered under
+	// the domain, with their owners, so that records left behind by
+	// earlier runs can be updated or removed.
+	DNSRecords() ([]DNSRecord, error)
+}
+
+// DNSRegistrarEnviron combines the standard Environ interface with
+// the functionality for registering DNS names.
+type DNSRegistrarEnviron interface {
+	// Environ represents a juju environment.
+	Environ
+
+	// DNSRegistrar defines the methods of environments with
+	// managed DNS.
+	DNSRegistrar
+}
+
+// SupportsDNSRegistration is a convenience helper to check if an
+// environment can register DNS names. It returns an interface
+// containing Environ and DNSRegistrar in this case.
+func SupportsDNSRegistration(environ Environ) (DNSRegistrarEnviron, bool) {
+	de, ok := environ.(DNSRegistrarEnviron)
+	return de, ok
+}
+
+// ServiceDNSName returns the DNS name registered for the given service,
+// of the form service-name.environment.domain.
+func ServiceDNSName(serviceName, environName, domain string) string {
+	return serviceName + "." + environName + "." + domain
+}
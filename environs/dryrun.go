@@ -0,0 +1,18 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+// IsDryRun reports whether destructive operations made through the
+// given value, typically an Environ, should only be logged, because
+// its environment config has dry-run set. Values that do not expose
+// an environment config, such as container brokers, are never in
+// dry-run mode.
+func IsDryRun(v interface{}) bool {
+	getter, ok := v.(ConfigGetter)
+	if !ok {
+		return false
+	}
+	cfg := getter.Config()
+	return cfg != nil && cfg.DryRun()
+}
@@ -23,6 +23,11 @@ var (
 	datasourceFuncs   []datasourceFuncId
 )
 
+// noDefaultSourceEnvs holds the names of the environments for which
+// ImageMetadataSources omits the public default source. It is guarded
+// by datasourceFuncsMu.
+var noDefaultSourceEnvs = make(map[string]bool)
+
 // ImageDataSourceFunc is a function type that takes an environment and
 // returns a simplestreams datasource.
 //
@@ -61,6 +66,27 @@ func UnregisterImageDataSourceFunc(id string) {
 	}
 }
 
+// SetDefaultImageSourceDisabled controls whether ImageMetadataSources
+// omits the public default source for the named environment; offline
+// bootstrap disables it for the duration of the bootstrap.
+func SetDefaultImageSourceDisabled(envName string, disabled bool) {
+	datasourceFuncsMu.Lock()
+	defer datasourceFuncsMu.Unlock()
+	if disabled {
+		noDefaultSourceEnvs[envName] = true
+	} else {
+		delete(noDefaultSourceEnvs, envName)
+	}
+}
+
+// defaultImageSourceDisabled reports whether the public default source
+// is omitted for the named environment.
+func defaultImageSourceDisabled(envName string) bool {
+	datasourceFuncsMu.RLock()
+	defer datasourceFuncsMu.RUnlock()
+	return noDefaultSourceEnvs[envName]
+}
+
 // ImageMetadataSources returns the sources to use when looking for
 // simplestreams image id metadata for the given stream.
 func ImageMetadataSources(env Environ) ([]simplestreams.DataSource, error) {
@@ -83,6 +109,9 @@ func ImageMetadataSources(env Environ) ([]simplestreams.DataSource, error) {
 	sources = append(sources, envDataSources...)
 
 	// Add the default, public datasource.
+	if defaultImageSourceDisabled(config.Name()) {
+		return sources, nil
+	}
 	defaultURL, err := imagemetadata.ImageMetadataURL(imagemetadata.DefaultBaseURL, config.ImageStream())
 	if err != nil {
 		return nil, err
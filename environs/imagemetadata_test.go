@@ -103,6 +103,23 @@ func (s *ImageMetadataSuite) TestImageMetadataURLsRegisteredFuncsError(c *gc.C)
 	c.Assert(err, gc.ErrorMatches, "oyvey!")
 }
 
+func (s *ImageMetadataSuite) TestImageMetadataURLsDefaultSourceDisabled(c *gc.C) {
+	env := s.env(c, "config-image-metadata-url", "")
+	environs.SetDefaultImageSourceDisabled(env.Config().Name(), true)
+	sources, err := environs.ImageMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	sstesting.AssertExpectedSources(c, sources, []string{
+		"config-image-metadata-url/",
+	})
+
+	environs.SetDefaultImageSourceDisabled(env.Config().Name(), false)
+	sources, err = environs.ImageMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	sstesting.AssertExpectedSources(c, sources, []string{
+		"config-image-metadata-url/", "http://cloud-images.ubuntu.com/releases/",
+	})
+}
+
 func (s *ImageMetadataSuite) TestImageMetadataURLsNonReleaseStream(c *gc.C) {
 	env := s.env(c, "", "daily")
 	sources, err := environs.ImageMetadataSources(env)
@@ -221,3 +221,17 @@ type BootstrapContext interface {
 	// credentials should be verified.
 	ShouldVerifyCredentials() bool
 }
+
+// BootstrapProgress is an optional interface implemented by bootstrap
+// contexts that report the progress of the bootstrap, such as the one
+// environs/bootstrap passes to Environ.Bootstrap. Providers report
+// through it the steps that only they can observe.
+type BootstrapProgress interface {
+	// ImageSelected is called once the image for the bootstrap
+	// instance has been chosen.
+	ImageSelected(imageId string)
+
+	// ToolsUploaded is called once tools built locally have been
+	// copied to the bootstrap instance.
+	ToolsUploaded()
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -59,6 +60,19 @@ type ProvisionMachineArgs struct {
 	// Stderr is required to present machine provisioning progress to the user.
 	Stderr io.Writer
 
+	// InstanceId, if non-empty, is the provider instance id of an
+	// existing instance being imported into the environment. When
+	// empty, a "manual:" instance id is derived from the host.
+	InstanceId instance.Id
+
+	// InteractiveLock, if non-nil, is held while the ubuntu user is
+	// initialised, which may prompt for a password on Stdout and read
+	// the reply from Stdin. It allows several machines to be
+	// provisioned concurrently while sharing a terminal. The host
+	// being initialised is reported on Stderr once the lock is held,
+	// so that an unprefixed prompt can be attributed to it.
+	InteractiveLock sync.Locker
+
 	*params.UpdateBehavior
 }
 
@@ -89,11 +103,21 @@ func ProvisionMachine(args ProvisionMachineArgs) (machineId string, err error) {
 	// ubuntu user's authorized_keys.
 	user, hostname := splitUserHost(args.Host)
 	authorizedKeys, err := config.ReadAuthorizedKeys("")
-	if err := InitUbuntuUser(hostname, user, authorizedKeys, args.Stdin, args.Stdout); err != nil {
+	if args.InteractiveLock != nil {
+		args.InteractiveLock.Lock()
+		// Other hosts may be provisioned at the same time, and any
+		// password prompt that follows does not name its host.
+		fmt.Fprintf(args.Stderr, "initialising ubuntu user on %s\n", hostname)
+	}
+	err = InitUbuntuUser(hostname, user, authorizedKeys, args.Stdin, args.Stdout)
+	if args.InteractiveLock != nil {
+		args.InteractiveLock.Unlock()
+	}
+	if err != nil {
 		return "", err
 	}
 
-	machineParams, err := gatherMachineParams(hostname)
+	machineParams, err := gatherMachineParams(hostname, args.InstanceId)
 	if err != nil {
 		return "", err
 	}
@@ -149,8 +173,10 @@ func recordMachineInState(client ProvisioningClientAPI, machineParams params.Add
 // we are about to provision. It will SSH into that machine as the ubuntu user.
 // The hostname supplied should not include a username.
 // If we can, we will reverse lookup the hostname by its IP address, and use
-// the DNS resolved name, rather than the name that was supplied
-func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
+// the DNS resolved name, rather than the name that was supplied.
+// If instanceId is non-empty, it is recorded as the machine's instance id
+// in place of one derived from the hostname.
+func gatherMachineParams(hostname string, instanceId instance.Id) (*params.AddMachineParams, error) {
 
 	// Generate a unique nonce for the machine.
 	uuid, err := utils.NewUUID()
@@ -190,7 +216,9 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 	// and never touches the network configuration files.
 	// No JobManageNetworking here due to manual provisioning.
 
-	instanceId := instance.Id(manualInstancePrefix + hostname)
+	if instanceId == "" {
+		instanceId = instance.Id(manualInstancePrefix + hostname)
+	}
 	nonce := fmt.Sprintf("%s:%s", instanceId, uuid.String())
 	machineParams := &params.AddMachineParams{
 		Series:                  series,
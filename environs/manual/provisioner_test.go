@@ -4,8 +4,10 @@
 package manual_test
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"sync"
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/shell"
@@ -120,6 +122,59 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "error checking if provisioned: subprocess encountered error code 255")
 }
 
+func (s *provisionerSuite) TestProvisionMachineWithInstanceId(c *gc.C) {
+	const series = coretesting.FakeDefaultSeries
+	const arch = "amd64"
+
+	args := s.getArgs(c)
+	args.InstanceId = "i-imported"
+
+	cfg := s.Environ.Config()
+	number, ok := cfg.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	binVersion := version.Binary{number, series, arch, version.Ubuntu}
+	envtesting.AssertUploadFakeToolsVersions(c, s.DefaultToolsStorage, "released", "released", binVersion)
+
+	defer fakeSSH{
+		Series:         series,
+		Arch:           arch,
+		InitUbuntuUser: true,
+	}.install(c).Restore()
+	machineId, err := manual.ProvisionMachine(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceId, err := m.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceId, gc.Equals, instance.Id("i-imported"))
+}
+
+func (s *provisionerSuite) TestProvisionMachineReportsInteractiveHost(c *gc.C) {
+	const series = coretesting.FakeDefaultSeries
+	const arch = "amd64"
+
+	var stderr bytes.Buffer
+	args := s.getArgs(c)
+	args.Stderr = &stderr
+	args.InteractiveLock = &sync.Mutex{}
+
+	cfg := s.Environ.Config()
+	number, ok := cfg.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	binVersion := version.Binary{number, series, arch, version.Ubuntu}
+	envtesting.AssertUploadFakeToolsVersions(c, s.DefaultToolsStorage, "released", "released", binVersion)
+
+	defer fakeSSH{
+		Series:         series,
+		Arch:           arch,
+		InitUbuntuUser: true,
+	}.install(c).Restore()
+	_, err := manual.ProvisionMachine(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr.String(), jc.Contains, "initialising ubuntu user on "+args.Host+"\n")
+}
+
 func (s *provisionerSuite) TestFinishInstancConfig(c *gc.C) {
 	const series = coretesting.FakeDefaultSeries
 	const arch = "amd64"
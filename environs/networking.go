@@ -23,7 +23,10 @@ type Networking interface {
 	ReleaseAddress(instId instance.Id, subnetId network.Id, addr network.Address) error
 
 	// Subnets returns basic information about subnets known
-	// by the provider for the environment.
+	// by the provider for the environment. If subnetIds is empty,
+	// all the subnets available to the environment are returned, or
+	// an error satisfying errors.IsNotSupported if the provider
+	// cannot list them.
 	Subnets(inst instance.Id, subnetIds []network.Id) ([]network.SubnetInfo, error)
 
 	// NetworkInterfaces requests information about the network
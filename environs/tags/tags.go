@@ -0,0 +1,16 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tags holds the names of the tags that juju applies to
+// the provider resources it creates.
+package tags
+
+const (
+	// JujuEnv is the tag holding the UUID of the environment
+	// that owns a resource.
+	JujuEnv = "juju-env-uuid"
+
+	// JujuStateServer is the tag set on instances that host
+	// a state server.
+	JujuStateServer = "juju-is-state"
+)
@@ -111,6 +111,7 @@ func CheckUpgraderReadyError(c *gc.C, obtained error, expected *upgrader.Upgrade
 	c.Assert(err.DataDir, gc.Equals, expected.DataDir)
 	c.Assert(err.OldTools, gc.Equals, expected.OldTools)
 	c.Assert(err.NewTools, gc.Equals, expected.NewTools)
+	c.Assert(err.Revert, gc.Equals, expected.Revert)
 }
 
 // PrimeTools sets up the current version of the tools to vers and
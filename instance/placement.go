@@ -14,6 +14,10 @@ const (
 	// MachineScope is a special scope name that is used
 	// for machine placement directives (e.g. --to 0).
 	MachineScope = "#"
+
+	// LabelDirectivePrefix prefixes placement directives that
+	// select machines by a user-defined label (e.g. --to label=gpu).
+	LabelDirectivePrefix = "label="
 )
 
 var ErrPlacementScopeMissing = fmt.Errorf("placement scope missing")
@@ -60,8 +64,12 @@ func ParsePlacement(directive string) (*Placement, error) {
 			return nil, ErrPlacementScopeMissing
 		}
 		// Sanity check: machine/container scopes require a machine ID as the value.
-		if (scope == MachineScope || isContainerType(scope)) && !names.IsValidMachine(directive) {
-			return nil, fmt.Errorf("invalid value %q for %q scope: expected machine-id", directive, scope)
+		// Containers may also be placed inside a machine chosen by label.
+		if scope == MachineScope || isContainerType(scope) {
+			_, isLabel := ParseLabelDirective(directive)
+			if !names.IsValidMachine(directive) && !(isLabel && scope != MachineScope) {
+				return nil, fmt.Errorf("invalid value %q for %q scope: expected machine-id", directive, scope)
+			}
 		}
 		return &Placement{Scope: scope, Directive: directive}, nil
 	}
@@ -74,6 +82,16 @@ func ParsePlacement(directive string) (*Placement, error) {
 	return nil, ErrPlacementScopeMissing
 }
 
+// ParseLabelDirective returns the machine label named by the given
+// label placement directive, such as "label=gpu", and whether the
+// directive is a label directive at all.
+func ParseLabelDirective(directive string) (string, bool) {
+	if !strings.HasPrefix(directive, LabelDirectivePrefix) {
+		return "", false
+	}
+	return directive[len(LabelDirectivePrefix):], true
+}
+
 // MustParsePlacement attempts to parse the specified string and create
 // a corresponding Placement structure, panicking if an error occurs.
 func MustParsePlacement(directive string) *Placement {
@@ -42,6 +42,13 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		arg:             "kvm:123",
 		expectScope:     string(instance.KVM),
 		expectDirective: "123",
+	}, {
+		arg:             "lxc:label=gpu",
+		expectScope:     string(instance.LXC),
+		expectDirective: "label=gpu",
+	}, {
+		arg: "#:label=gpu",
+		err: `invalid value "label=gpu" for "#" scope: expected machine-id`,
 	}, {
 		arg:         "lxc",
 		expectScope: string(instance.LXC),
@@ -75,3 +82,12 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		}
 	}
 }
+
+func (s *PlacementSuite) TestParseLabelDirective(c *gc.C) {
+	label, ok := instance.ParseLabelDirective("label=gpu")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(label, gc.Equals, "gpu")
+
+	_, ok = instance.ParseLabelDirective("lxc:1")
+	c.Assert(ok, jc.IsFalse)
+}
@@ -35,11 +35,15 @@ type DeployServiceParams struct {
 	// Networks holds a list of networks to required to start on boot.
 	Networks []string
 	Storage  map[string]storage.Constraints
+	// AssignmentPolicy, if set, overrides the environment's policy
+	// for choosing machines for the service's units.
+	AssignmentPolicy state.AssignmentPolicy
 }
 
 // DeployService takes a charm and various parameters and deploys it.
 func DeployService(st *state.State, args DeployServiceParams) (*state.Service, error) {
-	if args.NumUnits > 1 && args.ToMachineSpec != "" {
+	_, isLabel := instance.ParseLabelDirective(args.ToMachineSpec)
+	if args.NumUnits > 1 && args.ToMachineSpec != "" && !isLabel {
 		return nil, fmt.Errorf("cannot use --num-units with --to")
 	}
 	settings, err := args.Charm.Config().ValidateSettings(args.ConfigSettings)
@@ -54,6 +58,11 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, fmt.Errorf("subordinate service must be deployed without constraints")
 		}
 	}
+	if args.AssignmentPolicy != "" {
+		if err := args.AssignmentPolicy.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	if args.ServiceOwner == "" {
 		env, err := st.Environment()
 		if err != nil {
@@ -99,6 +108,11 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, err
 		}
 	}
+	if args.AssignmentPolicy != "" {
+		if err := service.SetAssignmentPolicy(args.AssignmentPolicy); err != nil {
+			return nil, err
+		}
+	}
 	if args.NumUnits > 0 {
 		if _, err := AddUnits(st, service, args.NumUnits, args.ToMachineSpec); err != nil {
 			return nil, err
@@ -111,8 +125,10 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 // to them as necessary.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
 	units := make([]*state.Unit, n)
-	// Hard code for now till we implement a different approach.
-	policy := state.AssignCleanEmpty
+	policy, err := assignmentPolicy(st, svc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	// All units should have the same networks as the service.
 	networks, err := svc.Networks()
 	if err != nil {
@@ -124,7 +140,13 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 		if err != nil {
 			return nil, fmt.Errorf("cannot add unit %d/%d to service %q: %v", i+1, n, svc.Name(), err)
 		}
-		if machineIdSpec != "" {
+		if label, ok := instance.ParseLabelDirective(machineIdSpec); ok {
+			// Each unit goes to a machine with the label, so many
+			// units may be placed with a single directive.
+			if _, err := unit.AssignToMachineWithLabel(label); err != nil {
+				return nil, errors.Annotatef(err, "cannot assign unit %q", unit.Name())
+			}
+		} else if machineIdSpec != "" {
 			if n != 1 {
 				return nil, fmt.Errorf("cannot add multiple units of service %q to a single machine", svc.Name())
 			}
@@ -195,3 +217,17 @@ func stateStorageConstraints(cons map[string]storage.Constraints) map[string]sta
 	}
 	return result
 }
+
+// assignmentPolicy returns the policy used to choose machines for new
+// units of the given service: the service's own policy if it has one,
+// and the environment's otherwise.
+func assignmentPolicy(st *state.State, svc *state.Service) (state.AssignmentPolicy, error) {
+	if policy, ok := svc.AssignmentPolicy(); ok {
+		return policy, nil
+	}
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return state.AssignmentPolicy(cfg.UnitAssignmentPolicy()), nil
+}
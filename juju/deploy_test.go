@@ -146,6 +146,56 @@ func (s *DeployLocalSuite) TestDeployNumUnits(c *gc.C) {
 	s.assertMachines(c, service, constraints.MustParse("mem=2G cpu-cores=2"), "0", "1")
 }
 
+func (s *DeployLocalSuite) TestDeployServiceAssignmentPolicy(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Id(), gc.Equals, "0")
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			NumUnits:         1,
+			AssignmentPolicy: state.AssignNew,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, ok := service.AssignmentPolicy()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(policy, gc.Equals, state.AssignNew)
+	s.assertMachines(c, service, constraints.Value{}, "1")
+}
+
+func (s *DeployLocalSuite) TestDeployEnvironAssignmentPolicy(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"unit-assignment-policy": "new",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Id(), gc.Equals, "0")
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+			NumUnits:    1,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := service.AssignmentPolicy()
+	c.Assert(ok, jc.IsFalse)
+	s.assertMachines(c, service, constraints.Value{}, "1")
+}
+
+func (s *DeployLocalSuite) TestDeployInvalidAssignmentPolicy(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			AssignmentPolicy: state.AssignLocal,
+		})
+	c.Assert(err, gc.ErrorMatches, `unit assignment policy "local" not valid`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *DeployLocalSuite) TestDeployWithForceMachineRejectsTooManyUnits(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -214,6 +264,37 @@ func (s *DeployLocalSuite) TestDeployForceMachineIdWithContainer(c *gc.C) {
 	c.Assert(machineCons, gc.DeepEquals, *unitCons)
 }
 
+func (s *DeployLocalSuite) TestDeployToMachineLabel(c *gc.C) {
+	for _, labels := range [][]string{{"gpu"}, nil, {"gpu", "ssd"}} {
+		_, err := s.State.AddOneMachine(state.MachineTemplate{
+			Series: "quantal",
+			Jobs:   []state.MachineJob{state.JobHostUnits},
+			Labels: labels,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      2,
+			ToMachineSpec: "label=gpu",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachines(c, service, constraints.Value{}, "0", "2")
+}
+
+func (s *DeployLocalSuite) TestDeployToMachineLabelNotFound(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "label=gpu",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0": machine with label "gpu" not found`)
+}
+
 func (s *DeployLocalSuite) assertCharm(c *gc.C, service *state.Service, expect *charm.URL) {
 	curl, force := service.CharmURL()
 	c.Assert(curl, gc.DeepEquals, expect)
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+var (
+	// minCacheSizeGB is the smallest WiredTiger cache size that
+	// defaultCacheSize returns.
+	minCacheSizeGB = 1
+
+	physicalMemoryMB   = procPhysicalMemoryMB
+	mongodMajorVersion = execMongodMajorVersion
+)
+
+// defaultCacheSize returns the size, in gigabytes, of the WiredTiger
+// cache to use when none is configured. The state server machine
+// also runs the machine agent, so mongod is given a quarter of its
+// memory rather than the half it would take by default.
+func defaultCacheSize() (int, error) {
+	memMB, err := physicalMemoryMB()
+	if err != nil {
+		return -1, errors.Trace(err)
+	}
+	size := int(memMB / 4 / 1024)
+	if size < minCacheSizeGB {
+		size = minCacheSizeGB
+	}
+	return size, nil
+}
+
+// procPhysicalMemoryMB returns the total physical memory of the
+// machine in megabytes, as reported by /proc/meminfo.
+func procPhysicalMemoryMB() (uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kB, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, errors.Annotatef(err, "cannot parse MemTotal %q", fields[1])
+		}
+		return kB / 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return 0, errors.New("MemTotal not found in /proc/meminfo")
+}
+
+var mongodVersionPattern = regexp.MustCompile(`db version v(\d+)\.`)
+
+// execMongodMajorVersion returns the major version of the mongod at
+// the given path.
+func execMongodMajorVersion(mongoPath string) (int, error) {
+	output, err := exec.Command(mongoPath, "--version").CombinedOutput()
+	if err != nil {
+		return -1, errors.Annotatef(err, "cannot run %s --version", mongoPath)
+	}
+	return parseMongodMajorVersion(string(output))
+}
+
+// parseMongodMajorVersion returns the major version reported in the
+// output of mongod --version.
+func parseMongodMajorVersion(output string) (int, error) {
+	match := mongodVersionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return -1, errors.Errorf("no version found in %q", output)
+	}
+	return strconv.Atoi(match[1])
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/mongo"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type cacheSizeSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&cacheSizeSuite{})
+
+func (s *cacheSizeSuite) TestDefaultCacheSize(c *gc.C) {
+	for i, test := range []struct {
+		memMB    uint64
+		expected int
+	}{
+		{memMB: 512, expected: 1},
+		{memMB: 4 * 1024, expected: 1},
+		{memMB: 16 * 1024, expected: 4},
+		{memMB: 30 * 1024, expected: 7},
+	} {
+		c.Logf("test %d: %dMB", i, test.memMB)
+		memMB := test.memMB
+		s.PatchValue(mongo.PhysicalMemoryMB, func() (uint64, error) {
+			return memMB, nil
+		})
+		size, err := mongo.DefaultCacheSize()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(size, gc.Equals, test.expected)
+	}
+}
+
+func (s *cacheSizeSuite) TestDefaultCacheSizeError(c *gc.C) {
+	s.PatchValue(mongo.PhysicalMemoryMB, func() (uint64, error) {
+		return 0, errors.New("no memory")
+	})
+	_, err := mongo.DefaultCacheSize()
+	c.Assert(err, gc.ErrorMatches, "no memory")
+}
+
+func (s *cacheSizeSuite) TestParseMongodMajorVersion(c *gc.C) {
+	major, err := mongo.ParseMongodMajorVersion("db version v2.4.9\nWed Oct 14 ...")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(major, gc.Equals, 2)
+
+	major, err = mongo.ParseMongodMajorVersion("db version v3.0.7\ngit version: ...")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(major, gc.Equals, 3)
+
+	_, err = mongo.ParseMongodMajorVersion("mongod: command not found")
+	c.Assert(err, gc.ErrorMatches, `no version found in "mongod: command not found"`)
+}
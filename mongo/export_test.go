@@ -26,6 +26,11 @@ var (
 	MaxOplogSizeMB = &maxOplogSizeMB
 	PreallocFile   = &preallocFile
 
+	PhysicalMemoryMB        = &physicalMemoryMB
+	MongodMajorVersion      = &mongodMajorVersion
+	DefaultCacheSize        = defaultCacheSize
+	ParseMongodMajorVersion = parseMongodMajorVersion
+
 	DefaultOplogSize  = defaultOplogSize
 	FsAvailSpace      = fsAvailSpace
 	PreallocFileSizes = preallocFileSizes
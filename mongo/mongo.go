@@ -152,6 +152,16 @@ type EnsureServerParams struct {
 	// algorithm defined in Mongo.
 	OplogSize int
 
+	// CacheSize is the size, in gigabytes, of the WiredTiger cache.
+	// If this is zero, then EnsureServer will calculate a default
+	// size from the memory of the machine. It is ignored by versions
+	// of mongod that do not support WiredTiger.
+	CacheSize int
+
+	// JournalInterval is the maximum time, in milliseconds, between
+	// journal commits. If this is zero, mongod's default is used.
+	JournalInterval int
+
 	// SetNumaControlPolicy preference - whether the user
 	// wants to set the numa control policy when starting mongo.
 	SetNumaControlPolicy bool
@@ -199,7 +209,27 @@ func EnsureServer(args EnsureServerParams) error {
 	}
 	logVersion(mongoPath)
 
-	svcConf := newConf(args.DataDir, dbDir, mongoPath, args.StatePort, oplogSizeMB, args.SetNumaControlPolicy)
+	cacheSizeGB := args.CacheSize
+	if major, err := mongodMajorVersion(mongoPath); err != nil {
+		logger.Infof("cannot determine mongod version, not setting cache size: %v", err)
+		cacheSizeGB = 0
+	} else if major < 3 {
+		if cacheSizeGB != 0 {
+			logger.Infof("mongod version %d does not support WiredTiger, ignoring cache size", major)
+		}
+		cacheSizeGB = 0
+	} else if cacheSizeGB == 0 {
+		if cacheSizeGB, err = defaultCacheSize(); err != nil {
+			logger.Infof("cannot calculate cache size, leaving it to mongod: %v", err)
+			cacheSizeGB = 0
+		}
+	}
+
+	svcConf := newConf(
+		args.DataDir, dbDir, mongoPath, args.StatePort,
+		oplogSizeMB, cacheSizeGB, args.JournalInterval,
+		args.SetNumaControlPolicy,
+	)
 	svc, err := newService(ServiceName(args.Namespace), svcConf)
 	if err != nil {
 		return err
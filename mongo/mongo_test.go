@@ -222,6 +222,39 @@ func (s *MongoSuite) TestEnsureServerNumaCtl(c *gc.C) {
 	s.testEnsureServerNumaCtl(c, true)
 }
 
+func (s *MongoSuite) ensureServerExecStart(c *gc.C, testParams mongo.EnsureServerParams) string {
+	mockShellCommand(c, &s.CleanupSuite, "apt-get")
+	err := mongo.EnsureServer(testParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.data.Installed, gc.HasLen, 1)
+	return s.data.Installed[0].Conf().ExecStart
+}
+
+func (s *MongoSuite) TestEnsureServerTuning(c *gc.C) {
+	s.PatchValue(mongo.MongodMajorVersion, func(string) (int, error) { return 3, nil })
+	testParams := makeEnsureServerParams(c.MkDir(), "namespace")
+	testParams.OplogSize = 2048
+	testParams.CacheSize = 8
+	testParams.JournalInterval = 50
+	execStart := s.ensureServerExecStart(c, testParams)
+	c.Assert(execStart, gc.Matches, `.* --oplogSize 2048 --journalCommitInterval 50 --wiredTigerCacheSizeGB 8$`)
+}
+
+func (s *MongoSuite) TestEnsureServerDefaultCacheSize(c *gc.C) {
+	s.PatchValue(mongo.MongodMajorVersion, func(string) (int, error) { return 3, nil })
+	s.PatchValue(mongo.PhysicalMemoryMB, func() (uint64, error) { return 16 * 1024, nil })
+	execStart := s.ensureServerExecStart(c, makeEnsureServerParams(c.MkDir(), "namespace"))
+	c.Assert(execStart, gc.Matches, `.* --wiredTigerCacheSizeGB 4$`)
+}
+
+func (s *MongoSuite) TestEnsureServerCacheSizeUnsupported(c *gc.C) {
+	// The fake mongod reports version 2.4.9, which predates WiredTiger.
+	testParams := makeEnsureServerParams(c.MkDir(), "namespace")
+	testParams.CacheSize = 8
+	execStart := s.ensureServerExecStart(c, testParams)
+	c.Assert(execStart, gc.Not(gc.Matches), `.*--wiredTigerCacheSizeGB.*`)
+}
+
 func (s *MongoSuite) testEnsureServerNumaCtl(c *gc.C, setNumaPolicy bool) string {
 	dataDir := c.MkDir()
 	dbDir := filepath.Join(dataDir, "db")
@@ -354,28 +387,28 @@ func (s *MongoSuite) TestInstallMongodServiceExists(c *gc.C) {
 func (s *MongoSuite) TestNewServiceWithReplSet(c *gc.C) {
 	dataDir := c.MkDir()
 
-	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
+	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, 0, 0, false)
 	c.Assert(strings.Contains(conf.ExecStart, "--replSet"), jc.IsTrue)
 }
 
 func (s *MongoSuite) TestNewServiceWithNumCtl(c *gc.C) {
 	dataDir := c.MkDir()
 
-	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, true)
+	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, 0, 0, true)
 	c.Assert(conf.ExtraScript, gc.Not(gc.Matches), "")
 }
 
 func (s *MongoSuite) TestNewServiceIPv6(c *gc.C) {
 	dataDir := c.MkDir()
 
-	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
+	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, 0, 0, false)
 	c.Assert(strings.Contains(conf.ExecStart, "--ipv6"), jc.IsTrue)
 }
 
 func (s *MongoSuite) TestNewServiceWithJournal(c *gc.C) {
 	dataDir := c.MkDir()
 
-	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
+	conf := mongo.NewConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, 0, 0, false)
 	c.Assert(conf.ExecStart, gc.Matches, `.* --journal.*`)
 }
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"golang.org/x/net/proxy"
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/cert"
@@ -75,6 +76,13 @@ type Info struct {
 	// CACert holds the CA certificate that will be used
 	// to validate the state server's certificate, in PEM format.
 	CACert string
+
+	// SOCKSProxy, if non-empty, holds the address (host:port) of a
+	// SOCKS5 proxy through which connections to remote MongoDB
+	// servers will be made, such as one set up by "ssh -D" to a
+	// bastion host. Connections to loopback addresses, as made by
+	// agents on the state servers themselves, never use the proxy.
+	SOCKSProxy string
 }
 
 // MongoInfo encapsulates information about cluster of
@@ -98,6 +106,11 @@ func DialInfo(info Info, opts DialOpts) (*mgo.DialInfo, error) {
 	if len(info.Addrs) == 0 {
 		return nil, stderrors.New("no mongo addresses")
 	}
+	for _, addr := range info.Addrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, errors.Annotatef(err, "invalid mongo address %q", addr)
+		}
+	}
 	if len(info.CACert) == 0 {
 		return nil, stderrors.New("missing CA certificate")
 	}
@@ -111,8 +124,19 @@ func DialInfo(info Info, opts DialOpts) (*mgo.DialInfo, error) {
 		RootCAs:    pool,
 		ServerName: "juju-mongodb",
 	}
+	var remoteDialer proxy.Dialer = proxy.Direct
+	if info.SOCKSProxy != "" {
+		remoteDialer, err = proxy.SOCKS5("tcp", info.SOCKSProxy, nil, proxy.Direct)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot use SOCKS proxy %q", info.SOCKSProxy)
+		}
+	}
 	dial := func(addr net.Addr) (net.Conn, error) {
-		c, err := net.Dial("tcp", addr.String())
+		dialer := remoteDialer
+		if isLoopback(addr) {
+			dialer = proxy.Direct
+		}
+		c, err := dialer.Dial("tcp", addr.String())
 		if err != nil {
 			logger.Debugf("connection failed, will retry: %v", err)
 			return nil, err
@@ -134,6 +158,12 @@ func DialInfo(info Info, opts DialOpts) (*mgo.DialInfo, error) {
 	}, nil
 }
 
+// isLoopback reports whether the given address is a loopback address.
+func isLoopback(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
+
 // DialWithInfo establishes a new session to the cluster identified by info,
 // with the specified options.
 func DialWithInfo(info Info, opts DialOpts) (*mgo.Session, error) {
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/mongo"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type OpenSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&OpenSuite{})
+
+func (s *OpenSuite) TestDialInfoIPv6(c *gc.C) {
+	info := mongo.Info{
+		Addrs:  []string{"[::1]:37017", "[2001:db8::1]:37017"},
+		CACert: coretesting.CACert,
+	}
+	dialInfo, err := mongo.DialInfo(info, mongo.DefaultDialOpts())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dialInfo.Addrs, jc.DeepEquals, info.Addrs)
+}
+
+func (s *OpenSuite) TestDialInfoInvalidAddress(c *gc.C) {
+	info := mongo.Info{
+		Addrs:  []string{"::1:37017"},
+		CACert: coretesting.CACert,
+	}
+	_, err := mongo.DialInfo(info, mongo.DefaultDialOpts())
+	c.Assert(err, gc.ErrorMatches, `invalid mongo address "::1:37017": .*too many colons.*`)
+}
+
+func (s *OpenSuite) TestDialInfoSOCKSProxy(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+
+	info := mongo.Info{
+		Addrs:      []string{"10.0.0.1:37017"},
+		CACert:     coretesting.CACert,
+		SOCKSProxy: listener.Addr().String(),
+	}
+	dialInfo, err := mongo.DialInfo(info, mongo.DefaultDialOpts())
+	c.Assert(err, jc.ErrorIsNil)
+
+	done := make(chan error, 1)
+	go func() {
+		addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 37017}
+		_, err := dialInfo.Dial(addr)
+		done <- err
+	}()
+
+	// The connection must be made to the proxy rather than to the
+	// mongo server; closing it without answering the SOCKS handshake
+	// causes the dial to fail.
+	conn, err := listener.Accept()
+	c.Assert(err, jc.ErrorIsNil)
+	conn.Close()
+	select {
+	case err := <-done:
+		c.Assert(err, gc.NotNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for dial to fail")
+	}
+}
+
+func (s *OpenSuite) TestDialInfoSOCKSProxyNotUsedForLoopback(c *gc.C) {
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer proxyListener.Close()
+	mongoListener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer mongoListener.Close()
+
+	info := mongo.Info{
+		Addrs:      []string{mongoListener.Addr().String()},
+		CACert:     coretesting.CACert,
+		SOCKSProxy: proxyListener.Addr().String(),
+	}
+	dialInfo, err := mongo.DialInfo(info, mongo.DefaultDialOpts())
+	c.Assert(err, jc.ErrorIsNil)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := dialInfo.Dial(mongoListener.Addr())
+		done <- err
+	}()
+
+	// The connection must be made directly to the local mongo
+	// server; closing it fails the TLS handshake.
+	conn, err := mongoListener.Accept()
+	c.Assert(err, jc.ErrorIsNil)
+	conn.Close()
+	select {
+	case err := <-done:
+		c.Assert(err, gc.NotNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for dial to fail")
+	}
+}
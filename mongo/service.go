@@ -112,7 +112,10 @@ func sharedSecretPath(dataDir string) string {
 }
 
 // newConf returns the init system config for the mongo state service.
-func newConf(dataDir, dbDir, mongoPath string, port, oplogSizeMB int, wantNumaCtl bool) common.Conf {
+// If cacheSizeGB is zero, mongod is not told the size of the
+// WiredTiger cache; if journalInterval is zero, mongod's default
+// interval between journal commits is used.
+func newConf(dataDir, dbDir, mongoPath string, port, oplogSizeMB, cacheSizeGB, journalInterval int, wantNumaCtl bool) common.Conf {
 	mongoCmd := mongoPath +
 		" --auth" +
 		" --dbpath " + utils.ShQuote(dbDir) +
@@ -128,6 +131,12 @@ func newConf(dataDir, dbDir, mongoPath string, port, oplogSizeMB int, wantNumaCt
 		" --replSet " + ReplicaSetName +
 		" --ipv6" +
 		" --oplogSize " + strconv.Itoa(oplogSizeMB)
+	if journalInterval > 0 {
+		mongoCmd += " --journalCommitInterval " + strconv.Itoa(journalInterval)
+	}
+	if cacheSizeGB > 0 {
+		mongoCmd += " --wiredTigerCacheSizeGB " + strconv.Itoa(cacheSizeGB)
+	}
 	extraScript := ""
 	if wantNumaCtl {
 		extraScript = fmt.Sprintf(detectMultiNodeScript, multinodeVarName, multinodeVarName)
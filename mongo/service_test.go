@@ -27,7 +27,7 @@ func (s *serviceSuite) TestNewConf(c *gc.C) {
 	mongodPath := "/mgo/bin/mongod"
 	port := 12345
 	oplogSizeMB := 10
-	conf := mongo.NewConf(dataDir, dbDir, mongodPath, port, oplogSizeMB, false)
+	conf := mongo.NewConf(dataDir, dbDir, mongodPath, port, oplogSizeMB, 0, 0, false)
 
 	expected := common.Conf{
 		Desc: "juju state database",
@@ -56,6 +56,11 @@ func (s *serviceSuite) TestNewConf(c *gc.C) {
 	c.Check(strings.Fields(conf.ExecStart), jc.DeepEquals, strings.Fields(expected.ExecStart))
 }
 
+func (s *serviceSuite) TestNewConfTuning(c *gc.C) {
+	conf := mongo.NewConf("/var/lib/juju", "/var/lib/juju/db", "/mgo/bin/mongod", 12345, 10, 4, 50, false)
+	c.Check(conf.ExecStart, gc.Matches, `.* --oplogSize 10 --journalCommitInterval 50 --wiredTigerCacheSizeGB 4$`)
+}
+
 func (s *serviceSuite) TestIsServiceInstalledWhenInstalled(c *gc.C) {
 	namespace := "some-namespace"
 	svcData := svctesting.NewFakeServiceData()
@@ -161,6 +161,14 @@ azure:
     #
     storage-account-name: abcdefghijkl
 
+    # availability-sets-enabled places the instances of each service in
+    # a single Azure availability set, so that they are spread across
+    # fault and update domains. Units must then be placed automatically
+    # rather than with --to. It cannot be changed once the environment
+    # has been prepared.
+    #
+    # availability-sets-enabled: true
+
     # force-image-name overrides the OS image selection to use a fixed
     # image for all deployments. Most useful for developers.
     #
@@ -95,12 +95,16 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	maybeSetBridge(instanceConfig)
 
 	fmt.Fprintln(ctx.GetStderr(), "Launching instance")
-	result, err := env.StartInstance(environs.StartInstanceParams{
+	startParams := environs.StartInstanceParams{
 		Constraints:    args.Constraints,
 		Tools:          availableTools,
 		InstanceConfig: instanceConfig,
 		Placement:      args.Placement,
-	})
+	}
+	if progress, ok := ctx.(environs.BootstrapProgress); ok {
+		startParams.ImageSelected = progress.ImageSelected
+	}
+	result, err := env.StartInstance(startParams)
 	if err != nil {
 		return nil, "", nil, errors.Annotate(err, "cannot start bootstrap instance")
 	}
@@ -113,6 +117,20 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 			return err
 		}
 		maybeSetBridge(icfg)
+		if reader, ok := environs.SupportsConsoleOutput(env); ok {
+			// Report the progress of cloud-init while waiting
+			// for the instance to accept the SSH connection.
+			stop := make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				followConsole(reader, result.Instance.Id(), ctx.GetStderr(), stop)
+			}()
+			defer func() {
+				close(stop)
+				<-done
+			}()
+		}
 		return FinishBootstrap(ctx, client, result.Instance, icfg)
 	}
 	return result, series, finalize, nil
@@ -155,6 +173,9 @@ var FinishBootstrap = func(ctx environs.BootstrapContext, client ssh.Client, ins
 	if err != nil {
 		return err
 	}
+	if err := checkBootstrapHost(ctx, client, addr); err != nil {
+		return err
+	}
 	return ConfigureMachine(ctx, client, addr, instanceConfig)
 }
 
@@ -186,13 +207,19 @@ func ConfigureMachine(ctx environs.BootstrapContext, client ssh.Client, host str
 		return err
 	}
 	script := shell.DumpFileOnErrorScript(instanceConfig.CloudInitOutputLog) + configScript
-	return sshinit.RunConfigureScript(script, sshinit.ConfigureParams{
+	params := sshinit.ConfigureParams{
 		Host:           "ubuntu@" + host,
 		Client:         client,
 		Config:         cloudcfg,
 		ProgressWriter: ctx.GetStderr(),
 		Series:         instanceConfig.Series,
-	})
+	}
+	// Tools built locally are carried to the instance by the script.
+	progress, ok := ctx.(environs.BootstrapProgress)
+	if ok && instanceConfig.Tools != nil && strings.HasPrefix(instanceConfig.Tools.URL, "file://") {
+		params.ScriptSent = progress.ToolsUploaded
+	}
+	return sshinit.RunConfigureScript(script, params)
 }
 
 type addresser interface {
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+// consolePollDelay is the time to wait between requests for the
+// console output of the bootstrap instance.
+var consolePollDelay = 10 * time.Second
+
+// consoleProgress matches the lines of console output that report the
+// progress of cloud-init.
+var consoleProgress = regexp.MustCompile(`(?i)cloud-init`)
+
+// followConsole writes to w each line of the console output of the
+// given instance that reports the progress of cloud-init, as it
+// appears, until stop is closed.
+func followConsole(reader environs.ConsoleOutputReader, id instance.Id, w io.Writer, stop <-chan struct{}) {
+	var previous []string
+	for {
+		output, err := reader.ConsoleOutput(id)
+		if err != nil {
+			logger.Debugf("cannot get console output of %s: %v", id, err)
+		} else {
+			current := completeLines(output)
+			for _, line := range newConsoleLines(previous, current) {
+				if consoleProgress.MatchString(line) {
+					fmt.Fprintf(w, "Console: %s\n", strings.TrimSpace(line))
+				}
+			}
+			previous = current
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(consolePollDelay):
+		}
+	}
+}
+
+// completeLines returns the complete lines of the given console
+// output. A final line without a newline may still be being written,
+// so it is left out until it is complete; otherwise it would not
+// match the complete line in the next output.
+func completeLines(output string) []string {
+	end := strings.LastIndex(output, "\n")
+	if end < 0 {
+		return nil
+	}
+	return strings.Split(output[:end], "\n")
+}
+
+// newConsoleLines returns the lines of the current console output that
+// follow those of the previous output. Clouds may discard the start of
+// a long console output, so the previous lines are matched against the
+// start of the current ones by their longest overlap.
+func newConsoleLines(previous, current []string) []string {
+	n := len(previous)
+	if n > len(current) {
+		n = len(current)
+	}
+	for ; n > 0; n-- {
+		if linesEqual(previous[len(previous)-n:], current[:n]) {
+			return current[n:]
+		}
+	}
+	return current
+}
+
+func linesEqual(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type consoleSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&consoleSuite{})
+
+func (s *consoleSuite) TestNewConsoleLines(c *gc.C) {
+	for i, test := range []struct {
+		about    string
+		previous []string
+		current  []string
+		expected []string
+	}{{
+		about:    "no previous output",
+		current:  []string{"a", "b"},
+		expected: []string{"a", "b"},
+	}, {
+		about:    "output appended",
+		previous: []string{"a", "b"},
+		current:  []string{"a", "b", "c"},
+		expected: []string{"c"},
+	}, {
+		about:    "start of output discarded",
+		previous: []string{"a", "b", "c"},
+		current:  []string{"b", "c", "d", "e"},
+		expected: []string{"d", "e"},
+	}, {
+		about:    "unchanged",
+		previous: []string{"a", "b"},
+		current:  []string{"a", "b"},
+		expected: []string{},
+	}, {
+		about:    "no overlap",
+		previous: []string{"a", "b"},
+		current:  []string{"x", "y"},
+		expected: []string{"x", "y"},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		c.Check(common.NewConsoleLines(test.previous, test.current), jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *consoleSuite) TestCompleteLines(c *gc.C) {
+	c.Check(common.CompleteLines(""), gc.HasLen, 0)
+	c.Check(common.CompleteLines("partial"), gc.HasLen, 0)
+	c.Check(common.CompleteLines("a\nb\n"), jc.DeepEquals, []string{"a", "b"})
+	c.Check(common.CompleteLines("a\nb\npart"), jc.DeepEquals, []string{"a", "b"})
+}
+
+type fakeConsoleReader struct {
+	outputs []string
+	calls   chan struct{}
+}
+
+func (r *fakeConsoleReader) ConsoleOutput(id instance.Id) (string, error) {
+	defer func() { r.calls <- struct{}{} }()
+	if len(r.outputs) == 0 {
+		return "", errors.New("no more output")
+	}
+	output := r.outputs[0]
+	r.outputs = r.outputs[1:]
+	return output, nil
+}
+
+func (s *consoleSuite) TestFollowConsole(c *gc.C) {
+	s.PatchValue(common.ConsolePollDelay, time.Millisecond)
+	reader := &fakeConsoleReader{
+		outputs: []string{
+			"[    0.000000] Linux version 3.13.0\nCloud-init v. 0.7.5 running 'init-local'\nCloud-in",
+			"[    0.000000] Linux version 3.13.0\nCloud-init v. 0.7.5 running 'init-local'\n" +
+				"eth0: link up\nCloud-init v. 0.7.5 running 'modules:final'\n",
+		},
+		calls: make(chan struct{}),
+	}
+	var stderr bytes.Buffer
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		common.FollowConsole(reader, "inst-0", &stderr, stop)
+	}()
+	// Wait for both outputs, and a failed request, to be read.
+	for i := 0; i < 3; i++ {
+		select {
+		case <-reader.calls:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for console output request")
+		}
+	}
+	close(stop)
+	go func() {
+		for _ = range reader.calls {
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for followConsole to stop")
+	}
+	close(reader.calls)
+	c.Assert(stderr.String(), gc.Equals, ""+
+		"Console: Cloud-init v. 0.7.5 running 'init-local'\n"+
+		"Console: Cloud-init v. 0.7.5 running 'modules:final'\n",
+	)
+}
@@ -7,4 +7,10 @@ var (
 	ConnectSSH                          = &connectSSH
 	WaitSSH                             = waitSSH
 	InternalAvailabilityZoneAllocations = &internalAvailabilityZoneAllocations
+	CheckPreflightOutput                = checkPreflightOutput
+	MaxBootstrapClockSkew               = &maxBootstrapClockSkew
+	ConsolePollDelay                    = &consolePollDelay
+	FollowConsole                       = followConsole
+	NewConsoleLines                     = newConsoleLines
+	CompleteLines                       = completeLines
 )
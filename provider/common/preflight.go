@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/utils/ssh"
+)
+
+// maxBootstrapClockSkew is the largest difference tolerated between the
+// clocks of the client and the bootstrap instance. Larger differences
+// cause certificate validation and mongo replica set failures that are
+// hard to diagnose once bootstrap has moved on.
+var maxBootstrapClockSkew = time.Hour
+
+// preflightCheckScript is run on the bootstrap instance to report its
+// clock and any problems with its DNS configuration. Each line of
+// output is a keyword followed by its details.
+const preflightCheckScript = `
+echo "time $(date -u +%s)"
+if ! grep -q '^nameserver' /etc/resolv.conf 2>/dev/null; then
+	echo "no-nameserver"
+fi
+if ! getent hosts "$(hostname)" >/dev/null; then
+	echo "unresolvable-hostname $(hostname)"
+fi
+`
+
+// runPreflightCheckScript runs preflightCheckScript on the given host
+// and returns its output.
+//
+// Note: runPreflightCheckScript is exposed so it can be replaced for
+// testing.
+var runPreflightCheckScript = func(client ssh.Client, host string) (string, error) {
+	cmd := client.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd.Stdin = strings.NewReader(preflightCheckScript)
+	output, err := cmd.CombinedOutput()
+	if err != nil && len(output) > 0 {
+		err = fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return string(output), err
+}
+
+// checkBootstrapHost checks the bootstrap instance at the given
+// address for clock skew, which would otherwise cause obscure failures
+// later in the bootstrap process. Broken DNS does not always stop
+// bootstrap, for example when the instance is reached by address, so
+// it is reported as a warning.
+func checkBootstrapHost(ctx environs.BootstrapContext, client ssh.Client, host string) error {
+	logger.Infof("checking clock and DNS on bootstrap instance %s", host)
+	sent := time.Now()
+	output, err := runPreflightCheckScript(client, host)
+	if err != nil {
+		return errors.Annotate(err, "cannot run preflight checks on bootstrap instance")
+	}
+	warnings, err := checkPreflightOutput(output, sent, time.Now())
+	for _, warning := range warnings {
+		fmt.Fprintf(ctx.GetStderr(), "WARNING: %s\n", warning)
+	}
+	return err
+}
+
+// checkPreflightOutput checks the output of preflightCheckScript, which
+// was sent to the bootstrap instance at the sent time and whose output
+// was received at the received time. It returns warnings of problems
+// that may not stop bootstrap, and an error for those that will.
+func checkPreflightOutput(output string, sent, received time.Time) (warnings []string, err error) {
+	var remoteTime *time.Time
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "time":
+			if len(fields) != 2 {
+				return nil, errors.Errorf("unexpected preflight check output %q", scanner.Text())
+			}
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return nil, errors.Annotate(err, "cannot parse bootstrap instance time")
+			}
+			t := time.Unix(seconds, 0)
+			remoteTime = &t
+		case "no-nameserver":
+			warnings = append(warnings, "bootstrap instance has no DNS nameservers configured in /etc/resolv.conf; "+
+				"check the network configuration of the cloud's instances")
+		case "unresolvable-hostname":
+			hostname := strings.Join(fields[1:], " ")
+			warnings = append(warnings, fmt.Sprintf("bootstrap instance cannot resolve its own hostname %q; "+
+				"check the cloud's DNS configuration or add the hostname to /etc/hosts in the image", hostname))
+		default:
+			logger.Debugf("ignoring unexpected preflight check output %q", scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if remoteTime == nil {
+		return nil, errors.New("bootstrap instance did not report its time")
+	}
+	// The remote time was taken some time between sending the script
+	// and receiving its output, and is truncated to the second.
+	earliest := sent.Add(-time.Second)
+	switch {
+	case remoteTime.Before(earliest.Add(-maxBootstrapClockSkew)):
+		return warnings, clockSkewError(earliest.Sub(*remoteTime), "behind")
+	case remoteTime.After(received.Add(maxBootstrapClockSkew)):
+		return warnings, clockSkewError(remoteTime.Sub(received), "ahead of")
+	}
+	return warnings, nil
+}
+
+func clockSkewError(skew time.Duration, relation string) error {
+	return errors.Errorf("bootstrap instance clock is %v %s this machine's clock (at most %v is allowed); "+
+		"check that both clocks are correct, for example using NTP, and try again",
+		skew-skew%time.Second, relation, maxBootstrapClockSkew)
+}
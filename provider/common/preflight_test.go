@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type PreflightSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&PreflightSuite{})
+
+func (s *PreflightSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(common.MaxBootstrapClockSkew, time.Hour)
+}
+
+func timeOutput(t time.Time) string {
+	return fmt.Sprintf("time %d\n", t.Unix())
+}
+
+func (s *PreflightSuite) TestCheckPreflightOutput(c *gc.C) {
+	sent := time.Date(2015, time.June, 1, 12, 0, 0, 0, time.UTC)
+	received := sent.Add(2 * time.Second)
+	for i, test := range []struct {
+		about    string
+		output   string
+		warnings []string
+		err      string
+	}{{
+		about:  "clocks agree",
+		output: timeOutput(sent.Add(time.Second)),
+	}, {
+		about:  "small skew is tolerated",
+		output: timeOutput(sent.Add(-30 * time.Minute)),
+	}, {
+		about:  "instance clock behind",
+		output: timeOutput(sent.Add(-3 * time.Hour)),
+		err:    `bootstrap instance clock is 2h59m59s behind this machine's clock \(at most 1h0m0s is allowed\); check that both clocks are correct.*`,
+	}, {
+		about:  "instance clock ahead",
+		output: timeOutput(received.Add(2 * time.Hour)),
+		err:    `bootstrap instance clock is 2h0m0s ahead of this machine's clock .*`,
+	}, {
+		about:    "no nameservers",
+		output:   timeOutput(sent) + "no-nameserver\n",
+		warnings: []string{"bootstrap instance has no DNS nameservers configured in /etc/resolv.conf; check the network configuration of the cloud's instances"},
+	}, {
+		about:    "unresolvable hostname",
+		output:   timeOutput(sent) + "unresolvable-hostname juju-machine-0\n",
+		warnings: []string{`bootstrap instance cannot resolve its own hostname "juju-machine-0"; check the cloud's DNS configuration or add the hostname to /etc/hosts in the image`},
+	}, {
+		about:  "missing time",
+		output: "",
+		err:    "bootstrap instance did not report its time",
+	}, {
+		about:  "invalid time",
+		output: "time yesterday\n",
+		err:    `cannot parse bootstrap instance time: .*`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		warnings, err := common.CheckPreflightOutput(test.output, sent, received)
+		c.Check(warnings, jc.DeepEquals, test.warnings)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
@@ -781,6 +781,12 @@ func (e *environ) StateServerInstances() ([]instance.Id, error) {
 	return stateServerInstances, nil
 }
 
+// VerifyCredentials is specified in the environs.CredentialsVerifier
+// interface.
+func (e *environ) VerifyCredentials() error {
+	return e.checkBroken("VerifyCredentials")
+}
+
 func (e *environ) Config() *config.Config {
 	return e.ecfg().Config
 }
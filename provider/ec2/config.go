@@ -31,6 +31,15 @@ amazon:
     #
     # secret-key: <secret>
 
+    # vpc-id specifies the id of a VPC into which all instances will
+    # be started. Instances are placed in the VPC's subnets, spread
+    # across availability zones; a specific subnet can be chosen with
+    # the "subnet=<id or CIDR>" placement directive. If omitted, the
+    # account's default VPC (or EC2-Classic) is used. It cannot be
+    # changed once the environment has been bootstrapped.
+    #
+    # vpc-id: vpc-xxxxxxxx
+
     # image-stream chooses a simplestreams stream from which to select
     # OS images, for example daily or released images (or any other stream
     # available on simplestreams).
@@ -64,6 +73,7 @@ var configFields = schema.Fields{
 	"secret-key":     schema.String(),
 	"region":         schema.String(),
 	"control-bucket": schema.String(),
+	"vpc-id":         schema.String(),
 }
 
 var configDefaults = schema.Defaults{
@@ -71,6 +81,7 @@ var configDefaults = schema.Defaults{
 	"secret-key":     "",
 	"region":         "us-east-1",
 	"control-bucket": "",
+	"vpc-id":         "",
 }
 
 type environConfig struct {
@@ -86,6 +97,10 @@ func (c *environConfig) controlBucket() string {
 	return c.attrs["control-bucket"].(string)
 }
 
+func (c *environConfig) vpcID() string {
+	return c.attrs["vpc-id"].(string)
+}
+
 func (c *environConfig) accessKey() string {
 	return c.attrs["access-key"].(string)
 }
@@ -146,6 +161,9 @@ func validateConfig(cfg, old *config.Config) (*environConfig, error) {
 		if bucket, _ := attrs["control-bucket"].(string); ecfg.controlBucket() != bucket {
 			return nil, fmt.Errorf("cannot change control-bucket from %q to %q", bucket, ecfg.controlBucket())
 		}
+		if vpcID, _ := attrs["vpc-id"].(string); ecfg.vpcID() != vpcID {
+			return nil, fmt.Errorf("cannot change vpc-id from %q to %q", vpcID, ecfg.vpcID())
+		}
 	}
 
 	// ssl-hostname-verification cannot be disabled
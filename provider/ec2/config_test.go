@@ -54,6 +54,7 @@ type configTest struct {
 	secretKey          string
 	firewallMode       string
 	blockStorageSource string
+	vpcID              string
 	err                string
 }
 
@@ -113,6 +114,7 @@ func (t configTest) check(c *gc.C) {
 	if t.firewallMode != "" {
 		c.Assert(ecfg.FirewallMode(), gc.Equals, t.firewallMode)
 	}
+	c.Assert(ecfg.vpcID(), gc.Equals, t.vpcID)
 	for name, expect := range t.expect {
 		actual, found := ecfg.UnknownAttrs()[name]
 		c.Check(found, jc.IsTrue)
@@ -154,6 +156,19 @@ var configTests = []configTest{
 			"region": "us-east-1",
 		},
 		err: `.*cannot change region from "configtest" to "us-east-1"`,
+	}, {
+		config: attrs{
+			"vpc-id": "vpc-abcd1234",
+		},
+		vpcID: "vpc-abcd1234",
+	}, {
+		config: attrs{
+			"vpc-id": "vpc-abcd1234",
+		},
+		change: attrs{
+			"vpc-id": "vpc-dcba4321",
+		},
+		err: `.*cannot change vpc-id from "vpc-abcd1234" to "vpc-dcba4321"`,
 	}, {
 		config: attrs{
 			"region": 666,
@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+// consoleOutputAPIVersion is the EC2 API version used to request
+// console output; amz does not wrap the GetConsoleOutput action.
+const consoleOutputAPIVersion = "2014-10-01"
+
+var _ environs.ConsoleOutputReader = (*environ)(nil)
+
+// ConsoleOutput is specified in the environs.ConsoleOutputReader
+// interface. EC2 only captures the most recent 64KiB of an instance's
+// console output, and updates it every few minutes.
+func (e *environ) ConsoleOutput(id instance.Id) (string, error) {
+	return consoleOutput(e.ec2(), id)
+}
+
+type consoleOutputResp struct {
+	Output string `xml:"output"`
+}
+
+type consoleOutputErrors struct {
+	Errors []struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Errors>Error"`
+}
+
+func consoleOutput(client *ec2.EC2, id instance.Id) (string, error) {
+	params := url.Values{
+		"Action":     {"GetConsoleOutput"},
+		"InstanceId": {string(id)},
+		"Version":    {consoleOutputAPIVersion},
+	}
+	req, err := http.NewRequest("POST", client.Region.EC2Endpoint+"/", strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Amz-Date", time.Now().UTC().Format(aws.ISO8601BasicFormat))
+	if err := client.Sign(req, client.Auth); err != nil {
+		return "", errors.Annotate(err, "cannot sign console output request")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot get console output of %s", id)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errs consoleOutputErrors
+		if err := xml.NewDecoder(resp.Body).Decode(&errs); err == nil && len(errs.Errors) > 0 {
+			return "", errors.Errorf("cannot get console output of %s: %s (%s)", id, errs.Errors[0].Message, errs.Errors[0].Code)
+		}
+		return "", errors.Errorf("cannot get console output of %s: %s", id, resp.Status)
+	}
+	var result consoleOutputResp
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", errors.Annotate(err, "cannot parse console output")
+	}
+	output, err := base64.StdEncoding.DecodeString(strings.TrimSpace(result.Output))
+	if err != nil {
+		return "", errors.Annotate(err, "cannot decode console output")
+	}
+	return string(output), nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/aws"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+)
+
+type consoleSuite struct{}
+
+var _ = gc.Suite(&consoleSuite{})
+
+func (*consoleSuite) client(url string) *amzec2.EC2 {
+	auth := aws.Auth{"access-key", "secret-key"}
+	region := aws.Region{Name: "test", EC2Endpoint: url}
+	return amzec2.New(auth, region, aws.SignV4Factory(region.Name, "ec2"))
+}
+
+func (s *consoleSuite) TestConsoleOutput(c *gc.C) {
+	output := "Cloud-init v. 0.7.5 running 'init-local'\n"
+	var form map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		req.ParseForm()
+		form = req.PostForm
+		fmt.Fprintf(w, `<GetConsoleOutputResponse>
+  <instanceId>i-28a64341</instanceId>
+  <output>%s</output>
+</GetConsoleOutputResponse>`, base64.StdEncoding.EncodeToString([]byte(output)))
+	}))
+	defer srv.Close()
+
+	got, err := consoleOutput(s.client(srv.URL), "i-28a64341")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, output)
+	c.Assert(form["Action"], jc.DeepEquals, []string{"GetConsoleOutput"})
+	c.Assert(form["InstanceId"], jc.DeepEquals, []string{"i-28a64341"})
+}
+
+func (s *consoleSuite) TestConsoleOutputError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error>
+  <Code>InvalidInstanceID.NotFound</Code>
+  <Message>The instance ID 'i-28a64341' does not exist</Message>
+</Error></Errors></Response>`)
+	}))
+	defer srv.Close()
+
+	_, err := consoleOutput(s.client(srv.URL), "i-28a64341")
+	c.Assert(err, gc.ErrorMatches, `cannot get console output of i-28a64341: The instance ID 'i-28a64341' does not exist \(InvalidInstanceID.NotFound\)`)
+}
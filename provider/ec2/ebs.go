@@ -10,12 +10,14 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
 	"gopkg.in/amz.v3/ec2"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/poolmanager"
 )
@@ -47,6 +49,12 @@ const (
 	EBS_AvailabilityZone = "availability-zone"
 )
 
+const (
+	// tagName is the name of the tag that AWS displays as the
+	// name of a resource.
+	tagName = "Name"
+)
+
 // AWS error codes
 const (
 	deviceInUse        = "InvalidDevice.InUse"
@@ -153,7 +161,13 @@ func (e *ebsProvider) VolumeSource(environConfig *config.Config, providerConfig
 	if err != nil {
 		return nil, errors.Annotate(err, "creating AWS clients")
 	}
-	return &ebsVolumeSource{ec2}, nil
+	envUUID, _ := environConfig.UUID()
+	source := &ebsVolumeSource{
+		ec2:     ec2,
+		envName: environConfig.Name(),
+		envUUID: envUUID,
+	}
+	return source, nil
 }
 
 // FilesystemSource is defined on the Provider interface.
@@ -162,7 +176,9 @@ func (e *ebsProvider) FilesystemSource(environConfig *config.Config, providerCon
 }
 
 type ebsVolumeSource struct {
-	ec2 *ec2.EC2
+	ec2     *ec2.EC2
+	envName string
+	envUUID string
 }
 
 var _ storage.VolumeSource = (*ebsVolumeSource)(nil)
@@ -257,6 +273,11 @@ func (v *ebsVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []stor
 			return nil, nil, err
 		}
 		volumeId := resp.Id
+		if err := tagResources(v.ec2, v.volumeTags(p.Tag), volumeId); err != nil {
+			// Record the volume so that it is cleaned up.
+			volumes = append(volumes, storage.Volume{Tag: p.Tag, VolumeId: volumeId})
+			return nil, nil, errors.Annotatef(err, "tagging volume %v", volumeId)
+		}
 		volumes = append(volumes, storage.Volume{
 			Tag:        p.Tag,
 			VolumeId:   volumeId,
@@ -298,6 +319,39 @@ func (v *ebsVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []stor
 	return volumes, volumeAttachments, nil
 }
 
+// volumeTags returns the tags to apply to the EBS volume created
+// for the volume with the given tag, so that it can be identified
+// in the AWS console and traced back to its environment.
+func (v *ebsVolumeSource) volumeTags(tag names.VolumeTag) map[string]string {
+	result := map[string]string{
+		tagName: "juju-" + v.envName + "-" + tag.String(),
+	}
+	if v.envUUID != "" {
+		result[tags.JujuEnv] = v.envUUID
+	}
+	return result
+}
+
+// tagResources applies the given tags to each of the specified EC2
+// resources, retrying while newly created resources become visible.
+func tagResources(e *ec2.EC2, tags map[string]string, resourceIds ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	ec2Tags := make([]ec2.Tag, 0, len(tags))
+	for k, v := range tags {
+		ec2Tags = append(ec2Tags, ec2.Tag{Key: k, Value: v})
+	}
+	var err error
+	for a := shortAttempt.Start(); a.Next(); {
+		_, err = e.CreateTags(resourceIds, ec2Tags)
+		if err == nil || !strings.HasSuffix(ec2ErrCode(err), ".NotFound") {
+			break
+		}
+	}
+	return err
+}
+
 // DescribeVolumes is specified on the storage.VolumeSource interface.
 func (v *ebsVolumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
 	resp, err := v.ec2.Volumes(volIds, nil)
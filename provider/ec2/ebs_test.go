@@ -211,6 +211,22 @@ func (s *ebsVolumeSuite) TestCreateVolumes(c *gc.C) {
 	s.assertCreateVolumes(c, vs, "us-east-1")
 }
 
+func (s *ebsVolumeSuite) TestVolumeTags(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	s.assertCreateVolumes(c, vs, "us-east-1")
+	ec2Client := ec2.StorageEC2(vs)
+	ec2Vols, err := ec2Client.Volumes(nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ec2Vols.Volumes, gc.HasLen, 3)
+	sortBySize(ec2Vols.Volumes)
+	for i, vol := range ec2Vols.Volumes {
+		c.Check(vol.Tags, jc.SameContents, []awsec2.Tag{
+			{"Name", "juju-sample-volume-" + strconv.Itoa(i)},
+			{"juju-env-uuid", testing.EnvironmentTag.Id()},
+		})
+	}
+}
+
 func (s *ebsVolumeSuite) TestDeleteVolumes(c *gc.C) {
 	vs := s.volumeSource(c, nil)
 	s.assertCreateVolumes(c, vs, "us-east-1")
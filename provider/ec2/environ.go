@@ -6,6 +6,7 @@ package ec2
 import (
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -89,6 +90,12 @@ func assignPrivateIPAddress(ec2Inst *ec2.EC2, netId string, addr network.Address
 	return err
 }
 
+// VerifyCredentials is specified in the environs.CredentialsVerifier
+// interface.
+func (e *environ) VerifyCredentials() error {
+	return verifyCredentials(e)
+}
+
 func (e *environ) Config() *config.Config {
 	return e.ecfg().Config
 }
@@ -321,6 +328,10 @@ func (e *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, er
 
 type ec2Placement struct {
 	availabilityZone ec2.AvailabilityZoneInfo
+
+	// subnet is non-nil if the placement directive names a
+	// subnet in the environment's VPC.
+	subnet *ec2.Subnet
 }
 
 func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
@@ -330,23 +341,81 @@ func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
 	}
 	switch key, value := placement[:pos], placement[pos+1:]; key {
 	case "zone":
-		availabilityZone := value
-		zones, err := e.AvailabilityZones()
+		zone, err := e.availabilityZoneInfo(value)
+		if err != nil {
+			return nil, err
+		}
+		return &ec2Placement{availabilityZone: zone}, nil
+	case "subnet":
+		subnets, err := e.vpcSubnets()
 		if err != nil {
 			return nil, err
 		}
-		for _, z := range zones {
-			if z.Name() == availabilityZone {
-				return &ec2Placement{
-					z.(*ec2AvailabilityZone).AvailabilityZoneInfo,
-				}, nil
+		for _, subnet := range subnets {
+			if subnet.Id != value && subnet.CIDRBlock != value {
+				continue
+			}
+			zone, err := e.availabilityZoneInfo(subnet.AvailZone)
+			if err != nil {
+				return nil, err
 			}
+			subnet := subnet
+			return &ec2Placement{availabilityZone: zone, subnet: &subnet}, nil
 		}
-		return nil, fmt.Errorf("invalid availability zone %q", availabilityZone)
+		return nil, fmt.Errorf("invalid subnet %q in VPC %q", value, e.ecfg().vpcID())
 	}
 	return nil, fmt.Errorf("unknown placement directive: %v", placement)
 }
 
+// availabilityZoneInfo returns information on the availability
+// zone with the given name.
+func (e *environ) availabilityZoneInfo(name string) (ec2.AvailabilityZoneInfo, error) {
+	zones, err := e.AvailabilityZones()
+	if err != nil {
+		return ec2.AvailabilityZoneInfo{}, err
+	}
+	for _, z := range zones {
+		if z.Name() == name {
+			return z.(*ec2AvailabilityZone).AvailabilityZoneInfo, nil
+		}
+	}
+	return ec2.AvailabilityZoneInfo{}, fmt.Errorf("invalid availability zone %q", name)
+}
+
+// vpcSubnets returns the available subnets in the VPC named by
+// the vpc-id configuration attribute.
+func (e *environ) vpcSubnets() ([]ec2.Subnet, error) {
+	vpcID := e.ecfg().vpcID()
+	if vpcID == "" {
+		return nil, errors.New("subnet placement requires vpc-id to be set")
+	}
+	filter := ec2.NewFilter()
+	filter.Add("vpc-id", vpcID)
+	filter.Add("state", "available")
+	resp, err := e.ec2().Subnets(nil, filter)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot list subnets in VPC %q", vpcID)
+	}
+	return resp.Subnets, nil
+}
+
+// vpcSubnetsByZone returns the ids of the available subnets in the
+// environment's VPC, keyed by availability zone.
+func (e *environ) vpcSubnetsByZone() (map[string][]string, error) {
+	subnets, err := e.vpcSubnets()
+	if err != nil {
+		return nil, err
+	}
+	byZone := make(map[string][]string)
+	for _, subnet := range subnets {
+		byZone[subnet.AvailZone] = append(byZone[subnet.AvailZone], subnet.Id)
+	}
+	for _, ids := range byZone {
+		sort.Strings(ids)
+	}
+	return byZone, nil
+}
+
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (e *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
 	if placement != "" {
@@ -424,16 +493,76 @@ func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
 
 // StartInstance is specified in the InstanceBroker interface.
 func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	availabilityZones, subnetsByZone, err := e.startInstanceZones(args)
+	if err != nil {
+		return nil, err
+	}
+	if args.InstanceConfig.HasNetworks() {
+		return nil, errors.New("starting instances with networks is not supported yet")
+	}
+	spec, err := e.startInstanceSpec(args)
+	if err != nil {
+		return nil, err
+	}
+	if args.ImageSelected != nil {
+		args.ImageSelected(spec.Image.Id)
+	}
+	if err := e.finishInstanceConfig(args, spec); err != nil {
+		return nil, err
+	}
+
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot make user data")
+	}
+	logger.Debugf("ec2 user data; %d bytes", len(userData))
+	groups, err := e.setUpGroups(args.InstanceConfig.MachineId, e.Config().APIPort())
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot set up groups")
+	}
+	blockDeviceMappings, err := getBlockDeviceMappings(args.Constraints)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create block device mappings")
+	}
+
+	instResp, err := e.runInstancesInZones(availabilityZones, subnetsByZone, &ec2.RunInstances{
+		ImageId:             spec.Image.Id,
+		MinCount:            1,
+		MaxCount:            1,
+		UserData:            userData,
+		InstanceType:        spec.InstanceType.Name,
+		SecurityGroups:      groups,
+		BlockDeviceMappings: blockDeviceMappings,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot run instances")
+	}
+	if len(instResp.Instances) != 1 {
+		return nil, errors.Errorf("expected 1 started instance, got %d", len(instResp.Instances))
+	}
+	return e.startedInstance(args, &instResp.Instances[0], spec, blockDeviceMappings), nil
+}
+
+// startInstanceZones returns the availability zones in which the
+// instance with the given params may be started, in order of
+// preference, and the subnets in each zone that it may be started in.
+func (e *environ) startInstanceZones(args environs.StartInstanceParams) ([]string, map[string][]string, error) {
 	var availabilityZones []string
+	var subnetsByZone map[string][]string
 	if args.Placement != "" {
 		placement, err := e.parsePlacement(args.Placement)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if placement.availabilityZone.State != "available" {
-			return nil, errors.Errorf("availability zone %q is %s", placement.availabilityZone.Name, placement.availabilityZone.State)
+			return nil, nil, errors.Errorf("availability zone %q is %s", placement.availabilityZone.Name, placement.availabilityZone.State)
 		}
 		availabilityZones = append(availabilityZones, placement.availabilityZone.Name)
+		if placement.subnet != nil {
+			subnetsByZone = map[string][]string{
+				placement.availabilityZone.Name: {placement.subnet.Id},
+			}
+		}
 	}
 
 	// If no availability zone is specified, then automatically spread across
@@ -445,101 +574,124 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		if args.DistributionGroup != nil {
 			group, err = args.DistributionGroup()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 		zoneInstances, err := availabilityZoneAllocations(e, group)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		for _, z := range zoneInstances {
 			availabilityZones = append(availabilityZones, z.ZoneName)
 		}
 		if len(availabilityZones) == 0 {
-			return nil, errors.New("failed to determine availability zones")
+			return nil, nil, errors.New("failed to determine availability zones")
 		}
 	}
 
-	if args.InstanceConfig.HasNetworks() {
-		return nil, errors.New("starting instances with networks is not supported yet")
+	// Instances in a VPC must be started in one of its subnets, so
+	// only zones containing a subnet can be used.
+	if vpcID := e.ecfg().vpcID(); vpcID != "" {
+		if subnetsByZone == nil {
+			var err error
+			subnetsByZone, err = e.vpcSubnetsByZone()
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		var zonesWithSubnets []string
+		for _, zone := range availabilityZones {
+			if len(subnetsByZone[zone]) > 0 {
+				zonesWithSubnets = append(zonesWithSubnets, zone)
+			}
+		}
+		if len(zonesWithSubnets) == 0 {
+			return nil, nil, errors.Errorf("no subnets in VPC %q in availability zones %v", vpcID, availabilityZones)
+		}
+		availabilityZones = zonesWithSubnets
 	}
-	arches := args.Tools.Arches()
+	return availabilityZones, subnetsByZone, nil
+}
+
+// startInstanceSpec returns the image and instance type with which
+// to start the instance with the given params.
+func (e *environ) startInstanceSpec(args environs.StartInstanceParams) (*instances.InstanceSpec, error) {
 	sources, err := environs.ImageMetadataSources(e)
 	if err != nil {
 		return nil, err
 	}
-
-	series := args.Tools.OneSeries()
-	spec, err := findInstanceSpec(sources, e.Config().ImageStream(), &instances.InstanceConstraint{
+	return findInstanceSpec(sources, e.Config().ImageStream(), &instances.InstanceConstraint{
 		Region:      e.ecfg().region(),
-		Series:      series,
-		Arches:      arches,
+		Series:      args.Tools.OneSeries(),
+		Arches:      args.Tools.Arches(),
 		Constraints: args.Constraints,
 		Storage:     []string{ssdStorage, ebsStorage},
 	})
-	if err != nil {
-		return nil, err
-	}
+}
+
+// finishInstanceConfig chooses the tools matching the image in spec
+// for the instance with the given params, and completes its instance
+// configuration.
+func (e *environ) finishInstanceConfig(args environs.StartInstanceParams, spec *instances.InstanceSpec) error {
 	tools, err := args.Tools.Match(tools.Filter{Arch: spec.Image.Arch})
 	if err != nil {
-		return nil, errors.Errorf("chosen architecture %v not present in %v", spec.Image.Arch, arches)
+		return errors.Errorf("chosen architecture %v not present in %v", spec.Image.Arch, args.Tools.Arches())
 	}
-
 	args.InstanceConfig.Tools = tools[0]
-	if err := instancecfg.FinishInstanceConfig(args.InstanceConfig, e.Config()); err != nil {
-		return nil, err
-	}
+	return instancecfg.FinishInstanceConfig(args.InstanceConfig, e.Config())
+}
 
-	userData, err := providerinit.ComposeUserData(args.InstanceConfig, nil)
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot make user data")
-	}
-	logger.Debugf("ec2 user data; %d bytes", len(userData))
-	cfg := e.Config()
-	groups, err := e.setUpGroups(args.InstanceConfig.MachineId, cfg.APIPort())
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot set up groups")
-	}
+// runInstancesInZones runs the instances described by ri in the first
+// of the given availability zones that is not constrained, using the
+// first subnet in that zone if there is one.
+func (e *environ) runInstancesInZones(availabilityZones []string, subnetsByZone map[string][]string, ri *ec2.RunInstances) (*ec2.RunInstancesResp, error) {
 	var instResp *ec2.RunInstancesResp
-
-	blockDeviceMappings, err := getBlockDeviceMappings(args.Constraints)
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot create block device mappings")
-	}
-	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
-
+	var err error
 	for _, availZone := range availabilityZones {
-		instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
-			AvailZone:           availZone,
-			ImageId:             spec.Image.Id,
-			MinCount:            1,
-			MaxCount:            1,
-			UserData:            userData,
-			InstanceType:        spec.InstanceType.Name,
-			SecurityGroups:      groups,
-			BlockDeviceMappings: blockDeviceMappings,
-		})
+		ri.AvailZone = availZone
+		ri.SubnetId = ""
+		if subnetIds := subnetsByZone[availZone]; len(subnetIds) > 0 {
+			ri.SubnetId = subnetIds[0]
+		}
+		// Instances started in a subnet of a non-default VPC get
+		// no public address unless one is asked for.
+		ri.AssociatePublicIPAddress = ri.SubnetId != ""
+		instResp, err = runInstances(e.ec2(), ri)
 		if isZoneConstrainedError(err) {
 			logger.Infof("%q is constrained, trying another availability zone", availZone)
 		} else {
 			break
 		}
 	}
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot run instances")
-	}
-	if len(instResp.Instances) != 1 {
-		return nil, errors.Errorf("expected 1 started instance, got %d", len(instResp.Instances))
-	}
+	return instResp, err
+}
 
+// startedInstance names and records the instance started with the
+// given params, and returns the result of starting it.
+func (e *environ) startedInstance(
+	args environs.StartInstanceParams,
+	ec2Inst *ec2.Instance,
+	spec *instances.InstanceSpec,
+	blockDeviceMappings []ec2.BlockDeviceMapping,
+) *environs.StartInstanceResult {
 	inst := &ec2Instance{
 		e:        e,
-		Instance: &instResp.Instances[0],
+		Instance: ec2Inst,
 	}
 	logger.Infof("started instance %q in %q", inst.Id(), inst.Instance.AvailZone)
 
-	// TODO(axw) tag all resources (instances and volumes), for accounting
-	// and identification.
+	instTags := make(map[string]string)
+	for k, v := range args.InstanceTags {
+		instTags[k] = v
+	}
+	if args.InstanceName != "" {
+		instTags[tagName] = args.InstanceName
+	}
+	if err := tagResources(e.ec2(), instTags, string(inst.Id())); err != nil {
+		// The tags are only used for identification, so don't
+		// fail the instance.
+		logger.Warningf("cannot tag instance %q: %v", inst.Id(), err)
+	}
 
 	if multiwatcher.AnyJobNeedsState(args.InstanceConfig.Jobs...) {
 		if err := common.AddStateInstance(e.Storage(), inst.Id()); err != nil {
@@ -547,6 +699,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		}
 	}
 
+	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
 	hc := instance.HardwareCharacteristics{
 		Arch:     &spec.Image.Arch,
 		Mem:      &spec.InstanceType.Mem,
@@ -559,7 +712,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	return &environs.StartInstanceResult{
 		Instance: inst,
 		Hardware: &hc,
-	}, nil
+	}
 }
 
 var runInstances = _runInstances
@@ -584,20 +737,37 @@ func (e *environ) StopInstances(ids ...instance.Id) error {
 	return common.RemoveStateInstances(e.Storage(), ids...)
 }
 
+// securityGroupsByName returns information on the security groups
+// with the given names. Groups in a non-default VPC cannot be looked
+// up by name, so when vpc-id is set they are found using a filter.
+func (e *environ) securityGroupsByName(names ...string) ([]ec2.SecurityGroupInfo, error) {
+	var groups []ec2.SecurityGroup
+	var filter *ec2.Filter
+	if vpcID := e.ecfg().vpcID(); vpcID != "" {
+		filter = ec2.NewFilter()
+		filter.Add("vpc-id", vpcID)
+		filter.Add("group-name", names...)
+	} else {
+		groups = ec2.SecurityGroupNames(names...)
+	}
+	resp, err := e.ec2().SecurityGroups(groups, filter)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Groups, nil
+}
+
 // groupInfoByName returns information on the security group
 // with the given name including rules and other details.
 func (e *environ) groupInfoByName(groupName string) (ec2.SecurityGroupInfo, error) {
-	// Non-default VPC does not support name-based group lookups, can
-	// use a filter by group name instead when support is needed.
-	limitToGroups := []ec2.SecurityGroup{{Name: groupName}}
-	resp, err := e.ec2().SecurityGroups(limitToGroups, nil)
+	groups, err := e.securityGroupsByName(groupName)
 	if err != nil {
 		return ec2.SecurityGroupInfo{}, err
 	}
-	if len(resp.Groups) != 1 {
-		return ec2.SecurityGroupInfo{}, fmt.Errorf("expected one security group named %q, got %v", groupName, resp.Groups)
+	if len(groups) != 1 {
+		return ec2.SecurityGroupInfo{}, fmt.Errorf("expected one security group named %q, got %v", groupName, groups)
 	}
-	return resp.Groups[0], nil
+	return groups[0], nil
 }
 
 // groupByName returns the security group with the given name.
@@ -870,16 +1040,23 @@ func (e *environ) NetworkInterfaces(instId instance.Id) ([]network.InterfaceInfo
 
 // Subnets returns basic information about the specified subnets known
 // by the provider for the specified instance. subnetIds must not be
-// empty. Implements NetworkingEnviron.Subnets.
+// empty unless vpc-id is set, in which case all subnets in the VPC are
+// returned. Implements NetworkingEnviron.Subnets.
 func (e *environ) Subnets(_ instance.Id, subnetIds []network.Id) ([]network.SubnetInfo, error) {
-	// At some point in the future an empty netIds may mean "fetch all subnets"
-	// but until that functionality is needed it's an error.
-	if len(subnetIds) == 0 {
-		return nil, errors.Errorf("subnetIds must not be empty")
+	// An empty subnetIds means "fetch all subnets", which is only
+	// supported when the environment is confined to a VPC.
+	vpcID := e.ecfg().vpcID()
+	if len(subnetIds) == 0 && vpcID == "" {
+		return nil, errors.NewNotSupported(nil, "subnetIds must not be empty")
 	}
 	ec2Inst := e.ec2()
 	// We can't filter by instance id here, unfortunately.
-	resp, err := ec2Inst.Subnets(nil, nil)
+	var filter *ec2.Filter
+	if vpcID != "" {
+		filter = ec2.NewFilter()
+		filter.Add("vpc-id", vpcID)
+	}
+	resp, err := ec2Inst.Subnets(nil, filter)
 	if err != nil {
 		return nil, errors.Annotatef(err, "failed to retrieve subnets")
 	}
@@ -892,7 +1069,7 @@ func (e *environ) Subnets(_ instance.Id, subnetIds []network.Id) ([]network.Subn
 	var results []network.SubnetInfo
 	for _, subnet := range resp.Subnets {
 		_, ok := subIdSet[subnet.Id]
-		if !ok {
+		if !ok && len(subnetIds) > 0 {
 			logger.Tracef("subnet %q not in %v, skipping", subnet.Id, subnetIds)
 			continue
 		}
@@ -1208,7 +1385,7 @@ var zeroGroup ec2.SecurityGroup
 // the named group only.
 func (e *environ) ensureGroup(name string, perms []ec2.IPPerm) (g ec2.SecurityGroup, err error) {
 	ec2inst := e.ec2()
-	resp, err := ec2inst.CreateSecurityGroup("", name, "juju group")
+	resp, err := ec2inst.CreateSecurityGroup(e.ecfg().vpcID(), name, "juju group")
 	if err != nil && ec2ErrCode(err) != "InvalidGroup.Duplicate" {
 		return zeroGroup, err
 	}
@@ -1217,11 +1394,10 @@ func (e *environ) ensureGroup(name string, perms []ec2.IPPerm) (g ec2.SecurityGr
 	if err == nil {
 		g = resp.SecurityGroup
 	} else {
-		resp, err := ec2inst.SecurityGroups(ec2.SecurityGroupNames(name), nil)
+		info, err := e.groupInfoByName(name)
 		if err != nil {
 			return zeroGroup, err
 		}
-		info := resp.Groups[0]
 		// It's possible that the old group has the wrong
 		// description here, but if it does it's probably due
 		// to something deliberately playing games with juju,
@@ -312,6 +312,40 @@ func (t *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
 	c.Assert(*hc.CpuPower, gc.Equals, uint64(100))
 }
 
+func (t *localServerSuite) TestStartInstanceName(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := environs.StartInstanceParams{InstanceName: "sample-1-mysql"}
+	result, err := testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	insts, err := env.Instances([]instance.Id{result.Instance.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ec2.InstanceEC2(insts[0]).Tags, jc.SameContents, []amzec2.Tag{
+		{"Name", "sample-1-mysql"},
+	})
+}
+
+func (t *localServerSuite) TestStartInstanceTags(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := environs.StartInstanceParams{
+		InstanceName: "sample-1-mysql",
+		InstanceTags: map[string]string{"juju-env-uuid": "some-uuid"},
+	}
+	result, err := testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	insts, err := env.Instances([]instance.Id{result.Instance.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ec2.InstanceEC2(insts[0]).Tags, jc.SameContents, []amzec2.Tag{
+		{"Name", "sample-1-mysql"},
+		{"juju-env-uuid", "some-uuid"},
+	})
+}
+
 func (t *localServerSuite) TestStartInstanceAvailZone(c *gc.C) {
 	inst, err := t.testStartInstanceAvailZone(c, "test-available")
 	c.Assert(err, jc.ErrorIsNil)
@@ -684,6 +718,13 @@ func (t *localServerSuite) TestPrecheckInstanceAvailZoneUnknown(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid availability zone "test-unknown"`)
 }
 
+func (t *localServerSuite) TestPrecheckInstanceSubnetWithoutVPC(c *gc.C) {
+	env := t.Prepare(c)
+	placement := "subnet=subnet-0"
+	err := env.PrecheckInstance(coretesting.FakeDefaultSeries, constraints.Value{}, placement)
+	c.Assert(err, gc.ErrorMatches, `subnet placement requires vpc-id to be set`)
+}
+
 func (t *localServerSuite) TestValidateImageMetadata(c *gc.C) {
 	env := t.Prepare(c)
 	params, err := env.(simplestreams.MetadataValidator).MetadataLookupParams("test")
@@ -868,6 +909,7 @@ func (t *localServerSuite) TestSubnetsNoNetIds(c *gc.C) {
 
 	_, err := env.Subnets("", []network.Id{})
 	c.Assert(err, gc.ErrorMatches, "subnetIds must not be empty")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
 }
 
 func (t *localServerSuite) TestSubnetsMissingSubnet(c *gc.C) {
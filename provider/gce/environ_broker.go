@@ -49,6 +49,9 @@ func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if args.ImageSelected != nil {
+		args.ImageSelected(spec.Image.Id)
+	}
 
 	if err := env.finishInstanceConfig(args, spec); err != nil {
 		return nil, errors.Trace(err)
@@ -224,6 +227,13 @@ func getMetadata(args environs.StartInstanceParams) (map[string]string, error) {
 	if isStateServer(args.InstanceConfig) {
 		metadata[metadataKeyIsState] = metadataValueTrue
 	}
+	// The instance tags are recorded as metadata too, without
+	// overriding any of the keys above.
+	for k, v := range args.InstanceTags {
+		if _, ok := metadata[k]; !ok {
+			metadata[k] = v
+		}
+	}
 
 	return metadata, nil
 }
@@ -173,6 +173,21 @@ func (s *environBrokerSuite) TestGetMetadata(c *gc.C) {
 	c.Check(metadata, gc.DeepEquals, s.Metadata)
 }
 
+func (s *environBrokerSuite) TestGetMetadataInstanceTags(c *gc.C) {
+	s.StartInstArgs.InstanceTags = map[string]string{
+		"juju-env-uuid": "some-uuid",
+		"juju-is-state": "true",
+	}
+	metadata, err := gce.GetMetadata(s.StartInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := map[string]string{"juju-env-uuid": "some-uuid"}
+	for k, v := range s.Metadata {
+		expected[k] = v
+	}
+	c.Check(metadata, gc.DeepEquals, expected)
+}
+
 func (s *environBrokerSuite) TestGetDisks(c *gc.C) {
 	diskSpecs := gce.GetDisks(s.spec, s.StartInstArgs.Constraints)
 
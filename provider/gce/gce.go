@@ -6,11 +6,13 @@ package gce
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/tags"
 )
 
 // The metadata keys used when creating new instances.
 const (
-	metadataKeyIsState = "juju-is-state"
+	metadataKeyIsState = tags.JujuStateServer
 	// This is defined by the cloud-init code:
 	// http://bazaar.launchpad.net/~cloud-init-dev/cloud-init/trunk/view/head:/cloudinit/sources/DataSourceGCE.py
 	// http://cloudinit.readthedocs.org/en/latest/
@@ -169,6 +169,12 @@ func (env *maasEnviron) ecfg() *maasEnvironConfig {
 	return env.ecfgUnlocked
 }
 
+// VerifyCredentials is specified in the environs.CredentialsVerifier
+// interface.
+func (env *maasEnviron) VerifyCredentials() error {
+	return verifyCredentials(env)
+}
+
 // Config is specified in the Environ interface.
 func (env *maasEnviron) Config() *config.Config {
 	return env.ecfg().Config
@@ -1164,36 +1170,74 @@ func (environ *maasEnviron) newCloudinitConfig(hostname, primaryIface, series st
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	switch operatingSystem {
-	case version.Windows:
-		cloudcfg.AddScripts(runCmd)
-	case version.Ubuntu:
-		cloudcfg.SetSystemUpdate(true)
-		cloudcfg.AddScripts("set -xe", runCmd)
-		// Only create the default bridge if we're not using static
-		// address allocation for containers.
-		if !environs.AddressAllocationEnabled() {
-			// Address allocated feature flag might be disabled, but
-			// DisableNetworkManagement can still disable the bridge
-			// creation.
-			if on, set := environ.Config().DisableNetworkManagement(); on && set {
-				logger.Infof(
-					"network management disabled - not using %q bridge for containers",
-					instancecfg.DefaultBridgeName,
-				)
-				break
-			}
-			bridgeScript, err := setupJujuNetworking()
-			if err != nil {
-				return nil, errors.Trace(err)
-			}
-			cloudcfg.AddPackage("bridge-utils")
-			cloudcfg.AddRunCmd(bridgeScript)
-		}
+	prepare, ok := nodePreparers[operatingSystem]
+	if !ok {
+		return nil, errors.NotSupportedf("preparing MAAS nodes running %s", operatingSystem)
+	}
+	if err := prepare(environ, cloudcfg, runCmd); err != nil {
+		return nil, errors.Trace(err)
 	}
 	return cloudcfg, nil
 }
 
+// nodePreparer adds to the given cloud config whatever a MAAS node
+// running a particular operating system needs, including the given
+// command that records the node's machine info.
+type nodePreparer func(environ *maasEnviron, cloudcfg cloudinit.CloudConfig, runCmd string) error
+
+// nodePreparers holds the nodePreparer for each operating system
+// that MAAS nodes may run.
+var nodePreparers = map[version.OSType]nodePreparer{
+	version.Ubuntu:  prepareUbuntuNode,
+	version.CentOS:  prepareCentOSNode,
+	version.Windows: prepareWindowsNode,
+}
+
+// prepareUbuntuNode updates the node's packages and, unless
+// containers are given static addresses or network management is
+// disabled, sets up the bridge used by containers.
+func prepareUbuntuNode(environ *maasEnviron, cloudcfg cloudinit.CloudConfig, runCmd string) error {
+	cloudcfg.SetSystemUpdate(true)
+	cloudcfg.AddScripts("set -xe", runCmd)
+	// Only create the default bridge if we're not using static
+	// address allocation for containers.
+	if environs.AddressAllocationEnabled() {
+		return nil
+	}
+	// Address allocated feature flag might be disabled, but
+	// DisableNetworkManagement can still disable the bridge
+	// creation.
+	if on, set := environ.Config().DisableNetworkManagement(); on && set {
+		logger.Infof(
+			"network management disabled - not using %q bridge for containers",
+			instancecfg.DefaultBridgeName,
+		)
+		return nil
+	}
+	bridgeScript, err := setupJujuNetworking()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cloudcfg.AddPackage("bridge-utils")
+	cloudcfg.AddRunCmd(bridgeScript)
+	return nil
+}
+
+// prepareCentOSNode updates the node's packages; the container
+// bridge is only set up on Ubuntu.
+func prepareCentOSNode(environ *maasEnviron, cloudcfg cloudinit.CloudConfig, runCmd string) error {
+	cloudcfg.SetSystemUpdate(true)
+	cloudcfg.AddScripts("set -xe", runCmd)
+	return nil
+}
+
+// prepareWindowsNode only records the machine info; cloudbase-init
+// runs the commands as a PowerShell script.
+func prepareWindowsNode(environ *maasEnviron, cloudcfg cloudinit.CloudConfig, runCmd string) error {
+	cloudcfg.AddScripts(runCmd)
+	return nil
+}
+
 func (environ *maasEnviron) releaseNodes(nodes gomaasapi.MAASObject, ids url.Values, recurse bool) error {
 	err := ReleaseNodes(nodes, ids)
 	if err == nil {
@@ -1492,7 +1536,7 @@ func (environ *maasEnviron) Subnets(instId instance.Id, subnetIds []network.Id)
 	// At some point in the future an empty netIds may mean "fetch all subnets"
 	// but until that functionality is needed it's an error.
 	if len(subnetIds) == 0 {
-		return nil, errors.Errorf("subnetIds must not be empty")
+		return nil, errors.NewNotSupported(nil, "subnetIds must not be empty")
 	}
 	instances, err := environ.acquiredInstances([]instance.Id{instId})
 	if err != nil {
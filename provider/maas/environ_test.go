@@ -245,3 +245,25 @@ func (*environSuite) TestNewCloudinitConfigWithDisabledNetworkManagement(c *gc.C
 	c.Assert(cloudcfg.SystemUpdate(), jc.IsTrue)
 	c.Assert(cloudcfg.RunCmds(), jc.DeepEquals, expectedCloudinitConfig)
 }
+
+func (s *environSuite) TestNewCloudinitConfigCentOS(c *gc.C) {
+	s.SetFeatureFlags() // clear the flags.
+	env, err := maas.NewEnviron(getSimpleTestConfig(c, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	cloudcfg, err := maas.NewCloudinitConfig(env, "testing.invalid", "eth0", "centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloudcfg.SystemUpdate(), jc.IsTrue)
+	// The container bridge is only set up on Ubuntu.
+	c.Assert(cloudcfg.RunCmds(), gc.HasLen, 2)
+	c.Assert(cloudcfg.RunCmds()[0], gc.Equals, "set -xe")
+}
+
+func (*environSuite) TestNewCloudinitConfigWindows(c *gc.C) {
+	env, err := maas.NewEnviron(getSimpleTestConfig(c, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	cloudcfg, err := maas.NewCloudinitConfig(env, "testing.invalid", "eth0", "win2012r2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloudcfg.SystemUpdate(), jc.IsFalse)
+	c.Assert(cloudcfg.RunCmds(), gc.HasLen, 1)
+	c.Assert(cloudcfg.RunCmds()[0], jc.Contains, "MAASmachine.txt")
+}
@@ -198,6 +198,12 @@ func (mi *maasInstance) hardwareCharacteristics() (*instance.HardwareCharacteris
 	if len(nodeTags) > 0 {
 		hc.Tags = &nodeTags
 	}
+	rootDisk, err := mi.rootDiskSize()
+	if err == nil {
+		hc.RootDisk = &rootDisk
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Annotate(err, "error determining root disk size")
+	}
 	return hc, nil
 }
 
@@ -159,6 +159,26 @@ func (s *instanceTest) TestHardwareCharacteristicsWithTags(c *gc.C) {
 	c.Assert(hc.String(), gc.Equals, `arch=amd64 cpu-cores=6 mem=16384M tags=a,b`)
 }
 
+func (s *instanceTest) TestHardwareCharacteristicsWithRootDisk(c *gc.C) {
+	jsonValue := `{
+		"system_id": "system_id",
+        "architecture": "amd64/generic",
+        "cpu_count": 6,
+        "memory": 16384,
+        "physicalblockdevice_set": [
+            {"id": 1, "name": "sda", "size": 250059350016},
+            {"id": 2, "name": "sdb", "size": 500059350016}
+        ],
+        "constraint_map": {"1": "root", "2": "1"}
+	}`
+	obj := s.testMAASObject.TestServer.NewNode(jsonValue)
+	inst := maasInstance{maasObject: &obj, environ: s.makeEnviron()}
+	hc, err := inst.hardwareCharacteristics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hc, gc.NotNil)
+	c.Assert(hc.String(), gc.Equals, `arch=amd64 cpu-cores=6 mem=16384M root-disk=238475M`)
+}
+
 func (s *instanceTest) TestHardwareCharacteristicsMissing(c *gc.C) {
 	s.testHardwareCharacteristicsMissing(c, `{"system_id": "id", "cpu_count": 6, "memory": 16384}`,
 		`error determining architecture: Requested string, got <nil>.`)
@@ -43,15 +43,73 @@ var validConfigOptions = set.NewStrings(
 
 // ValidateConfig is defined on the Provider interface.
 func (e *maasStorageProvider) ValidateConfig(providerConfig *storage.Config) error {
-	// TODO - check valid values as well as attr names
 	for attr := range providerConfig.Attrs() {
 		if !validConfigOptions.Contains(attr) {
 			return errors.Errorf("unknown provider config option %q", attr)
 		}
 	}
+	if tags, ok := providerConfig.Attrs()[tagsAttribute]; ok {
+		if _, err := parseDiskTags(tags); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
 
+// parseDiskTags returns the disk tags specified by the value of a
+// pool's tags attribute, which may be a comma separated string or a
+// list of strings. MAAS does not allow spaces in tag names, so new
+// pools with such tags are rejected; see legacyDiskTags for pools
+// created before that was checked.
+func parseDiskTags(value interface{}) ([]string, error) {
+	var tags []string
+	switch value := value.(type) {
+	case string:
+		if value == "" {
+			return nil, nil
+		}
+		tags = strings.Split(value, ",")
+	case []string:
+		tags = append(tags, value...)
+	case []interface{}:
+		for _, tag := range value {
+			s, ok := tag.(string)
+			if !ok {
+				return nil, errors.Errorf("expected string tag, got %T", tag)
+			}
+			tags = append(tags, s)
+		}
+	default:
+		return nil, errors.Errorf("expected %s to be a string or list of strings, got %T", tagsAttribute, value)
+	}
+	for i, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, errors.Errorf("empty tag in %s", tagsAttribute)
+		}
+		if strings.ContainsAny(tag, " \t") {
+			return nil, errors.Errorf("invalid tag %q: tags may not contain spaces", tag)
+		}
+		tags[i] = tag
+	}
+	return tags, nil
+}
+
+// legacyDiskTags returns the disk tags specified by a comma separated
+// tags attribute of a pool that may have been created before its tags
+// were validated. Such pools were accepted with spaces in their tags,
+// which were then removed, so they are removed here too rather than
+// failing to provision the pool's volumes. Empty tags are dropped.
+func legacyDiskTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(strings.Replace(value, " ", "", -1), ",") {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 // Supports is defined on the Provider interface.
 func (e *maasStorageProvider) Supports(k storage.StorageKind) bool {
 	return k == storage.StorageKindBlock
@@ -107,16 +165,16 @@ func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Val
 			name:     v.Tag.Id(),
 			sizeInGB: mibToGb(v.Size),
 		}
-		var tags string
-		if len(v.Attributes) > 0 {
-			tags = v.Attributes[tagsAttribute].(string)
-		}
-		if len(tags) > 0 {
-			// We don't want any spaces in the tags;
-			// strip out any just in case.
-			// TODO(wallyworld) reject pool configuration if tags have spaces
-			tags = strings.Replace(tags, " ", "", -1)
-			info.tags = strings.Split(tags, ",")
+		if value, ok := v.Attributes[tagsAttribute]; ok {
+			tags, err := parseDiskTags(value)
+			if s, ok := value.(string); ok && err != nil {
+				logger.Warningf("volume %s: %v; using tags %q", v.Tag.Id(), err, legacyDiskTags(s))
+				tags, err = legacyDiskTags(s), nil
+			}
+			if err != nil {
+				return nil, errors.Annotatef(err, "volume %s", v.Tag.Id())
+			}
+			info.tags = tags
 		}
 		volumes[i+1] = info
 	}
@@ -239,3 +297,60 @@ func (mi *maasInstance) volumes(
 	}
 	return volumes, attachments, nil
 }
+
+// rootDiskSize returns the size, in MiB, of the block device that MAAS
+// allocated for the root disk when the node was acquired. If the node
+// does not report its block devices, or which of them is the root disk,
+// an error satisfying errors.IsNotFound is returned.
+func (mi *maasInstance) rootDiskSize() (uint64, error) {
+	fields := mi.getMaasObject().GetMap()
+	deviceInfo, ok := fields["physicalblockdevice_set"]
+	if !ok || deviceInfo.IsNil() {
+		return 0, errors.NotFoundf("block devices")
+	}
+	labelsMap, ok := fields["constraint_map"]
+	if !ok || labelsMap.IsNil() {
+		return 0, errors.NotFoundf("constraint map field")
+	}
+	deviceLabels, err := labelsMap.GetMap()
+	if err != nil {
+		return 0, errors.Annotate(err, "invalid constraint map value")
+	}
+	var rootId string
+	for idKey, labelValue := range deviceLabels {
+		label, err := labelValue.GetString()
+		if err != nil {
+			return 0, errors.Annotate(err, "invalid device label")
+		}
+		if label == rootDiskLabel {
+			rootId = idKey
+			break
+		}
+	}
+	if rootId == "" {
+		return 0, errors.NotFoundf("root disk label")
+	}
+	devices, err := deviceInfo.GetArray()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	for _, d := range devices {
+		deviceAttrs, err := d.GetMap()
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		id, err := deviceAttrs["id"].GetFloat64()
+		if err != nil {
+			return 0, errors.Annotate(err, "invalid device id")
+		}
+		if strconv.Itoa(int(id)) != rootId {
+			continue
+		}
+		sizeInBytes, err := deviceAttrs["size"].GetFloat64()
+		if err != nil {
+			return 0, errors.Annotate(err, "invalid device size")
+		}
+		return uint64(sizeInBytes / humanize.MiByte), nil
+	}
+	return 0, errors.NotFoundf("root disk device %s", rootId)
+}
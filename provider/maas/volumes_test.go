@@ -4,6 +4,7 @@
 package maas
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -72,6 +73,37 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersWithTags(c *gc.C) {
 	})
 }
 
+func (s *volumeSuite) TestBuildMAASVolumeParametersWithTagList(c *gc.C) {
+	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
+		{Tag: names.NewVolumeTag("1"), Size: 2000000, Attributes: map[string]interface{}{"tags": []interface{}{"tag1", " tag2"}}},
+	}, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, nil}, //root disk
+		{"1", 1954, []string{"tag1", "tag2"}},
+	})
+}
+
+func (s *volumeSuite) TestBuildMAASVolumeParametersWithLegacyTags(c *gc.C) {
+	// Pools created before their tags were validated may have
+	// spaces and empty tags; they are tolerated as they were then.
+	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
+		{Tag: names.NewVolumeTag("1"), Size: 2000000, Attributes: map[string]interface{}{"tags": "fast disk,,tag2"}},
+	}, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, nil}, //root disk
+		{"1", 1954, []string{"fastdisk", "tag2"}},
+	})
+}
+
+func (s *volumeSuite) TestBuildMAASVolumeParametersInvalidTags(c *gc.C) {
+	_, err := buildMAASVolumeParameters([]storage.VolumeParams{
+		{Tag: names.NewVolumeTag("1"), Size: 2000000, Attributes: map[string]interface{}{"tags": 123}},
+	}, constraints.Value{})
+	c.Assert(err, gc.ErrorMatches, "volume 1: expected tags to be a string or list of strings, got int")
+}
+
 func (s *volumeSuite) TestInstanceVolumes(c *gc.C) {
 	obj := s.testMAASObject.TestServer.NewNode(validVolumeJson)
 	instance := maasInstance{maasObject: &obj, environ: s.makeEnviron()}
@@ -130,6 +162,33 @@ func (s *volumeSuite) TestInstanceVolumesOldMass(c *gc.C) {
 	c.Assert(attachments, gc.HasLen, 0)
 }
 
+func (s *volumeSuite) TestInstanceRootDiskSize(c *gc.C) {
+	obj := s.testMAASObject.TestServer.NewNode(validVolumeJson)
+	instance := maasInstance{maasObject: &obj, environ: s.makeEnviron()}
+	size, err := instance.rootDiskSize()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(238475))
+}
+
+func (s *volumeSuite) TestInstanceRootDiskSizeOldMaas(c *gc.C) {
+	obj := s.testMAASObject.TestServer.NewNode(`{"system_id": "node0"}`)
+	instance := maasInstance{maasObject: &obj, environ: s.makeEnviron()}
+	_, err := instance.rootDiskSize()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *volumeSuite) TestInstanceRootDiskSizeNoRootLabel(c *gc.C) {
+	obj := s.testMAASObject.TestServer.NewNode(`{
+		"system_id": "node0",
+		"physicalblockdevice_set": [{"id": 1, "name": "sda", "size": 1073741824}],
+		"constraint_map": {"1": "0"}
+	}`)
+	instance := maasInstance{maasObject: &obj, environ: s.makeEnviron()}
+	_, err := instance.rootDiskSize()
+	c.Assert(err, gc.ErrorMatches, "root disk label not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 var validVolumeJson = `
 {
     "system_id": "node0",
@@ -229,6 +288,39 @@ func (*storageProviderSuite) TestValidateConfigInvalidConfig(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unknown provider config option "invalid"`)
 }
 
+func (*storageProviderSuite) TestValidateConfigTags(c *gc.C) {
+	p := maasStorageProvider{}
+	for i, test := range []struct {
+		tags interface{}
+		err  string
+	}{{
+		tags: "ssd,sata",
+	}, {
+		tags: []interface{}{"ssd", "sata"},
+	}, {
+		tags: "fast disk",
+		err:  `invalid tag "fast disk": tags may not contain spaces`,
+	}, {
+		tags: "ssd,,sata",
+		err:  "empty tag in tags",
+	}, {
+		tags: []interface{}{"ssd", 1},
+		err:  "expected string tag, got int",
+	}} {
+		c.Logf("test %d: %v", i, test.tags)
+		cfg, err := storage.NewConfig("foo", maasStorageProviderType, map[string]interface{}{
+			"tags": test.tags,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
 func (s *storageProviderSuite) TestSupports(c *gc.C) {
 	p := maasStorageProvider{}
 	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
@@ -26,6 +26,7 @@ var configFields = schema.Fields{
 	"use-floating-ip":      schema.Bool(),
 	"use-default-secgroup": schema.Bool(),
 	"network":              schema.String(),
+	"floating-ip-pool":     schema.String(),
 }
 var configDefaults = schema.Defaults{
 	"username":             "",
@@ -40,6 +41,7 @@ var configDefaults = schema.Defaults{
 	"use-floating-ip":      false,
 	"use-default-secgroup": false,
 	"network":              "",
+	"floating-ip-pool":     "",
 }
 
 type environConfig struct {
@@ -95,6 +97,10 @@ func (c *environConfig) network() string {
 	return c.attrs["network"].(string)
 }
 
+func (c *environConfig) floatingIPPool() string {
+	return c.attrs["floating-ip-pool"].(string)
+}
+
 func (p environProvider) newConfig(cfg *config.Config) (*environConfig, error) {
 	valid, err := p.Validate(cfg, nil)
 	if err != nil {
@@ -55,6 +55,7 @@ type configTest struct {
 	useFloatingIP           bool
 	useDefaultSecurityGroup bool
 	network                 string
+	floatingIPPool          string
 	username                string
 	password                string
 	tenantName              string
@@ -154,6 +155,7 @@ func (t configTest) check(c *gc.C) {
 	c.Assert(ecfg.useFloatingIP(), gc.Equals, t.useFloatingIP)
 	c.Assert(ecfg.useDefaultSecurityGroup(), gc.Equals, t.useDefaultSecurityGroup)
 	c.Assert(ecfg.network(), gc.Equals, t.network)
+	c.Assert(ecfg.floatingIPPool(), gc.Equals, t.floatingIPPool)
 	// Default should be true
 	expectedHostnameVerification := true
 	if t.sslHostnameSet {
@@ -437,6 +439,15 @@ var configTests = []configTest{
 			"network": "a-network-label",
 		},
 		network: "a-network-label",
+	}, {
+		summary:        "default floating ip pool",
+		floatingIPPool: "",
+	}, {
+		summary: "floating ip pool",
+		config: attrs{
+			"floating-ip-pool": "ext-net",
+		},
+		floatingIPPool: "ext-net",
 	},
 }
 
@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/goose.v1/client"
+	goosehttp "gopkg.in/goose.v1/http"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+// consoleOutputLength is the number of lines of console output
+// requested at a time. Only the most recent lines are returned.
+const consoleOutputLength = 200
+
+var _ environs.ConsoleOutputReader = (*environ)(nil)
+
+// ConsoleOutput is specified in the environs.ConsoleOutputReader
+// interface.
+func (e *environ) ConsoleOutput(id instance.Id) (string, error) {
+	e.ecfgMutex.Lock()
+	novaClient := e.client
+	e.ecfgMutex.Unlock()
+
+	// goose does not wrap the os-getConsoleOutput server action.
+	var req struct {
+		GetConsoleOutput struct {
+			Length int `json:"length"`
+		} `json:"os-getConsoleOutput"`
+	}
+	req.GetConsoleOutput.Length = consoleOutputLength
+	var resp struct {
+		Output string `json:"output"`
+	}
+	requestData := goosehttp.RequestData{
+		ReqValue:       &req,
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK},
+	}
+	apiCall := fmt.Sprintf("servers/%s/action", id)
+	if err := novaClient.SendRequest(client.POST, "compute", apiCall, &requestData); err != nil {
+		return "", errors.Annotatef(err, "cannot get console output of %s", id)
+	}
+	return resp.Output, nil
+}
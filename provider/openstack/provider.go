@@ -86,6 +86,14 @@ openstack:
     #
     # network: <your network label or uuid>
 
+    # floating-ip-pool specifies the pool from which floating IP
+    # addresses are taken when use-floating-ip is true, in the case
+    # where multiple external networks exist. Juju will only use
+    # unassigned floating IP addresses that have already been
+    # allocated from this pool. It may be omitted otherwise.
+    #
+    # floating-ip-pool: <your floating IP pool name>
+
     # agent-metadata-url specifies the location of the Juju tools and
     # metadata. It defaults to the global public tools metadata
     # location https://streams.canonical.com/tools.
@@ -427,7 +435,9 @@ func (inst *openstackInstance) hardwareCharacteristics() *instance.HardwareChara
 		hc.CpuPower = inst.instType.CpuPower
 		// tags not currently supported on openstack
 	}
-	hc.AvailabilityZone = &inst.serverDetail.AvailabilityZone
+	if zone := inst.serverDetail.AvailabilityZone; zone != "" {
+		hc.AvailabilityZone = &zone
+	}
 	return hc
 }
 
@@ -877,33 +887,37 @@ func (e *environ) resolveNetwork(networkName string) (string, error) {
 }
 
 // allocatePublicIP tries to find an available floating IP address, or
-// allocates a new one, returning it, or an error
+// allocates a new one, returning it, or an error. If a floating IP
+// pool is configured, only unassigned addresses from that pool are
+// used, since nova cannot be asked to allocate from a specific pool.
 func (e *environ) allocatePublicIP() (*nova.FloatingIP, error) {
 	fips, err := e.nova().ListFloatingIPs()
 	if err != nil {
 		return nil, err
 	}
-	var newfip *nova.FloatingIP
+	pool := e.ecfg().floatingIPPool()
 	for _, fip := range fips {
-		newfip = &fip
 		if fip.InstanceId != nil && *fip.InstanceId != "" {
 			// unavailable, skip
-			newfip = nil
 			continue
-		} else {
-			logger.Debugf("found unassigned public ip: %v", newfip.IP)
-			// unassigned, we can use it
-			return newfip, nil
 		}
-	}
-	if newfip == nil {
-		// allocate a new IP and use it
-		newfip, err = e.nova().AllocateFloatingIP()
-		if err != nil {
-			return nil, err
+		if pool != "" && fip.Pool != pool {
+			continue
 		}
-		logger.Debugf("allocated new public IP: %v", newfip.IP)
+		logger.Debugf("found unassigned public ip: %v", fip.IP)
+		// unassigned, we can use it
+		newfip := fip
+		return &newfip, nil
+	}
+	if pool != "" {
+		return nil, errors.Errorf("no unassigned floating IP addresses in pool %q", pool)
 	}
+	// allocate a new IP and use it
+	newfip, err := e.nova().AllocateFloatingIP()
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("allocated new public IP: %v", newfip.IP)
 	return newfip, nil
 }
 
@@ -998,6 +1012,9 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	if err != nil {
 		return nil, err
 	}
+	if args.ImageSelected != nil {
+		args.ImageSelected(spec.Image.Id)
+	}
 	tools, err := args.Tools.Match(tools.Filter{Arch: spec.Image.Arch})
 	if err != nil {
 		return nil, fmt.Errorf("chosen architecture %v not present in %v", spec.Image.Arch, arches)
@@ -1044,6 +1061,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		groupNames[i] = nova.SecurityGroupName{g.Name}
 	}
 	var server *nova.Entity
+	var startedZone string
 	for _, availZone := range availabilityZones {
 		var opts = nova.RunServerOpts{
 			Name:               e.machineFullName(args.InstanceConfig.MachineId),
@@ -1063,6 +1081,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		if isNoValidHostsError(err) {
 			logger.Infof("no valid hosts available in zone %q, trying another availability zone", availZone)
 		} else {
+			startedZone = availZone
 			break
 		}
 	}
@@ -1073,6 +1092,11 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	if err != nil {
 		return nil, fmt.Errorf("cannot get started instance: %v", err)
 	}
+	if detail.AvailabilityZone == "" {
+		// The zone may not be reported until the server has
+		// been scheduled; record the one we asked for, if any.
+		detail.AvailabilityZone = startedZone
+	}
 	inst := &openstackInstance{
 		e:            e,
 		serverDetail: detail,
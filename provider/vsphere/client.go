@@ -258,7 +258,7 @@ func (c *client) GetNetworkInterfaces(inst instance.Id, ecfg *environConfig) ([]
 
 func (c *client) Subnets(inst instance.Id, ids []network.Id) ([]network.SubnetInfo, error) {
 	if len(ids) == 0 {
-		return nil, errors.Errorf("subnetIds must not be empty")
+		return nil, errors.NewNotSupported(nil, "subnetIds must not be empty")
 	}
 	vm, err := c.getVm(string(inst))
 	if err != nil {
@@ -87,6 +87,9 @@ type MachineTemplate struct {
 	// with the machine.
 	Placement string
 
+	// Labels holds the user-defined labels of the new machine.
+	Labels []string
+
 	// principals holds the principal units that will
 	// associated with the machine.
 	principals []string
@@ -254,6 +257,11 @@ func (st *State) effectiveMachineTemplate(p MachineTemplate, allowStateServer bo
 			return tmpl, errStateServerNotAllowed
 		}
 	}
+	labels, err := normaliseMachineLabels(p.Labels)
+	if err != nil {
+		return tmpl, errors.Trace(err)
+	}
+	p.Labels = labels
 	return p, nil
 }
 
@@ -450,6 +458,7 @@ func (st *State) machineDocForTemplate(template MachineTemplate, id string) *mac
 		Addresses:  fromNetworkAddresses(template.Addresses),
 		NoVote:     template.NoVote,
 		Placement:  template.Placement,
+		Labels:     template.Labels,
 	}
 }
 
@@ -63,6 +63,18 @@ func (s *StateServerAddressesSuite) TestStateServerEnv(c *gc.C) {
 	c.Assert(addresses, jc.SameContents, []string{"10.0.1.2:1234"})
 }
 
+func (s *StateServerAddressesSuite) TestIPv6StateServer(c *gc.C) {
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Jobs: []state.MachineJob{state.JobManageEnviron},
+		Addresses: []network.Address{
+			{Value: "2001:db8::1", Type: network.IPv6Address, Scope: network.ScopeCloudLocal},
+		},
+	})
+	addresses, err := s.State.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.SameContents, []string{"10.0.1.2:1234", "[2001:db8::1]:1234"})
+}
+
 func (s *StateServerAddressesSuite) TestOtherEnv(c *gc.C) {
 	st := s.Factory.MakeEnvironment(c, nil)
 	defer st.Close()
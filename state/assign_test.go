@@ -271,6 +271,68 @@ func (s *AssignSuite) TestAssignBadSeries(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: series does not match`)
 }
 
+func (s *AssignSuite) TestAssignBadArch(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("arch=arm64"))
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware("arch=amd64")
+	err = machine.SetProvisioned("inst-id", "fake_nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: machine architecture "amd64" does not match required architecture "arm64"`)
+}
+
+func (s *AssignSuite) TestAssignBadArchUnprovisioned(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("arch=arm64"))
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("arch=ppc64el"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: machine architecture "ppc64el" does not match required architecture "arm64"`)
+}
+
+func (s *AssignSuite) TestAssignBadArchContainer(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("arch=arm64"))
+	c.Assert(err, jc.ErrorIsNil)
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware("arch=amd64")
+	err = host.SetProvisioned("inst-id", "fake_nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0/lxc/0: machine architecture "amd64" does not match required architecture "arm64"`)
+}
+
+func (s *AssignSuite) TestAssignMatchingArch(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("arch=amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware("arch=amd64")
+	err = machine.SetProvisioned("inst-id", "fake_nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *AssignSuite) TestAssignMachineWhenDying(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -659,6 +721,52 @@ func (s *AssignSuite) TestAssignUnitNewPolicyWithDefaultContainerConstraint(c *g
 	s.assertAssignUnitNewPolicyWithContainerConstraint(c)
 }
 
+func (s *AssignSuite) TestAssignUnitLeastLoadedPolicy(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron) // bootstrap machine
+	c.Assert(err, jc.ErrorIsNil)
+	var machines []*state.Machine
+	for _, count := range []int{2, 1} {
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		for i := 0; i < count; i++ {
+			unit, err := mysql.AddUnit()
+			c.Assert(err, jc.ErrorIsNil)
+			err = unit.AssignToMachine(m)
+			c.Assert(err, jc.ErrorIsNil)
+		}
+		machines = append(machines, m)
+	}
+
+	// Units are assigned to the machines hosting the fewest units,
+	// but never alongside another unit of the same service.
+	for _, expect := range []string{machines[1].Id(), machines[0].Id(), "3"} {
+		unit, err := s.wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.State.AssignUnit(unit, state.AssignLeastLoaded)
+		c.Assert(err, jc.ErrorIsNil)
+		id, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(id, gc.Equals, expect)
+	}
+	assertMachineCount(c, s.State, 4)
+}
+
+func (s *AssignSuite) TestAssignUnitLeastLoadedPolicyIgnoresDeadMachines(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AssignUnit(unit, state.AssignLeastLoaded)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "1")
+}
+
 func (s *AssignSuite) TestAssignUnitWithSubordinate(c *gc.C) {
 	_, err := s.State.AddMachine("quantal", state.JobManageEnviron) // bootstrap machine
 	c.Assert(err, jc.ErrorIsNil)
@@ -668,7 +776,7 @@ func (s *AssignSuite) TestAssignUnitWithSubordinate(c *gc.C) {
 	// Check cannot assign subordinates to machines
 	subUnit := s.addSubordinate(c, unit)
 	for _, policy := range []state.AssignmentPolicy{
-		state.AssignLocal, state.AssignNew, state.AssignClean, state.AssignCleanEmpty,
+		state.AssignLocal, state.AssignNew, state.AssignClean, state.AssignCleanEmpty, state.AssignLeastLoaded,
 	} {
 		err = s.State.AssignUnit(subUnit, policy)
 		c.Assert(err, gc.ErrorMatches, `subordinate unit "logging/0" cannot be assigned directly to a machine`)
@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// AuditEntry records a single mutating API call.
+type AuditEntry struct {
+	// Time is when the call completed.
+	Time time.Time
+
+	// Caller is the tag of the authenticated entity that made the call.
+	Caller string
+
+	// Facade, Version and Method identify the facade method called.
+	Facade  string
+	Version int
+	Method  string
+
+	// Args holds the call's arguments, serialised as JSON.
+	Args string
+
+	// Result holds the call's result, serialised as JSON.
+	Result string
+
+	// Error holds the error returned by the call, if any.
+	Error string
+}
+
+// AuditFilter restricts the entries returned by AuditEntries.
+// Zero-valued fields do not restrict the results.
+type AuditFilter struct {
+	// Caller restricts entries to those made by the given entity tag.
+	Caller string
+
+	// Facade restricts entries to those made on the given facade.
+	Facade string
+
+	// Since restricts entries to those recorded after the given time.
+	Since time.Time
+
+	// Limit restricts the number of entries returned.
+	Limit int
+}
+
+// auditDoc is the document used to store an AuditEntry in the
+// audit collection.
+type auditDoc struct {
+	Id      bson.ObjectId `bson:"_id"`
+	EnvUUID string        `bson:"env-uuid"`
+	Time    time.Time     `bson:"time"`
+	Caller  string        `bson:"caller"`
+	Facade  string        `bson:"facade"`
+	Version int           `bson:"version"`
+	Method  string        `bson:"method"`
+	Args    string        `bson:"args,omitempty"`
+	Result  string        `bson:"result,omitempty"`
+	Error   string        `bson:"error,omitempty"`
+}
+
+// ensureAuditLog creates the capped collection that holds the audit
+// log, and its index, if they do not yet exist. The collection is
+// only created when the first entry is added, so that no space is
+// allocated for it while auditing is disabled.
+func (st *State) ensureAuditLog() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.auditLogReady {
+		return nil
+	}
+	audit, closer := st.getRawCollection(auditC)
+	defer closer()
+	err := audit.Create(&mgo.CollectionInfo{Capped: true, MaxBytes: auditLogSize})
+	if isCollectionExistsError(err) {
+		return maybeUnauthorized(err, "cannot create audit log collection")
+	}
+	index := mgo.Index{Key: []string{"env-uuid", "caller"}}
+	if err := audit.EnsureIndex(index); err != nil {
+		return errors.Annotate(err, "cannot create audit log index")
+	}
+	st.auditLogReady = true
+	return nil
+}
+
+// AddAuditEntry records the given entry in the audit log for the
+// environment. The log is held in a capped collection, so the oldest
+// entries are discarded once it is full.
+func (st *State) AddAuditEntry(entry AuditEntry) error {
+	if err := st.ensureAuditLog(); err != nil {
+		return errors.Trace(err)
+	}
+	audit, closer := st.getRawCollection(auditC)
+	defer closer()
+
+	err := audit.Insert(&auditDoc{
+		Id:      bson.NewObjectId(),
+		EnvUUID: st.EnvironUUID(),
+		Time:    entry.Time.UTC(),
+		Caller:  entry.Caller,
+		Facade:  entry.Facade,
+		Version: entry.Version,
+		Method:  entry.Method,
+		Args:    entry.Args,
+		Result:  entry.Result,
+		Error:   entry.Error,
+	})
+	return errors.Annotate(err, "cannot add audit entry")
+}
+
+// AuditEntries returns the audit log entries for the environment that
+// match the given filter, most recent first.
+func (st *State) AuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	audit, closer := st.getRawCollection(auditC)
+	defer closer()
+
+	sel := bson.D{{"env-uuid", st.EnvironUUID()}}
+	if filter.Caller != "" {
+		sel = append(sel, bson.DocElem{"caller", filter.Caller})
+	}
+	if filter.Facade != "" {
+		sel = append(sel, bson.DocElem{"facade", filter.Facade})
+	}
+	if !filter.Since.IsZero() {
+		sel = append(sel, bson.DocElem{"time", bson.D{{"$gt", filter.Since.UTC()}}})
+	}
+	// Documents in a capped collection are kept in insertion order.
+	query := audit.Find(sel).Sort("-$natural")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []auditDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read audit entries")
+	}
+	entries := make([]AuditEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = AuditEntry{
+			Time:    doc.Time.UTC(),
+			Caller:  doc.Caller,
+			Facade:  doc.Facade,
+			Version: doc.Version,
+			Method:  doc.Method,
+			Args:    doc.Args,
+			Result:  doc.Result,
+			Error:   doc.Error,
+		}
+	}
+	return entries, nil
+}
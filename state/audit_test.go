@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type AuditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+func (s *AuditSuite) addEntries(c *gc.C, st *state.State, entries ...state.AuditEntry) {
+	for _, entry := range entries {
+		err := st.AddAuditEntry(entry)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *AuditSuite) TestAuditEntries(c *gc.C) {
+	// Mongo only stores times to millisecond precision.
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []state.AuditEntry{{
+		Time:   t0,
+		Caller: "user-admin@local",
+		Facade: "Client",
+		Method: "AddMachinesV2",
+		Args:   `{"MachineParams":[]}`,
+		Result: `{"Machines":[]}`,
+	}, {
+		Time:   t0.Add(time.Second),
+		Caller: "user-bob@local",
+		Facade: "Client",
+		Method: "DestroyMachines",
+		Error:  "permission denied",
+	}, {
+		Time:    t0.Add(2 * time.Second),
+		Caller:  "user-admin@local",
+		Facade:  "Service",
+		Version: 1,
+		Method:  "SetMetricCredentials",
+	}}
+	s.addEntries(c, s.State, entries...)
+
+	all, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, []state.AuditEntry{entries[2], entries[1], entries[0]})
+
+	byCaller, err := s.State.AuditEntries(state.AuditFilter{Caller: "user-admin@local"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(byCaller, jc.DeepEquals, []state.AuditEntry{entries[2], entries[0]})
+
+	byFacade, err := s.State.AuditEntries(state.AuditFilter{Facade: "Client"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(byFacade, jc.DeepEquals, []state.AuditEntry{entries[1], entries[0]})
+
+	since, err := s.State.AuditEntries(state.AuditFilter{Since: t0})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(since, jc.DeepEquals, []state.AuditEntry{entries[2], entries[1]})
+
+	limited, err := s.State.AuditEntries(state.AuditFilter{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limited, jc.DeepEquals, []state.AuditEntry{entries[2]})
+}
+
+func (s *AuditSuite) TestAuditEntriesPerEnvironment(c *gc.C) {
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+
+	entry := state.AuditEntry{
+		Time:   time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC),
+		Caller: "user-admin@local",
+		Facade: "Client",
+		Method: "DestroyMachines",
+	}
+	s.addEntries(c, st, entry)
+
+	entries, err := st.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, jc.DeepEquals, []state.AuditEntry{entry})
+
+	entries, err = s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+func (s *AuditSuite) TestAuditLogCreatedOnFirstEntry(c *gc.C) {
+	audit, closer := state.GetRawCollection(s.State, "audit")
+	defer closer()
+	names, err := audit.Database.CollectionNames()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range names {
+		c.Assert(name, gc.Not(gc.Equals), "audit")
+	}
+
+	s.addEntries(c, s.State, state.AuditEntry{
+		Time:   time.Now(),
+		Caller: "user-admin@local",
+		Facade: "Client",
+		Method: "DestroyMachines",
+	})
+	var result struct {
+		Capped bool `bson:"capped"`
+	}
+	err = audit.Database.Run(bson.D{{"collStats", "audit"}}, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Capped, jc.IsTrue)
+}
@@ -48,6 +48,11 @@ type ArchivePaths struct {
 
 	// MetadataFile is the path to the metadata file.
 	MetadataFile string
+
+	// AgentKeysDir is the path to the directory within the archive
+	// contents that holds the sealed agent config secrets keys,
+	// one file per agent named after its tag.
+	AgentKeysDir string
 }
 
 // NewCanonicalArchivePaths composes a new ArchivePaths with default
@@ -60,6 +65,7 @@ func NewCanonicalArchivePaths() ArchivePaths {
 		FilesBundle:  path.Join(contentDir, filesBundle),
 		DBDumpDir:    path.Join(contentDir, dbDumpDir),
 		MetadataFile: path.Join(contentDir, metadataFile),
+		AgentKeysDir: path.Join(contentDir, agentKeysDir),
 	}
 }
 
@@ -74,6 +80,7 @@ func NewNonCanonicalArchivePaths(rootDir string) ArchivePaths {
 		FilesBundle:  filepath.Join(rootDir, contentDir, filesBundle),
 		DBDumpDir:    filepath.Join(rootDir, contentDir, dbDumpDir),
 		MetadataFile: filepath.Join(rootDir, contentDir, metadataFile),
+		AgentKeysDir: filepath.Join(rootDir, contentDir, agentKeysDir),
 	}
 }
 
@@ -56,6 +56,7 @@ var logger = loggo.GetLogger("juju.state.backups")
 
 var (
 	getFilesToBackUp = GetFilesToBackUp
+	getAgentKeys     = agentSecretKeys
 	getDBDumper      = NewDBDumper
 	runCreate        = create
 	finishMeta       = func(meta *Metadata, result *createResult) error {
@@ -138,7 +139,16 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	if err != nil {
 		return errors.Annotate(err, "while preparing for DB dump")
 	}
-	args := createArgs{filesToBackUp, dumper, metadataFile}
+	agentKeys, err := getAgentKeys("", paths)
+	if err != nil {
+		return errors.Annotate(err, "while sealing agent config keys")
+	}
+	args := createArgs{
+		filesToBackUp:  filesToBackUp,
+		db:             dumper,
+		metadataReader: metadataFile,
+		agentKeys:      agentKeys,
+	}
 	result, err := runCreate(&args)
 	if err != nil {
 		return errors.Annotate(err, "while creating backup archive")
@@ -7,6 +7,9 @@ package backups
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -63,6 +66,9 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 		return errors.Annotate(err, "cannot determine DataDir for the restored machine")
 	}
 	agentConfigFile := agent.ConfigPath(datadir, args.NewInstTag)
+	if err := importAgentKey(workspace, backupMachine, filepath.Dir(agentConfigFile), args.KeyPassphrase); err != nil {
+		return errors.Annotate(err, "cannot restore agent config secrets key")
+	}
 	if agentConfig, err = agent.ReadConfig(agentConfigFile); err != nil {
 		return errors.Annotate(err, "cannot load agent config from disk")
 	}
@@ -148,3 +154,17 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 
 	return errors.Annotate(err, "failed to set status to finished")
 }
+
+// importAgentKey writes the key sealing the secrets of the given
+// agent's config, stored sealed in the workspace, to the given agent
+// config directory. Archives made before the agent config secrets were
+// sealed hold no keys, and need none.
+func importAgentKey(ws *ArchiveWorkspace, tag names.Tag, dir, passphrase string) error {
+	sealed, err := ioutil.ReadFile(filepath.Join(ws.AgentKeysDir, tag.String()))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return agent.ImportSecretKey(dir, passphrase, string(sealed))
+}
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -31,6 +32,7 @@ type createArgs struct {
 	filesToBackUp  []string
 	db             DBDumper
 	metadataReader io.Reader
+	agentKeys      map[string]string
 }
 
 type createResult struct {
@@ -63,6 +65,9 @@ func create(args *createArgs) (_ *createResult, err error) {
 	if err := builder.injectMetadataFile(args.metadataReader); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := builder.injectAgentKeys(args.agentKeys); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// Build the backup.
 	if err := builder.buildAll(); err != nil {
@@ -239,6 +244,24 @@ func (b *builder) injectMetadataFile(source io.Reader) error {
 	return errors.Trace(err)
 }
 
+// injectAgentKeys writes the given sealed agent config secrets keys,
+// keyed by agent tag, into the archive.
+func (b *builder) injectAgentKeys(keys map[string]string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(b.archivePaths.AgentKeysDir, 0700); err != nil {
+		return errors.Annotate(err, "while creating agent keys dir")
+	}
+	for tag, sealed := range keys {
+		filename := filepath.Join(b.archivePaths.AgentKeysDir, tag)
+		if err := writeAll(filename, strings.NewReader(sealed)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 func writeAll(targetname string, source io.Reader) error {
 	target, err := os.Create(targetname)
 	if err != nil {
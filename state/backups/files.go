@@ -10,6 +10,8 @@ import (
 	"sort"
 
 	"github.com/juju/errors"
+
+	"github.com/juju/juju/agent"
 )
 
 // TODO(ericsnow) lp-1392876
@@ -41,8 +43,20 @@ const (
 type Paths struct {
 	DataDir string
 	LogsDir string
+
+	// KeyPassphrase, if set, is used to seal the keys that seal the
+	// secrets of the backed up agent configs, so that they can be
+	// stored in the archive. State servers use the environment's CA
+	// private key, which its clients also hold. If it is not set,
+	// the keys are not stored and the agent configs cannot be
+	// restored from the archive.
+	KeyPassphrase string
 }
 
+// agentKeysDir is the directory, within the archive content
+// directory, holding the sealed agent config secrets keys.
+const agentKeysDir = "agent-keys"
+
 // GetFilesToBackUp returns the paths that should be included in the
 // backup archive.
 func GetFilesToBackUp(rootDir string, paths *Paths, oldmachine string) ([]string, error) {
@@ -68,7 +82,13 @@ func GetFilesToBackUp(rootDir string, paths *Paths, oldmachine string) ([]string
 		filepath.Join(rootDir, paths.DataDir, dbPEM),
 		filepath.Join(rootDir, paths.DataDir, dbSecret),
 	}
-	backupFiles = append(backupFiles, agentConfs...)
+	for _, conf := range agentConfs {
+		files, err := agentConfFiles(conf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		backupFiles = append(backupFiles, files...)
+	}
 	backupFiles = append(backupFiles, jujuLogConfs...)
 
 	// Handle logs (might not exist).
@@ -118,6 +138,64 @@ func GetFilesToBackUp(rootDir string, paths *Paths, oldmachine string) ([]string
 	return backupFiles, nil
 }
 
+// agentConfFiles returns the files to back up for the given agent
+// config file or directory. The key sealing an agent config's secrets
+// is left out; it is stored in the archive sealed separately (see
+// agentSecretKeys).
+func agentConfFiles(conf string) ([]string, error) {
+	info, err := os.Stat(conf)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !info.IsDir() {
+		return []string{conf}, nil
+	}
+	f, err := os.Open(conf)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot list agent config dir %q", conf)
+	}
+	sort.Strings(names)
+	var files []string
+	for _, name := range names {
+		if name == agent.SecretKeyFilename {
+			continue
+		}
+		files = append(files, filepath.Join(conf, name))
+	}
+	return files, nil
+}
+
+// agentSecretKeys returns the keys sealing the secrets of the agent
+// configs under the given root, each sealed with the passphrase in
+// paths, keyed by agent tag.
+func agentSecretKeys(rootDir string, paths *Paths) (map[string]string, error) {
+	if paths.KeyPassphrase == "" {
+		logger.Warningf("no passphrase for agent config secrets keys; the backup cannot be restored")
+		return nil, nil
+	}
+	glob := filepath.Join(rootDir, paths.DataDir, agentsDir, agentsConfs)
+	agentConfs, err := filepath.Glob(glob)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to fetch agent config dirs")
+	}
+	keys := make(map[string]string)
+	for _, conf := range agentConfs {
+		sealed, err := agent.ExportSecretKey(conf, paths.KeyPassphrase)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys[filepath.Base(conf)] = sealed
+	}
+	return keys, nil
+}
+
 // replaceableFolders for testing purposes.
 var replaceableFolders = replaceableFoldersFunc
 
@@ -6,6 +6,7 @@
 package backups_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
@@ -241,3 +242,27 @@ func (s *filesSuite) TestGetFilesToBackUpMissing(c *gc.C) {
 	c.Check(files, jc.SameContents, expected)
 	s.checkSameStrings(c, files, expected)
 }
+
+func (s *filesSuite) TestGetFilesToBackUpSkipsAgentSecretKey(c *gc.C) {
+	paths := backups.Paths{
+		DataDir: "/var/lib/juju",
+		LogsDir: "/var/log/juju",
+	}
+	s.createFiles(c, paths, s.root, "0")
+	err := os.Remove(filepath.Join(s.root, "/var/lib/juju/agents/machine-0.conf"))
+	c.Assert(err, jc.ErrorIsNil)
+	agentDir := filepath.Join(s.root, "/var/lib/juju/agents/machine-0")
+	err = os.MkdirAll(agentDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range []string{"agent.conf", "agent.key"} {
+		err = ioutil.WriteFile(filepath.Join(agentDir, name), nil, 0600)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	files, err := backups.GetFilesToBackUp(s.root, &paths, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	backedUp := set.NewStrings(files...)
+	c.Check(backedUp.Contains(filepath.Join(agentDir, "agent.conf")), jc.IsTrue)
+	c.Check(backedUp.Contains(filepath.Join(agentDir, "agent.key")), jc.IsFalse)
+	c.Check(backedUp.Contains(agentDir), jc.IsFalse)
+}
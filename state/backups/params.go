@@ -16,4 +16,8 @@ type RestoreArgs struct {
 	NewInstId      instance.Id
 	NewInstTag     names.Tag
 	NewInstSeries  string
+
+	// KeyPassphrase unseals the agent config secrets keys stored in
+	// the backup archive; see Paths.KeyPassphrase.
+	KeyPassphrase string
 }
@@ -24,6 +24,9 @@ const (
 	cleanupServicesForDyingEnvironment cleanupKind = "services"
 	cleanupForceDestroyedMachine       cleanupKind = "machine"
 	cleanupAttachmentsForDyingStorage  cleanupKind = "storageAttachments"
+	cleanupAddressesForDeadMachine     cleanupKind = "machineAddresses"
+	cleanupAttachmentsForDeadMachine   cleanupKind = "machineAttachments"
+	cleanupContainersForDeadMachine    cleanupKind = "machineContainers"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -88,6 +91,12 @@ func (st *State) Cleanup() error {
 			err = st.cleanupForceDestroyedMachine(doc.Prefix)
 		case cleanupAttachmentsForDyingStorage:
 			err = st.cleanupAttachmentsForDyingStorage(doc.Prefix)
+		case cleanupAddressesForDeadMachine:
+			err = st.cleanupAddressesForDeadMachine(doc.Prefix)
+		case cleanupAttachmentsForDeadMachine:
+			err = st.cleanupAttachmentsForDeadMachine(doc.Prefix)
+		case cleanupContainersForDeadMachine:
+			err = st.cleanupContainersForDeadMachine(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -294,6 +303,77 @@ func (st *State) cleanupForceDestroyedMachine(machineId string) error {
 	// instance that would otherwise be ignored when in provisioner-safe-mode.
 }
 
+// cleanupAddressesForDeadMachine marks all IP addresses allocated to the
+// supplied machine as Dead, so that they will be released by the
+// addresser. It's expected to be used when a machine becomes Dead.
+func (st *State) cleanupAddressesForDeadMachine(machineId string) error {
+	addresses, err := st.AllocatedIPAddresses(machineId)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get addresses of machine %s", machineId)
+	}
+	for _, addr := range addresses {
+		if err := addr.EnsureDead(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupContainersForDeadMachine removes any containers left on the
+// dead machine. A machine hosting containers cannot become Dead, but
+// containers may be added while it is becoming so; nothing else would
+// remove those.
+func (st *State) cleanupContainersForDeadMachine(machineId string) error {
+	machine, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return st.cleanupContainers(machine)
+}
+
+// cleanupAttachmentsForDeadMachine detaches all volumes and filesystems
+// attached to the dead machine. Attachments of environment-scoped
+// storage are left Dying for the environment storage provisioner to
+// detach and remove. Attachments of storage scoped to the machine are
+// removed here, since the machine's storage provisioner is no longer
+// running and the machine's instance is about to be destroyed.
+func (st *State) cleanupAttachmentsForDeadMachine(machineId string) error {
+	machineTag := names.NewMachineTag(machineId)
+	volumeAttachments, err := st.MachineVolumeAttachments(machineTag)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get volume attachments of machine %s", machineId)
+	}
+	for _, va := range volumeAttachments {
+		if err := st.DetachVolume(machineTag, va.Volume()); err != nil {
+			return err
+		}
+		if _, ok := names.VolumeMachine(va.Volume()); !ok {
+			continue
+		}
+		if err := st.RemoveVolumeAttachment(machineTag, va.Volume()); err != nil {
+			return err
+		}
+	}
+	filesystemAttachments, err := st.MachineFilesystemAttachments(machineTag)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get filesystem attachments of machine %s", machineId)
+	}
+	for _, fsa := range filesystemAttachments {
+		if err := st.DetachFilesystem(machineTag, fsa.Filesystem()); err != nil {
+			return err
+		}
+		if _, ok := names.FilesystemMachine(fsa.Filesystem()); !ok {
+			continue
+		}
+		if err := st.RemoveFilesystemAttachment(machineTag, fsa.Filesystem()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // cleanupContainers recursively calls cleanupForceDestroyedMachine on the supplied
 // machine's containers, and removes them from state entirely.
 func (st *State) cleanupContainers(machine *Machine) error {
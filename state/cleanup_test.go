@@ -13,8 +13,11 @@ import (
 	"gopkg.in/juju/charm.v5"
 
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/storage/provider/dummy"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -26,7 +29,13 @@ var _ = gc.Suite(&CleanupSuite{})
 
 func (s *CleanupSuite) SetUpSuite(c *gc.C) {
 	s.ConnSuite.SetUpSuite(c)
-	registry.RegisterEnvironStorageProviders("someprovider", provider.LoopProviderType)
+	registry.RegisterProvider("environscoped", &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+	})
+	registry.RegisterEnvironStorageProviders("someprovider", provider.LoopProviderType, "environscoped")
+	s.AddSuiteCleanup(func(c *gc.C) {
+		registry.RegisterProvider("environscoped", nil)
+	})
 }
 
 func (s *CleanupSuite) TestCleanupDyingServiceUnits(c *gc.C) {
@@ -252,6 +261,159 @@ func (s *CleanupSuite) TestCleanupForceDestroyedMachineWithContainer(c *gc.C) {
 	assertLife(c, machine, state.Dead)
 }
 
+func (s *CleanupSuite) TestCleanupForceDestroyedMachineReleasesAddresses(c *gc.C) {
+	s.assertDoesNotNeedCleanup(c)
+
+	// Create a machine with a container, and allocate addresses to both.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	machineAddr := s.allocateAddress(c, "0.1.2.3", machine.Id())
+	containerAddr := s.allocateAddress(c, "0.1.2.4", container.Id())
+	otherAddr := s.allocateAddress(c, "0.1.2.5", "42")
+
+	// Force machine destruction, and clean up. The second pass releases
+	// the addresses of the now dead machine and container.
+	err = machine.ForceDestroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupCount(c, 2)
+	assertLife(c, machine, state.Dead)
+
+	// Check that the addresses of the machine and its container have
+	// been marked for release, but that other addresses are untouched.
+	assertAddressLife(c, machineAddr, state.Dead)
+	assertAddressLife(c, containerAddr, state.Dead)
+	assertAddressLife(c, otherAddr, state.Alive)
+}
+
+func (s *CleanupSuite) TestCleanupDeadMachineAddresses(c *gc.C) {
+	s.assertDoesNotNeedCleanup(c)
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	addr := s.allocateAddress(c, "0.1.2.3", machine.Id())
+
+	// Destroying the machine does not affect its addresses...
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDoesNotNeedCleanup(c)
+	assertAddressLife(c, addr, state.Alive)
+
+	// ...but once it's dead, a cleanup is scheduled to release them.
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNeedsCleanup(c)
+	assertAddressLife(c, addr, state.Alive)
+
+	s.assertCleanupCount(c, 1)
+	assertAddressLife(c, addr, state.Dead)
+}
+
+func (s *CleanupSuite) TestCleanupDeadMachineContainers(c *gc.C) {
+	s.assertDoesNotNeedCleanup(c)
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Add a container while the machine is becoming dead.
+	var container *state.Machine
+	defer state.SetBeforeHooks(c, s.State, func() {
+		container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+			Series: "quantal",
+			Jobs:   []state.MachineJob{state.JobHostUnits},
+		}, machine.Id(), instance.LXC)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNeedsCleanup(c)
+	assertLife(c, container, state.Alive)
+
+	// The cleanup removes the container.
+	s.assertCleanupCount(c, 1)
+	assertRemoved(c, container)
+}
+
+func (s *CleanupSuite) TestCleanupDeadMachineAttachments(c *gc.C) {
+	s.assertDoesNotNeedCleanup(c)
+
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.MachineVolumeParams{{
+			Volume: state.VolumeParams{Pool: "loop", Size: 1024},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := s.State.MachineVolumeAttachments(machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	volumeTag := attachments[0].Volume()
+
+	// Once the machine is dead, a cleanup is scheduled to detach
+	// its storage and remove the attachments.
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupCount(c, 1)
+
+	_, err = s.State.VolumeAttachment(machine.MachineTag(), volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	attachments, err = s.State.MachineVolumeAttachments(machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+}
+
+func (s *CleanupSuite) TestCleanupDeadMachineEnvironAttachments(c *gc.C) {
+	s.assertDoesNotNeedCleanup(c)
+
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.MachineVolumeParams{{
+			Volume: state.VolumeParams{Pool: "environscoped", Size: 1024},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := s.State.MachineVolumeAttachments(machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	volumeTag := attachments[0].Volume()
+
+	// The cleanup only detaches environment-scoped storage; the
+	// environment storage provisioner removes the attachment once
+	// the volume has been detached from the instance.
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupCount(c, 1)
+
+	attachment, err := s.State.VolumeAttachment(machine.MachineTag(), volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachment.Life(), gc.Equals, state.Dying)
+}
+
+func (s *CleanupSuite) allocateAddress(c *gc.C, value, machineId string) *state.IPAddress {
+	addr := network.NewScopedAddress(value, network.ScopeCloudLocal)
+	ipAddr, err := s.State.AddIPAddress(addr, "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.AllocateTo(machineId, "wibble")
+	c.Assert(err, jc.ErrorIsNil)
+	return ipAddr
+}
+
+func assertAddressLife(c *gc.C, ipAddr *state.IPAddress, life state.Life) {
+	err := ipAddr.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddr.Life(), gc.Equals, life)
+}
+
 func (s *CleanupSuite) TestCleanupDyingUnit(c *gc.C) {
 	// Create active unit, in a relation.
 	prr := NewProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
@@ -93,9 +93,11 @@ var multiEnvCollections = set.NewStrings(
 	storageConstraintsC,
 	storageInstancesC,
 	subnetsC,
+	unitEventsC,
 	unitsC,
 	volumesC,
 	volumeAttachmentsC,
+	workerHealthC,
 )
 
 func newStateCollection(coll *mgo.Collection, envUUID string) stateCollection {
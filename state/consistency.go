@@ -0,0 +1,277 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// InconsistencyKind identifies a kind of dangling reference in state.
+type InconsistencyKind string
+
+const (
+	// UnitMachineMissing identifies a unit assigned to a machine
+	// that no longer exists.
+	UnitMachineMissing InconsistencyKind = "unit-machine-missing"
+
+	// AddressMachineMissing identifies a live IP address allocated
+	// to a machine that no longer exists.
+	AddressMachineMissing InconsistencyKind = "address-machine-missing"
+
+	// RelationServiceMissing identifies a relation with an endpoint
+	// on a service that no longer exists.
+	RelationServiceMissing InconsistencyKind = "relation-service-missing"
+)
+
+// Inconsistency describes a document in state that refers to another
+// document that no longer exists, typically left behind by a bug in
+// an earlier version of juju.
+type Inconsistency struct {
+	// Kind identifies the kind of inconsistency.
+	Kind InconsistencyKind
+
+	// Entity identifies the document holding the dangling reference:
+	// a unit name, an IP address or a relation key, depending on Kind.
+	Entity string
+
+	// Missing identifies the document referred to that no longer
+	// exists: a machine id or a service name, depending on Kind.
+	Missing string
+}
+
+func (i Inconsistency) String() string {
+	switch i.Kind {
+	case UnitMachineMissing:
+		return fmt.Sprintf("unit %q is assigned to missing machine %q", i.Entity, i.Missing)
+	case AddressMachineMissing:
+		return fmt.Sprintf("IP address %q is allocated to missing machine %q", i.Entity, i.Missing)
+	case RelationServiceMissing:
+		return fmt.Sprintf("relation %q refers to missing service %q", i.Entity, i.Missing)
+	}
+	return fmt.Sprintf("%s: %q refers to missing %q", i.Kind, i.Entity, i.Missing)
+}
+
+// CheckConsistency scans the environment for documents that refer to
+// machines or services that no longer exist, and returns a description
+// of each one found. It does not change anything; inconsistencies can
+// be repaired with RepairInconsistency.
+func (st *State) CheckConsistency() ([]Inconsistency, error) {
+	machineIds, err := st.allDocIds(machinesC, "machineid")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read machines")
+	}
+	serviceNames, err := st.allDocIds(servicesC, "name")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read services")
+	}
+	var result []Inconsistency
+
+	units, closer := st.getCollection(unitsC)
+	defer closer()
+	var unitDoc struct {
+		Name      string `bson:"name"`
+		MachineId string `bson:"machineid"`
+	}
+	iter := units.Find(bson.D{{"machineid", bson.D{{"$exists", true}, {"$ne", ""}}}}).Iter()
+	for iter.Next(&unitDoc) {
+		if !machineIds.Contains(unitDoc.MachineId) {
+			result = append(result, Inconsistency{UnitMachineMissing, unitDoc.Name, unitDoc.MachineId})
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot read units")
+	}
+
+	addresses, closer := st.getCollection(ipaddressesC)
+	defer closer()
+	var addrDoc ipaddressDoc
+	iter = addresses.Find(bson.D{
+		{"life", Alive},
+		{"machineid", bson.D{{"$exists", true}, {"$ne", ""}}},
+	}).Iter()
+	for iter.Next(&addrDoc) {
+		if !machineIds.Contains(addrDoc.MachineId) {
+			result = append(result, Inconsistency{AddressMachineMissing, addrDoc.Value, addrDoc.MachineId})
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot read IP addresses")
+	}
+
+	relations, closer := st.getCollection(relationsC)
+	defer closer()
+	var relDoc relationDoc
+	iter = relations.Find(nil).Iter()
+	for iter.Next(&relDoc) {
+		for _, ep := range relDoc.Endpoints {
+			if !serviceNames.Contains(ep.ServiceName) {
+				result = append(result, Inconsistency{RelationServiceMissing, relDoc.Key, ep.ServiceName})
+			}
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot read relations")
+	}
+	return result, nil
+}
+
+// allDocIds returns the values of the given field of every document
+// in the named collection.
+func (st *State) allDocIds(collection, field string) (set.Strings, error) {
+	coll, closer := st.getCollection(collection)
+	defer closer()
+	var doc bson.M
+	ids := set.NewStrings()
+	iter := coll.Find(nil).Select(bson.M{field: 1}).Iter()
+	for iter.Next(&doc) {
+		if id, ok := doc[field].(string); ok {
+			ids.Add(id)
+		}
+	}
+	return ids, iter.Close()
+}
+
+// RepairInconsistency repairs an inconsistency found by CheckConsistency:
+// a unit assigned to a missing machine is unassigned, an IP address
+// allocated to a missing machine is made Dead so that it is released,
+// and a relation with a missing service is removed, along with the
+// scopes of its remaining units. It is not an error if the
+// inconsistency no longer exists.
+func (st *State) RepairInconsistency(i Inconsistency) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot repair inconsistency: %s", i)
+	switch i.Kind {
+	case UnitMachineMissing:
+		return st.repairUnitMachineMissing(i.Entity, i.Missing)
+	case AddressMachineMissing:
+		return st.repairAddressMachineMissing(i.Entity, i.Missing)
+	case RelationServiceMissing:
+		return st.repairRelationServiceMissing(i.Entity, i.Missing)
+	}
+	return errors.NotValidf("inconsistency kind %q", i.Kind)
+}
+
+func (st *State) repairUnitMachineMissing(unitName, machineId string) error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     st.docID(machineId),
+		Assert: txn.DocMissing,
+	}, {
+		C:      unitsC,
+		Id:     st.docID(unitName),
+		Assert: bson.D{{"machineid", machineId}},
+		Update: bson.D{{"$set", bson.D{{"machineid", ""}}}},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		// Either the machine has reappeared, or the unit has been
+		// removed or reassigned; either way, there's nothing to do.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func (st *State) repairAddressMachineMissing(value, machineId string) error {
+	addr, err := st.IPAddress(value)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if addr.MachineId() != machineId || addr.Life() == Dead {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     st.docID(machineId),
+		Assert: txn.DocMissing,
+	}, {
+		C:      ipaddressesC,
+		Id:     addr.doc.DocID,
+		Assert: bson.D{{"machineid", machineId}, {"life", bson.D{{"$ne", Dead}}}},
+		Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		// Either the machine has reappeared, or the address has
+		// been reassigned or killed; either way, there's nothing
+		// to do.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func (st *State) repairRelationServiceMissing(key, serviceName string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		rel, err := st.KeyRelation(key)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     st.docID(serviceName),
+			Assert: txn.DocMissing,
+		}, {
+			C:      relationsC,
+			Id:     rel.doc.DocID,
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		services, closer := st.getCollection(servicesC)
+		defer closer()
+		for _, ep := range rel.Endpoints() {
+			if ep.ServiceName == serviceName {
+				continue
+			}
+			count, err := services.FindId(ep.ServiceName).Count()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if count == 0 {
+				// Both services are missing.
+				ops = append(ops, txn.Op{
+					C:      servicesC,
+					Id:     st.docID(ep.ServiceName),
+					Assert: txn.DocMissing,
+				})
+				continue
+			}
+			ops = append(ops, txn.Op{
+				C:      servicesC,
+				Id:     st.docID(ep.ServiceName),
+				Assert: bson.D{{"relationcount", bson.D{{"$gt", 0}}}},
+				Update: bson.D{{"$inc", bson.D{{"relationcount", -1}}}},
+			})
+		}
+		// Units of the surviving service may still be in the
+		// relation's scope; their scope documents go with it.
+		prefix := fmt.Sprintf("r#%d#", rel.Id())
+		relationScopes, closer := st.getCollection(relationScopesC)
+		defer closer()
+		var scopeDocs []relationScopeDoc
+		sel := bson.D{{"key", bson.D{{"$regex", "^" + prefix}}}}
+		if err := relationScopes.Find(sel).All(&scopeDocs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, doc := range scopeDocs {
+			ops = append(ops, txn.Op{
+				C:      relationScopesC,
+				Id:     doc.DocID,
+				Remove: true,
+			})
+		}
+		cleanupOp := st.newCleanupOp(cleanupRelationSettings, prefix)
+		return append(ops, cleanupOp), nil
+	}
+	return st.run(buildTxn)
+}
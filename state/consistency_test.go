@@ -0,0 +1,192 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type ConsistencySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ConsistencySuite{})
+
+func (s *ConsistencySuite) removeRawDoc(c *gc.C, collection, id string) {
+	coll, closer := state.GetRawCollection(s.State, collection)
+	defer closer()
+	err := coll.RemoveId(state.DocID(s.State, id))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ConsistencySuite) assertConsistent(c *gc.C) {
+	found, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 0)
+}
+
+func (s *ConsistencySuite) addRelation(c *gc.C) *state.Relation {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	return rel
+}
+
+func (s *ConsistencySuite) TestCheckConsistencyClean(c *gc.C) {
+	s.assertConsistent(c)
+
+	s.addRelation(c)
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertConsistent(c)
+}
+
+func (s *ConsistencySuite) TestUnitMachineMissing(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	s.removeRawDoc(c, state.MachinesC, machineId)
+
+	found, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []state.Inconsistency{{
+		Kind:    state.UnitMachineMissing,
+		Entity:  "wordpress/0",
+		Missing: machineId,
+	}})
+	c.Assert(found[0].String(), gc.Equals, `unit "wordpress/0" is assigned to missing machine "0"`)
+
+	err = s.State.RepairInconsistency(found[0])
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+	s.assertConsistent(c)
+
+	// Repairing again is a no-op.
+	err = s.State.RepairInconsistency(found[0])
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ConsistencySuite) TestAddressMachineMissing(c *gc.C) {
+	addr := network.NewScopedAddress("0.1.2.3", network.ScopeCloudLocal)
+	ipAddr, err := s.State.AddIPAddress(addr, "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.AllocateTo("42", "wibble")
+	c.Assert(err, jc.ErrorIsNil)
+
+	found, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []state.Inconsistency{{
+		Kind:    state.AddressMachineMissing,
+		Entity:  "0.1.2.3",
+		Missing: "42",
+	}})
+
+	err = s.State.RepairInconsistency(found[0])
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddr.Life(), gc.Equals, state.Dead)
+	s.assertConsistent(c)
+}
+
+func (s *ConsistencySuite) TestAddressMachineReappeared(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	addr := network.NewScopedAddress("0.1.2.3", network.ScopeCloudLocal)
+	ipAddr, err := s.State.AddIPAddress(addr, "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.AllocateTo(m.Id(), "wibble")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machine exists by the time the inconsistency is repaired,
+	// so the address is left alone.
+	err = s.State.RepairInconsistency(state.Inconsistency{
+		Kind:    state.AddressMachineMissing,
+		Entity:  "0.1.2.3",
+		Missing: m.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ipAddr.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddr.Life(), gc.Equals, state.Alive)
+}
+
+func (s *ConsistencySuite) TestRelationServiceMissing(c *gc.C) {
+	rel := s.addRelation(c)
+	s.removeRawDoc(c, state.ServicesC, "mysql")
+
+	found, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []state.Inconsistency{{
+		Kind:    state.RelationServiceMissing,
+		Entity:  rel.String(),
+		Missing: "mysql",
+	}})
+
+	err = s.State.RepairInconsistency(found[0])
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.KeyRelation(rel.String())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	relations, err := wordpress.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations, gc.HasLen, 0)
+	s.assertConsistent(c)
+
+	// The surviving service can now be destroyed as usual.
+	err = wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConsistencySuite) TestRepairInvalidKind(c *gc.C) {
+	err := s.State.RepairInconsistency(state.Inconsistency{Kind: "foo"})
+	c.Assert(err, gc.ErrorMatches, `cannot repair inconsistency: foo: "" refers to missing "": inconsistency kind "foo" not valid`)
+}
+
+func (s *ConsistencySuite) TestRelationServiceMissingRemovesScopes(c *gc.C) {
+	rel := s.addRelation(c)
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.removeRawDoc(c, state.ServicesC, "mysql")
+
+	found, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 1)
+	err = s.State.RepairInconsistency(found[0])
+	c.Assert(err, jc.ErrorIsNil)
+
+	relationScopes, closer := state.GetRawCollection(s.State, state.RelationScopesC)
+	defer closer()
+	count, err := relationScopes.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
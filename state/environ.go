@@ -120,8 +120,22 @@ func (st *State) NewEnvironment(cfg *config.Config, owner names.UserTag) (_ *Env
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to create new environment")
 	}
+	if owner.IsLocal() {
+		// The owner must not be removed while the environment is
+		// being created; User.Remove refuses to remove owners.
+		ops = append(ops, txn.Op{
+			C:      usersC,
+			Id:     strings.ToLower(owner.Name()),
+			Assert: txn.DocExists,
+		})
+	}
 	err = newState.runTransactionNoEnvAliveAssert(ops)
 	if err == txn.ErrAborted {
+		if owner.IsLocal() {
+			if _, err := st.User(owner); err != nil {
+				return nil, nil, errors.Annotate(err, "cannot create environment")
+			}
+		}
 
 		// We have a  unique key restriction on the "owner" and "name" fields,
 		// which will cause the insert to fail if there is another record with
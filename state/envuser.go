@@ -32,6 +32,7 @@ type envUserDoc struct {
 	CreatedBy      string     `bson:"createdby"`
 	DateCreated    time.Time  `bson:"datecreated"`
 	LastConnection *time.Time `bson:"lastconnection"`
+	ReadOnly       bool       `bson:"readonly,omitempty"`
 }
 
 // ID returns the ID of the environment user.
@@ -80,6 +81,32 @@ func (e *EnvironmentUser) LastConnection() *time.Time {
 	return &result
 }
 
+// ReadOnly returns whether the environment user may only make API
+// calls that do not change the environment.
+func (e *EnvironmentUser) ReadOnly() bool {
+	return e.doc.ReadOnly
+}
+
+// SetReadOnly sets whether the environment user may only make API
+// calls that do not change the environment.
+func (e *EnvironmentUser) SetReadOnly(readOnly bool) error {
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     e.ID(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"readonly", readOnly}}}},
+	}}
+	err := e.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("environment user %q", e.UserName())
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot set access for envuser %q", e.ID())
+	}
+	e.doc.ReadOnly = readOnly
+	return nil
+}
+
 // UpdateLastConnection updates the last connection time of the environment user.
 func (e *EnvironmentUser) UpdateLastConnection() error {
 	timestamp := nowToTheSecond()
@@ -116,6 +143,16 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 
 // AddEnvironmentUser adds a new user to the database.
 func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*EnvironmentUser, error) {
+	return st.addEnvironmentUser(user, createdBy, displayName, false)
+}
+
+// AddReadOnlyEnvironmentUser adds a new user to the database, who may
+// only make API calls that do not change the environment.
+func (st *State) AddReadOnlyEnvironmentUser(user, createdBy names.UserTag, displayName string) (*EnvironmentUser, error) {
+	return st.addEnvironmentUser(user, createdBy, displayName, true)
+}
+
+func (st *State) addEnvironmentUser(user, createdBy names.UserTag, displayName string, readOnly bool) (*EnvironmentUser, error) {
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
 		localUser, err := st.User(user)
@@ -136,6 +173,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName s
 
 	envuuid := st.EnvironUUID()
 	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName)
+	doc.ReadOnly = readOnly
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.AlreadyExistsf("environment user %q", user.Username())
@@ -48,6 +48,49 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.CreatedBy(), gc.Equals, "createdby@local")
 	c.Assert(envUser.DateCreated().Equal(now) || envUser.DateCreated().After(now), jc.IsTrue)
 	c.Assert(envUser.LastConnection(), gc.IsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsFalse)
+}
+
+func (s *EnvUserSuite) TestAddReadOnlyEnvironmentUser(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "auditor", NoEnvUser: true})
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	envUser, err := s.State.AddReadOnlyEnvironmentUser(user.UserTag(), createdBy.UserTag(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+}
+
+func (s *EnvUserSuite) TestSetReadOnly(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "auditor"})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = envUser.SetReadOnly(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsTrue)
+
+	err = envUser.SetReadOnly(false)
+	c.Assert(err, jc.ErrorIsNil)
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.ReadOnly(), jc.IsFalse)
+}
+
+func (s *EnvUserSuite) TestSetReadOnlyRemovedUser(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "auditor"})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveEnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = envUser.SetReadOnly(true)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
 func (s *EnvUserSuite) TestCaseSensitiveEnvUserErrors(c *gc.C) {
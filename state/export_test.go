@@ -26,6 +26,7 @@ const (
 	InstanceDataC      = instanceDataC
 	MachinesC          = machinesC
 	NetworkInterfacesC = networkInterfacesC
+	RelationScopesC    = relationScopesC
 	ServicesC          = servicesC
 	SettingsC          = settingsC
 	UnitsC             = unitsC
@@ -50,6 +51,7 @@ var (
 	AddVolumeOp            = (*State).addVolumeOp
 	CombineMeterStatus     = combineMeterStatus
 	NewStatusNotFound      = newStatusNotFound
+	MaxUnitEvents          = &maxUnitEvents
 )
 
 type (
@@ -198,6 +200,7 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	txnLogSize = txnLogSizeTests
+	auditLogSize = auditLogSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
@@ -299,6 +302,8 @@ func GetCollection(st *State, name string) (stateCollection, func()) {
 	return st.getCollection(name)
 }
 
+const MacaroonKeyLifetime = macaroonKeyLifetime
+
 func GetRawCollection(st *State, name string) (*mgo.Collection, func()) {
 	return st.getRawCollection(name)
 }
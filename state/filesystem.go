@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/juju/storage"
 	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
@@ -91,14 +92,15 @@ type filesystemAttachment struct {
 
 // filesystemDoc records information about a filesystem in the environment.
 type filesystemDoc struct {
-	DocID        string            `bson:"_id"`
-	FilesystemId string            `bson:"filesystemid"`
-	EnvUUID      string            `bson:"env-uuid"`
-	Life         Life              `bson:"life"`
-	StorageId    string            `bson:"storageid,omitempty"`
-	VolumeId     string            `bson:"volumeid,omitempty"`
-	Info         *FilesystemInfo   `bson:"info,omitempty"`
-	Params       *FilesystemParams `bson:"params,omitempty"`
+	DocID           string            `bson:"_id"`
+	FilesystemId    string            `bson:"filesystemid"`
+	EnvUUID         string            `bson:"env-uuid"`
+	Life            Life              `bson:"life"`
+	StorageId       string            `bson:"storageid,omitempty"`
+	VolumeId        string            `bson:"volumeid,omitempty"`
+	AttachmentCount int               `bson:"attachmentcount"`
+	Info            *FilesystemInfo   `bson:"info,omitempty"`
+	Params          *FilesystemParams `bson:"params,omitempty"`
 }
 
 // filesystemAttachmentDoc records information about a filesystem attachment.
@@ -230,6 +232,14 @@ func (f *filesystemAttachment) Params() (FilesystemAttachmentParams, bool) {
 
 // Filesystem returns the Filesystem with the specified name.
 func (st *State) Filesystem(tag names.FilesystemTag) (Filesystem, error) {
+	f, err := st.filesystemByTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (st *State) filesystemByTag(tag names.FilesystemTag) (*filesystem, error) {
 	coll, cleanup := st.getCollection(filesystemsC)
 	defer cleanup()
 
@@ -291,15 +301,33 @@ func (st *State) FilesystemAttachment(machine names.MachineTag, filesystem names
 // MachineFilesystemAttachments returns all of the FilesystemAttachments for the
 // specified machine.
 func (st *State) MachineFilesystemAttachments(machine names.MachineTag) ([]FilesystemAttachment, error) {
+	attachments, err := st.filesystemAttachments(bson.D{{"machineid", machine.Id()}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting filesystem attachments for machine %q", machine.Id())
+	}
+	return attachments, nil
+}
+
+// FilesystemAttachments returns all of the FilesystemAttachments for the
+// specified filesystem.
+func (st *State) FilesystemAttachments(filesystem names.FilesystemTag) ([]FilesystemAttachment, error) {
+	attachments, err := st.filesystemAttachments(bson.D{{"filesystemid", filesystem.Id()}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting filesystem attachments for filesystem %q", filesystem.Id())
+	}
+	return attachments, nil
+}
+
+func (st *State) filesystemAttachments(query bson.D) ([]FilesystemAttachment, error) {
 	coll, cleanup := st.getCollection(filesystemAttachmentsC)
 	defer cleanup()
 
 	var docs []filesystemAttachmentDoc
-	err := coll.Find(bson.D{{"machineid", machine.Id()}}).All(&docs)
+	err := coll.Find(query).All(&docs)
 	if err == mgo.ErrNotFound {
 		return nil, nil
 	} else if err != nil {
-		return nil, errors.Annotatef(err, "getting filesystem attachments for machine %q", machine.Id())
+		return nil, errors.Trace(err)
 	}
 	attachments := make([]FilesystemAttachment, len(docs))
 	for i, doc := range docs {
@@ -392,6 +420,9 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 			VolumeId:     volumeId,
 			StorageId:    params.storage.Id(),
 			Params:       &params,
+			// Filesystems are currently only created along with
+			// an attachment to the machine they are created for.
+			AttachmentCount: 1,
 		},
 	}
 	ops = append(ops, filesystemOp)
@@ -572,3 +603,203 @@ func setFilesystemAttachmentInfoOps(
 		Update: update,
 	}}
 }
+
+// DetachFilesystem marks the filesystem attachment identified by the
+// specified machine and filesystem tags as Dying, if it is Alive. The
+// storage provisioner is responsible for detaching the filesystem from
+// the machine and then removing the attachment with
+// RemoveFilesystemAttachment.
+func (st *State) DetachFilesystem(machine names.MachineTag, filesystem names.FilesystemTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "detaching filesystem %s from machine %s", filesystem.Id(), machine.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		fsa, err := st.FilesystemAttachment(machine, filesystem)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fsa.Life() != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return detachFilesystemOps(machine, filesystem), nil
+	}
+	return st.run(buildTxn)
+}
+
+func detachFilesystemOps(m names.MachineTag, f names.FilesystemTag) []txn.Op {
+	return []txn.Op{{
+		C:      filesystemAttachmentsC,
+		Id:     filesystemAttachmentId(m.Id(), f.Id()),
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+	}}
+}
+
+// RemoveFilesystemAttachment removes the filesystem attachment from
+// state. RemoveFilesystemAttachment will fail if the attachment is
+// Alive; it must first be detached with DetachFilesystem. If the
+// filesystem is Dying and this is its last attachment, the filesystem
+// becomes Dead.
+func (st *State) RemoveFilesystemAttachment(machine names.MachineTag, filesystem names.FilesystemTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "removing attachment of filesystem %s from machine %s", filesystem.Id(), machine.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		attachment, err := st.FilesystemAttachment(machine, filesystem)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if attachment.Life() != Dying {
+			return nil, errors.New("filesystem attachment is not dying")
+		}
+		f, err := st.filesystemByTag(filesystem)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return removeFilesystemAttachmentOps(machine, f), nil
+	}
+	return st.run(buildTxn)
+}
+
+func removeFilesystemAttachmentOps(m names.MachineTag, f *filesystem) []txn.Op {
+	decrefFilesystemOp := txn.Op{
+		C:  filesystemsC,
+		Id: f.doc.FilesystemId,
+	}
+	if f.doc.Life == Dying && f.doc.AttachmentCount == 1 {
+		// This is the last attachment of a Dying filesystem, so
+		// the filesystem is now ready to be destroyed by the
+		// storage provisioner.
+		decrefFilesystemOp.Assert = bson.D{{"life", Dying}, {"attachmentcount", 1}}
+		decrefFilesystemOp.Update = bson.D{
+			{"$inc", bson.D{{"attachmentcount", -1}}},
+			{"$set", bson.D{{"life", Dead}}},
+		}
+	} else {
+		// Either the filesystem is Alive, or it is Dying with
+		// other attachments; allow for concurrent attachment
+		// removals, but make sure we don't miss removing the
+		// last one.
+		decrefFilesystemOp.Assert = bson.D{{"$or", []bson.D{
+			{{"life", Alive}},
+			{{"attachmentcount", bson.D{{"$gt", 1}}}},
+		}}}
+		decrefFilesystemOp.Update = bson.D{{"$inc", bson.D{{"attachmentcount", -1}}}}
+	}
+	return []txn.Op{{
+		C:      filesystemAttachmentsC,
+		Id:     filesystemAttachmentId(m.Id(), f.doc.FilesystemId),
+		Assert: bson.D{{"life", Dying}},
+		Remove: true,
+	}, decrefFilesystemOp}
+}
+
+// DestroyFilesystem ensures that the filesystem and all of its
+// attachments will be removed at some point. The filesystem's
+// attachments are marked Dying, for the storage provisioner to detach;
+// once they have all been removed, the filesystem becomes Dead, and
+// the storage provisioner will destroy it and remove it from state.
+//
+// A filesystem that is assigned to a storage instance cannot be
+// destroyed directly while the storage instance exists.
+func (st *State) DestroyFilesystem(tag names.FilesystemTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "destroying filesystem %s", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		f, err := st.filesystemByTag(tag)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if f.doc.Life != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if f.doc.StorageId != "" {
+			storageTag := names.NewStorageTag(f.doc.StorageId)
+			if _, err := st.storageInstance(storageTag); err == nil {
+				return nil, errors.Errorf("filesystem is assigned to %s", names.ReadableString(storageTag))
+			} else if !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+		}
+		return st.destroyFilesystemOps(f)
+	}
+	return st.run(buildTxn)
+}
+
+func (st *State) destroyFilesystemOps(f *filesystem) ([]txn.Op, error) {
+	if f.doc.AttachmentCount == 0 {
+		// There are no attachments, so the filesystem
+		// can be destroyed by the storage provisioner
+		// immediately.
+		return []txn.Op{{
+			C:      filesystemsC,
+			Id:     f.doc.FilesystemId,
+			Assert: append(bson.D{{"life", Alive}}, filesystemHasNoAttachments...),
+			Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
+		}}, nil
+	}
+	attachments, err := st.FilesystemAttachments(f.FilesystemTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      filesystemsC,
+		Id:     f.doc.FilesystemId,
+		Assert: bson.D{{"life", Alive}, {"attachmentcount", bson.D{{"$gt", 0}}}},
+		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+	}}
+	for _, a := range attachments {
+		if a.Life() == Alive {
+			ops = append(ops, detachFilesystemOps(a.Machine(), f.FilesystemTag())...)
+		}
+	}
+	return ops, nil
+}
+
+var filesystemHasNoAttachments = bson.D{{
+	"$or", []bson.D{
+		{{"attachmentcount", 0}},
+		{{"attachmentcount", bson.D{{"$exists", false}}}},
+	},
+}}
+
+// RemoveFilesystem removes the filesystem from state. RemoveFilesystem
+// will fail if the filesystem is not Dead, which implies that it has
+// no attachments. If the filesystem is backed by a volume managed by
+// Juju, the volume is destroyed too.
+func (st *State) RemoveFilesystem(tag names.FilesystemTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "removing filesystem %s", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		f, err := st.filesystemByTag(tag)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if f.doc.Life != Dead {
+			return nil, errors.New("filesystem is not dead")
+		}
+		ops := []txn.Op{{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: isDeadDoc,
+			Remove: true,
+		}}
+		if f.doc.VolumeId != "" {
+			v, err := st.volumeByTag(names.NewVolumeTag(f.doc.VolumeId))
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+			if err == nil && v.doc.Life == Alive {
+				volumeOps, err := st.destroyVolumeOps(v)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				ops = append(ops, volumeOps...)
+			}
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
@@ -426,3 +426,85 @@ func (s *FilesystemStateSuite) assertFilesystemAttachmentInfo(c *gc.C, m names.M
 	_, ok := filesystemAttachment.Params()
 	c.Assert(ok, jc.IsFalse)
 }
+
+func (s *FilesystemStateSuite) addMachineWithFilesystem(c *gc.C, pool string) (names.MachineTag, names.FilesystemTag) {
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Filesystems: []state.MachineFilesystemParams{{
+			Filesystem: state.FilesystemParams{Pool: pool, Size: 1024},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := s.State.MachineFilesystemAttachments(machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	return machine.MachineTag(), attachments[0].Filesystem()
+}
+
+func (s *FilesystemStateSuite) assertFilesystemLife(c *gc.C, tag names.FilesystemTag, life state.Life) {
+	filesystem, err := s.State.Filesystem(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystem.Life(), gc.Equals, life)
+}
+
+func (s *FilesystemStateSuite) TestDestroyFilesystem(c *gc.C) {
+	machineTag, filesystemTag := s.addMachineWithFilesystem(c, "environscoped")
+
+	err := s.State.DestroyFilesystem(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemLife(c, filesystemTag, state.Dying)
+	attachment, err := s.State.FilesystemAttachment(machineTag, filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachment.Life(), gc.Equals, state.Dying)
+
+	err = s.State.RemoveFilesystem(filesystemTag)
+	c.Assert(err, gc.ErrorMatches, `removing filesystem 0: filesystem is not dead`)
+
+	err = s.State.RemoveFilesystemAttachment(machineTag, filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemLife(c, filesystemTag, state.Dead)
+	attachments, err := s.State.FilesystemAttachments(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+
+	err = s.State.RemoveFilesystem(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Filesystem(filesystemTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *FilesystemStateSuite) TestDetachFilesystem(c *gc.C) {
+	machineTag, filesystemTag := s.addMachineWithFilesystem(c, "environscoped")
+
+	err := s.State.RemoveFilesystemAttachment(machineTag, filesystemTag)
+	c.Assert(err, gc.ErrorMatches, `removing attachment of filesystem 0 from machine 0: filesystem attachment is not dying`)
+
+	err = s.State.DetachFilesystem(machineTag, filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveFilesystemAttachment(machineTag, filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemLife(c, filesystemTag, state.Alive)
+}
+
+func (s *FilesystemStateSuite) TestRemoveFilesystemDestroysBackingVolume(c *gc.C) {
+	machineTag, filesystemTag := s.addMachineWithFilesystem(c, "environscoped-block")
+	filesystem, err := s.State.Filesystem(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag, err := filesystem.Volume()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.DestroyFilesystem(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveFilesystemAttachment(machineTag, filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveFilesystem(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	volume, err := s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.Life(), gc.Equals, state.Dying)
+	volumeAttachment, err := s.State.VolumeAttachment(machineTag, volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachment.Life(), gc.Equals, state.Dying)
+}
@@ -1,3 +1,6 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
 package state
 
 import (
@@ -6,8 +9,12 @@ import (
 	"gopkg.in/mgo.v2/txn"
 )
 
+// settingsKey is the format of the id of the settings document holding
+// the leadership settings of a service.
 const settingsKey = "s#%s#leader"
 
+// addLeadershipSettingsOp returns a txn.Op that creates the empty
+// leadership settings document for the given service.
 func addLeadershipSettingsOp(serviceId string) txn.Op {
 	return txn.Op{
 		C:      settingsC,
@@ -17,6 +24,8 @@ func addLeadershipSettingsOp(serviceId string) txn.Op {
 	}
 }
 
+// removeLeadershipSettingsOp returns a txn.Op that removes the
+// leadership settings document for the given service.
 func removeLeadershipSettingsOp(serviceId string) txn.Op {
 	return txn.Op{
 		C:      settingsC,
@@ -25,6 +34,8 @@ func removeLeadershipSettingsOp(serviceId string) txn.Op {
 	}
 }
 
+// LeadershipSettingsDocId returns the id of the settings document
+// holding the leadership settings of the given service.
 func LeadershipSettingsDocId(serviceId string) string {
 	return fmt.Sprintf(settingsKey, serviceId)
 }
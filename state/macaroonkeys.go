@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+)
+
+// macaroonKeyLifetime is how long a macaroon root key is kept once
+// stored. It is longer than the lifetime of the login macaroons made
+// with the keys, so a key is only removed once no macaroon made with
+// it can still be valid.
+const macaroonKeyLifetime = 48 * time.Hour
+
+// macaroonKeyDoc holds the root key of a macaroon, as stored by the
+// API servers' macaroon bakery.
+type macaroonKeyDoc struct {
+	Location string    `bson:"_id"`
+	Item     string    `bson:"item"`
+	Created  time.Time `bson:"created"`
+}
+
+// Root keys are written directly rather than through transactions: each
+// is written once, when its macaroon is minted, and never asserted on.
+// A key is minted for every login attempt without valid macaroons, so
+// mongo removes keys older than macaroonKeyLifetime through a TTL index
+// on their creation time, lest unauthenticated clients grow the
+// collection without bound.
+
+// ensureMacaroonKeyExpiry creates the TTL index that removes expired
+// macaroon root keys.
+func ensureMacaroonKeyExpiry(db *mgo.Database) error {
+	err := db.C(macaroonKeysC).EnsureIndex(mgo.Index{
+		Key:         []string{"created"},
+		ExpireAfter: macaroonKeyLifetime,
+	})
+	return errors.Annotate(err, "cannot create macaroon key expiry index")
+}
+
+// PutMacaroonKey stores the given macaroon root key item at the given
+// location, replacing any item already there.
+func (st *State) PutMacaroonKey(location, item string) error {
+	coll, closer := st.getRawCollection(macaroonKeysC)
+	defer closer()
+	_, err := coll.UpsertId(location, &macaroonKeyDoc{
+		Location: location,
+		Item:     item,
+		Created:  time.Now(),
+	})
+	return errors.Annotatef(err, "cannot store macaroon key %q", location)
+}
+
+// MacaroonKey returns the macaroon root key item stored at the given
+// location. It returns an error satisfying errors.IsNotFound if there
+// is none.
+func (st *State) MacaroonKey(location string) (string, error) {
+	coll, closer := st.getRawCollection(macaroonKeysC)
+	defer closer()
+	var doc macaroonKeyDoc
+	err := coll.FindId(location).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", errors.NotFoundf("macaroon key %q", location)
+	} else if err != nil {
+		return "", errors.Annotatef(err, "cannot get macaroon key %q", location)
+	}
+	return doc.Item, nil
+}
+
+// RemoveMacaroonKey removes the macaroon root key item stored at the
+// given location, if any.
+func (st *State) RemoveMacaroonKey(location string) error {
+	coll, closer := st.getRawCollection(macaroonKeysC)
+	defer closer()
+	err := coll.RemoveId(location)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return errors.Annotatef(err, "cannot remove macaroon key %q", location)
+}
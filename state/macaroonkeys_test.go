@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type macaroonKeysSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&macaroonKeysSuite{})
+
+func (s *macaroonKeysSuite) TestPutGetRemove(c *gc.C) {
+	_, err := s.State.MacaroonKey("loc")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.PutMacaroonKey("loc", "item")
+	c.Assert(err, jc.ErrorIsNil)
+	item, err := s.State.MacaroonKey("loc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(item, gc.Equals, "item")
+
+	err = s.State.PutMacaroonKey("loc", "other")
+	c.Assert(err, jc.ErrorIsNil)
+	item, err = s.State.MacaroonKey("loc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(item, gc.Equals, "other")
+
+	err = s.State.RemoveMacaroonKey("loc")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MacaroonKey("loc")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RemoveMacaroonKey("loc")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *macaroonKeysSuite) TestKeysExpire(c *gc.C) {
+	before := time.Now()
+	err := s.State.PutMacaroonKey("loc", "item")
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll, closer := state.GetRawCollection(s.State, "macaroonkeys")
+	defer closer()
+	var doc struct {
+		Created time.Time `bson:"created"`
+	}
+	err = coll.FindId("loc").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Created.Before(before.Add(-time.Second)), jc.IsFalse)
+
+	// Mongo removes keys once they are older than their lifetime.
+	indexes, err := coll.Indexes()
+	c.Assert(err, jc.ErrorIsNil)
+	var found bool
+	for _, index := range indexes {
+		if len(index.Key) == 1 && index.Key[0] == "created" {
+			c.Assert(index.ExpireAfter, gc.Equals, state.MacaroonKeyLifetime)
+			found = true
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
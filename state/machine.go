@@ -108,7 +108,11 @@ type machineDoc struct {
 	NoVote        bool
 	HasVote       bool
 	PasswordHash  string
-	Clean         bool
+	// InitialPassword records that PasswordHash holds the one-time
+	// password the machine was provisioned with, which its agent
+	// must exchange for a permanent password when it first logs in.
+	InitialPassword bool `bson:"initialpassword,omitempty"`
+	Clean           bool
 	// We store 2 different sets of addresses for the machine, obtained
 	// from different sources.
 	// Addresses is the set of addresses obtained by asking the provider.
@@ -122,6 +126,13 @@ type machineDoc struct {
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
+	// Labels holds the user-defined labels of the machine, which may be
+	// used to choose machines for units.
+	Labels []string `bson:"labels,omitempty"`
+
+	// SSHHostKeys holds the public SSH host keys reported by the
+	// machine agent, in authorized_keys format.
+	SSHHostKeys []string `bson:"sshhostkeys,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -347,7 +358,8 @@ func (m *Machine) SetMongoPassword(password string) error {
 	return mongo.SetAdminMongoPassword(m.st.db.Session, m.Tag().String(), password)
 }
 
-// SetPassword sets the password for the machine's agent.
+// SetPassword sets the password for the machine's agent. Any one-time
+// initial password is replaced.
 func (m *Machine) SetPassword(password string) error {
 	if len(password) < utils.MinAgentPasswordLength {
 		return fmt.Errorf("password is only %d bytes long, and is not a valid Agent password", len(password))
@@ -355,15 +367,40 @@ func (m *Machine) SetPassword(password string) error {
 	return m.setPasswordHash(utils.AgentPasswordHash(password))
 }
 
+// SetInitialPassword sets a one-time password for the machine's agent,
+// to be delivered to the machine when it is provisioned. An agent that
+// logs in with it may do nothing but exchange it for a permanent
+// password with SetPassword.
+func (m *Machine) SetInitialPassword(password string) error {
+	if len(password) < utils.MinAgentPasswordLength {
+		return fmt.Errorf("password is only %d bytes long, and is not a valid Agent password", len(password))
+	}
+	return m.setPasswordHashInitial(utils.AgentPasswordHash(password), true)
+}
+
+// HasInitialPassword returns whether the machine's agent password is
+// a one-time password set by SetInitialPassword that has not yet been
+// exchanged for a permanent one.
+func (m *Machine) HasInitialPassword() bool {
+	return m.doc.InitialPassword
+}
+
 // setPasswordHash sets the underlying password hash in the database directly
 // to the value supplied. This is split out from SetPassword to allow direct
 // manipulation in tests (to check for backwards compatibility).
 func (m *Machine) setPasswordHash(passwordHash string) error {
+	return m.setPasswordHashInitial(passwordHash, false)
+}
+
+func (m *Machine) setPasswordHashInitial(passwordHash string, initial bool) error {
 	ops := []txn.Op{{
 		C:      machinesC,
 		Id:     m.doc.DocID,
 		Assert: notDeadDoc,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+		Update: bson.D{{"$set", bson.D{
+			{"passwordhash", passwordHash},
+			{"initialpassword", initial},
+		}}},
 	}}
 	// A "raw" transaction is used here because this code has to work
 	// before the machine env UUID DB migration has run. In this case
@@ -373,6 +410,7 @@ func (m *Machine) setPasswordHash(passwordHash string) error {
 		return fmt.Errorf("cannot set password of machine %v: %v", m, onAbort(err, ErrDead))
 	}
 	m.doc.PasswordHash = passwordHash
+	m.doc.InitialPassword = initial
 	return nil
 }
 
@@ -588,7 +626,18 @@ func (original *Machine) advanceLifecycle(life Life) (err error) {
 				UnitNames: m.doc.Principals,
 			}
 		}
-		return []txn.Op{op}, nil
+		ops := []txn.Op{op}
+		if life == Dead {
+			// Any addresses allocated to the machine are released,
+			// any storage attached to it detached, and any containers
+			// added to it meanwhile removed, once it is dead.
+			ops = append(ops,
+				m.st.newCleanupOp(cleanupAddressesForDeadMachine, m.doc.Id),
+				m.st.newCleanupOp(cleanupAttachmentsForDeadMachine, m.doc.Id),
+				m.st.newCleanupOp(cleanupContainersForDeadMachine, m.doc.Id),
+			)
+		}
+		return ops, nil
 	}
 	if err = m.st.run(buildTxn); err == jujutxn.ErrExcessiveContention {
 		err = errors.Annotatef(err, "machine %s cannot advance lifecycle", m)
@@ -666,6 +715,7 @@ func (m *Machine) Remove() (err error) {
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
 		removeMachineBlockDevicesOp(m.Id()),
+		removeWorkerHealthOp(m.st, m.Tag()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
 	if err != nil {
@@ -1041,6 +1091,7 @@ func (m *Machine) setAddresses(addresses []network.Address, field *[]address, fi
 	}
 	// Update addresses now.
 	var changed bool
+	var oldAddresses []network.Address
 	envConfig, err := m.st.EnvironConfig()
 	if err != nil {
 		return err
@@ -1058,6 +1109,7 @@ func (m *Machine) setAddresses(addresses []network.Address, field *[]address, fi
 		if m.doc.Life == Dead {
 			return nil, ErrDead
 		}
+		oldAddresses = m.Addresses()
 		op := txn.Op{
 			C:      machinesC,
 			Id:     m.doc.DocID,
@@ -1080,9 +1132,84 @@ func (m *Machine) setAddresses(addresses []network.Address, field *[]address, fi
 		return nil
 	}
 	*field = stateAddresses
+	if err := m.updateRelationAddresses(oldAddresses); err != nil {
+		// The addresses have been set; failing to tell related
+		// units about them must not be reported as failing that.
+		logger.Errorf("cannot update relation addresses of units on machine %v: %v", m, err)
+	}
 	return nil
 }
 
+// updateRelationAddresses updates the address settings of each unit
+// on the machine in every relation it is in scope of, so that the
+// units' counterparts see any new addresses in relation-changed hooks.
+// Charms may set private-address and public-address themselves, for
+// example to a virtual IP, so a setting is only updated if it is
+// missing or still holds the address the unit had before the change.
+func (m *Machine) updateRelationAddresses(oldAddresses []network.Address) error {
+	oldSettings := relationAddressSettings(oldAddresses)
+	newSettings := relationAddressSettings(m.Addresses())
+	units, err := m.Units()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unit := range units {
+		relations, err := unit.RelationsInScope()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, relation := range relations {
+			ru, err := relation.Unit(unit)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			settings, err := ru.Settings()
+			if errors.IsNotFound(err) {
+				// The unit left scope since we looked.
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			changed := false
+			for key, newValue := range newSettings {
+				if newValue == "" {
+					continue
+				}
+				value, ok := settings.Get(key)
+				if ok && value != oldSettings[key] {
+					// The charm set its own address.
+					continue
+				}
+				if value != newValue {
+					settings.Set(key, newValue)
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+			if _, err := settings.Write(); err != nil {
+				return errors.Annotatef(err, "cannot update settings of unit %q in relation %q", unit, relation)
+			}
+		}
+	}
+	return nil
+}
+
+// relationAddressSettings returns the private-address and public-address
+// relation settings that Juju derives from the given machine addresses.
+func relationAddressSettings(addresses []network.Address) map[string]string {
+	settings := map[string]string{
+		"private-address": "",
+		"public-address":  "",
+	}
+	if len(addresses) > 0 {
+		settings["private-address"] = network.SelectInternalAddress(addresses, false)
+		settings["public-address"] = network.SelectPublicAddress(addresses)
+	}
+	return settings
+}
+
 // RequestedNetworks returns the list of network names the machine
 // should be on. Unlike networks specified with constraints, these
 // networks are required to be present on the machine.
@@ -1299,6 +1426,10 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	if err != nil {
 		return err
 	}
+	oldDoc, err := getStatus(m.st, m.globalKey())
+	if err != nil && !IsStatusNotFound(err) {
+		logger.Debugf("cannot get status for %q yet", m.globalKey())
+	}
 	ops := []txn.Op{{
 		C:      machinesC,
 		Id:     m.doc.DocID,
@@ -1309,9 +1440,20 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	if err = m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
 	}
+	if oldDoc.Status != "" {
+		if err := updateStatusHistory(oldDoc, m.globalKey(), m.st); err != nil {
+			logger.Errorf("could not record status history before change to %q: %v", status, err)
+		}
+	}
 	return nil
 }
 
+// StatusHistory returns a slice of at most <size> StatusInfo items
+// representing past statuses for this machine.
+func (m *Machine) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(size, m.globalKey(), m.st)
+}
+
 // Clean returns true if the machine does not have any deployed units or containers.
 func (m *Machine) Clean() bool {
 	return m.doc.Clean
@@ -552,6 +552,34 @@ func (s *MachineSuite) TestSetPasswordPreEnvUUID(c *gc.C) {
 	c.Assert(m.PasswordValid(goodPassword), jc.IsTrue)
 }
 
+func (s *MachineSuite) TestSetInitialPassword(c *gc.C) {
+	c.Assert(s.machine.HasInitialPassword(), jc.IsFalse)
+	err := s.machine.SetInitialPassword(goodPassword)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.PasswordValid(goodPassword), jc.IsTrue)
+	c.Assert(s.machine.HasInitialPassword(), jc.IsTrue)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.PasswordValid(goodPassword), jc.IsTrue)
+	c.Assert(m.HasInitialPassword(), jc.IsTrue)
+
+	// Exchanging it for a permanent password invalidates it.
+	err = m.SetPassword(alternatePassword)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.PasswordValid(goodPassword), jc.IsFalse)
+	c.Assert(m.PasswordValid(alternatePassword), jc.IsTrue)
+	c.Assert(m.HasInitialPassword(), jc.IsFalse)
+}
+
+func (s *MachineSuite) TestSetInitialPasswordTooShort(c *gc.C) {
+	err := s.machine.SetInitialPassword("foo")
+	c.Assert(err, gc.ErrorMatches, "password is only 3 bytes long, and is not a valid Agent password")
+	c.Assert(s.machine.HasInitialPassword(), jc.IsFalse)
+}
+
 func (s *MachineSuite) TestSetAgentCompatPassword(c *gc.C) {
 	e, err := s.State.Machine(s.machine.Id())
 	c.Assert(err, jc.ErrorIsNil)
@@ -1600,6 +1628,24 @@ func (s *MachineSuite) TestSetStatusPending(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set status "pending"`)
 }
 
+func (s *MachineSuite) TestSetStatusHistory(c *gc.C) {
+	err := s.machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetStatus(state.StatusError, "it broke", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, state.StatusStarted)
+	c.Assert(history[1].Status, gc.Equals, state.StatusPending)
+
+	history, err = s.machine.StatusHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, state.StatusStarted)
+}
+
 func (s *MachineSuite) TestGetSetStatusWhileNotAlive(c *gc.C) {
 	// When Dying set/get should work.
 	err := s.machine.Destroy()
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+var validMachineLabel = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
+// IsValidMachineLabel returns whether label may be used as a
+// user-defined machine label.
+func IsValidMachineLabel(label string) bool {
+	return validMachineLabel.MatchString(label)
+}
+
+// Labels returns the user-defined labels of the machine, sorted.
+func (m *Machine) Labels() []string {
+	labels := make([]string, len(m.doc.Labels))
+	copy(labels, m.doc.Labels)
+	return labels
+}
+
+// SetLabels replaces the user-defined labels of the machine. Labels
+// may be used in placement directives, such as "label=gpu", to choose
+// the machines that units are assigned to.
+func (m *Machine) SetLabels(labels []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set labels of machine %s", m)
+	labels, err = normaliseMachineLabels(labels)
+	if err != nil {
+		return err
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"labels", labels}}}},
+	}}
+	if err := m.st.runTransaction(ops); err != nil {
+		return onAbort(err, ErrDead)
+	}
+	m.doc.Labels = labels
+	return nil
+}
+
+// normaliseMachineLabels checks the given labels and returns them
+// sorted, without duplicates.
+func normaliseMachineLabels(labels []string) ([]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	unique := set.NewStrings()
+	for _, label := range labels {
+		if !IsValidMachineLabel(label) {
+			return nil, errors.NotValidf("machine label %q", label)
+		}
+		unique.Add(label)
+	}
+	return unique.SortedValues(), nil
+}
+
+// MachinesWithLabel returns the alive machines that carry the given
+// user-defined label, ordered by id.
+func (st *State) MachinesWithLabel(label string) ([]*Machine, error) {
+	machinesCollection, closer := st.getCollection(machinesC)
+	defer closer()
+
+	mdocs := machineDocSlice{}
+	err := machinesCollection.Find(bson.D{
+		{"labels", label},
+		{"life", Alive},
+	}).All(&mdocs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get machines with label %q", label)
+	}
+	sort.Sort(mdocs)
+	machines := make([]*Machine, len(mdocs))
+	for i := range mdocs {
+		machines[i] = newMachine(st, &mdocs[i])
+	}
+	return machines, nil
+}
+
+// AssignToMachineWithLabel assigns the unit to an existing machine
+// that carries the given user-defined label, and returns that machine.
+// Machines that do not yet host any units are preferred.
+func (u *Unit) AssignToMachineWithLabel(label string) (*Machine, error) {
+	machines, err := u.st.MachinesWithLabel(label)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(machines) == 0 {
+		return nil, errors.NotFoundf("machine with label %q", label)
+	}
+	var empty, occupied []*Machine
+	for _, m := range machines {
+		if len(m.doc.Principals) == 0 {
+			empty = append(empty, m)
+		} else {
+			occupied = append(occupied, m)
+		}
+	}
+	var lastErr error
+	for _, m := range append(empty, occupied...) {
+		lastErr = u.AssignToMachine(m)
+		if lastErr == nil {
+			return m, nil
+		}
+		logger.Debugf("cannot assign unit %q to machine %s: %v", u, m, lastErr)
+	}
+	return nil, errors.Annotatef(lastErr, "no machine with label %q can host unit %q", label, u)
+}
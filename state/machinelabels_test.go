@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type MachineLabelsSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&MachineLabelsSuite{})
+
+func (s *MachineLabelsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *MachineLabelsSuite) addMachine(c *gc.C, labels ...string) *state.Machine {
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Labels: labels,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *MachineLabelsSuite) TestIsValidMachineLabel(c *gc.C) {
+	for label, valid := range map[string]bool{
+		"gpu":       true,
+		"fast-disk": true,
+		"x86":       true,
+		"":          false,
+		"GPU":       false,
+		"-gpu":      false,
+		"gpu-":      false,
+		"fast_disk": false,
+		"a=b":       false,
+	} {
+		c.Check(state.IsValidMachineLabel(label), gc.Equals, valid, gc.Commentf("label %q", label))
+	}
+}
+
+func (s *MachineLabelsSuite) TestAddMachineWithLabels(c *gc.C) {
+	m := s.addMachine(c, "ssd", "gpu", "ssd")
+	c.Assert(m.Labels(), jc.DeepEquals, []string{"gpu", "ssd"})
+
+	err := m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Labels(), jc.DeepEquals, []string{"gpu", "ssd"})
+}
+
+func (s *MachineLabelsSuite) TestAddMachineInvalidLabel(c *gc.C) {
+	_, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Labels: []string{"Not Valid"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add a new machine: machine label "Not Valid" not valid`)
+}
+
+func (s *MachineLabelsSuite) TestSetLabels(c *gc.C) {
+	m := s.addMachine(c)
+	c.Assert(m.Labels(), gc.HasLen, 0)
+
+	err := m.SetLabels([]string{"gpu"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Labels(), jc.DeepEquals, []string{"gpu"})
+
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Labels(), jc.DeepEquals, []string{"gpu"})
+
+	err = m.SetLabels(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Labels(), gc.HasLen, 0)
+}
+
+func (s *MachineLabelsSuite) TestSetLabelsInvalid(c *gc.C) {
+	m := s.addMachine(c)
+	err := m.SetLabels([]string{"gpu", "a b"})
+	c.Assert(err, gc.ErrorMatches, `cannot set labels of machine 0: machine label "a b" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *MachineLabelsSuite) TestSetLabelsDead(c *gc.C) {
+	m := s.addMachine(c)
+	err := m.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetLabels([]string{"gpu"})
+	c.Assert(err, gc.ErrorMatches, `cannot set labels of machine 0: not found or dead`)
+}
+
+func (s *MachineLabelsSuite) TestMachinesWithLabel(c *gc.C) {
+	s.addMachine(c, "gpu")
+	s.addMachine(c)
+	s.addMachine(c, "gpu", "ssd")
+
+	machines, err := s.State.MachinesWithLabel("gpu")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineIds(machines), jc.DeepEquals, []string{"0", "2"})
+
+	machines, err = s.State.MachinesWithLabel("ssd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineIds(machines), jc.DeepEquals, []string{"2"})
+
+	machines, err = s.State.MachinesWithLabel("none")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *MachineLabelsSuite) TestAssignToMachineWithLabel(c *gc.C) {
+	m0 := s.addMachine(c, "gpu")
+	s.addMachine(c)
+	m2 := s.addMachine(c, "gpu")
+
+	unit0, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	assigned, err := unit0.AssignToMachineWithLabel("gpu")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(assigned.Id(), gc.Equals, m0.Id())
+
+	// Machines without units are preferred.
+	unit1, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	assigned, err = unit1.AssignToMachineWithLabel("gpu")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(assigned.Id(), gc.Equals, m2.Id())
+}
+
+func (s *MachineLabelsSuite) TestAssignToMachineWithLabelNotFound(c *gc.C) {
+	s.addMachine(c, "ssd")
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.AssignToMachineWithLabel("gpu")
+	c.Assert(err, gc.ErrorMatches, `machine with label "gpu" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func machineIds(machines []*state.Machine) []string {
+	ids := make([]string, len(machines))
+	for i, m := range machines {
+		ids[i] = m.Id()
+	}
+	return ids
+}
@@ -792,6 +792,61 @@ func backingEntityIdForGlobalKey(key string) (multiwatcher.EntityId, bool) {
 	}
 }
 
+type backingIPAddress ipaddressDoc
+
+func (a *backingIPAddress) updated(st *State, store *multiwatcherStore, id interface{}) error {
+	info := &multiwatcher.IPAddressInfo{
+		Value:       a.Value,
+		Life:        multiwatcher.Life(a.Life.String()),
+		SubnetId:    a.SubnetId,
+		MachineId:   a.MachineId,
+		InterfaceId: a.InterfaceId,
+		Type:        a.Type,
+		Scope:       a.Scope,
+		State:       string(a.State),
+	}
+	store.Update(info)
+	return nil
+}
+
+func (a *backingIPAddress) removed(st *State, store *multiwatcherStore, id interface{}) {
+	store.Remove(multiwatcher.EntityId{
+		Kind: "ipaddress",
+		Id:   st.localID(id.(string)),
+	})
+}
+
+func (a *backingIPAddress) mongoId() interface{} {
+	return a.DocID
+}
+
+type backingSubnet subnetDoc
+
+func (s *backingSubnet) updated(st *State, store *multiwatcherStore, id interface{}) error {
+	info := &multiwatcher.SubnetInfo{
+		CIDR:              s.CIDR,
+		Life:              multiwatcher.Life(s.Life.String()),
+		ProviderId:        s.ProviderId,
+		VLANTag:           s.VLANTag,
+		AvailabilityZone:  s.AvailabilityZone,
+		AllocatableIPLow:  s.AllocatableIPLow,
+		AllocatableIPHigh: s.AllocatableIPHigh,
+	}
+	store.Update(info)
+	return nil
+}
+
+func (s *backingSubnet) removed(st *State, store *multiwatcherStore, id interface{}) {
+	store.Remove(multiwatcher.EntityId{
+		Kind: "subnet",
+		Id:   st.localID(id.(string)),
+	})
+}
+
+func (s *backingSubnet) mongoId() interface{} {
+	return s.DocID
+}
+
 // backingEntityDoc is implemented by the documents in
 // collections that the allWatcherStateBacking watches.
 type backingEntityDoc interface {
@@ -852,6 +907,12 @@ func newAllWatcherStateBacking(st *State) Backing {
 	}, {
 		Collection: st.db.C(blocksC),
 		infoType:   reflect.TypeOf(backingBlock{}),
+	}, {
+		Collection: st.db.C(ipaddressesC),
+		infoType:   reflect.TypeOf(backingIPAddress{}),
+	}, {
+		Collection: st.db.C(subnetsC),
+		infoType:   reflect.TypeOf(backingSubnet{}),
 	}, {
 		Collection: st.db.C(statusesC),
 		infoType:   reflect.TypeOf(backingStatus{}),
@@ -596,6 +596,95 @@ func (s *storeManagerStateSuite) TestChangeBlocks(c *gc.C) {
 	s.performChangeTestCases(c, changeTestFuncs)
 }
 
+// TestChangeIPAddresses tests the changing of IP addresses.
+func (s *storeManagerStateSuite) TestChangeIPAddresses(c *gc.C) {
+	changeTestFuncs := []changeTestFunc{
+		func(c *gc.C, st *State) changeTestCase {
+			return changeTestCase{
+				about: "IP address is removed if it's not in backing",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.IPAddressInfo{
+					Value: "10.0.0.5",
+				}},
+				change: watcher.Change{
+					C:  ipaddressesC,
+					Id: st.docID("10.0.0.5"),
+				}}
+		},
+		func(c *gc.C, st *State) changeTestCase {
+			addr := network.NewScopedAddress("10.0.0.5", network.ScopeCloudLocal)
+			ipAddr, err := st.AddIPAddress(addr, "foo")
+			c.Assert(err, jc.ErrorIsNil)
+			err = ipAddr.AllocateTo("0", "wobble")
+			c.Assert(err, jc.ErrorIsNil)
+
+			return changeTestCase{
+				about: "IP address is added if it's in backing but not in Store",
+				change: watcher.Change{
+					C:  ipaddressesC,
+					Id: st.docID("10.0.0.5"),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					&multiwatcher.IPAddressInfo{
+						Value:       "10.0.0.5",
+						Life:        multiwatcher.Life("alive"),
+						SubnetId:    "foo",
+						MachineId:   "0",
+						InterfaceId: "wobble",
+						Type:        string(addr.Type),
+						Scope:       string(addr.Scope),
+						State:       string(AddressStateAllocated),
+					}}}
+		},
+	}
+	s.performChangeTestCases(c, changeTestFuncs)
+}
+
+// TestChangeSubnets tests the changing of subnets.
+func (s *storeManagerStateSuite) TestChangeSubnets(c *gc.C) {
+	changeTestFuncs := []changeTestFunc{
+		func(c *gc.C, st *State) changeTestCase {
+			return changeTestCase{
+				about: "subnet is removed if it's not in backing",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.SubnetInfo{
+					CIDR: "10.0.0.0/24",
+				}},
+				change: watcher.Change{
+					C:  subnetsC,
+					Id: st.docID("10.0.0.0/24"),
+				}}
+		},
+		func(c *gc.C, st *State) changeTestCase {
+			_, err := st.AddSubnet(SubnetInfo{
+				CIDR:              "10.0.0.0/24",
+				ProviderId:        "foo",
+				VLANTag:           79,
+				AllocatableIPLow:  "10.0.0.10",
+				AllocatableIPHigh: "10.0.0.100",
+				AvailabilityZone:  "zone1",
+			})
+			c.Assert(err, jc.ErrorIsNil)
+
+			return changeTestCase{
+				about: "subnet is added if it's in backing but not in Store",
+				change: watcher.Change{
+					C:  subnetsC,
+					Id: st.docID("10.0.0.0/24"),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					&multiwatcher.SubnetInfo{
+						CIDR:              "10.0.0.0/24",
+						Life:              multiwatcher.Life("alive"),
+						ProviderId:        "foo",
+						VLANTag:           79,
+						AvailabilityZone:  "zone1",
+						AllocatableIPLow:  "10.0.0.10",
+						AllocatableIPHigh: "10.0.0.100",
+					}}}
+		},
+	}
+	s.performChangeTestCases(c, changeTestFuncs)
+}
+
 // TestChangeMachines tests the changing of machines.
 func (s *storeManagerStateSuite) TestChangeMachines(c *gc.C) {
 	changeTestFuncs := []changeTestFunc{
@@ -2135,6 +2224,31 @@ func (s *storeManagerStateSuite) TestStateWatcherTwoEnvironments(c *gc.C) {
 				err = st.SwitchBlockOn(DestroyBlock, "test block")
 				c.Assert(err, jc.ErrorIsNil)
 			},
+		}, {
+			about: "actions",
+			setUpState: func(st *State) {
+				svc := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"), s.owner)
+				_, err := svc.AddUnit()
+				c.Assert(err, jc.ErrorIsNil)
+			},
+			triggerEvent: func(st *State) {
+				u, err := st.Unit("wordpress/0")
+				c.Assert(err, jc.ErrorIsNil)
+				_, err = st.EnqueueAction(u.Tag(), "vacuumdb", map[string]interface{}{})
+				c.Assert(err, jc.ErrorIsNil)
+			},
+		}, {
+			about: "IP addresses",
+			triggerEvent: func(st *State) {
+				_, err := st.AddIPAddress(network.NewAddress("10.0.0.5"), "")
+				c.Assert(err, jc.ErrorIsNil)
+			},
+		}, {
+			about: "subnets",
+			triggerEvent: func(st *State) {
+				_, err := st.AddSubnet(SubnetInfo{CIDR: "10.0.0.0/24"})
+				c.Assert(err, jc.ErrorIsNil)
+			},
 		},
 	} {
 		c.Logf("Test %d: %s", i, test.about)
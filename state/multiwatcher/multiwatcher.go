@@ -99,6 +99,12 @@ func (d *Delta) UnmarshalJSON(data []byte) error {
 		d.Entity = new(AnnotationInfo)
 	case "block":
 		d.Entity = new(BlockInfo)
+	case "action":
+		d.Entity = new(ActionInfo)
+	case "ipaddress":
+		d.Entity = new(IPAddressInfo)
+	case "subnet":
+		d.Entity = new(SubnetInfo)
 	default:
 		return fmt.Errorf("Unexpected entity name %q", entityKind)
 	}
@@ -245,6 +251,47 @@ func (i *AnnotationInfo) EntityId() EntityId {
 	}
 }
 
+// IPAddressInfo holds the information about an IP address
+// that is watched by StateMultiwatcher.
+type IPAddressInfo struct {
+	Value       string
+	Life        Life
+	SubnetId    string
+	MachineId   string
+	InterfaceId string
+	Type        string
+	Scope       string
+	State       string
+}
+
+// EntityId returns the IP address's value.
+func (i *IPAddressInfo) EntityId() EntityId {
+	return EntityId{
+		Kind: "ipaddress",
+		Id:   i.Value,
+	}
+}
+
+// SubnetInfo holds the information about a subnet
+// that is watched by StateMultiwatcher.
+type SubnetInfo struct {
+	CIDR              string
+	Life              Life
+	ProviderId        string
+	VLANTag           int
+	AvailabilityZone  string
+	AllocatableIPLow  string
+	AllocatableIPHigh string
+}
+
+// EntityId returns the subnet's CIDR.
+func (i *SubnetInfo) EntityId() EntityId {
+	return EntityId{
+		Kind: "subnet",
+		Id:   i.CIDR,
+	}
+}
+
 type Endpoint struct {
 	ServiceName string
 	Relation    charm.Relation
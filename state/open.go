@@ -182,6 +182,8 @@ var indexes = []struct {
 	{volumesC, []string{"env-uuid", "storageid"}, false, false},
 	{filesystemsC, []string{"env-uuid", "storageid"}, false, false},
 	{statusesHistoryC, []string{"env-uuid", "entityid"}, false, false},
+	{unitEventsC, []string{"env-uuid", "unit", "seq"}, false, false},
+	{workerHealthC, []string{"env-uuid", "agent"}, false, false},
 }
 
 // The capped collection used for transaction logs defaults to 10MB.
@@ -192,6 +194,14 @@ var (
 	txnLogSizeTests = 1000000
 )
 
+// The capped collection used for the API audit log, created when the
+// first entry is added, defaults to 50MB, and is similarly reduced to
+// 1MB in tests.
+var (
+	auditLogSize      = 50000000
+	auditLogSizeTests = 1000000
+)
+
 func maybeUnauthorized(err error, msg string) error {
 	if err == nil {
 		return nil
@@ -271,6 +281,10 @@ func newState(session *mgo.Session, mongoInfo *mongo.MongoInfo, policy Policy) (
 		}
 	}
 
+	if err := ensureMacaroonKeyExpiry(db); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	if err := InitDbLogs(session); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -296,6 +310,13 @@ func (st *State) CACert() string {
 
 func (st *State) Close() (err error) {
 	defer errors.DeferredAnnotatef(&err, "closing state failed")
+	// The presence timeout is kept up to date through the state
+	// watcher, so stop doing that before stopping the watchers.
+	if st.pconfigWatcher != nil {
+		if err := st.pconfigWatcher.Stop(); err != nil {
+			logger.Warningf("failed to stop presence timeout updates: %v", err)
+		}
+	}
 	err1 := st.watcher.Stop()
 	var err2 error
 	if st.pwatcher != nil {
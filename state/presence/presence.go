@@ -109,6 +109,10 @@ type Watcher struct {
 	// knowledge. It's maintained here so that ForceRefresh
 	// can manipulate it to force a sync sooner.
 	next <-chan time.Time
+
+	// slots holds the number of time slots before the current one
+	// in which a ping keeps a key alive.
+	slots int64
 }
 
 type event struct {
@@ -134,6 +138,7 @@ func NewWatcher(base *mgo.Collection, envTag names.EnvironTag) *Watcher {
 		beingSeq: make(map[string]int64),
 		watches:  make(map[string][]chan<- Change),
 		request:  make(chan interface{}),
+		slots:    1,
 	}
 	go func() {
 		err := w.loop()
@@ -188,6 +193,10 @@ type reqAlive struct {
 	result chan bool
 }
 
+type reqSetTimeout struct {
+	slots int64
+}
+
 func (w *Watcher) sendReq(req interface{}) {
 	select {
 	case w.request <- req:
@@ -225,6 +234,17 @@ func (w *Watcher) Sync() {
 	}
 }
 
+// SetTimeout sets how long a key may go without being pinged before
+// it is reported as dead. The timeout is rounded up to a whole number
+// of time slots, and is never shorter than the default of two slots.
+func (w *Watcher) SetTimeout(timeout time.Duration) {
+	slots := int64((timeout+time.Duration(period)*time.Second-1)/(time.Duration(period)*time.Second)) - 1
+	if slots < 1 {
+		slots = 1
+	}
+	w.sendReq(reqSetTimeout{slots})
+}
+
 // Alive returns whether the key is currently considered alive by w,
 // or an error in case the watcher is dying.
 func (w *Watcher) Alive(key string) (bool, error) {
@@ -299,6 +319,9 @@ func (w *Watcher) flush() {
 func (w *Watcher) handle(req interface{}) {
 	logger.Tracef("got request: %#v", req)
 	switch r := req.(type) {
+	case reqSetTimeout:
+		w.slots = r.slots
+		w.next = time.After(0)
 	case reqSync:
 		w.next = time.After(0)
 		if r.done != nil {
@@ -368,8 +391,9 @@ func (w *Watcher) findAllBeings() (map[int64]beingInfo, error) {
 }
 
 // sync updates the watcher knowledge from the database, and
-// queues events to observing channels. It fetches the last two time
-// slots and compares the union of both to the in-memory state.
+// queues events to observing channels. It fetches the current time
+// slot and the w.slots before it, and compares the union of them all
+// to the in-memory state.
 func (w *Watcher) sync() error {
 	var allBeings map[int64]beingInfo
 	if len(w.beingKey) == 0 {
@@ -381,13 +405,15 @@ func (w *Watcher) sync() error {
 		}
 	}
 	s := timeSlot(time.Now(), w.delta)
-	slot := docIDInt64(w.envUUID, s)
-	previousSlot := docIDInt64(w.envUUID, s-period)
+	slots := make([]pingInfo, w.slots+1)
+	for i := range slots {
+		slots[i].DocID = docIDInt64(w.envUUID, s-int64(i)*period)
+	}
 	session := w.pings.Database.Session.Copy()
 	defer session.Close()
 	pings := w.pings.With(session)
 	var ping []pingInfo
-	q := bson.D{{"$or", []pingInfo{{DocID: slot}, {DocID: previousSlot}}}}
+	q := bson.D{{"$or", slots}}
 	err := pings.Find(q).All(&ping)
 	if err != nil && err == mgo.ErrNotFound {
 		return errors.Trace(err)
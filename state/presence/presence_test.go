@@ -264,6 +264,35 @@ func (s *PresenceSuite) TestExpiry(c *gc.C) {
 	assertNoChange(c, ch)
 }
 
+func (s *PresenceSuite) TestExpiryWithTimeout(c *gc.C) {
+	w := presence.NewWatcher(s.presence, s.envTag)
+	p := presence.NewPinger(s.presence, s.envTag, "a")
+	defer w.Stop()
+	defer p.Stop()
+
+	// Four 30 second slots, including the current one.
+	w.SetTimeout(2 * time.Minute)
+
+	ch := make(chan presence.Change)
+	w.Watch("a", ch)
+	assertChange(c, ch, presence.Change{"a", false})
+
+	c.Assert(p.Start(), gc.IsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", true})
+
+	// Still alive in the three slots after the ping.
+	for slot := 1; slot <= 3; slot++ {
+		presence.FakeTimeSlot(slot)
+		w.StartSync()
+		assertNoChange(c, ch)
+	}
+
+	presence.FakeTimeSlot(4)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", false})
+}
+
 func (s *PresenceSuite) TestWatchPeriod(c *gc.C) {
 	presence.FakePeriod(1)
 	presence.RealTimeSlot()
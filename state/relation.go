@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -272,6 +273,108 @@ func (r *Relation) RelatedEndpoints(serviceName string) ([]Endpoint, error) {
 	return eps, nil
 }
 
+// serviceSettingsKey returns the key under which the settings of the
+// named service within the relation are stored. It shares the relation's
+// settings prefix, so the settings are removed along with the relation.
+func (r *Relation) serviceSettingsKey(serviceName string) string {
+	return fmt.Sprintf("r#%d#service#%s", r.doc.Id, serviceName)
+}
+
+// ErrNotServiceLeader is returned when a unit that does not hold the
+// leadership of its service tries to change the service's settings.
+var ErrNotServiceLeader = stderrors.New("unit is not the leader of its service")
+
+// leadershipAssertOp returns an op asserting that the named unit holds
+// the leadership of the named service. Leadership is held through a
+// lease in the namespace <service>-leadership.
+func leadershipAssertOp(serviceName, unitName string) txn.Op {
+	return txn.Op{
+		C:  leaseC,
+		Id: serviceName + "-leadership",
+		Assert: bson.D{
+			{"token.id", unitName},
+			{"token.expiration", bson.D{{"$gt", time.Now()}}},
+		},
+	}
+}
+
+// ServiceSettings returns a Settings which allows access to the
+// service-level settings of the named service within the relation,
+// creating them if they do not yet exist. Changes to them must be
+// written with WriteServiceSettings, so that only the leader of the
+// service can make them.
+func (r *Relation) ServiceSettings(serviceName string) (*Settings, error) {
+	if _, err := r.Endpoint(serviceName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	key := r.serviceSettingsKey(serviceName)
+	settings, err := readSettings(r.st, key)
+	if errors.IsNotFound(err) {
+		settings, err = createSettings(r.st, key, nil)
+		if err == errSettingsExist {
+			settings, err = readSettings(r.st, key)
+		}
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read settings for service %q in relation %q", serviceName, r)
+	}
+	return settings, nil
+}
+
+// WriteServiceSettings writes the changes made to the given settings,
+// as returned by ServiceSettings, on behalf of the named unit. It
+// returns ErrNotServiceLeader if the unit does not hold the leadership
+// of the service whose settings they are.
+//
+// Units of the counterpart service see the change as a change to the
+// settings of every unit of the service in scope, so that their
+// relation-changed hooks run.
+func (r *Relation) WriteServiceSettings(unitName string, settings *Settings) error {
+	serviceName, err := names.UnitService(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if settings.key != r.serviceSettingsKey(serviceName) {
+		return errors.Errorf("settings do not belong to service %q in relation %q", serviceName, r)
+	}
+	changes, update := settings.delta()
+	if len(changes) == 0 {
+		return nil
+	}
+	ops := []txn.Op{leadershipAssertOp(serviceName, unitName), {
+		C:      settingsC,
+		Id:     r.st.docID(settings.key),
+		Assert: txn.DocExists,
+		Update: update,
+	}}
+	if err := r.st.runTransaction(ops); err == txn.ErrAborted {
+		if _, _, err := readSettingsDoc(r.st, settings.key); err == mgo.ErrNotFound {
+			return errors.NotFoundf("settings for service %q in relation %q", serviceName, r)
+		}
+		return ErrNotServiceLeader
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot write settings for service %q in relation %q", serviceName, r)
+	}
+	settings.disk = copyMap(settings.core, nil)
+	return nil
+}
+
+// ReadServiceSettings returns a map holding the service-level settings
+// of the named service within the relation. Settings that have never
+// been written are reported as empty.
+func (r *Relation) ReadServiceSettings(serviceName string) (map[string]interface{}, error) {
+	if _, err := r.Endpoint(serviceName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	settings, err := readSettings(r.st, r.serviceSettingsKey(serviceName))
+	if errors.IsNotFound(err) {
+		return map[string]interface{}{}, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot read settings for service %q in relation %q", serviceName, r)
+	}
+	return settings.Map(), nil
+}
+
 // Unit returns a RelationUnit for the supplied unit.
 func (r *Relation) Unit(u *Unit) (*RelationUnit, error) {
 	ep, err := r.Endpoint(u.doc.Service)
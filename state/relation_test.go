@@ -286,6 +286,45 @@ func (s *RelationSuite) TestDestroyPeerRelation(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *RelationSuite) TestServiceSettings(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressEP, err := wordpress.Endpoint("db")
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysqlEP, err := mysql.Endpoint("server")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(wordpressEP, mysqlEP)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Settings that were never written read as empty.
+	read, err := rel.ReadServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, gc.HasLen, 0)
+
+	settings, err := rel.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("endpoint", "10.0.0.1:3306")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	read, err = rel.ReadServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, gc.DeepEquals, map[string]interface{}{"endpoint": "10.0.0.1:3306"})
+	read, err = rel.ReadServiceSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, gc.HasLen, 0)
+
+	// A second call sees the stored settings rather than recreating them.
+	settings, err = rel.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings.Map(), gc.DeepEquals, map[string]interface{}{"endpoint": "10.0.0.1:3306"})
+
+	_, err = rel.ServiceSettings("riak")
+	c.Assert(err, gc.ErrorMatches, `service "riak" is not a member of "wordpress:db mysql:server"`)
+	_, err = rel.ReadServiceSettings("riak")
+	c.Assert(err, gc.ErrorMatches, `service "riak" is not a member of "wordpress:db mysql:server"`)
+}
+
 func assertNoRelations(c *gc.C, srv *state.Service) {
 	rels, err := srv.Relations()
 	c.Assert(err, jc.ErrorIsNil)
@@ -15,6 +15,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
 
+	"github.com/juju/juju/lease"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
@@ -1116,6 +1117,70 @@ func (s *WatchScopeSuite) TestProviderRequirerGlobal(c *gc.C) {
 	// Cleanup handled by defers as before.
 }
 
+func (s *WatchScopeSuite) TestServiceSettings(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysqlEP, err := mysql.Endpoint("server")
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressEP, err := wordpress.Endpoint("db")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(mysqlEP, wordpressEP)
+	c.Assert(err, jc.ErrorIsNil)
+	enterScope := func(svc *state.Service) *state.RelationUnit {
+		unit, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		ru, err := rel.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		return ru
+	}
+	enterScope(mysql)
+	enterScope(mysql)
+	wordpressRU := enterScope(wordpress)
+
+	w := wordpressRU.Watch()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewRelationUnitsWatcherC(c, s.State, w)
+	wc.AssertChange([]string{"mysql/0", "mysql/1"}, nil)
+	wc.AssertNoChange()
+
+	// Only the leader of the service can change its settings.
+	settings, err := rel.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("endpoint", "10.0.0.1:3306")
+	err = rel.WriteServiceSettings("mysql/0", settings)
+	c.Assert(err, gc.Equals, state.ErrNotServiceLeader)
+	wc.AssertNoChange()
+
+	err = s.State.LeasePersistor.WriteToken("mysql-leadership", lease.Token{
+		Namespace:  "mysql-leadership",
+		Id:         "mysql/0",
+		Expiration: time.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.WriteServiceSettings("mysql/1", settings)
+	c.Assert(err, gc.Equals, state.ErrNotServiceLeader)
+	err = rel.WriteServiceSettings("mysql/0", settings)
+	c.Assert(err, jc.ErrorIsNil)
+	read, err := rel.ReadServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, gc.DeepEquals, map[string]interface{}{"endpoint": "10.0.0.1:3306"})
+
+	// The change is seen as a change to every unit of the service,
+	// so that relation-changed hooks run for them.
+	wc.AssertChange([]string{"mysql/0", "mysql/1"}, nil)
+	wc.AssertNoChange()
+
+	// A service's own units do not see changes to its settings.
+	wordpressSettings, err := rel.ServiceSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	wordpressSettings.Set("ignored", "true")
+	_, err = wordpressSettings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
 func (s *WatchScopeSuite) TestProviderRequirerContainer(c *gc.C) {
 	// Create a pair of services and a relation between them.
 	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
@@ -46,6 +46,14 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	// AssignmentPolicy, if set, overrides the environment's
+	// unit-assignment-policy for units of the service.
+	AssignmentPolicy AssignmentPolicy `bson:"assignmentpolicy,omitempty"`
+
+	// CharmOrigin records where the service's charm was obtained
+	// from, so that upgrades can track the same source.
+	CharmOrigin *CharmOrigin `bson:"charmorigin,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -266,6 +274,85 @@ func (s *Service) setExposed(exposed bool) (err error) {
 	return nil
 }
 
+// CharmOrigin records the charm store that a service's charm was
+// deployed from.
+type CharmOrigin struct {
+	// StoreURL holds the URL of the charm store.
+	StoreURL string `bson:"storeurl"`
+}
+
+// Validate returns an error if the origin is not valid.
+func (o CharmOrigin) Validate() error {
+	if o.StoreURL == "" {
+		return errors.NotValidf("charm origin without store URL")
+	}
+	return nil
+}
+
+// CharmOrigin returns the charm store the service's charm was
+// deployed from, and whether it is known. It is not known
+// for local charms, nor for services deployed by older clients.
+func (s *Service) CharmOrigin() (CharmOrigin, bool) {
+	if s.doc.CharmOrigin == nil {
+		return CharmOrigin{}, false
+	}
+	return *s.doc.CharmOrigin, true
+}
+
+// SetCharmOrigin records the charm store that the service's charm
+// was deployed from. A nil origin clears any previously set origin.
+func (s *Service) SetCharmOrigin(origin *CharmOrigin) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set charm origin for service %q", s)
+	if origin != nil {
+		if err := origin.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		copied := *origin
+		origin = &copied
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"charmorigin", origin}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	s.doc.CharmOrigin = origin
+	return nil
+}
+
+// AssignmentPolicy returns the policy used to choose machines for new
+// units of the service, and whether one has been set. If it has not,
+// the environment's policy applies.
+func (s *Service) AssignmentPolicy() (AssignmentPolicy, bool) {
+	return s.doc.AssignmentPolicy, s.doc.AssignmentPolicy != ""
+}
+
+// SetAssignmentPolicy sets the policy used to choose machines for new
+// units of the service. An empty policy clears any previously set,
+// so that the environment's policy applies.
+func (s *Service) SetAssignmentPolicy(policy AssignmentPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set assignment policy for service %q", s)
+	if policy != "" {
+		if err := policy.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"assignmentpolicy", policy}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	s.doc.AssignmentPolicy = policy
+	return nil
+}
+
 // Charm returns the service's charm and whether units should upgrade to that
 // charm even if they are in an error state.
 func (s *Service) Charm() (ch *Charm, force bool, err error) {
@@ -748,6 +835,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeStatusOp(s.st, u.globalKey()),
 		removeConstraintsOp(s.st, u.globalAgentKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
+		removeWorkerHealthOp(s.st, u.Tag()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
 	ops = append(ops, portsOps...)
@@ -867,6 +955,79 @@ func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
 	return err
 }
 
+// UpdateServicesConfigSettings changes the configuration settings of
+// several services, keyed by service name, in a single transaction:
+// either all of the changes are made or none of them are. The changes
+// for each service are validated and applied as by
+// Service.UpdateConfigSettings.
+func (st *State) UpdateServicesConfigSettings(changes map[string]charm.Settings) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update service settings")
+	serviceNames := make([]string, 0, len(changes))
+	for name := range changes {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var ops []txn.Op
+		for _, name := range serviceNames {
+			serviceOps, err := st.updateServiceConfigSettingsOps(name, changes[name])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, serviceOps...)
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// updateServiceConfigSettingsOps returns the operations that apply the
+// given changes to the settings of the named service, asserting that
+// the service is alive, that its charm has not changed and that the
+// settings have not been changed since they were read.
+func (st *State) updateServiceConfigSettingsOps(serviceName string, changes charm.Settings) ([]txn.Op, error) {
+	service, err := st.Service(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if service.Life() != Alive {
+		return nil, errors.Errorf("service %q is not alive", serviceName)
+	}
+	ch, _, err := service.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changes, err = ch.Config().ValidateSettings(changes)
+	if err != nil {
+		return nil, errors.Annotatef(err, "service %q", serviceName)
+	}
+	node, err := readSettings(st, service.settingsKey())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, value := range changes {
+		if value == nil {
+			node.Delete(name)
+		} else {
+			node.Set(name, value)
+		}
+	}
+	itemChanges, update := node.delta()
+	if len(itemChanges) == 0 {
+		return nil, nil
+	}
+	settingsOp := node.assertUnchangedOp()
+	settingsOp.Update = update
+	return []txn.Op{{
+		C:      servicesC,
+		Id:     service.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"charmurl", service.doc.CharmURL}},
+	}, settingsOp}, nil
+}
+
 var ErrSubordinateConstraints = stderrors.New("constraints do not apply to subordinate services")
 
 // Constraints returns the current service constraints.
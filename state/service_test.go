@@ -651,6 +651,75 @@ func (s *ServiceSuite) TestUpdateConfigSettings(c *gc.C) {
 	}
 }
 
+func (s *ServiceSuite) TestUpdateServicesConfigSettings(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	svc1 := s.AddTestingService(c, "dummy1", sch)
+	svc2 := s.AddTestingService(c, "dummy2", sch)
+	err := svc2.UpdateConfigSettings(charm.Settings{"title": "sir"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.UpdateServicesConfigSettings(map[string]charm.Settings{
+		"dummy1": {"outlook": "positive"},
+		"dummy2": {"title": nil, "skill-level": 303},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "positive"})
+	settings, err = svc2.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"skill-level": int64(303)})
+}
+
+func (s *ServiceSuite) TestUpdateServicesConfigSettingsAllOrNothing(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	svc1 := s.AddTestingService(c, "dummy1", sch)
+	s.AddTestingService(c, "dummy2", sch)
+
+	err := s.State.UpdateServicesConfigSettings(map[string]charm.Settings{
+		"dummy1": {"outlook": "positive"},
+		"dummy2": {"skill-level": "profound"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot update service settings: service "dummy2": option "skill-level" expected int, got "profound"`)
+	settings, err := svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+
+	err = s.State.UpdateServicesConfigSettings(map[string]charm.Settings{
+		"dummy1":  {"outlook": "positive"},
+		"missing": {"outlook": "negative"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot update service settings: service "missing" not found`)
+	settings, err = svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *ServiceSuite) TestUpdateServicesConfigSettingsConcurrentChange(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	svc1 := s.AddTestingService(c, "dummy1", sch)
+	svc2 := s.AddTestingService(c, "dummy2", sch)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := svc2.UpdateConfigSettings(charm.Settings{"title": "sir"})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err := s.State.UpdateServicesConfigSettings(map[string]charm.Settings{
+		"dummy1": {"outlook": "positive"},
+		"dummy2": {"outlook": "negative"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := svc1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "positive"})
+	settings, err = svc2.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "negative", "title": "sir"})
+}
+
 func assertNoSettingsRef(c *gc.C, st *state.State, svcName string, sch *state.Charm) {
 	_, err := state.ServiceSettingsRefCount(st, svcName, sch.URL())
 	c.Assert(err, gc.Equals, mgo.ErrNotFound)
@@ -1040,6 +1109,71 @@ func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ServiceSuite) TestAssignmentPolicy(c *gc.C) {
+	_, ok := s.mysql.AssignmentPolicy()
+	c.Assert(ok, jc.IsFalse)
+
+	err := s.mysql.SetAssignmentPolicy(state.AssignLeastLoaded)
+	c.Assert(err, jc.ErrorIsNil)
+	policy, ok := s.mysql.AssignmentPolicy()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(policy, gc.Equals, state.AssignLeastLoaded)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	policy, ok = s.mysql.AssignmentPolicy()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(policy, gc.Equals, state.AssignLeastLoaded)
+
+	err = s.mysql.SetAssignmentPolicy(state.AssignLocal)
+	c.Assert(err, gc.ErrorMatches, `cannot set assignment policy for service "mysql": unit assignment policy "local" not valid`)
+
+	err = s.mysql.SetAssignmentPolicy("")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.mysql.AssignmentPolicy()
+	c.Assert(ok, jc.IsFalse)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetAssignmentPolicy(state.AssignNew)
+	c.Assert(err, gc.ErrorMatches, notAliveErr)
+}
+
+func (s *ServiceSuite) TestCharmOrigin(c *gc.C) {
+	_, ok := s.mysql.CharmOrigin()
+	c.Assert(ok, jc.IsFalse)
+
+	origin := &state.CharmOrigin{StoreURL: "https://api.jujucharms.com/charmstore"}
+	err := s.mysql.SetCharmOrigin(origin)
+	c.Assert(err, jc.ErrorIsNil)
+	got, ok := s.mysql.CharmOrigin()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got, gc.Equals, *origin)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	got, ok = s.mysql.CharmOrigin()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got, gc.Equals, *origin)
+
+	err = s.mysql.SetCharmOrigin(&state.CharmOrigin{})
+	c.Assert(err, gc.ErrorMatches, `cannot set charm origin for service "mysql": charm origin without store URL not valid`)
+
+	err = s.mysql.SetCharmOrigin(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.mysql.CharmOrigin()
+	c.Assert(ok, jc.IsFalse)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetCharmOrigin(origin)
+	c.Assert(err, gc.ErrorMatches, notAliveErr)
+}
+
 func (s *ServiceSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit()
@@ -1728,6 +1862,47 @@ func (s *ServiceSuite) TestWatchService(c *gc.C) {
 	testing.NewNotifyWatcherC(c, s.State, w).AssertOneChange()
 }
 
+func (s *ServiceSuite) TestWatchRemoval(c *gc.C) {
+	w := s.mysql.WatchRemoval()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Adding a unit of another service does not notify.
+	wpch := s.AddTestingCharm(c, "wordpress")
+	wp := s.AddTestingService(c, "wordpress", wpch)
+	_, err := wp.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Units and relations of the service notify.
+	unit, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Destroying the service notifies, as do the removals of its
+	// unit and relation.
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 // SCHEMACHANGE
 // TODO(mattyw) remove when schema upgrades are possible
 // Check that GetOwnerTag returns user-admin even
@@ -133,6 +133,30 @@ func cacheKeys(caches ...map[string]interface{}) map[string]bool {
 // as a delta applied on top of the latest version of the node, to prevent
 // overwriting unrelated changes made to the node since it was last read.
 func (c *Settings) Write() ([]ItemChange, error) {
+	changes, update := c.delta()
+	if len(changes) == 0 {
+		return []ItemChange{}, nil
+	}
+	ops := []txn.Op{{
+		C:      settingsC,
+		Id:     c.st.docID(c.key),
+		Assert: txn.DocExists,
+		Update: update,
+	}}
+	err := c.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return nil, errors.NotFoundf("settings")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot write settings: %v", err)
+	}
+	c.disk = copyMap(c.core, nil)
+	return changes, nil
+}
+
+// delta returns the changes made to c since it was last read or
+// written, and the update that applies them to its node.
+func (c *Settings) delta() ([]ItemChange, bson.D) {
 	changes := []ItemChange{}
 	updates := bson.M{}
 	deletions := bson.M{}
@@ -159,25 +183,8 @@ func (c *Settings) Write() ([]ItemChange, error) {
 		}
 		changes = append(changes, change)
 	}
-	if len(changes) == 0 {
-		return []ItemChange{}, nil
-	}
 	sort.Sort(itemChangeSlice(changes))
-	ops := []txn.Op{{
-		C:      settingsC,
-		Id:     c.st.docID(c.key),
-		Assert: txn.DocExists,
-		Update: setUnsetUpdate(updates, deletions),
-	}}
-	err := c.st.runTransaction(ops)
-	if err == txn.ErrAborted {
-		return nil, errors.NotFoundf("settings")
-	}
-	if err != nil {
-		return nil, fmt.Errorf("cannot write settings: %v", err)
-	}
-	c.disk = copyMap(c.core, nil)
-	return changes, nil
+	return changes, setUnsetUpdate(updates, deletions)
 }
 
 func newSettings(st *State, key string) *Settings {
@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// SSHHostKeys returns the public SSH host keys reported by the
+// machine agent, in authorized_keys format. Clients use them to
+// verify the identity of the machine when connecting over SSH.
+func (m *Machine) SSHHostKeys() []string {
+	keys := make([]string, len(m.doc.SSHHostKeys))
+	copy(keys, m.doc.SSHHostKeys)
+	return keys
+}
+
+// SetSSHHostKeys records the public SSH host keys of the machine,
+// replacing any recorded previously.
+func (m *Machine) SetSSHHostKeys(keys []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set SSH host keys of machine %s", m)
+	if len(keys) == 0 {
+		keys = nil
+	} else {
+		keys = append([]string(nil), keys...)
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"sshhostkeys", keys}}}},
+	}}
+	if err := m.st.runTransaction(ops); err != nil {
+		return onAbort(err, ErrDead)
+	}
+	m.doc.SSHHostKeys = keys
+	return nil
+}
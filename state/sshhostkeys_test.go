@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type SSHHostKeysSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&SSHHostKeysSuite{})
+
+func (s *SSHHostKeysSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SSHHostKeysSuite) TestSetSSHHostKeys(c *gc.C) {
+	c.Assert(s.machine.SSHHostKeys(), gc.HasLen, 0)
+
+	keys := []string{"ssh-rsa AAAAB3Nza root@host", "ssh-ed25519 AAAAC3Nza root@host"}
+	err := s.machine.SetSSHHostKeys(keys)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.SSHHostKeys(), jc.DeepEquals, keys)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.SSHHostKeys(), jc.DeepEquals, keys)
+
+	// Keys are replaced, not merged.
+	err = m.SetSSHHostKeys(keys[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.SSHHostKeys(), jc.DeepEquals, keys[1:])
+
+	err = m.SetSSHHostKeys(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.SSHHostKeys(), gc.HasLen, 0)
+}
+
+func (s *SSHHostKeysSuite) TestSetSSHHostKeysDead(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetSSHHostKeys([]string{"ssh-rsa AAAAB3Nza root@host"})
+	c.Assert(err, gc.ErrorMatches, `cannot set SSH host keys of machine 0: not found or dead`)
+}
@@ -74,6 +74,8 @@ const (
 	annotationsC           = "annotations"
 	statusesC              = "statuses"
 	statusesHistoryC       = "statuseshistory"
+	unitEventsC            = "unitevents"
+	workerHealthC          = "workerhealth"
 	stateServersC          = "stateServers"
 	openedPortsC           = "openedPorts"
 	metricsC               = "metrics"
@@ -101,6 +103,16 @@ const (
 	// toolsmetadataC is the collection used to store tools metadata.
 	toolsmetadataC = "toolsmetadata"
 
+	// auditC is the capped collection used to record mutating API
+	// calls. Documents cannot be removed from a capped collection, so
+	// entries outlive their environment and the collection is not
+	// subject to automatic environment filtering.
+	auditC = "audit"
+
+	// macaroonKeysC holds the root keys of the macaroons minted by the
+	// API servers, so that all of them can check any macaroon.
+	macaroonKeysC = "macaroonkeys"
+
 	// These collections are used by the mgo transaction runner.
 	txnLogC = "txns.log"
 	txnsC   = "txns"
@@ -135,11 +147,17 @@ type State struct {
 	db                *mgo.Database
 	watcher           *watcher.Watcher
 	pwatcher          *presence.Watcher
-	// mu guards allManager.
+	// pconfigWatcher watches the environment's configuration
+	// for changes to the presence timeout of pwatcher.
+	pconfigWatcher NotifyWatcher
+	// mu guards allManager and auditLogReady.
 	mu         sync.Mutex
 	allManager *storeManager
-	environTag names.EnvironTag
-	serverTag  names.EnvironTag
+	// auditLogReady records that the audit log collection
+	// is known to exist.
+	auditLogReady bool
+	environTag    names.EnvironTag
+	serverTag     names.EnvironTag
 }
 
 // StateServingInfo holds information needed by a state server.
@@ -278,6 +296,24 @@ func (st *State) getPresence() *mgo.Collection {
 func (st *State) startPresenceWatcher() {
 	pdb := st.db.Session.DB("presence")
 	st.pwatcher = presence.NewWatcher(pdb.C(presenceC), st.environTag)
+	st.setPresenceTimeout()
+	st.pconfigWatcher = st.WatchForEnvironConfigChanges()
+	go func() {
+		// The channel is closed when the watcher is stopped.
+		for range st.pconfigWatcher.Changes() {
+			st.setPresenceTimeout()
+		}
+	}()
+}
+
+// setPresenceTimeout sets the timeout of the presence watcher
+// from the environment's agent-presence-timeout setting.
+func (st *State) setPresenceTimeout() {
+	// The environment's configuration does not exist yet while
+	// it is being initialized, in which case the default is used.
+	if cfg, err := st.EnvironConfig(); err == nil {
+		st.pwatcher.SetTimeout(cfg.AgentPresenceTimeout())
+	}
 }
 
 // newDB returns a database connection using a new session, along with
@@ -1649,6 +1685,16 @@ func (st *State) strictLocalID(ID string) (string, error) {
 // uniquely specify a possible relation once all implicit relations have been
 // filtered, the endpoints corresponding to that relation will be returned.
 func (st *State) InferEndpoints(names ...string) ([]Endpoint, error) {
+	return st.InferEndpointsVia("", names...)
+}
+
+// InferEndpointsVia is like InferEndpoints, but only considers relations
+// using the supplied interface. If the interface is empty, relations using
+// any interface are considered.
+func (st *State) InferEndpointsVia(interfaceName string, names ...string) ([]Endpoint, error) {
+	usesInterface := func(ep Endpoint) bool {
+		return interfaceName == "" || ep.Interface == interfaceName
+	}
 	// Collect all possible sane endpoint lists.
 	var candidates [][]Endpoint
 	switch len(names) {
@@ -1658,7 +1704,9 @@ func (st *State) InferEndpoints(names ...string) ([]Endpoint, error) {
 			return nil, errors.Trace(err)
 		}
 		for _, ep := range eps {
-			candidates = append(candidates, []Endpoint{ep})
+			if usesInterface(ep) {
+				candidates = append(candidates, []Endpoint{ep})
+			}
 		}
 	case 2:
 		eps1, err := st.endpoints(names[0], notPeer)
@@ -1671,7 +1719,7 @@ func (st *State) InferEndpoints(names ...string) ([]Endpoint, error) {
 		}
 		for _, ep1 := range eps1 {
 			for _, ep2 := range eps2 {
-				if ep1.CanRelateTo(ep2) && containerScopeOk(st, ep1, ep2) {
+				if ep1.CanRelateTo(ep2) && usesInterface(ep1) && containerScopeOk(st, ep1, ep2) {
 					candidates = append(candidates, []Endpoint{ep1, ep2})
 				}
 			}
@@ -1682,6 +1730,9 @@ func (st *State) InferEndpoints(names ...string) ([]Endpoint, error) {
 	// If there's ambiguity, try discarding implicit relations.
 	switch len(candidates) {
 	case 0:
+		if interfaceName != "" {
+			return nil, errors.Errorf("no relations found using interface %q", interfaceName)
+		}
 		return nil, errors.Errorf("no relations found")
 	case 1:
 		return candidates[0], nil
@@ -2002,6 +2053,11 @@ func (st *State) AssignUnit(u *Unit, policy AssignmentPolicy) (err error) {
 		return u.AssignToNewMachineOrContainer()
 	case AssignNew:
 		return errors.Trace(u.AssignToNewMachine())
+	case AssignLeastLoaded:
+		if _, err = u.AssignToLeastLoadedMachine(); err != noCleanMachines {
+			return errors.Trace(err)
+		}
+		return u.AssignToNewMachineOrContainer()
 	}
 	return errors.Errorf("unknown unit assignment policy: %q", policy)
 }
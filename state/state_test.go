@@ -2071,6 +2071,43 @@ func (s *StateSuite) TestInferEndpoints(c *gc.C) {
 	}
 }
 
+func (s *StateSuite) TestInferEndpointsVia(c *gc.C) {
+	s.AddTestingService(c, "wp", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "lg", s.AddTestingCharm(c, "logging"))
+
+	// Without an interface, the explicit logging relation is preferred.
+	eps, err := s.State.InferEndpointsVia("", "lg", "wp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, gc.HasLen, 2)
+	c.Assert(eps[0].Name, gc.Equals, "logging-directory")
+	c.Assert(eps[1].Name, gc.Equals, "logging-dir")
+
+	// The implicit juju-info relation can be chosen by interface.
+	eps, err = s.State.InferEndpointsVia("juju-info", "lg", "wp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, gc.DeepEquals, []state.Endpoint{{
+		ServiceName: "lg",
+		Relation: charm.Relation{
+			Interface: "juju-info",
+			Name:      "info",
+			Role:      charm.RoleRequirer,
+			Scope:     charm.ScopeContainer,
+			Limit:     1,
+		},
+	}, {
+		ServiceName: "wp",
+		Relation: charm.Relation{
+			Interface: "juju-info",
+			Name:      "juju-info",
+			Role:      charm.RoleProvider,
+			Scope:     charm.ScopeGlobal,
+		},
+	}})
+
+	_, err = s.State.InferEndpointsVia("mysql", "lg", "wp")
+	c.Assert(err, gc.ErrorMatches, `no relations found using interface "mysql"`)
+}
+
 func (s *StateSuite) TestEnvironConfig(c *gc.C) {
 	attrs := map[string]interface{}{
 		"authorized-keys": "different-keys",
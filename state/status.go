@@ -284,7 +284,7 @@ func newHistoricalStatusDoc(s statusDoc, entityId string) *historicalStatusDoc {
 func updateStatusHistory(oldDoc statusDoc, globalKey string, st *State) error {
 	id, err := st.sequence("statushistory")
 	if err != nil {
-		errors.Annotatef(err, "cannot make id updating status history of unit agent %q", globalKey)
+		return errors.Annotatef(err, "cannot make id updating status history of %q", globalKey)
 	}
 	hDoc := newHistoricalStatusDoc(oldDoc, globalKey)
 
@@ -295,7 +295,7 @@ func updateStatusHistory(oldDoc statusDoc, globalKey string, st *State) error {
 	}
 
 	err = st.runTransaction([]txn.Op{h})
-	return errors.Annotatef(err, "cannot update status history of unit agent %q", globalKey)
+	return errors.Annotatef(err, "cannot update status history of %q", globalKey)
 }
 
 func statusHistory(size int, globalKey string, st *State) ([]StatusInfo, error) {
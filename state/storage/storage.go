@@ -1,6 +1,13 @@
 // Copyright 2014 Canonical Ltd.
 // Licensed under the AGPLv3, see LICENCE file for details.
 
+// Package storage provides binary storage by path, namespaced to an
+// environment and held in the state server's MongoDB. The data lives
+// in GridFS and is managed by the juju/blobstore package, so storing
+// identical data at several paths keeps a single copy.
+//
+// Charms and custom image metadata are stored here. Tools and backups
+// have their own stores, in state/toolstorage and state/backups.
 package storage
 
 import (
@@ -36,7 +43,7 @@ type Storage interface {
 	Remove(path string) error
 }
 
-// Storage returns a Storage for the environment with the specified UUID.
+// NewStorage returns a Storage for the environment with the specified UUID.
 func NewStorage(envUUID string, session *mgo.Session) Storage {
 	return stateStorage{envUUID, session}
 }
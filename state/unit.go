@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/juju/errors"
@@ -50,8 +51,23 @@ const (
 	// AssignNew indicates that every service unit should be assigned to a new
 	// dedicated machine.  A new machine will be launched for each new unit.
 	AssignNew AssignmentPolicy = "new"
+
+	// AssignLeastLoaded indicates that every service unit should be assigned
+	// to the existing machine hosting the fewest principal units, and that
+	// new machines should be launched only if no existing machine is suitable.
+	AssignLeastLoaded AssignmentPolicy = "least-loaded"
 )
 
+// Validate returns an error if the policy is not one that may be
+// configured for a service or environment.
+func (policy AssignmentPolicy) Validate() error {
+	switch policy {
+	case AssignClean, AssignCleanEmpty, AssignNew, AssignLeastLoaded:
+		return nil
+	}
+	return errors.NotValidf("unit assignment policy %q", policy)
+}
+
 // ResolvedMode describes the way state transition errors
 // are resolved.
 type ResolvedMode string
@@ -655,7 +671,13 @@ func (u *Unit) Remove() (err error) {
 		}
 		return nil, jujutxn.ErrNoOperations
 	}
-	return unit.st.run(buildTxn)
+	if err = unit.st.run(buildTxn); err != nil {
+		return err
+	}
+	if err := unit.eraseEvents(); err != nil {
+		logger.Errorf("cannot delete events for unit %q: %v", unit, err)
+	}
+	return nil
 }
 
 // Resolved returns the resolved mode for the unit.
@@ -1170,6 +1192,9 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	if u.doc.Principal != "" {
 		return fmt.Errorf("unit is a subordinate")
 	}
+	if err := u.checkArch(m); err != nil {
+		return err
+	}
 	canHost := false
 	for _, j := range m.doc.Jobs {
 		if j == JobHostUnits {
@@ -1330,6 +1355,60 @@ func assignContextf(err *error, unit *Unit, target string) {
 	}
 }
 
+// checkArch returns an error if the unit's constraints require an
+// architecture other than that of the given machine. Although direct
+// assignment otherwise ignores constraints, a unit cannot run at all
+// on a machine of the wrong architecture.
+func (u *Unit) checkArch(m *Machine) error {
+	cons, err := u.Constraints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cons.Arch == nil || *cons.Arch == "" {
+		return nil
+	}
+	arch, err := machineArch(m)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if arch != "" && arch != *cons.Arch {
+		return fmt.Errorf("machine architecture %q does not match required architecture %q", arch, *cons.Arch)
+	}
+	return nil
+}
+
+// machineArch returns the architecture of the given machine, if it
+// is known. The architecture of a machine that has not yet been
+// provisioned is taken from its constraints or, failing that, from
+// its host machine if it is a container.
+func machineArch(m *Machine) (string, error) {
+	for {
+		hc, err := m.HardwareCharacteristics()
+		if err == nil {
+			if hc.Arch != nil {
+				return *hc.Arch, nil
+			}
+			return "", nil
+		} else if !errors.IsNotFound(err) {
+			return "", errors.Trace(err)
+		}
+		cons, err := m.Constraints()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if cons.Arch != nil && *cons.Arch != "" {
+			return *cons.Arch, nil
+		}
+		parentId, ok := m.ParentId()
+		if !ok {
+			return "", nil
+		}
+		if m, err = m.st.Machine(parentId); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+}
+
 // AssignToMachine assigns this unit to a given machine.
 func (u *Unit) AssignToMachine(m *Machine) (err error) {
 	defer assignContextf(&err, u, fmt.Sprintf("machine %s", m))
@@ -1690,7 +1769,13 @@ var hasNoContainersTerm = bson.DocElem{
 
 // findCleanMachineQuery returns a Mongo query to find clean (and possibly empty) machines with
 // characteristics matching the specified constraints.
-func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value) (_ *mgo.Query, _ func(), err error) {
+func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value) (*mgo.Query, func(), error) {
+	return u.findMachineQuery(true, requireEmpty, cons)
+}
+
+// findMachineQuery returns a Mongo query to find machines, clean or not and
+// possibly empty, with characteristics matching the specified constraints.
+func (u *Unit) findMachineQuery(requireClean, requireEmpty bool, cons *constraints.Value) (_ *mgo.Query, _ func(), err error) {
 	db, closer := u.st.newDB()
 	defer func() {
 		if err != nil {
@@ -1699,7 +1784,8 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	}()
 	containerRefsCollection := db.C(containerRefsC)
 
-	// Select all machines that can accept principal units and are clean.
+	// Select all machines that can accept principal units and are clean
+	// if required.
 	var containerRefs []machineContainers
 	// If we need empty machines, first build up a list of machine ids which have containers
 	// so we can exclude those.
@@ -1717,9 +1803,11 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		{"life", Alive},
 		{"series", u.doc.Series},
 		{"jobs", []MachineJob{JobHostUnits}},
-		{"clean", true},
 		{"machineid", bson.D{{"$nin", machinesWithContainers}}},
 	}
+	if requireClean {
+		terms = append(terms, bson.DocElem{"clean", true})
+	}
 	// Add the container filter term if necessary.
 	var containerType instance.ContainerType
 	if cons.Container != nil {
@@ -1883,6 +1971,88 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 	return nil, noCleanMachines
 }
 
+// AssignToLeastLoadedMachine assigns u to the machine hosting the fewest
+// principal units, other than those already hosting a unit of the same
+// service. Machines hosting equal numbers of units are chosen between in
+// order of id. If there are no such machines besides any machine(s)
+// running JobHostEnviron, an error is returned.
+func (u *Unit) AssignToLeastLoadedMachine() (m *Machine, err error) {
+	context := "least loaded machine"
+	if u.doc.Principal != "" {
+		err = fmt.Errorf("unit is a subordinate")
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	storageCons, err := u.StorageConstraints()
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	if len(storageCons) > 0 {
+		return nil, noCleanMachines
+	}
+	cons, err := u.Constraints()
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	query, closer, err := u.findMachineQuery(false, false, cons)
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	defer closer()
+	var mdocs []*machineDoc
+	if err := query.All(&mdocs); err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	var machines []*Machine
+	for _, mdoc := range mdocs {
+		if hostsServiceUnit(mdoc, u.doc.Service) {
+			continue
+		}
+		machines = append(machines, newMachine(u.st, mdoc))
+	}
+	sort.Sort(byPrincipalCount(machines))
+	for _, m := range machines {
+		err := u.assignToMachine(m, false)
+		if err == nil {
+			return m, nil
+		}
+		if err != machineNotAliveErr {
+			assignContextf(&err, u, context)
+			return nil, err
+		}
+	}
+	return nil, noCleanMachines
+}
+
+// hostsServiceUnit returns whether the machine described by mdoc
+// hosts a principal unit of the named service.
+func hostsServiceUnit(mdoc *machineDoc, serviceName string) bool {
+	for _, unitName := range mdoc.Principals {
+		if name, err := names.UnitService(unitName); err == nil && name == serviceName {
+			return true
+		}
+	}
+	return false
+}
+
+// byPrincipalCount sorts machines by the number of principal units
+// they host, and then by id.
+type byPrincipalCount []*Machine
+
+func (b byPrincipalCount) Len() int      { return len(b) }
+func (b byPrincipalCount) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byPrincipalCount) Less(i, j int) bool {
+	ni, nj := len(b[i].doc.Principals), len(b[j].doc.Principals)
+	if ni != nj {
+		return ni < nj
+	}
+	return machineIdLessThan(b[i].Id(), b[j].Id())
+}
+
 // UnassignFromMachine removes the assignment between this unit and the
 // machine it's assigned to.
 func (u *Unit) UnassignFromMachine() (err error) {
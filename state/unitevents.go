@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// UnitEventKind identifies the kind of an event recorded in a unit's
+// event log.
+type UnitEventKind string
+
+const (
+	// UnitEventHookStarted is recorded when a hook starts running.
+	UnitEventHookStarted UnitEventKind = "hook-started"
+
+	// UnitEventHookCompleted is recorded when a hook runs successfully.
+	UnitEventHookCompleted UnitEventKind = "hook-completed"
+
+	// UnitEventHookFailed is recorded when a hook fails.
+	UnitEventHookFailed UnitEventKind = "hook-failed"
+)
+
+// Valid returns true if the kind is known.
+func (kind UnitEventKind) Valid() bool {
+	switch kind {
+	case UnitEventHookStarted, UnitEventHookCompleted, UnitEventHookFailed:
+		return true
+	}
+	return false
+}
+
+// UnitEvent describes something that happened to a unit's workload,
+// such as the execution of a hook.
+type UnitEvent struct {
+	// Kind identifies what happened.
+	Kind UnitEventKind
+
+	// Hook is the name of the hook the event refers to.
+	Hook string
+
+	// Time is when the event happened.
+	Time time.Time
+
+	// Duration is how long the hook ran for. It is zero for
+	// UnitEventHookStarted events.
+	Duration time.Duration
+
+	// Message holds any further information, such as the error
+	// reported by a failed hook.
+	Message string
+}
+
+// maxUnitEvents is the number of events kept in each unit's event log.
+// Older events are discarded as new ones are recorded.
+var maxUnitEvents = 100
+
+// unitEventDoc is the document used to store a UnitEvent.
+type unitEventDoc struct {
+	DocID    string        `bson:"_id"`
+	EnvUUID  string        `bson:"env-uuid"`
+	Unit     string        `bson:"unit"`
+	Seq      int           `bson:"seq"`
+	Kind     UnitEventKind `bson:"kind"`
+	Hook     string        `bson:"hook"`
+	Time     time.Time     `bson:"time"`
+	Duration time.Duration `bson:"duration,omitempty"`
+	Message  string        `bson:"message,omitempty"`
+}
+
+// RecordEvent adds the given event to the unit's event log, discarding
+// the oldest events if the log has grown too large.
+func (u *Unit) RecordEvent(event UnitEvent) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record event for unit %q", u)
+	if !event.Kind.Valid() {
+		return errors.NotValidf("event kind %q", event.Kind)
+	}
+	seq, err := u.st.sequence("unitevents")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+	}, {
+		C:      unitEventsC,
+		Id:     fmt.Sprintf("%s#%d", u.globalKey(), seq),
+		Assert: txn.DocMissing,
+		Insert: &unitEventDoc{
+			Unit:     u.Name(),
+			Seq:      seq,
+			Kind:     event.Kind,
+			Hook:     event.Hook,
+			Time:     event.Time.UTC(),
+			Duration: event.Duration,
+			Message:  event.Message,
+		},
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("unit")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return u.pruneEvents(maxUnitEvents)
+}
+
+// pruneEvents removes all but the newest size events from the unit's
+// event log.
+func (u *Unit) pruneEvents(size int) error {
+	events, closer := u.st.getCollection(unitEventsC)
+	defer closer()
+
+	var oldest unitEventDoc
+	err := events.Find(bson.D{{"unit", u.Name()}}).Sort("-seq").Skip(size - 1).One(&oldest)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = events.RemoveAll(bson.D{
+		{"unit", u.Name()},
+		{"seq", bson.D{{"$lt", oldest.Seq}}},
+	})
+	return errors.Trace(err)
+}
+
+// Events returns at most size of the most recent events in the unit's
+// event log, newest first.
+func (u *Unit) Events(size int) ([]UnitEvent, error) {
+	events, closer := u.st.getCollection(unitEventsC)
+	defer closer()
+
+	var docs []unitEventDoc
+	err := events.Find(bson.D{{"unit", u.Name()}}).Sort("-seq").Limit(size).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get events for unit %q", u)
+	}
+	result := make([]UnitEvent, len(docs))
+	for i, doc := range docs {
+		result[i] = UnitEvent{
+			Kind:     doc.Kind,
+			Hook:     doc.Hook,
+			Time:     doc.Time.UTC(),
+			Duration: doc.Duration,
+			Message:  doc.Message,
+		}
+	}
+	return result, nil
+}
+
+// eraseEvents removes the unit's event log.
+func (u *Unit) eraseEvents() error {
+	events, closer := u.st.getCollection(unitEventsC)
+	defer closer()
+
+	_, err := events.RemoveAll(bson.D{{"unit", u.Name()}})
+	return errors.Trace(err)
+}
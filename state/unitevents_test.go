@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UnitEventsSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&UnitEventsSuite{})
+
+func (s *UnitEventsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = s.factory.MakeUnit(c, nil)
+}
+
+func (s *UnitEventsSuite) TestRecordEvent(c *gc.C) {
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []state.UnitEvent{{
+		Kind: state.UnitEventHookStarted,
+		Hook: "install",
+		Time: t0,
+	}, {
+		Kind:     state.UnitEventHookFailed,
+		Hook:     "install",
+		Time:     t0.Add(3 * time.Second),
+		Duration: 3 * time.Second,
+		Message:  "exit status 1",
+	}}
+	for _, event := range events {
+		err := s.unit.RecordEvent(event)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	recorded, err := s.unit.Events(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, jc.DeepEquals, []state.UnitEvent{events[1], events[0]})
+
+	recorded, err = s.unit.Events(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, jc.DeepEquals, []state.UnitEvent{events[1]})
+}
+
+func (s *UnitEventsSuite) TestRecordEventInvalidKind(c *gc.C) {
+	err := s.unit.RecordEvent(state.UnitEvent{Kind: "exploded", Hook: "install"})
+	c.Assert(err, gc.ErrorMatches, `cannot record event for unit "[^"]+": event kind "exploded" not valid`)
+}
+
+func (s *UnitEventsSuite) TestEventsArePruned(c *gc.C) {
+	s.PatchValue(state.MaxUnitEvents, 3)
+	t0 := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		err := s.unit.RecordEvent(state.UnitEvent{
+			Kind: state.UnitEventHookCompleted,
+			Hook: "update-status",
+			Time: t0.Add(time.Duration(i) * time.Minute),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	recorded, err := s.unit.Events(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, gc.HasLen, 3)
+	c.Assert(recorded[0].Time, gc.Equals, t0.Add(4*time.Minute))
+	c.Assert(recorded[2].Time, gc.Equals, t0.Add(2*time.Minute))
+}
+
+func (s *UnitEventsSuite) TestEventsArePerUnit(c *gc.C) {
+	other := s.factory.MakeUnit(c, nil)
+	err := other.RecordEvent(state.UnitEvent{
+		Kind: state.UnitEventHookStarted,
+		Hook: "start",
+		Time: time.Now(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	recorded, err := s.unit.Events(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, gc.HasLen, 0)
+}
+
+func (s *UnitEventsSuite) TestRemoveErasesEvents(c *gc.C) {
+	err := s.unit.RecordEvent(state.UnitEvent{
+		Kind: state.UnitEventHookStarted,
+		Hook: "stop",
+		Time: time.Now(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	recorded, err := s.unit.Events(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, gc.HasLen, 0)
+
+	err = s.unit.RecordEvent(state.UnitEvent{
+		Kind: state.UnitEventHookStarted,
+		Hook: "stop",
+		Time: time.Now(),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -1383,3 +1383,79 @@ func AddDefaultBlockDevicesDocs(st *State) error {
 	}
 	return nil
 }
+
+// SetStorageAttachmentCounts sets the attachment count of all existing
+// volumes and filesystems in all environments from their attachments,
+// as the count was not recorded before volume and filesystem
+// lifecycles were modelled.
+func SetStorageAttachmentCounts(st *State) error {
+	environments, closer := st.getCollection(environmentsC)
+	defer closer()
+
+	var envDocs []bson.M
+	err := environments.Find(nil).Select(bson.M{"_id": 1}).All(&envDocs)
+	if err != nil {
+		return errors.Annotate(err, "failed to read environments")
+	}
+
+	for _, envDoc := range envDocs {
+		envUUID := envDoc["_id"].(string)
+		if err := setEnvironAttachmentCounts(st, envUUID); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// setEnvironAttachmentCounts sets the attachment counts of the volumes
+// and filesystems in the environment with the given UUID, closing its
+// State before returning so that environments are not held open for
+// the whole upgrade step.
+func setEnvironAttachmentCounts(st *State, envUUID string) error {
+	envSt, err := st.ForEnviron(names.NewEnvironTag(envUUID))
+	if err != nil {
+		return errors.Annotatef(err, "failed to open environment %q", envUUID)
+	}
+	defer envSt.Close()
+
+	if err := setAttachmentCounts(envSt, volumesC, volumeAttachmentsC, "volumeid"); err != nil {
+		return errors.Annotatef(err, "failed to set volume attachment counts for environment %q", envUUID)
+	}
+	if err := setAttachmentCounts(envSt, filesystemsC, filesystemAttachmentsC, "filesystemid"); err != nil {
+		return errors.Annotatef(err, "failed to set filesystem attachment counts for environment %q", envUUID)
+	}
+	return nil
+}
+
+// setAttachmentCounts sets the attachment count of each document in the
+// named collection, keyed by name, to the number of documents in the
+// attachments collection whose idField holds that name.
+func setAttachmentCounts(st *State, collName, attachmentsCollName, idField string) error {
+	attachments, closer := st.getCollection(attachmentsCollName)
+	defer closer()
+
+	var attachmentDocs []bson.M
+	if err := attachments.Find(nil).Select(bson.M{idField: 1}).All(&attachmentDocs); err != nil {
+		return errors.Trace(err)
+	}
+	counts := make(map[string]int)
+	for _, doc := range attachmentDocs {
+		if id, ok := doc[idField].(string); ok {
+			counts[id]++
+		}
+	}
+
+	var ops []txn.Op
+	for id, count := range counts {
+		ops = append(ops, txn.Op{
+			C:      collName,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"attachmentcount", count}}}},
+		})
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	return st.runTransaction(ops)
+}
@@ -2842,3 +2842,51 @@ func (s *upgradesSuite) TestMoveServiceUnitSeqToSequenceWithPreExistingSequence(
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(count, gc.Equals, 7)
 }
+
+func (s *upgradesSuite) TestSetStorageAttachmentCounts(c *gc.C) {
+	volumes, closer := s.state.getRawCollection(volumesC)
+	defer closer()
+	attachments, closer := s.state.getRawCollection(volumeAttachmentsC)
+	defer closer()
+
+	envUUID := s.state.EnvironUUID()
+	for _, name := range []string{"0", "1", "2"} {
+		err := volumes.Insert(bson.D{
+			{"_id", s.state.docID(name)},
+			{"name", name},
+			{"env-uuid", envUUID},
+			{"life", Alive},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	for _, ids := range [][2]string{{"0", "0"}, {"0", "1"}, {"1", "1"}} {
+		machineId, volumeId := ids[0], ids[1]
+		err := attachments.Insert(bson.D{
+			{"_id", s.state.docID(volumeAttachmentId(machineId, volumeId))},
+			{"env-uuid", envUUID},
+			{"volumeid", volumeId},
+			{"machineid", machineId},
+			{"life", Alive},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err := SetStorageAttachmentCounts(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := map[string]int{"0": 1, "1": 2, "2": 0}
+	for name, count := range expected {
+		var doc volumeDoc
+		err := volumes.FindId(s.state.docID(name)).One(&doc)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(doc.AttachmentCount, gc.Equals, count, gc.Commentf("volume %s", name))
+	}
+
+	// Running the step again changes nothing.
+	err = SetStorageAttachmentCounts(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	var doc volumeDoc
+	err = volumes.FindId(s.state.docID("1")).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.AttachmentCount, gc.Equals, 2)
+}
@@ -10,6 +10,7 @@ package state
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -327,6 +328,103 @@ func (u *User) Disable() error {
 	return errors.Annotatef(u.setDeactivated(true), "cannot disable user %q", u.Name())
 }
 
+// Remove removes the user from state. Removed users cannot log in, and
+// their name becomes available for a new user. Users that own any
+// environment cannot be removed until those environments are destroyed.
+func (u *User) Remove() error {
+	environment, err := u.st.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if u.doc.Name == environment.Owner().Name() {
+		return errors.Unauthorizedf("cannot remove state server environment owner")
+	}
+	// The user's environment user documents belong to any number of
+	// environments, so they are removed with a raw transaction runner
+	// that leaves their IDs alone.
+	session := u.st.db.Session.Copy()
+	defer session.Close()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if _, err := u.st.User(u.UserTag()); errors.IsNotFound(err) {
+				return nil, fmt.Errorf("user no longer exists")
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		owned, err := u.ownedEnvironmentNames()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(owned) > 0 {
+			return nil, errors.Errorf("user owns environments: %s", strings.Join(owned, ", "))
+		}
+		ops := []txn.Op{{
+			C:      usersC,
+			Id:     u.doc.DocID,
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		envUserOps, err := u.removeEnvUsersOps()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, envUserOps...), nil
+	}
+	if err := u.st.rawTxnRunner(session).Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot remove user %q", u.Name())
+	}
+	return nil
+}
+
+// ownedEnvironmentNames returns the sorted names of the environments
+// owned by the user.
+func (u *User) ownedEnvironmentNames() ([]string, error) {
+	environments, closer := u.st.getRawCollection(environmentsC)
+	defer closer()
+
+	// Owner names are matched case insensitively, as user names are.
+	pattern := "^" + regexp.QuoteMeta(u.UserTag().Username()) + "$"
+	query := bson.D{{"owner", bson.RegEx{Pattern: pattern, Options: "i"}}}
+	var docs []environmentDoc
+	if err := environments.Find(query).Select(bson.D{{"name", 1}}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	names := make([]string, len(docs))
+	for i, doc := range docs {
+		names[i] = doc.Name
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// removeEnvUsersOps returns the operations to remove the user's access
+// to all environments.
+func (u *User) removeEnvUsersOps() ([]txn.Op, error) {
+	envUsers, closer := u.st.getRawCollection(envUsersC)
+	defer closer()
+
+	// Environment user names are matched case insensitively, as user
+	// names are.
+	pattern := "^" + regexp.QuoteMeta(u.UserTag().Username()) + "$"
+	query := bson.D{{"user", bson.RegEx{Pattern: pattern, Options: "i"}}}
+	var docs []envUserDoc
+	err := envUsers.Find(query).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      envUsersC,
+			Id:     doc.ID,
+			Assert: txn.DocExists,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
 // Enable reactivates the user, setting disabled to false.
 func (u *User) Enable() error {
 	return errors.Annotatef(u.setDeactivated(false), "cannot enable user %q", u.Name())
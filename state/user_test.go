@@ -202,6 +202,62 @@ func (s *UserSuite) TestCantDisableAdmin(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot disable state server environment owner")
 }
 
+func (s *UserSuite) TestRemove(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "Bob"})
+
+	err := user.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = user.Remove()
+	c.Assert(err, gc.ErrorMatches, `cannot remove user "Bob": user no longer exists`)
+
+	// The name can be reused once the user is removed.
+	_, err = s.State.AddUser("bob", "ignored", "ignored", "ignored")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserSuite) TestRemoveRemovesEnvironmentUsers(c *gc.C) {
+	// MakeUser also gives the user access to the current environment.
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "Bob"})
+	otherSt := s.factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+	_, err := otherSt.AddEnvironmentUser(user.UserTag(), s.Owner, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = user.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = otherSt.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	envs, err := s.State.EnvironmentsForUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs, gc.HasLen, 0)
+}
+
+func (s *UserSuite) TestCantRemoveEnvironmentOwner(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "Bob"})
+	otherSt := s.factory.MakeEnvironment(c, &factory.EnvParams{
+		Name:  "bobs-env",
+		Owner: user.UserTag(),
+	})
+	defer otherSt.Close()
+
+	err := user.Remove()
+	c.Assert(err, gc.ErrorMatches, `cannot remove user "Bob": user owns environments: bobs-env`)
+	_, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserSuite) TestCantRemoveAdmin(c *gc.C) {
+	user, err := s.State.User(s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Remove()
+	c.Assert(err, gc.ErrorMatches, "cannot remove state server environment owner")
+}
+
 func (s *UserSuite) TestCaseSensitiveUsersErrors(c *gc.C) {
 	s.factory.MakeUser(c, &factory.UserParams{Name: "Bob"})
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
@@ -79,13 +80,14 @@ type volumeAttachment struct {
 
 // volumeDoc records information about a volume in the environment.
 type volumeDoc struct {
-	DocID     string        `bson:"_id"`
-	Name      string        `bson:"name"`
-	EnvUUID   string        `bson:"env-uuid"`
-	Life      Life          `bson:"life"`
-	StorageId string        `bson:"storageid,omitempty"`
-	Info      *VolumeInfo   `bson:"info,omitempty"`
-	Params    *VolumeParams `bson:"params,omitempty"`
+	DocID           string        `bson:"_id"`
+	Name            string        `bson:"name"`
+	EnvUUID         string        `bson:"env-uuid"`
+	Life            Life          `bson:"life"`
+	StorageId       string        `bson:"storageid,omitempty"`
+	AttachmentCount int           `bson:"attachmentcount"`
+	Info            *VolumeInfo   `bson:"info,omitempty"`
+	Params          *VolumeParams `bson:"params,omitempty"`
 }
 
 // volumeAttachmentDoc records information about a volume attachment.
@@ -204,6 +206,14 @@ func (v *volumeAttachment) Params() (VolumeAttachmentParams, bool) {
 
 // Volume returns the Volume with the specified name.
 func (st *State) Volume(tag names.VolumeTag) (Volume, error) {
+	v, err := st.volumeByTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (st *State) volumeByTag(tag names.VolumeTag) (*volume, error) {
 	coll, cleanup := st.getCollection(volumesC)
 	defer cleanup()
 
@@ -348,6 +358,9 @@ func (st *State) addVolumeOp(params VolumeParams, machineId string) (txn.Op, nam
 			Name:      name,
 			StorageId: params.storage.Id(),
 			Params:    &params,
+			// Volumes are currently only created along with
+			// an attachment to the machine they are created for.
+			AttachmentCount: 1,
 		},
 	}
 	return op, names.NewVolumeTag(name), nil
@@ -578,3 +591,184 @@ func (st *State) AllVolumes() ([]Volume, error) {
 	}
 	return v, nil
 }
+
+// DetachVolume marks the volume attachment identified by the specified
+// machine and volume tags as Dying, if it is Alive. The storage
+// provisioner is responsible for detaching the volume from the machine
+// and then removing the attachment with RemoveVolumeAttachment.
+func (st *State) DetachVolume(machine names.MachineTag, volume names.VolumeTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "detaching volume %s from machine %s", volume.Id(), machine.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		va, err := st.VolumeAttachment(machine, volume)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if va.Life() != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return detachVolumeOps(machine, volume), nil
+	}
+	return st.run(buildTxn)
+}
+
+func detachVolumeOps(m names.MachineTag, v names.VolumeTag) []txn.Op {
+	return []txn.Op{{
+		C:      volumeAttachmentsC,
+		Id:     volumeAttachmentId(m.Id(), v.Id()),
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+	}}
+}
+
+// RemoveVolumeAttachment removes the volume attachment from state.
+// RemoveVolumeAttachment will fail if the attachment is Alive; it
+// must first be detached with DetachVolume. If the volume is Dying
+// and this is its last attachment, the volume becomes Dead.
+func (st *State) RemoveVolumeAttachment(machine names.MachineTag, volume names.VolumeTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "removing attachment of volume %s from machine %s", volume.Id(), machine.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		attachment, err := st.VolumeAttachment(machine, volume)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if attachment.Life() != Dying {
+			return nil, errors.New("volume attachment is not dying")
+		}
+		v, err := st.volumeByTag(volume)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return removeVolumeAttachmentOps(machine, v), nil
+	}
+	return st.run(buildTxn)
+}
+
+func removeVolumeAttachmentOps(m names.MachineTag, v *volume) []txn.Op {
+	decrefVolumeOp := txn.Op{
+		C:  volumesC,
+		Id: v.doc.Name,
+	}
+	if v.doc.Life == Dying && v.doc.AttachmentCount == 1 {
+		// This is the last attachment of a Dying volume, so the
+		// volume is now ready to be destroyed by the storage
+		// provisioner.
+		decrefVolumeOp.Assert = bson.D{{"life", Dying}, {"attachmentcount", 1}}
+		decrefVolumeOp.Update = bson.D{
+			{"$inc", bson.D{{"attachmentcount", -1}}},
+			{"$set", bson.D{{"life", Dead}}},
+		}
+	} else {
+		// Either the volume is Alive, or it is Dying with other
+		// attachments; allow for concurrent attachment removals,
+		// but make sure we don't miss removing the last one.
+		decrefVolumeOp.Assert = bson.D{{"$or", []bson.D{
+			{{"life", Alive}},
+			{{"attachmentcount", bson.D{{"$gt", 1}}}},
+		}}}
+		decrefVolumeOp.Update = bson.D{{"$inc", bson.D{{"attachmentcount", -1}}}}
+	}
+	return []txn.Op{{
+		C:      volumeAttachmentsC,
+		Id:     volumeAttachmentId(m.Id(), v.doc.Name),
+		Assert: bson.D{{"life", Dying}},
+		Remove: true,
+	}, decrefVolumeOp}
+}
+
+// DestroyVolume ensures that the volume and all of its attachments will
+// be removed at some point. The volume's attachments are marked Dying,
+// for the storage provisioner to detach; once they have all been
+// removed, the volume becomes Dead, and the storage provisioner will
+// destroy it and remove it from state.
+//
+// A volume that is assigned to a storage instance cannot be destroyed
+// directly while the storage instance exists.
+func (st *State) DestroyVolume(tag names.VolumeTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "destroying volume %s", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.volumeByTag(tag)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.doc.Life != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if v.doc.StorageId != "" {
+			storageTag := names.NewStorageTag(v.doc.StorageId)
+			if _, err := st.storageInstance(storageTag); err == nil {
+				return nil, errors.Errorf("volume is assigned to %s", names.ReadableString(storageTag))
+			} else if !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+		}
+		return st.destroyVolumeOps(v)
+	}
+	return st.run(buildTxn)
+}
+
+func (st *State) destroyVolumeOps(v *volume) ([]txn.Op, error) {
+	if v.doc.AttachmentCount == 0 {
+		// There are no attachments, so the volume
+		// can be destroyed by the storage provisioner
+		// immediately.
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     v.doc.Name,
+			Assert: append(bson.D{{"life", Alive}}, volumeHasNoAttachments...),
+			Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
+		}}, nil
+	}
+	attachments, err := st.VolumeAttachments(v.VolumeTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      volumesC,
+		Id:     v.doc.Name,
+		Assert: bson.D{{"life", Alive}, {"attachmentcount", bson.D{{"$gt", 0}}}},
+		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+	}}
+	for _, a := range attachments {
+		if a.Life() == Alive {
+			ops = append(ops, detachVolumeOps(a.Machine(), v.VolumeTag())...)
+		}
+	}
+	return ops, nil
+}
+
+var volumeHasNoAttachments = bson.D{{
+	"$or", []bson.D{
+		{{"attachmentcount", 0}},
+		{{"attachmentcount", bson.D{{"$exists", false}}}},
+	},
+}}
+
+// RemoveVolume removes the volume from state. RemoveVolume will fail
+// if the volume is not Dead, which implies that it has no attachments.
+func (st *State) RemoveVolume(tag names.VolumeTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "removing volume %s", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(tag)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Dead {
+			return nil, errors.New("volume is not dead")
+		}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: isDeadDoc,
+			Remove: true,
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
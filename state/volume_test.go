@@ -454,3 +454,94 @@ func (s *VolumeStateSuite) TestAllVolumes(c *gc.C) {
 		c.Assert(expected.Contains(one.VolumeTag()), jc.IsTrue)
 	}
 }
+
+func (s *VolumeStateSuite) addMachineWithVolume(c *gc.C) (names.MachineTag, names.VolumeTag) {
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.MachineVolumeParams{{
+			Volume: state.VolumeParams{Pool: "environscoped", Size: 1024},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := s.State.MachineVolumeAttachments(machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	return machine.MachineTag(), attachments[0].Volume()
+}
+
+func (s *VolumeStateSuite) assertVolumeLife(c *gc.C, tag names.VolumeTag, life state.Life) {
+	volume, err := s.State.Volume(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.Life(), gc.Equals, life)
+}
+
+func (s *VolumeStateSuite) assertVolumeAttachmentLife(c *gc.C, m names.MachineTag, v names.VolumeTag, life state.Life) {
+	attachment, err := s.State.VolumeAttachment(m, v)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachment.Life(), gc.Equals, life)
+}
+
+func (s *VolumeStateSuite) TestDestroyVolume(c *gc.C) {
+	machineTag, volumeTag := s.addMachineWithVolume(c)
+
+	err := s.State.DestroyVolume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeLife(c, volumeTag, state.Dying)
+	s.assertVolumeAttachmentLife(c, machineTag, volumeTag, state.Dying)
+
+	// Destroying again is a no-op.
+	err = s.State.DestroyVolume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveVolume(volumeTag)
+	c.Assert(err, gc.ErrorMatches, `removing volume 0: volume is not dead`)
+
+	// Removing the last attachment makes the volume Dead.
+	err = s.State.RemoveVolumeAttachment(machineTag, volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.VolumeAttachment(machineTag, volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertVolumeLife(c, volumeTag, state.Dead)
+
+	err = s.State.RemoveVolume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing again is a no-op.
+	err = s.State.RemoveVolume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *VolumeStateSuite) TestDetachVolume(c *gc.C) {
+	machineTag, volumeTag := s.addMachineWithVolume(c)
+
+	err := s.State.RemoveVolumeAttachment(machineTag, volumeTag)
+	c.Assert(err, gc.ErrorMatches, `removing attachment of volume 0 from machine 0: volume attachment is not dying`)
+
+	err = s.State.DetachVolume(machineTag, volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeAttachmentLife(c, machineTag, volumeTag, state.Dying)
+
+	// Removing the attachment of an Alive volume leaves it Alive
+	// and unattached; destroying it then makes it Dead immediately.
+	err = s.State.RemoveVolumeAttachment(machineTag, volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeLife(c, volumeTag, state.Alive)
+	err = s.State.DestroyVolume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeLife(c, volumeTag, state.Dead)
+}
+
+func (s *VolumeStateSuite) TestDestroyVolumeAssignedToStorage(c *gc.C) {
+	_, unit, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.DestroyVolume(volume.VolumeTag())
+	c.Assert(err, gc.ErrorMatches, `destroying volume 0/0: volume is assigned to storage data/0`)
+	s.assertVolumeLife(c, volume.VolumeTag(), state.Alive)
+}
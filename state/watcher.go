@@ -344,6 +344,90 @@ func (s *Service) WatchRelations() StringsWatcher {
 	return newLifecycleWatcher(s.st, relationsC, members, filter, nil)
 }
 
+// WatchRemoval returns a NotifyWatcher that notifies of changes to s,
+// its units and its relations, so that the progress of the service's
+// removal can be followed.
+func (s *Service) WatchRemoval() NotifyWatcher {
+	return newServiceRemovalWatcher(s)
+}
+
+// serviceRemovalWatcher notifies of changes to a service and to the
+// units and relations that must be removed along with it.
+type serviceRemovalWatcher struct {
+	commonWatcher
+	name string
+	out  chan struct{}
+}
+
+var _ Watcher = (*serviceRemovalWatcher)(nil)
+
+func newServiceRemovalWatcher(s *Service) NotifyWatcher {
+	w := &serviceRemovalWatcher{
+		commonWatcher: commonWatcher{st: s.st},
+		name:          s.doc.Name,
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *serviceRemovalWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// matches reports whether the document with the given id in the given
+// collection belongs to the watched service.
+func (w *serviceRemovalWatcher) matches(coll string, id interface{}) bool {
+	localID, err := w.st.strictLocalID(id.(string))
+	if err != nil {
+		return false
+	}
+	switch coll {
+	case servicesC:
+		return localID == w.name
+	case unitsC:
+		return strings.HasPrefix(localID, w.name+"/")
+	case relationsC:
+		prefix := w.name + ":"
+		return strings.HasPrefix(localID, prefix) || strings.Contains(localID, " "+prefix)
+	}
+	return false
+}
+
+func (w *serviceRemovalWatcher) loop() (err error) {
+	in := make(chan watcher.Change)
+	for _, coll := range []string{servicesC, unitsC, relationsC} {
+		coll := coll
+		filter := func(id interface{}) bool {
+			return w.matches(coll, id)
+		}
+		w.st.watcher.WatchCollectionWithFilter(coll, in, filter)
+		defer w.st.watcher.UnwatchCollection(coll, in)
+	}
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // WatchEnvironMachines returns a StringsWatcher that notifies of changes to
 // the lifecycles of the machines (but not containers) in the environment.
 func (st *State) WatchEnvironMachines() StringsWatcher {
@@ -861,6 +945,18 @@ type relationUnitsWatcher struct {
 	watching set.Strings
 	updates  chan watcher.Change
 	out      chan multiwatcher.RelationUnitsChange
+
+	// serviceKey holds the key of the counterpart service's settings
+	// within the relation. A unit's reported version is the sum of
+	// the revisions of its own settings and of the service's, so
+	// that a change to the service's settings is reported as a
+	// change to every counterpart unit.
+	serviceKey   string
+	serviceRevno int64
+
+	// unitRevnos holds the settings revision of each watched unit,
+	// keyed by the settings document id.
+	unitRevnos map[string]int64
 }
 
 // TODO(dfc) this belongs in a test
@@ -873,12 +969,21 @@ func (ru *RelationUnit) Watch() RelationUnitsWatcher {
 }
 
 func newRelationUnitsWatcher(ru *RelationUnit) RelationUnitsWatcher {
+	var serviceName string
+	role := counterpartRole(ru.endpoint.Role)
+	for _, ep := range ru.relation.Endpoints() {
+		if ep.Role == role {
+			serviceName = ep.ServiceName
+		}
+	}
 	w := &relationUnitsWatcher{
 		commonWatcher: commonWatcher{st: ru.st},
 		sw:            ru.WatchScope(),
 		watching:      make(set.Strings),
 		updates:       make(chan watcher.Change),
 		out:           make(chan multiwatcher.RelationUnitsChange),
+		serviceKey:    ru.relation.serviceSettingsKey(serviceName),
+		unitRevnos:    make(map[string]int64),
 	}
 	go func() {
 		defer w.finish()
@@ -916,10 +1021,35 @@ func (w *relationUnitsWatcher) mergeSettings(changes *multiwatcher.RelationUnits
 	if err != nil {
 		return -1, err
 	}
-	setRelationUnitChangeVersion(changes, key, node.txnRevno)
+	w.unitRevnos[w.st.docID(key)] = node.txnRevno
+	setRelationUnitChangeVersion(changes, key, node.txnRevno+w.serviceRevno)
 	return node.txnRevno, nil
 }
 
+// watchServiceSettings starts watching the counterpart service's
+// settings, which do not exist until its leader first writes them.
+func (w *relationUnitsWatcher) watchServiceSettings() error {
+	_, revno, err := readSettingsDoc(w.st, w.serviceKey)
+	if err == mgo.ErrNotFound {
+		revno = -1
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	w.setServiceRevno(revno)
+	w.st.watcher.Watch(settingsC, w.st.docID(w.serviceKey), revno, w.updates)
+	return nil
+}
+
+// setServiceRevno records the revision of the counterpart service's
+// settings. Missing settings count as revision 0, so that the versions
+// of units in relations without service settings are unaffected.
+func (w *relationUnitsWatcher) setServiceRevno(revno int64) {
+	if revno < 0 {
+		revno = 0
+	}
+	w.serviceRevno = revno
+}
+
 // mergeScope starts and stops settings watches on the units entering and
 // leaving the scope in the supplied RelationScopeChange event, and applies
 // the expressed changes to the supplied RelationUnitsChange event.
@@ -944,6 +1074,7 @@ func (w *relationUnitsWatcher) mergeScope(changes *multiwatcher.RelationUnitsCha
 		}
 		w.st.watcher.Unwatch(settingsC, docID, w.updates)
 		w.watching.Remove(docID)
+		delete(w.unitRevnos, docID)
 	}
 	return nil
 }
@@ -964,6 +1095,7 @@ func (w *relationUnitsWatcher) finish() {
 	for _, watchedValue := range w.watching.Values() {
 		w.st.watcher.Unwatch(settingsC, watchedValue, w.updates)
 	}
+	w.st.watcher.Unwatch(settingsC, w.st.docID(w.serviceKey), w.updates)
 	close(w.updates)
 	close(w.out)
 	w.tomb.Done()
@@ -975,6 +1107,9 @@ func (w *relationUnitsWatcher) loop() (err error) {
 		changes     multiwatcher.RelationUnitsChange
 		out         chan<- multiwatcher.RelationUnitsChange
 	)
+	if err := w.watchServiceSettings(); err != nil {
+		return err
+	}
 	for {
 		select {
 		case <-w.st.watcher.Dead():
@@ -998,7 +1133,17 @@ func (w *relationUnitsWatcher) loop() (err error) {
 			if !ok {
 				logger.Warningf("ignoring bad relation scope id: %#v", c.Id)
 			}
-			setRelationUnitChangeVersion(&changes, id, c.Revno)
+			if id == w.st.docID(w.serviceKey) {
+				w.setServiceRevno(c.Revno)
+				for docID, revno := range w.unitRevnos {
+					setRelationUnitChangeVersion(&changes, docID, revno+w.serviceRevno)
+				}
+			} else {
+				if w.watching.Contains(id) {
+					w.unitRevnos[id] = c.Revno
+				}
+				setRelationUnitChangeVersion(&changes, id, c.Revno+w.serviceRevno)
+			}
 			out = w.out
 		case out <- changes:
 			sentInitial = true
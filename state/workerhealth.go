@@ -0,0 +1,245 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// WorkerHealth describes the health of one of the workers run by
+// an agent.
+type WorkerHealth struct {
+	// Name identifies the worker within the agent.
+	Name string
+
+	// Running holds whether the worker is currently running.
+	Running bool
+
+	// Restarts holds the number of times the worker has been
+	// restarted since the agent started it.
+	Restarts int
+
+	// LastError holds the error the worker last exited with, if any.
+	LastError string
+
+	// LastErrorTime holds when the worker last exited with an error.
+	LastErrorTime time.Time
+
+	// Failed holds whether the worker has exited too often in
+	// quick succession. The agent keeps restarting it.
+	Failed bool
+}
+
+// AgentWorkerHealth holds the most recent report of the health of
+// an agent's workers.
+type AgentWorkerHealth struct {
+	// Agent holds the tag of the reporting agent.
+	Agent names.Tag
+
+	// Updated holds when the health of the agent's workers last
+	// changed.
+	Updated time.Time
+
+	// Reported holds when the agent last reported, whether or not
+	// anything changed.
+	Reported time.Time
+
+	// Workers holds the health of each of the agent's workers.
+	Workers []WorkerHealth
+}
+
+// workerHealthDoc stores the most recent worker health report
+// of an agent.
+type workerHealthDoc struct {
+	DocID    string                 `bson:"_id"`
+	EnvUUID  string                 `bson:"env-uuid"`
+	Agent    string                 `bson:"agent"`
+	Updated  time.Time              `bson:"updated"`
+	Reported time.Time              `bson:"reported"`
+	Workers  []workerHealthEntryDoc `bson:"workers"`
+}
+
+type workerHealthEntryDoc struct {
+	Name          string    `bson:"name"`
+	Running       bool      `bson:"running"`
+	Restarts      int       `bson:"restarts"`
+	LastError     string    `bson:"lasterror,omitempty"`
+	LastErrorTime time.Time `bson:"lasterrortime,omitempty"`
+	Failed        bool      `bson:"failed,omitempty"`
+}
+
+// SetWorkerHealth records the health of the workers of the given
+// agent, replacing any previous report. Agents report periodically;
+// if the health of their workers is unchanged only the time of the
+// report is recorded.
+func (st *State) SetWorkerHealth(agent names.Tag, workers []WorkerHealth) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set worker health for %q", agent)
+	switch agent.(type) {
+	case names.MachineTag, names.UnitTag:
+	default:
+		return errors.NotValidf("agent tag %q", agent)
+	}
+	entries := make([]workerHealthEntryDoc, len(workers))
+	for i, w := range workers {
+		entries[i] = workerHealthEntryDoc{
+			Name:          w.Name,
+			Running:       w.Running,
+			Restarts:      w.Restarts,
+			LastError:     w.LastError,
+			LastErrorTime: truncateMillis(w.LastErrorTime),
+			Failed:        w.Failed,
+		}
+	}
+	now := time.Now().UTC()
+
+	coll, closer := st.getCollection(workerHealthC)
+	defer closer()
+	id := agent.String()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var doc workerHealthDoc
+		err := coll.FindId(id).One(&doc)
+		if err == mgo.ErrNotFound {
+			return []txn.Op{{
+				C:      workerHealthC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: &workerHealthDoc{
+					Agent:    id,
+					Updated:  now,
+					Reported: now,
+					Workers:  entries,
+				},
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if sameWorkerHealth(doc.Workers, entries) {
+			return []txn.Op{{
+				C:      workerHealthC,
+				Id:     id,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"reported", now}}}},
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      workerHealthC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"updated", now},
+				{"reported", now},
+				{"workers", entries},
+			}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// removeWorkerHealthOp returns the operation needed to remove the
+// worker health report of the agent with the given tag.
+func removeWorkerHealthOp(st *State, agent names.Tag) txn.Op {
+	return txn.Op{
+		C:      workerHealthC,
+		Id:     st.docID(agent.String()),
+		Remove: true,
+	}
+}
+
+// sameWorkerHealth reports whether the two worker health reports
+// hold the same workers in the same states.
+func sameWorkerHealth(a, b []workerHealthEntryDoc) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		if x.Name != y.Name ||
+			x.Running != y.Running ||
+			x.Restarts != y.Restarts ||
+			x.LastError != y.LastError ||
+			x.Failed != y.Failed ||
+			!x.LastErrorTime.Equal(y.LastErrorTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// truncateMillis returns t in UTC, truncated to the millisecond
+// precision that mongo stores, so that it compares equal to the
+// time read back.
+func truncateMillis(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC().Truncate(time.Millisecond)
+}
+
+// WorkerHealth returns the most recent worker health report of the
+// given agent. It returns an error satisfying errors.IsNotFound if the
+// agent has not reported.
+func (st *State) WorkerHealth(agent names.Tag) (*AgentWorkerHealth, error) {
+	coll, closer := st.getCollection(workerHealthC)
+	defer closer()
+
+	var doc workerHealthDoc
+	err := coll.FindId(agent.String()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("worker health for %q", agent)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get worker health for %q", agent)
+	}
+	return doc.health()
+}
+
+// AllWorkerHealth returns the most recent worker health report of
+// each agent in the environment that has reported.
+func (st *State) AllWorkerHealth() ([]*AgentWorkerHealth, error) {
+	coll, closer := st.getCollection(workerHealthC)
+	defer closer()
+
+	var docs []workerHealthDoc
+	if err := coll.Find(nil).Sort("agent").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get worker health")
+	}
+	result := make([]*AgentWorkerHealth, len(docs))
+	for i, doc := range docs {
+		health, err := doc.health()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[i] = health
+	}
+	return result, nil
+}
+
+func (doc *workerHealthDoc) health() (*AgentWorkerHealth, error) {
+	agent, err := names.ParseTag(doc.Agent)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	workers := make([]WorkerHealth, len(doc.Workers))
+	for i, w := range doc.Workers {
+		workers[i] = WorkerHealth{
+			Name:          w.Name,
+			Running:       w.Running,
+			Restarts:      w.Restarts,
+			LastError:     w.LastError,
+			LastErrorTime: w.LastErrorTime,
+			Failed:        w.Failed,
+		}
+	}
+	return &AgentWorkerHealth{
+		Agent:    agent,
+		Updated:  doc.Updated,
+		Reported: doc.Reported,
+		Workers:  workers,
+	}, nil
+}
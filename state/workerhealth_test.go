@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type WorkerHealthSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&WorkerHealthSuite{})
+
+func (s *WorkerHealthSuite) TestWorkerHealthNotFound(c *gc.C) {
+	_, err := s.State.WorkerHealth(names.NewMachineTag("0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `worker health for "machine-0" not found`)
+}
+
+func (s *WorkerHealthSuite) TestSetWorkerHealth(c *gc.C) {
+	failedAt := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	workers := []state.WorkerHealth{{
+		Name:    "machiner",
+		Running: true,
+	}, {
+		Name:          "addresser",
+		Restarts:      4,
+		LastError:     "authentication failed",
+		LastErrorTime: failedAt,
+		Failed:        true,
+	}}
+	machine := names.NewMachineTag("0")
+	err := s.State.SetWorkerHealth(machine, workers)
+	c.Assert(err, jc.ErrorIsNil)
+
+	health, err := s.State.WorkerHealth(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Agent, gc.Equals, machine)
+	c.Assert(health.Updated.IsZero(), jc.IsFalse)
+	c.Assert(health.Reported.Equal(health.Updated), jc.IsTrue)
+	c.Assert(health.Workers, gc.HasLen, 2)
+	c.Assert(health.Workers[0], jc.DeepEquals, workers[0])
+	c.Assert(health.Workers[1].LastErrorTime.Equal(failedAt), jc.IsTrue)
+	health.Workers[1].LastErrorTime = failedAt
+	c.Assert(health.Workers[1], jc.DeepEquals, workers[1])
+
+	// A new report replaces the previous one.
+	err = s.State.SetWorkerHealth(machine, workers[:1])
+	c.Assert(err, jc.ErrorIsNil)
+	health, err = s.State.WorkerHealth(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Workers, jc.DeepEquals, workers[:1])
+}
+
+func (s *WorkerHealthSuite) TestSetWorkerHealthInvalidAgent(c *gc.C) {
+	err := s.State.SetWorkerHealth(names.NewServiceTag("mysql"), nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set worker health for "service-mysql": agent tag "service-mysql" not valid`)
+}
+
+func (s *WorkerHealthSuite) TestAllWorkerHealth(c *gc.C) {
+	all, err := s.State.AllWorkerHealth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+
+	for _, tag := range []names.Tag{names.NewUnitTag("mysql/0"), names.NewMachineTag("1")} {
+		err := s.State.SetWorkerHealth(tag, []state.WorkerHealth{{Name: "w", Running: true}})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	all, err = s.State.AllWorkerHealth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 2)
+	c.Assert(all[0].Agent, gc.Equals, names.NewMachineTag("1"))
+	c.Assert(all[1].Agent, gc.Equals, names.NewUnitTag("mysql/0"))
+}
+
+func (s *WorkerHealthSuite) TestSetWorkerHealthUnchanged(c *gc.C) {
+	workers := []state.WorkerHealth{{
+		Name:          "addresser",
+		Restarts:      1,
+		LastError:     "authentication failed",
+		LastErrorTime: time.Date(2015, 6, 1, 12, 0, 0, 123456789, time.UTC),
+	}}
+	machine := names.NewMachineTag("0")
+	err := s.State.SetWorkerHealth(machine, workers)
+	c.Assert(err, jc.ErrorIsNil)
+	health, err := s.State.WorkerHealth(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	updated := health.Updated
+
+	// Reporting the same health again records only the time
+	// of the report.
+	time.Sleep(10 * time.Millisecond)
+	err = s.State.SetWorkerHealth(machine, workers)
+	c.Assert(err, jc.ErrorIsNil)
+	health, err = s.State.WorkerHealth(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Updated.Equal(updated), jc.IsTrue)
+	c.Assert(health.Reported.After(updated), jc.IsTrue)
+}
+
+func (s *WorkerHealthSuite) TestRemovingMachineRemovesWorkerHealth(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetWorkerHealth(machine.Tag(), []state.WorkerHealth{{Name: "w", Running: true}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.WorkerHealth(machine.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
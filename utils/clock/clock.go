@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package clock provides an abstraction of the passage of time, so
+// that code that waits or retries can be tested without waiting for
+// real time to pass.
+package clock
+
+import (
+	"time"
+)
+
+// Clock provides access to the current time and to timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the
+	// current time on the returned channel.
+	After(time.Duration) <-chan time.Time
+}
+
+// WallClock is a Clock that uses the system's real time.
+var WallClock Clock = wallClock{}
+
+type wallClock struct{}
+
+// Now is part of the Clock interface.
+func (wallClock) Now() time.Time {
+	return time.Now()
+}
+
+// After is part of the Clock interface.
+func (wallClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
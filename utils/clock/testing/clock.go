@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/juju/utils/clock"
+)
+
+// Clock is a clock.Clock whose time only changes when Advance is
+// called, so that tests can control exactly when timers fire.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	alarms []alarm
+	notify chan struct{}
+}
+
+var _ clock.Clock = (*Clock)(nil)
+
+type alarm struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewClock returns a new Clock whose current time is now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{
+		now:    now,
+		notify: make(chan struct{}, 1024),
+	}
+}
+
+// Now is part of the clock.Clock interface.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After is part of the clock.Clock interface. The returned channel
+// receives a value once the clock has been advanced by at least d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+	} else {
+		c.alarms = append(c.alarms, alarm{c.now.Add(d), ch})
+		sort.Sort(byDeadline(c.alarms))
+	}
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+	return ch
+}
+
+// Advance moves the clock forward by d, firing any timers that
+// become due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for len(c.alarms) > 0 && !c.alarms[0].deadline.After(c.now) {
+		c.alarms[0].c <- c.now
+		c.alarms = c.alarms[1:]
+	}
+}
+
+// Alarms returns a channel that receives a value every time After is
+// called, so that tests can wait for code to start waiting before
+// advancing the clock.
+func (c *Clock) Alarms() <-chan struct{} {
+	return c.notify
+}
+
+type byDeadline []alarm
+
+func (a byDeadline) Len() int           { return len(a) }
+func (a byDeadline) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byDeadline) Less(i, j int) bool { return a[i].deadline.Before(a[j].deadline) }
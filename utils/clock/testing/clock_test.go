@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	clocktesting "github.com/juju/juju/utils/clock/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type clockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clockSuite{})
+
+var epoch = time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func (s *clockSuite) TestNow(c *gc.C) {
+	clock := clocktesting.NewClock(epoch)
+	c.Assert(clock.Now(), gc.Equals, epoch)
+	clock.Advance(time.Minute)
+	c.Assert(clock.Now(), gc.Equals, epoch.Add(time.Minute))
+}
+
+func (s *clockSuite) TestAfter(c *gc.C) {
+	clock := clocktesting.NewClock(epoch)
+	second := clock.After(2 * time.Second)
+	first := clock.After(time.Second)
+	assertAlarms(c, clock, 2)
+
+	clock.Advance(500 * time.Millisecond)
+	assertNotFired(c, first)
+	assertNotFired(c, second)
+
+	clock.Advance(500 * time.Millisecond)
+	assertFired(c, first, epoch.Add(time.Second))
+	assertNotFired(c, second)
+
+	clock.Advance(5 * time.Second)
+	assertFired(c, second, epoch.Add(6*time.Second))
+}
+
+func (s *clockSuite) TestAfterZero(c *gc.C) {
+	clock := clocktesting.NewClock(epoch)
+	assertFired(c, clock.After(0), epoch)
+}
+
+func assertAlarms(c *gc.C, clock *clocktesting.Clock, n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-clock.Alarms():
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for alarm %d", i)
+		}
+	}
+}
+
+func assertFired(c *gc.C, ch <-chan time.Time, expect time.Time) {
+	select {
+	case t := <-ch:
+		c.Assert(t, gc.Equals, expect)
+	default:
+		c.Fatalf("timer did not fire")
+	}
+}
+
+func assertNotFired(c *gc.C, ch <-chan time.Time) {
+	select {
+	case <-ch:
+		c.Fatalf("timer fired unexpectedly")
+	default:
+	}
+}
@@ -36,6 +36,9 @@ type Options struct {
 	// knownHostsFile is a path to a file in which to save the host's
 	// fingerprint.
 	knownHostsFile string
+	// strictHostKeyChecking requires the host's key to be
+	// listed in the known hosts file; it is disabled by default.
+	strictHostKeyChecking bool
 }
 
 // SetProxyCommand sets a command to execute to proxy traffic through.
@@ -63,6 +66,13 @@ func (o *Options) SetKnownHostsFile(file string) {
 	o.knownHostsFile = file
 }
 
+// EnableStrictHostKeyChecking refuses connections to hosts whose
+// keys are not listed in the known hosts file, rather than accepting
+// and saving unknown keys.
+func (o *Options) EnableStrictHostKeyChecking() {
+	o.strictHostKeyChecking = true
+}
+
 // AllowPasswordAuthentication allows the SSH
 // client to prompt the user for a password.
 //
@@ -14,8 +14,6 @@ import (
 	"github.com/juju/utils"
 )
 
-var opensshCommonOptions = []string{"-o", "StrictHostKeyChecking no"}
-
 // default identities will not be attempted if
 // -i is specified and they are not explcitly
 // included.
@@ -64,10 +62,13 @@ func NewOpenSSHClient() (*OpenSSHClient, error) {
 }
 
 func opensshOptions(options *Options, commandKind opensshCommandKind) []string {
-	args := append([]string{}, opensshCommonOptions...)
 	if options == nil {
 		options = &Options{}
 	}
+	args := []string{"-o", "StrictHostKeyChecking no"}
+	if options.strictHostKeyChecking {
+		args = []string{"-o", "StrictHostKeyChecking yes"}
+	}
 	if len(options.proxyCommand) > 0 {
 		args = append(args, "-o", "ProxyCommand "+utils.CommandString(options.proxyCommand...))
 	}
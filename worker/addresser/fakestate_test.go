@@ -0,0 +1,199 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package addresser_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
+	coretesting "github.com/juju/juju/testing"
+	clocktesting "github.com/juju/juju/utils/clock/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/addresser"
+	addressertesting "github.com/juju/juju/worker/addresser/testing"
+)
+
+// fakeStateSuite tests the worker against an in-memory state, so
+// unlike workerSuite it does not need a running mongod.
+type fakeStateSuite struct {
+	coretesting.BaseSuite
+	st       *addressertesting.FakeState
+	releaser *fakeReleaser
+	clock    *clocktesting.Clock
+}
+
+var _ = gc.Suite(&fakeStateSuite{})
+
+func (s *fakeStateSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = addressertesting.NewFakeState(coretesting.EnvironConfig(c))
+	s.releaser = &fakeReleaser{released: make(chan releaseCall, 10)}
+	s.clock = clocktesting.NewClock(time.Now())
+
+	s.st.AddMachine("0", "i-0")
+	s.st.AddIPAddress("0.1.2.3", "foobar", "0", params.Alive)
+	s.st.AddIPAddress("0.1.2.4", "foobar", "0", params.Dead)
+	s.st.AddIPAddress("0.1.2.5", "foobar", "dead-machine", params.Dead)
+}
+
+func (s *fakeStateSuite) startWorker(c *gc.C) worker.Worker {
+	w := addresser.NewWorkerWithReleaser(s.st, s.releaser, s.clock)
+	s.AddCleanup(func(*gc.C) { worker.Stop(w) })
+	return w
+}
+
+func (s *fakeStateSuite) waitForRelease(c *gc.C) releaseCall {
+	select {
+	case call := <-s.releaser.released:
+		return call
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timeout waiting for address release")
+	}
+	panic("unreachable")
+}
+
+func (s *fakeStateSuite) waitForAddresses(c *gc.C, expected ...string) {
+	for a := common.ShortAttempt.Start(); a.Next(); {
+		values := s.st.IPAddresses()
+		if len(values) == len(expected) {
+			c.Assert(values, jc.SameContents, expected)
+			return
+		}
+		if !a.HasNext() {
+			c.Fatalf("timeout waiting for addresses %v (have %v)", expected, values)
+		}
+	}
+}
+
+func (s *fakeStateSuite) TestReleasesAlreadyDead(c *gc.C) {
+	s.startWorker(c)
+
+	calls := []releaseCall{s.waitForRelease(c), s.waitForRelease(c)}
+	c.Assert(calls, jc.SameContents, []releaseCall{{
+		instId:   "i-0",
+		subnetId: "foobar",
+		addr:     network.NewAddress("0.1.2.4"),
+	}, {
+		// The machine does not exist, so ReleaseAddress should be
+		// called with instance.UnknownId.
+		instId:   instance.UnknownId,
+		subnetId: "foobar",
+		addr:     network.NewAddress("0.1.2.5"),
+	}})
+	s.waitForAddresses(c, "0.1.2.3")
+}
+
+func (s *fakeStateSuite) TestReleasesNewlyDead(c *gc.C) {
+	s.startWorker(c)
+	s.waitForAddresses(c, "0.1.2.3")
+
+	addr, err := s.st.IPAddress("0.1.2.3")
+	c.Assert(err, jc.ErrorIsNil)
+	addr.(*addressertesting.FakeIPAddress).EnsureDead()
+
+	// Skip the releases of the initially dead addresses.
+	s.waitForRelease(c)
+	s.waitForRelease(c)
+	call := s.waitForRelease(c)
+	c.Assert(call, jc.DeepEquals, releaseCall{
+		instId:   "i-0",
+		subnetId: "foobar",
+		addr:     network.NewAddress("0.1.2.3"),
+	})
+	s.waitForAddresses(c)
+}
+
+func (s *fakeStateSuite) TestReleaseErrorKillsWorker(c *gc.C) {
+	s.releaser.err = errors.New("boom")
+	w := s.startWorker(c)
+
+	stopErr := make(chan error)
+	go func() {
+		w.Wait()
+		stopErr <- worker.Stop(w)
+	}()
+	// The release is retried until common.ShortAttempt.Total has
+	// passed, without waiting for real time to pass.
+	for {
+		select {
+		case err := <-stopErr:
+			c.Assert(err, gc.ErrorMatches, "failed to release address .*: boom")
+			c.Assert(s.releaser.Calls(), gc.Equals, 1+int(common.ShortAttempt.Total/common.ShortAttempt.Delay))
+			// Addresses that could not be released must not be removed.
+			c.Assert(s.st.IPAddresses(), gc.DeepEquals, []string{"0.1.2.3", "0.1.2.4", "0.1.2.5"})
+			return
+		case <-s.clock.Alarms():
+			s.clock.Advance(common.ShortAttempt.Delay)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("worker did not stop as expected")
+		}
+	}
+}
+
+func (s *fakeStateSuite) TestDryRunDoesNotRelease(c *gc.C) {
+	cfg, err := coretesting.EnvironConfig(c).Apply(map[string]interface{}{
+		"dry-run": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	releaser := &configReleaser{fakeReleaser: s.releaser, config: cfg}
+	w := addresser.NewWorkerWithReleaser(s.st, releaser, s.clock)
+	defer worker.Stop(w)
+
+	// The dead addresses are neither released nor removed, as
+	// they are still allocated by the provider.
+	time.Sleep(coretesting.ShortWait)
+	c.Assert(s.releaser.Calls(), gc.Equals, 0)
+	c.Assert(s.st.IPAddresses(), gc.DeepEquals, []string{"0.1.2.3", "0.1.2.4", "0.1.2.5"})
+}
+
+type releaseCall struct {
+	instId   instance.Id
+	subnetId network.Id
+	addr     network.Address
+}
+
+type fakeReleaser struct {
+	mu       sync.Mutex
+	calls    int
+	err      error
+	released chan releaseCall
+}
+
+// Calls returns the number of times ReleaseAddress has been called.
+func (r *fakeReleaser) Calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func (r *fakeReleaser) ReleaseAddress(instId instance.Id, subnetId network.Id, addr network.Address) error {
+	r.mu.Lock()
+	r.calls++
+	r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.released <- releaseCall{instId, subnetId, addr}
+	return nil
+}
+
+// configReleaser is a fakeReleaser that exposes an environment
+// config, like the environ used by the real worker.
+type configReleaser struct {
+	*fakeReleaser
+	config *config.Config
+}
+
+func (r *configReleaser) Config() *config.Config {
+	return r.config
+}
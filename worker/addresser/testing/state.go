@@ -0,0 +1,277 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package testing provides an in-memory implementation of the state
+// interfaces used by the addresser worker, so its behaviour can be
+// tested without a running mongod.
+package testing
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/worker/stateadapter"
+)
+
+// FakeState is an in-memory implementation of stateadapter.Addresser.
+// It is safe for concurrent use.
+type FakeState struct {
+	mu        sync.Mutex
+	config    *config.Config
+	addresses map[string]*FakeIPAddress
+	machines  map[string]*FakeMachine
+	watchers  []*fakeStringsWatcher
+}
+
+var _ stateadapter.Addresser = (*FakeState)(nil)
+
+// NewFakeState returns a FakeState with no addresses or machines, whose
+// EnvironConfig method returns the given config.
+func NewFakeState(cfg *config.Config) *FakeState {
+	return &FakeState{
+		config:    cfg,
+		addresses: make(map[string]*FakeIPAddress),
+		machines:  make(map[string]*FakeMachine),
+	}
+}
+
+// AddIPAddress adds an IP address with the given value, subnet and
+// allocated machine, and notifies any watchers.
+func (st *FakeState) AddIPAddress(value, subnetId, machineId string, life params.Life) *FakeIPAddress {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	addr := &FakeIPAddress{
+		st:        st,
+		value:     value,
+		subnetId:  subnetId,
+		machineId: machineId,
+		life:      life,
+	}
+	st.addresses[value] = addr
+	st.notify(value)
+	return addr
+}
+
+// AddMachine adds a machine with the given id and instance id.
+func (st *FakeState) AddMachine(id string, instId instance.Id) *FakeMachine {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m := &FakeMachine{instanceId: instId}
+	st.machines[id] = m
+	return m
+}
+
+// IPAddresses returns the values of all the addresses in the state,
+// sorted.
+func (st *FakeState) IPAddresses() []string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	values := make([]string, 0, len(st.addresses))
+	for value := range st.addresses {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// EnvironConfig is part of stateadapter.Addresser.
+func (st *FakeState) EnvironConfig() (*config.Config, error) {
+	return st.config, nil
+}
+
+// IPAddress is part of stateadapter.Addresser.
+func (st *FakeState) IPAddress(value string) (stateadapter.IPAddress, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	addr, ok := st.addresses[value]
+	if !ok {
+		return nil, errors.NotFoundf("IP address %q", value)
+	}
+	return addr, nil
+}
+
+// Machine is part of stateadapter.Addresser.
+func (st *FakeState) Machine(id string) (stateadapter.Machine, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m, ok := st.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	return m, nil
+}
+
+// WatchIPAddresses is part of stateadapter.Addresser. As with the
+// real state, the initial event holds the values of all addresses.
+func (st *FakeState) WatchIPAddresses() (watcher.StringsWatcher, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var initial []string
+	for value := range st.addresses {
+		initial = append(initial, value)
+	}
+	w := newFakeStringsWatcher(initial)
+	st.watchers = append(st.watchers, w)
+	return w, nil
+}
+
+// notify sends the given value to all watchers. It must be called
+// with st.mu held.
+func (st *FakeState) notify(value string) {
+	for _, w := range st.watchers {
+		w.send(value)
+	}
+}
+
+// FakeIPAddress is an in-memory implementation of
+// stateadapter.IPAddress.
+type FakeIPAddress struct {
+	st        *FakeState
+	value     string
+	subnetId  string
+	machineId string
+	life      params.Life
+}
+
+// Value is part of stateadapter.IPAddress.
+func (a *FakeIPAddress) Value() string {
+	return a.value
+}
+
+// Life is part of stateadapter.IPAddress.
+func (a *FakeIPAddress) Life() params.Life {
+	a.st.mu.Lock()
+	defer a.st.mu.Unlock()
+	return a.life
+}
+
+// MachineId is part of stateadapter.IPAddress.
+func (a *FakeIPAddress) MachineId() string {
+	return a.machineId
+}
+
+// SubnetId is part of stateadapter.IPAddress.
+func (a *FakeIPAddress) SubnetId() string {
+	return a.subnetId
+}
+
+// Address is part of stateadapter.IPAddress.
+func (a *FakeIPAddress) Address() network.Address {
+	return network.NewAddress(a.value)
+}
+
+// EnsureDead sets the address's life to Dead and notifies any
+// watchers.
+func (a *FakeIPAddress) EnsureDead() {
+	a.st.mu.Lock()
+	defer a.st.mu.Unlock()
+	a.life = params.Dead
+	a.st.notify(a.value)
+}
+
+// Remove is part of stateadapter.IPAddress. As with the real state,
+// only Dead addresses may be removed.
+func (a *FakeIPAddress) Remove() error {
+	a.st.mu.Lock()
+	defer a.st.mu.Unlock()
+	if a.life != params.Dead {
+		return errors.Errorf("cannot remove IP address %q: IP address is not dead", a.value)
+	}
+	if _, ok := a.st.addresses[a.value]; ok {
+		delete(a.st.addresses, a.value)
+		a.st.notify(a.value)
+	}
+	return nil
+}
+
+// FakeMachine is an in-memory implementation of stateadapter.Machine.
+type FakeMachine struct {
+	instanceId instance.Id
+}
+
+// InstanceId is part of stateadapter.Machine.
+func (m *FakeMachine) InstanceId() (instance.Id, error) {
+	return m.instanceId, nil
+}
+
+// fakeStringsWatcher implements state.StringsWatcher, coalescing
+// changes until they are read like the real watchers do.
+type fakeStringsWatcher struct {
+	tomb    tomb.Tomb
+	in      chan string
+	changes chan []string
+}
+
+func newFakeStringsWatcher(initial []string) *fakeStringsWatcher {
+	w := &fakeStringsWatcher{
+		in:      make(chan string),
+		changes: make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.changes)
+		w.tomb.Kill(w.loop(initial))
+	}()
+	return w
+}
+
+func (w *fakeStringsWatcher) loop(pending []string) error {
+	// The initial event is always sent, even when empty.
+	out := w.changes
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case value := <-w.in:
+			pending = append(pending, value)
+			out = w.changes
+		case out <- pending:
+			pending = nil
+			out = nil
+		}
+	}
+}
+
+// send queues a change, unless the watcher has been stopped.
+func (w *fakeStringsWatcher) send(value string) {
+	go func() {
+		select {
+		case w.in <- value:
+		case <-w.tomb.Dying():
+		}
+	}()
+}
+
+// Changes is part of state.StringsWatcher.
+func (w *fakeStringsWatcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Kill is part of state.Watcher.
+func (w *fakeStringsWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of state.Watcher.
+func (w *fakeStringsWatcher) Wait() error {
+	return w.tomb.Wait()
+}
+
+// Stop is part of state.Watcher.
+func (w *fakeStringsWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+// Err is part of state.Watcher.
+func (w *fakeStringsWatcher) Err() error {
+	return w.tomb.Err()
+}
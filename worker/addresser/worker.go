@@ -8,13 +8,15 @@ import (
 	"github.com/juju/loggo"
 
 	apiWatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
-	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/stateadapter"
 )
 
 var logger = loggo.GetLogger("juju.worker.addresser")
@@ -25,23 +27,27 @@ type releaser interface {
 	ReleaseAddress(instance.Id, network.Id, network.Address) error
 }
 
-// stateAddresser defines the State methods used by the addresserHandler
-type stateAddresser interface {
-	DeadIPAddresses() ([]*state.IPAddress, error)
-	EnvironConfig() (*config.Config, error)
-	IPAddress(string) (*state.IPAddress, error)
-	Machine(string) (*state.Machine, error)
-	WatchIPAddresses() state.StringsWatcher
-}
-
 type addresserHandler struct {
-	st       stateAddresser
+	st       stateadapter.Addresser
 	releaser releaser
+
+	// clock is used to wait between attempts to release an address.
+	clock clock.Clock
+
+	// environ is the environ used to release addresses, if it was
+	// created by the worker. It is kept up to date with the
+	// environment config so that rotated credentials are used.
+	environ environs.Environ
 }
 
 // NewWorker returns a worker that keeps track of
 // IP address lifecycles, releaseing and removing Dead addresses.
-func NewWorker(st stateAddresser) (worker.Worker, error) {
+// The given adapter may be backed by state or by the API.
+func NewWorker(st stateadapter.Addresser) (worker.Worker, error) {
+	return newWorker(st, clock.WallClock)
+}
+
+func newWorker(st stateadapter.Addresser, clock clock.Clock) (worker.Worker, error) {
 	config, err := st.EnvironConfig()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -50,17 +56,27 @@ func NewWorker(st stateAddresser) (worker.Worker, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	// If netEnviron is nil the worker will start but won't do anything as
-	// no IP addresses will be created or destroyed.
-	netEnviron, _ := environs.SupportsNetworking(environ)
-	a := newWorkerWithReleaser(st, netEnviron)
-	return a, nil
+	// If the environ does not support networking the worker will start
+	// but won't do anything as no IP addresses will be created or
+	// destroyed.
+	netEnviron, ok := environs.SupportsNetworking(environ)
+	if !ok {
+		return newWorkerWithReleaser(st, nil, clock), nil
+	}
+	a := &addresserHandler{
+		st:       st,
+		releaser: netEnviron,
+		clock:    clock,
+		environ:  netEnviron,
+	}
+	return worker.NewStringsWorker(a), nil
 }
 
-func newWorkerWithReleaser(st stateAddresser, releaser releaser) worker.Worker {
+func newWorkerWithReleaser(st stateadapter.Addresser, releaser releaser, clock clock.Clock) worker.Worker {
 	a := &addresserHandler{
 		st:       st,
 		releaser: releaser,
+		clock:    clock,
 	}
 	w := worker.NewStringsWorker(a)
 	return w
@@ -71,6 +87,9 @@ func (a *addresserHandler) Handle(ids []string) error {
 	if a.releaser == nil {
 		return nil
 	}
+	if err := a.refreshEnviron(); err != nil {
+		return err
+	}
 	for _, id := range ids {
 		logger.Debugf("received notification about address %v", id)
 		addr, err := a.st.IPAddress(id)
@@ -81,10 +100,16 @@ func (a *addresserHandler) Handle(ids []string) error {
 			}
 			return err
 		}
-		if addr.Life() != state.Dead {
+		if addr.Life() != params.Dead {
 			logger.Debugf("address %v is not Dead (life %q); skipping", id, addr.Life())
 			continue
 		}
+		if environs.IsDryRun(a.releaser) {
+			// The address is still allocated by the provider,
+			// so it stays in state until it can be released.
+			logger.Infof("dry run: would release address %q", addr.Value())
+			continue
+		}
 		err = a.releaseIPAddress(addr)
 		if err != nil {
 			return err
@@ -99,9 +124,23 @@ func (a *addresserHandler) Handle(ids []string) error {
 	return nil
 }
 
-func (a *addresserHandler) releaseIPAddress(addr *state.IPAddress) (err error) {
+// refreshEnviron updates the worker's environ with the current
+// environment config, so that changes such as rotated provider
+// credentials take effect without restarting the worker.
+func (a *addresserHandler) refreshEnviron() error {
+	if a.environ == nil {
+		return nil
+	}
+	cfg, err := a.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Annotate(a.environ.SetConfig(cfg), "cannot update environ config")
+}
+
+func (a *addresserHandler) releaseIPAddress(addr stateadapter.IPAddress) (err error) {
 	defer errors.DeferredAnnotatef(&err, "failed to release address %v", addr.Value())
-	var machine *state.Machine
+	var machine stateadapter.Machine
 	logger.Debugf("attempting to release dead address %#v", addr.Value())
 
 	var instId instance.Id
@@ -118,11 +157,16 @@ func (a *addresserHandler) releaseIPAddress(addr *state.IPAddress) (err error) {
 	}
 
 	subnetId := network.Id(addr.SubnetId())
-	for attempt := common.ShortAttempt.Start(); attempt.Next(); {
+	deadline := a.clock.Now().Add(common.ShortAttempt.Total)
+	for {
 		err = a.releaser.ReleaseAddress(instId, subnetId, addr.Address())
 		if err == nil {
 			return nil
 		}
+		if !a.clock.Now().Before(deadline) {
+			break
+		}
+		<-a.clock.After(common.ShortAttempt.Delay)
 	}
 	// Don't remove the address from state so we
 	// can retry releasing the address later.
@@ -132,7 +176,7 @@ func (a *addresserHandler) releaseIPAddress(addr *state.IPAddress) (err error) {
 
 // SetUp is part of the StringsWorker interface.
 func (a *addresserHandler) SetUp() (apiWatcher.StringsWatcher, error) {
-	return a.st.WatchIPAddresses(), nil
+	return a.st.WatchIPAddresses()
 }
 
 // TearDown is part of the StringsWorker interface.
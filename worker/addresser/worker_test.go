@@ -19,8 +19,10 @@ import (
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/addresser"
+	"github.com/juju/juju/worker/stateadapter"
 )
 
 var _ = gc.Suite(&workerSuite{})
@@ -108,7 +110,7 @@ func (s *workerSuite) TestWorkerReleasesAlreadyDead(c *gc.C) {
 
 	opsChan := dummyListen()
 
-	w, err := addresser.NewWorker(s.State)
+	w, err := addresser.NewWorker(stateadapter.NewStateAddresser(s.State))
 	c.Assert(err, jc.ErrorIsNil)
 	defer s.assertStop(c, w)
 	s.waitForInitialDead(c)
@@ -138,7 +140,7 @@ func (s *workerSuite) waitForInitialDead(c *gc.C) {
 }
 
 func (s *workerSuite) TestWorkerIgnoresAliveAddresses(c *gc.C) {
-	w, err := addresser.NewWorker(s.State)
+	w, err := addresser.NewWorker(stateadapter.NewStateAddresser(s.State))
 	c.Assert(err, jc.ErrorIsNil)
 	defer s.assertStop(c, w)
 	s.waitForInitialDead(c)
@@ -159,7 +161,7 @@ func (s *workerSuite) TestWorkerIgnoresAliveAddresses(c *gc.C) {
 }
 
 func (s *workerSuite) TestWorkerRemovesDeadAddress(c *gc.C) {
-	w, err := addresser.NewWorker(s.State)
+	w, err := addresser.NewWorker(stateadapter.NewStateAddresser(s.State))
 	c.Assert(err, jc.ErrorIsNil)
 	defer s.assertStop(c, w)
 	s.waitForInitialDead(c)
@@ -188,7 +190,7 @@ func (s *workerSuite) TestWorkerRemovesDeadAddress(c *gc.C) {
 
 func (s *workerSuite) TestErrorKillsWorker(c *gc.C) {
 	s.AssertConfigParameterUpdated(c, "broken", "ReleaseAddress")
-	w, err := addresser.NewWorker(s.State)
+	w, err := addresser.NewWorker(stateadapter.NewStateAddresser(s.State))
 	c.Assert(err, jc.ErrorIsNil)
 	defer worker.Stop(w)
 
@@ -217,9 +219,36 @@ func (s *workerSuite) TestErrorKillsWorker(c *gc.C) {
 	}
 }
 
+func (s *workerSuite) TestWorkerRefreshesEnvironConfig(c *gc.C) {
+	w, err := addresser.NewWorker(stateadapter.NewStateAddresser(s.State))
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+	s.waitForInitialDead(c)
+
+	// Once SetConfig is broken, the next address change should fail
+	// because the worker tries to update its environ first.
+	s.AssertConfigParameterUpdated(c, "broken", "SetConfig")
+	addr, err := s.State.IPAddress("0.1.2.3")
+	c.Assert(err, jc.ErrorIsNil)
+	err = addr.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	stopErr := make(chan error)
+	go func() {
+		w.Wait()
+		stopErr <- worker.Stop(w)
+	}()
+	select {
+	case err := <-stopErr:
+		c.Assert(err, gc.ErrorMatches, "cannot update environ config: dummy.SetConfig is broken")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker did not stop as expected")
+	}
+}
+
 func (s *workerSuite) TestAddresserWithNoNetworkingEnviron(c *gc.C) {
 	opsChan := dummyListen()
-	w := addresser.NewWorkerWithReleaser(s.State, nil)
+	w := addresser.NewWorkerWithReleaser(stateadapter.NewStateAddresser(s.State), nil, clock.WallClock)
 	defer s.assertStop(c, w)
 
 	for {
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiaddressupdater
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/network"
+)
+
+const (
+	hostsBeginMarker = "# Begin juju API servers (managed by juju, do not edit)"
+	hostsEndMarker   = "# End juju API servers"
+)
+
+// HostsFile is an APIAddressSetter that maps the host names of the API
+// servers to their IP addresses in a section of a hosts file, so that
+// agents can still reach the API servers when the cloud's DNS fails.
+type HostsFile struct {
+	path string
+}
+
+// NewHostsFile returns a HostsFile that manages a section of the
+// hosts file at the given path.
+func NewHostsFile(path string) *HostsFile {
+	return &HostsFile{path: path}
+}
+
+// SetAPIHostPorts is part of the APIAddressSetter interface.
+func (h *HostsFile) SetAPIHostPorts(servers [][]network.HostPort) error {
+	data, err := ioutil.ReadFile(h.path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Annotate(err, "cannot read hosts file")
+	}
+	newData := replaceHostsSection(string(data), hostsEntries(servers))
+	if newData == string(data) {
+		return nil
+	}
+	if err := utils.AtomicWriteFile(h.path, []byte(newData), 0644); err != nil {
+		return errors.Annotate(err, "cannot write hosts file")
+	}
+	return nil
+}
+
+// hostsEntries returns a hosts file line for every API server with at
+// least one host name and one IP address, mapping the server's host
+// names to its best internal IP address.
+func hostsEntries(servers [][]network.HostPort) []string {
+	var entries []string
+	for _, hostPorts := range servers {
+		var names []string
+		var ips []network.Address
+		seen := make(map[string]bool)
+		for _, addr := range network.HostsWithoutPort(hostPorts) {
+			if seen[addr.Value] || addr.Value == "localhost" || addr.Scope == network.ScopeMachineLocal {
+				continue
+			}
+			seen[addr.Value] = true
+			if addr.Type == network.HostName {
+				names = append(names, addr.Value)
+			} else {
+				ips = append(ips, addr)
+			}
+		}
+		if len(names) == 0 || len(ips) == 0 {
+			continue
+		}
+		ip := network.SelectInternalAddress(ips, false)
+		if ip == "" {
+			ip = network.SelectPublicAddress(ips)
+		}
+		if ip == "" {
+			continue
+		}
+		entries = append(entries, fmt.Sprintf("%s %s", ip, strings.Join(names, " ")))
+	}
+	return entries
+}
+
+// replaceHostsSection returns the given hosts file contents with the
+// juju-managed section replaced by the given entries. The section is
+// removed entirely if there are no entries.
+func replaceHostsSection(data string, entries []string) string {
+	var lines []string
+	if data != "" {
+		inSection := false
+		for _, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+			switch {
+			case line == hostsBeginMarker:
+				inSection = true
+			case line == hostsEndMarker && inSection:
+				inSection = false
+			case !inSection:
+				lines = append(lines, line)
+			}
+		}
+	}
+	if len(entries) > 0 {
+		lines = append(lines, hostsBeginMarker)
+		lines = append(lines, entries...)
+		lines = append(lines, hostsEndMarker)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
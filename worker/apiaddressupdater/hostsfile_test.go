@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiaddressupdater_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/apiaddressupdater"
+)
+
+type HostsFileSuite struct {
+	coretesting.BaseSuite
+	path string
+}
+
+var _ = gc.Suite(&HostsFileSuite{})
+
+func (s *HostsFileSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.path = filepath.Join(c.MkDir(), "hosts")
+}
+
+func (s *HostsFileSuite) writeHosts(c *gc.C, data string) {
+	err := ioutil.WriteFile(s.path, []byte(data), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *HostsFileSuite) assertHosts(c *gc.C, expect string) {
+	data, err := ioutil.ReadFile(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, expect)
+}
+
+var apiServers = [][]network.HostPort{
+	network.NewHostPorts(17070, "ec2-1-2-3-4.example.com", "10.0.0.1", "1.2.3.4"),
+	network.NewHostPorts(17070, "10.0.0.2"),
+	network.NewHostPorts(17070, "ip-10-0-0-3.internal", "localhost", "127.0.0.1", "10.0.0.3"),
+}
+
+const localHosts = "127.0.0.1 localhost\n::1 ip6-localhost\n"
+
+func (s *HostsFileSuite) TestSetAPIHostPorts(c *gc.C) {
+	s.writeHosts(c, localHosts)
+	hosts := apiaddressupdater.NewHostsFile(s.path)
+	err := hosts.SetAPIHostPorts(apiServers)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHosts(c, localHosts+`
+# Begin juju API servers (managed by juju, do not edit)
+10.0.0.1 ec2-1-2-3-4.example.com
+10.0.0.3 ip-10-0-0-3.internal
+# End juju API servers
+`[1:])
+}
+
+func (s *HostsFileSuite) TestSetAPIHostPortsReplacesSection(c *gc.C) {
+	s.writeHosts(c, `
+127.0.0.1 localhost
+# Begin juju API servers (managed by juju, do not edit)
+10.0.0.9 old.example.com
+# End juju API servers
+::1 ip6-localhost
+`[1:])
+	hosts := apiaddressupdater.NewHostsFile(s.path)
+	err := hosts.SetAPIHostPorts(apiServers[:1])
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHosts(c, localHosts+`
+# Begin juju API servers (managed by juju, do not edit)
+10.0.0.1 ec2-1-2-3-4.example.com
+# End juju API servers
+`[1:])
+}
+
+func (s *HostsFileSuite) TestSetAPIHostPortsRemovesSection(c *gc.C) {
+	s.writeHosts(c, localHosts)
+	hosts := apiaddressupdater.NewHostsFile(s.path)
+	err := hosts.SetAPIHostPorts(apiServers)
+	c.Assert(err, jc.ErrorIsNil)
+	err = hosts.SetAPIHostPorts(apiServers[1:2])
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHosts(c, localHosts)
+}
+
+func (s *HostsFileSuite) TestSetAPIHostPortsCreatesFile(c *gc.C) {
+	hosts := apiaddressupdater.NewHostsFile(s.path)
+	err := hosts.SetAPIHostPorts(apiServers[:1])
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHosts(c, `
+# Begin juju API servers (managed by juju, do not edit)
+10.0.0.1 ec2-1-2-3-4.example.com
+# End juju API servers
+`[1:])
+}
@@ -10,10 +10,12 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api/cleaner"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
-	"github.com/juju/juju/worker/cleaner"
+	workercleaner "github.com/juju/juju/worker/cleaner"
 )
 
 func TestPackage(t *stdtesting.T) {
@@ -26,10 +28,13 @@ type CleanerSuite struct {
 
 var _ = gc.Suite(&CleanerSuite{})
 
-var _ worker.NotifyWatchHandler = (*cleaner.Cleaner)(nil)
+var _ worker.NotifyWatchHandler = (*workercleaner.Cleaner)(nil)
+
+var _ workercleaner.StateCleaner = (*cleaner.API)(nil)
 
 func (s *CleanerSuite) TestCleaner(c *gc.C) {
-	cr := cleaner.NewCleaner(s.State)
+	st, _ := s.OpenAPIAsNewMachine(c, state.JobManageEnviron)
+	cr := workercleaner.NewCleaner(cleaner.NewAPI(st))
 	defer func() { c.Assert(worker.Stop(cr), gc.IsNil) }()
 
 	needed, err := s.State.NeedsCleanup()
@@ -0,0 +1,300 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package dnsregistrar provides a worker that registers DNS names for
+// exposed services, on clouds whose environments implement the
+// environs.DNSRegistrar interface.
+package dnsregistrar
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.dnsregistrar")
+
+type registrar struct {
+	tomb      tomb.Tomb
+	st        *state.State
+	registrar environs.DNSRegistrar
+
+	// environ is the environ used to register names, if it was
+	// created by the worker. It is kept up to date with the
+	// environment config so that rotated credentials are used.
+	environ environs.Environ
+
+	// registered holds the sorted addresses of each name
+	// registered for the environment.
+	registered map[string][]string
+
+	// foreign holds the owner of each name, among those the
+	// environment would register, that was already registered by
+	// another owner when the worker started. Those records are
+	// never changed.
+	foreign map[string]string
+}
+
+// NewWorker returns a worker that registers the name of each exposed
+// service, of the form service-name.environment.domain, with the
+// addresses of the service's units, updating the records as the
+// addresses change and removing them when services are unexposed. If
+// the environment does not implement environs.DNSRegistrar, the
+// worker does nothing.
+//
+// When it starts, the worker takes over the records already registered
+// for the environment, so that records of services destroyed while no
+// worker was running are removed. Records are marked with the UUID of
+// the environment that registered them, and the worker never changes
+// a record owned by anyone else, even one under the environment's
+// names.
+func NewWorker(st *state.State) (worker.Worker, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	environ, err := environs.New(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dnsEnviron, ok := environs.SupportsDNSRegistration(environ)
+	if !ok {
+		logger.Debugf("environment does not support DNS registration")
+		return worker.NewNoOpWorker(), nil
+	}
+	r := newRegistrar(st, dnsEnviron)
+	r.environ = dnsEnviron
+	r.start()
+	return r, nil
+}
+
+func newWorkerWithRegistrar(st *state.State, dnsRegistrar environs.DNSRegistrar) worker.Worker {
+	r := newRegistrar(st, dnsRegistrar)
+	r.start()
+	return r
+}
+
+func newRegistrar(st *state.State, dnsRegistrar environs.DNSRegistrar) *registrar {
+	return &registrar{
+		st:        st,
+		registrar: dnsRegistrar,
+	}
+}
+
+func (r *registrar) start() {
+	go func() {
+		defer r.tomb.Done()
+		r.tomb.Kill(r.loop())
+	}()
+}
+
+// Kill is part of the worker.Worker interface.
+func (r *registrar) Kill() {
+	r.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (r *registrar) Wait() error {
+	return r.tomb.Wait()
+}
+
+// loop updates the records whenever a service, unit or machine
+// changes; the first changes reported by the watcher describe the
+// whole environment, so the records are brought up to date at once.
+func (r *registrar) loop() error {
+	w := r.st.Watch()
+	go func() {
+		<-r.tomb.Dying()
+		if err := w.Stop(); err != nil {
+			logger.Errorf("cannot stop watcher: %v", err)
+		}
+	}()
+	for {
+		deltas, err := w.Next()
+		if errors.Cause(err) == state.ErrStopped {
+			return tomb.ErrDying
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if !affectsRecords(deltas) {
+			continue
+		}
+		if err := r.update(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// affectsRecords reports whether any of the given changes may
+// affect the names or addresses of exposed services.
+func affectsRecords(deltas []multiwatcher.Delta) bool {
+	for _, delta := range deltas {
+		switch delta.Entity.(type) {
+		case *multiwatcher.ServiceInfo, *multiwatcher.UnitInfo, *multiwatcher.MachineInfo:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *registrar) refreshEnviron() error {
+	if r.environ == nil {
+		return nil
+	}
+	cfg, err := r.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Annotate(r.environ.SetConfig(cfg), "cannot update environ config")
+}
+
+// update registers, updates and unregisters DNS names so that they
+// match the exposed services and their addresses.
+func (r *registrar) update() error {
+	if err := r.refreshEnviron(); err != nil {
+		return errors.Trace(err)
+	}
+	wanted, suffix, err := r.wantedRecords()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	owner := r.st.EnvironUUID()
+	if r.registered == nil {
+		if r.registered, r.foreign, err = r.existingRecords(suffix, owner); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for name, addrs := range wanted {
+		if equalStrings(r.registered[name], addrs) {
+			continue
+		}
+		if other, ok := r.foreign[name]; ok {
+			logger.Warningf("not registering %q: already registered by %q", name, other)
+			continue
+		}
+		logger.Infof("registering %q with addresses %v", name, addrs)
+		record := environs.DNSRecord{
+			Name:      name,
+			Addresses: network.NewAddresses(addrs...),
+			Owner:     owner,
+		}
+		if err := r.registrar.RegisterDNS(record); err != nil {
+			return errors.Annotatef(err, "cannot register %q", name)
+		}
+		r.registered[name] = addrs
+	}
+	for name := range r.registered {
+		if _, ok := wanted[name]; ok {
+			continue
+		}
+		logger.Infof("unregistering %q", name)
+		if err := r.registrar.UnregisterDNS(name); err != nil {
+			return errors.Annotatef(err, "cannot unregister %q", name)
+		}
+		delete(r.registered, name)
+	}
+	return nil
+}
+
+// existingRecords returns the sorted addresses of each name already
+// registered by the given owner with the given suffix, which names
+// belonging to the environment have, and the owners of the names with
+// that suffix registered by anyone else.
+func (r *registrar) existingRecords(suffix, owner string) (map[string][]string, map[string]string, error) {
+	existing := make(map[string][]string)
+	foreign := make(map[string]string)
+	if suffix == "" {
+		return existing, foreign, nil
+	}
+	records, err := r.registrar.DNSRecords()
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot get DNS records")
+	}
+	for _, record := range records {
+		if !strings.HasSuffix(record.Name, suffix) {
+			continue
+		}
+		if record.Owner != owner {
+			foreign[record.Name] = record.Owner
+			continue
+		}
+		addrs := make([]string, len(record.Addresses))
+		for i, addr := range record.Addresses {
+			addrs[i] = addr.Value
+		}
+		sort.Strings(addrs)
+		existing[record.Name] = addrs
+	}
+	return existing, foreign, nil
+}
+
+// wantedRecords returns the sorted public addresses of the units of
+// each exposed service, keyed by the service's DNS name, and the
+// suffix shared by the names of all of the environment's services.
+// Services without addresses are omitted.
+func (r *registrar) wantedRecords() (map[string][]string, string, error) {
+	domain, err := r.registrar.DNSDomain()
+	if err != nil {
+		return nil, "", errors.Annotate(err, "cannot get DNS domain")
+	}
+	wanted := make(map[string][]string)
+	if domain == "" {
+		return wanted, "", nil
+	}
+	cfg, err := r.st.EnvironConfig()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	suffix := environs.ServiceDNSName("", cfg.Name(), domain)
+	services, err := r.st.AllServices()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	for _, service := range services {
+		if !service.IsExposed() {
+			continue
+		}
+		units, err := service.AllUnits()
+		if err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		seen := make(map[string]bool)
+		var addrs []string
+		for _, unit := range units {
+			addr, ok := unit.PublicAddress()
+			if !ok || seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+		if len(addrs) == 0 {
+			continue
+		}
+		sort.Strings(addrs)
+		name := environs.ServiceDNSName(service.Name(), cfg.Name(), domain)
+		wanted[name] = addrs
+	}
+	return wanted, suffix, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dnsregistrar_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dnsregistrar"
+)
+
+type workerSuite struct {
+	jujutesting.JujuConnSuite
+
+	service   *state.Service
+	registrar *fakeRegistrar
+	worker    worker.Worker
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+type dnsCall struct {
+	register  bool
+	name      string
+	addresses []network.Address
+	owner     string
+}
+
+type fakeRegistrar struct {
+	domain  string
+	records []environs.DNSRecord
+	calls   chan dnsCall
+}
+
+func (r *fakeRegistrar) DNSDomain() (string, error) {
+	return r.domain, nil
+}
+
+func (r *fakeRegistrar) RegisterDNS(record environs.DNSRecord) error {
+	r.calls <- dnsCall{
+		register:  true,
+		name:      record.Name,
+		addresses: record.Addresses,
+		owner:     record.Owner,
+	}
+	return nil
+}
+
+func (r *fakeRegistrar) UnregisterDNS(name string) error {
+	r.calls <- dnsCall{name: name}
+	return nil
+}
+
+func (r *fakeRegistrar) DNSRecords() ([]environs.DNSRecord, error) {
+	return r.records, nil
+}
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	for _, addr := range []string{"8.8.8.8", "8.8.4.4"} {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetProviderAddresses(network.NewScopedAddress(addr, network.ScopePublic))
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.registrar = &fakeRegistrar{
+		domain: "example.com",
+		calls:  make(chan dnsCall, 10),
+	}
+}
+
+func (s *workerSuite) startWorker(c *gc.C) {
+	s.worker = dnsregistrar.NewWorkerWithRegistrar(s.State, s.registrar)
+	s.AddCleanup(func(c *gc.C) {
+		s.worker.Kill()
+		c.Assert(s.worker.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *workerSuite) nextCall(c *gc.C) dnsCall {
+	select {
+	case call := <-s.registrar.calls:
+		return call
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for DNS registration")
+	}
+	panic("unreachable")
+}
+
+func (s *workerSuite) assertNoCall(c *gc.C) {
+	select {
+	case call := <-s.registrar.calls:
+		c.Fatalf("unexpected DNS registration: %#v", call)
+	case <-time.After(coretesting.ShortWait * 3):
+	}
+}
+
+func (s *workerSuite) TestRegistersExposedServices(c *gc.C) {
+	s.startWorker(c)
+	s.assertNoCall(c)
+
+	err := s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.nextCall(c), jc.DeepEquals, dnsCall{
+		register:  true,
+		name:      "wordpress.dummyenv.example.com",
+		addresses: network.NewAddresses("8.8.4.4", "8.8.8.8"),
+		owner:     s.State.EnvironUUID(),
+	})
+	// Nothing changed, so the record is not registered again.
+	s.assertNoCall(c)
+
+	err = s.service.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.nextCall(c), jc.DeepEquals, dnsCall{
+		name: "wordpress.dummyenv.example.com",
+	})
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestUpdatesChangedAddresses(c *gc.C) {
+	err := s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+	call := s.nextCall(c)
+	c.Assert(call.addresses, jc.DeepEquals, network.NewAddresses("8.8.4.4", "8.8.8.8"))
+
+	m, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProviderAddresses(network.NewScopedAddress("1.2.3.4", network.ScopePublic))
+	c.Assert(err, jc.ErrorIsNil)
+	call = s.nextCall(c)
+	c.Assert(call.register, jc.IsTrue)
+	c.Assert(call.addresses, jc.DeepEquals, network.NewAddresses("1.2.3.4", "8.8.8.8"))
+}
+
+func (s *workerSuite) TestTakesOverExistingRecords(c *gc.C) {
+	uuid := s.State.EnvironUUID()
+	s.registrar.records = []environs.DNSRecord{{
+		// Up to date, so left alone.
+		Name:      "wordpress.dummyenv.example.com",
+		Addresses: network.NewAddresses("8.8.8.8", "8.8.4.4"),
+		Owner:     uuid,
+	}, {
+		// Left behind by a destroyed service.
+		Name:      "mysql.dummyenv.example.com",
+		Addresses: network.NewAddresses("8.8.8.8"),
+		Owner:     uuid,
+	}, {
+		// Belongs to another environment.
+		Name:      "mysql.otherenv.example.com",
+		Addresses: network.NewAddresses("8.8.8.8"),
+		Owner:     "other-uuid",
+	}, {
+		// Under the environment's names, but not registered by it.
+		Name:      "www.dummyenv.example.com",
+		Addresses: network.NewAddresses("8.8.8.8"),
+	}}
+	err := s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+	c.Assert(s.nextCall(c), jc.DeepEquals, dnsCall{
+		name: "mysql.dummyenv.example.com",
+	})
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestLeavesRecordsOwnedByOthers(c *gc.C) {
+	s.registrar.records = []environs.DNSRecord{{
+		Name:      "wordpress.dummyenv.example.com",
+		Addresses: network.NewAddresses("1.2.3.4"),
+		Owner:     "other-uuid",
+	}}
+	err := s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+	s.assertNoCall(c)
+
+	err = s.service.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestEmptyDomainDisablesRegistration(c *gc.C) {
+	s.registrar.domain = ""
+	err := s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+	s.assertNoCall(c)
+}
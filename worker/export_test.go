@@ -26,3 +26,5 @@ func SetEnsureErr(f func(watcher.Errer) error) {
 func EnsureErr() func(watcher.Errer) error {
 	return ensureErr
 }
+
+var RestartPolicyDelay = RestartPolicy.restartDelay
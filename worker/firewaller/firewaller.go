@@ -320,7 +320,7 @@ func (fw *Firewaller) reconcileGlobal() error {
 	}
 	if len(toClose) > 0 {
 		logger.Infof("closing global ports %v", toClose)
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		if err := fw.closeGlobalPorts(toClose); err != nil {
 			return err
 		}
 		network.SortPortRanges(toClose)
@@ -330,8 +330,13 @@ func (fw *Firewaller) reconcileGlobal() error {
 
 // reconcileInstances compares the initially started watcher for machines,
 // units and services with the opened and closed ports of the instances and
-// opens and closes the appropriate ports for each instance.
+// opens and closes the appropriate ports for each instance. Machines that
+// have not yet been provisioned, or whose instances cannot be found, are
+// skipped so that they do not prevent the remaining instances from being
+// reconciled.
 func (fw *Firewaller) reconcileInstances() error {
+	var machineds []*machineData
+	var instanceIds []instance.Id
 	for _, machined := range fw.machineds {
 		m, err := machined.machine()
 		if params.IsCodeNotFound(err) {
@@ -343,17 +348,34 @@ func (fw *Firewaller) reconcileInstances() error {
 			return err
 		}
 		instanceId, err := m.InstanceId()
-		if err != nil {
-			return err
-		}
-		instances, err := fw.environ.Instances([]instance.Id{instanceId})
-		if err == environs.ErrNoInstances {
-			return nil
+		if params.IsCodeNotProvisioned(err) {
+			logger.Debugf("not reconciling ports for %q: not provisioned", machined.tag)
+			continue
 		} else if err != nil {
 			return err
 		}
+		machineds = append(machineds, machined)
+		instanceIds = append(instanceIds, instanceId)
+	}
+	if len(instanceIds) == 0 {
+		return nil
+	}
+	instances, err := fw.environ.Instances(instanceIds)
+	switch err {
+	case nil, environs.ErrPartialInstances:
+	case environs.ErrNoInstances:
+		instances = make([]instance.Instance, len(instanceIds))
+	default:
+		return err
+	}
+	for i, machined := range machineds {
+		inst := instances[i]
+		if inst == nil {
+			logger.Warningf("not reconciling ports for %q: instance %q not found", machined.tag, instanceIds[i])
+			continue
+		}
 		machineId := machined.tag.Id()
-		initialPortRanges, err := instances[0].Ports(machineId)
+		initialPortRanges, err := inst.Ports(machineId)
 		if err != nil {
 			return err
 		}
@@ -364,7 +386,7 @@ func (fw *Firewaller) reconcileInstances() error {
 		if len(toOpen) > 0 {
 			logger.Infof("opening instance port ranges %v for %q",
 				toOpen, machined.tag)
-			if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+			if err := inst.OpenPorts(machineId, toOpen); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
@@ -373,7 +395,7 @@ func (fw *Firewaller) reconcileInstances() error {
 		if len(toClose) > 0 {
 			logger.Infof("closing instance port ranges %v for %q",
 				toClose, machined.tag)
-			if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+			if err := fw.closeInstancePorts(inst, machineId, toClose); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
@@ -548,7 +570,7 @@ func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) er
 		logger.Infof("opened port ranges %v in environment", toOpen)
 	}
 	if len(toClose) > 0 {
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		if err := fw.closeGlobalPorts(toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
@@ -558,6 +580,27 @@ func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) er
 	return nil
 }
 
+// closeGlobalPorts closes the given ports in the environment, or
+// only logs them if the environment is configured for a dry run.
+func (fw *Firewaller) closeGlobalPorts(ports []network.PortRange) error {
+	if environs.IsDryRun(fw.environ) {
+		logger.Infof("dry run: would close port ranges %v in environment", ports)
+		return nil
+	}
+	return fw.environ.ClosePorts(ports)
+}
+
+// closeInstancePorts closes the given ports on the instance of the
+// given machine, or only logs them if the environment is configured
+// for a dry run.
+func (fw *Firewaller) closeInstancePorts(inst instance.Instance, machineId string, ports []network.PortRange) error {
+	if environs.IsDryRun(fw.environ) {
+		logger.Infof("dry run: would close port ranges %v on machine %q", ports, machineId)
+		return nil
+	}
+	return inst.ClosePorts(machineId, ports)
+}
+
 // flushInstancePorts opens and closes ports global on the machine.
 func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.PortRange) error {
 	// If there's nothing to do, do nothing.
@@ -593,7 +636,7 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
 	}
 	if len(toClose) > 0 {
-		if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+		if err := fw.closeInstancePorts(instances[0], machineId, toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
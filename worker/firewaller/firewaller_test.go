@@ -372,6 +372,43 @@ func (s *InstanceModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
 }
 
+func (s *InstanceModeSuite) TestStartReconcilesInstancePorts(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err := svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Neither an unprovisioned machine nor the manager machine, whose
+	// instance is unknown to the environ, may prevent reconciliation.
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Open a port that is not wanted while the firewaller is down.
+	err = inst.OpenPorts(m.Id(), []network.PortRange{{22, 22, "tcp"}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{22, 22, "tcp"}})
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+
+	// Drift again while the firewaller is stopped.
+	err = worker.Stop(fw)
+	c.Assert(err, jc.ErrorIsNil)
+	err = inst.ClosePorts(m.Id(), []network.PortRange{{80, 80, "tcp"}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = inst.OpenPorts(m.Id(), []network.PortRange{{8080, 8080, "tcp"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw, err = firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+}
+
 func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,173 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// MetadataFile holds the name of the file, in a charm's root
+// directory, whose health-checks section declares the charm's health
+// checks. For example:
+//
+//	health-checks:
+//	  website:
+//	    http: http://localhost:80/
+//	    interval: 1m
+//	  database:
+//	    tcp: localhost:5432
+//
+// Each check names exactly one of a TCP address to connect to or an
+// HTTP URL to fetch; an HTTP check passes if the response has a 2xx
+// or 3xx status code. The charm metadata parser ignores sections it
+// does not know, so charms declaring checks still deploy everywhere.
+const MetadataFile = "metadata.yaml"
+
+const (
+	// DefaultInterval holds the time between probes of a check that
+	// does not specify an interval.
+	DefaultInterval = 30 * time.Second
+
+	// DefaultTimeout holds how long a probe may take before the
+	// check fails, when the check does not specify a timeout.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Check describes a single health check declared by a charm.
+type Check struct {
+	Name     string
+	TCP      string
+	HTTP     string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type checksDoc struct {
+	Checks map[string]checkDoc `yaml:"health-checks"`
+}
+
+type checkDoc struct {
+	TCP      string `yaml:"tcp"`
+	HTTP     string `yaml:"http"`
+	Interval string `yaml:"interval"`
+	Timeout  string `yaml:"timeout"`
+}
+
+// ReadChecks returns the health checks declared by the charm in the
+// given directory, ordered by name. A charm that does not declare
+// any health checks has none.
+func ReadChecks(charmDir string) ([]Check, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, MetadataFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var doc checksDoc
+	if err := goyaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %s", MetadataFile)
+	}
+	checks := make([]Check, 0, len(doc.Checks))
+	for name, d := range doc.Checks {
+		check, err := d.check(name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid health check %q", name)
+		}
+		checks = append(checks, check)
+	}
+	sort.Sort(byName(checks))
+	return checks, nil
+}
+
+func (d checkDoc) check(name string) (Check, error) {
+	check := Check{
+		Name:     name,
+		TCP:      d.TCP,
+		HTTP:     d.HTTP,
+		Interval: DefaultInterval,
+		Timeout:  DefaultTimeout,
+	}
+	switch {
+	case d.TCP == "" && d.HTTP == "":
+		return Check{}, errors.New("neither tcp nor http specified")
+	case d.TCP != "" && d.HTTP != "":
+		return Check{}, errors.New("both tcp and http specified")
+	case d.TCP != "":
+		if _, _, err := net.SplitHostPort(d.TCP); err != nil {
+			return Check{}, errors.NotValidf("tcp address %q", d.TCP)
+		}
+	default:
+		if u, err := url.Parse(d.HTTP); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return Check{}, errors.NotValidf("http URL %q", d.HTTP)
+		}
+	}
+	var err error
+	if d.Interval != "" {
+		if check.Interval, err = parsePositiveDuration(d.Interval); err != nil {
+			return Check{}, errors.Annotate(err, "invalid interval")
+		}
+	}
+	if d.Timeout != "" {
+		if check.Timeout, err = parsePositiveDuration(d.Timeout); err != nil {
+			return Check{}, errors.Annotate(err, "invalid timeout")
+		}
+	}
+	return check, nil
+}
+
+func parsePositiveDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.Errorf("%q is not positive", s)
+	}
+	return d, nil
+}
+
+type byName []Check
+
+func (c byName) Len() int           { return len(c) }
+func (c byName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c byName) Less(i, j int) bool { return c[i].Name < c[j].Name }
+
+// Probe runs the check once, returning an error if it fails.
+func (c Check) Probe() error {
+	if c.TCP != "" {
+		return probeTCP(c.TCP, c.Timeout)
+	}
+	return probeHTTP(c.HTTP, c.Timeout)
+}
+
+var probeTCP = func(addr string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return conn.Close()
+}
+
+var probeHTTP = func(rawURL string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.Errorf("%s returned %s", rawURL, resp.Status)
+	}
+	return nil
+}
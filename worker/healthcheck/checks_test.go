@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/healthcheck"
+)
+
+type checksSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&checksSuite{})
+
+func writeChecks(c *gc.C, dir, content string) {
+	err := ioutil.WriteFile(filepath.Join(dir, healthcheck.MetadataFile), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *checksSuite) TestReadChecksNoFile(c *gc.C) {
+	checks, err := healthcheck.ReadChecks(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 0)
+}
+
+func (s *checksSuite) TestReadChecks(c *gc.C) {
+	dir := c.MkDir()
+	writeChecks(c, dir, `
+name: website
+summary: a website
+health-checks:
+  website:
+    http: http://localhost:80/
+    interval: 1m
+    timeout: 5s
+  database:
+    tcp: localhost:5432
+`)
+	checks, err := healthcheck.ReadChecks(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, jc.DeepEquals, []healthcheck.Check{{
+		Name:     "database",
+		TCP:      "localhost:5432",
+		Interval: healthcheck.DefaultInterval,
+		Timeout:  healthcheck.DefaultTimeout,
+	}, {
+		Name:     "website",
+		HTTP:     "http://localhost:80/",
+		Interval: time.Minute,
+		Timeout:  5 * time.Second,
+	}})
+}
+
+var invalidChecksTests = []struct {
+	about   string
+	content string
+	err     string
+}{{
+	about:   "no probe",
+	content: "health-checks: {foo: {interval: 1m}}",
+	err:     `invalid health check "foo": neither tcp nor http specified`,
+}, {
+	about:   "two probes",
+	content: "health-checks: {foo: {tcp: 'localhost:1', http: 'http://localhost/'}}",
+	err:     `invalid health check "foo": both tcp and http specified`,
+}, {
+	about:   "bad address",
+	content: "health-checks: {foo: {tcp: localhost}}",
+	err:     `invalid health check "foo": tcp address "localhost" not valid`,
+}, {
+	about:   "bad URL",
+	content: "health-checks: {foo: {http: 'ftp://localhost/'}}",
+	err:     `invalid health check "foo": http URL "ftp://localhost/" not valid`,
+}, {
+	about:   "bad interval",
+	content: "health-checks: {foo: {tcp: 'localhost:1', interval: -1s}}",
+	err:     `invalid health check "foo": invalid interval: "-1s" is not positive`,
+}, {
+	about:   "bad yaml",
+	content: "health-checks: [",
+	err:     "cannot parse metadata.yaml: .*",
+}}
+
+func (s *checksSuite) TestReadChecksInvalid(c *gc.C) {
+	for i, test := range invalidChecksTests {
+		c.Logf("test %d: %s", i, test.about)
+		dir := c.MkDir()
+		writeChecks(c, dir, test.content)
+		_, err := healthcheck.ReadChecks(dir)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *checksSuite) TestProbeTCP(c *gc.C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	addr := l.Addr().String()
+	check := healthcheck.Check{Name: "tcp", TCP: addr, Timeout: time.Second}
+	c.Assert(check.Probe(), jc.ErrorIsNil)
+
+	l.Close()
+	c.Assert(check.Probe(), gc.NotNil)
+}
+
+func (s *checksSuite) TestProbeHTTP(c *gc.C) {
+	code := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(code)
+	}))
+	defer srv.Close()
+	check := healthcheck.Check{Name: "http", HTTP: srv.URL, Timeout: time.Second}
+	c.Assert(check.Probe(), jc.ErrorIsNil)
+
+	code = http.StatusServiceUnavailable
+	c.Assert(check.Probe(), gc.ErrorMatches, ".* returned 503 Service Unavailable")
+}
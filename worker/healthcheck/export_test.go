@@ -0,0 +1,33 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"time"
+)
+
+var (
+	ProbeTCP  = &probeTCP
+	ProbeHTTP = &probeHTTP
+)
+
+// Checker exposes a health checker's single pass for tests.
+type Checker struct {
+	h *healthChecker
+}
+
+// NewChecker returns a Checker for the charm in charmDir.
+func NewChecker(unit Unit, charmDir string) *Checker {
+	return &Checker{&healthChecker{
+		unit:     unit,
+		charmDir: charmDir,
+		nextRun:  make(map[string]time.Time),
+		failures: make(map[string]error),
+	}}
+}
+
+// RunDueChecks runs the checks due at the given time.
+func (c *Checker) RunDueChecks(now time.Time) (time.Duration, error) {
+	return c.h.runDueChecks(now)
+}
@@ -0,0 +1,163 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package healthcheck provides a unit agent worker that periodically
+// probes the health checks declared by the unit's charm, and reflects
+// any failure in the unit's workload status.
+package healthcheck
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.healthcheck")
+
+// Unit is the subset of the uniter API's Unit used by the worker.
+type Unit interface {
+	UnitStatus() (params.StatusResult, error)
+	SetUnitStatus(status params.Status, info string, data map[string]interface{}) error
+}
+
+// rescanInterval holds the longest time the worker waits before
+// reading the charm's health checks again, so that checks added by a
+// charm upgrade are picked up.
+var rescanInterval = DefaultInterval
+
+// NewWorker returns a worker that probes the health checks declared
+// in the charm deployed to charmDir. While any check fails, the unit's
+// workload status is set to blocked; once all checks pass again, the
+// status the unit had before the failure is restored, unless the charm
+// has set a different status in the meantime.
+func NewWorker(unit Unit, charmDir string) worker.Worker {
+	h := &healthChecker{
+		unit:     unit,
+		charmDir: charmDir,
+		nextRun:  make(map[string]time.Time),
+		failures: make(map[string]error),
+	}
+	return worker.NewSimpleWorker(h.loop)
+}
+
+type healthChecker struct {
+	unit     Unit
+	charmDir string
+
+	// nextRun records when each check is next due.
+	nextRun map[string]time.Time
+
+	// failures records the most recent error of each failing check.
+	failures map[string]error
+
+	// blocked holds the status info set by the worker while the unit
+	// is unhealthy, and is empty otherwise.
+	blocked string
+
+	// saved holds the unit's status from before it became unhealthy.
+	saved params.StatusResult
+}
+
+func (h *healthChecker) loop(stop <-chan struct{}) error {
+	for {
+		wait, err := h.runDueChecks(time.Now())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// runDueChecks runs every check that is due at the given time, updates
+// the unit's status accordingly, and returns how long to wait before
+// checks should be run again.
+func (h *healthChecker) runDueChecks(now time.Time) (time.Duration, error) {
+	checks, err := ReadChecks(h.charmDir)
+	if err != nil {
+		// Broken health checks are the charm author's problem; don't
+		// bring the agent down because of it.
+		logger.Warningf("cannot read health checks: %v", err)
+		checks = nil
+	}
+	wait := rescanInterval
+	current := make(map[string]bool)
+	for _, check := range checks {
+		current[check.Name] = true
+		next, ok := h.nextRun[check.Name]
+		if !ok || !now.Before(next) {
+			if err := check.Probe(); err != nil {
+				logger.Debugf("health check %q failed: %v", check.Name, err)
+				h.failures[check.Name] = err
+			} else {
+				delete(h.failures, check.Name)
+			}
+			next = now.Add(check.Interval)
+			h.nextRun[check.Name] = next
+		}
+		if d := next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	for name := range h.nextRun {
+		if !current[name] {
+			delete(h.nextRun, name)
+			delete(h.failures, name)
+		}
+	}
+	if err := h.updateStatus(checks); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return wait, nil
+}
+
+// updateStatus sets or clears the blocked workload status according to
+// the current set of failing checks.
+func (h *healthChecker) updateStatus(checks []Check) error {
+	info := ""
+	for _, check := range checks {
+		if err, ok := h.failures[check.Name]; ok {
+			info = fmt.Sprintf("health check %q failed: %v", check.Name, err)
+			break
+		}
+	}
+	if info == h.blocked {
+		return nil
+	}
+	current, err := h.unit.UnitStatus()
+	if err != nil {
+		return errors.Annotate(err, "cannot get unit status")
+	}
+	if h.blocked == "" {
+		h.saved = current
+	} else if current.Status != params.StatusBlocked || current.Info != h.blocked {
+		// The charm has set its own status since the worker blocked
+		// the unit; respect it, and don't restore the old one later.
+		h.saved = current
+		if info == "" {
+			h.blocked = ""
+			return nil
+		}
+	}
+	if info == "" {
+		logger.Infof("all health checks passing")
+		if err := h.unit.SetUnitStatus(h.saved.Status, h.saved.Info, h.saved.Data); err != nil {
+			return errors.Annotate(err, "cannot restore unit status")
+		}
+	} else {
+		logger.Infof("%s", info)
+		if err := h.unit.SetUnitStatus(params.StatusBlocked, info, nil); err != nil {
+			return errors.Annotate(err, "cannot set unit status")
+		}
+	}
+	h.blocked = info
+	return nil
+}
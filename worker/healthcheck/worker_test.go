@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/healthcheck"
+)
+
+type workerSuite struct {
+	testing.BaseSuite
+	unit     *fakeUnit
+	charmDir string
+	tcpErr   error
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.unit = &fakeUnit{status: params.StatusResult{
+		Status: params.StatusActive,
+		Info:   "serving",
+	}}
+	s.charmDir = c.MkDir()
+	s.tcpErr = nil
+	s.PatchValue(healthcheck.ProbeTCP, func(string, time.Duration) error {
+		return s.tcpErr
+	})
+	writeChecks(c, s.charmDir, "health-checks: {db: {tcp: 'localhost:5432', interval: 10s}}")
+}
+
+func (s *workerSuite) TestFailureBlocksAndRecoveryRestores(c *gc.C) {
+	checker := healthcheck.NewChecker(s.unit, s.charmDir)
+	now := time.Now()
+
+	wait, err := checker.RunDueChecks(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wait, gc.Equals, 10*time.Second)
+	c.Assert(s.unit.sets, gc.Equals, 0)
+
+	s.tcpErr = errors.New("connection refused")
+	_, err = checker.RunDueChecks(now.Add(5 * time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.sets, gc.Equals, 0)
+
+	_, err = checker.RunDueChecks(now.Add(10 * time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.status.Status, gc.Equals, params.StatusBlocked)
+	c.Assert(s.unit.status.Info, gc.Equals, `health check "db" failed: connection refused`)
+
+	s.tcpErr = nil
+	_, err = checker.RunDueChecks(now.Add(20 * time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.status, jc.DeepEquals, params.StatusResult{
+		Status: params.StatusActive,
+		Info:   "serving",
+	})
+	c.Assert(s.unit.sets, gc.Equals, 2)
+}
+
+func (s *workerSuite) TestRecoveryRespectsCharmStatus(c *gc.C) {
+	checker := healthcheck.NewChecker(s.unit, s.charmDir)
+	now := time.Now()
+
+	s.tcpErr = errors.New("connection refused")
+	_, err := checker.RunDueChecks(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.status.Status, gc.Equals, params.StatusBlocked)
+
+	// The charm sets its own status while the check is failing.
+	s.unit.status = params.StatusResult{Status: params.StatusMaintenance, Info: "upgrading"}
+
+	s.tcpErr = nil
+	_, err = checker.RunDueChecks(now.Add(10 * time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.status, jc.DeepEquals, params.StatusResult{
+		Status: params.StatusMaintenance,
+		Info:   "upgrading",
+	})
+	c.Assert(s.unit.sets, gc.Equals, 1)
+}
+
+func (s *workerSuite) TestRemovedCheckClearsFailure(c *gc.C) {
+	checker := healthcheck.NewChecker(s.unit, s.charmDir)
+	s.tcpErr = errors.New("connection refused")
+	_, err := checker.RunDueChecks(time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.status.Status, gc.Equals, params.StatusBlocked)
+
+	writeChecks(c, s.charmDir, "health-checks: {}")
+	wait, err := checker.RunDueChecks(time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wait, gc.Equals, healthcheck.DefaultInterval)
+	c.Assert(s.unit.status.Status, gc.Equals, params.StatusActive)
+}
+
+func (s *workerSuite) TestWorkerStops(c *gc.C) {
+	w := healthcheck.NewWorker(s.unit, s.charmDir)
+	w.Kill()
+	c.Assert(w.Wait(), jc.ErrorIsNil)
+}
+
+type fakeUnit struct {
+	status params.StatusResult
+	sets   int
+}
+
+func (u *fakeUnit) UnitStatus() (params.StatusResult, error) {
+	return u.status, nil
+}
+
+func (u *fakeUnit) SetUnitStatus(status params.Status, info string, data map[string]interface{}) error {
+	u.sets++
+	u.status = params.StatusResult{Status: status, Info: info, Data: data}
+	return nil
+}
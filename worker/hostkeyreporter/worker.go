@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hostkeyreporter provides a worker that reports the public
+// SSH host keys of a machine, so that "juju ssh" and "juju scp" can
+// verify the machine's identity.
+package hostkeyreporter
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.hostkeyreporter")
+
+// DefaultSSHDir is the directory holding the machine's SSH host keys.
+const DefaultSSHDir = "/etc/ssh"
+
+// Reporter records the public SSH host keys of a machine.
+type Reporter interface {
+	ReportKeys(tag names.MachineTag, publicKeys []string) error
+}
+
+// NewWorker returns a worker that reads the public SSH host keys of
+// the machine with the given tag from sshDir, reports them using the
+// given reporter, and then waits to be stopped.
+func NewWorker(reporter Reporter, tag names.MachineTag, sshDir string) worker.Worker {
+	if version.Current.OS == version.Windows {
+		return worker.NewNoOpWorker()
+	}
+	return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+		keys, err := readPublicKeys(sshDir)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(keys) == 0 {
+			logger.Warningf("no SSH host keys found in %q", sshDir)
+		}
+		if err := reporter.ReportKeys(tag, keys); err != nil {
+			return errors.Annotate(err, "cannot report SSH host keys")
+		}
+		logger.Infof("reported %d SSH host keys", len(keys))
+		<-stop
+		return nil
+	})
+}
+
+// readPublicKeys returns the contents of the public SSH host key
+// files in sshDir, sorted by file name.
+func readPublicKeys(sshDir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(sshDir, "ssh_host_*_key.pub"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(paths)
+	var keys []string
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read SSH host key")
+		}
+		if key := strings.TrimSpace(string(data)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/hostkeyreporter"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type workerSuite struct {
+	coretesting.BaseSuite
+	sshDir string
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	if runtime.GOOS == "windows" {
+		c.Skip("host keys are not reported on windows")
+	}
+	s.sshDir = c.MkDir()
+	for name, content := range map[string]string{
+		"ssh_host_rsa_key.pub":     "ssh-rsa rsa-key root@host\n",
+		"ssh_host_ed25519_key.pub": "ssh-ed25519 ed25519-key root@host\n",
+		"ssh_host_rsa_key":         "private, not reported",
+		"ssh_config":               "not a key",
+	} {
+		err := ioutil.WriteFile(filepath.Join(s.sshDir, name), []byte(content), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+type fakeReporter struct {
+	reported chan []string
+	err      error
+}
+
+func (r *fakeReporter) ReportKeys(tag names.MachineTag, keys []string) error {
+	if tag != names.NewMachineTag("42") {
+		return errors.New("unexpected tag")
+	}
+	r.reported <- keys
+	return r.err
+}
+
+func (s *workerSuite) TestReportsKeys(c *gc.C) {
+	reporter := &fakeReporter{reported: make(chan []string, 1)}
+	w := hostkeyreporter.NewWorker(reporter, names.NewMachineTag("42"), s.sshDir)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	}()
+	select {
+	case keys := <-reporter.reported:
+		c.Assert(keys, jc.DeepEquals, []string{
+			"ssh-ed25519 ed25519-key root@host",
+			"ssh-rsa rsa-key root@host",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for keys to be reported")
+	}
+}
+
+func (s *workerSuite) TestReportError(c *gc.C) {
+	reporter := &fakeReporter{
+		reported: make(chan []string, 1),
+		err:      errors.New("boom"),
+	}
+	w := hostkeyreporter.NewWorker(reporter, names.NewMachineTag("42"), s.sshDir)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot report SSH host keys: boom")
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/worker"
 )
 
@@ -338,7 +339,7 @@ func startProvisionerWorker(
 	// already been added to the machine. It will see that the
 	// container does not have an instance yet and create one.
 	return runner.StartWorker(workerName, func() (worker.Worker, error) {
-		return NewContainerProvisioner(containerType, provisioner, config, broker, toolsFinder), nil
+		return NewContainerProvisioner(containerType, provisioner, config, broker, toolsFinder, clock.WallClock), nil
 	})
 }
 
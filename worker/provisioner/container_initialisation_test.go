@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/provisioner"
@@ -76,7 +77,7 @@ func (s *ContainerSetupSuite) SetUpTest(c *gc.C) {
 
 	// Set up provisioner for the state machine.
 	s.agentConfig = s.AgentConfigForTag(c, names.NewMachineTag("0"))
-	s.p = provisioner.NewEnvironProvisioner(s.provisioner, s.agentConfig)
+	s.p = provisioner.NewEnvironProvisioner(s.provisioner, s.agentConfig, clock.WallClock)
 
 	// Create a new container initialisation lock.
 	s.initLockDir = c.MkDir()
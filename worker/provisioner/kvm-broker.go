@@ -104,6 +104,9 @@ func (broker *kvmBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		return nil, err
 	}
 
+	// The container manager sizes the domain from the constraints.
+	args.InstanceConfig.Constraints = args.Constraints
+
 	storageConfig := &container.StorageConfig{
 		AllowMount: true,
 	}
@@ -27,6 +27,7 @@ import (
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/provisioner"
 )
@@ -91,12 +92,15 @@ func (s *kvmBrokerSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *kvmBrokerSuite) startInstance(c *gc.C, machineId string) instance.Instance {
+	return s.startInstanceWithConstraints(c, machineId, constraints.Value{}).Instance
+}
+
+func (s *kvmBrokerSuite) startInstanceWithConstraints(c *gc.C, machineId string, cons constraints.Value) *environs.StartInstanceResult {
 	machineNonce := "fake-nonce"
 	stateInfo := jujutesting.FakeStateInfo(machineId)
 	apiInfo := jujutesting.FakeAPIInfo(machineId)
 	instanceConfig, err := instancecfg.NewInstanceConfig(machineId, machineNonce, "released", "quantal", true, nil, stateInfo, apiInfo)
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.Value{}
 	possibleTools := coretools.List{&coretools.Tools{
 		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
 		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
@@ -107,7 +111,16 @@ func (s *kvmBrokerSuite) startInstance(c *gc.C, machineId string) instance.Insta
 		InstanceConfig: instanceConfig,
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	return result.Instance
+	return result
+}
+
+func (s *kvmBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
+	cons := constraints.MustParse("mem=2G cpu-cores=4 root-disk=10G")
+	result := s.startInstanceWithConstraints(c, "1/kvm/0", cons)
+	c.Assert(result.Hardware, gc.NotNil)
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(2048))
+	c.Assert(*result.Hardware.CpuCores, gc.Equals, uint64(4))
+	c.Assert(*result.Hardware.RootDisk, gc.Equals, uint64(10240))
 }
 
 func (s *kvmBrokerSuite) TestStopInstance(c *gc.C) {
@@ -237,7 +250,7 @@ func (s *kvmProvisionerSuite) newKvmProvisioner(c *gc.C) provisioner.Provisioner
 	broker, err := provisioner.NewKvmBroker(s.provisioner, agentConfig, managerConfig)
 	c.Assert(err, jc.ErrorIsNil)
 	toolsFinder := (*provisioner.GetToolsFinder)(s.provisioner)
-	return provisioner.NewContainerProvisioner(instance.KVM, s.provisioner, agentConfig, broker, toolsFinder)
+	return provisioner.NewContainerProvisioner(instance.KVM, s.provisioner, agentConfig, broker, toolsFinder, clock.WallClock)
 }
 
 func (s *kvmProvisionerSuite) TestProvisionerStartStop(c *gc.C) {
@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/utils"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/worker"
 )
 
@@ -60,6 +61,7 @@ type provisioner struct {
 	agentConfig agent.Config
 	broker      environs.InstanceBroker
 	toolsFinder ToolsFinder
+	clock       clock.Clock
 	tomb        tomb.Tomb
 }
 
@@ -109,7 +111,7 @@ var getToolsFinder = func(st *apiprovisioner.State) ToolsFinder {
 }
 
 // getStartTask creates a new worker for the provisioner,
-func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerTask, error) {
+func (p *provisioner) getStartTask(harvestMode config.HarvestMode, stopApprover StopApprover) (ProvisionerTask, error) {
 	auth, err := authentication.NewAPIAuthenticator(p.st)
 	if err != nil {
 		return nil, err
@@ -142,6 +144,7 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 	task := NewProvisionerTask(
 		machineTag,
 		harvestMode,
+		stopApprover,
 		p.st,
 		p.toolsFinder,
 		machineWatcher,
@@ -150,19 +153,22 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		auth,
 		envCfg.ImageStream(),
 		secureServerConnection,
+		p.clock,
 	)
 	return task, nil
 }
 
 // NewEnvironProvisioner returns a new Provisioner for an environment.
 // When new machines are added to the state, it allocates instances
-// from the environment and allocates them to the new machines.
-func NewEnvironProvisioner(st *apiprovisioner.State, agentConfig agent.Config) Provisioner {
+// from the environment and allocates them to the new machines. The
+// clock is used to time its waits and retries.
+func NewEnvironProvisioner(st *apiprovisioner.State, agentConfig agent.Config, clock clock.Clock) Provisioner {
 	p := &environProvisioner{
 		provisioner: provisioner{
 			st:          st,
 			agentConfig: agentConfig,
 			toolsFinder: getToolsFinder(st),
+			clock:       clock,
 		},
 	}
 	p.Provisioner = p
@@ -189,8 +195,9 @@ func (p *environProvisioner) loop() error {
 	}
 	p.broker = p.environ
 
-	harvestMode := p.environ.Config().ProvisionerHarvestMode()
-	task, err := p.getStartTask(harvestMode)
+	environConfig := p.environ.Config()
+	harvestMode := environConfig.ProvisionerHarvestMode()
+	task, err := p.getStartTask(harvestMode, stopApproverForConfig(environConfig))
 	if err != nil {
 		return utils.LoggedErrorStack(errors.Trace(err))
 	}
@@ -217,10 +224,22 @@ func (p *environProvisioner) loop() error {
 				logger.Errorf("loaded invalid environment configuration: %v", err)
 			}
 			task.SetHarvestMode(environConfig.ProvisionerHarvestMode())
+			task.SetStopApprover(stopApproverForConfig(environConfig))
 		}
 	}
 }
 
+// stopApproverForConfig returns the StopApprover configured in the
+// given environment configuration, or nil if there is none.
+func stopApproverForConfig(cfg *config.Config) StopApprover {
+	approvalURL, ok := cfg.InstanceStopApprovalURL()
+	if !ok {
+		return nil
+	}
+	uuid, _ := cfg.UUID()
+	return NewHTTPStopApprover(approvalURL, uuid)
+}
+
 func (p *environProvisioner) getMachineWatcher() (apiwatcher.StringsWatcher, error) {
 	return p.st.WatchEnvironMachines()
 }
@@ -241,13 +260,15 @@ func (p *environProvisioner) setConfig(environConfig *config.Config) error {
 
 // NewContainerProvisioner returns a new Provisioner. When new machines
 // are added to the state, it allocates instances from the environment
-// and allocates them to the new machines.
+// and allocates them to the new machines. The clock is used to time
+// its waits and retries.
 func NewContainerProvisioner(
 	containerType instance.ContainerType,
 	st *apiprovisioner.State,
 	agentConfig agent.Config,
 	broker environs.InstanceBroker,
 	toolsFinder ToolsFinder,
+	clock clock.Clock,
 ) Provisioner {
 
 	p := &containerProvisioner{
@@ -256,6 +277,7 @@ func NewContainerProvisioner(
 			agentConfig: agentConfig,
 			broker:      broker,
 			toolsFinder: toolsFinder,
+			clock:       clock,
 		},
 		containerType: containerType,
 	}
@@ -269,7 +291,7 @@ func NewContainerProvisioner(
 }
 
 func (p *containerProvisioner) loop() error {
-	task, err := p.getStartTask(config.HarvestDestroyed)
+	task, err := p.getStartTask(config.HarvestDestroyed, nil)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@ package provisioner
 import (
 	"fmt"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -27,6 +28,7 @@ import (
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/storage"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 )
@@ -41,6 +43,11 @@ type ProvisionerTask interface {
 	// should harvest machines. See config.HarvestMode for
 	// documentation of behavior.
 	SetHarvestMode(mode config.HarvestMode)
+
+	// SetStopApprover replaces the StopApprover that must approve
+	// each request to stop instances. If it is nil, no approval is
+	// needed.
+	SetStopApprover(approver StopApprover)
 }
 
 type MachineGetter interface {
@@ -62,6 +69,7 @@ var _ ToolsFinder = (*apiprovisioner.State)(nil)
 func NewProvisionerTask(
 	machineTag names.MachineTag,
 	harvestMode config.HarvestMode,
+	stopApprover StopApprover,
 	machineGetter MachineGetter,
 	toolsFinder ToolsFinder,
 	machineWatcher apiwatcher.StringsWatcher,
@@ -70,6 +78,7 @@ func NewProvisionerTask(
 	auth authentication.AuthenticationProvider,
 	imageStream string,
 	secureServerConnection bool,
+	clock clock.Clock,
 ) ProvisionerTask {
 	task := &provisionerTask{
 		machineTag:             machineTag,
@@ -81,9 +90,13 @@ func NewProvisionerTask(
 		auth:                   auth,
 		harvestMode:            harvestMode,
 		harvestModeChan:        make(chan config.HarvestMode, 1),
+		stopApprover:           stopApprover,
 		machines:               make(map[string]*apiprovisioner.Machine),
+		unapproved:             make(set.Strings),
+		abandoned:              make(set.Strings),
 		imageStream:            imageStream,
 		secureServerConnection: secureServerConnection,
+		clock:                  clock,
 	}
 	go func() {
 		defer task.tomb.Done()
@@ -105,10 +118,25 @@ type provisionerTask struct {
 	secureServerConnection bool
 	harvestMode            config.HarvestMode
 	harvestModeChan        chan config.HarvestMode
+	clock                  clock.Clock
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
 	machines map[string]*apiprovisioner.Machine
+	// unapproved holds the ids of dead machines whose instances
+	// could not be stopped because stopping them was not approved,
+	// or because the environment is configured for a dry run.
+	unapproved set.Strings
+
+	// abandoned holds the ids of machines whose instance was started
+	// but could not be recorded, and was left running because the
+	// environment is configured for a dry run. They are not retried,
+	// so that no more instances are started for them.
+	abandoned set.Strings
+
+	// stopApproverMu guards stopApprover.
+	stopApproverMu sync.Mutex
+	stopApprover   StopApprover
 }
 
 // Kill implements worker.Worker.Kill.
@@ -187,6 +215,13 @@ func (task *provisionerTask) loop() error {
 			if err := task.processMachinesWithTransientErrors(); err != nil {
 				return errors.Annotate(err, "failed to process machines with transient errors")
 			}
+			if !task.unapproved.IsEmpty() {
+				// Ask again for approval to stop the instances
+				// of dead machines.
+				if err := task.processMachines(nil); err != nil {
+					return errors.Annotate(err, "failed to process dead machines")
+				}
+			}
 		}
 	}
 }
@@ -199,6 +234,28 @@ func (task *provisionerTask) SetHarvestMode(mode config.HarvestMode) {
 	}
 }
 
+// SetStopApprover implements ProvisionerTask.SetStopApprover().
+func (task *provisionerTask) SetStopApprover(approver StopApprover) {
+	task.stopApproverMu.Lock()
+	defer task.stopApproverMu.Unlock()
+	task.stopApprover = approver
+}
+
+// approveStop returns nil if the given instances may be stopped.
+func (task *provisionerTask) approveStop(instances []instance.Instance) error {
+	task.stopApproverMu.Lock()
+	approver := task.stopApprover
+	task.stopApproverMu.Unlock()
+	if approver == nil || len(instances) == 0 {
+		return nil
+	}
+	ids := make([]instance.Id, len(instances))
+	for i, inst := range instances {
+		ids[i] = inst.Id()
+	}
+	return approver.ApproveStop(ids)
+}
+
 func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	machines, statusResults, err := task.machineGetter.MachinesWithTransientErrors()
 	if err != nil {
@@ -212,6 +269,10 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 			continue
 		}
 		machine := machines[i]
+		if task.abandoned.Contains(machine.Id()) {
+			logger.Warningf("dry run: not retrying provisioning of machine %q, whose instance was left running", machine)
+			continue
+		}
 		if err := machine.SetStatus(params.StatusPending, "", nil); err != nil {
 			logger.Errorf("cannot reset status of machine %q: %v", status.Id, err)
 			continue
@@ -225,6 +286,15 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 func (task *provisionerTask) processMachines(ids []string) error {
 	logger.Tracef("processMachines(%v)", ids)
 
+	// Reconsider any dead machines whose instances we were
+	// not previously allowed to stop.
+	for _, id := range task.unapproved.Values() {
+		if !stringsContain(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	task.unapproved = make(set.Strings)
+
 	// Populate the tasks maps of current instances and machines.
 	if err := task.populateMachineMaps(ids); err != nil {
 		return err
@@ -273,9 +343,32 @@ func (task *provisionerTask) processMachines(ids []string) error {
 	// pending ones, because if we start an instance and then fail to
 	// set its InstanceId on the machine we don't want to start a new
 	// instance for the same machine ID.
-	if err := task.stopInstances(append(stopping, unknown...)); err != nil {
+	toStop := append(stopping, unknown...)
+	var keepDead bool
+	if len(toStop) > 0 && environs.IsDryRun(task.broker) {
+		logger.Infof("dry run: would stop instances %v", instanceIds(toStop))
+		keepDead = true
+	} else if err := task.approveStop(toStop); err != nil {
+		logger.Warningf("not stopping instances %v: %v", instanceIds(toStop), err)
+		keepDead = true
+	} else if err := task.stopInstances(toStop); err != nil {
 		return err
 	}
+	if keepDead {
+		// Keep the dead machines whose instances are still running,
+		// so that stopping them is attempted again later; removing
+		// them would leave their instances untracked.
+		notStopped := set.NewStrings(instanceIds(stopping)...)
+		var removable []*apiprovisioner.Machine
+		for _, machine := range dead {
+			if instId, err := machine.InstanceId(); err == nil && notStopped.Contains(string(instId)) {
+				task.unapproved.Add(machine.Id())
+				continue
+			}
+			removable = append(removable, machine)
+		}
+		dead = removable
+	}
 
 	// Remove any dead machines from state.
 	for _, machine := range dead {
@@ -293,6 +386,15 @@ func (task *provisionerTask) processMachines(ids []string) error {
 	return task.startMachines(pending)
 }
 
+func stringsContain(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func instanceIds(instances []instance.Instance) []string {
 	ids := make([]string, 0, len(instances))
 	for _, inst := range instances {
@@ -534,6 +636,8 @@ func constructStartInstanceParams(
 		Placement:         provisioningInfo.Placement,
 		DistributionGroup: machine.DistributionGroup,
 		Volumes:           volumes,
+		InstanceName:      provisioningInfo.InstanceName,
+		InstanceTags:      provisioningInfo.Tags,
 	}, nil
 }
 
@@ -550,7 +654,15 @@ func (task *provisionerTask) maintainMachines(machines []*apiprovisioner.Machine
 	return nil
 }
 
+// machineStart holds what is needed to start an instance for a machine.
+type machineStart struct {
+	machine             *apiprovisioner.Machine
+	provisioningInfo    *params.ProvisioningInfo
+	startInstanceParams environs.StartInstanceParams
+}
+
 func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) error {
+	var starts []machineStart
 	for _, m := range machines {
 
 		pInfo, err := task.blockUntilProvisioned(m.ProvisioningInfo)
@@ -571,7 +683,10 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 			pInfo.Constraints.Arch,
 		)
 		if err != nil {
-			return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
+			if err := task.setErrorStatus("cannot find tools for machine %q: %v", m, err); err != nil {
+				return err
+			}
+			continue
 		}
 
 		startInstanceParams, err := constructStartInstanceParams(
@@ -581,11 +696,49 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 			possibleTools,
 		)
 		if err != nil {
-			return task.setErrorStatus("cannot construct params for machine %q: %v", m, err)
+			if err := task.setErrorStatus("cannot construct params for machine %q: %v", m, err); err != nil {
+				return err
+			}
+			continue
 		}
+		starts = append(starts, machineStart{m, pInfo, startInstanceParams})
+	}
 
-		if err := task.startMachine(m, pInfo, startInstanceParams); err != nil {
-			return errors.Annotatef(err, "cannot start machine %v", m)
+	if batcher, ok := task.broker.(environs.InstanceBatcher); ok && len(starts) > 1 {
+		return task.startMachineBatch(batcher, starts)
+	}
+	for _, start := range starts {
+		if err := task.startMachine(start.machine, start.provisioningInfo, start.startInstanceParams); err != nil {
+			return errors.Annotatef(err, "cannot start machine %v", start.machine)
+		}
+	}
+	return nil
+}
+
+// startMachineBatch starts instances for the given machines with a
+// single request to the broker, then records each instance as
+// startMachine does.
+func (task *provisionerTask) startMachineBatch(batcher environs.InstanceBatcher, starts []machineStart) error {
+	args := make([]environs.StartInstanceParams, len(starts))
+	for i, start := range starts {
+		args[i] = start.startInstanceParams
+	}
+	results, errs := batcher.StartInstances(args)
+	for i, start := range starts {
+		if err := errs[i]; err != nil {
+			if instance.IsRetryableCreationError(errors.Cause(err)) {
+				// Retry the machine on its own, as startMachine does.
+				err = task.startMachine(start.machine, start.provisioningInfo, start.startInstanceParams)
+			} else {
+				err = task.setErrorStatus("cannot start instance for machine %q: %v", start.machine, err)
+			}
+			if err != nil {
+				return errors.Annotatef(err, "cannot start machine %v", start.machine)
+			}
+			continue
+		}
+		if err := task.recordInstance(start.machine, start.startInstanceParams, results[i]); err != nil {
+			return errors.Annotatef(err, "cannot start machine %v", start.machine)
 		}
 	}
 	return nil
@@ -653,7 +806,16 @@ func (task *provisionerTask) startMachine(
 			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
 		}
 	}
+	return task.recordInstance(machine, startInstanceParams, result)
+}
 
+// recordInstance records in state the instance started for the given
+// machine with the given params, stopping the instance if that fails.
+func (task *provisionerTask) recordInstance(
+	machine *apiprovisioner.Machine,
+	startInstanceParams environs.StartInstanceParams,
+	result *environs.StartInstanceResult,
+) error {
 	inst := result.Instance
 	hardware := result.Hardware
 	nonce := startInstanceParams.InstanceConfig.MachineNonce
@@ -681,6 +843,11 @@ func (task *provisionerTask) startMachine(
 	}
 	// We need to stop the instance right away here, set error status and go on.
 	task.setErrorStatus("cannot register instance for machine %v: %v", machine, err)
+	if environs.IsDryRun(task.broker) {
+		logger.Warningf("dry run: left instance %q running for machine %v; it must be stopped by hand", inst.Id(), machine)
+		task.abandoned.Add(machine.Id())
+		return nil
+	}
 	if err := task.broker.StopInstances(inst.Id()); err != nil {
 		// We cannot even stop the instance, log the error and quit.
 		return errors.Annotatef(err, "cannot stop instance %q for machine %v", inst.Id(), machine)
@@ -758,7 +925,7 @@ func (task *provisionerTask) blockUntilProvisioned(
 			select {
 			case <-task.tomb.Dying():
 				return nil, tomb.ErrDying
-			case <-time.After(15 * time.Second):
+			case <-task.clock.After(15 * time.Second):
 				continue
 			}
 		}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -42,6 +43,7 @@ import (
 	"github.com/juju/juju/storage/provider/registry"
 	coretesting "github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/provisioner"
 )
@@ -398,7 +400,7 @@ func (s *CommonProvisionerSuite) waitInstanceId(c *gc.C, m *state.Machine, expec
 func (s *CommonProvisionerSuite) newEnvironProvisioner(c *gc.C) provisioner.Provisioner {
 	machineTag := names.NewMachineTag("0")
 	agentConfig := s.AgentConfigForTag(c, machineTag)
-	return provisioner.NewEnvironProvisioner(s.provisioner, agentConfig)
+	return provisioner.NewEnvironProvisioner(s.provisioner, agentConfig, clock.WallClock)
 }
 
 func (s *CommonProvisionerSuite) addMachine() (*state.Machine, error) {
@@ -1162,6 +1164,17 @@ func (s *ProvisionerSuite) newProvisionerTask(
 	machineGetter provisioner.MachineGetter,
 	toolsFinder provisioner.ToolsFinder,
 ) provisioner.ProvisionerTask {
+	return s.newProvisionerTaskWithStopApprover(c, harvestingMethod, nil, broker, machineGetter, toolsFinder)
+}
+
+func (s *ProvisionerSuite) newProvisionerTaskWithStopApprover(
+	c *gc.C,
+	harvestingMethod config.HarvestMode,
+	stopApprover provisioner.StopApprover,
+	broker environs.InstanceBroker,
+	machineGetter provisioner.MachineGetter,
+	toolsFinder provisioner.ToolsFinder,
+) provisioner.ProvisionerTask {
 
 	machineWatcher, err := s.provisioner.WatchEnvironMachines()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1173,6 +1186,7 @@ func (s *ProvisionerSuite) newProvisionerTask(
 	return provisioner.NewProvisionerTask(
 		names.NewMachineTag("0"),
 		harvestingMethod,
+		stopApprover,
 		machineGetter,
 		toolsFinder,
 		machineWatcher,
@@ -1181,6 +1195,7 @@ func (s *ProvisionerSuite) newProvisionerTask(
 		auth,
 		imagemetadata.ReleasedStream,
 		true,
+		clock.WallClock,
 	)
 }
 
@@ -1255,6 +1270,99 @@ func (s *ProvisionerSuite) TestHarvestDestroyedReapsOnlyDestroyed(c *gc.C) {
 	s.waitRemoved(c, m0)
 }
 
+// fakeStopApprover is a provisioner.StopApprover that records
+// requests to stop instances and answers them with err.
+type fakeStopApprover struct {
+	mu       sync.Mutex
+	err      error
+	requests [][]instance.Id
+}
+
+func (a *fakeStopApprover) ApproveStop(ids []instance.Id) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = append(a.requests, ids)
+	return a.err
+}
+
+func (a *fakeStopApprover) setErr(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.err = err
+}
+
+func (s *ProvisionerSuite) TestStopApproverDeniesStop(c *gc.C) {
+	approver := &fakeStopApprover{err: errors.New("change freeze")}
+	task := s.newProvisionerTaskWithStopApprover(
+		c,
+		config.HarvestDestroyed,
+		approver,
+		s.Environ,
+		s.provisioner,
+		mockToolsFinder{},
+	)
+	defer stop(c, task)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+
+	// While stopping is not approved, the instance keeps running
+	// and the dead machine stays in state.
+	c.Assert(m0.EnsureDead(), gc.IsNil)
+	s.checkNoOperations(c)
+	c.Assert(m0.Refresh(), jc.ErrorIsNil)
+	approver.mu.Lock()
+	c.Assert(approver.requests, gc.Not(gc.HasLen), 0)
+	c.Assert(approver.requests[0], jc.DeepEquals, []instance.Id{i0.Id()})
+	approver.mu.Unlock()
+
+	// Once approved, the instance is stopped when the dead machine
+	// is next considered.
+	approver.setErr(nil)
+	task.SetHarvestMode(config.HarvestAll)
+	s.checkStopInstances(c, i0)
+	s.waitRemoved(c, m0)
+}
+
+func (s *ProvisionerSuite) setDryRun(c *gc.C, dryRun bool) {
+	cfg, err := s.Environ.Config().Apply(map[string]interface{}{
+		"dry-run": dryRun,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Environ.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ProvisionerSuite) TestDryRunKeepsDeadMachines(c *gc.C) {
+	task := s.newProvisionerTask(
+		c,
+		config.HarvestDestroyed,
+		s.Environ,
+		s.provisioner,
+		mockToolsFinder{},
+	)
+	defer stop(c, task)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+
+	// In a dry run, the instance keeps running, so the dead
+	// machine stays in state to keep track of it.
+	s.setDryRun(c, true)
+	c.Assert(m0.EnsureDead(), gc.IsNil)
+	s.checkNoOperations(c)
+	c.Assert(m0.Refresh(), jc.ErrorIsNil)
+
+	// Once the dry run is over, the instance is stopped when the
+	// dead machine is next considered.
+	s.setDryRun(c, false)
+	task.SetHarvestMode(config.HarvestAll)
+	s.checkStopInstances(c, i0)
+	s.waitRemoved(c, m0)
+}
+
 func (s *ProvisionerSuite) TestHarvestAllReapsAllTheThings(c *gc.C) {
 
 	task := s.newProvisionerTask(c,
@@ -1342,6 +1450,40 @@ func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 	}
 }
 
+func (s *ProvisionerSuite) TestProvisionerStartsPendingMachinesInBatch(c *gc.C) {
+	var machines []*state.Machine
+	for i := 0; i < 3; i++ {
+		m, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+	broker := &mockBatchBroker{Environ: s.Environ}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	for _, m := range machines {
+		s.checkStartInstance(c, m)
+	}
+	c.Assert(broker.batchSizes, gc.DeepEquals, []int{3})
+}
+
+// mockBatchBroker is a broker that records the number of instances it
+// is asked to start together.
+type mockBatchBroker struct {
+	environs.Environ
+	batchSizes []int
+}
+
+func (b *mockBatchBroker) StartInstances(args []environs.StartInstanceParams) ([]*environs.StartInstanceResult, []error) {
+	b.batchSizes = append(b.batchSizes, len(args))
+	results := make([]*environs.StartInstanceResult, len(args))
+	errs := make([]error, len(args))
+	for i, arg := range args {
+		results[i], errs[i] = b.Environ.StartInstance(arg)
+	}
+	return results, errs
+}
+
 type mockBroker struct {
 	environs.Environ
 	retryCount map[string]int
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+)
+
+// StopApprover decides whether the provisioner may stop instances.
+type StopApprover interface {
+	// ApproveStop returns nil if the instances with the given
+	// ids may be stopped, and an error describing why not
+	// otherwise.
+	ApproveStop(ids []instance.Id) error
+}
+
+// stopApprovalTimeout holds how long the provisioner waits for
+// an instance stop approval service to respond.
+var stopApprovalTimeout = 30 * time.Second
+
+// stopApprovalRequest holds the body of a request sent to an
+// instance stop approval service.
+type stopApprovalRequest struct {
+	EnvironUUID string        `json:"environment-uuid"`
+	InstanceIds []instance.Id `json:"instance-ids"`
+}
+
+// NewHTTPStopApprover returns a StopApprover that asks the service at
+// the given URL to approve each request to stop instances in the
+// environment with the given UUID. The request is POSTed as JSON, and
+// is approved only if the service responds with a 2xx status code.
+func NewHTTPStopApprover(url, envUUID string) StopApprover {
+	return &httpStopApprover{
+		url:     url,
+		envUUID: envUUID,
+		client:  &http.Client{Timeout: stopApprovalTimeout},
+	}
+}
+
+type httpStopApprover struct {
+	url     string
+	envUUID string
+	client  *http.Client
+}
+
+// ApproveStop implements StopApprover.
+func (a *httpStopApprover) ApproveStop(ids []instance.Id) error {
+	body, err := json.Marshal(stopApprovalRequest{
+		EnvironUUID: a.envUUID,
+		InstanceIds: ids,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := a.client.Post(a.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Annotate(err, "cannot request instance stop approval")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	// Include the start of any explanation the service gives.
+	reason, _ := ioutil.ReadAll(&io.LimitedReader{R: resp.Body, N: 1024})
+	if msg := strings.TrimSpace(string(reason)); msg != "" {
+		return errors.Errorf("instance stop not approved: %s: %s", resp.Status, msg)
+	}
+	return errors.Errorf("instance stop not approved: %s", resp.Status)
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type stopApproverSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&stopApproverSuite{})
+
+func (s *stopApproverSuite) TestApproveStop(c *gc.C) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, gc.Equals, "POST")
+		c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+		c.Check(json.NewDecoder(req.Body).Decode(&received), jc.ErrorIsNil)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	approver := provisioner.NewHTTPStopApprover(server.URL, coretesting.EnvironmentTag.Id())
+	err := approver.ApproveStop([]instance.Id{"i-0", "i-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(received, jc.DeepEquals, map[string]interface{}{
+		"environment-uuid": coretesting.EnvironmentTag.Id(),
+		"instance-ids":     []interface{}{"i-0", "i-1"},
+	})
+}
+
+func (s *stopApproverSuite) TestApproveStopDenied(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "change freeze in effect", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	approver := provisioner.NewHTTPStopApprover(server.URL, coretesting.EnvironmentTag.Id())
+	err := approver.ApproveStop([]instance.Id{"i-0"})
+	c.Assert(err, gc.ErrorMatches, "instance stop not approved: 403 Forbidden: change freeze in effect")
+}
+
+func (s *stopApproverSuite) TestApproveStopUnreachable(c *gc.C) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	approver := provisioner.NewHTTPStopApprover(url, coretesting.EnvironmentTag.Id())
+	err := approver.ApproveStop([]instance.Id{"i-0"})
+	c.Assert(err, gc.ErrorMatches, "cannot request instance stop approval: .*")
+}
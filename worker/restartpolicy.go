@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package worker
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestartPolicy describes how a Runner restarts a worker after
+// it exits with an error.
+type RestartPolicy struct {
+	// Delay holds the time to wait before restarting the worker.
+	Delay time.Duration
+
+	// Factor holds the amount by which the restart delay is
+	// multiplied each time the worker exits rapidly in succession.
+	// Values less than or equal to 1 give a fixed delay.
+	Factor float64
+
+	// MaxDelay holds the upper bound on the restart delay.
+	// If it is zero, the delay is not bounded.
+	MaxDelay time.Duration
+
+	// MinRunTime holds the time a worker must run for before its
+	// exit is no longer considered rapid. Once a worker has run
+	// for this long, its restart delay is reset to Delay.
+	MinRunTime time.Duration
+
+	// MaxFailures holds the number of rapid exits in succession
+	// after which the worker is recorded as failed. A failed worker
+	// is still restarted, at the longest delay the policy allows,
+	// and is no longer recorded as failed once it has run for
+	// MinRunTime. If it is zero, the worker is never recorded as
+	// failed.
+	MaxFailures int
+}
+
+// DefaultRestartPolicy returns the policy used for workers started
+// with StartWorker: they are restarted after RestartDelay, however
+// often they fail.
+func DefaultRestartPolicy() RestartPolicy {
+	return RestartPolicy{Delay: RestartDelay}
+}
+
+// Validate returns an error if the policy is not valid.
+func (p RestartPolicy) Validate() error {
+	switch {
+	case p.Delay < 0:
+		return fmt.Errorf("negative restart delay %v", p.Delay)
+	case p.MaxDelay < 0:
+		return fmt.Errorf("negative maximum restart delay %v", p.MaxDelay)
+	case p.MinRunTime < 0:
+		return fmt.Errorf("negative minimum run time %v", p.MinRunTime)
+	case p.MaxFailures < 0:
+		return fmt.Errorf("negative maximum failures %d", p.MaxFailures)
+	}
+	return nil
+}
+
+// restartDelay returns the time to wait before restarting a worker
+// that has exited rapidly the given number of times in succession.
+func (p RestartPolicy) restartDelay(failures int) time.Duration {
+	delay := float64(p.Delay)
+	if p.Factor > 1 {
+		for i := 1; i < failures; i++ {
+			delay *= p.Factor
+			if p.MaxDelay > 0 && delay >= float64(p.MaxDelay) {
+				break
+			}
+		}
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// failed reports whether a worker that has exited rapidly the
+// given number of times in succession should be recorded as failed.
+func (p RestartPolicy) failed(failures int) bool {
+	return p.MaxFailures > 0 && failures >= p.MaxFailures
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package worker_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+)
+
+type restartPolicySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restartPolicySuite{})
+
+func (*restartPolicySuite) TestDefaultRestartPolicy(c *gc.C) {
+	policy := worker.DefaultRestartPolicy()
+	c.Assert(policy, jc.DeepEquals, worker.RestartPolicy{Delay: worker.RestartDelay})
+	c.Assert(worker.RestartPolicyDelay(policy, 10), gc.Equals, worker.RestartDelay)
+}
+
+func (*restartPolicySuite) TestRestartDelay(c *gc.C) {
+	policy := worker.RestartPolicy{
+		Delay:    time.Second,
+		Factor:   2,
+		MaxDelay: 10 * time.Second,
+	}
+	for failures, expect := range []time.Duration{
+		time.Second,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	} {
+		c.Check(worker.RestartPolicyDelay(policy, failures), gc.Equals, expect, gc.Commentf("failures %d", failures))
+	}
+}
+
+func (*restartPolicySuite) TestRestartDelayUnbounded(c *gc.C) {
+	policy := worker.RestartPolicy{
+		Delay:  time.Second,
+		Factor: 3,
+	}
+	c.Assert(worker.RestartPolicyDelay(policy, 4), gc.Equals, 27*time.Second)
+}
+
+func (*restartPolicySuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		policy worker.RestartPolicy
+		err    string
+	}{{
+		policy: worker.RestartPolicy{Delay: time.Second, MinRunTime: time.Minute, MaxFailures: 5},
+	}, {
+		policy: worker.RestartPolicy{Delay: -time.Second},
+		err:    "negative restart delay -1s",
+	}, {
+		policy: worker.RestartPolicy{MaxDelay: -time.Second},
+		err:    "negative maximum restart delay -1s",
+	}, {
+		policy: worker.RestartPolicy{MinRunTime: -time.Second},
+		err:    "negative minimum run time -1s",
+	}, {
+		policy: worker.RestartPolicy{MaxFailures: -1},
+		err:    "negative maximum failures -1",
+	}} {
+		c.Logf("test %d", i)
+		err := test.policy.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
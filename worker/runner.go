@@ -5,6 +5,7 @@ package worker
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"launchpad.net/tomb"
@@ -31,6 +32,47 @@ type Runner interface {
 	StopWorker(id string) error
 }
 
+// PolicyRunner is implemented by runners that can restart each
+// worker according to its own RestartPolicy.
+type PolicyRunner interface {
+	Runner
+
+	// StartWorkerWithPolicy is like StartWorker, but restarts the
+	// worker according to the given policy rather than the default
+	// one.
+	StartWorkerWithPolicy(id string, policy RestartPolicy, startFunc func() (Worker, error)) error
+
+	// FailedWorkers returns the last error returned by each worker
+	// that has exited rapidly more often in succession than its
+	// restart policy allows, and has not run for long since.
+	FailedWorkers() map[string]error
+
+	// WorkerStatus returns the status of each worker known to the
+	// runner, including those that have failed.
+	WorkerStatus() map[string]WorkerStatus
+}
+
+// WorkerStatus describes the state of a worker managed by a runner.
+type WorkerStatus struct {
+	// Running holds whether the worker is currently running.
+	Running bool
+
+	// Restarts holds the number of times the worker has been
+	// restarted since it was last started with StartWorker.
+	Restarts int
+
+	// LastError holds the error the worker last exited with, if any.
+	LastError error
+
+	// LastErrorTime holds when the worker last exited with an error.
+	LastErrorTime time.Time
+
+	// Failed holds whether the worker has exited rapidly more often
+	// in succession than its restart policy allows. The runner keeps
+	// restarting a failed worker.
+	Failed bool
+}
+
 // runner runs a set of workers, restarting them as necessary
 // when they fail.
 type runner struct {
@@ -39,20 +81,31 @@ type runner struct {
 	stopc         chan string
 	donec         chan doneInfo
 	startedc      chan startInfo
+	recoveredc    chan startInfo
 	isFatal       func(error) bool
 	moreImportant func(err0, err1 error) bool
+
+	// policy holds the restart policy of workers started
+	// with StartWorker.
+	policy RestartPolicy
+
+	// mu guards status.
+	mu     sync.Mutex
+	status map[string]*WorkerStatus
 }
 
-var _ Runner = (*runner)(nil)
+var _ PolicyRunner = (*runner)(nil)
 
 type startReq struct {
-	id    string
-	start func() (Worker, error)
+	id     string
+	policy RestartPolicy
+	start  func() (Worker, error)
 }
 
 type startInfo struct {
 	id     string
 	worker Worker
+	starts int
 }
 
 type doneInfo struct {
@@ -71,13 +124,26 @@ type doneInfo struct {
 // function moreImportant(err0, err1) returns whether err0 is considered
 // more important than err1.
 func NewRunner(isFatal func(error) bool, moreImportant func(err0, err1 error) bool) Runner {
+	return NewRunnerWithPolicy(isFatal, moreImportant, DefaultRestartPolicy())
+}
+
+// NewRunnerWithPolicy is like NewRunner, but workers started with
+// StartWorker are restarted according to the given policy rather
+// than the default one. It panics if the policy is not valid.
+func NewRunnerWithPolicy(isFatal func(error) bool, moreImportant func(err0, err1 error) bool, policy RestartPolicy) PolicyRunner {
+	if err := policy.Validate(); err != nil {
+		panic(err)
+	}
 	runner := &runner{
 		startc:        make(chan startReq),
 		stopc:         make(chan string),
 		donec:         make(chan doneInfo),
 		startedc:      make(chan startInfo),
+		recoveredc:    make(chan startInfo),
 		isFatal:       isFatal,
 		moreImportant: moreImportant,
+		policy:        policy,
+		status:        make(map[string]*WorkerStatus),
 	}
 	go func() {
 		defer runner.tomb.Done()
@@ -90,15 +156,30 @@ var ErrDead = errors.New("worker runner is not running")
 
 // StartWorker starts a worker running associated with the given id.
 // The startFunc function will be called to create the worker;
-// when the worker exits, it will be restarted as long as it
-// does not return a fatal error.
+// when the worker exits, it will be restarted according to the
+// runner's restart policy as long as it does not return a fatal error.
 //
 // If there is already a worker with the given id, nothing will be done.
 //
 // StartWorker returns ErrDead if the runner is not running.
 func (runner *runner) StartWorker(id string, startFunc func() (Worker, error)) error {
+	return runner.StartWorkerWithPolicy(id, runner.policy, startFunc)
+}
+
+// StartWorkerWithPolicy starts a worker running associated with the
+// given id, restarting it according to the given policy. If the
+// worker exits rapidly more often in succession than the policy
+// allows, it is reported by FailedWorkers, and restarted at the
+// longest delay the policy allows, until it next runs for the
+// policy's MinRunTime.
+//
+// StartWorkerWithPolicy returns ErrDead if the runner is not running.
+func (runner *runner) StartWorkerWithPolicy(id string, policy RestartPolicy, startFunc func() (Worker, error)) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
 	select {
-	case runner.startc <- startReq{id, startFunc}:
+	case runner.startc <- startReq{id, policy, startFunc}:
 		return nil
 	case <-runner.tomb.Dead():
 	}
@@ -118,6 +199,50 @@ func (runner *runner) StopWorker(id string) error {
 	return ErrDead
 }
 
+// FailedWorkers implements PolicyRunner.FailedWorkers.
+func (runner *runner) FailedWorkers() map[string]error {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	failed := make(map[string]error)
+	for id, status := range runner.status {
+		if status.Failed {
+			failed[id] = status.LastError
+		}
+	}
+	return failed
+}
+
+// WorkerStatus implements PolicyRunner.WorkerStatus.
+func (runner *runner) WorkerStatus() map[string]WorkerStatus {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	result := make(map[string]WorkerStatus, len(runner.status))
+	for id, status := range runner.status {
+		result[id] = *status
+	}
+	return result
+}
+
+// updateStatus calls f with the status of the worker with the
+// given id, creating it if necessary.
+func (runner *runner) updateStatus(id string, f func(status *WorkerStatus)) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	status := runner.status[id]
+	if status == nil {
+		status = &WorkerStatus{}
+		runner.status[id] = status
+	}
+	f(status)
+}
+
+// resetStatus forgets the status of the worker with the given id.
+func (runner *runner) resetStatus(id string) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	delete(runner.status, id)
+}
+
 func (runner *runner) Wait() error {
 	return runner.tomb.Wait()
 }
@@ -134,10 +259,14 @@ func Stop(worker Worker) error {
 }
 
 type workerInfo struct {
-	start        func() (Worker, error)
-	worker       Worker
-	restartDelay time.Duration
-	stopping     bool
+	start      func() (Worker, error)
+	worker     Worker
+	policy     RestartPolicy
+	started    time.Time
+	starts     int
+	failures   int
+	restartNow bool
+	stopping   bool
 }
 
 func (runner *runner) run() error {
@@ -170,9 +299,10 @@ func (runner *runner) run() error {
 			}
 			info := workers[req.id]
 			if info == nil {
+				runner.resetStatus(req.id)
 				workers[req.id] = &workerInfo{
-					start:        req.start,
-					restartDelay: RestartDelay,
+					start:  req.start,
+					policy: req.policy,
 				}
 				go runner.runWorker(0, req.id, req.start)
 				break
@@ -186,7 +316,9 @@ func (runner *runner) run() error {
 			// does stop, we'll restart it immediately with
 			// the new start function.
 			info.start = req.start
-			info.restartDelay = 0
+			info.policy = req.policy
+			info.failures = 0
+			info.restartNow = true
 		case id := <-runner.stopc:
 			if info := workers[id]; info != nil {
 				killWorker(id, info)
@@ -194,13 +326,42 @@ func (runner *runner) run() error {
 		case info := <-runner.startedc:
 			workerInfo := workers[info.id]
 			workerInfo.worker = info.worker
+			workerInfo.started = time.Now()
+			workerInfo.starts++
+			runner.updateStatus(info.id, func(status *WorkerStatus) {
+				status.Running = true
+			})
 			if isDying {
 				killWorker(info.id, workerInfo)
+			} else if workerInfo.policy.failed(workerInfo.failures) {
+				info.starts = workerInfo.starts
+				go runner.watchRecovery(info, workerInfo.policy.MinRunTime)
+			}
+		case info := <-runner.recoveredc:
+			// A failed worker has now run for long enough to be
+			// considered healthy again, unless it has since been
+			// restarted or stopped.
+			workerInfo := workers[info.id]
+			if workerInfo == nil || workerInfo.stopping || workerInfo.starts != info.starts {
+				break
 			}
+			logger.Infof("%q has recovered", info.id)
+			workerInfo.failures = 0
+			runner.updateStatus(info.id, func(status *WorkerStatus) {
+				status.Failed = false
+			})
 		case info := <-runner.donec:
 			workerInfo := workers[info.id]
+			runner.updateStatus(info.id, func(status *WorkerStatus) {
+				status.Running = false
+				if info.err != nil {
+					status.LastError = info.err
+					status.LastErrorTime = time.Now()
+				}
+			})
 			if !workerInfo.stopping && info.err == nil {
 				delete(workers, info.id)
+				runner.resetStatus(info.id)
 				break
 			}
 			if info.err != nil {
@@ -210,6 +371,7 @@ func (runner *runner) run() error {
 						finalError = info.err
 					}
 					delete(workers, info.id)
+					runner.resetStatus(info.id)
 					if !isDying {
 						isDying = true
 						killAll(workers)
@@ -223,14 +385,59 @@ func (runner *runner) run() error {
 				// The worker has been deliberately stopped;
 				// we can now remove it from the list of workers.
 				delete(workers, info.id)
+				runner.resetStatus(info.id)
 				break
 			}
-			go runner.runWorker(workerInfo.restartDelay, info.id, workerInfo.start)
-			workerInfo.restartDelay = RestartDelay
+			var delay time.Duration
+			if workerInfo.restartNow {
+				workerInfo.restartNow = false
+				runner.resetStatus(info.id)
+			} else {
+				if workerInfo.ranFor() < workerInfo.policy.MinRunTime {
+					workerInfo.failures++
+				} else {
+					workerInfo.failures = 0
+				}
+				delay = workerInfo.policy.restartDelay(workerInfo.failures)
+				failed := workerInfo.policy.failed(workerInfo.failures)
+				if failed {
+					logger.Errorf("%q exited %d times in quick succession; restarting in %v", info.id, workerInfo.failures, delay)
+				}
+				runner.updateStatus(info.id, func(status *WorkerStatus) {
+					status.Restarts++
+					status.Failed = failed
+				})
+			}
+			workerInfo.started = time.Time{}
+			go runner.runWorker(delay, info.id, workerInfo.start)
 		}
 	}
 }
 
+// ranFor returns how long the worker ran for before exiting,
+// or zero if it never started.
+func (info *workerInfo) ranFor() time.Duration {
+	if info.started.IsZero() {
+		return 0
+	}
+	return time.Since(info.started)
+}
+
+// watchRecovery tells the runner when the given worker start has
+// run for minRunTime, so that it can stop considering the worker
+// failed.
+func (runner *runner) watchRecovery(info startInfo, minRunTime time.Duration) {
+	select {
+	case <-time.After(minRunTime):
+	case <-runner.tomb.Dying():
+		return
+	}
+	select {
+	case runner.recoveredc <- info:
+	case <-runner.tomb.Dying():
+	}
+}
+
 func killAll(workers map[string]*workerInfo) {
 	for id, info := range workers {
 		killWorker(id, info)
@@ -261,7 +468,7 @@ func (runner *runner) runWorker(delay time.Duration, id string, start func() (Wo
 	logger.Infof("start %q", id)
 	worker, err := start()
 	if err == nil {
-		runner.startedc <- startInfo{id, worker}
+		runner.startedc <- startInfo{id: id, worker: worker}
 		err = worker.Wait()
 	}
 	runner.donec <- doneInfo{id, err}
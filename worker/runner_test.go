@@ -176,6 +176,210 @@ func (*runnerSuite) TestOneWorkerRestartDelay(c *gc.C) {
 	c.Assert(worker.Stop(runner), gc.IsNil)
 }
 
+func (*runnerSuite) TestStartWorkerWithInvalidPolicy(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance).(worker.PolicyRunner)
+	defer worker.Stop(runner)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{MaxFailures: -1}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, gc.ErrorMatches, "negative maximum failures -1")
+	starter.assertNeverStarted(c)
+}
+
+func (*runnerSuite) TestOneWorkerFailedAfterRapidFailures(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance).(worker.PolicyRunner)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{
+		MinRunTime:  time.Minute,
+		MaxFailures: 3,
+	}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	dieErr := fmt.Errorf("permanently broken")
+	for i := 0; i < 3; i++ {
+		starter.assertStarted(c, true)
+		c.Assert(runner.FailedWorkers(), gc.HasLen, 0)
+		starter.die <- dieErr
+		starter.assertStarted(c, false)
+	}
+	// The failed worker is still restarted.
+	starter.assertStarted(c, true)
+	c.Assert(runner.FailedWorkers(), jc.DeepEquals, map[string]error{"id": dieErr})
+
+	// Starting the worker afresh clears its failure.
+	err = runner.StopWorker("id")
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, false)
+	err = runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	c.Assert(runner.FailedWorkers(), gc.HasLen, 0)
+	c.Assert(worker.Stop(runner), gc.IsNil)
+}
+
+func (*runnerSuite) TestFailedWorkerRecovers(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance).(worker.PolicyRunner)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{
+		MinRunTime:  500 * time.Millisecond,
+		MaxFailures: 2,
+	}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	dieErr := fmt.Errorf("temporarily broken")
+	for i := 0; i < 2; i++ {
+		starter.assertStarted(c, true)
+		starter.die <- dieErr
+		starter.assertStarted(c, false)
+	}
+	starter.assertStarted(c, true)
+	c.Assert(runner.FailedWorkers(), jc.DeepEquals, map[string]error{"id": dieErr})
+
+	// Once the restarted worker has run for long enough, it is no
+	// longer considered failed.
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		if len(runner.FailedWorkers()) == 0 {
+			break
+		}
+	}
+	c.Assert(runner.FailedWorkers(), gc.HasLen, 0)
+	c.Assert(runner.WorkerStatus()["id"].Failed, jc.IsFalse)
+	c.Assert(worker.Stop(runner), gc.IsNil)
+}
+
+func (*runnerSuite) TestStartWorkerUsesRunnerPolicy(c *gc.C) {
+	policy := worker.RestartPolicy{
+		MinRunTime:  time.Minute,
+		MaxFailures: 2,
+	}
+	runner := worker.NewRunnerWithPolicy(noneFatal, noImportance, policy)
+	starter := newTestWorkerStarter()
+	err := runner.StartWorker("id", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	dieErr := fmt.Errorf("permanently broken")
+	for i := 0; i < 2; i++ {
+		starter.assertStarted(c, true)
+		starter.die <- dieErr
+		starter.assertStarted(c, false)
+	}
+	starter.assertStarted(c, true)
+	c.Assert(runner.FailedWorkers(), jc.DeepEquals, map[string]error{"id": dieErr})
+	c.Assert(worker.Stop(runner), gc.IsNil)
+}
+
+func (*runnerSuite) TestNewRunnerWithInvalidPolicy(c *gc.C) {
+	policy := worker.RestartPolicy{Delay: -time.Second}
+	c.Assert(func() {
+		worker.NewRunnerWithPolicy(noneFatal, noImportance, policy)
+	}, gc.PanicMatches, "negative restart delay -1s")
+}
+
+func (*runnerSuite) TestWorkerStatus(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance).(worker.PolicyRunner)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{
+		MinRunTime:  time.Minute,
+		MaxFailures: 3,
+	}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	dieErr := fmt.Errorf("permanently broken")
+	for i := 0; i < 3; i++ {
+		starter.assertStarted(c, true)
+		starter.die <- dieErr
+		starter.assertStarted(c, false)
+	}
+	starter.assertStarted(c, true)
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		if runner.WorkerStatus()["id"].Running {
+			break
+		}
+	}
+	status := runner.WorkerStatus()
+	c.Assert(status, gc.HasLen, 1)
+	c.Assert(status["id"].LastErrorTime.IsZero(), jc.IsFalse)
+	status["id"] = worker.WorkerStatus{
+		Running:   status["id"].Running,
+		Restarts:  status["id"].Restarts,
+		LastError: status["id"].LastError,
+		Failed:    status["id"].Failed,
+	}
+	c.Assert(status, jc.DeepEquals, map[string]worker.WorkerStatus{
+		"id": {Running: true, Restarts: 3, LastError: dieErr, Failed: true},
+	})
+
+	// Starting the worker afresh resets its status.
+	err = runner.StopWorker("id")
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, false)
+	err = runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		if runner.WorkerStatus()["id"].Running {
+			break
+		}
+	}
+	c.Assert(runner.WorkerStatus(), jc.DeepEquals, map[string]worker.WorkerStatus{
+		"id": {Running: true},
+	})
+
+	// Stopping the worker forgets it.
+	err = runner.StopWorker("id")
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, false)
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		if len(runner.WorkerStatus()) == 0 {
+			break
+		}
+	}
+	c.Assert(runner.WorkerStatus(), gc.HasLen, 0)
+	c.Assert(worker.Stop(runner), gc.IsNil)
+}
+
+func (*runnerSuite) TestOneWorkerNotFailedAfterSlowFailures(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance).(worker.PolicyRunner)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{
+		MaxFailures: 1,
+	}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 3; i++ {
+		starter.assertStarted(c, true)
+		starter.die <- fmt.Errorf("transient error")
+		starter.assertStarted(c, false)
+	}
+	starter.assertStarted(c, true)
+	c.Assert(runner.FailedWorkers(), gc.HasLen, 0)
+	c.Assert(worker.Stop(runner), gc.IsNil)
+}
+
+func (*runnerSuite) TestOneWorkerRestartBackoff(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance).(worker.PolicyRunner)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{
+		Delay:      50 * time.Millisecond,
+		Factor:     4,
+		MinRunTime: time.Minute,
+	}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	starter.die <- fmt.Errorf("non-fatal error")
+	starter.assertStarted(c, false)
+	starter.assertStarted(c, true)
+	starter.die <- fmt.Errorf("non-fatal error")
+	starter.assertStarted(c, false)
+	t0 := time.Now()
+	starter.assertStarted(c, true)
+	restartDuration := time.Since(t0)
+	if restartDuration < 200*time.Millisecond {
+		c.Fatalf("restart delay did not back off; got %v want %v", restartDuration, 200*time.Millisecond)
+	}
+	c.Assert(worker.Stop(runner), gc.IsNil)
+}
+
 type errorLevel int
 
 func (e errorLevel) Error() string {
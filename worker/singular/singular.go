@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/worker"
@@ -17,6 +18,10 @@ var logger = loggo.GetLogger("juju.worker.singular")
 
 var PingInterval = 10 * time.Second
 
+// ErrMasterChanged is returned by workers started on a master runner
+// when the connection is found to no longer be held by the master.
+var ErrMasterChanged = errors.New("master status changed")
+
 type runner struct {
 	pingErr         error
 	pingerDied      chan struct{}
@@ -43,12 +48,16 @@ type Conn interface {
 // run the workers or not.
 //
 // If conn.IsMaster returns true, any workers started will be started on the
-// underlying runner.
+// underlying runner. The connection is then pinged periodically and its
+// master status rechecked; if either fails, the workers are stopped and
+// exit with the error, so that the workers are handed off cleanly to the
+// new master rather than running in two places at once.
 //
 // If conn.IsMaster returns false, any workers started will actually
-// start do-nothing placeholder workers on the underlying runner
-// that continually ping the connection until a ping fails and then exit
-// with that error.
+// start do-nothing placeholder workers on the underlying runner that
+// continually ping the connection and recheck its master status, and
+// exit when a ping fails or the connection becomes master, so that
+// the real workers can be started in their place.
 func New(underlying worker.Runner, conn Conn) (worker.Runner, error) {
 	isMaster, err := conn.IsMaster()
 	if err != nil {
@@ -75,7 +84,7 @@ func (r *runner) pinger() {
 	}()
 	timer := time.NewTimer(0)
 	for {
-		if err := r.conn.Ping(); err != nil {
+		if err := r.ping(); err != nil {
 			// The ping has failed: cause all other workers
 			// to exit with the ping error.
 			logger.Infof("pinger has died: %v", err)
@@ -92,26 +101,73 @@ func (r *runner) pinger() {
 	}
 }
 
+// ping pings the connection and checks that its master status has
+// not changed since the runner was created, whether it was master
+// then or not.
+func (r *runner) ping() error {
+	if err := r.conn.Ping(); err != nil {
+		return err
+	}
+	isMaster, err := r.conn.IsMaster()
+	if err != nil {
+		return errors.Annotate(err, "cannot get master status")
+	}
+	if isMaster != r.isMaster {
+		return ErrMasterChanged
+	}
+	return nil
+}
+
 func (r *runner) StartWorker(id string, startFunc func() (worker.Worker, error)) error {
+	// Whether or not we are master, start a pinger so that we know
+	// when the connection master changes.
+	r.startPingerOnce.Do(func() {
+		go r.pinger()
+	})
 	if r.isMaster {
-		// We are master; the started workers should
-		// encounter an error as they do what they're supposed
-		// to do - we can just start the worker in the
-		// underlying runner.
+		// We are master; start the worker in the underlying
+		// runner, but stop it if we lose mastership so that
+		// the new master can take over.
 		logger.Infof("starting %q", id)
-		return r.Runner.StartWorker(id, startFunc)
+		return r.Runner.StartWorker(id, func() (worker.Worker, error) {
+			w, err := startFunc()
+			if err != nil {
+				return nil, err
+			}
+			return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+				return r.runMaster(w, stop)
+			}), nil
+		})
 	}
 	logger.Infof("standby %q", id)
-	// We're not master, so don't start the worker, but start a pinger so
-	// that we know when the connection master changes.
-	r.startPingerOnce.Do(func() {
-		go r.pinger()
-	})
+	// We're not master, so don't start the worker; the placeholder
+	// exits when the pinger does.
 	return r.Runner.StartWorker(id, func() (worker.Worker, error) {
 		return worker.NewSimpleWorker(r.waitPinger), nil
 	})
 }
 
+// runMaster waits for the given worker to exit, stopping it early
+// if stop is closed or the pinger dies.
+func (r *runner) runMaster(w worker.Worker, stop <-chan struct{}) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-stop:
+		w.Kill()
+		return <-done
+	case <-r.pingerDied:
+		logger.Infof("handing off worker: %v", r.pingErr)
+		w.Kill()
+		<-done
+		return r.pingErr
+	}
+}
+
 func (r *runner) waitPinger(stop <-chan struct{}) error {
 	select {
 	case <-stop:
@@ -66,6 +66,43 @@ func (s *singularSuite) TestWithIsMasterTrue(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *singularSuite) TestWithIsMasterTrueHandsOff(c *gc.C) {
+	// When we are master and lose mastership, the workers
+	// are stopped and exit with ErrMasterChanged.
+	s.PatchValue(&singular.PingInterval, testing.ShortWait/10)
+	underlyingRunner := newRunner()
+	conn := &fakeConn{
+		isMaster: true,
+	}
+	r, err := singular.New(underlyingRunner, conn)
+	c.Assert(err, jc.ErrorIsNil)
+
+	started := make(chan struct{}, 1)
+	stopped := make(chan struct{}, 1)
+	err = r.StartWorker("worker", func() (worker.Worker, error) {
+		return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+			started <- struct{}{}
+			<-stop
+			stopped <- struct{}{}
+			return nil
+		}), nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-started:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for worker to start")
+	}
+
+	conn.setMaster(false)
+	select {
+	case <-stopped:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for worker to stop")
+	}
+	worker.Stop(r)
+}
+
 var errFatal = fmt.Errorf("fatal error")
 
 func (s *singularSuite) TestWithIsMasterFalse(c *gc.C) {
@@ -130,6 +167,36 @@ loop2:
 	}
 }
 
+func (s *singularSuite) TestWithIsMasterFalseTakesOver(c *gc.C) {
+	// When we are not master and become master, the placeholder
+	// workers exit with ErrMasterChanged so that the real workers
+	// can be started in their place.
+	s.PatchValue(&singular.PingInterval, testing.ShortWait/10)
+	underlyingRunner := worker.NewRunner(
+		func(err error) bool {
+			return err == singular.ErrMasterChanged
+		},
+		func(err0, err1 error) bool { return true },
+	)
+	conn := &fakeConn{
+		isMaster: false,
+	}
+	r, err := singular.New(underlyingRunner, conn)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = r.StartWorker("worker", func() (worker.Worker, error) {
+		c.Errorf("worker unexpectedly started")
+		return nil, fmt.Errorf("no worker")
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	conn.setMaster(true)
+	runWithTimeout(c, "wait for underlying runner", func() {
+		err = underlyingRunner.Wait()
+	})
+	c.Assert(err, gc.Equals, singular.ErrMasterChanged)
+}
+
 func (s *singularSuite) TestPingCalledOnceOnlyForSeveralWorkers(c *gc.C) {
 	// Patch the ping interval to a large value, start several workers
 	// and check that Ping is only called once.
@@ -186,9 +253,17 @@ type fakeConn struct {
 }
 
 func (c *fakeConn) IsMaster() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.isMaster, c.isMasterErr
 }
 
+func (c *fakeConn) setMaster(isMaster bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isMaster = isMaster
+}
+
 func (c *fakeConn) Ping() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
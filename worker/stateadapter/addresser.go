@@ -0,0 +1,42 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package stateadapter holds the narrow interfaces through which
+// workers access the environment, together with implementations
+// backed by *state.State and by the API. A worker written against
+// these interfaces can move from direct state access to API access
+// by changing only the adapter it is started with.
+package stateadapter
+
+import (
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// Addresser defines the methods used by the addresser worker.
+type Addresser interface {
+	EnvironConfig() (*config.Config, error)
+	IPAddress(value string) (IPAddress, error)
+	Machine(id string) (Machine, error)
+	WatchIPAddresses() (watcher.StringsWatcher, error)
+}
+
+// IPAddress defines the IP address methods used by the addresser
+// worker. Its life is reported as the API does, so that the interface
+// does not tie workers to the state package.
+type IPAddress interface {
+	Value() string
+	Life() params.Life
+	MachineId() string
+	SubnetId() string
+	Address() network.Address
+	Remove() error
+}
+
+// Machine defines the machine methods used by the addresser worker.
+type Machine interface {
+	InstanceId() (instance.Id, error)
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateadapter
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/addresser"
+	"github.com/juju/juju/instance"
+)
+
+// This file holds the implementations of the adapter interfaces
+// that go through the API.
+
+// NewAPIAddresser returns an Addresser backed by the given
+// Addresser API facade.
+func NewAPIAddresser(api *addresser.API) Addresser {
+	return &apiAddresser{api}
+}
+
+type apiAddresser struct {
+	*addresser.API
+}
+
+// IPAddress is part of the Addresser interface.
+func (a *apiAddresser) IPAddress(value string) (IPAddress, error) {
+	addr, err := a.API.IPAddress(value)
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+// Machine is part of the Addresser interface. The instance id is
+// fetched straight away, so that a missing machine is reported here
+// as it is by state; any other error is returned by InstanceId.
+func (a *apiAddresser) Machine(id string) (Machine, error) {
+	instId, err := a.API.InstanceId(id)
+	if errors.IsNotFound(err) {
+		return nil, err
+	}
+	return &apiMachine{instId, err}, nil
+}
+
+type apiMachine struct {
+	instanceId instance.Id
+	err        error
+}
+
+// InstanceId is part of the Machine interface.
+func (m *apiMachine) InstanceId() (instance.Id, error) {
+	return m.instanceId, m.err
+}
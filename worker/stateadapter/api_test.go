@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateadapter_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/addresser"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/stateadapter"
+)
+
+type apiAddresserSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&apiAddresserSuite{})
+
+func (s *apiAddresserSuite) TestIPAddressLife(c *gc.C) {
+	for i, life := range []params.Life{params.Alive, params.Dying, params.Dead} {
+		c.Logf("test %d: %s", i, life)
+		apiCaller := basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, response interface{},
+			) error {
+				results := response.(*params.IPAddressResults)
+				results.Results = []params.IPAddressResult{{
+					Result: &params.IPAddressInfo{Value: "0.1.2.3", Life: life},
+				}}
+				return nil
+			})
+		st := stateadapter.NewAPIAddresser(addresser.NewAPI(apiCaller))
+		addr, err := st.IPAddress("0.1.2.3")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(addr.Life(), gc.Equals, life)
+	}
+}
+
+func (s *apiAddresserSuite) TestMachine(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			results := response.(*params.StringResults)
+			results.Results = []params.StringResult{{Result: "i-0"}}
+			return nil
+		})
+	st := stateadapter.NewAPIAddresser(addresser.NewAPI(apiCaller))
+	m, err := st.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	instId, err := m.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("i-0"))
+}
+
+func (s *apiAddresserSuite) TestMachineNotFound(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			results := response.(*params.StringResults)
+			results.Results = []params.StringResult{{
+				Error: &params.Error{Code: params.CodeNotFound, Message: "machine 0 not found"},
+			}}
+			return nil
+		})
+	st := stateadapter.NewAPIAddresser(addresser.NewAPI(apiCaller))
+	_, err := st.Machine("0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *apiAddresserSuite) TestMachineNotProvisioned(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			results := response.(*params.StringResults)
+			results.Results = []params.StringResult{{
+				Error: &params.Error{Code: params.CodeNotProvisioned, Message: "machine 0 not provisioned"},
+			}}
+			return nil
+		})
+	st := stateadapter.NewAPIAddresser(addresser.NewAPI(apiCaller))
+	m, err := st.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = m.InstanceId()
+	c.Assert(err, gc.ErrorMatches, "machine 0 not provisioned")
+}
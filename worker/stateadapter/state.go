@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateadapter
+
+import (
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// This file holds the implementations of the adapter interfaces
+// that use *state.State directly.
+
+// NewStateAddresser returns an Addresser backed by the given state.
+func NewStateAddresser(st *state.State) Addresser {
+	return &stateAddresser{st}
+}
+
+type stateAddresser struct {
+	*state.State
+}
+
+// IPAddress is part of the Addresser interface.
+func (s *stateAddresser) IPAddress(value string) (IPAddress, error) {
+	addr, err := s.State.IPAddress(value)
+	if err != nil {
+		return nil, err
+	}
+	return &stateIPAddress{addr}, nil
+}
+
+// Machine is part of the Addresser interface.
+func (s *stateAddresser) Machine(id string) (Machine, error) {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WatchIPAddresses is part of the Addresser interface.
+func (s *stateAddresser) WatchIPAddresses() (watcher.StringsWatcher, error) {
+	return s.State.WatchIPAddresses(), nil
+}
+
+type stateIPAddress struct {
+	*state.IPAddress
+}
+
+// Life is part of the IPAddress interface.
+func (a *stateIPAddress) Life() params.Life {
+	return params.Life(a.IPAddress.Life().String())
+}
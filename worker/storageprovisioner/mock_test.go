@@ -389,10 +389,6 @@ func (m *mockLifecycleManager) AttachmentLife(ids []params.MachineStorageId) ([]
 	return result, nil
 }
 
-func (m *mockLifecycleManager) EnsureDead([]names.Tag) ([]params.ErrorResult, error) {
-	return nil, nil
-}
-
 func (m *mockLifecycleManager) Remove([]names.Tag) ([]params.ErrorResult, error) {
 	return nil, nil
 }
@@ -106,10 +106,6 @@ type LifecycleManager interface {
 	// Life returns the lifecycle state of the specified entities.
 	Life([]names.Tag) ([]params.LifeResult, error)
 
-	// EnsureDead ensures that the specified entities become Dead if
-	// they are Alive or Dying.
-	EnsureDead([]names.Tag) ([]params.ErrorResult, error)
-
 	// Remove removes the specified entities from state.
 	Remove([]names.Tag) ([]params.ErrorResult, error)
 
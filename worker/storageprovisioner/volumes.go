@@ -24,19 +24,11 @@ func volumesChanged(ctx *context, changes []string) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	// TODO(axw) wait for volumes to have no attachments first.
-	// We can then have the removal of the last attachment trigger
-	// the volume's Life being transitioned to Dead, or watch the
-	// attachments until they're all gone. We need to watch
-	// attachments *anyway*, so we can probably integrate the two
-	// things.
 	logger.Debugf("volumes alive: %v, dying: %v, dead: %v", alive, dying, dead)
-	if err := ensureDead(ctx, dying); err != nil {
-		return errors.Annotate(err, "ensuring volumes dead")
-	}
-	// Once the entities are Dead, they can be removed from state
-	// after the corresponding cloud storage resources are removed.
-	dead = append(dead, dying...)
+	// Dying volumes are waiting for their attachments to be removed;
+	// once the last one is, the volume becomes Dead, and can be
+	// removed from state after the corresponding cloud storage
+	// resources are removed.
 	if len(alive)+len(dead) == 0 {
 		return nil
 	}
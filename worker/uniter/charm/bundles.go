@@ -5,6 +5,7 @@ package charm
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 
@@ -15,15 +16,21 @@ import (
 	"github.com/juju/juju/downloader"
 )
 
+// maxDownloadResumes is the number of times an interrupted charm
+// download is resumed before the next archive URL is tried.
+const maxDownloadResumes = 5
+
 // BundlesDir is responsible for storing and retrieving charm bundles
 // identified by state charms.
 type BundlesDir struct {
-	path string
+	path   string
+	client *http.Client
 }
 
-// NewBundlesDir returns a new BundlesDir which uses path for storage.
-func NewBundlesDir(path string) *BundlesDir {
-	return &BundlesDir{path}
+// NewBundlesDir returns a new BundlesDir which uses path for storage,
+// and client to download charm bundles.
+func NewBundlesDir(path string, client *http.Client) *BundlesDir {
+	return &BundlesDir{path, client}
 }
 
 // Read returns a charm bundle from the directory. If no bundle exists yet,
@@ -58,7 +65,7 @@ func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error
 	for _, archiveURL := range archiveURLs {
 		aurl := archiveURL.String()
 		logger.Infof("downloading %s from %s", info.URL(), aurl)
-		st, err = tryDownload(aurl, dir, abort)
+		st, err = d.tryDownload(aurl, dir, abort)
 		if err == nil {
 			break
 		}
@@ -90,13 +97,10 @@ func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error
 	return os.Rename(st.File.Name(), d.bundlePath(info))
 }
 
-func tryDownload(url, dir string, abort <-chan struct{}) (downloader.Status, error) {
-	// Downloads always go through the API server, which at
-	// present cannot be verified due to the certificates
-	// being inadequate. We always verify the SHA-256 hash,
-	// and the data transferred is not sensitive, so this
-	// does not pose a problem.
-	dl := downloader.New(url, dir, utils.NoVerifySSLHostnames)
+func (d *BundlesDir) tryDownload(url, dir string, abort <-chan struct{}) (downloader.Status, error) {
+	dl := downloader.NewWithOptions(url, dir, d.client, downloader.Options{
+		MaxResumes: maxDownloadResumes,
+	})
 	defer dl.Stop()
 	select {
 	case <-abort:
@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -112,7 +113,7 @@ func (i *mockArchiveURLCharm) ArchiveURLs() ([]*url.URL, error) {
 func (s *BundlesDirSuite) TestGet(c *gc.C) {
 	basedir := c.MkDir()
 	bunsdir := filepath.Join(basedir, "random", "bundles")
-	d := charm.NewBundlesDir(bunsdir)
+	d := charm.NewBundlesDir(bunsdir, &http.Client{})
 
 	// Check it doesn't get created until it's needed.
 	_, err := os.Stat(bunsdir)
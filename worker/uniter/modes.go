@@ -293,11 +293,11 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 		}
 		lastCollectMetrics := time.Unix(u.operationState().CollectMetricsTime, 0)
 		collectMetricsSignal := u.collectMetricsAt(
-			time.Now(), lastCollectMetrics, metricsPollInterval,
+			u.clock.Now(), lastCollectMetrics, metricsPollInterval,
 		)
 		var creator creator
 		select {
-		case <-time.After(idleWaitTime):
+		case <-u.clock.After(idleWaitTime):
 			if err := setAgentStatus(u, params.StatusIdle, "", nil); err != nil {
 				return nil, errors.Trace(err)
 			}
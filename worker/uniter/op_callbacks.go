@@ -118,6 +118,20 @@ func (opc *operationCallbacks) NotifyHookFailed(hook string, ctx runner.Context)
 	}
 }
 
+// RecordHookEvent is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) RecordHookEvent(event params.UnitEvent) {
+	err := opc.u.unit.RecordEvents(event)
+	if errors.IsNotImplemented(err) {
+		// The state server is too old to keep an event log.
+		return
+	}
+	if err != nil {
+		// The event log is informational only, so failing to
+		// update it must not stop the hook from running.
+		logger.Errorf("cannot record %s event for %q hook: %v", event.Kind, event.Hook, err)
+	}
+}
+
 // FailAction is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	if !names.IsValidAction(actionId) {
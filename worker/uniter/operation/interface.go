@@ -9,6 +9,7 @@ import (
 	utilexec "github.com/juju/utils/exec"
 	corecharm "gopkg.in/juju/charm.v5"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -152,6 +153,10 @@ type Callbacks interface {
 	NotifyHookCompleted(string, runner.Context)
 	NotifyHookFailed(string, runner.Context)
 
+	// RecordHookEvent records the progress of a hook in the unit's event
+	// log. It's only used by RunHook operations.
+	RecordHookEvent(event params.UnitEvent)
+
 	// InitializeMetricsCollector ensures that the collect-metrics hook timer is
 	// up to date given the current deployed charm. It's only used in deploy
 	// operations.
@@ -74,7 +74,16 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	message := RunningHookMessage(rh.name)
 	unlock, err := rh.callbacks.AcquireExecutionLock(message)
 	if err != nil {
-		return nil, err
+		// The hook has not started; this is usually because the uniter
+		// was stopped while the machine lock was held, for example by
+		// the reboot worker. Queue the hook to be run again when the
+		// uniter restarts, rather than leaving it looking as though it
+		// failed part way through.
+		return stateChange{
+			Kind: RunHook,
+			Step: Queued,
+			Hook: &rh.info,
+		}.apply(state), err
 	}
 	defer unlock()
 
@@ -91,7 +100,19 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	ranHook := true
 	step := Done
 
+	started := time.Now()
+	rh.callbacks.RecordHookEvent(params.UnitEvent{
+		Kind: params.UnitEventHookStarted,
+		Hook: rh.name,
+		Time: started,
+	})
 	err = rh.runner.RunHook(rh.name)
+	finished := params.UnitEvent{
+		Kind:     params.UnitEventHookCompleted,
+		Hook:     rh.name,
+		Time:     time.Now(),
+		Duration: time.Since(started),
+	}
 	cause := errors.Cause(err)
 	switch {
 	case runner.IsMissingHookError(cause):
@@ -105,6 +126,9 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	case err == nil:
 	default:
 		logger.Errorf("hook %q failed: %v", rh.name, err)
+		finished.Kind = params.UnitEventHookFailed
+		finished.Message = err.Error()
+		rh.callbacks.RecordHookEvent(finished)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
 		return nil, ErrHookFailed
 	}
@@ -114,7 +138,9 @@ func (rh *runHook) Execute(state State) (*State, error) {
 		rh.callbacks.NotifyHookCompleted(rh.name, rh.runner.Context())
 	} else {
 		logger.Infof("skipped %q hook (missing)", rh.name)
+		finished.Message = "hook not implemented by charm"
 	}
+	rh.callbacks.RecordHookEvent(finished)
 
 	var hasRunStatusSet bool
 	var afterHookErr error
@@ -12,6 +12,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5/hooks"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -175,8 +176,12 @@ func (s *RunHookSuite) testExecuteLockError(c *gc.C, newHook newHook) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	newState, err := op.Execute(operation.State{})
-	c.Assert(newState, gc.IsNil)
 	c.Assert(err, gc.ErrorMatches, "blart")
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind: operation.RunHook,
+		Step: operation.Queued,
+		Hook: &hook.Info{Kind: hooks.ConfigChanged},
+	})
 	c.Assert(*callbacks.MockAcquireExecutionLock.gotMessage, gc.Equals, "running some-hook-name hook")
 }
 
@@ -193,6 +198,7 @@ func (s *RunHookSuite) getExecuteRunnerTest(c *gc.C, newHook newHook, kind hooks
 	callbacks := &ExecuteHookCallbacks{
 		PrepareHookCallbacks:     NewPrepareHookCallbacks(),
 		MockAcquireExecutionLock: &MockAcquireExecutionLock{},
+		MockRecordHookEvent:      &MockRecordHookEvent{},
 		MockNotifyHookCompleted:  &MockNotify{},
 		MockNotifyHookFailed:     &MockNotify{},
 	}
@@ -222,6 +228,7 @@ func (s *RunHookSuite) testExecuteMissingHookError(c *gc.C, newHook newHook) {
 		c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
 		c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 		c.Assert(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+		assertHookEvents(c, callbacks, params.UnitEventHookCompleted, "hook not implemented by charm")
 
 		status, err := runnerFactory.MockNewHookRunner.runner.Context().UnitStatus()
 		c.Assert(err, jc.ErrorIsNil)
@@ -310,6 +317,7 @@ func (s *RunHookSuite) testExecuteOtherError(c *gc.C, newHook newHook) {
 	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
 	c.Assert(*callbacks.MockNotifyHookFailed.gotContext, gc.Equals, runnerFactory.MockNewHookRunner.runner.context)
 	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+	assertHookEvents(c, callbacks, params.UnitEventHookFailed, "graaargh")
 }
 
 func (s *RunHookSuite) TestExecuteOtherError_Run(c *gc.C) {
@@ -333,6 +341,21 @@ func (s *RunHookSuite) testExecuteSuccess(
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(newState, gc.DeepEquals, &after)
 	c.Check(callbacks.executingMessage, gc.Equals, "running some-hook-name hook")
+	assertHookEvents(c, callbacks, params.UnitEventHookCompleted, "")
+}
+
+// assertHookEvents checks that the hook's start was recorded, followed
+// by an event of the given kind with the given message.
+func assertHookEvents(c *gc.C, callbacks *ExecuteHookCallbacks, kind params.UnitEventKind, message string) {
+	events := callbacks.MockRecordHookEvent.gotEvents
+	c.Assert(events, gc.HasLen, 2)
+	c.Check(events[0].Kind, gc.Equals, params.UnitEventHookStarted)
+	c.Check(events[0].Hook, gc.Equals, "some-hook-name")
+	c.Check(events[0].Duration, gc.Equals, time.Duration(0))
+	c.Check(events[1].Kind, gc.Equals, kind)
+	c.Check(events[1].Hook, gc.Equals, "some-hook-name")
+	c.Check(events[1].Message, gc.Equals, message)
+	c.Check(events[1].Time.Before(events[0].Time), jc.IsFalse)
 }
 
 func (s *RunHookSuite) TestExecuteSuccess_BlankSlate(c *gc.C) {
@@ -9,6 +9,7 @@ import (
 	corecharm "gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/hooks"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
@@ -213,13 +214,26 @@ func (mock *MockNotify) Call(hookName string, ctx runner.Context) {
 	mock.gotContext = &ctx
 }
 
+type MockRecordHookEvent struct {
+	gotEvents []params.UnitEvent
+}
+
+func (mock *MockRecordHookEvent) Call(event params.UnitEvent) {
+	mock.gotEvents = append(mock.gotEvents, event)
+}
+
 type ExecuteHookCallbacks struct {
 	*PrepareHookCallbacks
 	*MockAcquireExecutionLock
+	*MockRecordHookEvent
 	MockNotifyHookCompleted *MockNotify
 	MockNotifyHookFailed    *MockNotify
 }
 
+func (cb *ExecuteHookCallbacks) RecordHookEvent(event params.UnitEvent) {
+	cb.MockRecordHookEvent.Call(event)
+}
+
 func (cb *ExecuteHookCallbacks) AcquireExecutionLock(message string) (func(), error) {
 	return cb.MockAcquireExecutionLock.Call(message)
 }
@@ -196,6 +196,9 @@ func (d *StateDir) Write(hi hook.Info) (err error) {
 	name := strings.Replace(hi.RemoteUnit, "/", "-", 1)
 	path := filepath.Join(d.path, name)
 	if hi.Kind == hooks.RelationDeparted {
+		if err := os.Remove(d.settingsPath(hi.RemoteUnit)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
 			return err
 		}
@@ -217,6 +220,38 @@ func (d *StateDir) Write(hi hook.Info) (err error) {
 	return nil
 }
 
+// settingsPath returns the path of the file holding the snapshot of the
+// named remote unit's settings.
+func (d *StateDir) settingsPath(unitName string) string {
+	return filepath.Join(d.path, strings.Replace(unitName, "/", "-", 1)+".settings")
+}
+
+// WriteSettings atomically writes to disk a snapshot of the settings of
+// the named remote unit, so that they are still available to the unit's
+// relation-departed hook once they can no longer be read from state,
+// even if the uniter restarts in between. The snapshot is removed when
+// the relation-departed hook for the unit is written.
+func (d *StateDir) WriteSettings(unitName string, settings map[string]interface{}) error {
+	if err := utils.WriteYaml(d.settingsPath(unitName), settings); err != nil {
+		return errors.Annotatef(err, "cannot write settings snapshot for %q", unitName)
+	}
+	return nil
+}
+
+// ReadSettings returns the snapshot of the settings of the named remote
+// unit last written with WriteSettings. If there is none, an error
+// satisfying errors.IsNotFound is returned.
+func (d *StateDir) ReadSettings(unitName string) (map[string]interface{}, error) {
+	var settings map[string]interface{}
+	err := utils.ReadYaml(d.settingsPath(unitName), &settings)
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, errors.NotFoundf("settings snapshot for %q", unitName)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot read settings snapshot for %q", unitName)
+	}
+	return settings, nil
+}
+
 // Remove removes the directory if it exists and is empty.
 func (d *StateDir) Remove() error {
 	if err := os.Remove(d.path); err != nil && !os.IsNotExist(err) {
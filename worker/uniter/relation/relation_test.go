@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5/hooks"
@@ -243,6 +244,38 @@ func (s *StateDirSuite) TestWrite(c *gc.C) {
 	}
 }
 
+func (s *StateDirSuite) TestSettingsSnapshot(c *gc.C) {
+	basedir := c.MkDir()
+	setUpDir(c, basedir, "123", map[string]string{
+		"foo-1": "change-version: 0\n",
+	})
+	dir, err := relation.ReadStateDir(basedir, 123)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = dir.ReadSettings("foo/1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	settings := map[string]interface{}{"foo": "bar"}
+	err = dir.WriteSettings("foo/1", settings)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The snapshot is read back after a restart, and does not
+	// affect the relation state.
+	dir, err = relation.ReadStateDir(basedir, 123)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(msi(dir.State().Members), gc.DeepEquals, msi{"foo/1": 0})
+	read, err := dir.ReadSettings("foo/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, settings)
+
+	// The snapshot is removed once the unit has departed.
+	err = dir.Write(hook.Info{Kind: hooks.RelationDeparted, RelationId: 123, RemoteUnit: "foo/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = dir.ReadSettings("foo/1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = dir.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *StateDirSuite) TestRemove(c *gc.C) {
 	basedir := c.MkDir()
 	dir, err := relation.ReadStateDir(basedir, 1)
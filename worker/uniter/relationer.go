@@ -6,9 +6,11 @@ package uniter
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v5/hooks"
 
 	apiuniter "github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/relation"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -40,7 +42,21 @@ func (r *Relationer) ContextInfo() *runner.RelationInfo {
 	for memberName := range members {
 		memberNames = append(memberNames, memberName)
 	}
-	return &runner.RelationInfo{r.ru, memberNames}
+	return &runner.RelationInfo{
+		RelationUnit:     r.ru,
+		MemberNames:      memberNames,
+		SettingsSnapshot: r.settingsSnapshot,
+	}
+}
+
+// settingsSnapshot returns the settings of the named remote unit as they
+// were when its last relation-changed hook was prepared.
+func (r *Relationer) settingsSnapshot(unitName string) (params.Settings, error) {
+	settings, err := r.dir.ReadSettings(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return params.Settings(settings), nil
 }
 
 // IsImplicit returns whether the local relation endpoint is implicit. Implicit
@@ -138,10 +154,30 @@ func (r *Relationer) PrepareHook(hi hook.Info) (hookName string, err error) {
 	if err = r.dir.State().Validate(hi); err != nil {
 		return
 	}
+	if hi.Kind == hooks.RelationChanged {
+		if err = r.snapshotSettings(hi.RemoteUnit); err != nil {
+			return
+		}
+	}
 	name := r.ru.Endpoint().Name
 	return fmt.Sprintf("%s-%s", name, hi.Kind), nil
 }
 
+// snapshotSettings records the current settings of the named remote
+// unit, so that its relation-departed hook can see them once the unit has
+// left the relation scope and they can no longer be read.
+func (r *Relationer) snapshotSettings(unitName string) error {
+	settings, err := r.ru.ReadSettings(unitName)
+	if params.IsCodeNotFound(err) {
+		// The unit has already left the scope; keep any
+		// earlier snapshot.
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot read settings of %q", unitName)
+	}
+	return r.dir.WriteSettings(unitName, settings)
+}
+
 // CommitHook persists the fact of the supplied hook's completion.
 func (r *Relationer) CommitHook(hi hook.Info) error {
 	if r.IsImplicit() {
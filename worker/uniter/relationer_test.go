@@ -324,6 +324,51 @@ func (s *RelationerSuite) TestPrepareCommitHooks(c *gc.C) {
 	assertMembers(map[string]int64{"u/1": 7, "u/2": 3})
 }
 
+func (s *RelationerSuite) TestDepartedSettingsSurviveRestart(c *gc.C) {
+	ru1, _ := s.AddRelationUnit(c, "u/1")
+	err := ru1.EnterScope(map[string]interface{}{"unit": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+	r := uniter.NewRelationer(s.apiRelUnit, s.dir, s.hooks)
+	err = r.Join()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Preparing the relation-changed hook for u/1 persists its settings.
+	joined := hook.Info{Kind: hooks.RelationJoined, RemoteUnit: "u/1"}
+	_, err = r.PrepareHook(joined)
+	c.Assert(err, jc.ErrorIsNil)
+	err = r.CommitHook(joined)
+	c.Assert(err, jc.ErrorIsNil)
+	changed := hook.Info{Kind: hooks.RelationChanged, RemoteUnit: "u/1"}
+	_, err = r.PrepareHook(changed)
+	c.Assert(err, jc.ErrorIsNil)
+	err = r.CommitHook(changed)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Once u/1 has left the scope, its settings can no longer be read.
+	err = ru1.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.apiRelUnit.ReadSettings("u/1")
+	c.Assert(err, gc.NotNil)
+
+	// After a restart, the persisted settings are still available for
+	// the relation-departed hook.
+	dir, err := relation.ReadStateDir(s.dirPath, s.rel.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	r = uniter.NewRelationer(s.apiRelUnit, dir, s.hooks)
+	settings, err := r.ContextInfo().SettingsSnapshot("u/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["unit"], gc.Equals, "settings")
+
+	// They are discarded once the hook has run.
+	departed := hook.Info{Kind: hooks.RelationDeparted, RemoteUnit: "u/1"}
+	_, err = r.PrepareHook(departed)
+	c.Assert(err, jc.ErrorIsNil)
+	err = r.CommitHook(departed)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = r.ContextInfo().SettingsSnapshot("u/1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *RelationerSuite) TestSetDying(c *gc.C) {
 	ru1, _ := s.AddRelationUnit(c, "u/1")
 	settings := map[string]interface{}{"unit": "settings"}
@@ -6,12 +6,19 @@ package runner
 import (
 	"sort"
 
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/params"
 )
 
 // SettingsFunc returns the relation settings for a unit.
 type SettingsFunc func(unitName string) (params.Settings, error)
 
+// MultipleSettingsFunc returns the relation settings for several units,
+// or the error reading the settings of each, in the order the units
+// were given.
+type MultipleSettingsFunc func(unitNames []string) ([]params.SettingsResult, error)
+
 // SettingsMap is a map from unit name to relation settings.
 type SettingsMap map[string]params.Settings
 
@@ -21,6 +28,12 @@ type SettingsMap map[string]params.Settings
 type RelationCache struct {
 	// readSettings is used to get settings data if when not already present.
 	readSettings SettingsFunc
+	// readMultipleSettings, if not nil, is used to get the settings data
+	// of all members not already present at once.
+	readMultipleSettings MultipleSettingsFunc
+	// readSnapshot, if not nil, is used to get the settings of a departing
+	// member that were persisted before it left the relation.
+	readSnapshot SettingsFunc
 	// members' keys define the relation's membership; non-nil values hold
 	// cached settings.
 	members SettingsMap
@@ -39,6 +52,27 @@ func NewRelationCache(readSettings SettingsFunc, memberNames []string) *Relation
 	return cache
 }
 
+// NewPrefetchingRelationCache creates a new RelationCache as NewRelationCache
+// does, except that when the settings of a member must be read, those of all
+// members not already present are read together using readMultipleSettings.
+// This avoids reading the settings of each member of a large relation
+// separately when a hook iterates over them.
+func NewPrefetchingRelationCache(readSettings SettingsFunc, readMultipleSettings MultipleSettingsFunc, memberNames []string) *RelationCache {
+	cache := NewRelationCache(readSettings, memberNames)
+	cache.readMultipleSettings = readMultipleSettings
+	return cache
+}
+
+// NewSnapshottingRelationCache creates a new RelationCache as
+// NewPrefetchingRelationCache does, except that when a member is removed
+// before its settings have been read, readSnapshot is used to get the
+// settings persisted for it before it departed.
+func NewSnapshottingRelationCache(readSettings SettingsFunc, readMultipleSettings MultipleSettingsFunc, readSnapshot SettingsFunc, memberNames []string) *RelationCache {
+	cache := NewPrefetchingRelationCache(readSettings, readMultipleSettings, memberNames)
+	cache.readSnapshot = readSnapshot
+	return cache
+}
+
 // Prune resets the membership to the supplied list, and discards the settings
 // of all non-member units.
 func (cache *RelationCache) Prune(memberNames []string) {
@@ -66,6 +100,11 @@ func (cache *RelationCache) Settings(unitName string) (params.Settings, error) {
 	if settings == nil {
 		if !isMember {
 			settings = cache.others[unitName]
+		} else if cache.readMultipleSettings != nil {
+			if err := cache.readMemberSettings(); err != nil {
+				return nil, err
+			}
+			settings = cache.members[unitName]
 		}
 		if settings == nil {
 			var err error
@@ -83,6 +122,35 @@ func (cache *RelationCache) Settings(unitName string) (params.Settings, error) {
 	return settings, nil
 }
 
+// readMemberSettings reads the settings of all members whose settings are
+// not already present, if there are several. The settings of members that
+// cannot be read are left absent, to be read individually when asked for.
+func (cache *RelationCache) readMemberSettings() error {
+	var unitNames []string
+	for memberName, settings := range cache.members {
+		if settings == nil {
+			unitNames = append(unitNames, memberName)
+		}
+	}
+	if len(unitNames) < 2 {
+		return nil
+	}
+	sort.Strings(unitNames)
+	results, err := cache.readMultipleSettings(unitNames)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(unitNames) {
+		return errors.Errorf("expected %d results, got %d", len(unitNames), len(results))
+	}
+	for i, result := range results {
+		if result.Error == nil {
+			cache.members[unitNames[i]] = result.Settings
+		}
+	}
+	return nil
+}
+
 // InvalidateMember ensures that the named remote unit will be considered a
 // member of the relation, and that the next attempt to read its settings will
 // use fresh data.
@@ -91,7 +159,24 @@ func (cache *RelationCache) InvalidateMember(memberName string) {
 }
 
 // RemoveMember ensures that the named remote unit will not be considered a
-// member of the relation,
-func (cache *RelationCache) RemoveMember(memberName string) {
+// member of the relation. The settings already read for the unit, or else
+// those persisted for it before it departed, are kept as a snapshot until
+// the cache is next pruned, so that the relation-departed hook can still see
+// them once they can no longer be read.
+func (cache *RelationCache) RemoveMember(memberName string) error {
+	settings := cache.members[memberName]
 	delete(cache.members, memberName)
+	if settings == nil && cache.readSnapshot != nil {
+		var err error
+		settings, err = cache.readSnapshot(memberName)
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if settings != nil {
+		cache.others[memberName] = settings
+	}
+	return nil
 }
@@ -4,6 +4,8 @@
 package runner_test
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -20,8 +22,9 @@ type settingsResult struct {
 
 type RelationCacheSuite struct {
 	testing.IsolationSuite
-	calls   []string
-	results []settingsResult
+	calls      []string
+	results    []settingsResult
+	unreadable map[string]bool
 }
 
 var _ = gc.Suite(&RelationCacheSuite{})
@@ -29,6 +32,7 @@ var _ = gc.Suite(&RelationCacheSuite{})
 func (s *RelationCacheSuite) SetUpTest(c *gc.C) {
 	s.calls = []string{}
 	s.results = []settingsResult{}
+	s.unreadable = nil
 }
 
 func (s *RelationCacheSuite) ReadSettings(unitName string) (params.Settings, error) {
@@ -37,6 +41,26 @@ func (s *RelationCacheSuite) ReadSettings(unitName string) (params.Settings, err
 	return result.settings, result.err
 }
 
+// ReadMultipleSettings records a single call, named for all the units
+// read, and returns the next result's settings for each of them, except
+// for the units in s.unreadable, for which it returns an error.
+func (s *RelationCacheSuite) ReadMultipleSettings(unitNames []string) ([]params.SettingsResult, error) {
+	result := s.results[len(s.calls)]
+	s.calls = append(s.calls, strings.Join(unitNames, ","))
+	if result.err != nil {
+		return nil, result.err
+	}
+	results := make([]params.SettingsResult, len(unitNames))
+	for i, unitName := range unitNames {
+		if s.unreadable[unitName] {
+			results[i].Error = &params.Error{Message: "unreadable"}
+			continue
+		}
+		results[i].Settings = result.settings
+	}
+	return results, nil
+}
+
 func (s *RelationCacheSuite) TestCreateEmpty(c *gc.C) {
 	cache := runner.NewRelationCache(s.ReadSettings, nil)
 	c.Assert(cache.MemberNames(), gc.HasLen, 0)
@@ -142,7 +166,7 @@ func (s *RelationCacheSuite) TestInvalidateMemberUncachesOtherSettings(c *gc.C)
 	c.Assert(s.calls, jc.DeepEquals, []string{"x/2", "x/2"})
 }
 
-func (s *RelationCacheSuite) TestRemoveMemberUncachesMemberSettings(c *gc.C) {
+func (s *RelationCacheSuite) TestRemoveMemberKeepsSettingsUntilPrune(c *gc.C) {
 	s.results = []settingsResult{{
 		params.Settings{"foo": "bar"}, nil,
 	}, {
@@ -155,13 +179,74 @@ func (s *RelationCacheSuite) TestRemoveMemberUncachesMemberSettings(c *gc.C) {
 	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
 	c.Assert(s.calls, jc.DeepEquals, []string{"x/2"})
 
+	// The settings read while x/2 was a member are still available
+	// once it has departed, without being read again.
 	cache.RemoveMember("x/2")
+	c.Assert(cache.MemberNames(), gc.HasLen, 0)
+	settings, err = cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/2"})
+
+	cache.Prune(nil)
 	settings, err = cache.Settings("x/2")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
 	c.Assert(s.calls, jc.DeepEquals, []string{"x/2", "x/2"})
 }
 
+func (s *RelationCacheSuite) TestRemoveMemberReadsUncachedSettings(c *gc.C) {
+	s.results = []settingsResult{{
+		params.Settings{"foo": "bar"}, nil,
+	}}
+	cache := runner.NewRelationCache(s.ReadSettings, []string{"x/2"})
+
+	cache.RemoveMember("x/2")
+	settings, err := cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/2"})
+}
+
+func (s *RelationCacheSuite) TestRemoveMemberUsesSnapshot(c *gc.C) {
+	readSnapshot := func(unitName string) (params.Settings, error) {
+		if unitName == "x/2" {
+			return params.Settings{"foo": "snapshot"}, nil
+		}
+		return nil, errors.NotFoundf("settings snapshot for %q", unitName)
+	}
+	s.results = []settingsResult{{
+		params.Settings{"foo": "bar"}, nil,
+	}}
+	cache := runner.NewSnapshottingRelationCache(s.ReadSettings, s.ReadMultipleSettings, readSnapshot, []string{"x/1", "x/2"})
+
+	// The settings of x/2 were never read before it departed, so the
+	// persisted snapshot is used rather than reading them.
+	err := cache.RemoveMember("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "snapshot"})
+	c.Assert(s.calls, gc.HasLen, 0)
+
+	// Without a snapshot, the settings are read on demand.
+	err = cache.RemoveMember("x/1")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = cache.Settings("x/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/1"})
+}
+
+func (s *RelationCacheSuite) TestRemoveMemberSnapshotError(c *gc.C) {
+	readSnapshot := func(unitName string) (params.Settings, error) {
+		return nil, errors.New("disk on fire")
+	}
+	cache := runner.NewSnapshottingRelationCache(s.ReadSettings, s.ReadMultipleSettings, readSnapshot, []string{"x/1"})
+	err := cache.RemoveMember("x/1")
+	c.Assert(err, gc.ErrorMatches, "disk on fire")
+}
+
 func (s *RelationCacheSuite) TestSettingsCachesOtherSettings(c *gc.C) {
 	s.results = []settingsResult{{
 		params.Settings{"foo": "bar"}, nil,
@@ -213,3 +298,72 @@ func (s *RelationCacheSuite) TestPruneUncachesOtherSettings(c *gc.C) {
 	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
 	c.Assert(s.calls, jc.DeepEquals, []string{"x/2", "x/2"})
 }
+
+func (s *RelationCacheSuite) TestPrefetchingReadsMemberSettingsTogether(c *gc.C) {
+	s.results = []settingsResult{{
+		params.Settings{"foo": "bar"}, nil,
+	}, {
+		params.Settings{"baz": "qux"}, nil,
+	}, {
+		params.Settings{"other": "unit"}, nil,
+	}}
+	cache := runner.NewPrefetchingRelationCache(s.ReadSettings, s.ReadMultipleSettings, []string{"x/3", "x/1", "x/2"})
+
+	for _, unitName := range []string{"x/1", "x/2", "x/3"} {
+		settings, err := cache.Settings(unitName)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	}
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/1,x/2,x/3"})
+
+	// Only invalidated members are read again.
+	cache.InvalidateMember("x/2")
+	cache.InvalidateMember("x/4")
+	settings, err := cache.Settings("x/4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
+	settings, err = cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/1,x/2,x/3", "x/2,x/4"})
+
+	// Non-members are still read individually.
+	settings, err = cache.Settings("y/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"other": "unit"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/1,x/2,x/3", "x/2,x/4", "y/0"})
+}
+
+func (s *RelationCacheSuite) TestPrefetchingReadsUnreadableMemberAlone(c *gc.C) {
+	s.unreadable = map[string]bool{"x/1": true}
+	s.results = []settingsResult{{
+		params.Settings{"foo": "bar"}, nil,
+	}, {
+		nil, errors.New("blam"),
+	}}
+	cache := runner.NewPrefetchingRelationCache(s.ReadSettings, s.ReadMultipleSettings, []string{"x/1", "x/2"})
+
+	// The member that can be read is unaffected by the other.
+	settings, err := cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/1,x/2"})
+
+	// The other is read individually, to report its own error.
+	settings, err = cache.Settings("x/1")
+	c.Assert(settings, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "blam")
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/1,x/2", "x/1"})
+}
+
+func (s *RelationCacheSuite) TestPrefetchingPropagatesError(c *gc.C) {
+	s.results = []settingsResult{{
+		nil, errors.New("blam"),
+	}}
+	cache := runner.NewPrefetchingRelationCache(s.ReadSettings, s.ReadMultipleSettings, []string{"x/1", "x/2"})
+
+	settings, err := cache.Settings("x/2")
+	c.Assert(settings, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "blam")
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/1,x/2"})
+}
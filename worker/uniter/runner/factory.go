@@ -168,7 +168,9 @@ func (f *factory) NewHookRunner(hookInfo hook.Info) (Runner, error) {
 			return nil, errors.Errorf("unknown relation id: %v", hookInfo.RelationId)
 		}
 		if hookInfo.Kind == hooks.RelationDeparted {
-			relation.cache.RemoveMember(hookInfo.RemoteUnit)
+			if err := relation.cache.RemoveMember(hookInfo.RemoteUnit); err != nil {
+				return nil, errors.Trace(err)
+			}
 		} else if hookInfo.RemoteUnit != "" {
 			// Clear remote settings cache for changing remote unit.
 			relation.cache.InvalidateMember(hookInfo.RemoteUnit)
@@ -314,7 +316,12 @@ func (f *factory) getContextRelations() map[int]*ContextRelation {
 		if found {
 			cache.Prune(memberNames)
 		} else {
-			cache = NewRelationCache(relationUnit.ReadSettings, memberNames)
+			cache = NewSnapshottingRelationCache(
+				relationUnit.ReadSettings,
+				relationUnit.ReadMultipleSettings,
+				info.SettingsSnapshot,
+				memberNames,
+			)
 		}
 		relationCaches[id] = cache
 		contextRelations[id] = NewContextRelation(relationUnit, cache)
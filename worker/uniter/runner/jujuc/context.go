@@ -154,6 +154,14 @@ type ContextRelation interface {
 
 	// ReadSettings returns the settings of any remote unit in the relation.
 	ReadSettings(unit string) (params.Settings, error)
+
+	// ServiceSettings allows read/write access to the local service's
+	// settings in this relation. Only the service leader may write them.
+	ServiceSettings() (Settings, error)
+
+	// ReadServiceSettings returns the settings of any service in the
+	// relation.
+	ReadServiceSettings(service string) (params.Settings, error)
 }
 
 // ContextStorage expresses the capabilities of a hook with respect to a
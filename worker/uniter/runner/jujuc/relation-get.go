@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -19,6 +20,8 @@ type RelationGetCommand struct {
 	RelationId int
 	Key        string
 	UnitName   string
+	App        bool
+	Service    string
 	out        cmd.Output
 }
 
@@ -31,6 +34,10 @@ func (c *RelationGetCommand) Info() *cmd.Info {
 	doc := `
 relation-get prints the value of a unit's relation setting, specified by key.
 If no key is given, or if the key is "-", all keys and values will be printed.
+With --app, the settings published by the leader of the unit's service are
+printed instead; the unit id may then also be given as a service name.
+In a relation-departed hook, the departing unit's settings are those seen
+by its last relation-changed hook.
 `
 	if name, found := c.ctx.RemoteUnitName(); found {
 		args = "[<key> [<unit id>]]"
@@ -50,6 +57,7 @@ func (c *RelationGetCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.Var(rV, "r", "specify a relation by id")
 	f.Var(rV, "relation", "")
+	f.BoolVar(&c.App, "app", false, "get the service's settings rather than the unit's")
 }
 
 func (c *RelationGetCommand) Init(args []string) error {
@@ -73,6 +81,16 @@ func (c *RelationGetCommand) Init(args []string) error {
 	if c.UnitName == "" {
 		return fmt.Errorf("no unit id specified")
 	}
+	if c.App {
+		switch {
+		case names.IsValidUnit(c.UnitName):
+			c.Service, _ = names.UnitService(c.UnitName)
+		case names.IsValidService(c.UnitName):
+			c.Service = c.UnitName
+		default:
+			return fmt.Errorf("invalid unit or service name %q", c.UnitName)
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -82,7 +100,13 @@ func (c *RelationGetCommand) Run(ctx *cmd.Context) error {
 		return fmt.Errorf("unknown relation id")
 	}
 	var settings params.Settings
-	if c.UnitName == c.ctx.UnitName() {
+	if c.App {
+		var err error
+		settings, err = c.readServiceSettings(r)
+		if err != nil {
+			return err
+		}
+	} else if c.UnitName == c.ctx.UnitName() {
 		node, err := r.Settings()
 		if err != nil {
 			return err
@@ -103,3 +127,21 @@ func (c *RelationGetCommand) Run(ctx *cmd.Context) error {
 	}
 	return c.out.Write(ctx, nil)
 }
+
+// readServiceSettings returns the settings of c.Service in the given
+// relation. The local service's settings are read through the hook
+// context, so that changes made earlier in the hook are visible.
+func (c *RelationGetCommand) readServiceSettings(r ContextRelation) (params.Settings, error) {
+	localService, err := names.UnitService(c.ctx.UnitName())
+	if err != nil {
+		return nil, err
+	}
+	if c.Service != localService {
+		return r.ReadServiceSettings(c.Service)
+	}
+	node, err := r.ServiceSettings()
+	if err != nil {
+		return nil, err
+	}
+	return node.Map(), nil
+}
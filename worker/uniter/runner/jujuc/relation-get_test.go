@@ -28,6 +28,10 @@ func (s *RelationGetSuite) SetUpTest(c *gc.C) {
 	s.rels[0].units["u/0"]["private-address"] = "foo: bar\n"
 	s.rels[1].units["m/0"] = Settings{"pew": "pew\npew\n"}
 	s.rels[1].units["u/1"] = Settings{"value": "12345"}
+	s.rels[1].services = map[string]Settings{
+		"u": {"leader": "local"},
+		"m": {"endpoint": "10.0.0.1"},
+	}
 }
 
 var relationGetTests = []struct {
@@ -135,6 +139,34 @@ var relationGetTests = []struct {
 		relid:   1,
 		args:    []string{"missing", "u/1", "--format", "smart"},
 		out:     "",
+	}, {
+		summary: "service settings of implicit member",
+		relid:   1,
+		unit:    "m/0",
+		args:    []string{"--app", "endpoint"},
+		out:     "10.0.0.1",
+	}, {
+		summary: "service settings by service name",
+		relid:   1,
+		args:    []string{"--app", "-", "m"},
+		out:     "endpoint: 10.0.0.1",
+	}, {
+		summary: "local service settings",
+		relid:   1,
+		args:    []string{"--app", "leader", "u/0"},
+		out:     "local",
+	}, {
+		summary: "service settings of unknown service",
+		relid:   1,
+		args:    []string{"--app", "-", "bad"},
+		code:    1,
+		out:     `unknown service bad`,
+	}, {
+		summary: "service settings with invalid name",
+		relid:   1,
+		args:    []string{"--app", "-", "bad/x"},
+		code:    2,
+		out:     `invalid unit or service name "bad/x"`,
 	},
 }
 
@@ -218,6 +250,8 @@ usage: %s
 purpose: get relation settings
 
 options:
+--app  (= false)
+    get the service's settings rather than the unit's
 --format  (= smart)
     specify output format (json|smart|yaml)
 -o, --output (= "")
@@ -227,6 +261,10 @@ options:
 
 relation-get prints the value of a unit's relation setting, specified by key.
 If no key is given, or if the key is "-", all keys and values will be printed.
+With --app, the settings published by the leader of the unit's service are
+printed instead; the unit id may then also be given as a service name.
+In a relation-departed hook, the departing unit's settings are those seen
+by its last relation-changed hook.
 %s`[1:]
 
 var relationGetHelpTests = []struct {
@@ -27,6 +27,9 @@ too long to fit within the command length limit of the shell or
 operating system. The file will contain a YAML map containing the
 settings.  Settings in the file will be overridden by any duplicate
 key-value arguments. A value of "-" for the filename means <stdin>.
+
+The --app option writes the settings of the local unit's service rather
+than those of the unit. Only the service leader may use it.
 `
 
 // RelationSetCommand implements the relation-set command.
@@ -36,6 +39,7 @@ type RelationSetCommand struct {
 	RelationId   int
 	Settings     map[string]string
 	settingsFile cmd.FileVar
+	App          bool
 	formatFlag   string // deprecated
 }
 
@@ -60,6 +64,7 @@ func (c *RelationSetCommand) SetFlags(f *gnuflag.FlagSet) {
 
 	c.settingsFile.SetStdin()
 	f.Var(&c.settingsFile, "file", "file containing key-value pairs")
+	f.BoolVar(&c.App, "app", false, "set the service's settings rather than the unit's")
 
 	f.StringVar(&c.formatFlag, "format", "", "deprecated format flag")
 }
@@ -149,9 +154,24 @@ func (c *RelationSetCommand) Run(ctx *cmd.Context) (err error) {
 	if !found {
 		return fmt.Errorf("unknown relation id")
 	}
-	settings, err := r.Settings()
-	if err != nil {
-		return errors.Annotate(err, "cannot read relation settings")
+	var settings Settings
+	if c.App {
+		isLeader, err := c.ctx.IsLeader()
+		if err != nil {
+			return errors.Annotate(err, "cannot determine leadership status")
+		}
+		if !isLeader {
+			return errors.Errorf("cannot write service settings: not the leader")
+		}
+		settings, err = r.ServiceSettings()
+		if err != nil {
+			return errors.Annotate(err, "cannot read service relation settings")
+		}
+	} else {
+		settings, err = r.Settings()
+		if err != nil {
+			return errors.Annotate(err, "cannot read relation settings")
+		}
 	}
 	for k, v := range c.Settings {
 		if v != "" {
@@ -43,6 +43,8 @@ usage: relation-set [options] key=value [key=value ...]
 purpose: set relation settings
 
 options:
+--app  (= false)
+    set the service's settings rather than the unit's
 --file  (= )
     file containing key-value pairs
 --format (= "")
@@ -61,6 +63,9 @@ too long to fit within the command length limit of the shell or
 operating system. The file will contain a YAML map containing the
 settings.  Settings in the file will be overridden by any duplicate
 key-value arguments. A value of "-" for the filename means <stdin>.
+
+The --app option writes the settings of the local unit's service rather
+than those of the unit. Only the service leader may use it.
 `[1:], t.expect))
 		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
 	}
@@ -369,6 +374,25 @@ func (s *RelationSetSuite) TestRun(c *gc.C) {
 	}
 }
 
+func (s *RelationSetSuite) TestRunApp(c *gc.C) {
+	hctx := s.GetHookContext(c, 0, "")
+	hctx.rels[1].services = map[string]Settings{"u": {"base": "value"}}
+
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = testing.RunCommand(c, com, "-r", "1", "--app", "base=", "endpoint=10.0.0.1")
+	c.Assert(err, gc.ErrorMatches, "cannot write service settings: not the leader")
+	c.Assert(hctx.rels[1].services["u"], gc.DeepEquals, Settings{"base": "value"})
+
+	hctx.isLeader = true
+	com, err = jujuc.NewCommand(hctx, cmdString("relation-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = testing.RunCommand(c, com, "-r", "1", "--app", "base=", "endpoint=10.0.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hctx.rels[1].services["u"], gc.DeepEquals, Settings{"endpoint": "10.0.0.1"})
+	c.Assert(hctx.rels[1].units["u/0"], gc.DeepEquals, Settings{"private-address": "u-0.testing.invalid"})
+}
+
 func (s *RelationSetSuite) TestRunDeprecationWarning(c *gc.C) {
 	hctx := s.GetHookContext(c, 0, "")
 	com, _ := jujuc.NewCommand(hctx, cmdString("relation-set"))
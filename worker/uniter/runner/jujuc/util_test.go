@@ -121,6 +121,7 @@ type Context struct {
 	storageTag     names.StorageTag
 	storage        map[names.StorageTag]*ContextStorage
 	status         jujuc.StatusInfo
+	isLeader       bool
 }
 
 func (c *Context) AddMetric(key, value string, created time.Time) error {
@@ -247,9 +248,10 @@ func (c *Context) OwnerTag() string {
 }
 
 type ContextRelation struct {
-	id    int
-	name  string
-	units map[string]Settings
+	id       int
+	name     string
+	units    map[string]Settings
+	services map[string]Settings
 }
 
 func (r *ContextRelation) Id() int {
@@ -285,6 +287,26 @@ func (r *ContextRelation) ReadSettings(name string) (params.Settings, error) {
 	return s.Map(), nil
 }
 
+func (r *ContextRelation) ServiceSettings() (jujuc.Settings, error) {
+	if r.services == nil {
+		r.services = map[string]Settings{}
+	}
+	s, found := r.services["u"]
+	if !found {
+		s = Settings{}
+		r.services["u"] = s
+	}
+	return s, nil
+}
+
+func (r *ContextRelation) ReadServiceSettings(name string) (params.Settings, error) {
+	s, found := r.services[name]
+	if !found {
+		return nil, fmt.Errorf("unknown service %s", name)
+	}
+	return s.Map(), nil
+}
+
 type ContextStorage struct {
 	tag      names.StorageTag
 	kind     storage.StorageKind
@@ -326,6 +348,10 @@ func (s Settings) Map() params.Settings {
 	return r
 }
 
+func (c *Context) IsLeader() (bool, error) {
+	return c.isLeader, nil
+}
+
 func (c *Context) RequestReboot(priority jujuc.RebootPriority) error {
 	c.rebootPriority = priority
 	if c.shouldError {
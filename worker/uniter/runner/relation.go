@@ -5,6 +5,7 @@ package runner
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
@@ -14,6 +15,11 @@ import (
 type RelationInfo struct {
 	RelationUnit *uniter.RelationUnit
 	MemberNames  []string
+
+	// SettingsSnapshot, if not nil, returns the settings of a remote
+	// unit as last persisted for its relation-departed hook, or an
+	// error satisfying errors.IsNotFound if there are none.
+	SettingsSnapshot SettingsFunc
 }
 
 // ContextRelation is the implementation of jujuc.ContextRelation.
@@ -25,6 +31,12 @@ type ContextRelation struct {
 	// settings allows read and write access to the relation unit settings.
 	settings *uniter.Settings
 
+	// serviceSettings allows read and write access to the settings of
+	// the unit's service in the relation. Only the service leader may
+	// write them, so they are only written back when changed.
+	serviceSettings     *uniter.Settings
+	serviceSettingsRead params.Settings
+
 	// cache holds remote unit membership and settings.
 	cache *RelationCache
 }
@@ -71,10 +83,35 @@ func (ctx *ContextRelation) Settings() (jujuc.Settings, error) {
 	return ctx.settings, nil
 }
 
-// WriteSettings persists all changes made to the unit's relation settings.
+func (ctx *ContextRelation) ServiceSettings() (jujuc.Settings, error) {
+	if ctx.serviceSettings == nil {
+		node, err := ctx.ru.ServiceSettings()
+		if err != nil {
+			return nil, err
+		}
+		ctx.serviceSettings = node
+		ctx.serviceSettingsRead = node.Map()
+	}
+	return ctx.serviceSettings, nil
+}
+
+func (ctx *ContextRelation) ReadServiceSettings(service string) (params.Settings, error) {
+	return ctx.ru.ReadServiceSettings(service)
+}
+
+// WriteSettings persists all changes made to the unit's relation settings,
+// and to its service's relation settings if they were changed.
 func (ctx *ContextRelation) WriteSettings() (err error) {
 	if ctx.settings != nil {
-		err = ctx.settings.Write()
+		if err = ctx.settings.Write(); err != nil {
+			return
+		}
+	}
+	if ctx.serviceSettings != nil && !reflect.DeepEqual(ctx.serviceSettings.Map(), ctx.serviceSettingsRead) {
+		if err = ctx.serviceSettings.Write(); err != nil {
+			return
+		}
+		ctx.serviceSettingsRead = ctx.serviceSettings.Map()
 	}
 	return
 }
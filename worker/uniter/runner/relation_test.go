@@ -161,6 +161,27 @@ func (s *ContextRelationSuite) TestLocalSettings(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, map[string]interface{}{"change": "exciting"})
 }
 
+func (s *ContextRelationSuite) TestServiceSettings(c *gc.C) {
+	settings, err := s.rel.ServiceSettings("u")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("endpoint", "10.0.0.1")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := runner.NewContextRelation(s.apiRelUnit, nil)
+	m, err := ctx.ReadServiceSettings("u")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, gc.DeepEquals, params.Settings{"endpoint": "10.0.0.1"})
+
+	node, err := ctx.ServiceSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(node.Map(), gc.DeepEquals, params.Settings{"endpoint": "10.0.0.1"})
+
+	// Unmodified service settings are not written back.
+	err = ctx.WriteSettings()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func convertSettings(settings params.Settings) map[string]interface{} {
 	result := make(map[string]interface{})
 	for k, v := range settings {
@@ -160,27 +160,54 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
-	outReader, outWriter, err := os.Pipe()
-	if err != nil {
-		return errors.Errorf("cannot make logging pipe: %v", err)
-	}
-	ps.Stdout = outWriter
-	ps.Stderr = outWriter
-	hookLogger := &hookLogger{
-		r:      outReader,
-		done:   make(chan struct{}),
-		logger: runner.getLogger(hookName),
+
+	// The hook's stdout and stderr are logged separately, so that
+	// the output of each stream can be picked out of the logs.
+	var hookLoggers []*hookLogger
+	var outWriters []*os.File
+	defer func() {
+		for _, outWriter := range outWriters {
+			outWriter.Close()
+		}
+		for _, hookLogger := range hookLoggers {
+			hookLogger.stop()
+		}
+	}()
+	for _, stream := range []string{"stdout", "stderr"} {
+		outReader, outWriter, err := os.Pipe()
+		if err != nil {
+			return errors.Errorf("cannot make logging pipe: %v", err)
+		}
+		outWriters = append(outWriters, outWriter)
+		hookLogger := &hookLogger{
+			r:      outReader,
+			done:   make(chan struct{}),
+			logger: runner.getStreamLogger(hookName, stream),
+		}
+		go hookLogger.run()
+		hookLoggers = append(hookLoggers, hookLogger)
 	}
-	go hookLogger.run()
+	ps.Stdout = outWriters[0]
+	ps.Stderr = outWriters[1]
+
+	hookModuleLogger := runner.getLogger(hookName)
+	hookModuleLogger.Debugf("running hook (context %s)", runner.context.Id())
 	err = ps.Start()
-	outWriter.Close()
+	for _, outWriter := range outWriters {
+		outWriter.Close()
+	}
+	outWriters = nil
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(ps.Process)
 		// Block until execution finishes
 		err = ps.Wait()
 	}
-	hookLogger.stop()
+	for _, hookLogger := range hookLoggers {
+		hookLogger.stop()
+	}
+	hookLoggers = nil
+	hookModuleLogger.Debugf("hook finished (context %s)", runner.context.Id())
 	return errors.Trace(err)
 }
 
@@ -203,3 +230,10 @@ func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 func (runner *runner) getLogger(hookName string) loggo.Logger {
 	return loggo.GetLogger(fmt.Sprintf("unit.%s.%s", runner.context.UnitName(), hookName))
 }
+
+// getStreamLogger returns the logger for the given output stream of
+// the given hook, whose module is a child of the hook's module, such
+// as "unit.mysql/0.install.stderr".
+func (runner *runner) getStreamLogger(hookName, stream string) loggo.Logger {
+	return loggo.GetLogger(fmt.Sprintf("unit.%s.%s.%s", runner.context.UnitName(), hookName, stream))
+}
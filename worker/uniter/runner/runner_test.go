@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	envtesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -157,6 +158,10 @@ func (ctx *MockContext) UnitName() string {
 	return "some-unit/999"
 }
 
+func (ctx *MockContext) Id() string {
+	return "some-unit/999-some-hook-123"
+}
+
 func (ctx *MockContext) HookVars(paths runner.Paths) []string {
 	return []string{"VAR=value"}
 }
@@ -215,6 +220,35 @@ func (s *RunMockContextSuite) TestRunHookFlushSuccess(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookLogsOutputStreams(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("hook-output-test", &tw, loggo.TRACE), gc.IsNil)
+	defer loggo.RemoveWriter("hook-output-test")
+	loggo.GetLogger("unit").SetLogLevel(loggo.TRACE)
+
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		stdout: "to stdout",
+		stderr: "to stderr",
+	}, s.paths.charm)
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+
+	messages := make(map[string][]string)
+	for _, entry := range tw.Log() {
+		messages[entry.Module] = append(messages[entry.Module], entry.Message)
+	}
+	c.Assert(messages["unit.some-unit/999.something-happened.stdout"], jc.DeepEquals, []string{"to stdout"})
+	c.Assert(messages["unit.some-unit/999.something-happened.stderr"], jc.DeepEquals, []string{"to stderr"})
+	c.Assert(messages["unit.some-unit/999.something-happened"], jc.DeepEquals, []string{
+		"running hook (context some-unit/999-some-hook-123)",
+		"hook finished (context some-unit/999-some-hook-123)",
+	})
+}
+
 func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -18,9 +18,11 @@ import (
 	corecharm "gopkg.in/juju/charm.v5"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	coreleadership "github.com/juju/juju/leadership"
+	"github.com/juju/juju/utils/clock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/leadership"
@@ -76,6 +78,10 @@ type Uniter struct {
 	hookLock    *fslock.Lock
 	runListener *RunListener
 
+	// caCert holds the environment's CA certificate, used to verify
+	// the API server when downloading charms.
+	caCert string
+
 	ranLeaderSettingsChanged bool
 	ranConfigChanged         bool
 
@@ -86,17 +92,26 @@ type Uniter struct {
 	// collectMetricsAt defines a function that will be used to generate signals
 	// for the collect-metrics hook.
 	collectMetricsAt CollectMetricsSignal
+
+	// clock is used to decide when the unit has become idle and
+	// when metrics should next be collected.
+	clock clock.Clock
 }
 
 // NewUniter creates a new Uniter which will install, run, and upgrade
 // a charm on behalf of the unit with the given unitTag, by executing
-// hooks and operations provoked by changes in st.
+// hooks and operations provoked by changes in st. Charms are only
+// downloaded from API servers whose certificates are signed by caCert.
+// The clock is used to decide when the unit is idle and when to collect
+// metrics.
 func NewUniter(
 	st *uniter.State,
 	unitTag names.UnitTag,
 	leadershipManager coreleadership.LeadershipManager,
 	dataDir string,
 	hookLock *fslock.Lock,
+	caCert string,
+	clock clock.Clock,
 ) *Uniter {
 	u := &Uniter{
 		st:                st,
@@ -104,6 +119,8 @@ func NewUniter(
 		hookLock:          hookLock,
 		leadershipManager: leadershipManager,
 		collectMetricsAt:  inactiveMetricsTimer,
+		caCert:            caCert,
+		clock:             clock,
 	}
 	go func() {
 		defer u.tomb.Done()
@@ -238,10 +255,14 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	u.storage = storageAttachments
 	u.addCleanup(storageAttachments.Stop)
 
+	client, err := api.NewHTTPClientForCACert(u.caCert)
+	if err != nil {
+		return errors.Annotatef(err, "cannot create charm download client")
+	}
 	deployer, err := charm.NewDeployer(
 		u.paths.State.CharmDir,
 		u.paths.State.DeployerDir,
-		charm.NewBundlesDir(u.paths.State.BundlesDir),
+		charm.NewBundlesDir(u.paths.State.BundlesDir, client),
 	)
 	if err != nil {
 		return errors.Annotatef(err, "cannot create deployer")
@@ -15,6 +15,10 @@ type UpgradeReadyError struct {
 	OldTools  version.Binary
 	NewTools  version.Binary
 	DataDir   string
+
+	// Revert reports whether NewTools are the tools the agent ran
+	// before its most recent upgrade, to which it should revert.
+	Revert bool
 }
 
 func (e *UpgradeReadyError) Error() string {
@@ -23,7 +27,9 @@ func (e *UpgradeReadyError) Error() string {
 
 // ChangeAgentTools does the actual agent upgrade.
 // It should be called just before an agent exits, so that
-// it will restart running the new tools.
+// it will restart running the new tools. Reverts are made
+// by the agent instead, as it may need to rewrite its config
+// for the older tools.
 func (e *UpgradeReadyError) ChangeAgentTools() error {
 	agentTools, err := tools.ChangeAgentTools(e.DataDir, e.AgentName, e.NewTools)
 	if err != nil {
@@ -10,11 +10,11 @@ import (
 
 	"github.com/juju/loggo"
 	"github.com/juju/names"
-	"github.com/juju/utils"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/agent"
 	agenttools "github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/upgrader"
 	"github.com/juju/juju/state/watcher"
 	coretools "github.com/juju/juju/tools"
@@ -36,6 +36,7 @@ type Upgrader struct {
 	st               *upgrader.State
 	dataDir          string
 	tag              names.Tag
+	caCert           string
 	origAgentVersion version.Number
 	isUpgradeRunning func() bool
 }
@@ -56,6 +57,7 @@ func NewUpgrader(
 		st:               st,
 		dataDir:          agentConfig.DataDir(),
 		tag:              agentConfig.Tag(),
+		caCert:           agentConfig.CACert(),
 		origAgentVersion: origAgentVersion,
 		isUpgradeRunning: isUpgradeRunning,
 	}
@@ -138,6 +140,14 @@ func (u *Upgrader) loop() error {
 		}
 		if wantVersion == version.Current.Number {
 			continue
+		} else if previous, ok := agenttools.PreviousTools(u.dataDir, u.tag.String()); ok && previous.Number == wantVersion {
+			// The environment's agent version has been set back to
+			// the version this agent ran before its last upgrade,
+			// and those tools are kept, so revert to them.
+			logger.Infof("revert requested from %v to %v", version.Current, previous)
+			ugErr := u.newUpgradeReadyError(previous)
+			ugErr.Revert = true
+			return ugErr
 		} else if !allowedTargetVersion(u.origAgentVersion, version.Current.Number,
 			u.isUpgradeRunning(), wantVersion) {
 			// See also bug #1299802 where when upgrading from
@@ -149,6 +159,13 @@ func (u *Upgrader) loop() error {
 				wantVersion, version.Current)
 			continue
 		}
+		if revertedFrom, ok := agenttools.RevertedFrom(u.dataDir, u.tag.String()); ok && revertedFrom.Number == wantVersion {
+			// The agent kept failing after it last upgraded to this
+			// version, and was reverted; don't upgrade to it again.
+			logger.Warningf("desired tool version: %s was reverted after failing to start, refusing to upgrade",
+				wantVersion)
+			continue
+		}
 		logger.Infof("upgrade requested from %v to %v", version.Current, wantVersion)
 
 		// Check if tools have already been downloaded.
@@ -199,9 +216,14 @@ func (u *Upgrader) newUpgradeReadyError(newVersion version.Binary) *UpgradeReady
 
 func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
 	logger.Infof("fetching tools from %q", agentTools.URL)
-	// The reader MUST verify the tools' hash, so there is no
-	// need to validate the peer. We cannot anyway: see http://pad.lv/1261780.
-	resp, err := utils.GetNonValidatingHTTPClient().Get(agentTools.URL)
+	// Tools are always fetched from the API server, which must
+	// present a certificate signed by the environment's CA. The
+	// reader also verifies the tools' hash.
+	client, err := api.NewHTTPClientForCACert(u.caCert)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Get(agentTools.URL)
 	if err != nil {
 		return err
 	}
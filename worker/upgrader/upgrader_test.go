@@ -79,6 +79,10 @@ func (mock *mockConfig) DataDir() string {
 	return mock.datadir
 }
 
+func (mock *mockConfig) CACert() string {
+	return coretesting.CACert
+}
+
 func agentConfig(tag names.Tag, datadir string) agent.Config {
 	return &mockConfig{
 		tag:     tag,
@@ -267,6 +271,54 @@ func (s *UpgraderSuite) TestUsesAlreadyDownloadedToolsIfAvailable(c *gc.C) {
 	})
 }
 
+func (s *UpgraderSuite) TestUpgraderRefusesToUpgradeToRevertedVersion(c *gc.C) {
+	oldVersion := version.MustParseBinary("1.2.3-quantal-amd64")
+	newVersion := version.MustParseBinary("5.4.3-quantal-amd64")
+	agentName := s.machine.Tag().String()
+	for _, vers := range []version.Binary{oldVersion, newVersion} {
+		envtesting.InstallFakeDownloadedTools(c, s.DataDir(), vers)
+		_, err := agenttools.ChangeAgentTools(s.DataDir(), agentName, vers)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := agenttools.RevertAgentTools(s.DataDir(), agentName)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(&version.Current, oldVersion)
+	err = statetesting.SetAgentVersion(s.State, newVersion.Number)
+	c.Assert(err, jc.ErrorIsNil)
+
+	u := s.makeUpgrader(c)
+	err = u.Stop()
+	// The tools are already downloaded, so if the upgrade had not
+	// been refused we would have got an UpgradeReadyError.
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *UpgraderSuite) TestUpgraderRevertsToPreviousVersion(c *gc.C) {
+	oldVersion := version.MustParseBinary("5.3.0-quantal-amd64")
+	newVersion := version.MustParseBinary("5.4.3-quantal-amd64")
+	agentName := s.machine.Tag().String()
+	for _, vers := range []version.Binary{oldVersion, newVersion} {
+		envtesting.InstallFakeDownloadedTools(c, s.DataDir(), vers)
+		_, err := agenttools.ChangeAgentTools(s.DataDir(), agentName, vers)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.PatchValue(&version.Current, newVersion)
+	err := statetesting.SetAgentVersion(s.State, oldVersion.Number)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Minor version downgrades are otherwise refused, but setting
+	// the agent version back to the previous version reverts.
+	u := s.makeUpgrader(c)
+	err = u.Stop()
+	envtesting.CheckUpgraderReadyError(c, err, &upgrader.UpgradeReadyError{
+		AgentName: agentName,
+		OldTools:  newVersion,
+		NewTools:  oldVersion,
+		DataDir:   s.DataDir(),
+		Revert:    true,
+	})
+}
+
 func (s *UpgraderSuite) TestUpgraderRefusesToDowngradeMinorVersions(c *gc.C) {
 	stor := s.DefaultToolsStorage
 	origTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))
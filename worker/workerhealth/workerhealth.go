@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package workerhealth provides a worker that periodically reports the
+// health of an agent's workers to the state server.
+package workerhealth
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.workerhealth")
+
+// DefaultPeriod is how often agents report the health of their
+// workers to the state server.
+const DefaultPeriod = time.Minute
+
+// Facade is the API used to report worker health.
+type Facade interface {
+	SetWorkerHealth(agent names.Tag, workers []params.WorkerHealth) error
+}
+
+// Registry collects the runners whose workers' health an agent reports.
+type Registry struct {
+	mu      sync.Mutex
+	runners map[string]worker.PolicyRunner
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		runners: make(map[string]worker.PolicyRunner),
+	}
+}
+
+// Register adds the given runner to the registry under the given
+// name, replacing any runner previously registered under that name.
+// The runner is removed from the registry when it stops. Runners that
+// cannot report the status of their workers are ignored.
+func (r *Registry) Register(name string, runner worker.Runner) {
+	policyRunner, ok := runner.(worker.PolicyRunner)
+	if !ok {
+		logger.Debugf("runner %q cannot report worker status", name)
+		return
+	}
+	r.mu.Lock()
+	r.runners[name] = policyRunner
+	r.mu.Unlock()
+	go func() {
+		policyRunner.Wait()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.runners[name] == policyRunner {
+			delete(r.runners, name)
+		}
+	}()
+}
+
+// Health returns the health of each worker of each registered runner,
+// named "<runner>/<worker>" and sorted by name.
+func (r *Registry) Health() []params.WorkerHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []params.WorkerHealth
+	for runnerName, runner := range r.runners {
+		for id, status := range runner.WorkerStatus() {
+			health := params.WorkerHealth{
+				Name:          runnerName + "/" + id,
+				Running:       status.Running,
+				Restarts:      status.Restarts,
+				LastErrorTime: status.LastErrorTime,
+				Failed:        status.Failed,
+			}
+			if status.LastError != nil {
+				health.LastError = status.LastError.Error()
+			}
+			result = append(result, health)
+		}
+	}
+	sort.Sort(byName(result))
+	return result
+}
+
+type byName []params.WorkerHealth
+
+func (b byName) Len() int           { return len(b) }
+func (b byName) Less(i, j int) bool { return b[i].Name < b[j].Name }
+func (b byName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// NewWorker returns a worker that reports the health of the workers
+// in the given registry on behalf of the given agent, once every period.
+func NewWorker(facade Facade, agent names.Tag, registry *Registry, period time.Duration) worker.Worker {
+	return worker.NewPeriodicWorker(func(stop <-chan struct{}) error {
+		return facade.SetWorkerHealth(agent, registry.Health())
+	}, period)
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package workerhealth_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/workerhealth"
+)
+
+type workerHealthSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&workerHealthSuite{})
+
+// fakeRunner is a worker.PolicyRunner reporting a fixed status.
+type fakeRunner struct {
+	worker.PolicyRunner
+	status map[string]worker.WorkerStatus
+	done   chan struct{}
+}
+
+func newFakeRunner(status map[string]worker.WorkerStatus) *fakeRunner {
+	return &fakeRunner{status: status, done: make(chan struct{})}
+}
+
+func (r *fakeRunner) WorkerStatus() map[string]worker.WorkerStatus {
+	return r.status
+}
+
+func (r *fakeRunner) Kill() {
+	close(r.done)
+}
+
+func (r *fakeRunner) Wait() error {
+	<-r.done
+	return nil
+}
+
+type fakeFacade struct {
+	reports chan []params.WorkerHealth
+}
+
+func (f *fakeFacade) SetWorkerHealth(agent names.Tag, workers []params.WorkerHealth) error {
+	if agent != names.NewMachineTag("0") {
+		return errors.New("unexpected agent")
+	}
+	f.reports <- workers
+	return nil
+}
+
+func (s *workerHealthSuite) TestRegistryHealth(c *gc.C) {
+	failedAt := time.Now()
+	registry := workerhealth.NewRegistry()
+	api := newFakeRunner(map[string]worker.WorkerStatus{
+		"machiner": {Running: true},
+	})
+	env := newFakeRunner(map[string]worker.WorkerStatus{
+		"addresser": {
+			Restarts:      4,
+			LastError:     errors.New("authentication failed"),
+			LastErrorTime: failedAt,
+			Failed:        true,
+		},
+	})
+	registry.Register("api", api)
+	registry.Register("env", env)
+	c.Assert(registry.Health(), jc.DeepEquals, []params.WorkerHealth{{
+		Name:    "api/machiner",
+		Running: true,
+	}, {
+		Name:          "env/addresser",
+		Restarts:      4,
+		LastError:     "authentication failed",
+		LastErrorTime: failedAt,
+		Failed:        true,
+	}})
+
+	// Stopped runners are forgotten.
+	env.Kill()
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(registry.Health()) == 1 {
+			break
+		}
+	}
+	c.Assert(registry.Health(), jc.DeepEquals, []params.WorkerHealth{{
+		Name:    "api/machiner",
+		Running: true,
+	}})
+	api.Kill()
+}
+
+func (s *workerHealthSuite) TestWorkerReports(c *gc.C) {
+	registry := workerhealth.NewRegistry()
+	runner := newFakeRunner(map[string]worker.WorkerStatus{
+		"machiner": {Running: true},
+	})
+	defer runner.Kill()
+	registry.Register("api", runner)
+
+	facade := &fakeFacade{reports: make(chan []params.WorkerHealth, 1)}
+	w := workerhealth.NewWorker(facade, names.NewMachineTag("0"), registry, time.Hour)
+	defer worker.Stop(w)
+	select {
+	case report := <-facade.reports:
+		c.Assert(report, jc.DeepEquals, []params.WorkerHealth{{
+			Name:    "api/machiner",
+			Running: true,
+		}})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for report")
+	}
+}